@@ -0,0 +1,216 @@
+package cacheimport
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/rootfs"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/cache/blobs"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/auth"
+	"github.com/moby/buildkit/snapshot"
+	"github.com/moby/buildkit/util/progress"
+	"github.com/moby/buildkit/util/push"
+	"github.com/moby/buildkit/util/resolver"
+	digest "github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+const mediaTypeConfig = "application/vnd.buildkit.cacheconfig.v0"
+
+// CacheRecord is a reference that can be found from the cache with a cache
+// key
+type CacheRecord struct {
+	CacheKey  digest.Digest
+	Reference cache.ImmutableRef
+}
+
+// ContentMapping links a content based cache key to a regular cache key
+type ContentMapping struct {
+	ContentKey digest.Digest
+	CacheKey   digest.Digest
+}
+
+type ExporterOpt struct {
+	Snapshotter  snapshot.Snapshotter
+	ContentStore content.Store
+	Differ       rootfs.MountDiffer
+	// Registries configures the access to the registries. The defaults are
+	// used if it is nil.
+	Registries *resolver.Registries
+	// SessionManager is used for requesting the registry credentials from
+	// the client of the build
+	SessionManager *session.Manager
+}
+
+func NewCacheExporter(opt ExporterOpt) *CacheExporter {
+	return &CacheExporter{opt: opt}
+}
+
+type CacheExporter struct {
+	opt ExporterOpt
+}
+
+// Export pushes the layer blobs of the records and a config describing the
+// cache keys to target
+func (ce *CacheExporter) Export(ctx context.Context, records []CacheRecord, mappings []ContentMapping, target string) error {
+	descs, err := ce.write(ctx, records, mappings)
+	if err != nil {
+		return err
+	}
+
+	pushDone := oneOffProgress(ctx, "pushing cache to "+target)
+	resolver := ce.opt.Registries.Resolver(auth.SessionCredentials(ctx, ce.opt.SessionManager))
+	pusher, err := resolver.Pusher(ctx, target)
+	if err != nil {
+		return pushDone(err)
+	}
+	handler := push.Handler(ce.opt.ContentStore, pusher)
+	for _, desc := range descs {
+		if _, err := handler(ctx, desc); err != nil {
+			return pushDone(errors.Wrapf(err, "failed to push %s", desc.Digest))
+		}
+	}
+	return pushDone(nil)
+}
+
+// write writes the config and the manifest list of the cache of the records
+// to the content store. It returns the descriptors of the layers, the config
+// and the manifest list, which is the last one.
+func (ce *CacheExporter) write(ctx context.Context, records []CacheRecord, mappings []ContentMapping) ([]ocispec.Descriptor, error) {
+	layersDone := oneOffProgress(ctx, "exporting layers")
+
+	var config cacheConfig
+	var layers []ocispec.Descriptor
+	allBlobs := map[digest.Digest]struct{}{}
+
+	for _, rec := range records {
+		dpairs, err := blobs.GetDiffPairs(ctx, ce.opt.Snapshotter, ce.opt.Differ, rec.Reference)
+		if err != nil {
+			return nil, layersDone(err)
+		}
+		var parent digest.Digest
+		for _, dp := range dpairs {
+			if _, ok := allBlobs[dp.Blobsum]; !ok {
+				info, err := ce.opt.ContentStore.Info(ctx, dp.Blobsum)
+				if err != nil {
+					return nil, layersDone(errors.Wrapf(err, "could not get blob %s", dp.Blobsum))
+				}
+				layers = append(layers, ocispec.Descriptor{
+					Digest:    dp.Blobsum,
+					Size:      info.Size,
+					MediaType: ocispec.MediaTypeImageLayerGzip,
+				})
+				config.Items = append(config.Items, configItem{
+					Blobsum: dp.Blobsum,
+					DiffID:  dp.DiffID,
+					Parent:  parent,
+				})
+				allBlobs[dp.Blobsum] = struct{}{}
+			}
+			parent = dp.Blobsum
+		}
+		config.Items = append(config.Items, configItem{
+			Blobsum:  parent,
+			CacheKey: rec.CacheKey,
+		})
+	}
+	for _, m := range mappings {
+		config.ContentMappings = append(config.ContentMappings, contentMapping{
+			ContentKey: m.ContentKey,
+			CacheKey:   m.CacheKey,
+		})
+	}
+	layersDone(nil)
+
+	dt, err := json.Marshal(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal cache config")
+	}
+	dgst := digest.FromBytes(dt)
+	configDone := oneOffProgress(ctx, "exporting config "+dgst.String())
+	if err := content.WriteBlob(ctx, ce.opt.ContentStore, dgst.String(), bytes.NewReader(dt), int64(len(dt)), dgst); err != nil {
+		return nil, configDone(errors.Wrap(err, "error writing config blob"))
+	}
+	configDone(nil)
+
+	configDesc := ocispec.Descriptor{
+		Digest:    dgst,
+		Size:      int64(len(dt)),
+		MediaType: mediaTypeConfig,
+	}
+
+	// the layers are referenced from a manifest list so that the registry
+	// keeps them around without them being part of a runnable image
+	mfst := manifestList{
+		MediaType: images.MediaTypeDockerSchema2ManifestList,
+		Index: ocispec.Index{
+			Versioned: specs.Versioned{SchemaVersion: 2},
+			Manifests: append(layers, configDesc),
+		},
+	}
+	dt, err = json.Marshal(mfst)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal manifest")
+	}
+	dgst = digest.FromBytes(dt)
+	mfstDone := oneOffProgress(ctx, "exporting manifest "+dgst.String())
+	if err := content.WriteBlob(ctx, ce.opt.ContentStore, dgst.String(), bytes.NewReader(dt), int64(len(dt)), dgst); err != nil {
+		return nil, mfstDone(errors.Wrap(err, "error writing manifest blob"))
+	}
+	mfstDone(nil)
+
+	return append(mfst.Manifests, ocispec.Descriptor{
+		Digest:    dgst,
+		Size:      int64(len(dt)),
+		MediaType: mfst.MediaType,
+	}), nil
+}
+
+type manifestList struct {
+	MediaType string `json:"mediaType,omitempty"`
+	ocispec.Index
+}
+
+type cacheConfig struct {
+	Items           []configItem
+	ContentMappings []contentMapping `json:",omitempty"`
+}
+
+// configItem describes a single layer blob. Items with a cache key point to
+// the top layer of the reference matching the key.
+type configItem struct {
+	Blobsum  digest.Digest
+	DiffID   digest.Digest `json:",omitempty"`
+	Parent   digest.Digest `json:",omitempty"`
+	CacheKey digest.Digest `json:",omitempty"`
+}
+
+type contentMapping struct {
+	ContentKey digest.Digest
+	CacheKey   digest.Digest
+}
+
+func oneOffProgress(ctx context.Context, id string) func(err error) error {
+	pw, _, _ := progress.FromContext(ctx)
+	now := time.Now()
+	st := progress.Status{
+		Started: &now,
+	}
+	pw.Write(id, st)
+	return func(err error) error {
+		// TODO: set error on status
+		now := time.Now()
+		st.Completed = &now
+		pw.Write(id, st)
+		pw.Close()
+		return err
+	}
+}
@@ -0,0 +1,235 @@
+package cacheimport
+
+import (
+	gocontext "context"
+	"fmt"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/remotes"
+	"github.com/containerd/containerd/rootfs"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/auth"
+	"github.com/moby/buildkit/snapshot"
+	"github.com/moby/buildkit/util/flightcontrol"
+	"github.com/moby/buildkit/util/resolver"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/identity"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+type blobmapper interface {
+	GetBlob(ctx gocontext.Context, key string) (digest.Digest, error)
+	SetBlob(ctx gocontext.Context, key string, blob digest.Digest) error
+}
+
+type ImporterOpt struct {
+	Snapshotter   snapshot.Snapshotter
+	ContentStore  content.Store
+	Applier       rootfs.Applier
+	CacheAccessor cache.Accessor
+	// Registries configures the access to the registries. The defaults are
+	// used if it is nil.
+	Registries *resolver.Registries
+	// SessionManager is used for requesting the registry credentials from
+	// the client of the build
+	SessionManager *session.Manager
+}
+
+func NewCacheImporter(opt ImporterOpt) *CacheImporter {
+	return &CacheImporter{opt: opt}
+}
+
+type CacheImporter struct {
+	opt ImporterOpt
+}
+
+// Import loads the cache config from ref. ref is either a cache exported by
+// CacheExporter or an image with an inline cache config. The inline cache of
+// a multi-platform image is read from the image of platform p, or of the
+// default platform if p is nil. Layer blobs are only pulled when a cache key
+// is looked up from the returned cache.
+func (ci *CacheImporter) Import(ctx context.Context, ref string, p *ocispec.Platform) (*ImportedCache, error) {
+	if _, ok := ci.opt.Snapshotter.(blobmapper); !ok {
+		return nil, errors.Errorf("cache importer requires snapshotter with blobs mapping support")
+	}
+
+	resolveDone := oneOffProgress(ctx, "resolve cache "+ref)
+	resolver := ci.opt.Registries.Resolver(auth.SessionCredentials(ctx, ci.opt.SessionManager))
+	ref, desc, err := resolver.Resolve(ctx, ref)
+	if err != nil {
+		return nil, resolveDone(err)
+	}
+	fetcher, err := resolver.Fetcher(ctx, ref)
+	if err != nil {
+		return nil, resolveDone(err)
+	}
+	resolveDone(nil)
+
+	return ci.load(ctx, ref, desc, fetcher, p)
+}
+
+// load reads the cache config of the manifest desc with fetcher
+func (ci *CacheImporter) load(ctx context.Context, ref string, desc ocispec.Descriptor, fetcher remotes.Fetcher, p *ocispec.Platform) (*ImportedCache, error) {
+	importDone := oneOffProgress(ctx, "importing cache manifest from "+ref)
+	fetch := remotes.FetchHandler(ci.opt.ContentStore, fetcher)
+	if _, err := fetch(ctx, desc); err != nil {
+		return nil, importDone(err)
+	}
+	layers, config, err := ci.cacheConfig(ctx, fetch, desc, p)
+	if err != nil {
+		return nil, importDone(errors.Wrapf(err, "invalid build cache %s", ref))
+	}
+	importDone(nil)
+
+	ic := &ImportedCache{
+		opt:             ci.opt,
+		ref:             ref,
+		fetcher:         fetcher,
+		layers:          layers,
+		byBlob:          map[digest.Digest]configItem{},
+		byCacheKey:      map[digest.Digest]digest.Digest{},
+		contentMappings: map[digest.Digest][]digest.Digest{},
+	}
+	for _, item := range config.Items {
+		if item.CacheKey != "" {
+			ic.byCacheKey[item.CacheKey] = item.Blobsum
+			continue
+		}
+		ic.byBlob[item.Blobsum] = item
+	}
+	for _, m := range config.ContentMappings {
+		ic.contentMappings[m.ContentKey] = append(ic.contentMappings[m.ContentKey], m.CacheKey)
+	}
+	return ic, nil
+}
+
+// cacheConfig returns the layers and the cache config of the cache manifest
+// list desc. The inline cache config is used if desc is the manifest, or the
+// manifest list, of an image.
+func (ci *CacheImporter) cacheConfig(ctx context.Context, fetch images.HandlerFunc, desc ocispec.Descriptor, p *ocispec.Platform) (map[digest.Digest]ocispec.Descriptor, *cacheConfig, error) {
+	if desc.MediaType == images.MediaTypeDockerSchema2Manifest || desc.MediaType == ocispec.MediaTypeImageManifest {
+		return inlineConfig(ctx, ci.opt.ContentStore, fetch, desc, p)
+	}
+	var mfst ocispec.Index
+	if err := readJSON(ctx, ci.opt.ContentStore, desc, &mfst); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to parse cache manifest")
+	}
+
+	var configDesc *ocispec.Descriptor
+	layers := map[digest.Digest]ocispec.Descriptor{}
+	for _, m := range mfst.Manifests {
+		if m.MediaType == mediaTypeConfig {
+			m := m
+			configDesc = &m
+			continue
+		}
+		layers[m.Digest] = m
+	}
+	if configDesc == nil {
+		// the manifest list of a multi-platform image
+		return inlineConfig(ctx, ci.opt.ContentStore, fetch, desc, p)
+	}
+	if _, err := fetch(ctx, *configDesc); err != nil {
+		return nil, nil, err
+	}
+	var config cacheConfig
+	if err := readJSON(ctx, ci.opt.ContentStore, *configDesc, &config); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to parse cache config")
+	}
+	return layers, &config, nil
+}
+
+// ImportedCache is a read-only instruction cache backed by an imported build
+// cache config
+type ImportedCache struct {
+	opt             ImporterOpt
+	ref             string
+	fetcher         remotes.Fetcher
+	layers          map[digest.Digest]ocispec.Descriptor
+	byBlob          map[digest.Digest]configItem
+	byCacheKey      map[digest.Digest]digest.Digest
+	contentMappings map[digest.Digest][]digest.Digest
+	g               flightcontrol.Group
+}
+
+func (ic *ImportedCache) Probe(ctx context.Context, key digest.Digest) (bool, error) {
+	_, ok := ic.byCacheKey[key]
+	return ok, nil
+}
+
+func (ic *ImportedCache) Lookup(ctx context.Context, key digest.Digest) (interface{}, error) {
+	blob, ok := ic.byCacheKey[key]
+	if !ok {
+		return nil, nil
+	}
+	chainID, err := ic.g.Do(ctx, blob.String(), func(ctx context.Context) (interface{}, error) {
+		return ic.unpack(ctx, blob)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ic.opt.CacheAccessor.Get(ctx, chainID.(string), cache.WithDescription(fmt.Sprintf("imported cache from %s", ic.ref)))
+}
+
+func (ic *ImportedCache) Set(key digest.Digest, ref interface{}) error {
+	return errors.Errorf("imported cache is read-only")
+}
+
+func (ic *ImportedCache) SetContentMapping(contentKey, key digest.Digest) error {
+	return errors.Errorf("imported cache is read-only")
+}
+
+func (ic *ImportedCache) GetContentMapping(dgst digest.Digest) ([]digest.Digest, error) {
+	return ic.contentMappings[dgst], nil
+}
+
+func (ic *ImportedCache) unpack(ctx context.Context, blob digest.Digest) (string, error) {
+	var layers []rootfs.Layer
+	for blob != "" {
+		item, ok := ic.byBlob[blob]
+		if !ok {
+			return "", errors.Errorf("invalid build cache: no layer for %s", blob)
+		}
+		desc, ok := ic.layers[blob]
+		if !ok {
+			return "", errors.Errorf("invalid build cache: missing layer descriptor %s", blob)
+		}
+		layers = append([]rootfs.Layer{{
+			Diff: ocispec.Descriptor{
+				MediaType: ocispec.MediaTypeImageLayer,
+				Digest:    item.DiffID,
+			},
+			Blob: desc,
+		}}, layers...)
+		blob = item.Parent
+	}
+
+	fetch := remotes.FetchHandler(ic.opt.ContentStore, ic.fetcher)
+	for _, l := range layers {
+		if _, err := ic.opt.ContentStore.Info(ctx, l.Blob.Digest); err == nil {
+			continue
+		}
+		if _, err := fetch(ctx, l.Blob); err != nil {
+			return "", err
+		}
+	}
+
+	unpackDone := oneOffProgress(ctx, "unpacking cache layers")
+	chainID, err := rootfs.ApplyLayers(ctx, layers, ic.opt.Snapshotter, ic.opt.Applier)
+	if err != nil {
+		return "", unpackDone(err)
+	}
+
+	var chain []digest.Digest
+	for _, l := range layers {
+		chain = append(chain, l.Diff.Digest)
+		if err := ic.opt.Snapshotter.(blobmapper).SetBlob(ctx, string(identity.ChainID(chain)), l.Blob.Digest); err != nil {
+			return "", unpackDone(err)
+		}
+	}
+	return string(chainID), unpackDone(nil)
+}
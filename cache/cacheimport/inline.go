@@ -0,0 +1,178 @@
+package cacheimport
+
+import (
+	"encoding/json"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/platforms"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/cache/blobs"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// InlineConfigKey is the field of the image config with the inline cache
+// config of the image
+const InlineConfigKey = "moby.buildkit.cache.v0"
+
+// Records are the cache records and the content mappings of a build
+type Records struct {
+	CacheRecords    []CacheRecord
+	ContentMappings []ContentMapping
+}
+
+// InlineConfig returns the cache config of the records whose refs are in the
+// chain of ref, the result of an image. exported are the layers of the image
+// from the bottom one, they are the blobs of the chain of ref that may be
+// compressed differently. It returns nil if no record matches the image.
+func InlineConfig(ctx context.Context, ref cache.ImmutableRef, records Records, exported []blobs.DiffPair) ([]byte, error) {
+	resolved, err := cache.Resolve(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	chain := refChain(resolved)
+	if len(chain) != len(exported) {
+		return nil, errors.Errorf("invalid exported layers: %d layers for %d refs", len(exported), len(chain))
+	}
+	if ref != nil && resolved != ref {
+		// the records of a lazy result have the ID of the lazy ref
+		chain[ref.ID()] = len(exported) - 1
+	}
+	var config cacheConfig
+	keys := map[digest.Digest]struct{}{}
+	for _, rec := range records.CacheRecords {
+		if rec.Reference == nil {
+			continue
+		}
+		i, ok := chain[rec.Reference.ID()]
+		if !ok {
+			continue
+		}
+		config.Items = append(config.Items, configItem{
+			Blobsum:  exported[i].Blobsum,
+			CacheKey: rec.CacheKey,
+		})
+		keys[rec.CacheKey] = struct{}{}
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	var parent digest.Digest
+	for _, dp := range exported {
+		config.Items = append(config.Items, configItem{
+			Blobsum: dp.Blobsum,
+			DiffID:  dp.DiffID,
+			Parent:  parent,
+		})
+		parent = dp.Blobsum
+	}
+	for _, m := range records.ContentMappings {
+		if _, ok := keys[m.CacheKey]; ok {
+			config.ContentMappings = append(config.ContentMappings, contentMapping{
+				ContentKey: m.ContentKey,
+				CacheKey:   m.CacheKey,
+			})
+		}
+	}
+	dt, err := json.Marshal(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal inline cache config")
+	}
+	return dt, nil
+}
+
+// refChain returns the index of the layer of every ref in the chain of ref,
+// from 0 for the bottom one
+func refChain(ref cache.ImmutableRef) map[string]int {
+	var ids []string
+	for r := ref; r != nil; {
+		ids = append(ids, r.ID())
+		p := r.Parent()
+		if r != ref {
+			r.Release(context.TODO())
+		}
+		r = p
+	}
+	chain := make(map[string]int, len(ids))
+	for i, id := range ids {
+		chain[id] = len(ids) - 1 - i
+	}
+	return chain
+}
+
+// inlineConfig returns the layers and the inline cache config of the image of
+// the manifest or manifest list desc. The manifest of platform p, or of the
+// default platform if p is nil, is used from a list.
+func inlineConfig(ctx context.Context, cs content.Store, fetch images.HandlerFunc, desc ocispec.Descriptor, p *ocispec.Platform) (map[digest.Digest]ocispec.Descriptor, *cacheConfig, error) {
+	if desc.MediaType == images.MediaTypeDockerSchema2ManifestList || desc.MediaType == ocispec.MediaTypeImageIndex {
+		var idx ocispec.Index
+		if err := readJSON(ctx, cs, desc, &idx); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to parse manifest list")
+		}
+		m, err := platformManifest(idx.Manifests, p)
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, err := fetch(ctx, m); err != nil {
+			return nil, nil, err
+		}
+		desc = m
+	}
+
+	var mfst ocispec.Manifest
+	if err := readJSON(ctx, cs, desc, &mfst); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to parse manifest")
+	}
+	if _, err := fetch(ctx, mfst.Config); err != nil {
+		return nil, nil, err
+	}
+	var img map[string]json.RawMessage
+	if err := readJSON(ctx, cs, mfst.Config, &img); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to parse image config")
+	}
+	dt, ok := img[InlineConfigKey]
+	if !ok {
+		return nil, nil, errors.Errorf("image %s has no inline cache", desc.Digest)
+	}
+	var config cacheConfig
+	if err := json.Unmarshal(dt, &config); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to parse inline cache config")
+	}
+	layers := map[digest.Digest]ocispec.Descriptor{}
+	for _, l := range mfst.Layers {
+		layers[l.Digest] = l
+	}
+	return layers, &config, nil
+}
+
+func platformManifest(manifests []ocispec.Descriptor, p *ocispec.Platform) (ocispec.Descriptor, error) {
+	want := platforms.Default()
+	if p != nil {
+		want = *p
+	}
+	want = platforms.Normalize(want)
+	for _, m := range manifests {
+		if m.Platform == nil {
+			continue
+		}
+		mp := platforms.Normalize(*m.Platform)
+		if mp.OS == want.OS && mp.Architecture == want.Architecture && (p == nil || mp.Variant == want.Variant) {
+			return m, nil
+		}
+	}
+	if len(manifests) == 1 {
+		return manifests[0], nil
+	}
+	return ocispec.Descriptor{}, errors.Errorf("no manifest for platform %s", platforms.Format(want))
+}
+
+func readJSON(ctx context.Context, cs content.Store, desc ocispec.Descriptor, v interface{}) error {
+	dt, err := content.ReadBlob(ctx, cs, desc.Digest)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(dt, v)
+}
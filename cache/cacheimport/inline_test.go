@@ -0,0 +1,161 @@
+package cacheimport
+
+import (
+	"bytes"
+	gocontext "context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/content/local"
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/platforms"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/cache/blobs"
+	digest "github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestInlineCacheConfig(t *testing.T) {
+	root, err := ioutil.TempDir("", "buildkit-cacheimport")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	cs, err := local.NewStore(root)
+	require.NoError(t, err)
+	ctx := context.TODO()
+
+	write := func(mediaType string, v interface{}) ocispec.Descriptor {
+		dt, err := json.Marshal(v)
+		require.NoError(t, err)
+		dgst := digest.FromBytes(dt)
+		require.NoError(t, content.WriteBlob(ctx, cs, dgst.String(), bytes.NewReader(dt), int64(len(dt)), dgst))
+		return ocispec.Descriptor{MediaType: mediaType, Digest: dgst, Size: int64(len(dt))}
+	}
+
+	layer := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageLayerGzip, Digest: digest.FromString("layer"), Size: 5}
+	config := cacheConfig{Items: []configItem{
+		{Blobsum: layer.Digest, CacheKey: digest.FromString("key")},
+		{Blobsum: layer.Digest, DiffID: digest.FromString("diff")},
+	}}
+	img := map[string]interface{}{
+		"architecture":  "amd64",
+		InlineConfigKey: config,
+	}
+	mfst := ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		Config:    write(ocispec.MediaTypeImageConfig, img),
+		Layers:    []ocispec.Descriptor{layer},
+	}
+	mfstDesc := write(ocispec.MediaTypeImageManifest, mfst)
+	p := platforms.Default()
+	mfstDesc.Platform = &p
+	otherConfig := cacheConfig{Items: []configItem{
+		{Blobsum: layer.Digest, CacheKey: digest.FromString("otherkey")},
+	}}
+	other := write(ocispec.MediaTypeImageManifest, ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		Config:    write(ocispec.MediaTypeImageConfig, map[string]interface{}{InlineConfigKey: otherConfig}),
+		Layers:    []ocispec.Descriptor{layer},
+	})
+	otherPlatform := ocispec.Platform{OS: "plan9", Architecture: "mips"}
+	other.Platform = &otherPlatform
+	idxDesc := write(images.MediaTypeDockerSchema2ManifestList, ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		Manifests: []ocispec.Descriptor{other, mfstDesc},
+	})
+
+	// all the blobs are in the content store already
+	fetch := func(ctx gocontext.Context, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+		return nil, nil
+	}
+	ci := NewCacheImporter(ImporterOpt{ContentStore: cs})
+	for _, desc := range []ocispec.Descriptor{mfstDesc, idxDesc} {
+		layers, cfg, err := ci.cacheConfig(ctx, fetch, desc, nil)
+		require.NoError(t, err)
+		require.Equal(t, map[digest.Digest]ocispec.Descriptor{layer.Digest: layer}, layers)
+		require.Equal(t, &config, cfg)
+	}
+
+	// the build platform selects the image of the list
+	_, cfg, err := ci.cacheConfig(ctx, fetch, idxDesc, &otherPlatform)
+	require.NoError(t, err)
+	require.Equal(t, &otherConfig, cfg)
+	_, _, err = ci.cacheConfig(ctx, fetch, idxDesc, &ocispec.Platform{OS: "plan9", Architecture: "arm"})
+	require.Error(t, err)
+
+	delete(img, InlineConfigKey)
+	mfst.Config = write(ocispec.MediaTypeImageConfig, img)
+	_, _, err = ci.cacheConfig(ctx, fetch, write(ocispec.MediaTypeImageManifest, mfst), nil)
+	require.Error(t, err)
+}
+
+func TestInlineConfig(t *testing.T) {
+	base := &testChainRef{id: "base"}
+	mid := &testChainRef{id: "mid", parent: base}
+	top := &testChainRef{id: "top", parent: mid}
+	exported := []blobs.DiffPair{
+		{DiffID: digest.FromString("diff0"), Blobsum: digest.FromString("blob0")},
+		{DiffID: digest.FromString("diff1"), Blobsum: digest.FromString("blob1")},
+		{DiffID: digest.FromString("diff2"), Blobsum: digest.FromString("blob2")},
+	}
+	records := Records{
+		CacheRecords: []CacheRecord{
+			{CacheKey: digest.FromString("key-top"), Reference: top},
+			{CacheKey: digest.FromString("key-base"), Reference: base},
+			// the records of the refs that are not in the chain are skipped
+			{CacheKey: digest.FromString("key-other"), Reference: &testChainRef{id: "other", parent: base}},
+		},
+		ContentMappings: []ContentMapping{
+			{ContentKey: digest.FromString("content-base"), CacheKey: digest.FromString("key-base")},
+			{ContentKey: digest.FromString("content-other"), CacheKey: digest.FromString("key-other")},
+		},
+	}
+
+	dt, err := InlineConfig(context.TODO(), top, records, exported)
+	require.NoError(t, err)
+	var config cacheConfig
+	require.NoError(t, json.Unmarshal(dt, &config))
+	require.Equal(t, []configItem{
+		{Blobsum: exported[2].Blobsum, CacheKey: digest.FromString("key-top")},
+		{Blobsum: exported[0].Blobsum, CacheKey: digest.FromString("key-base")},
+		{Blobsum: exported[0].Blobsum, DiffID: exported[0].DiffID},
+		{Blobsum: exported[1].Blobsum, DiffID: exported[1].DiffID, Parent: exported[0].Blobsum},
+		{Blobsum: exported[2].Blobsum, DiffID: exported[2].DiffID, Parent: exported[1].Blobsum},
+	}, config.Items)
+	require.Equal(t, []contentMapping{
+		{ContentKey: digest.FromString("content-base"), CacheKey: digest.FromString("key-base")},
+	}, config.ContentMappings)
+
+	dt, err = InlineConfig(context.TODO(), mid, Records{CacheRecords: records.CacheRecords[:1]}, exported[:2])
+	require.NoError(t, err)
+	require.Nil(t, dt)
+
+	_, err = InlineConfig(context.TODO(), top, records, exported[:2])
+	require.Error(t, err)
+}
+
+type testChainRef struct {
+	cache.ImmutableRef
+	id     string
+	parent *testChainRef
+}
+
+func (r *testChainRef) ID() string {
+	return r.id
+}
+
+func (r *testChainRef) Parent() cache.ImmutableRef {
+	if r.parent == nil {
+		return nil
+	}
+	return r.parent
+}
+
+func (r *testChainRef) Release(context.Context) error {
+	return nil
+}
@@ -0,0 +1,169 @@
+package cacheimport
+
+import (
+	gocontext "context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/containerd/containerd/content"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/filesync"
+	digest "github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// ImportLocal loads the cache config from the OCI image layout in the synced
+// directory name of the client. The layout is copied to a temporary
+// directory that is removed by the returned function. p selects the image of
+// a multi-platform image like in Import.
+func (ci *CacheImporter) ImportLocal(ctx context.Context, name string, p *ocispec.Platform) (*ImportedCache, func() error, error) {
+	if _, ok := ci.opt.Snapshotter.(blobmapper); !ok {
+		return nil, nil, errors.Errorf("cache importer requires snapshotter with blobs mapping support")
+	}
+	caller, err := sessionCaller(ctx, ci.opt.SessionManager)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dir, err := ioutil.TempDir("", "buildkit-cache-import")
+	if err != nil {
+		return nil, nil, err
+	}
+	release := func() error {
+		return os.RemoveAll(dir)
+	}
+	syncDone := oneOffProgress(ctx, "transferring cache from "+name)
+	if err := filesync.FSSync(ctx, caller, filesync.FSSendRequestOpt{Name: name, DestDir: dir}); err != nil {
+		release()
+		return nil, nil, syncDone(errors.Wrapf(err, "failed to transfer cache from %s", name))
+	}
+	syncDone(nil)
+
+	dt, err := ioutil.ReadFile(filepath.Join(dir, "index.json"))
+	if err != nil {
+		release()
+		return nil, nil, errors.Wrap(err, "failed to read oci layout index of cache")
+	}
+	var idx ocispec.Index
+	if err := json.Unmarshal(dt, &idx); err != nil {
+		release()
+		return nil, nil, errors.Wrap(err, "failed to parse oci layout index of cache")
+	}
+	if len(idx.Manifests) != 1 {
+		release()
+		return nil, nil, errors.Errorf("invalid cache layout %s with %d manifests", name, len(idx.Manifests))
+	}
+
+	ic, err := ci.load(ctx, name, idx.Manifests[0], layoutFetcher(dir), p)
+	if err != nil {
+		release()
+		return nil, nil, err
+	}
+	return ic, release, nil
+}
+
+// ExportLocal sends the layer blobs of the records and a config describing
+// the cache keys as an OCI image layout to the target of the client
+func (ce *CacheExporter) ExportLocal(ctx context.Context, records []CacheRecord, mappings []ContentMapping, target string) error {
+	caller, err := sessionCaller(ctx, ce.opt.SessionManager)
+	if err != nil {
+		return err
+	}
+	descs, err := ce.write(ctx, records, mappings)
+	if err != nil {
+		return err
+	}
+
+	dir, err := ioutil.TempDir("", "buildkit-cache-export")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	sendDone := oneOffProgress(ctx, "sending cache to client")
+	if err := writeLayout(ctx, dir, ce.opt.ContentStore, descs); err != nil {
+		return sendDone(err)
+	}
+	return sendDone(filesync.CopyToCaller(ctx, dir, caller, target, nil))
+}
+
+// writeLayout writes the blobs of descs to an OCI image layout in dir. The
+// last descriptor is the manifest of the index of the layout.
+func writeLayout(ctx context.Context, dir string, provider content.Provider, descs []ocispec.Descriptor) error {
+	dt, err := json.Marshal(ocispec.ImageLayout{Version: ocispec.ImageLayoutVersion})
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, ocispec.ImageLayoutFile), dt, 0644); err != nil {
+		return err
+	}
+	dt, err = json.Marshal(ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		Manifests: descs[len(descs)-1:],
+	})
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "index.json"), dt, 0644); err != nil {
+		return err
+	}
+	for _, desc := range descs {
+		if err := writeLayoutBlob(ctx, dir, provider, desc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeLayoutBlob(ctx context.Context, dir string, provider content.Provider, desc ocispec.Descriptor) error {
+	p := layoutBlobPath(dir, desc.Digest)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	ra, err := provider.ReaderAt(ctx, desc.Digest)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read blob %s", desc.Digest)
+	}
+	defer ra.Close()
+	f, err := os.Create(p)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, io.NewSectionReader(ra, 0, desc.Size)); err != nil {
+		f.Close()
+		return errors.Wrapf(err, "failed to write blob %s", desc.Digest)
+	}
+	return f.Close()
+}
+
+// layoutFetcher fetches the blobs of the OCI image layout in a directory
+type layoutFetcher string
+
+func (dir layoutFetcher) Fetch(ctx gocontext.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+	if err := desc.Digest.Validate(); err != nil {
+		return nil, err
+	}
+	return os.Open(layoutBlobPath(string(dir), desc.Digest))
+}
+
+func layoutBlobPath(dir string, dgst digest.Digest) string {
+	return filepath.Join(dir, "blobs", dgst.Algorithm().String(), dgst.Hex())
+}
+
+// sessionCaller returns the session of the client of the build
+func sessionCaller(ctx context.Context, sm *session.Manager) (session.Caller, error) {
+	id := session.FromContext(ctx)
+	if id == "" || sm == nil {
+		return nil, errors.New("could not access local cache without session")
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	return sm.Get(timeoutCtx, id)
+}
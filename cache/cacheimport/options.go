@@ -0,0 +1,89 @@
+package cacheimport
+
+import (
+	"github.com/pkg/errors"
+)
+
+const (
+	// TypeRegistry imports and exports the cache from the reference of a
+	// registry
+	TypeRegistry = "registry"
+	// TypeLocal imports and exports the cache from an OCI image layout
+	// directory of the client
+	TypeLocal = "local"
+)
+
+const (
+	// AttrRef is the reference of a registry cache
+	AttrRef = "ref"
+	// AttrName is the name of the synced directory of the client a local
+	// cache is imported from
+	AttrName = "name"
+	// AttrTarget is the name of the target of the client a local cache is
+	// exported to
+	AttrTarget = "target"
+	// AttrMode selects the exported cache records, ModeMin by default
+	AttrMode = "mode"
+)
+
+const (
+	// ModeMin exports the cache of the results of the build only
+	ModeMin = "min"
+	// ModeMax exports the cache of all the intermediate steps
+	ModeMax = "max"
+)
+
+// Entry is a source the cache is imported from or a target it is exported to
+type Entry struct {
+	Type  string
+	Attrs map[string]string
+}
+
+// String returns the description of the entry in the progress of the build
+func (e Entry) String() string {
+	switch e.Type {
+	case TypeRegistry:
+		return e.Attrs[AttrRef]
+	case TypeLocal:
+		return "local " + e.Attrs[AttrName] + e.Attrs[AttrTarget]
+	}
+	return e.Type
+}
+
+// Mode returns the mode of an export entry
+func (e Entry) Mode() (string, error) {
+	switch m := e.Attrs[AttrMode]; m {
+	case "", ModeMin:
+		return ModeMin, nil
+	case ModeMax:
+		return ModeMax, nil
+	default:
+		return "", errors.Errorf("invalid cache export mode %s", m)
+	}
+}
+
+// Validate checks that the entry has the attributes of its type
+func (e Entry) Validate(export bool) error {
+	switch e.Type {
+	case TypeRegistry:
+		if e.Attrs[AttrRef] == "" {
+			return errors.Errorf("%s attribute required for %s cache", AttrRef, e.Type)
+		}
+	case TypeLocal:
+		attr := AttrName
+		if export {
+			attr = AttrTarget
+		}
+		if e.Attrs[attr] == "" {
+			return errors.Errorf("%s attribute required for %s cache", attr, e.Type)
+		}
+	default:
+		return errors.Errorf("unsupported cache type %s", e.Type)
+	}
+	if export {
+		if _, err := e.Mode(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package cacheimport
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntryValidate(t *testing.T) {
+	require.NoError(t, Entry{Type: TypeRegistry, Attrs: map[string]string{AttrRef: "example.com/cache"}}.Validate(true))
+	require.NoError(t, Entry{Type: TypeLocal, Attrs: map[string]string{AttrName: "cache-import-0"}}.Validate(false))
+
+	err := Entry{Type: TypeLocal, Attrs: map[string]string{AttrName: "cache-import-0"}}.Validate(true)
+	require.EqualError(t, err, "target attribute required for local cache")
+
+	err = Entry{Type: TypeRegistry}.Validate(false)
+	require.EqualError(t, err, "ref attribute required for registry cache")
+
+	err = Entry{Type: "s3"}.Validate(false)
+	require.EqualError(t, err, "unsupported cache type s3")
+
+	e := Entry{Type: TypeRegistry, Attrs: map[string]string{AttrRef: "example.com/cache"}}
+	mode, err := e.Mode()
+	require.NoError(t, err)
+	require.Equal(t, ModeMin, mode)
+
+	e.Attrs[AttrMode] = ModeMax
+	mode, err = e.Mode()
+	require.NoError(t, err)
+	require.Equal(t, ModeMax, mode)
+
+	e.Attrs[AttrMode] = "all"
+	require.EqualError(t, e.Validate(true), "invalid cache export mode all")
+}
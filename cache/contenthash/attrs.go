@@ -0,0 +1,146 @@
+package contenthash
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/tonistiigi/fsutil"
+)
+
+// Attrs are the file attributes that are part of a checksum in addition to
+// the contents, the file types, the permission bits, the link targets and
+// the extended attributes
+type Attrs uint8
+
+const (
+	// AttrOwnership is the uid and gid of the files
+	AttrOwnership Attrs = 1 << iota
+	// AttrXattrs are all the extended attributes, including the capabilities.
+	// They are already part of the checksum unless the ChecksumOpts of the
+	// reference exclude them.
+	AttrXattrs
+	// AttrCapabilities is the security.capability extended attribute
+	AttrCapabilities
+	// AttrModTime is the modification time of the files
+	AttrModTime
+)
+
+const capabilityXattr = "security.capability"
+
+var attrNames = []struct {
+	attr Attrs
+	name string
+}{
+	{AttrOwnership, "ownership"},
+	{AttrXattrs, "xattrs"},
+	{AttrCapabilities, "capabilities"},
+	{AttrModTime, "modtime"},
+}
+
+func (a Attrs) String() string {
+	var names []string
+	for _, n := range attrNames {
+		if a&n.attr != 0 {
+			names = append(names, n.name)
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, "+")
+}
+
+func (cr *CacheRecord) setAttrs(stat *fsutil.Stat) {
+	cr.Uid = stat.Uid
+	cr.Gid = stat.Gid
+	cr.ModTime = stat.ModTime
+	cr.Xattrs = stat.Xattrs
+}
+
+func (cr *CacheRecord) writeAttrs(w io.Writer, attrs Attrs) {
+	if attrs&AttrOwnership != 0 {
+		fmt.Fprintf(w, "uid%dgid%d", cr.Uid, cr.Gid)
+	}
+	if attrs&AttrModTime != 0 {
+		fmt.Fprintf(w, "mtime%d", cr.ModTime)
+	}
+	var keys []string
+	if attrs&AttrXattrs != 0 {
+		for k := range cr.Xattrs {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+	} else if _, ok := cr.Xattrs[capabilityXattr]; ok && attrs&AttrCapabilities != 0 {
+		keys = []string{capabilityXattr}
+	}
+	for _, k := range keys {
+		w.Write([]byte("xattr:" + k))
+		w.Write(cr.Xattrs[k])
+	}
+}
+
+// withAttrs returns the checksum dgst of the path p combined with the attrs
+// of p and the files under it that are selected by opts. The digests of the
+// files need to be computed before calling. The attributes of the FollowPaths
+// of opts are not part of the checksum.
+func (cc *cacheContext) withAttrs(dgst digest.Digest, p string, attrs Attrs, opts ChecksumOpts) (digest.Digest, error) {
+	k := []byte(p)
+	if p == "/" {
+		k = []byte{}
+	}
+
+	cc.mu.RLock()
+	root := cc.tree.Root()
+	cc.mu.RUnlock()
+
+	v, ok := root.Get(k)
+	if !ok {
+		return "", errors.Wrapf(errNotFound, "%s not found", p)
+	}
+
+	h := sha256.New()
+	h.Write([]byte(dgst))
+	h.Write([]byte(attrs.String()))
+	if cr := v.(*CacheRecord); cr.Type != CacheRecordTypeDir {
+		cr.writeAttrs(h, attrs)
+		return digest.NewDigest(digest.SHA256, h), nil
+	}
+
+	excludes, err := opts.excludeMatcher()
+	if err != nil {
+		return "", err
+	}
+
+	prefix := append(k, '/')
+	var walkErr error
+	root.WalkPrefix(prefix, func(subk []byte, v interface{}) bool {
+		subcr := v.(*CacheRecord)
+		if subcr.Type == CacheRecordTypeDir {
+			return false
+		}
+		if !bytes.Equal(subk, prefix) {
+			rel := strings.TrimPrefix(strings.TrimSuffix(string(subk), "/"), "/")
+			ok, err := opts.selects(rel, excludes)
+			if err != nil {
+				walkErr = err
+				return true
+			}
+			if !ok {
+				return false
+			}
+		}
+		h.Write(bytes.TrimPrefix(subk, k))
+		subcr.writeAttrs(h, attrs)
+		return false
+	})
+	if walkErr != nil {
+		return "", walkErr
+	}
+	return digest.NewDigest(digest.SHA256, h), nil
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 	"sync"
 
 	"github.com/BurntSushi/locker"
@@ -17,6 +18,8 @@ import (
 	"github.com/moby/buildkit/cache"
 	"github.com/moby/buildkit/cache/metadata"
 	"github.com/moby/buildkit/snapshot"
+	"github.com/moby/buildkit/util/flightcontrol"
+	"github.com/moby/buildkit/util/tracing"
 	digest "github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
 	"github.com/tonistiigi/fsutil"
@@ -27,12 +30,13 @@ var errNotFound = errors.Errorf("not found")
 var defaultManager *cacheManager
 var defaultManagerOnce sync.Once
 
-const keyContentHash = "buildkit.contenthash.v0"
+const keyContentHash = "buildkit.contenthash.v2"
 
 func getDefaultManager() *cacheManager {
 	defaultManagerOnce.Do(func() {
 		lru, _ := simplelru.NewLRU(20, nil) // error is impossible on positive size
-		defaultManager = &cacheManager{lru: lru, locker: locker.NewLocker()}
+		results, _ := simplelru.NewLRU(resultsCacheSize, nil)
+		defaultManager = &cacheManager{lru: lru, results: results, locker: locker.NewLocker()}
 	})
 	return defaultManager
 }
@@ -43,10 +47,33 @@ func getDefaultManager() *cacheManager {
 // header, "/dir" is for contents. For the root node "" (empty string) is the
 // key for root, "/" for the root header
 
+// Checksum returns the content based checksum of path in ref. The
+// ChecksumOpts saved for ref with SetChecksumOpts are applied.
 func Checksum(ctx context.Context, ref cache.ImmutableRef, path string) (digest.Digest, error) {
 	return getDefaultManager().Checksum(ctx, ref, path)
 }
 
+// ChecksumWithOpts returns the checksum of path in ref that only includes
+// the files selected by opts
+func ChecksumWithOpts(ctx context.Context, ref cache.ImmutableRef, path string, opts ChecksumOpts) (digest.Digest, error) {
+	return getDefaultManager().ChecksumWithOpts(ctx, ref, path, opts)
+}
+
+// NormalizeSelector validates a path selecting a subtree of a reference and
+// returns it as a cleaned absolute unix path. Paths with ".." components are
+// rejected so that a selector can't point outside of the reference.
+func NormalizeSelector(p string) (string, error) {
+	if strings.IndexByte(p, 0) != -1 {
+		return "", errors.Errorf("invalid selector %q: contains null byte", p)
+	}
+	for _, c := range strings.Split(p, "/") {
+		if c == ".." {
+			return "", errors.Errorf("invalid selector %q: must not contain \"..\"", p)
+		}
+	}
+	return path.Join("/", p), nil
+}
+
 func GetCacheContext(ctx context.Context, md *metadata.StorageItem) (CacheContext, error) {
 	return getDefaultManager().GetCacheContext(ctx, md)
 }
@@ -68,14 +95,37 @@ type cacheManager struct {
 	locker *locker.Locker
 	lru    *simplelru.LRU
 	lruMu  sync.Mutex
+
+	// results are the checksums of the selections of the cache contexts
+	results   *simplelru.LRU
+	resultsMu sync.Mutex
+	g         flightcontrol.Group
 }
 
 func (cm *cacheManager) Checksum(ctx context.Context, ref cache.ImmutableRef, p string) (digest.Digest, error) {
-	cc, err := cm.GetCacheContext(ctx, ensureOriginMetadata(ref.Metadata()))
+	opts, err := getChecksumOpts(ensureOriginMetadata(ref.Metadata()))
 	if err != nil {
-		return "", nil
+		return "", err
 	}
-	return cc.Checksum(ctx, ref, p)
+	return cm.ChecksumWithOpts(ctx, ref, p, opts)
+}
+
+func (cm *cacheManager) ChecksumWithOpts(ctx context.Context, ref cache.ImmutableRef, p string, opts ChecksumOpts) (dgst digest.Digest, retErr error) {
+	p, err := NormalizeSelector(p)
+	if err != nil {
+		return "", err
+	}
+	span, ctx := tracing.StartSpan(ctx, "checksum", ref.ID()+":"+p)
+	defer func() {
+		span.Finish(retErr)
+	}()
+	cc, err := cm.refCacheContext(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	return cm.cachedChecksum(ctx, cc, p, opts, func(ctx context.Context) (digest.Digest, error) {
+		return cc.checksumWithOpts(ctx, ref, p, opts)
+	})
 }
 
 func (cm *cacheManager) GetCacheContext(ctx context.Context, md *metadata.StorageItem) (CacheContext, error) {
@@ -106,9 +156,10 @@ func (cm *cacheManager) SetCacheContext(ctx context.Context, md *metadata.Storag
 	}
 	if md.ID() != cc.md.ID() {
 		cc = &cacheContext{
-			md:       md,
-			tree:     cci.(*cacheContext).tree,
-			dirtyMap: map[string]struct{}{},
+			md:         md,
+			tree:       cci.(*cacheContext).tree,
+			dirtyMap:   map[string]struct{}{},
+			generation: nextGeneration(),
 		}
 	} else {
 		if err := cc.save(); err != nil {
@@ -126,6 +177,10 @@ type cacheContext struct {
 	md    *metadata.StorageItem
 	tree  *iradix.Tree
 	dirty bool // needs to be persisted to disk
+	// generation changes when the contents of the tree change
+	generation uint64
+	// fromParent derives the checksums from the parent on the first checksum
+	fromParent bool
 
 	// used in HandleChange
 	txn      *iradix.Txn
@@ -172,9 +227,10 @@ func (m *mount) clean() error {
 
 func newCacheContext(md *metadata.StorageItem) (*cacheContext, error) {
 	cc := &cacheContext{
-		md:       md,
-		tree:     iradix.New(),
-		dirtyMap: map[string]struct{}{},
+		md:         md,
+		tree:       iradix.New(),
+		dirtyMap:   map[string]struct{}{},
+		generation: nextGeneration(),
 	}
 	if err := cc.load(); err != nil {
 		return nil, err
@@ -246,6 +302,7 @@ func (cc *cacheContext) HandleChange(kind fsutil.ChangeKind, p string, fi os.Fil
 
 	cc.mu.Lock()
 	defer cc.mu.Unlock()
+	cc.generation = nextGeneration()
 	if cc.txn == nil {
 		cc.txn = cc.tree.Txn()
 		cc.node = cc.tree.Root()
@@ -306,6 +363,7 @@ func (cc *cacheContext) HandleChange(kind fsutil.ChangeKind, p string, fi os.Fil
 		k = append(k, []byte("/")...)
 	}
 	cr.Digest = h.Digest()
+	cr.setAttrs(stat)
 	cc.txn.Insert(k, cr)
 	d := path.Dir(string(k))
 	if d == "/" {
@@ -320,15 +378,26 @@ func (cc *cacheContext) Checksum(ctx context.Context, mountable cache.Mountable,
 	m := &mount{mountable: mountable}
 	defer m.clean()
 
-	const maxSymlinkLimit = 255
+	_, cr, err := cc.checksumFollow(ctx, m, p)
+	if err != nil {
+		return "", err
+	}
+	return cr.Digest, nil
+}
+
+// checksumFollow returns the record of p after following the symlinks and
+// the path the record was found from
+func (cc *cacheContext) checksumFollow(ctx context.Context, m *mount, p string) (string, *CacheRecord, error) {
+	p = path.Join("/", filepath.ToSlash(p))
+
 	i := 0
 	for {
 		if i > maxSymlinkLimit {
-			return "", errors.Errorf("too many symlinks: %s", p)
+			return "", nil, errors.Errorf("too many symlinks: %s", p)
 		}
 		cr, err := cc.checksumNoFollow(ctx, m, p)
 		if err != nil {
-			return "", err
+			return "", nil, err
 		}
 		if cr.Type == CacheRecordTypeSymlink {
 			link := cr.Linkname
@@ -338,7 +407,7 @@ func (cc *cacheContext) Checksum(ctx context.Context, mountable cache.Mountable,
 			i++
 			p = link
 		} else {
-			return cr.Digest, nil
+			return p, cr, nil
 		}
 	}
 }
@@ -401,10 +470,14 @@ func (cc *cacheContext) lazyChecksum(ctx context.Context, m *mount, p string) (*
 			return nil, err
 		}
 	}
+	opts, err := getChecksumOpts(ensureOriginMetadata(cc.md))
+	if err != nil {
+		return nil, err
+	}
 	k := []byte(p)
 	root = cc.tree.Root()
 	txn := cc.tree.Txn()
-	cr, updated, err := cc.checksum(ctx, root, txn, m, k)
+	cr, updated, err := cc.checksum(ctx, root, txn, m, k, ContentHasher(opts))
 	if err != nil {
 		return nil, err
 	}
@@ -413,7 +486,7 @@ func (cc *cacheContext) lazyChecksum(ctx context.Context, m *mount, p string) (*
 	return cr, err
 }
 
-func (cc *cacheContext) checksum(ctx context.Context, root *iradix.Node, txn *iradix.Txn, m *mount, k []byte) (*CacheRecord, bool, error) {
+func (cc *cacheContext) checksum(ctx context.Context, root *iradix.Node, txn *iradix.Txn, m *mount, k []byte, hasher fsutil.ContentHasher) (*CacheRecord, bool, error) {
 	v, ok := root.Get(k)
 
 	if !ok {
@@ -425,6 +498,7 @@ func (cc *cacheContext) checksum(ctx context.Context, root *iradix.Node, txn *ir
 		return cr, false, nil
 	}
 	var dgst digest.Digest
+	var stat *fsutil.Stat
 
 	switch cr.Type {
 	case CacheRecordTypeDir:
@@ -439,7 +513,7 @@ func (cc *cacheContext) checksum(ctx context.Context, root *iradix.Node, txn *ir
 			}
 			h.Write(bytes.TrimPrefix(subk, k))
 
-			subcr, _, err := cc.checksum(ctx, root, txn, m, subk)
+			subcr, _, err := cc.checksum(ctx, root, txn, m, subk, hasher)
 			if err != nil {
 				return nil, false, err
 			}
@@ -468,7 +542,7 @@ func (cc *cacheContext) checksum(ctx context.Context, root *iradix.Node, txn *ir
 			return nil, false, err
 		}
 
-		dgst, err = prepareDigest(fp, p, fi)
+		dgst, stat, err = prepareDigest(fp, p, fi, hasher)
 		if err != nil {
 			return nil, false, err
 		}
@@ -479,6 +553,9 @@ func (cc *cacheContext) checksum(ctx context.Context, root *iradix.Node, txn *ir
 		Type:     cr.Type,
 		Linkname: cr.Linkname,
 	}
+	if stat != nil {
+		cr2.setAttrs(stat)
+	}
 
 	txn.Insert(k, cr2)
 
@@ -559,24 +636,28 @@ func (cc *cacheContext) scanPath(ctx context.Context, m *mount, p string) (retEr
 	return nil
 }
 
-func prepareDigest(fp, p string, fi os.FileInfo) (digest.Digest, error) {
-	h, err := NewFileHash(fp, fi)
+func prepareDigest(fp, p string, fi os.FileInfo, hasher fsutil.ContentHasher) (digest.Digest, *fsutil.Stat, error) {
+	stat, err := newStat(fp, fi)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "failed to stat %s", p)
+	}
+	h, err := hasher(stat)
 	if err != nil {
-		return "", errors.Wrapf(err, "failed to create hash for %s", p)
+		return "", nil, errors.Wrapf(err, "failed to create hash for %s", p)
 	}
 	if fi.Mode().IsRegular() && fi.Size() > 0 {
 		// TODO: would be nice to put the contents to separate hash first
 		// so it can be cached for hardlinks
 		f, err := os.Open(fp)
 		if err != nil {
-			return "", errors.Wrapf(err, "failed to open %s", p)
+			return "", nil, errors.Wrapf(err, "failed to open %s", p)
 		}
 		defer f.Close()
 		if _, err := pools.Copy(h, f); err != nil {
-			return "", errors.Wrapf(err, "failed to copy file data for %s", p)
+			return "", nil, errors.Wrapf(err, "failed to copy file data for %s", p)
 		}
 	}
-	return digest.NewDigest(digest.SHA256, h), nil
+	return digest.NewDigest(digest.SHA256, h), stat, nil
 }
 
 func addParentToMap(d string, m map[string]struct{}) {
@@ -1,5 +1,6 @@
-// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// Code generated by protoc-gen-gogo.
 // source: checksum.proto
+// DO NOT EDIT!
 
 /*
 	Package contenthash is a generated protocol buffer package.
@@ -65,6 +66,11 @@ type CacheRecord struct {
 	Digest   github_com_opencontainers_go_digest.Digest `protobuf:"bytes,1,opt,name=digest,proto3,customtype=github.com/opencontainers/go-digest.Digest" json:"digest"`
 	Type     CacheRecordType                            `protobuf:"varint,2,opt,name=type,proto3,enum=contenthash.CacheRecordType" json:"type,omitempty"`
 	Linkname string                                     `protobuf:"bytes,3,opt,name=linkname,proto3" json:"linkname,omitempty"`
+	// the attributes that are not part of the digest
+	Uid     uint32            `protobuf:"varint,4,opt,name=uid,proto3" json:"uid,omitempty"`
+	Gid     uint32            `protobuf:"varint,5,opt,name=gid,proto3" json:"gid,omitempty"`
+	ModTime int64             `protobuf:"varint,6,opt,name=modTime,proto3" json:"modTime,omitempty"`
+	Xattrs  map[string][]byte `protobuf:"bytes,7,rep,name=xattrs" json:"xattrs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
 func (m *CacheRecord) Reset()                    { *m = CacheRecord{} }
@@ -86,6 +92,34 @@ func (m *CacheRecord) GetLinkname() string {
 	return ""
 }
 
+func (m *CacheRecord) GetUid() uint32 {
+	if m != nil {
+		return m.Uid
+	}
+	return 0
+}
+
+func (m *CacheRecord) GetGid() uint32 {
+	if m != nil {
+		return m.Gid
+	}
+	return 0
+}
+
+func (m *CacheRecord) GetModTime() int64 {
+	if m != nil {
+		return m.ModTime
+	}
+	return 0
+}
+
+func (m *CacheRecord) GetXattrs() map[string][]byte {
+	if m != nil {
+		return m.Xattrs
+	}
+	return nil
+}
+
 type CacheRecordWithPath struct {
 	Path   string       `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
 	Record *CacheRecord `protobuf:"bytes,2,opt,name=record" json:"record,omitempty"`
@@ -164,6 +198,44 @@ func (m *CacheRecord) MarshalTo(dAtA []byte) (int, error) {
 		i = encodeVarintChecksum(dAtA, i, uint64(len(m.Linkname)))
 		i += copy(dAtA[i:], m.Linkname)
 	}
+	if m.Uid != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintChecksum(dAtA, i, uint64(m.Uid))
+	}
+	if m.Gid != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintChecksum(dAtA, i, uint64(m.Gid))
+	}
+	if m.ModTime != 0 {
+		dAtA[i] = 0x30
+		i++
+		i = encodeVarintChecksum(dAtA, i, uint64(m.ModTime))
+	}
+	if len(m.Xattrs) > 0 {
+		for k, _ := range m.Xattrs {
+			dAtA[i] = 0x3a
+			i++
+			v := m.Xattrs[k]
+			byteSize := 0
+			if len(v) > 0 {
+				byteSize = 1 + len(v) + sovChecksum(uint64(len(v)))
+			}
+			mapSize := 1 + len(k) + sovChecksum(uint64(len(k))) + byteSize
+			i = encodeVarintChecksum(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintChecksum(dAtA, i, uint64(len(k)))
+			i += copy(dAtA[i:], k)
+			if len(v) > 0 {
+				dAtA[i] = 0x12
+				i++
+				i = encodeVarintChecksum(dAtA, i, uint64(len(v)))
+				i += copy(dAtA[i:], v)
+			}
+		}
+	}
 	return i, nil
 }
 
@@ -272,6 +344,27 @@ func (m *CacheRecord) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovChecksum(uint64(l))
 	}
+	if m.Uid != 0 {
+		n += 1 + sovChecksum(uint64(m.Uid))
+	}
+	if m.Gid != 0 {
+		n += 1 + sovChecksum(uint64(m.Gid))
+	}
+	if m.ModTime != 0 {
+		n += 1 + sovChecksum(uint64(m.ModTime))
+	}
+	if len(m.Xattrs) > 0 {
+		for k, v := range m.Xattrs {
+			_ = k
+			_ = v
+			l = 0
+			if len(v) > 0 {
+				l = 1 + len(v) + sovChecksum(uint64(len(v)))
+			}
+			mapEntrySize := 1 + len(k) + sovChecksum(uint64(len(k))) + l
+			n += mapEntrySize + 1 + sovChecksum(uint64(mapEntrySize))
+		}
+	}
 	return n
 }
 
@@ -420,6 +513,180 @@ func (m *CacheRecord) Unmarshal(dAtA []byte) error {
 			}
 			m.Linkname = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Uid", wireType)
+			}
+			m.Uid = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowChecksum
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Uid |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Gid", wireType)
+			}
+			m.Gid = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowChecksum
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Gid |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ModTime", wireType)
+			}
+			m.ModTime = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowChecksum
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ModTime |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Xattrs", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowChecksum
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthChecksum
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			var keykey uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowChecksum
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				keykey |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			var stringLenmapkey uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowChecksum
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLenmapkey |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLenmapkey := int(stringLenmapkey)
+			if intStringLenmapkey < 0 {
+				return ErrInvalidLengthChecksum
+			}
+			postStringIndexmapkey := iNdEx + intStringLenmapkey
+			if postStringIndexmapkey > l {
+				return io.ErrUnexpectedEOF
+			}
+			mapkey := string(dAtA[iNdEx:postStringIndexmapkey])
+			iNdEx = postStringIndexmapkey
+			if m.Xattrs == nil {
+				m.Xattrs = make(map[string][]byte)
+			}
+			if iNdEx < postIndex {
+				var valuekey uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowChecksum
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					valuekey |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				var mapbyteLen uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowChecksum
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					mapbyteLen |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				intMapbyteLen := int(mapbyteLen)
+				if intMapbyteLen < 0 {
+					return ErrInvalidLengthChecksum
+				}
+				postbytesIndex := iNdEx + intMapbyteLen
+				if postbytesIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				mapvalue := make([]byte, mapbyteLen)
+				copy(mapvalue, dAtA[iNdEx:postbytesIndex])
+				iNdEx = postbytesIndex
+				m.Xattrs[mapkey] = mapvalue
+			} else {
+				var mapvalue []byte
+				m.Xattrs[mapkey] = mapvalue
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipChecksum(dAtA[iNdEx:])
@@ -742,32 +1009,37 @@ var (
 func init() { proto.RegisterFile("checksum.proto", fileDescriptorChecksum) }
 
 var fileDescriptorChecksum = []byte{
-	// 418 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x92, 0xc1, 0x6a, 0xd4, 0x40,
-	0x18, 0xc7, 0x77, 0xba, 0xeb, 0xaa, 0xdf, 0x4a, 0x0d, 0x53, 0x68, 0xc3, 0x50, 0xb2, 0xe3, 0x5e,
-	0x5c, 0x8a, 0xcd, 0x96, 0x08, 0xde, 0xad, 0xd9, 0xa5, 0xd1, 0x2a, 0x32, 0x15, 0x44, 0x3c, 0x48,
-	0x36, 0x3b, 0x66, 0x42, 0x9b, 0x4c, 0x48, 0x66, 0x0f, 0xfb, 0x06, 0x92, 0x93, 0x2f, 0x90, 0x93,
-	0x82, 0xef, 0xe0, 0x5d, 0xe8, 0xd1, 0xb3, 0x87, 0x22, 0xeb, 0x8b, 0x48, 0x26, 0x55, 0x42, 0xca,
-	0x9e, 0xe6, 0xfb, 0x66, 0x7e, 0xdf, 0xff, 0xff, 0x9f, 0x61, 0x60, 0x3b, 0x10, 0x3c, 0x38, 0xcf,
-	0x97, 0xb1, 0x9d, 0x66, 0x52, 0x49, 0x3c, 0x08, 0x64, 0xa2, 0x78, 0xa2, 0x84, 0x9f, 0x0b, 0x72,
-	0x18, 0x46, 0x4a, 0x2c, 0xe7, 0x76, 0x20, 0xe3, 0x49, 0x28, 0x43, 0x39, 0xd1, 0xcc, 0x7c, 0xf9,
-	0x51, 0x77, 0xba, 0xd1, 0x55, 0x3d, 0x3b, 0xfa, 0x86, 0x60, 0xf0, 0xcc, 0x0f, 0x04, 0x67, 0x3c,
-	0x90, 0xd9, 0x02, 0x3f, 0x87, 0xfe, 0x22, 0x0a, 0x79, 0xae, 0x4c, 0x44, 0xd1, 0xf8, 0xee, 0xb1,
-	0x73, 0x79, 0x35, 0xec, 0xfc, 0xba, 0x1a, 0x1e, 0x34, 0x64, 0x65, 0xca, 0x93, 0xca, 0xd2, 0x8f,
-	0x12, 0x9e, 0xe5, 0x93, 0x50, 0x1e, 0xd6, 0x23, 0xb6, 0xab, 0x17, 0x76, 0xad, 0x80, 0x8f, 0xa0,
-	0xa7, 0x56, 0x29, 0x37, 0xb7, 0x28, 0x1a, 0x6f, 0x3b, 0xfb, 0x76, 0x23, 0xa6, 0xdd, 0xf0, 0x7c,
-	0xb3, 0x4a, 0x39, 0xd3, 0x24, 0x26, 0x70, 0xe7, 0x22, 0x4a, 0xce, 0x13, 0x3f, 0xe6, 0x66, 0xb7,
-	0xf2, 0x67, 0xff, 0xfb, 0xd1, 0x7b, 0xd8, 0x69, 0x0c, 0xbd, 0x8d, 0x94, 0x78, 0xed, 0x2b, 0x81,
-	0x31, 0xf4, 0x52, 0x5f, 0x89, 0x3a, 0x2e, 0xd3, 0x35, 0x3e, 0x82, 0x7e, 0xa6, 0x29, 0x6d, 0x3d,
-	0x70, 0xcc, 0x4d, 0xd6, 0xec, 0x9a, 0x1b, 0xcd, 0xe0, 0x5e, 0x63, 0x3b, 0xc7, 0x4f, 0xe0, 0x56,
-	0xa5, 0x94, 0x9b, 0x88, 0x76, 0xc7, 0x03, 0x87, 0x6e, 0x12, 0xf8, 0x17, 0x83, 0xd5, 0xf8, 0xc1,
-	0x0f, 0x04, 0xf7, 0x5b, 0x57, 0xc3, 0x0f, 0xa0, 0x37, 0xf3, 0x4e, 0xa7, 0x46, 0x87, 0xec, 0x15,
-	0x25, 0xdd, 0x69, 0x1d, 0xcf, 0xa2, 0x0b, 0x8e, 0x87, 0xd0, 0x75, 0x3d, 0x66, 0x20, 0xb2, 0x5b,
-	0x94, 0x14, 0xb7, 0x08, 0x37, 0xca, 0xf0, 0x23, 0x00, 0xd7, 0x63, 0x1f, 0x4e, 0xa6, 0x4f, 0xdd,
-	0x29, 0x33, 0xb6, 0xc8, 0x7e, 0x51, 0x52, 0xf3, 0x26, 0x77, 0xc2, 0xfd, 0x05, 0xcf, 0xf0, 0x43,
-	0xb8, 0x7d, 0xf6, 0xee, 0xe5, 0xa9, 0xf7, 0xea, 0x85, 0xd1, 0x25, 0xa4, 0x28, 0xe9, 0x6e, 0x0b,
-	0x3d, 0x5b, 0xc5, 0xd5, 0xbb, 0x92, 0xbd, 0x4f, 0x5f, 0xac, 0xce, 0xf7, 0xaf, 0x56, 0x3b, 0xf3,
-	0xb1, 0x71, 0xb9, 0xb6, 0xd0, 0xcf, 0xb5, 0x85, 0x7e, 0xaf, 0x2d, 0xf4, 0xf9, 0x8f, 0xd5, 0x99,
-	0xf7, 0xf5, 0x7f, 0x79, 0xfc, 0x37, 0x00, 0x00, 0xff, 0xff, 0x55, 0xf2, 0x2e, 0x06, 0x7d, 0x02,
-	0x00, 0x00,
+	// 508 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x92, 0x4f, 0x8b, 0xd3, 0x40,
+	0x18, 0xc6, 0x3b, 0x4d, 0xff, 0xe8, 0xdb, 0x75, 0x0d, 0xb3, 0xb2, 0x3b, 0x84, 0x25, 0x8d, 0x45,
+	0xb0, 0x2c, 0x6e, 0xba, 0x54, 0x10, 0x15, 0x2f, 0xae, 0x6d, 0xd9, 0xea, 0x2a, 0x32, 0xbb, 0xa0,
+	0xe2, 0x41, 0xd2, 0x64, 0x4c, 0x86, 0x36, 0x99, 0x92, 0x4c, 0xc5, 0x7e, 0x03, 0xe9, 0xc9, 0x2f,
+	0xd0, 0x93, 0x7e, 0x0a, 0xef, 0xc2, 0x1e, 0x3d, 0x7b, 0x28, 0x52, 0xbf, 0x88, 0x64, 0xd2, 0x95,
+	0xd0, 0xa5, 0xa7, 0xbc, 0xcf, 0x9b, 0xdf, 0xfb, 0xbc, 0xcf, 0x24, 0x03, 0xdb, 0x6e, 0xc0, 0xdc,
+	0x61, 0x32, 0x09, 0xed, 0x71, 0x2c, 0xa4, 0xc0, 0x35, 0x57, 0x44, 0x92, 0x45, 0x32, 0x70, 0x92,
+	0xc0, 0x38, 0xf4, 0xb9, 0x0c, 0x26, 0x03, 0xdb, 0x15, 0x61, 0xcb, 0x17, 0xbe, 0x68, 0x29, 0x66,
+	0x30, 0xf9, 0xa8, 0x94, 0x12, 0xaa, 0xca, 0x66, 0x1b, 0x8b, 0x22, 0xd4, 0x9e, 0x39, 0x6e, 0xc0,
+	0x28, 0x73, 0x45, 0xec, 0xe1, 0xe7, 0x50, 0xf1, 0xb8, 0xcf, 0x12, 0x49, 0x90, 0x85, 0x9a, 0xd7,
+	0x8f, 0xdb, 0x17, 0x8b, 0x7a, 0xe1, 0xf7, 0xa2, 0x7e, 0x90, 0xb3, 0x15, 0x63, 0x16, 0xa5, 0x2b,
+	0x1d, 0x1e, 0xb1, 0x38, 0x69, 0xf9, 0xe2, 0x30, 0x1b, 0xb1, 0x3b, 0xea, 0x41, 0x57, 0x0e, 0xf8,
+	0x08, 0x4a, 0x72, 0x3a, 0x66, 0xa4, 0x68, 0xa1, 0xe6, 0x76, 0x7b, 0xdf, 0xce, 0xc5, 0xb4, 0x73,
+	0x3b, 0xcf, 0xa7, 0x63, 0x46, 0x15, 0x89, 0x0d, 0xb8, 0x36, 0xe2, 0xd1, 0x30, 0x72, 0x42, 0x46,
+	0xb4, 0x74, 0x3f, 0xfd, 0xaf, 0xb1, 0x0e, 0xda, 0x84, 0x7b, 0xa4, 0x64, 0xa1, 0xe6, 0x0d, 0x9a,
+	0x96, 0x69, 0xc7, 0xe7, 0x1e, 0x29, 0x67, 0x1d, 0x9f, 0x7b, 0x98, 0x40, 0x35, 0x14, 0xde, 0x39,
+	0x0f, 0x19, 0xa9, 0x58, 0xa8, 0xa9, 0xd1, 0x4b, 0x89, 0x9f, 0x40, 0xe5, 0xb3, 0x23, 0x65, 0x9c,
+	0x90, 0xaa, 0xa5, 0x35, 0x6b, 0xed, 0x3b, 0x9b, 0xd2, 0xd8, 0x6f, 0x15, 0xd6, 0x8d, 0x64, 0x3c,
+	0xa5, 0xab, 0x19, 0xe3, 0x11, 0xd4, 0x72, 0xed, 0x74, 0xf1, 0x90, 0x4d, 0xb3, 0x2f, 0x44, 0xd3,
+	0x12, 0xdf, 0x82, 0xf2, 0x27, 0x67, 0x34, 0xc9, 0xce, 0xba, 0x45, 0x33, 0xf1, 0xb8, 0xf8, 0x10,
+	0x35, 0xde, 0xc3, 0x4e, 0xce, 0xfd, 0x0d, 0x97, 0xc1, 0x6b, 0x47, 0x06, 0x18, 0x43, 0x69, 0xec,
+	0xc8, 0x60, 0xe5, 0xa1, 0x6a, 0x7c, 0x04, 0x95, 0x58, 0x51, 0xca, 0xa5, 0xd6, 0x26, 0x9b, 0x32,
+	0xd2, 0x15, 0xd7, 0xe8, 0xc1, 0x56, 0xae, 0x9d, 0xe0, 0x07, 0x50, 0x4e, 0x9d, 0x12, 0x82, 0xd4,
+	0x21, 0xad, 0x4d, 0x06, 0x97, 0x31, 0x68, 0x86, 0x1f, 0xfc, 0x44, 0x70, 0x73, 0xed, 0x8f, 0xe0,
+	0xdb, 0x50, 0xea, 0xf5, 0x4f, 0xbb, 0x7a, 0xc1, 0xd8, 0x9b, 0xcd, 0xad, 0x9d, 0xb5, 0xd7, 0x3d,
+	0x3e, 0x62, 0xb8, 0x0e, 0x5a, 0xa7, 0x4f, 0x75, 0x64, 0xec, 0xce, 0xe6, 0x16, 0x5e, 0x23, 0x3a,
+	0x3c, 0xc6, 0xf7, 0x00, 0x3a, 0x7d, 0xfa, 0xe1, 0xa4, 0xfb, 0xb4, 0xd3, 0xa5, 0x7a, 0xd1, 0xd8,
+	0x9f, 0xcd, 0x2d, 0x72, 0x95, 0x3b, 0x61, 0x8e, 0xc7, 0x62, 0x7c, 0x17, 0xaa, 0x67, 0xef, 0x5e,
+	0x9e, 0xf6, 0x5f, 0xbd, 0xd0, 0x35, 0xc3, 0x98, 0xcd, 0xad, 0xdd, 0x35, 0xf4, 0x6c, 0x1a, 0xa6,
+	0xd7, 0xc1, 0xd8, 0xfb, 0xf2, 0xcd, 0x2c, 0xfc, 0xf8, 0x6e, 0xae, 0x67, 0x3e, 0xd6, 0x2f, 0x96,
+	0x26, 0xfa, 0xb5, 0x34, 0xd1, 0x9f, 0xa5, 0x89, 0xbe, 0xfe, 0x35, 0x0b, 0x83, 0x8a, 0xba, 0xe6,
+	0xf7, 0xff, 0x0d, 0x00, 0x52, 0x7d, 0x95, 0x61, 0x34, 0x03, 0x00, 0x00,
 }
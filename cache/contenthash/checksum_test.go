@@ -20,6 +20,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/tonistiigi/fsutil"
+	"golang.org/x/sync/errgroup"
 )
 
 const (
@@ -175,6 +176,183 @@ func TestChecksumBasicFile(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestChecksumWithOpts(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "buildkit-state")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	cm := setupCacheManager(t, tmpdir)
+	defer cm.Close()
+
+	opts := ChecksumOpts{
+		IncludePatterns: []string{"d0", "foo"},
+		ExcludePatterns: []string{"d0/*.log"},
+		FollowPaths:     []string{"link"},
+	}
+
+	checksum := func(ch []string) digest.Digest {
+		ref := createRef(t, cm, ch)
+		defer ref.Release(context.TODO())
+		cc, err := newCacheContext(ref.Metadata())
+		require.NoError(t, err)
+		dgst, err := cc.checksumWithOpts(context.TODO(), ref, "/", opts)
+		require.NoError(t, err)
+		return dgst
+	}
+
+	base := []string{
+		"ADD bar file data1",
+		"ADD d0 dir",
+		"ADD d0/abc file data0",
+		"ADD d0/def.log file data0",
+		"ADD foo file data0",
+		"ADD link symlink target",
+		"ADD target file data0",
+	}
+	dgst := checksum(base)
+
+	// excluded and unmatched files don't change the checksum
+	ignored := append([]string{}, base...)
+	ignored[0] = "ADD bar file data2"
+	ignored[3] = "ADD d0/def.log file data2"
+	require.Equal(t, dgst, checksum(ignored))
+
+	matched := append([]string{}, base...)
+	matched[2] = "ADD d0/abc file data2"
+	require.NotEqual(t, dgst, checksum(matched))
+
+	followed := append([]string{}, base...)
+	followed[6] = "ADD target file data2"
+	require.NotEqual(t, dgst, checksum(followed))
+}
+
+func TestChecksumSelectors(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "buildkit-state")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	cm := setupCacheManager(t, tmpdir)
+	defer cm.Close()
+
+	ch := []string{
+		"ADD foo file data0",
+		"ADD d0 dir",
+		"ADD d0/abc.go file data0",
+		"ADD d0/def symlink abc.go",
+		"ADD d0/sub dir",
+		"ADD d0/sub/x.go file data1",
+		"ADD d0/sub/y.txt file data0",
+		"ADD d1 symlink d0",
+	}
+	ref := createRef(t, cm, ch)
+	defer ref.Release(context.TODO())
+
+	ch[6] = "ADD d0/sub/y.txt file data1"
+	ref2 := createRef(t, cm, ch)
+	defer ref2.Release(context.TODO())
+
+	ch[5] = "ADD d0/sub/x.go file data0"
+	ref3 := createRef(t, cm, ch)
+	defer ref3.Release(context.TODO())
+
+	goFiles := []Selector{{Path: "d0/**/*.go", Wildcard: true}}
+	dgst, err := ChecksumSelectors(context.TODO(), ref, goFiles)
+	require.NoError(t, err)
+
+	// only the matched files are part of the checksum
+	dgst2, err := ChecksumSelectors(context.TODO(), ref2, goFiles)
+	require.NoError(t, err)
+	require.Equal(t, dgst, dgst2)
+	dgst3, err := ChecksumSelectors(context.TODO(), ref3, goFiles)
+	require.NoError(t, err)
+	require.NotEqual(t, dgst, dgst3)
+
+	// the pattern is matched in the target of a symlinked directory
+	linked := []Selector{{Path: "d1/**/*.go", Wildcard: true}, {Path: "d1/sub/*.go", Wildcard: true}, {Path: "d1/sub/x.go"}}
+	dgst, err = ChecksumSelectors(context.TODO(), ref, linked)
+	require.NoError(t, err)
+	dgst2, err = ChecksumSelectors(context.TODO(), ref2, linked)
+	require.NoError(t, err)
+	require.Equal(t, dgst, dgst2)
+	dgst3, err = ChecksumSelectors(context.TODO(), ref3, linked)
+	require.NoError(t, err)
+	require.NotEqual(t, dgst, dgst3)
+	linked = []Selector{{Path: "d1/sub/*.go", Wildcard: true}}
+	dgst, err = ChecksumSelectors(context.TODO(), ref, linked)
+	require.NoError(t, err)
+	dgst3, err = ChecksumSelectors(context.TODO(), ref3, linked)
+	require.NoError(t, err)
+	require.NotEqual(t, dgst, dgst3)
+
+	// a single path has the same checksum as Checksum
+	expected, err := Checksum(context.TODO(), ref, "d0/sub")
+	require.NoError(t, err)
+	dgst, err = ChecksumSelectors(context.TODO(), ref, []Selector{{Path: "d0/sub", FollowLinks: true}})
+	require.NoError(t, err)
+	require.Equal(t, expected, dgst)
+
+	expected, err = Checksum(context.TODO(), ref, "d0/abc.go")
+	require.NoError(t, err)
+	dgst, err = ChecksumSelectors(context.TODO(), ref, []Selector{{Path: "d0/def", FollowLinks: true}})
+	require.NoError(t, err)
+	require.Equal(t, expected, dgst)
+	dgst, err = ChecksumSelectors(context.TODO(), ref, []Selector{{Path: "d0/def"}})
+	require.NoError(t, err)
+	require.NotEqual(t, expected, dgst)
+
+	dgst, err = ChecksumSelectors(context.TODO(), ref, []Selector{{Path: "d0/*.go", Wildcard: true}})
+	require.NoError(t, err)
+	dgst2, err = ChecksumSelectors(context.TODO(), ref, []Selector{{Path: "d0/*.go", Wildcard: true}, {Path: "foo"}})
+	require.NoError(t, err)
+	require.NotEqual(t, dgst, dgst2)
+
+	_, err = ChecksumSelectors(context.TODO(), ref, []Selector{{Path: "nosuch/*", Wildcard: true}})
+	require.NoError(t, err)
+	_, err = ChecksumSelectors(context.TODO(), ref, []Selector{{Path: "d0/[", Wildcard: true}})
+	require.Error(t, err)
+}
+
+func TestMatchWildcard(t *testing.T) {
+	for _, tc := range []struct {
+		pattern string
+		path    string
+		match   bool
+	}{
+		{"/src/*.go", "/src/a.go", true},
+		{"/src/*.go", "/src/sub/a.go", false},
+		{"/src/**/*.go", "/src/a.go", true},
+		{"/src/**/*.go", "/src/sub/dir/a.go", true},
+		{"/src/**/*.go", "/other/a.go", false},
+		{"/src/**", "/src/sub/a.txt", true},
+		{"/**/test", "/a/b/test", true},
+		{"/src/a?.go", "/src/ab.go", true},
+	} {
+		require.Equal(t, tc.match, matchWildcard(tc.pattern, tc.path), "%s %s", tc.pattern, tc.path)
+	}
+}
+
+func TestNormalizeSelector(t *testing.T) {
+	for _, tc := range []struct {
+		in, out string
+	}{
+		{"", "/"},
+		{"/", "/"},
+		{"foo", "/foo"},
+		{"/foo/bar/", "/foo/bar"},
+		{"foo//./bar", "/foo/bar"},
+		{"foo..bar", "/foo..bar"},
+	} {
+		p, err := NormalizeSelector(tc.in)
+		require.NoError(t, err)
+		assert.Equal(t, tc.out, p)
+	}
+
+	for _, in := range []string{"..", "../foo", "/foo/../../bar", "foo/..", "foo\x00bar"} {
+		_, err := NormalizeSelector(in)
+		assert.Error(t, err, in)
+	}
+}
+
 func TestHandleChange(t *testing.T) {
 	tmpdir, err := ioutil.TempDir("", "buildkit-state")
 	require.NoError(t, err)
@@ -302,6 +480,237 @@ func TestPersistence(t *testing.T) {
 	assert.Equal(t, dgstFileData0, dgst)
 }
 
+func TestUpdateFromParent(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "buildkit-state")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	cm := setupCacheManager(t, tmpdir)
+	defer cm.Close()
+
+	ch := []string{
+		"ADD foo file data0",
+		"ADD bar file data1",
+		"ADD d0 dir",
+		"ADD d0/abc file data0",
+		"ADD d0/def symlink abc",
+		"ADD d0/ghi symlink nosuchfile",
+	}
+
+	parent := createRef(t, cm, ch)
+	defer parent.Release(context.TODO())
+
+	_, err = Checksum(context.TODO(), parent, "/")
+	require.NoError(t, err)
+	dgstAbc, err := Checksum(context.TODO(), parent, "d0/abc")
+	require.NoError(t, err)
+	dgstD0, err := Checksum(context.TODO(), parent, "d0")
+	require.NoError(t, err)
+	// the checksums are saved in the background, they have to be saved before
+	// the child finalizes the parent
+	pcci, err := GetCacheContext(context.TODO(), ensureOriginMetadata(parent.Metadata()))
+	require.NoError(t, err)
+	require.NoError(t, pcci.(*cacheContext).save())
+
+	ref := createChildRef(t, cm, parent, []string{
+		"ADD d1 dir",
+		"ADD d1/abc file data0",
+	}, func(root string) error {
+		if err := os.Remove(filepath.Join(root, "bar")); err != nil {
+			return err
+		}
+		return ioutil.WriteFile(filepath.Join(root, "foo"), []byte("data1"), 0644)
+	})
+	defer ref.Release(context.TODO())
+
+	err = UpdateFromParent(context.TODO(), ref)
+	require.NoError(t, err)
+
+	// the checksums are derived when ref is checksummed
+	cci, err := GetCacheContext(context.TODO(), ensureOriginMetadata(ref.Metadata()))
+	require.NoError(t, err)
+	require.Equal(t, 0, cci.(*cacheContext).tree.Len())
+	cc, err := getDefaultManager().refCacheContext(context.TODO(), ref)
+	require.NoError(t, err)
+	tree := cc.tree
+
+	// unchanged records keep their digests
+	v, ok := tree.Get([]byte("/d0/abc"))
+	require.True(t, ok)
+	require.Equal(t, dgstAbc, v.(*CacheRecord).Digest)
+	v, ok = tree.Get([]byte("/d0"))
+	require.True(t, ok)
+	require.Equal(t, dgstD0, v.(*CacheRecord).Digest)
+
+	v, ok = tree.Get([]byte("/foo"))
+	require.True(t, ok)
+	require.Equal(t, digest.Digest(""), v.(*CacheRecord).Digest)
+	v, ok = tree.Get([]byte(""))
+	require.True(t, ok)
+	require.Equal(t, digest.Digest(""), v.(*CacheRecord).Digest)
+	_, ok = tree.Get([]byte("/bar"))
+	require.False(t, ok)
+	_, ok = tree.Get([]byte("/d1/abc"))
+	require.True(t, ok)
+
+	// the result is the same as for a full scan
+	expected := createRef(t, cm, []string{
+		"ADD foo file data1",
+		"ADD d0 dir",
+		"ADD d0/abc file data0",
+		"ADD d0/def symlink abc",
+		"ADD d0/ghi symlink nosuchfile",
+		"ADD d1 dir",
+		"ADD d1/abc file data0",
+	})
+	defer expected.Release(context.TODO())
+
+	dgstExpected, err := Checksum(context.TODO(), expected, "/")
+	require.NoError(t, err)
+	dgst, err := Checksum(context.TODO(), ref, "/")
+	require.NoError(t, err)
+	require.Equal(t, dgstExpected, dgst)
+
+	dgst, err = Checksum(context.TODO(), ref, "foo")
+	require.NoError(t, err)
+	dgstExpected, err = Checksum(context.TODO(), parent, "bar")
+	require.NoError(t, err)
+	require.Equal(t, dgstExpected, dgst)
+}
+
+func TestChecksumResultsCache(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "buildkit-state")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	cm := setupCacheManager(t, tmpdir)
+	defer cm.Close()
+
+	ref := createRef(t, cm, []string{
+		"ADD foo file data0",
+		"ADD d0 dir",
+		"ADD d0/abc file data0",
+	})
+	defer ref.Release(context.TODO())
+
+	sels := []Selector{{Path: "d0/*", Wildcard: true}}
+	var eg errgroup.Group
+	dgsts := make([]digest.Digest, 10)
+	for i := range dgsts {
+		func(i int) {
+			eg.Go(func() error {
+				dgst, err := ChecksumSelectors(context.TODO(), ref, sels)
+				dgsts[i] = dgst
+				return err
+			})
+		}(i)
+	}
+	require.NoError(t, eg.Wait())
+	for _, dgst := range dgsts {
+		require.Equal(t, dgsts[0], dgst)
+	}
+
+	cci, err := GetCacheContext(context.TODO(), ensureOriginMetadata(ref.Metadata()))
+	require.NoError(t, err)
+	cc := cci.(*cacheContext)
+	sel := Selector{Path: "/d0/*", Wildcard: true}
+	key := fmt.Sprintf("%s:%d:%s:%+v", cc.md.ID(), cc.currentGeneration(), sel.String(), ChecksumOpts{})
+	m := getDefaultManager()
+	m.resultsMu.Lock()
+	_, ok := m.results.Get(key)
+	m.resultsMu.Unlock()
+	require.True(t, ok)
+
+	dgstFoo, err := Checksum(context.TODO(), ref, "foo")
+	require.NoError(t, err)
+
+	// the results are not used after the contents have changed
+	err = emit(cc.HandleChange, changeStream([]string{"ADD foo file data1"}))
+	require.NoError(t, err)
+	dgst, err := Checksum(context.TODO(), ref, "foo")
+	require.NoError(t, err)
+	require.NotEqual(t, dgstFoo, dgst)
+}
+
+func TestChecksumAttrs(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("requires root to change the owner of the files")
+	}
+	tmpdir, err := ioutil.TempDir("", "buildkit-state")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	cm := setupCacheManager(t, tmpdir)
+	defer cm.Close()
+
+	parent := createRef(t, cm, []string{
+		"ADD foo file data0",
+		"ADD d0 dir",
+		"ADD d0/abc file data0",
+	})
+	defer parent.Release(context.TODO())
+
+	ref := createChildRef(t, cm, parent, nil, func(mp string) error {
+		return os.Lchown(filepath.Join(mp, "d0/abc"), 1000, 1000)
+	})
+	defer ref.Release(context.TODO())
+
+	checksum := func(ref cache.ImmutableRef, sel Selector) digest.Digest {
+		dgst, err := ChecksumSelectors(context.TODO(), ref, []Selector{sel})
+		require.NoError(t, err)
+		return dgst
+	}
+
+	// the ownership is not part of the checksum by default
+	sel := Selector{Path: "d0", FollowLinks: true}
+	dgst := checksum(parent, sel)
+	require.Equal(t, dgst, checksum(ref, sel))
+	dgstDir, err := Checksum(context.TODO(), ref, "d0")
+	require.NoError(t, err)
+	require.Equal(t, dgstDir, dgst)
+
+	sel.Attrs = AttrCapabilities
+	require.Equal(t, checksum(parent, sel), checksum(ref, sel))
+	require.NotEqual(t, dgst, checksum(ref, sel))
+
+	for _, sel := range []Selector{
+		{Path: "d0", FollowLinks: true, Attrs: AttrOwnership},
+		{Path: "d0/abc", Attrs: AttrOwnership},
+		{Path: "d0/*", Wildcard: true, Attrs: AttrOwnership | AttrCapabilities},
+	} {
+		require.NotEqual(t, checksum(parent, sel), checksum(ref, sel), sel.String())
+	}
+
+	sel = Selector{Path: "foo", FollowLinks: true, Attrs: AttrOwnership}
+	require.Equal(t, checksum(parent, sel), checksum(ref, sel))
+}
+
+func TestContentHasherXattrs(t *testing.T) {
+	digestOf := func(hasher fsutil.ContentHasher, stat *fsutil.Stat) digest.Digest {
+		h, err := hasher(stat)
+		require.NoError(t, err)
+		return digest.NewDigest(digest.SHA256, h)
+	}
+
+	stat := &fsutil.Stat{Path: "foo", Mode: 0644}
+	withXattrs := &fsutil.Stat{Path: "foo", Mode: 0644, Xattrs: map[string][]byte{"user.foo": []byte("bar")}}
+
+	// the extended attributes are part of the hash by default
+	require.NotEqual(t, digestOf(NewFromStat, stat), digestOf(NewFromStat, withXattrs))
+	require.Equal(t, digestOf(NewFromStat, withXattrs), digestOf(ContentHasher(ChecksumOpts{}), withXattrs))
+
+	hasher := ContentHasher(ChecksumOpts{ExcludeXattrs: true})
+	require.Equal(t, digestOf(NewFromStat, stat), digestOf(hasher, withXattrs))
+	require.Len(t, withXattrs.Xattrs, 1)
+}
+
+func TestAttrsString(t *testing.T) {
+	require.Equal(t, "none", Attrs(0).String())
+	require.Equal(t, "ownership+capabilities", (AttrOwnership | AttrCapabilities).String())
+	require.Equal(t, "/foo wildcard=false followlinks=true", Selector{Path: "/foo", FollowLinks: true}.String())
+	require.Equal(t, "/foo wildcard=false followlinks=true attrs=modtime", Selector{Path: "/foo", FollowLinks: true, Attrs: AttrModTime}.String())
+}
+
 func createRef(t *testing.T, cm cache.Manager, files []string) cache.ImmutableRef {
 	mref, err := cm.New(context.TODO(), nil, cache.CachePolicyRetain)
 	require.NoError(t, err)
@@ -324,6 +733,31 @@ func createRef(t *testing.T, cm cache.Manager, files []string) cache.ImmutableRe
 	return ref
 }
 
+func createChildRef(t *testing.T, cm cache.Manager, parent cache.ImmutableRef, files []string, fn func(string) error) cache.ImmutableRef {
+	mref, err := cm.New(context.TODO(), parent, cache.CachePolicyRetain)
+	require.NoError(t, err)
+
+	mounts, err := mref.Mount(context.TODO(), false)
+	require.NoError(t, err)
+
+	lm := snapshot.LocalMounter(mounts)
+
+	mp, err := lm.Mount()
+	require.NoError(t, err)
+
+	err = writeChanges(mp, changeStream(files))
+	if err == nil {
+		err = fn(mp)
+	}
+	lm.Unmount()
+	require.NoError(t, err)
+
+	ref, err := mref.Commit(context.TODO())
+	require.NoError(t, err)
+
+	return ref
+}
+
 func setupCacheManager(t *testing.T, tmpdir string) cache.Manager {
 	snapshotter, err := naive.NewSnapshotter(filepath.Join(tmpdir, "snapshots"))
 	require.NoError(t, err)
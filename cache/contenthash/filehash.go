@@ -13,6 +13,14 @@ import (
 
 // NewFileHash returns new hash that is used for the builder cache keys
 func NewFileHash(path string, fi os.FileInfo) (hash.Hash, error) {
+	stat, err := newStat(path, fi)
+	if err != nil {
+		return nil, err
+	}
+	return NewFromStat(stat)
+}
+
+func newStat(path string, fi os.FileInfo) (*fsutil.Stat, error) {
 	var link string
 	if fi.Mode()&os.ModeSymlink != 0 {
 		var err error
@@ -32,9 +40,11 @@ func NewFileHash(path string, fi os.FileInfo) (hash.Hash, error) {
 	if err := setUnixOpt(path, fi, stat); err != nil {
 		return nil, err
 	}
-	return NewFromStat(stat)
+	return stat, nil
 }
 
+// NewFromStat returns the hash of a file with the stat information. The
+// ownership and the modification time are not part of the hash.
 func NewFromStat(stat *fsutil.Stat) (hash.Hash, error) {
 	fi := &statInfo{stat}
 	hdr, err := tar.FileInfoHeader(fi, stat.Linkname)
@@ -58,6 +68,19 @@ func NewFromStat(stat *fsutil.Stat) (hash.Hash, error) {
 	return tsh, nil
 }
 
+// ContentHasher returns the hash function of the files of the references
+// using opts
+func ContentHasher(opts ChecksumOpts) fsutil.ContentHasher {
+	if !opts.ExcludeXattrs {
+		return NewFromStat
+	}
+	return func(stat *fsutil.Stat) (hash.Hash, error) {
+		st := *stat
+		st.Xattrs = nil
+		return NewFromStat(&st)
+	}
+}
+
 type tarsumHash struct {
 	hash.Hash
 	hdr *tar.Header
@@ -0,0 +1,184 @@
+package contenthash
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/boltdb/bolt"
+	"github.com/docker/docker/pkg/fileutils"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/cache/metadata"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+const keyChecksumOpts = "buildkit.contenthash.opts.v0"
+
+// ChecksumOpts selects the files of a directory that are part of its
+// checksum. The patterns are matched against paths relative to the root of
+// the reference, not the selected directory.
+type ChecksumOpts struct {
+	// IncludePatterns limits the checksum to the paths that match any of
+	// the patterns, or have a parent that does
+	IncludePatterns []string
+	// ExcludePatterns removes the matching paths from the checksum using
+	// the .dockerignore syntax
+	ExcludePatterns []string
+	// FollowPaths are always part of the checksum. Symlinks on these paths
+	// are resolved and the checksum of the target is used.
+	FollowPaths []string
+	// ExcludeXattrs leaves the extended attributes of the files out of the
+	// checksum unless the selectors add them back with their Attrs
+	ExcludeXattrs bool
+}
+
+func (opts ChecksumOpts) empty() bool {
+	return len(opts.IncludePatterns) == 0 && len(opts.ExcludePatterns) == 0 && len(opts.FollowPaths) == 0
+}
+
+// SetChecksumOpts saves the options that Checksum applies for the
+// references using md
+func SetChecksumOpts(md *metadata.StorageItem, opts ChecksumOpts) error {
+	v, err := metadata.NewValue(opts)
+	if err != nil {
+		return errors.Wrap(err, "failed to create checksum options value")
+	}
+	return md.Update(func(b *bolt.Bucket) error {
+		return md.SetValue(b, keyChecksumOpts, v)
+	})
+}
+
+func getChecksumOpts(md *metadata.StorageItem) (ChecksumOpts, error) {
+	var opts ChecksumOpts
+	v := md.Get(keyChecksumOpts)
+	if v == nil {
+		return opts, nil
+	}
+	if err := v.Unmarshal(&opts); err != nil {
+		return opts, errors.Wrap(err, "failed to load checksum options")
+	}
+	return opts, nil
+}
+
+func (opts ChecksumOpts) excludeMatcher() (*fileutils.PatternMatcher, error) {
+	if len(opts.ExcludePatterns) == 0 {
+		return nil, nil
+	}
+	pm, err := fileutils.NewPatternMatcher(opts.ExcludePatterns)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid exclude patterns %s", opts.ExcludePatterns)
+	}
+	return pm, nil
+}
+
+// selects returns true if the path rel relative to the root of the reference
+// is part of the checksums with opts
+func (opts ChecksumOpts) selects(rel string, excludes *fileutils.PatternMatcher) (bool, error) {
+	if !matchIncludes(opts.IncludePatterns, rel) {
+		return false, nil
+	}
+	if excludes != nil {
+		excluded, err := excludes.Matches(rel)
+		if err != nil {
+			return false, errors.Wrap(err, "failed to match exclude patterns")
+		}
+		if excluded {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (cc *cacheContext) checksumWithOpts(ctx context.Context, mountable cache.Mountable, p string, opts ChecksumOpts) (digest.Digest, error) {
+	m := &mount{mountable: mountable}
+	defer m.clean()
+	return cc.checksumMountWithOpts(ctx, m, p, opts)
+}
+
+func (cc *cacheContext) checksumMountWithOpts(ctx context.Context, m *mount, p string, opts ChecksumOpts) (digest.Digest, error) {
+	p, cr, err := cc.checksumFollow(ctx, m, p)
+	if err != nil {
+		return "", err
+	}
+	if opts.empty() || cr.Type != CacheRecordTypeDir {
+		return cr.Digest, nil
+	}
+
+	excludes, err := opts.excludeMatcher()
+	if err != nil {
+		return "", err
+	}
+
+	// the digests of the whole directory were computed by checksumFollow
+	cc.mu.RLock()
+	root := cc.tree.Root()
+	cc.mu.RUnlock()
+
+	k := []byte(p)
+	if p == "/" {
+		k = []byte{}
+	}
+	prefix := append(k, '/')
+
+	h := sha256.New()
+	var walkErr error
+	root.WalkPrefix(prefix, func(subk []byte, v interface{}) bool {
+		subcr := v.(*CacheRecord)
+		if subcr.Type == CacheRecordTypeDir || bytes.Equal(subk, prefix) {
+			return false
+		}
+		rel := strings.TrimPrefix(strings.TrimSuffix(string(subk), "/"), "/")
+		ok, err := opts.selects(rel, excludes)
+		if err != nil {
+			walkErr = err
+			return true
+		}
+		if !ok {
+			return false
+		}
+		h.Write(bytes.TrimPrefix(subk, k))
+		h.Write([]byte(subcr.Digest))
+		return false
+	})
+	if walkErr != nil {
+		return "", walkErr
+	}
+
+	follow := append([]string{}, opts.FollowPaths...)
+	sort.Strings(follow)
+	for _, fp := range follow {
+		_, fcr, err := cc.checksumFollow(ctx, m, fp)
+		if err != nil {
+			if cause := errors.Cause(err); cause == errNotFound || os.IsNotExist(cause) {
+				continue
+			}
+			return "", err
+		}
+		h.Write([]byte("follow:" + path.Join("/", filepath.ToSlash(fp))))
+		h.Write([]byte(fcr.Digest))
+	}
+
+	return digest.NewDigest(digest.SHA256, h), nil
+}
+
+// matchIncludes returns true if p or any of its parents matches one of the
+// include patterns
+func matchIncludes(patterns []string, p string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for ; p != "." && p != "/" && p != ""; p = path.Dir(p) {
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, p); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,222 @@
+package contenthash
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/containerd/containerd/fs"
+	iradix "github.com/hashicorp/go-immutable-radix"
+	"github.com/moby/buildkit/cache"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// UpdateFromParent makes the first checksum of ref initialize its checksums
+// from the checksums that were computed for its parent. Only the records of
+// the paths that changed between the parent and ref are reset, so the
+// unchanged files are not hashed again. Nothing is computed if ref is never
+// checksummed. The checksums are computed from a full scan if ref has no
+// parent, the parent has no checksums or ref has checksums already.
+func UpdateFromParent(ctx context.Context, ref cache.ImmutableRef) error {
+	return getDefaultManager().UpdateFromParent(ctx, ref)
+}
+
+func (cm *cacheManager) UpdateFromParent(ctx context.Context, ref cache.ImmutableRef) error {
+	parent := ref.Parent()
+	if parent == nil {
+		return nil
+	}
+	parent.Release(context.TODO())
+
+	cci, err := cm.GetCacheContext(ctx, ensureOriginMetadata(ref.Metadata()))
+	if err != nil {
+		return err
+	}
+	cc := cci.(*cacheContext)
+	cc.mu.Lock()
+	cc.fromParent = true
+	cc.mu.Unlock()
+	return nil
+}
+
+// refCacheContext returns the cache context of ref. The checksums of a ref
+// marked with UpdateFromParent are derived from its parent first.
+func (cm *cacheManager) refCacheContext(ctx context.Context, ref cache.ImmutableRef) (*cacheContext, error) {
+	cci, err := cm.GetCacheContext(ctx, ensureOriginMetadata(ref.Metadata()))
+	if err != nil {
+		return nil, err
+	}
+	cc := cci.(*cacheContext)
+	cc.mu.Lock()
+	fromParent := cc.fromParent
+	cc.fromParent = false
+	cc.mu.Unlock()
+	if fromParent {
+		if err := cm.deriveFromParent(ctx, ref, cc); err != nil {
+			logrus.Warnf("failed to derive the checksums of %s from its parent: %v", ref.ID(), err)
+		}
+	}
+	return cc, nil
+}
+
+// deriveFromParent applies the changes between the parent of ref and ref to
+// the checksums of the parent and saves them as the checksums of ref
+func (cm *cacheManager) deriveFromParent(ctx context.Context, ref cache.ImmutableRef, cc *cacheContext) error {
+	parent := ref.Parent()
+	if parent == nil {
+		return nil
+	}
+	defer parent.Release(context.TODO())
+
+	pcci, err := cm.GetCacheContext(ctx, ensureOriginMetadata(parent.Metadata()))
+	if err != nil {
+		return err
+	}
+	pcc := pcci.(*cacheContext)
+	pcc.mu.Lock()
+	if pcc.txn != nil {
+		pcc.commitActiveTransaction()
+	}
+	tree := pcc.tree
+	pcc.mu.Unlock()
+	if tree.Len() == 0 {
+		return nil
+	}
+
+	cc.mu.RLock()
+	empty := cc.tree.Len() == 0 && cc.txn == nil
+	cc.mu.RUnlock()
+	if !empty {
+		return nil
+	}
+
+	u := &treeUpdater{txn: tree.Txn(), reset: map[string]struct{}{}}
+	// with overlayfs the directory of the layer of ref only contains the
+	// paths that changed from the parent, so only those are walked
+	if dir, ok := layerDir(ctx, ref, parent); ok {
+		u.root = dir
+		if err := layerChanges(ctx, dir, u.handleChange); err != nil {
+			return errors.Wrapf(err, "failed to read changes from %s", parent.ID())
+		}
+	} else {
+		pm := &mount{mountable: parent}
+		defer pm.clean()
+		parentPath, err := pm.mount(ctx)
+		if err != nil {
+			return err
+		}
+		m := &mount{mountable: ref}
+		defer m.clean()
+		refPath, err := m.mount(ctx)
+		if err != nil {
+			return err
+		}
+		u.root = refPath
+		if err := fs.Changes(ctx, parentPath, refPath, u.handleChange); err != nil {
+			return errors.Wrapf(err, "failed to compute changes from %s", parent.ID())
+		}
+	}
+	tree = u.txn.Commit()
+
+	cc.mu.Lock()
+	if cc.tree.Len() != 0 || cc.txn != nil {
+		// checksummed while the changes were computed
+		cc.mu.Unlock()
+		return nil
+	}
+	cc.tree = tree
+	cc.generation = nextGeneration()
+	cc.mu.Unlock()
+	return cc.save()
+}
+
+// treeUpdater applies the changes of a ref to the checksum tree of its parent.
+// The tree contains the full subtree of every directory record in it, so a
+// change is only applied if the record of its parent directory exists. The
+// other paths are added when they are scanned.
+type treeUpdater struct {
+	txn  *iradix.Txn
+	root string
+	// reset are the directory records with removed digests
+	reset map[string]struct{}
+}
+
+func (u *treeUpdater) handleChange(kind fs.ChangeKind, p string, fi os.FileInfo, err error) error {
+	if err != nil {
+		return err
+	}
+	k := path.Join("/", p)
+	if k == "/" {
+		k = ""
+	}
+	d := parentKey(k)
+	if v, ok := u.txn.Get([]byte(d)); !ok || v.(*CacheRecord).Type != CacheRecordTypeDir {
+		return nil
+	}
+
+	switch kind {
+	case fs.ChangeKindDelete:
+		u.txn.Delete([]byte(k))
+		u.txn.DeletePrefix([]byte(k + "/"))
+	case fs.ChangeKindAdd, fs.ChangeKindModify:
+		old, ok := u.txn.Get([]byte(k))
+		wasDir := ok && old.(*CacheRecord).Type == CacheRecordTypeDir
+		if fi.IsDir() {
+			if !wasDir {
+				u.txn.Delete([]byte(k))
+			}
+			// the children of the directory are reported as separate
+			// changes
+			u.txn.Insert([]byte(k), &CacheRecord{Type: CacheRecordTypeDir})
+			u.txn.Insert([]byte(k+"/"), &CacheRecord{Type: CacheRecordTypeDirHeader})
+			u.reset[k] = struct{}{}
+			break
+		}
+		if wasDir {
+			u.txn.DeletePrefix([]byte(k + "/"))
+		}
+		cr := &CacheRecord{Type: CacheRecordTypeFile}
+		if fi.Mode()&os.ModeSymlink != 0 {
+			link, err := os.Readlink(filepath.Join(u.root, p))
+			if err != nil {
+				return err
+			}
+			cr.Type = CacheRecordTypeSymlink
+			cr.Linkname = filepath.ToSlash(link)
+		}
+		u.txn.Insert([]byte(k), cr)
+	default:
+		return nil
+	}
+	u.resetParents(d)
+	return nil
+}
+
+// resetParents removes the digests of the directory d and its parents
+func (u *treeUpdater) resetParents(d string) {
+	for {
+		if _, ok := u.reset[d]; ok {
+			return
+		}
+		v, ok := u.txn.Get([]byte(d))
+		if !ok || v.(*CacheRecord).Type != CacheRecordTypeDir {
+			return
+		}
+		u.txn.Insert([]byte(d), &CacheRecord{Type: CacheRecordTypeDir})
+		u.reset[d] = struct{}{}
+		if d == "" {
+			return
+		}
+		d = parentKey(d)
+	}
+}
+
+func parentKey(k string) string {
+	d := path.Dir(k)
+	if d == "/" || d == "." {
+		return ""
+	}
+	return d
+}
@@ -0,0 +1,103 @@
+package contenthash
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/containerd/containerd/fs"
+	"github.com/moby/buildkit/cache"
+	"golang.org/x/sys/unix"
+)
+
+// layerDir returns the directory of the overlayfs layer of ref if the rest of
+// the layers of ref are the ones of parent
+func layerDir(ctx context.Context, ref, parent cache.Mountable) (string, bool) {
+	dirs, ok := overlayDirs(ctx, ref)
+	if !ok || len(dirs) < 2 {
+		return "", false
+	}
+	parentDirs, ok := overlayDirs(ctx, parent)
+	if !ok || len(parentDirs) != len(dirs)-1 {
+		return "", false
+	}
+	for i, d := range parentDirs {
+		if dirs[i+1] != d {
+			return "", false
+		}
+	}
+	return dirs[0], true
+}
+
+// overlayDirs returns the directories of the layers of the read-only mounts
+// of m with the overlayfs snapshotter, the top one first. The committed refs
+// can still be mounted with the upper directory of the snapshot they were
+// committed from.
+func overlayDirs(ctx context.Context, m cache.Mountable) ([]string, bool) {
+	mounts, err := m.Mount(ctx, true)
+	if err != nil || len(mounts) != 1 {
+		return nil, false
+	}
+	switch mounts[0].Type {
+	case "bind":
+		return []string{mounts[0].Source}, true
+	case "overlay":
+		var upper string
+		var dirs []string
+		for _, o := range mounts[0].Options {
+			if strings.HasPrefix(o, "upperdir=") {
+				upper = strings.TrimPrefix(o, "upperdir=")
+			}
+			if strings.HasPrefix(o, "lowerdir=") {
+				dirs = strings.Split(strings.TrimPrefix(o, "lowerdir="), ":")
+			}
+		}
+		if upper != "" {
+			dirs = append([]string{upper}, dirs...)
+		}
+		return dirs, len(dirs) > 0
+	default:
+		return nil, false
+	}
+}
+
+// layerChanges calls fn for the paths of the overlayfs layer dir. The
+// whiteouts are reported as deletes and the opaque directories as deletes
+// followed by adds.
+func layerChanges(ctx context.Context, dir string, fn fs.ChangeFunc) error {
+	return filepath.Walk(dir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		k := string(filepath.Separator) + rel
+		if fi.Mode()&os.ModeCharDevice != 0 {
+			if st, ok := fi.Sys().(*syscall.Stat_t); ok && st.Rdev == 0 {
+				return fn(fs.ChangeKindDelete, k, nil, nil)
+			}
+		}
+		if fi.IsDir() {
+			buf := make([]byte, 1)
+			if n, err := unix.Lgetxattr(p, "trusted.overlay.opaque", buf); err == nil && n == 1 && buf[0] == 'y' {
+				if err := fn(fs.ChangeKindDelete, k, nil, nil); err != nil {
+					return err
+				}
+				return fn(fs.ChangeKindAdd, k, fi, nil)
+			}
+		}
+		return fn(fs.ChangeKindModify, k, fi, nil)
+	})
+}
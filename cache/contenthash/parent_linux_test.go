@@ -0,0 +1,105 @@
+package contenthash
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containerd/containerd/fs"
+	"github.com/containerd/containerd/snapshot/overlay"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/cache/metadata"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func TestUpdateFromParentOverlay(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "buildkit-state")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	snapshotter, err := overlay.NewSnapshotter(filepath.Join(tmpdir, "snapshots"))
+	require.NoError(t, err)
+	md, err := metadata.NewStore(filepath.Join(tmpdir, "metadata.db"))
+	require.NoError(t, err)
+	cm, err := cache.NewManager(cache.ManagerOpt{
+		Snapshotter:   snapshotter,
+		MetadataStore: md,
+	})
+	require.NoError(t, err)
+	defer cm.Close()
+
+	parent := createRef(t, cm, []string{
+		"ADD foo file data0",
+		"ADD bar file data1",
+		"ADD d0 dir",
+		"ADD d0/abc file data0",
+	})
+	defer parent.Release(context.TODO())
+	dgstAbc, err := Checksum(context.TODO(), parent, "d0/abc")
+	require.NoError(t, err)
+	pcci, err := GetCacheContext(context.TODO(), ensureOriginMetadata(parent.Metadata()))
+	require.NoError(t, err)
+	require.NoError(t, pcci.(*cacheContext).save())
+
+	ref := createChildRef(t, cm, parent, []string{
+		"ADD d1 dir",
+		"ADD d1/abc file data0",
+	}, func(root string) error {
+		if err := os.Remove(filepath.Join(root, "bar")); err != nil {
+			return err
+		}
+		return ioutil.WriteFile(filepath.Join(root, "foo"), []byte("data1"), 0644)
+	})
+	defer ref.Release(context.TODO())
+
+	// only the layer of ref is read
+	dir, ok := layerDir(context.TODO(), ref, parent)
+	require.True(t, ok)
+	_, err = os.Stat(filepath.Join(dir, "d0"))
+	require.True(t, os.IsNotExist(err))
+
+	require.NoError(t, UpdateFromParent(context.TODO(), ref))
+	cc, err := getDefaultManager().refCacheContext(context.TODO(), ref)
+	require.NoError(t, err)
+	v, ok := cc.tree.Get([]byte("/d0/abc"))
+	require.True(t, ok)
+	require.Equal(t, dgstAbc, v.(*CacheRecord).Digest)
+	_, ok = cc.tree.Get([]byte("/bar"))
+	require.False(t, ok)
+
+	expected := createRef(t, cm, []string{
+		"ADD foo file data1",
+		"ADD d0 dir",
+		"ADD d0/abc file data0",
+		"ADD d1 dir",
+		"ADD d1/abc file data0",
+	})
+	defer expected.Release(context.TODO())
+	dgstExpected, err := Checksum(context.TODO(), expected, "/")
+	require.NoError(t, err)
+	dgst, err := Checksum(context.TODO(), ref, "/")
+	require.NoError(t, err)
+	require.Equal(t, dgstExpected, dgst)
+}
+
+func TestLayerChanges(t *testing.T) {
+	dir, err := ioutil.TempDir("", "layerchanges")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "foo"), []byte("foo"), 0644))
+	require.NoError(t, unix.Mknod(filepath.Join(dir, "bar"), unix.S_IFCHR, 0))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "d0"), 0755))
+	require.NoError(t, unix.Setxattr(filepath.Join(dir, "d0"), "trusted.overlay.opaque", []byte("y"), 0))
+
+	var changes []string
+	err = layerChanges(context.TODO(), dir, func(kind fs.ChangeKind, p string, fi os.FileInfo, err error) error {
+		changes = append(changes, kind.String()+" "+p)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"delete /bar", "delete /d0", "add /d0", "modify /foo"}, changes)
+}
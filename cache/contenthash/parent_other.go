@@ -0,0 +1,21 @@
+// +build !linux
+
+package contenthash
+
+import (
+	"context"
+
+	"github.com/containerd/containerd/fs"
+	"github.com/moby/buildkit/cache"
+	"github.com/pkg/errors"
+)
+
+// layerDir returns false as the layers of the snapshots can only be read
+// separately with overlayfs
+func layerDir(ctx context.Context, ref, parent cache.Mountable) (string, bool) {
+	return "", false
+}
+
+func layerChanges(ctx context.Context, dir string, fn fs.ChangeFunc) error {
+	return errors.Errorf("overlay layers are not supported")
+}
@@ -0,0 +1,60 @@
+package contenthash
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	digest "github.com/opencontainers/go-digest"
+	netcontext "golang.org/x/net/context"
+)
+
+const resultsCacheSize = 1000
+
+var lastGeneration uint64
+
+// nextGeneration returns a new generation for the contents of a cache
+// context. Generations are never reused, so the results of a context that was
+// replaced or reloaded can't be returned for another tree.
+func nextGeneration() uint64 {
+	return atomic.AddUint64(&lastGeneration, 1)
+}
+
+// cachedChecksum returns the result of fn for the selection sel of cc from
+// the results cache. Concurrent calls for the same selection share a single
+// call of fn. The results are only stored if the contents of cc didn't
+// change while fn was running.
+func (cm *cacheManager) cachedChecksum(ctx context.Context, cc *cacheContext, sel string, opts ChecksumOpts, fn func(context.Context) (digest.Digest, error)) (digest.Digest, error) {
+	gen := cc.currentGeneration()
+	key := fmt.Sprintf("%s:%d:%s:%+v", cc.md.ID(), gen, sel, opts)
+
+	cm.resultsMu.Lock()
+	v, ok := cm.results.Get(key)
+	cm.resultsMu.Unlock()
+	if ok {
+		return v.(digest.Digest), nil
+	}
+
+	v, err := cm.g.Do(ctx, key, func(ctx netcontext.Context) (interface{}, error) {
+		dgst, err := fn(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if cc.currentGeneration() == gen {
+			cm.resultsMu.Lock()
+			cm.results.Add(key, dgst)
+			cm.resultsMu.Unlock()
+		}
+		return dgst, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return v.(digest.Digest), nil
+}
+
+func (cc *cacheContext) currentGeneration() uint64 {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+	return cc.generation
+}
@@ -0,0 +1,344 @@
+package contenthash
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/moby/buildkit/cache"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+const maxSymlinkLimit = 255
+
+// Selector is a path of a reference that is part of a checksum
+type Selector struct {
+	// Path is relative to the root of the reference. With Wildcard it's a
+	// pattern where "**" matches any number of directories and the other
+	// components use the path.Match syntax.
+	Path     string
+	Wildcard bool
+	// FollowLinks uses the targets of the selected symlinks instead of the
+	// links themselves
+	FollowLinks bool
+	// Attrs are the file attributes of the selected paths that are part of
+	// the checksum
+	Attrs Attrs
+}
+
+func (s Selector) String() string {
+	str := fmt.Sprintf("%s wildcard=%t followlinks=%t", s.Path, s.Wildcard, s.FollowLinks)
+	if s.Attrs != 0 {
+		str += " attrs=" + s.Attrs.String()
+	}
+	return str
+}
+
+// ChecksumSelectors returns the checksum of the paths of ref selected by sels.
+// The checksum of a single path without Attrs is the checksum of the path
+// itself, so a path that follows links has the same checksum as with Checksum. Patterns
+// that don't match any paths are part of the checksum without contents.
+func ChecksumSelectors(ctx context.Context, ref cache.ImmutableRef, sels []Selector) (digest.Digest, error) {
+	return getDefaultManager().ChecksumSelectors(ctx, ref, sels)
+}
+
+func (cm *cacheManager) ChecksumSelectors(ctx context.Context, ref cache.ImmutableRef, sels []Selector) (digest.Digest, error) {
+	if len(sels) == 0 {
+		return "", errors.Errorf("no selectors for %s", ref.ID())
+	}
+	normalized := make([]Selector, len(sels))
+	for i, s := range sels {
+		p, err := NormalizeSelector(s.Path)
+		if err != nil {
+			return "", err
+		}
+		if s.Wildcard {
+			if err := validateWildcard(p); err != nil {
+				return "", err
+			}
+		}
+		s.Path = p
+		normalized[i] = s
+	}
+	sels = normalized
+	if len(sels) == 1 && !sels[0].Wildcard && sels[0].FollowLinks && sels[0].Attrs == 0 {
+		return cm.Checksum(ctx, ref, sels[0].Path)
+	}
+
+	opts, err := getChecksumOpts(ensureOriginMetadata(ref.Metadata()))
+	if err != nil {
+		return "", err
+	}
+	cc, err := cm.refCacheContext(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	key := make([]string, len(sels))
+	for i, s := range sels {
+		key[i] = s.String()
+	}
+	return cm.cachedChecksum(ctx, cc, strings.Join(key, ","), opts, func(ctx context.Context) (digest.Digest, error) {
+		return cc.checksumSelectors(ctx, ref, sels, opts)
+	})
+}
+
+func (cc *cacheContext) checksumSelectors(ctx context.Context, mountable cache.Mountable, sels []Selector, opts ChecksumOpts) (digest.Digest, error) {
+	m := &mount{mountable: mountable}
+	defer m.clean()
+
+	if len(sels) == 1 && !sels[0].Wildcard {
+		return cc.checksumSelected(ctx, m, sels[0], opts)
+	}
+
+	h := sha256.New()
+	for _, s := range sels {
+		h.Write([]byte(s.String()))
+		if !s.Wildcard {
+			dgst, err := cc.checksumSelected(ctx, m, s, opts)
+			if err != nil {
+				return "", err
+			}
+			h.Write([]byte(dgst))
+			continue
+		}
+		matches, err := cc.wildcardMatches(ctx, m, s.Path, opts)
+		if err != nil {
+			return "", err
+		}
+		for _, p := range matches {
+			match := s
+			match.Path = p
+			dgst, err := cc.checksumSelected(ctx, m, match, opts)
+			if err != nil {
+				return "", err
+			}
+			h.Write([]byte(p))
+			h.Write([]byte(dgst))
+		}
+	}
+	return digest.NewDigest(digest.SHA256, h), nil
+}
+
+// checksumSelected returns the checksum of the path of s, which is not a
+// pattern. A symlink on the path is hashed as a link unless s follows links.
+func (cc *cacheContext) checksumSelected(ctx context.Context, m *mount, s Selector, opts ChecksumOpts) (digest.Digest, error) {
+	dir, _, err := cc.resolve(ctx, m, path.Dir(s.Path))
+	if err != nil {
+		return "", err
+	}
+	p := path.Join(dir, path.Base(s.Path))
+	if !s.FollowLinks {
+		cr, err := cc.checksumNoFollow(ctx, m, p)
+		if err != nil {
+			return "", err
+		}
+		if cr.Type == CacheRecordTypeSymlink {
+			if s.Attrs == 0 {
+				return cr.Digest, nil
+			}
+			return cc.withAttrs(cr.Digest, p, s.Attrs, opts)
+		}
+	}
+	dgst, err := cc.checksumMountWithOpts(ctx, m, p, opts)
+	if err != nil || s.Attrs == 0 {
+		return dgst, err
+	}
+	p, _, err = cc.checksumFollow(ctx, m, p)
+	if err != nil {
+		return "", err
+	}
+	return cc.withAttrs(dgst, p, s.Attrs, opts)
+}
+
+// wildcardMatches returns the paths that match pattern in the order of the
+// tree. The contents of a matched directory are not matched separately.
+func (cc *cacheContext) wildcardMatches(ctx context.Context, m *mount, pattern string, opts ChecksumOpts) ([]string, error) {
+	dir := wildcardPrefix(pattern)
+	resolved, cr, err := cc.resolve(ctx, m, dir)
+	if err != nil {
+		if errors.Cause(err) == errNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if cr.Type != CacheRecordTypeDir {
+		return nil, nil
+	}
+	pattern = path.Join(resolved, strings.TrimPrefix(pattern, dir))
+	dir = resolved
+
+	excludes, err := opts.excludeMatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	cc.mu.RLock()
+	root := cc.tree.Root()
+	cc.mu.RUnlock()
+
+	prefix := dir + "/"
+	if dir == "/" {
+		prefix = "/"
+	}
+	var matches []string
+	var skip string
+	var walkErr error
+	root.WalkPrefix([]byte(prefix), func(k []byte, v interface{}) bool {
+		p := string(k)
+		if v.(*CacheRecord).Type == CacheRecordTypeDirHeader || (skip != "" && strings.HasPrefix(p, skip)) {
+			return false
+		}
+		if !matchWildcard(pattern, p) {
+			return false
+		}
+		ok, err := opts.selects(strings.TrimPrefix(p, "/"), excludes)
+		if err != nil {
+			walkErr = err
+			return true
+		}
+		if !ok {
+			return false
+		}
+		matches = append(matches, p)
+		if v.(*CacheRecord).Type == CacheRecordTypeDir {
+			skip = p + "/"
+		}
+		return false
+	})
+	if walkErr != nil {
+		return nil, walkErr
+	}
+	return matches, nil
+}
+
+// resolve follows the symlinks in all components of p and returns the
+// resolved path and its record
+func (cc *cacheContext) resolve(ctx context.Context, m *mount, p string) (string, *CacheRecord, error) {
+	resolved := "/"
+	parts := splitPath(p)
+	links := 0
+	for len(parts) > 0 {
+		next := path.Join(resolved, parts[0])
+		parts = parts[1:]
+		cr, err := cc.record(ctx, m, next)
+		if err != nil {
+			return "", nil, err
+		}
+		if cr.Type != CacheRecordTypeSymlink {
+			resolved = next
+			continue
+		}
+		links++
+		if links > maxSymlinkLimit {
+			return "", nil, errors.Errorf("too many symlinks: %s", p)
+		}
+		link := cr.Linkname
+		if !path.IsAbs(link) {
+			link = path.Join(resolved, link)
+		}
+		parts = append(splitPath(link), parts...)
+		resolved = "/"
+	}
+	cr, err := cc.record(ctx, m, resolved)
+	if err != nil {
+		return "", nil, err
+	}
+	return resolved, cr, nil
+}
+
+func splitPath(p string) []string {
+	p = strings.TrimPrefix(path.Clean("/"+p), "/")
+	if p == "" {
+		return nil
+	}
+	return strings.Split(p, "/")
+}
+
+// record returns the record of p without computing its digest. The paths
+// around p are scanned if they are not in the tree.
+func (cc *cacheContext) record(ctx context.Context, m *mount, p string) (*CacheRecord, error) {
+	k := p
+	if k == "/" {
+		k = ""
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if cc.txn != nil {
+		cc.commitActiveTransaction()
+	}
+	if cc.needsScan(cc.tree.Root(), k) {
+		if err := cc.scanPath(ctx, m, k); err != nil {
+			if os.IsNotExist(errors.Cause(err)) {
+				return nil, errors.Wrapf(errNotFound, "%s not found", p)
+			}
+			return nil, err
+		}
+	}
+	v, ok := cc.tree.Root().Get([]byte(k))
+	if !ok {
+		return nil, errors.Wrapf(errNotFound, "%s not found", p)
+	}
+	return v.(*CacheRecord), nil
+}
+
+// wildcardPrefix returns the directory of pattern before the first component
+// with wildcards
+func wildcardPrefix(pattern string) string {
+	parts := strings.Split(pattern, "/")
+	for i, c := range parts {
+		if hasWildcard(c) {
+			return path.Join("/", strings.Join(parts[:i], "/"))
+		}
+	}
+	return pattern
+}
+
+func hasWildcard(s string) bool {
+	return strings.ContainsAny(s, `*?[\`)
+}
+
+func validateWildcard(pattern string) error {
+	for _, c := range strings.Split(pattern, "/") {
+		if _, err := path.Match(c, ""); err != nil {
+			return errors.Wrapf(err, "invalid pattern %s", pattern)
+		}
+	}
+	return nil
+}
+
+// matchWildcard returns true if the path p matches pattern. Both are cleaned
+// absolute unix paths.
+func matchWildcard(pattern, p string) bool {
+	return matchComponents(strings.Split(pattern, "/"), strings.Split(p, "/"))
+}
+
+func matchComponents(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			pattern = pattern[1:]
+			if len(pattern) == 0 {
+				return true
+			}
+			for i := range parts {
+				if matchComponents(pattern, parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+			return false
+		}
+		pattern, parts = pattern[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
@@ -0,0 +1,259 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/boltdb/bolt"
+	"github.com/containerd/containerd/fs"
+	"github.com/moby/buildkit/cache/metadata"
+	"github.com/moby/buildkit/snapshot"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+const keyDiffDigest = "cache.diffDigest"
+
+var errDifferentFiles = errors.New("different files")
+
+// diffIndex is the index of the records of parent with the changes dgst
+func diffIndex(parent string, dgst digest.Digest) string {
+	return fmt.Sprintf("%s:%s:%s", keyDiffDigest, parent, dgst)
+}
+
+func setDiffDigest(si *metadata.StorageItem, parent string, dgst digest.Digest) error {
+	v, err := metadata.NewValue(dgst)
+	if err != nil {
+		return errors.Wrap(err, "failed to create diffDigest value")
+	}
+	v.Index = diffIndex(parent, dgst)
+	si.Queue(func(b *bolt.Bucket) error {
+		return si.SetValue(b, keyDiffDigest, v)
+	})
+	return nil
+}
+
+// diffDigest returns the digest of the changes of sr compared to its parent.
+// Modified files that are identical to the files of the parent apart from
+// their modification times are not part of the changes.
+func (sr *mutableRef) diffDigest(ctx context.Context) (digest.Digest, error) {
+	var parentDir string
+	if sr.parent != nil {
+		m, err := sr.parent.Mount(ctx, true)
+		if err != nil {
+			return "", err
+		}
+		lm := snapshot.LocalMounter(m)
+		parentDir, err = lm.Mount()
+		if err != nil {
+			return "", err
+		}
+		defer lm.Unmount()
+	}
+
+	m, err := sr.Mount(ctx, true)
+	if err != nil {
+		return "", err
+	}
+	lm := snapshot.LocalMounter(m)
+	dir, err := lm.Mount()
+	if err != nil {
+		return "", err
+	}
+	defer lm.Unmount()
+
+	h := sha256.New()
+	if err := fs.Changes(ctx, parentDir, dir, func(kind fs.ChangeKind, p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if kind == fs.ChangeKindDelete {
+			fmt.Fprintf(h, "%s %s\n", kind, filepath.ToSlash(p))
+			return nil
+		}
+		dgst, err := fileDigest(filepath.Join(dir, p), fi)
+		if err != nil {
+			return err
+		}
+		if kind == fs.ChangeKindModify {
+			pfi, err := os.Lstat(filepath.Join(parentDir, p))
+			if err != nil {
+				return err
+			}
+			pdgst, err := fileDigest(filepath.Join(parentDir, p), pfi)
+			if err != nil {
+				return err
+			}
+			if pdgst == dgst {
+				return nil
+			}
+		}
+		fmt.Fprintf(h, "%s %s %s\n", kind, filepath.ToSlash(p), dgst)
+		return nil
+	}); err != nil {
+		return "", errors.Wrapf(err, "failed to compute changes of %s", sr.ID())
+	}
+	return digest.NewDigest(digest.SHA256, h), nil
+}
+
+// fileDigest returns the digest of the metadata and the contents of the file
+// p without its modification time
+func fileDigest(p string, fi os.FileInfo) (digest.Digest, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "mode:%o\n", fi.Mode())
+	if fi.Mode()&os.ModeSymlink != 0 {
+		link, err := os.Readlink(p)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "link:%s\n", link)
+	}
+	if err := writeFileAttrs(h, p, fi); err != nil {
+		return "", errors.Wrapf(err, "failed to read attributes of %s", p)
+	}
+	if fi.Mode().IsRegular() {
+		f, err := os.Open(p)
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+		if _, err := io.Copy(h, f); err != nil {
+			return "", errors.Wrapf(err, "failed to read %s", p)
+		}
+	}
+	return digest.NewDigest(digest.SHA256, h), nil
+}
+
+// findDuplicate returns a reference to an existing record with the same
+// parent and the changes dgst. The files of the record are compared to the
+// files of sr so that a stale or colliding digest isn't reused. It returns
+// nil if there is no such record.
+func (sr *mutableRef) findDuplicate(ctx context.Context, dgst digest.Digest) (ImmutableRef, error) {
+	var parentID string
+	if sr.parent != nil {
+		parentID = sr.parent.ID()
+	}
+	items, err := sr.cm.md.Search(diffIndex(parentID, dgst))
+	if err != nil {
+		return nil, err
+	}
+	for _, si := range items {
+		ref := sr.cm.immutable(ctx, si.ID())
+		if ref == nil {
+			continue
+		}
+		same, err := sr.sameFiles(ctx, ref)
+		if err == nil && same {
+			return ref, nil
+		}
+		ref.Release(context.TODO())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// immutable returns a new reference to the committed record id, or nil if
+// there is no such record
+func (cm *cacheManager) immutable(ctx context.Context, id string) ImmutableRef {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	rec, err := cm.load(ctx, id)
+	if err != nil || rec.mutable {
+		return nil
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return rec.ref()
+}
+
+// sameFiles checks if the files of sr and ref only differ in their
+// modification times
+func (sr *mutableRef) sameFiles(ctx context.Context, ref ImmutableRef) (bool, error) {
+	m, err := ref.Mount(ctx, true)
+	if err != nil {
+		return false, err
+	}
+	lm := snapshot.LocalMounter(m)
+	refDir, err := lm.Mount()
+	if err != nil {
+		return false, err
+	}
+	defer lm.Unmount()
+
+	m, err = sr.Mount(ctx, true)
+	if err != nil {
+		return false, err
+	}
+	lm2 := snapshot.LocalMounter(m)
+	dir, err := lm2.Mount()
+	if err != nil {
+		return false, err
+	}
+	defer lm2.Unmount()
+
+	same := true
+	if err := fs.Changes(ctx, refDir, dir, func(kind fs.ChangeKind, p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if kind == fs.ChangeKindModify {
+			dgst, err := fileDigest(filepath.Join(dir, p), fi)
+			if err != nil {
+				return err
+			}
+			rfi, err := os.Lstat(filepath.Join(refDir, p))
+			if err != nil {
+				return err
+			}
+			rdgst, err := fileDigest(filepath.Join(refDir, p), rfi)
+			if err != nil {
+				return err
+			}
+			if dgst == rdgst {
+				return nil
+			}
+		}
+		same = false
+		return errDifferentFiles
+	}); err != nil && err != errDifferentFiles {
+		return false, errors.Wrapf(err, "failed to compare %s to %s", sr.ID(), ref.ID())
+	}
+	return same, nil
+}
+
+// commitDuplicate removes sr and returns ref, the existing record with the
+// same changes, instead. Hold the manager and sr locks before calling.
+func (sr *mutableRef) commitDuplicate(ctx context.Context, ref ImmutableRef) (ImmutableRef, error) {
+	ir := ref.(*immutableRef)
+	ir.mu.Lock()
+	if getCachePolicy(sr.md) == cachePolicyRetain && getCachePolicy(ir.md) != cachePolicyRetain {
+		err := queueCachePolicy(ir.md, cachePolicyRetain)
+		if err == nil {
+			err = ir.md.Commit()
+		}
+		if err != nil {
+			ir.release(ctx)
+			ir.mu.Unlock()
+			return nil, err
+		}
+	}
+	ir.mu.Unlock()
+
+	delete(sr.refs, sr)
+	if sr.parent != nil {
+		if err := sr.parent.(*immutableRef).release(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if err := sr.remove(ctx, true); err != nil {
+		return nil, err
+	}
+	metricDedupCommits.Inc()
+	return ref, nil
+}
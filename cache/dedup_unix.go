@@ -0,0 +1,32 @@
+// +build !windows
+
+package cache
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"syscall"
+
+	"github.com/stevvooe/continuity/sysx"
+)
+
+func writeFileAttrs(w io.Writer, p string, fi os.FileInfo) error {
+	if s, ok := fi.Sys().(*syscall.Stat_t); ok {
+		fmt.Fprintf(w, "uid:%d gid:%d rdev:%d\n", s.Uid, s.Gid, s.Rdev)
+	}
+	attrs, err := sysx.LListxattr(p)
+	if err != nil {
+		return err
+	}
+	sort.Strings(attrs)
+	for _, attr := range attrs {
+		v, err := sysx.LGetxattr(p, attr)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "xattr:%s=%x\n", attr, v)
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+// +build windows
+
+package cache
+
+import (
+	"io"
+	"os"
+)
+
+func writeFileAttrs(w io.Writer, p string, fi os.FileInfo) error {
+	return nil
+}
@@ -2,14 +2,30 @@ package cache
 
 import (
 	"context"
-	"errors"
+	"sort"
+	"strings"
 	"time"
+
+	"github.com/moby/buildkit/client"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
 // GCPolicy defines policy for garbage collection
 type GCPolicy struct {
-	MaxSize         uint64
+	// MaxSize is the number of bytes that can be used by the unreferenced
+	// records before the least recently used ones are removed
+	MaxSize uint64
+	// MaxKeepDuration is the time after the last use that a record is kept for
 	MaxKeepDuration time.Duration
+	// Filter limits the records that can be removed, see client.PruneInfo
+	Filter []string
+	// Interval runs the garbage collection periodically if set
+	Interval time.Duration
+}
+
+func (p GCPolicy) empty() bool {
+	return p.MaxSize == 0 && p.MaxKeepDuration == 0 && len(p.Filter) == 0
 }
 
 // // CachePolicy defines policy for keeping a resource in cache
@@ -22,10 +38,265 @@ type GCPolicy struct {
 // 	return CachePolicy{Priority: 10, LastUsed: time.Now()}
 // }
 
-func (cm *cacheManager) Prune(ctx context.Context) (map[string]int64, error) {
-	return nil, errors.New("Prune not implemented")
+// Prune removes the records that are not used by any reference and match
+// the options. Every removed record is sent to ch if it is not nil.
+func (cm *cacheManager) Prune(ctx context.Context, ch chan client.UsageInfo, opt client.PruneInfo) error {
+	filter, err := parsePruneFilter(opt.Filter)
+	if err != nil {
+		return err
+	}
+
+	cm.gcMu.Lock()
+	defer cm.gcMu.Unlock()
+
+	du, err := cm.DiskUsage(ctx, client.DiskUsageInfo{})
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	candidates := map[string]*client.UsageInfo{}
+	now := time.Now()
+	for _, d := range du {
+		total += d.Size
+		if d.InUse || !filter.match(d, now) {
+			continue
+		}
+		if opt.KeepDuration > 0 && now.Sub(lastUsedAt(d)) < opt.KeepDuration {
+			continue
+		}
+		candidates[d.ID] = d
+	}
+
+	// a parent can not be removed before all of its children
+	for {
+		n := len(candidates)
+		for _, d := range du {
+			if _, ok := candidates[d.ID]; !ok && d.Parent != "" {
+				delete(candidates, d.Parent)
+			}
+		}
+		if len(candidates) == n {
+			break
+		}
+	}
+
+	sorted := make([]*client.UsageInfo, 0, len(candidates))
+	for _, d := range candidates {
+		sorted = append(sorted, d)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return lastUsedAt(sorted[i]).Before(lastUsedAt(sorted[j]))
+	})
+	if opt.KeepBytes > 0 {
+		i := 0
+		for ; i < len(sorted) && total > opt.KeepBytes; i++ {
+			total -= sorted[i].Size
+		}
+		sorted = sorted[:i]
+	}
+
+	return cm.prune(ctx, ch, sorted)
 }
 
+// GC removes the records selected by the GCPolicy of the manager
 func (cm *cacheManager) GC(ctx context.Context) error {
-	return errors.New("GC not implemented")
+	if cm.GCPolicy.empty() {
+		return nil
+	}
+	ch := make(chan client.UsageInfo)
+	done := make(chan struct{})
+	var n int
+	var size int64
+	go func() {
+		defer close(done)
+		for r := range ch {
+			n++
+			size += r.Size
+		}
+	}()
+	err := cm.Prune(ctx, ch, client.PruneInfo{
+		Filter:       cm.GCPolicy.Filter,
+		KeepDuration: cm.GCPolicy.MaxKeepDuration,
+		KeepBytes:    int64(cm.GCPolicy.MaxSize),
+	})
+	close(ch)
+	<-done
+	if n > 0 {
+		logrus.Debugf("gc removed %d records, %d bytes", n, size)
+	}
+	metricGCRemoved.Add(float64(n))
+	metricGCReclaimed.Add(float64(size))
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	metricGCRuns.Inc(result)
+	return err
+}
+
+func (cm *cacheManager) scheduleGC(ctx context.Context, interval time.Duration) {
+	defer close(cm.gcDone)
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := cm.GC(ctx); err != nil {
+				logrus.Errorf("gc failed: %+v", err)
+			}
+		}
+	}
+}
+
+// prune removes the records in the order they are passed and sends every
+// removed one to ch as soon as it is removed. The manager lock is released
+// before the record is sent, so that the reader of ch can use the manager.
+// Records that got new references after DiskUsage was called are skipped.
+// Children release their parents when removed so the records are retried
+// until none can be removed anymore.
+func (cm *cacheManager) prune(ctx context.Context, ch chan client.UsageInfo, infos []*client.UsageInfo) error {
+	for {
+		var retry []*client.UsageInfo
+		for _, d := range infos {
+			removed, ok, err := cm.removeRecord(ctx, d.ID)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			if !removed {
+				retry = append(retry, d)
+				continue
+			}
+			if ch != nil {
+				select {
+				case ch <- *d:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+		if len(retry) == len(infos) {
+			return nil
+		}
+		infos = retry
+	}
+}
+
+// removeRecord removes the record id if it is not used. ok is false if the
+// record doesn't exist anymore.
+func (cm *cacheManager) removeRecord(ctx context.Context, id string) (removed, ok bool, err error) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	cr, ok := cm.records[id]
+	if !ok {
+		return false, false, nil
+	}
+	removed, err = cm.pruneRecord(ctx, cr)
+	return removed, true, err
+}
+
+// hold manager lock before calling
+func (cm *cacheManager) pruneRecord(ctx context.Context, cr *cacheRecord) (bool, error) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	if len(cr.refs) > 0 || cr.equalMutable != nil {
+		return false, nil
+	}
+	if cr.equalImmutable != nil {
+		if len(cr.equalImmutable.refs) > 0 {
+			return false, nil
+		}
+		if err := cr.equalImmutable.remove(ctx, false); err != nil {
+			return false, err
+		}
+		cr.equalImmutable = nil
+	}
+	if cr.parent != nil {
+		parent := cr.parent.(*immutableRef)
+		parent.mu.Lock()
+		err := parent.release(ctx)
+		parent.mu.Unlock()
+		if err != nil {
+			return false, err
+		}
+	}
+	if err := cr.remove(ctx, true); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func lastUsedAt(d *client.UsageInfo) time.Time {
+	if d.LastUsedAt != nil {
+		return *d.LastUsedAt
+	}
+	return d.CreatedAt
+}
+
+type pruneFilter struct {
+	id          []string
+	description []string
+	typ         string
+	unusedFor   time.Duration
+}
+
+func parsePruneFilter(filters []string) (*pruneFilter, error) {
+	f := &pruneFilter{}
+	for _, v := range filters {
+		parts := strings.SplitN(v, "=", 2)
+		if len(parts) == 1 {
+			f.id = append(f.id, v)
+			continue
+		}
+		switch parts[0] {
+		case "id":
+			f.id = append(f.id, parts[1])
+		case "description":
+			f.description = append(f.description, parts[1])
+		case "type":
+			if parts[1] != "mutable" && parts[1] != "immutable" {
+				return nil, errors.Errorf("invalid type filter %s", parts[1])
+			}
+			f.typ = parts[1]
+		case "unused-for":
+			d, err := time.ParseDuration(parts[1])
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid unused-for filter %s", parts[1])
+			}
+			f.unusedFor = d
+		default:
+			return nil, errors.Errorf("invalid prune filter %s", v)
+		}
+	}
+	return f, nil
+}
+
+func (f *pruneFilter) match(d *client.UsageInfo, now time.Time) bool {
+	if now.Sub(lastUsedAt(d)) < f.unusedFor {
+		return false
+	}
+	for _, id := range f.id {
+		if !strings.HasPrefix(d.ID, id) {
+			return false
+		}
+	}
+	for _, descr := range f.description {
+		if !strings.Contains(d.Description, descr) {
+			return false
+		}
+	}
+	switch f.typ {
+	case "mutable":
+		return d.Mutable
+	case "immutable":
+		return !d.Mutable
+	}
+	return true
 }
@@ -56,6 +56,13 @@ func (ls *LocalStore) Lookup(ctx context.Context, key digest.Digest) (interface{
 			}
 			r, err := ls.Cache.Get(ctx, id)
 			if err != nil {
+				if cache.IsNotFound(err) {
+					// snapshot is gone, make sure the key doesn't match it again
+					if err := ls.clear(s, key); err != nil {
+						logrus.Warnf("failed to clear stale cache key %s: %v", key, err)
+					}
+					continue
+				}
 				logrus.Warnf("failed to get cached snapshot %s: %v", id, err)
 				continue
 			}
@@ -65,6 +72,12 @@ func (ls *LocalStore) Lookup(ctx context.Context, key digest.Digest) (interface{
 	return nil, nil
 }
 
+func (ls *LocalStore) clear(si *metadata.StorageItem, key digest.Digest) error {
+	return si.Update(func(b *bolt.Bucket) error {
+		return si.SetValue(b, index(key.String()), nil)
+	})
+}
+
 func (ls *LocalStore) SetContentMapping(contentKey, regularKey digest.Digest) error {
 	db := ls.MetadataStore.DB()
 	return db.Update(func(tx *bolt.Tx) error {
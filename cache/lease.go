@@ -0,0 +1,169 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/boltdb/bolt"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// leaseBucket stores the snapshots created under every lease. The leases are
+// written before their snapshots are created so that the snapshots of a
+// daemon that crashed can be found when it restarts.
+const leaseBucket = "_leases"
+
+const (
+	leaseActive = "active"
+	leaseView   = "view"
+)
+
+type leaseKey struct{}
+
+type leaseFuncKey struct{}
+
+// Leaser covers the snapshots created by a job with a lease
+type Leaser interface {
+	// WithLease returns a context that adds the snapshots created with it to
+	// the lease id. The lease is kept until the returned function is called.
+	// The leases that are still held when the daemon stops are collected
+	// when it restarts: their snapshots are removed unless they store cache
+	// records that can be reused.
+	WithLease(ctx context.Context, id string) (context.Context, func() error, error)
+}
+
+func (cm *cacheManager) WithLease(ctx context.Context, id string) (context.Context, func() error, error) {
+	if err := cm.md.DB().Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(leaseBucket))
+		if err != nil {
+			return err
+		}
+		if b.Bucket([]byte(id)) != nil {
+			return errors.Errorf("lease %s exists", id)
+		}
+		_, err = b.CreateBucket([]byte(id))
+		return err
+	}); err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to create lease %s", id)
+	}
+	done := func() error {
+		return cm.md.DB().Update(func(tx *bolt.Tx) error {
+			b := tx.Bucket([]byte(leaseBucket))
+			if b == nil || b.Bucket([]byte(id)) == nil {
+				return nil
+			}
+			return b.DeleteBucket([]byte(id))
+		})
+	}
+	return context.WithValue(ctx, leaseKey{}, id), done, nil
+}
+
+// WithLeaseFunc returns a context that adds the snapshots created with it to
+// the lease returned by fn, for the contexts shared by jobs that hold
+// different leases. The snapshots are not leased if fn returns an empty id
+// or a lease that has been released.
+func WithLeaseFunc(ctx context.Context, fn func() string) context.Context {
+	return context.WithValue(ctx, leaseFuncKey{}, fn)
+}
+
+// LeaseID returns the lease of a context returned by WithLease
+func LeaseID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(leaseKey{}).(string)
+	return id, ok
+}
+
+// addLease adds the snapshot of the kind to the lease of ctx
+func (cm *cacheManager) addLease(ctx context.Context, snapshotID, kind string) error {
+	id, ok := LeaseID(ctx)
+	if !ok {
+		fn, ok := ctx.Value(leaseFuncKey{}).(func() string)
+		if !ok {
+			return nil
+		}
+		if id = fn(); id == "" {
+			return nil
+		}
+	}
+	return cm.md.DB().Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(leaseBucket))
+		if b != nil {
+			b = b.Bucket([]byte(id))
+		}
+		if b == nil {
+			if !ok {
+				// the job of the lease has completed since fn returned it
+				return nil
+			}
+			return errors.Errorf("lease %s not found", id)
+		}
+		return b.Put([]byte(snapshotID), []byte(kind))
+	})
+}
+
+// collectLeases removes the snapshots of the leases that were held when the
+// daemon stopped. The views and the mutable snapshots are removed, except the
+// ones that are retained or store the data of a committed record.
+func (cm *cacheManager) collectLeases(ctx context.Context) error {
+	leased := map[string]string{}
+	if err := cm.md.DB().View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(leaseBucket))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, _ []byte) error {
+			lb := b.Bucket(k)
+			if lb == nil {
+				return nil
+			}
+			return lb.ForEach(func(k, v []byte) error {
+				leased[string(k)] = string(v)
+				return nil
+			})
+		})
+	}); err != nil {
+		return err
+	}
+	if len(leased) == 0 {
+		return nil
+	}
+
+	items, err := cm.md.All()
+	if err != nil {
+		return err
+	}
+	committed := map[string]struct{}{}
+	for _, si := range items {
+		if id := getEqualMutable(si); id != "" {
+			committed[id] = struct{}{}
+		}
+	}
+
+	for id, kind := range leased {
+		if kind == leaseActive {
+			if _, ok := committed[id]; ok {
+				continue
+			}
+			if si, ok := cm.md.Get(id); ok && getCachePolicy(si) == cachePolicyRetain {
+				continue
+			}
+			if err := cm.md.Clear(id); err != nil {
+				return err
+			}
+		}
+		if _, err := cm.Snapshotter.Stat(ctx, id); err != nil {
+			continue
+		}
+		if err := cm.Snapshotter.Remove(ctx, id); err != nil {
+			logrus.Warnf("failed to remove leased snapshot %s: %v", id, err)
+			continue
+		}
+		logrus.Debugf("removed leased snapshot %s", id)
+	}
+
+	return cm.md.DB().Update(func(tx *bolt.Tx) error {
+		if tx.Bucket([]byte(leaseBucket)) == nil {
+			return nil
+		}
+		return tx.DeleteBucket([]byte(leaseBucket))
+	})
+}
@@ -26,6 +26,11 @@ type ManagerOpt struct {
 	Snapshotter   snapshot.Snapshotter
 	GCPolicy      GCPolicy
 	MetadataStore *metadata.Store
+	// DedupCommits compares the changes of a committed ref to the other
+	// records with the same parent. If the changes are identical apart from
+	// the modification times the existing record is returned and the new
+	// snapshot is removed.
+	DedupCommits bool
 }
 
 type Accessor interface {
@@ -36,13 +41,14 @@ type Accessor interface {
 
 type Controller interface {
 	DiskUsage(ctx context.Context, info client.DiskUsageInfo) ([]*client.UsageInfo, error)
-	Prune(ctx context.Context) (map[string]int64, error)
+	Prune(ctx context.Context, ch chan client.UsageInfo, info client.PruneInfo) error
 	GC(ctx context.Context) error
 }
 
 type Manager interface {
 	Accessor
 	Controller
+	Leaser
 	Close() error
 }
 
@@ -51,6 +57,10 @@ type cacheManager struct {
 	mu      sync.Mutex
 	ManagerOpt
 	md *metadata.Store
+
+	gcMu     sync.Mutex
+	gcCancel func()
+	gcDone   chan struct{}
 }
 
 func NewManager(opt ManagerOpt) (Manager, error) {
@@ -64,12 +74,21 @@ func NewManager(opt ManagerOpt) (Manager, error) {
 		return nil, err
 	}
 
-	// cm.scheduleGC(5 * time.Minute)
+	if opt.GCPolicy.Interval > 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		cm.gcCancel = cancel
+		cm.gcDone = make(chan struct{})
+		go cm.scheduleGC(ctx, opt.GCPolicy.Interval)
+	}
 
 	return cm, nil
 }
 
 func (cm *cacheManager) init(ctx context.Context) error {
+	if err := cm.collectLeases(ctx); err != nil {
+		return errors.Wrap(err, "failed to collect leases")
+	}
+
 	items, err := cm.md.All()
 	if err != nil {
 		return err
@@ -85,14 +104,28 @@ func (cm *cacheManager) init(ctx context.Context) error {
 }
 
 func (cm *cacheManager) Close() error {
-	// TODO: allocate internal context and cancel it here
+	if cm.gcCancel != nil {
+		cm.gcCancel()
+		<-cm.gcDone
+	}
 	return cm.md.Close()
 }
 
 func (cm *cacheManager) Get(ctx context.Context, id string, opts ...RefOption) (ImmutableRef, error) {
 	cm.mu.Lock()
 	defer cm.mu.Unlock()
-	return cm.get(ctx, id, opts...)
+	ref, err := cm.get(ctx, id, opts...)
+	if err != nil {
+		return nil, err
+	}
+	sr := ref.(*immutableRef)
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	if err := updateLastUsed(sr.md); err != nil {
+		sr.release(ctx)
+		return nil, err
+	}
+	return sr, nil
 }
 
 func (cm *cacheManager) get(ctx context.Context, id string, opts ...RefOption) (ImmutableRef, error) {
@@ -178,6 +211,9 @@ func (cm *cacheManager) New(ctx context.Context, s ImmutableRef, opts ...RefOpti
 	var parentID string
 	if s != nil {
 		var err error
+		if s, err = Resolve(ctx, s); err != nil {
+			return nil, err
+		}
 		parent, err = cm.Get(ctx, s.ID())
 		if err != nil {
 			return nil, err
@@ -188,6 +224,13 @@ func (cm *cacheManager) New(ctx context.Context, s ImmutableRef, opts ...RefOpti
 		parentID = parent.ID()
 	}
 
+	if err := cm.addLease(ctx, id, leaseActive); err != nil {
+		if parent != nil {
+			parent.Release(ctx)
+		}
+		return nil, errors.Wrapf(err, "failed to lease %s", id)
+	}
+
 	if _, err := cm.Snapshotter.Prepare(ctx, id, parentID); err != nil {
 		if parent != nil {
 			parent.Release(ctx)
@@ -249,6 +292,10 @@ func (cm *cacheManager) GetMutable(ctx context.Context, id string) (MutableRef,
 		rec.equalImmutable = nil
 	}
 
+	if err := updateLastUsed(rec.md); err != nil {
+		return nil, err
+	}
+
 	return rec.mref(), nil
 }
 
@@ -329,6 +376,7 @@ func (cm *cacheManager) DiskUsage(ctx context.Context, opt client.DiskUsageInfo)
 			Description: cr.description,
 			LastUsedAt:  cr.lastUsedAt,
 			UsageCount:  cr.usageCount,
+			Refs:        cr.refs,
 		}
 		du = append(du, c)
 	}
@@ -339,17 +387,27 @@ func (cm *cacheManager) DiskUsage(ctx context.Context, opt client.DiskUsageInfo)
 		if d.Size == sizeUnknown {
 			func(d *client.UsageInfo) {
 				eg.Go(func() error {
-					ref, err := cm.Get(ctx, d.ID)
-					if err != nil {
+					// taking a ref would count as a use of the record
+					cm.mu.Lock()
+					cr, ok := cm.records[d.ID]
+					cm.mu.Unlock()
+					if !ok {
 						d.Size = 0
 						return nil
 					}
-					s, err := ref.Size(ctx)
+					s, err := cr.Size(ctx)
 					if err != nil {
+						cm.mu.Lock()
+						_, ok := cm.records[d.ID]
+						cm.mu.Unlock()
+						if !ok {
+							d.Size = 0
+							return nil
+						}
 						return err
 					}
 					d.Size = s
-					return ref.Release(context.TODO())
+					return nil
 				})
 			}(d)
 		}
@@ -366,6 +424,10 @@ func IsLocked(err error) bool {
 	return errors.Cause(err) == errLocked
 }
 
+func IsNotFound(err error) bool {
+	return errors.Cause(err) == errNotFound
+}
+
 type RefOption func(withMetadata) error
 
 type cachePolicy int
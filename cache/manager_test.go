@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/containerd/containerd/namespaces"
 	"github.com/containerd/containerd/snapshot/naive"
@@ -250,21 +251,375 @@ func TestLazyCommit(t *testing.T) {
 	require.Equal(t, errNotFound, errors.Cause(err))
 }
 
-func getCacheManager(t *testing.T, tmpdir string) Manager {
+func TestDiskUsage(t *testing.T) {
+	ctx := namespaces.WithNamespace(context.Background(), "buildkit-test")
+
+	tmpdir, err := ioutil.TempDir("", "cachemanager")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	cm := getCacheManager(t, tmpdir)
+
+	active, err := cm.New(ctx, nil, WithDescription("base"))
+	require.NoError(t, err)
+
+	snap, err := active.Commit(ctx)
+	require.NoError(t, err)
+
+	err = snap.Finalize(ctx)
+	require.NoError(t, err)
+
+	active2, err := cm.New(ctx, snap, CachePolicyRetain, WithDescription("child"))
+	require.NoError(t, err)
+
+	err = snap.Release(ctx)
+	require.NoError(t, err)
+
+	du, err := cm.DiskUsage(ctx, client.DiskUsageInfo{})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(du))
+
+	records := map[string]*client.UsageInfo{}
+	for _, r := range du {
+		records[r.ID] = r
+	}
+
+	base, ok := records[snap.ID()]
+	require.True(t, ok)
+	require.Equal(t, "base", base.Description)
+	require.False(t, base.Mutable)
+	require.True(t, base.InUse)
+	require.Equal(t, 1, base.Refs)
+	require.Equal(t, "", base.Parent)
+	// used when the child was created
+	require.Equal(t, 1, base.UsageCount)
+	require.NotNil(t, base.LastUsedAt)
+
+	child, ok := records[active2.ID()]
+	require.True(t, ok)
+	require.Equal(t, "child", child.Description)
+	require.True(t, child.Mutable)
+	require.True(t, child.InUse)
+	require.Equal(t, 1, child.Refs)
+	require.Equal(t, snap.ID(), child.Parent)
+	require.Equal(t, 0, child.UsageCount)
+
+	err = active2.Release(ctx)
+	require.NoError(t, err)
+
+	du, err = cm.DiskUsage(ctx, client.DiskUsageInfo{Filter: active2.ID()})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(du))
+	require.False(t, du[0].InUse)
+	require.Equal(t, 0, du[0].Refs)
+	require.Equal(t, 0, du[0].UsageCount)
+	require.NotNil(t, du[0].LastUsedAt)
+
+	active2, err = cm.GetMutable(ctx, active2.ID())
+	require.NoError(t, err)
+
+	err = active2.Release(ctx)
+	require.NoError(t, err)
+
+	// checking the disk usage is not a use of the records
+	for i := 0; i < 2; i++ {
+		du, err = cm.DiskUsage(ctx, client.DiskUsageInfo{})
+		require.NoError(t, err)
+		for _, r := range du {
+			require.Equal(t, 1, r.UsageCount)
+		}
+	}
+
+	err = cm.Close()
+	require.NoError(t, err)
+}
+
+func TestPrune(t *testing.T) {
+	ctx := namespaces.WithNamespace(context.Background(), "buildkit-test")
+
+	tmpdir, err := ioutil.TempDir("", "cachemanager")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	cm := getCacheManager(t, tmpdir)
+
+	active, err := cm.New(ctx, nil, CachePolicyRetain, WithDescription("foo"))
+	require.NoError(t, err)
+
+	snap, err := active.Commit(ctx)
+	require.NoError(t, err)
+
+	err = snap.Finalize(ctx)
+	require.NoError(t, err)
+
+	active2, err := cm.New(ctx, snap, CachePolicyRetain, WithDescription("bar"))
+	require.NoError(t, err)
+
+	err = snap.Release(ctx)
+	require.NoError(t, err)
+
+	checkDiskUsage(t, ctx, cm, 2, 0)
+
+	// records used by the active ref are not removed
+	removed, err := pruneRecords(ctx, cm, client.PruneInfo{})
+	require.NoError(t, err)
+	require.Equal(t, 0, len(removed))
+
+	err = active2.Release(ctx)
+	require.NoError(t, err)
+
+	checkDiskUsage(t, ctx, cm, 0, 2)
+
+	err = cm.Prune(ctx, nil, client.PruneInfo{Filter: []string{"foo=bar"}})
+	require.Error(t, err)
+
+	removed, err = pruneRecords(ctx, cm, client.PruneInfo{KeepDuration: time.Hour})
+	require.NoError(t, err)
+	require.Equal(t, 0, len(removed))
+
+	removed, err = pruneRecords(ctx, cm, client.PruneInfo{Filter: []string{"unused-for=1h"}})
+	require.NoError(t, err)
+	require.Equal(t, 0, len(removed))
+
+	// parent can't be removed while the child exists
+	removed, err = pruneRecords(ctx, cm, client.PruneInfo{Filter: []string{"description=foo"}})
+	require.NoError(t, err)
+	require.Equal(t, 0, len(removed))
+
+	removed, err = pruneRecords(ctx, cm, client.PruneInfo{Filter: []string{"type=mutable"}})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(removed))
+	require.Equal(t, active2.ID(), removed[0].ID)
+	require.Equal(t, "bar", removed[0].Description)
+
+	checkDiskUsage(t, ctx, cm, 0, 1)
+
+	removed, err = pruneRecords(ctx, cm, client.PruneInfo{})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(removed))
+	require.Equal(t, snap.ID(), removed[0].ID)
+
+	checkDiskUsage(t, ctx, cm, 0, 0)
+
+	_, err = cm.Get(ctx, snap.ID())
+	require.Error(t, err)
+	require.Equal(t, errNotFound, errors.Cause(err))
+
+	err = cm.Close()
+	require.NoError(t, err)
+}
+
+func TestDedupCommits(t *testing.T) {
+	ctx := namespaces.WithNamespace(context.Background(), "buildkit-test")
+
+	tmpdir, err := ioutil.TempDir("", "cachemanager")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	cm := getCacheManager(t, tmpdir, func(opt *ManagerOpt) {
+		opt.DedupCommits = true
+	})
+	defer cm.Close()
+
+	write := func(parent ImmutableRef, fn func(dir string) error) ImmutableRef {
+		active, err := cm.New(ctx, parent, CachePolicyRetain)
+		require.NoError(t, err)
+		m, err := active.Mount(ctx, false)
+		require.NoError(t, err)
+		lm := snapshot.LocalMounter(m)
+		dir, err := lm.Mount()
+		require.NoError(t, err)
+		err = fn(dir)
+		lm.Unmount()
+		require.NoError(t, err)
+		snap, err := active.Commit(ctx)
+		require.NoError(t, err)
+		return snap
+	}
+	writeFile := func(name, data string) func(string) error {
+		return func(dir string) error {
+			return ioutil.WriteFile(filepath.Join(dir, name), []byte(data), 0644)
+		}
+	}
+
+	base := write(nil, writeFile("foo", "foo"))
+	defer base.Release(ctx)
+
+	snap1 := write(base, writeFile("bar", "data0"))
+	defer snap1.Release(ctx)
+	checkDiskUsage(t, ctx, cm, 2, 0)
+
+	// identical changes with a different modification time
+	time.Sleep(10 * time.Millisecond)
+	snap2 := write(base, writeFile("bar", "data0"))
+	defer snap2.Release(ctx)
+	require.Equal(t, snap1.ID(), snap2.ID())
+	checkDiskUsage(t, ctx, cm, 2, 0)
+
+	snap3 := write(base, writeFile("bar", "data1"))
+	defer snap3.Release(ctx)
+	require.NotEqual(t, snap1.ID(), snap3.ID())
+
+	// touching a file of the parent is not a change
+	snap4 := write(base, func(dir string) error {
+		now := time.Now().Add(time.Hour)
+		return os.Chtimes(filepath.Join(dir, "foo"), now, now)
+	})
+	defer snap4.Release(ctx)
+	snap5 := write(base, func(string) error { return nil })
+	defer snap5.Release(ctx)
+	require.Equal(t, snap4.ID(), snap5.ID())
+
+	// the same changes on another parent are not identical
+	snap6 := write(snap3, writeFile("bar", "data0"))
+	defer snap6.Release(ctx)
+	require.NotEqual(t, snap1.ID(), snap6.ID())
+	checkDiskUsage(t, ctx, cm, 5, 0)
+
+	// a record with the same digest but other files is not reused
+	snap7 := write(base, writeFile("bar", "data2"))
+	defer snap7.Release(ctx)
+	active, err := cm.New(ctx, base, CachePolicyRetain)
+	require.NoError(t, err)
+	m, err := active.Mount(ctx, false)
+	require.NoError(t, err)
+	lm := snapshot.LocalMounter(m)
+	dir, err := lm.Mount()
+	require.NoError(t, err)
+	err = writeFile("bar", "data3")(dir)
+	lm.Unmount()
+	require.NoError(t, err)
+	dgst, err := active.(*mutableRef).diffDigest(ctx)
+	require.NoError(t, err)
+	require.NoError(t, setDiffDigest(snap7.Metadata(), base.ID(), dgst))
+	require.NoError(t, snap7.Metadata().Commit())
+	snap8, err := active.Commit(ctx)
+	require.NoError(t, err)
+	defer snap8.Release(ctx)
+	require.NotEqual(t, snap7.ID(), snap8.ID())
+}
+
+func TestLeases(t *testing.T) {
+	ctx := namespaces.WithNamespace(context.Background(), "buildkit-test")
+
+	tmpdir, err := ioutil.TempDir("", "cachemanager")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	cm := getCacheManager(t, tmpdir)
+
+	lctx, done, err := cm.WithLease(ctx, "job1")
+	require.NoError(t, err)
+	_, _, err = cm.WithLease(ctx, "job1")
+	require.Error(t, err)
+
+	retained, err := cm.New(lctx, nil, CachePolicyRetain)
+	require.NoError(t, err)
+	snap, err := retained.Commit(lctx)
+	require.NoError(t, err)
+	_, err = snap.Mount(lctx, true)
+	require.NoError(t, err)
+	view := snap.(*immutableRef).view
+	active, err := cm.New(lctx, snap)
+	require.NoError(t, err)
+
+	// the daemon stops without releasing the lease
+	require.NoError(t, cm.Close())
+	cm = getCacheManager(t, tmpdir)
+
+	_, err = cm.(*cacheManager).Snapshotter.Stat(ctx, view)
+	require.Error(t, err)
+	snap, err = cm.Get(ctx, snap.ID())
+	require.NoError(t, err)
+	require.NoError(t, snap.Release(ctx))
+	_, err = cm.GetMutable(ctx, active.ID())
+	require.Error(t, err)
+	require.Equal(t, errNotFound, errors.Cause(err))
+	checkDiskUsage(t, ctx, cm, 0, 1)
+
+	// the released leases are not collected
+	lctx, done, err = cm.WithLease(ctx, "job1")
+	require.NoError(t, err)
+	active, err = cm.New(lctx, nil)
+	require.NoError(t, err)
+	require.NoError(t, done())
+	require.NoError(t, cm.Close())
+	cm = getCacheManager(t, tmpdir)
+
+	active, err = cm.GetMutable(ctx, active.ID())
+	require.NoError(t, err)
+	require.NoError(t, active.Release(ctx))
+	checkDiskUsage(t, ctx, cm, 0, 1)
+}
+
+func getCacheManager(t *testing.T, tmpdir string, opts ...func(*ManagerOpt)) Manager {
 	snapshotter, err := naive.NewSnapshotter(filepath.Join(tmpdir, "snapshots"))
 	require.NoError(t, err)
 
 	md, err := metadata.NewStore(filepath.Join(tmpdir, "metadata.db"))
 	require.NoError(t, err)
 
-	cm, err := NewManager(ManagerOpt{
+	opt := ManagerOpt{
 		Snapshotter:   snapshotter,
 		MetadataStore: md,
-	})
+	}
+	for _, o := range opts {
+		o(&opt)
+	}
+	cm, err := NewManager(opt)
 	require.NoError(t, err, fmt.Sprintf("error: %+v", err))
 	return cm
 }
 
+// TestPruneReaderUsesManager checks that the reader of the removed records
+// can use the manager while the prune runs
+func TestPruneReaderUsesManager(t *testing.T) {
+	ctx := namespaces.WithNamespace(context.Background(), "buildkit-test")
+
+	tmpdir, err := ioutil.TempDir("", "cachemanager")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	cm := getCacheManager(t, tmpdir)
+	for i := 0; i < 2; i++ {
+		active, err := cm.New(ctx, nil, CachePolicyRetain)
+		require.NoError(t, err)
+		require.NoError(t, active.Release(ctx))
+	}
+
+	ch := make(chan client.UsageInfo)
+	done := make(chan error, 1)
+	go func() {
+		done <- cm.Prune(ctx, ch, client.PruneInfo{})
+		close(ch)
+	}()
+	var n int
+	for range ch {
+		n++
+		_, err := cm.DiskUsage(ctx, client.DiskUsageInfo{})
+		require.NoError(t, err)
+	}
+	require.NoError(t, <-done)
+	require.Equal(t, 2, n)
+	checkDiskUsage(t, ctx, cm, 0, 0)
+}
+
+func pruneRecords(ctx context.Context, cm Manager, info client.PruneInfo) ([]client.UsageInfo, error) {
+	ch := make(chan client.UsageInfo)
+	done := make(chan struct{})
+	var removed []client.UsageInfo
+	go func() {
+		defer close(done)
+		for r := range ch {
+			removed = append(removed, r)
+		}
+	}()
+	err := cm.Prune(ctx, ch, info)
+	close(ch)
+	<-done
+	return removed, err
+}
+
 func checkDiskUsage(t *testing.T, ctx context.Context, cm Manager, inuse, unused int) {
 	du, err := cm.DiskUsage(ctx, client.DiskUsageInfo{})
 	require.NoError(t, err)
@@ -147,15 +147,13 @@ func getCreatedAt(si *metadata.StorageItem) time.Time {
 }
 
 func getLastUsed(si *metadata.StorageItem) (int, *time.Time) {
-	v := si.Get(keyUsageCount)
-	if v == nil {
-		return 0, nil
-	}
 	var usageCount int
-	if err := v.Unmarshal(&usageCount); err != nil {
-		return 0, nil
+	if v := si.Get(keyUsageCount); v != nil {
+		if err := v.Unmarshal(&usageCount); err != nil {
+			usageCount = 0
+		}
 	}
-	v = si.Get(keyLastUsedAt)
+	v := si.Get(keyLastUsedAt)
 	if v == nil {
 		return usageCount, nil
 	}
@@ -167,6 +165,7 @@ func getLastUsed(si *metadata.StorageItem) (int, *time.Time) {
 	return usageCount, &tm
 }
 
+// updateLastUsed counts a new use of the record
 func updateLastUsed(si *metadata.StorageItem) error {
 	count, _ := getLastUsed(si)
 	count++
@@ -186,3 +185,14 @@ func updateLastUsed(si *metadata.StorageItem) error {
 		return si.SetValue(b, keyLastUsedAt, v2)
 	})
 }
+
+// touchLastUsed updates the last used time without counting a new use
+func touchLastUsed(si *metadata.StorageItem) error {
+	v, err := metadata.NewValue(time.Now().UnixNano())
+	if err != nil {
+		return errors.Wrap(err, "failed to create lastUsedAt value")
+	}
+	return si.Update(func(b *bolt.Bucket) error {
+		return si.SetValue(b, keyLastUsedAt, v)
+	})
+}
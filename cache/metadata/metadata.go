@@ -72,8 +72,12 @@ func (s *Store) Probe(index string) (bool, error) {
 		search := []byte(indexKey(index, ""))
 		c := b.Cursor()
 		k, _ := c.Seek(search)
-		if k != nil && bytes.HasPrefix(k, search) {
-			exists = true
+		for ; k != nil && bytes.HasPrefix(k, search); k, _ = c.Next() {
+			// ignore indexes pointing to records that have been removed
+			if main.Bucket(bytes.TrimPrefix(k, search)) != nil {
+				exists = true
+				break
+			}
 		}
 		return nil
 	})
@@ -163,6 +167,36 @@ func (s *Store) Clear(id string) error {
 	})
 }
 
+// MoveExternal moves the external data of the item from to the item to. The
+// existing external data of to is replaced.
+func (s *Store) MoveExternal(from, to string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		external := tx.Bucket([]byte(externalBucket))
+		if external == nil {
+			return nil
+		}
+		src := external.Bucket([]byte(from))
+		if src == nil {
+			return nil
+		}
+		if external.Bucket([]byte(to)) != nil {
+			if err := external.DeleteBucket([]byte(to)); err != nil {
+				return err
+			}
+		}
+		dst, err := external.CreateBucket([]byte(to))
+		if err != nil {
+			return err
+		}
+		if err := src.ForEach(func(k, v []byte) error {
+			return dst.Put(k, v)
+		}); err != nil {
+			return err
+		}
+		return external.DeleteBucket([]byte(from))
+	})
+}
+
 func (s *Store) Update(id string, fn func(b *bolt.Bucket) error) error {
 	return s.db.Update(func(tx *bolt.Tx) error {
 		b, err := tx.CreateBucketIfNotExists([]byte(mainBucket))
@@ -336,6 +370,13 @@ func (s *StorageItem) SetValue(b *bolt.Bucket, key string, v *Value) error {
 		if err := b.Put([]byte(key), nil); err != nil {
 			return err
 		}
+		if old, ok := s.values[key]; ok && old.Index != "" {
+			if b := b.Tx().Bucket([]byte(indexBucket)); b != nil {
+				if err := b.Delete([]byte(indexKey(old.Index, s.ID()))); err != nil {
+					return err
+				}
+			}
+		}
 		delete(s.values, key)
 		return nil
 	}
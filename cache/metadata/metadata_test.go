@@ -203,4 +203,58 @@ func TestExternalData(t *testing.T) {
 	si, _ = s.Get("foo")
 	_, err = si.GetExternal("ext1")
 	require.Error(t, err)
+
+	err = si.SetExternal("ext2", []byte("data2"))
+	require.NoError(t, err)
+
+	err = s.MoveExternal("foo", "bar")
+	require.NoError(t, err)
+
+	_, err = si.GetExternal("ext2")
+	require.Error(t, err)
+
+	si, _ = s.Get("bar")
+	dt, err = si.GetExternal("ext2")
+	require.NoError(t, err)
+	require.Equal(t, "data2", string(dt))
+}
+
+func TestClearIndexedValue(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "buildkit-storage")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	dbPath := filepath.Join(tmpdir, "storage.db")
+
+	s, err := NewStore(dbPath)
+	require.NoError(t, err)
+	defer s.Close()
+
+	si, _ := s.Get("foo")
+
+	v, err := NewValue("foobar")
+	require.NoError(t, err)
+	v.Index = "tag:baz"
+
+	err = si.Update(func(b *bolt.Bucket) error {
+		return si.SetValue(b, "bar", v)
+	})
+	require.NoError(t, err)
+
+	exists, err := s.Probe("tag:baz")
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	err = si.Update(func(b *bolt.Bucket) error {
+		return si.SetValue(b, "bar", nil)
+	})
+	require.NoError(t, err)
+
+	exists, err = s.Probe("tag:baz")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	sis, err := s.Search("tag:baz")
+	require.NoError(t, err)
+	require.Equal(t, 0, len(sis))
 }
@@ -0,0 +1,18 @@
+package cache
+
+import "github.com/moby/buildkit/util/metrics"
+
+var (
+	metricGCRuns = metrics.NewCounter("buildkit_cache_gc_runs_total",
+		"Number of garbage collections of the cache.", "result")
+	metricGCReclaimed = metrics.NewCounter("buildkit_cache_gc_reclaimed_bytes_total",
+		"Number of bytes removed from the cache by the garbage collection.")
+	metricGCRemoved = metrics.NewCounter("buildkit_cache_gc_removed_records_total",
+		"Number of cache records removed by the garbage collection.")
+	metricDedupCommits = metrics.NewCounter("buildkit_cache_dedup_commits_total",
+		"Number of commits that reused an existing record with identical changes.")
+)
+
+func init() {
+	metrics.MustRegister(metricGCRuns, metricGCReclaimed, metricGCRemoved, metricDedupCommits)
+}
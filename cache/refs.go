@@ -7,7 +7,9 @@ import (
 	"github.com/moby/buildkit/cache/metadata"
 	"github.com/moby/buildkit/identity"
 	"github.com/moby/buildkit/util/flightcontrol"
+	digest "github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	"golang.org/x/net/context"
 )
 
@@ -35,6 +37,30 @@ type Mountable interface {
 	Mount(ctx context.Context, readonly bool) ([]mount.Mount, error)
 }
 
+// FileReader is implemented by the refs that can read a file without being
+// mounted
+type FileReader interface {
+	ReadFile(ctx context.Context, p string) ([]byte, error)
+}
+
+// LazyRef is implemented by the refs that are backed by a record of the
+// manager only once they are resolved, e.g. the images that haven't been
+// pulled. Their parent is nil until they are resolved.
+type LazyRef interface {
+	// Resolve returns the ref of the record, which is valid until the lazy
+	// ref is released
+	Resolve(ctx context.Context) (ImmutableRef, error)
+}
+
+// Resolve returns the ref of the record of ref if ref is a LazyRef, or ref
+// itself
+func Resolve(ctx context.Context, ref ImmutableRef) (ImmutableRef, error) {
+	if lr, ok := ref.(LazyRef); ok {
+		return lr.Resolve(ctx)
+	}
+	return ref, nil
+}
+
 type cacheRecord struct {
 	mu        sync.Mutex
 	mutable   bool
@@ -130,7 +156,11 @@ func (cr *cacheRecord) Mount(ctx context.Context, readonly bool) ([]mount.Mount,
 		return nil, err
 	}
 	if cr.viewMount == nil { // TODO: handle this better
-		cr.view = identity.NewID()
+		view := identity.NewID()
+		if err := cr.cm.addLease(ctx, view, leaseView); err != nil {
+			return nil, errors.Wrapf(err, "failed to lease %s", view)
+		}
+		cr.view = view
 		m, err := cr.cm.Snapshotter.View(ctx, cr.view, cr.ID())
 		if err != nil {
 			cr.view = ""
@@ -185,7 +215,7 @@ func (sr *immutableRef) release(ctx context.Context) error {
 		sr.viewMount = nil
 	}
 
-	updateLastUsed(sr.md)
+	touchLastUsed(sr.md)
 
 	delete(sr.refs, sr)
 
@@ -219,6 +249,10 @@ func (sr *cacheRecord) finalize(ctx context.Context) error {
 	if err != nil {
 		return errors.Wrapf(err, "failed to commit %s", sr.equalMutable.ID())
 	}
+	// keep the data saved for the contents, e.g. the checksums
+	if err := sr.cm.md.MoveExternal(sr.equalMutable.ID(), sr.ID()); err != nil {
+		return err
+	}
 	if err := sr.equalMutable.remove(ctx, false); err != nil {
 		return err
 	}
@@ -271,13 +305,46 @@ func (sr *mutableRef) commit(ctx context.Context) (ImmutableRef, error) {
 }
 
 func (sr *mutableRef) Commit(ctx context.Context) (ImmutableRef, error) {
+	// the changes are compared without holding the locks
+	var dgst digest.Digest
+	var dup ImmutableRef
+	if sr.cm.DedupCommits {
+		var err error
+		if dgst, err = sr.diffDigest(ctx); err != nil {
+			logrus.Debugf("not deduplicating %s: %v", sr.ID(), err)
+		} else if dup, err = sr.findDuplicate(ctx, dgst); err != nil {
+			logrus.Debugf("not deduplicating %s: %v", sr.ID(), err)
+		}
+	}
+
 	sr.cm.mu.Lock()
 	defer sr.cm.mu.Unlock()
 
 	sr.mu.Lock()
 	defer sr.mu.Unlock()
 
-	return sr.commit(ctx)
+	if dup != nil {
+		return sr.commitDuplicate(ctx, dup)
+	}
+	if dgst == "" {
+		return sr.commit(ctx)
+	}
+	ref, err := sr.commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var parentID string
+	if sr.parent != nil {
+		parentID = sr.parent.ID()
+	}
+	md := ref.Metadata()
+	if err := setDiffDigest(md, parentID, dgst); err != nil {
+		return nil, err
+	}
+	if err := md.Commit(); err != nil {
+		return nil, err
+	}
+	return ref, nil
 }
 
 func (sr *mutableRef) Release(ctx context.Context) error {
@@ -292,7 +359,7 @@ func (sr *mutableRef) Release(ctx context.Context) error {
 
 func (sr *mutableRef) release(ctx context.Context) error {
 	delete(sr.refs, sr)
-	updateLastUsed(sr.md)
+	touchLastUsed(sr.md)
 	if getCachePolicy(sr.md) != cachePolicyRetain {
 		if sr.equalImmutable != nil {
 			if getCachePolicy(sr.equalImmutable.md) == cachePolicyRetain {
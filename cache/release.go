@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"expvar"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+)
+
+var (
+	releaseAttempts = 5
+	releaseBackoff  = 100 * time.Millisecond
+
+	// leakedRefs counts the references that couldn't be released. It is
+	// published on /debug/vars of the debug address of the daemon.
+	leakedRefs = expvar.NewInt("buildkit.solver.leakedRefs")
+)
+
+// Releaser is a reference that is released when it is not used anymore
+type Releaser interface {
+	Release(context.Context) error
+}
+
+// ReleaseDetached releases ref in the background. The references are
+// released on the error paths and after the builds where the context of the
+// request may already be cancelled, so the release uses a context that is not
+// cancelled with the request.
+func ReleaseDetached(ref Releaser) {
+	go releaseWithRetry(ref)
+}
+
+// releaseWithRetry retries a failed release with an exponential backoff. The
+// reference is counted as leaked if all attempts fail.
+func releaseWithRetry(ref Releaser) error {
+	backoff := releaseBackoff
+	var err error
+	for i := 0; i < releaseAttempts; i++ {
+		if i > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+		if err = ref.Release(context.Background()); err == nil {
+			return nil
+		}
+		logrus.Debugf("failed to release %T, attempt %d/%d: %v", ref, i+1, releaseAttempts, err)
+	}
+	leakedRefs.Add(1)
+	logrus.Errorf("leaked reference %T: %v", ref, err)
+	return err
+}
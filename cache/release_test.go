@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestReleaseWithRetry(t *testing.T) {
+	defer func(backoff time.Duration) { releaseBackoff = backoff }(releaseBackoff)
+	releaseBackoff = time.Millisecond
+
+	r := &flakyRef{failures: 2}
+	require.NoError(t, releaseWithRetry(r))
+	require.Equal(t, 3, r.attempts)
+
+	leaked := leakedRefs.Value()
+	r = &flakyRef{failures: releaseAttempts}
+	require.Error(t, releaseWithRetry(r))
+	require.Equal(t, releaseAttempts, r.attempts)
+	require.Equal(t, leaked+1, leakedRefs.Value())
+}
+
+type flakyRef struct {
+	failures int
+	attempts int
+}
+
+func (r *flakyRef) Release(ctx context.Context) error {
+	r.attempts++
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if r.attempts <= r.failures {
+		return errors.Errorf("release failed")
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"fmt"
+	"os"
+
+	controlapi "github.com/moby/buildkit/api/services/control"
+	"github.com/moby/buildkit/session/filesync"
+	"github.com/pkg/errors"
+)
+
+const (
+	// CacheTypeRegistry imports and exports the cache from the reference of
+	// the "ref" attribute
+	CacheTypeRegistry = "registry"
+	// CacheTypeLocal imports the cache from the OCI image layout directory
+	// of the "src" attribute and exports it to the directory of the "dest"
+	// attribute
+	CacheTypeLocal = "local"
+
+	cacheLocalSrc  = "src"
+	cacheLocalDest = "dest"
+	// cacheLocalName and cacheTargetName are the names of the synced
+	// directories and targets of the local caches
+	cacheLocalName  = "name"
+	cacheTargetName = "target"
+)
+
+// CacheOptionsEntry is a source the cache of a build is imported from or a
+// target it is exported to. The "mode" attribute of the exports selects the
+// exported cache: "min", the default, exports the cache of the results of
+// the build and "max" the cache of all the intermediate steps.
+type CacheOptionsEntry struct {
+	Type  string
+	Attrs map[string]string
+}
+
+// cacheOptions returns the cache options of the request of opt and the
+// directories and targets of the local caches
+func cacheOptions(opt SolveOpt) (controlapi.CacheOptions, []filesync.SyncedDir, []filesync.FSSyncTarget, error) {
+	co := controlapi.CacheOptions{
+		ExportRef: opt.ExportCache,
+		ImportRef: opt.ImportCache,
+	}
+	var dirs []filesync.SyncedDir
+	var targets []filesync.FSSyncTarget
+	for i, e := range opt.CacheImports {
+		attrs := copyAttrs(e.Attrs)
+		if e.Type == CacheTypeLocal {
+			src := e.Attrs[cacheLocalSrc]
+			if src == "" {
+				return co, nil, nil, errors.Errorf("%s attribute required for local cache import", cacheLocalSrc)
+			}
+			fi, err := os.Stat(src)
+			if err != nil {
+				return co, nil, nil, errors.Wrapf(err, "could not find cache %s", src)
+			}
+			if !fi.IsDir() {
+				return co, nil, nil, errors.Errorf("cache %s not a directory", src)
+			}
+			name := fmt.Sprintf("cache-import-%d", i)
+			dirs = append(dirs, filesync.SyncedDir{Name: name, Dir: src})
+			attrs[cacheLocalName] = name
+		}
+		co.Imports = append(co.Imports, &controlapi.CacheOptionsEntry{Type: e.Type, Attrs: attrs})
+	}
+	for i, e := range opt.CacheExports {
+		attrs := copyAttrs(e.Attrs)
+		if e.Type == CacheTypeLocal {
+			dest := e.Attrs[cacheLocalDest]
+			if dest == "" {
+				return co, nil, nil, errors.Errorf("%s attribute required for local cache export", cacheLocalDest)
+			}
+			if err := os.MkdirAll(dest, 0755); err != nil {
+				return co, nil, nil, errors.Wrapf(err, "failed to create cache directory %s", dest)
+			}
+			name := fmt.Sprintf("cache-export-%d", i)
+			targets = append(targets, filesync.FSSyncTarget{Name: name, OutDir: dest})
+			attrs[cacheTargetName] = name
+		}
+		co.Exports = append(co.Exports, &controlapi.CacheOptionsEntry{Type: e.Type, Attrs: attrs})
+	}
+	return co, dirs, targets, nil
+}
+
+func copyAttrs(attrs map[string]string) map[string]string {
+	m := make(map[string]string, len(attrs)+1)
+	for k, v := range attrs {
+		m[k] = v
+	}
+	return m
+}
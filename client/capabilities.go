@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	controlapi "github.com/moby/buildkit/api/services/control"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Capabilities describes what the daemon can build and export
+type Capabilities struct {
+	// Ops are the types of the ops of the definitions, eg. "exec"
+	Ops []string
+	// MountTypes are the types of the mounts of the execs, eg. "cache"
+	MountTypes   []string
+	Exporters    []string
+	Frontends    []string
+	CacheFormats []string
+}
+
+// UnsupportedError is returned by Solve if the daemon doesn't support a
+// feature the build requires
+type UnsupportedError struct {
+	// Kind is the kind of the feature, eg. "exporter" or "mount type"
+	Kind string
+	Name string
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("daemon does not support %s %s", e.Kind, e.Name)
+}
+
+// Capabilities returns the capabilities of the daemon
+func (c *Client) Capabilities(ctx context.Context) (*Capabilities, error) {
+	resp, err := c.controlClient().Capabilities(ctx, &controlapi.CapabilitiesRequest{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get capabilities")
+	}
+	return &Capabilities{
+		Ops:          resp.Ops,
+		MountTypes:   resp.MountTypes,
+		Exporters:    resp.Exporters,
+		Frontends:    resp.Frontends,
+		CacheFormats: resp.CacheFormats,
+	}, nil
+}
+
+// checkCapabilities returns an UnsupportedError if the daemon can't run the
+// solve of defs with opt. Daemons that don't report their capabilities are
+// not checked, with a warning.
+func (c *Client) checkCapabilities(ctx context.Context, defs [][]byte, opt SolveOpt) error {
+	caps, err := c.Capabilities(ctx)
+	if err != nil {
+		if s, ok := status.FromError(errors.Cause(err)); ok && s.Code() == codes.Unimplemented {
+			logrus.Warnf("daemon doesn't report its capabilities, the build isn't checked: %v", err)
+			return nil
+		}
+		return err
+	}
+	return caps.check(defs, opt)
+}
+
+func (caps *Capabilities) check(defs [][]byte, opt SolveOpt) error {
+	if opt.Frontend != "" && !contains(caps.Frontends, opt.Frontend) {
+		return &UnsupportedError{Kind: "frontend", Name: opt.Frontend}
+	}
+	if opt.Exporter != "" && !contains(caps.Exporters, opt.Exporter) {
+		return &UnsupportedError{Kind: "exporter", Name: opt.Exporter}
+	}
+	for _, e := range opt.Exports {
+		if !contains(caps.Exporters, e.Type) {
+			return &UnsupportedError{Kind: "exporter", Name: e.Type}
+		}
+	}
+	if (opt.ExportCache != "" || opt.ImportCache != "") && !contains(caps.CacheFormats, CacheTypeRegistry) {
+		return &UnsupportedError{Kind: "cache format", Name: CacheTypeRegistry}
+	}
+	for _, e := range append(append([]CacheOptionsEntry{}, opt.CacheImports...), opt.CacheExports...) {
+		if !contains(caps.CacheFormats, e.Type) {
+			return &UnsupportedError{Kind: "cache format", Name: e.Type}
+		}
+	}
+	for _, dt := range defs {
+		var op pb.Op
+		if err := (&op).Unmarshal(dt); err != nil {
+			return errors.Wrap(err, "failed to parse llb proto op")
+		}
+		if op.Op == nil {
+			continue
+		}
+		t := pb.OpType(op.Op)
+		if t == "" {
+			t = fmt.Sprintf("%T", op.Op)
+		}
+		if !contains(caps.Ops, t) {
+			return &UnsupportedError{Kind: "op", Name: t}
+		}
+		if exec, ok := op.Op.(*pb.Op_Exec); ok {
+			for _, m := range exec.Exec.Mounts {
+				if t := pb.MountTypeName(m.MountType); !contains(caps.MountTypes, t) {
+					return &UnsupportedError{Kind: "mount type", Name: t}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func contains(l []string, s string) bool {
+	for _, v := range l {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapabilitiesCheck(t *testing.T) {
+	caps := &Capabilities{
+		Ops:          []string{"source", "exec"},
+		MountTypes:   []string{"bind"},
+		Exporters:    []string{"image"},
+		Frontends:    []string{"dockerfile.v0"},
+		CacheFormats: []string{"registry"},
+	}
+	exec := func(mt pb.MountType) []byte {
+		op := &pb.Op{Op: &pb.Op_Exec{Exec: &pb.ExecOp{
+			Meta:   &pb.Meta{Args: []string{"true"}},
+			Mounts: []*pb.Mount{{Dest: "/", MountType: mt}},
+		}}}
+		dt, err := op.Marshal()
+		require.NoError(t, err)
+		return dt
+	}
+
+	require.NoError(t, caps.check([][]byte{exec(pb.MountType_BIND)}, SolveOpt{Exporter: "image", ExportCache: "example.com/cache"}))
+	require.NoError(t, caps.check(nil, SolveOpt{Frontend: "dockerfile.v0"}))
+
+	err := caps.check([][]byte{exec(pb.MountType_CACHE)}, SolveOpt{})
+	require.Equal(t, &UnsupportedError{Kind: "mount type", Name: "cache"}, err)
+
+	merge, err := (&pb.Op{Op: &pb.Op_Merge{Merge: &pb.MergeOp{}}}).Marshal()
+	require.NoError(t, err)
+	err = caps.check([][]byte{merge}, SolveOpt{})
+	require.Equal(t, &UnsupportedError{Kind: "op", Name: "merge"}, err)
+
+	err = caps.check(nil, SolveOpt{Exports: []ExportEntry{{Type: "oci"}}})
+	require.EqualError(t, err, "daemon does not support exporter oci")
+
+	err = caps.check(nil, SolveOpt{Frontend: "gateway.v0"})
+	require.Equal(t, &UnsupportedError{Kind: "frontend", Name: "gateway.v0"}, err)
+
+	err = caps.check(nil, SolveOpt{CacheExports: []CacheOptionsEntry{{Type: CacheTypeLocal}}})
+	require.Equal(t, &UnsupportedError{Kind: "cache format", Name: "local"}, err)
+
+	caps.CacheFormats = nil
+	err = caps.check(nil, SolveOpt{ImportCache: "example.com/cache"})
+	require.Equal(t, &UnsupportedError{Kind: "cache format", Name: "registry"}, err)
+}
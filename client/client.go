@@ -1,12 +1,19 @@
 package client
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net"
+	"strings"
 	"time"
 
 	controlapi "github.com/moby/buildkit/api/services/control"
 	"github.com/moby/buildkit/util/appdefaults"
+	"github.com/moby/buildkit/util/tracing"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 type Client struct {
@@ -16,22 +23,42 @@ type Client struct {
 type ClientOpt interface{}
 
 // New returns a new buildkit client. Address can be empty for the system-default address.
+// A tcp://host:port address connects to a daemon serving TLS, see
+// WithCredentials.
 func New(address string, opts ...ClientOpt) (*Client, error) {
+	if address == "" {
+		address = appdefaults.Socket
+	}
+	dial, target := dialer, dialAddress(address)
+	if strings.HasPrefix(address, "tcp://") {
+		dial, target = dialTCP, address
+	}
 	gopts := []grpc.DialOption{
-		grpc.WithInsecure(),
 		grpc.WithTimeout(30 * time.Second),
-		grpc.WithDialer(dialer),
+		grpc.WithDialer(dial),
 		grpc.FailOnNonTempDialError(true),
+		grpc.WithUnaryInterceptor(tracing.UnaryClientInterceptor()),
+		grpc.WithStreamInterceptor(tracing.StreamClientInterceptor()),
 	}
+	var creds *withCredentials
 	for _, o := range opts {
 		if _, ok := o.(*withBlockOpt); ok {
 			gopts = append(gopts, grpc.WithBlock(), grpc.FailOnNonTempDialError(true))
 		}
+		if c, ok := o.(*withCredentials); ok {
+			creds = c
+		}
 	}
-	if address == "" {
-		address = appdefaults.Socket
+	if creds != nil {
+		opt, err := loadCredentials(creds)
+		if err != nil {
+			return nil, err
+		}
+		gopts = append(gopts, opt)
+	} else {
+		gopts = append(gopts, grpc.WithInsecure())
 	}
-	conn, err := grpc.Dial(dialAddress(address), gopts...)
+	conn, err := grpc.Dial(target, gopts...)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to dial %q . make sure buildd is running", address)
 	}
@@ -41,12 +68,68 @@ func New(address string, opts ...ClientOpt) (*Client, error) {
 	return c, nil
 }
 
+// Close closes the connection to the daemon
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
 func (c *Client) controlClient() controlapi.ControlClient {
 	return controlapi.NewControlClient(c.conn)
 }
 
+// ControlClient returns the client of the control API of the daemon
+func (c *Client) ControlClient() controlapi.ControlClient {
+	return c.controlClient()
+}
+
 type withBlockOpt struct{}
 
 func WithBlock() ClientOpt {
 	return &withBlockOpt{}
 }
+
+type withCredentials struct {
+	ServerName string
+	CACert     string
+	Cert       string
+	Key        string
+}
+
+// WithCredentials authenticates the client to the daemon with the TLS
+// certificate and key files, and verifies the certificate of the daemon for
+// serverName with the CA certificate file ca
+func WithCredentials(serverName, ca, cert, key string) ClientOpt {
+	return &withCredentials{
+		ServerName: serverName,
+		CACert:     ca,
+		Cert:       cert,
+		Key:        key,
+	}
+}
+
+func loadCredentials(opts *withCredentials) (grpc.DialOption, error) {
+	ca, err := ioutil.ReadFile(opts.CACert)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read ca certificate")
+	}
+	certPool := x509.NewCertPool()
+	if ok := certPool.AppendCertsFromPEM(ca); !ok {
+		return nil, errors.New("failed to append ca certs")
+	}
+	cfg := &tls.Config{
+		ServerName: opts.ServerName,
+		RootCAs:    certPool,
+	}
+	if opts.Cert != "" || opts.Key != "" {
+		cert, err := tls.LoadX509KeyPair(opts.Cert, opts.Key)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read certificate/key")
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return grpc.WithTransportCredentials(credentials.NewTLS(cfg)), nil
+}
+
+func dialTCP(address string, timeout time.Duration) (net.Conn, error) {
+	return net.DialTimeout("tcp", strings.TrimPrefix(address, "tcp://"), timeout)
+}
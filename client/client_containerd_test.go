@@ -71,6 +71,10 @@ func TestCallDiskUsageContainerd(t *testing.T) {
 	testCallDiskUsage(t, clientAddressContainerd)
 }
 
+func TestListWorkersContainerd(t *testing.T) {
+	testListWorkers(t, clientAddressContainerd)
+}
+
 func TestBuildMultiMountContainerd(t *testing.T) {
 	testBuildMultiMount(t, clientAddressContainerd)
 }
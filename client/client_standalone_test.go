@@ -21,6 +21,10 @@ func TestCallDiskUsageStandalone(t *testing.T) {
 	testCallDiskUsage(t, clientAddressStandalone)
 }
 
+func TestListWorkersStandalone(t *testing.T) {
+	testListWorkers(t, clientAddressStandalone)
+}
+
 func TestBuildMultiMountStandalone(t *testing.T) {
 	testBuildMultiMount(t, clientAddressStandalone)
 }
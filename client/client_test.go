@@ -87,6 +87,20 @@ func testCallDiskUsage(t *testing.T, address string) {
 	assert.Nil(t, err)
 }
 
+func testListWorkers(t *testing.T, address string) {
+	c, err := New(address)
+	assert.Nil(t, err)
+	workers, err := c.ListWorkers(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(workers))
+	assert.NotEmpty(t, workers[0].ID)
+	assert.Equal(t, 1, len(workers[0].Platforms))
+
+	workers, err = c.ListWorkers(context.TODO(), WithWorkerFilter("id!="+workers[0].ID))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(workers))
+}
+
 func testBuildMultiMount(t *testing.T, address string) {
 	requiresLinux(t)
 	t.Parallel()
@@ -106,6 +120,6 @@ func testBuildMultiMount(t *testing.T, address string) {
 	err = llb.WriteTo(dt, buf)
 	assert.Nil(t, err)
 
-	err = c.Solve(context.TODO(), buf, SolveOpt{}, nil)
+	_, err = c.Solve(context.TODO(), buf, SolveOpt{}, nil)
 	assert.Nil(t, err)
 }
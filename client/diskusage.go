@@ -18,10 +18,27 @@ type UsageInfo struct {
 	CreatedAt   time.Time
 	LastUsedAt  *time.Time
 	UsageCount  int
+	Refs        int
 	Parent      string
 	Description string
 }
 
+// fromControlUsageRecord returns the usage info of the record d of the API
+func fromControlUsageRecord(d *controlapi.UsageRecord) UsageInfo {
+	return UsageInfo{
+		ID:          d.ID,
+		Mutable:     d.Mutable,
+		InUse:       d.InUse,
+		Size:        d.Size_,
+		Parent:      d.Parent,
+		CreatedAt:   d.CreatedAt,
+		Description: d.Description,
+		UsageCount:  int(d.UsageCount),
+		LastUsedAt:  d.LastUsedAt,
+		Refs:        int(d.Refs),
+	}
+}
+
 func (c *Client) DiskUsage(ctx context.Context, opts ...DiskUsageOption) ([]*UsageInfo, error) {
 	info := &DiskUsageInfo{}
 	for _, o := range opts {
@@ -37,17 +54,8 @@ func (c *Client) DiskUsage(ctx context.Context, opts ...DiskUsageOption) ([]*Usa
 	var du []*UsageInfo
 
 	for _, d := range resp.Record {
-		du = append(du, &UsageInfo{
-			ID:          d.ID,
-			Mutable:     d.Mutable,
-			InUse:       d.InUse,
-			Size:        d.Size_,
-			Parent:      d.Parent,
-			CreatedAt:   d.CreatedAt,
-			Description: d.Description,
-			UsageCount:  int(d.UsageCount),
-			LastUsedAt:  d.LastUsedAt,
-		})
+		ui := fromControlUsageRecord(d)
+		du = append(du, &ui)
 	}
 
 	sort.Slice(du, func(i, j int) bool {
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"fmt"
+	"io"
+)
+
+// WriteDot writes the dependency graph of the vertexes of the report in the
+// dot format. The shapes show the types of the operations. The vertexes that
+// would run are red, the cached ones green and the ones that aren't needed
+// because the results depending on them are cached gray.
+func (r *DryRunReport) WriteDot(w io.Writer) error {
+	if _, err := fmt.Fprintln(w, "digraph {"); err != nil {
+		return err
+	}
+	for _, v := range r.Vertexes {
+		label := fmt.Sprintf("%s\n%s", v.Name, v.Digest)
+		if _, err := fmt.Fprintf(w, "  %q [label=%q shape=%q style=\"filled\" fillcolor=%q];\n", v.Digest, label, dotShape(v.OpType), dotColor(v)); err != nil {
+			return err
+		}
+	}
+	for _, v := range r.Vertexes {
+		for _, inp := range v.Inputs {
+			if _, err := fmt.Fprintf(w, "  %q -> %q;\n", inp, v.Digest); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
+
+func dotShape(opType string) string {
+	switch opType {
+	case "source":
+		return "ellipse"
+	case "exec":
+		return "box"
+	case "build":
+		return "box3d"
+	case "file":
+		return "note"
+	case "merge":
+		return "invtriangle"
+	case "diff":
+		return "triangle"
+	default:
+		return "plaintext"
+	}
+}
+
+func dotColor(v *DryRunVertex) string {
+	switch {
+	case v.Required:
+		return "lightcoral"
+	case v.Cached:
+		return "palegreen"
+	default:
+		return "lightgray"
+	}
+}
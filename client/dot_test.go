@@ -0,0 +1,27 @@
+package client
+
+import (
+	"bytes"
+	"testing"
+
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRunReportWriteDot(t *testing.T) {
+	src := digest.FromBytes([]byte("src"))
+	run := digest.FromBytes([]byte("run"))
+	report := &DryRunReport{
+		Vertexes: []*DryRunVertex{
+			{Digest: src, Name: `docker-image://"busybox"`, OpType: "source", Cached: true},
+			{Digest: run, Name: "make", OpType: "exec", Inputs: []digest.Digest{src}, Required: true},
+		},
+		Work: 1,
+	}
+	buf := &bytes.Buffer{}
+	require.NoError(t, report.WriteDot(buf))
+	dot := buf.String()
+	require.Contains(t, dot, `"`+src.String()+`" [label="docker-image://\"busybox\"\n`+src.String()+`" shape="ellipse" style="filled" fillcolor="palegreen"];`)
+	require.Contains(t, dot, `shape="box" style="filled" fillcolor="lightcoral"`)
+	require.Contains(t, dot, `"`+src.String()+`" -> "`+run.String()+`";`)
+}
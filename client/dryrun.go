@@ -0,0 +1,73 @@
+package client
+
+import (
+	controlapi "github.com/moby/buildkit/api/services/control"
+	digest "github.com/opencontainers/go-digest"
+)
+
+// DryRunReport describes how a definition would be solved
+type DryRunReport struct {
+	// Vertexes are sorted so that the inputs of a vertex come before it
+	Vertexes []*DryRunVertex
+	// Work is the number of vertexes that would need to run
+	Work int
+}
+
+// DryRunVertex describes the cache state of a single vertex
+type DryRunVertex struct {
+	Digest digest.Digest
+	Name   string
+	// OpType is the type of the operation of the vertex, eg. "exec"
+	OpType string
+	Inputs []digest.Digest
+	// CacheKey is the cache key of the vertex without the output index
+	CacheKey digest.Digest
+	// InputKeys are the cache keys of the inputs that CacheKey was computed
+	// from
+	InputKeys []digest.Digest
+	// ContentKeys are only computed if all the inputs are cached
+	ContentKeys []digest.Digest
+	Cached      bool
+	// Required is set if the vertex would need to run
+	Required bool
+}
+
+// SolveOption is an option of Solve
+type SolveOption func(*solveOptions)
+
+type solveOptions struct {
+	dryRun       bool
+	dryRunReport *DryRunReport
+	refFunc      func(string)
+}
+
+// WithDryRun only computes the cache keys of the definition instead of
+// running it. The result is written to report.
+func WithDryRun(report *DryRunReport) SolveOption {
+	return func(so *solveOptions) {
+		so.dryRun = true
+		so.dryRunReport = report
+	}
+}
+
+func fromControlDryRunReport(r *controlapi.DryRunReport) DryRunReport {
+	var report DryRunReport
+	if r == nil {
+		return report
+	}
+	report.Work = int(r.Work)
+	for _, v := range r.Vertexes {
+		report.Vertexes = append(report.Vertexes, &DryRunVertex{
+			Digest:      v.Digest,
+			Name:        v.Name,
+			OpType:      v.OpType,
+			Inputs:      v.Inputs,
+			CacheKey:    v.CacheKey,
+			InputKeys:   v.InputKeys,
+			ContentKeys: v.ContentKeys,
+			Cached:      v.Cached,
+			Required:    v.Required,
+		})
+	}
+	return report
+}
@@ -3,6 +3,17 @@ package client
 const (
 	ExporterImage = "image"
 	ExporterLocal = "local"
+	ExporterTar   = "tar"
+	ExporterOCI   = "oci"
 
-	exporterLocalOutputDir = "output"
+	// ExporterImageDigest is the key of the digest of the exported
+	// manifest in the response of the image and oci exporters
+	ExporterImageDigest = "containerimage.digest"
+	// ExporterImageDescriptor is the key of the JSON descriptor of the
+	// exported manifest in the response of the image and oci exporters
+	ExporterImageDescriptor = "containerimage.descriptor"
+
+	exporterLocalOutputDir   = "output"
+	exporterLocalIncremental = "incremental"
+	exporterTargetName       = "target"
 )
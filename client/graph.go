@@ -15,6 +15,68 @@ type Vertex struct {
 	Cached    bool
 	Error     string
 	Parent    digest.Digest
+	ExecError *ExecError
+	// CacheMissReason is set for vertexes that were not loaded from the cache
+	CacheMissReason *CacheMissReason
+	// Internal is set for the vertexes that are not steps of the build
+	// definition, like the import of the cache or the export of the result
+	Internal bool
+	// Group is the name of the group of related internal vertexes, like
+	// "importing cache", that UIs can show as a single step
+	Group string
+	// CacheKey is the cache key the result of a cached vertex was loaded
+	// with
+	CacheKey digest.Digest
+	// Timings are the durations of the phases of the vertex
+	Timings *VertexTimings
+}
+
+// VertexTimings are the durations of the phases of a vertex
+type VertexTimings struct {
+	// CacheLookup is spent looking up the results of the cache keys
+	CacheLookup time.Duration
+	// ContentHash is spent computing the content keys of the inputs
+	ContentHash time.Duration
+	// Exec is spent running the process of an exec
+	Exec time.Duration
+	// Commit is spent committing the snapshots of the outputs
+	Commit time.Duration
+}
+
+const (
+	// CacheMissNoMatch means that no cached result matched the cache key
+	CacheMissNoMatch = "no-match"
+	// CacheMissInputChanged means that some inputs had to be rebuilt
+	CacheMissInputChanged = "input-changed"
+	// CacheMissContentChanged means that no results were cached for the
+	// content of the inputs
+	CacheMissContentChanged = "content-changed"
+	// CacheMissPruned means that a result matched the cache key but it has
+	// been removed
+	CacheMissPruned = "pruned"
+)
+
+// CacheMissReason explains why a vertex was not loaded from the cache
+type CacheMissReason struct {
+	// Reason is one of the CacheMiss constants
+	Reason string
+	// Keys are the cache keys involved in the miss. For pruned results it's
+	// the matching key and for changed content the content keys that had no
+	// results.
+	Keys []digest.Digest
+	// Inputs are the input vertexes that were not loaded from the cache
+	Inputs []digest.Digest
+}
+
+// ExecError describes a failed process of an exec vertex
+type ExecError struct {
+	Vertex   digest.Digest
+	Args     []string
+	ExitCode uint32
+	// Signal is set if the process was killed by a signal
+	Signal int
+	// Stderr contains the end of the error output of the process
+	Stderr []byte
 }
 
 type VertexStatus struct {
@@ -28,8 +90,18 @@ type VertexStatus struct {
 	Completed *time.Time
 }
 
+const (
+	// StreamStdout is the stream of the standard output of a process
+	StreamStdout = 1
+	// StreamStderr is the stream of the error output of a process
+	StreamStderr = 2
+)
+
+// VertexLog is a chunk of the output of a process run by a vertex. The
+// timestamps order the chunks of all the streams of a solve.
 type VertexLog struct {
-	Vertex    digest.Digest
+	Vertex digest.Digest
+	// Stream is StreamStdout or StreamStderr
 	Stream    int
 	Data      []byte
 	Timestamp time.Time
@@ -39,6 +111,25 @@ type SolveStatus struct {
 	Vertexes []*Vertex
 	Statuses []*VertexStatus
 	Logs     []*VertexLog
+	// Summary is sent with the last status of a successful build
+	Summary *BuildSummary
+}
+
+// BuildSummary describes the work done by a build
+type BuildSummary struct {
+	// Vertexes is the number of vertexes of the build, the ones that were
+	// not needed are neither cached nor executed
+	Vertexes int
+	Cached   int
+	Executed int
+	// ExecTime is the sum of the times spent running the processes of the
+	// executed vertexes, from their Exec timings
+	ExecTime time.Duration
+	// TransferredBytes is the size of the pulled images and the transferred
+	// local files
+	TransferredBytes int64
+	// ExportedBytes is the size of the content sent by the exporters
+	ExportedBytes int64
 }
 
 //
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"io"
+	"time"
+
+	controlapi "github.com/moby/buildkit/api/services/control"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+// BuildInfo is the history record of a build of the daemon
+type BuildInfo struct {
+	Ref string
+	// Definition is the digest of the terminal vertex of the LLB definition.
+	// It's empty for frontend builds.
+	Definition    digest.Digest
+	Frontend      string
+	FrontendAttrs map[string]string
+	Exporter      string
+	ExporterAttrs map[string]string
+	CreatedAt     time.Time
+	// CompletedAt is nil while the build is running
+	CompletedAt *time.Time
+	Error       string
+	Vertexes    []*Vertex
+	LogSize     int64
+	// LogsTruncated is set if the daemon dropped some of the logs of the
+	// build because they were too large
+	LogsTruncated bool
+	// Summary is set for the builds that completed successfully
+	Summary *BuildSummary
+	// ExporterResponse is the response of the exporter of a successful build
+	ExporterResponse map[string]string
+}
+
+// ListBuilds returns the records of the builds kept by the daemon from the
+// oldest to the newest
+func (c *Client) ListBuilds(ctx context.Context) ([]*BuildInfo, error) {
+	return c.listBuilds(ctx, "")
+}
+
+// InspectBuild returns the record of the build ref
+func (c *Client) InspectBuild(ctx context.Context, ref string) (*BuildInfo, error) {
+	builds, err := c.listBuilds(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if len(builds) != 1 {
+		return nil, errors.Errorf("build %s not found", ref)
+	}
+	return builds[0], nil
+}
+
+func (c *Client) listBuilds(ctx context.Context, ref string) ([]*BuildInfo, error) {
+	resp, err := c.controlClient().ListBuilds(ctx, &controlapi.ListBuildsRequest{Ref: ref})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list builds")
+	}
+
+	var builds []*BuildInfo
+
+	for _, r := range resp.Record {
+		bi := &BuildInfo{
+			Ref:           r.Ref,
+			Definition:    r.Definition,
+			Frontend:      r.Frontend,
+			FrontendAttrs: r.FrontendAttrs,
+			Exporter:      r.Exporter,
+			ExporterAttrs: r.ExporterAttrs,
+			CreatedAt:     r.CreatedAt,
+			CompletedAt:   r.CompletedAt,
+			Error:         r.Error,
+			LogSize:       r.LogSize,
+			LogsTruncated: r.LogsTruncated,
+			Summary:       fromControlBuildSummary(r.Summary),
+
+			ExporterResponse: r.ExporterResponse,
+		}
+		for _, v := range r.Vertexes {
+			bi.Vertexes = append(bi.Vertexes, fromControlVertex(v))
+		}
+		builds = append(builds, bi)
+	}
+
+	return builds, nil
+}
+
+// BuildLogs sends the stored logs of the build ref to ch in the order they
+// were written. ch is closed when BuildLogs returns.
+func (c *Client) BuildLogs(ctx context.Context, ref string, ch chan *VertexLog) error {
+	defer close(ch)
+
+	stream, err := c.controlClient().BuildLogs(ctx, &controlapi.BuildLogsRequest{Ref: ref})
+	if err != nil {
+		return errors.Wrap(err, "failed to get build logs")
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return errors.Wrap(err, "failed to receive build logs")
+		}
+		for _, l := range resp.Logs {
+			ch <- fromControlVertexLog(l)
+		}
+	}
+}
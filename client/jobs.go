@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	controlapi "github.com/moby/buildkit/api/services/control"
+	"github.com/pkg/errors"
+)
+
+// JobInfo describes a build that is running or waiting in the queue of the
+// daemon
+type JobInfo struct {
+	Ref       string
+	Client    string
+	Priority  int
+	CreatedAt time.Time
+	// Position is the position of the build in the queue, starting at 1, or
+	// 0 if the build is running
+	Position int
+}
+
+// ListJobs returns the builds of all the clients of the daemon that have not
+// completed
+func (c *Client) ListJobs(ctx context.Context) ([]*JobInfo, error) {
+	resp, err := c.controlClient().ListJobs(ctx, &controlapi.ListJobsRequest{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list jobs")
+	}
+	jobs := make([]*JobInfo, 0, len(resp.Jobs))
+	for _, j := range resp.Jobs {
+		jobs = append(jobs, &JobInfo{
+			Ref:       j.Ref,
+			Client:    j.Client,
+			Priority:  int(j.Priority),
+			CreatedAt: j.CreatedAt,
+			Position:  int(j.Position),
+		})
+	}
+	return jobs, nil
+}
+
+// CancelJob cancels the build ref. The Solve of the build returns the error
+// of the canceled context.
+func (c *Client) CancelJob(ctx context.Context, ref string) error {
+	if _, err := c.controlClient().Cancel(ctx, &controlapi.CancelRequest{Ref: ref}); err != nil {
+		return errors.Wrapf(err, "failed to cancel %s", ref)
+	}
+	return nil
+}
+
+// AttachStatus sends the status of the build ref, that can be started by
+// another client, to statusChan until the build has completed or ctx is
+// canceled. statusChan is closed when AttachStatus returns.
+func (c *Client) AttachStatus(ctx context.Context, ref string, statusChan chan *SolveStatus) error {
+	defer close(statusChan)
+	return c.status(ctx, ref, statusChan)
+}
@@ -0,0 +1,93 @@
+package llb
+
+import (
+	"github.com/moby/buildkit/solver/pb"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+// FromDefinition returns the state of the result of a marshaled definition,
+// eg. the named inputs of a frontend. The metadata of the state is the
+// default one, it is not part of the definition.
+func FromDefinition(def [][]byte) (State, error) {
+	if len(def) == 0 {
+		return State{}, errors.New("invalid empty definition")
+	}
+	ops := make(map[digest.Digest]*pb.Op, len(def))
+	dts := make(map[digest.Digest][]byte, len(def))
+	for _, dt := range def {
+		var op pb.Op
+		if err := op.Unmarshal(dt); err != nil {
+			return State{}, errors.Wrap(err, "failed to parse llb proto op")
+		}
+		dgst := digest.FromBytes(dt)
+		ops[dgst] = &op
+		dts[dgst] = dt
+	}
+	// the last op only points to the result of the definition
+	last := ops[digest.FromBytes(def[len(def)-1])]
+	if len(last.Inputs) != 1 {
+		return State{}, errors.New("invalid definition without a result")
+	}
+	vertexes := map[digest.Digest]*DefinitionOp{}
+	out, err := definitionOutput(last.Inputs[0], ops, dts, vertexes)
+	if err != nil {
+		return State{}, err
+	}
+	return NewState(out), nil
+}
+
+func definitionOutput(inp *pb.Input, ops map[digest.Digest]*pb.Op, dts map[digest.Digest][]byte, vertexes map[digest.Digest]*DefinitionOp) (Output, error) {
+	v, ok := vertexes[inp.Digest]
+	if !ok {
+		op, ok := ops[inp.Digest]
+		if !ok {
+			return nil, errors.Errorf("invalid missing input %s", inp.Digest)
+		}
+		v = &DefinitionOp{dt: dts[inp.Digest]}
+		// the inputs are added before the vertex, so a cycle is reported as
+		// a missing input instead of recursing forever
+		delete(ops, inp.Digest)
+		for _, in := range op.Inputs {
+			o, err := definitionOutput(in, ops, dts, vertexes)
+			if err != nil {
+				return nil, err
+			}
+			v.inputs = append(v.inputs, o)
+		}
+		v.output = &output{vertex: v}
+		vertexes[inp.Digest] = v
+	}
+	index := inp.Index
+	return &output{vertex: v, getIndex: func() (pb.OutputIndex, error) {
+		return index, nil
+	}}, nil
+}
+
+// DefinitionOp is an op of a marshaled definition. It is marshaled as is.
+type DefinitionOp struct {
+	dt     []byte
+	inputs []Output
+	output Output
+}
+
+func (d *DefinitionOp) Validate() error {
+	for _, inp := range d.inputs {
+		if err := inp.Vertex().Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *DefinitionOp) Marshal() ([]byte, error) {
+	return d.dt, nil
+}
+
+func (d *DefinitionOp) Output() Output {
+	return d.output
+}
+
+func (d *DefinitionOp) Inputs() []Output {
+	return d.inputs
+}
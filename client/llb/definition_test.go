@@ -0,0 +1,37 @@
+package llb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromDefinition(t *testing.T) {
+	base := Image("docker.io/library/busybox:latest")
+	st := base.Run(Shlex("ls -l"), AddMount("/ctx", Local("context"))).Root()
+	st = Merge([]State{base, st})
+
+	def, err := st.Marshal()
+	require.NoError(t, err)
+
+	st2, err := FromDefinition(def)
+	require.NoError(t, err)
+	require.NoError(t, st2.Validate())
+	def2, err := st2.Marshal()
+	require.NoError(t, err)
+	require.Equal(t, def, def2)
+
+	// the state can be used in a new definition
+	def3, err := Merge([]State{Local("other"), st2}).Marshal()
+	require.NoError(t, err)
+	require.Equal(t, len(def)+2, len(def3))
+
+	_, err = FromDefinition(nil)
+	require.Error(t, err)
+
+	_, err = FromDefinition(def[:len(def)-1])
+	require.Error(t, err)
+
+	_, err = FromDefinition(def[1:])
+	require.Error(t, err)
+}
@@ -0,0 +1,81 @@
+package llb
+
+import (
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/pkg/errors"
+)
+
+// Diff returns a state with the files that were added or changed in upper
+// compared to lower. The metadata of the result is taken from upper.
+func Diff(lower, upper State) State {
+	if upper.Output() == nil {
+		return upper
+	}
+	d := &DiffOp{lower: lower.Output(), upper: upper.Output()}
+	d.output = &output{vertex: d}
+	return upper.WithOutput(d.output)
+}
+
+type DiffOp struct {
+	lower    Output
+	upper    Output
+	output   Output
+	cachedPB []byte
+}
+
+func (d *DiffOp) Validate() error {
+	if d.upper == nil {
+		return errors.Errorf("diff of an empty state is not allowed")
+	}
+	for _, inp := range d.Inputs() {
+		if err := inp.Vertex().Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *DiffOp) Marshal() ([]byte, error) {
+	if d.cachedPB != nil {
+		return d.cachedPB, nil
+	}
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+
+	pd := &pb.DiffOp{Lower: pb.Empty}
+	pop := &pb.Op{
+		Op: &pb.Op_Diff{
+			Diff: pd,
+		},
+	}
+	for _, o := range d.Inputs() {
+		inp, err := o.ToInput()
+		if err != nil {
+			return nil, err
+		}
+		pop.Inputs = append(pop.Inputs, inp)
+	}
+	if d.lower != nil {
+		pd.Lower = 0
+		pd.Upper = 1
+	}
+
+	dt, err := pop.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	d.cachedPB = dt
+	return dt, nil
+}
+
+func (d *DiffOp) Output() Output {
+	return d.output
+}
+
+func (d *DiffOp) Inputs() (inputs []Output) {
+	if d.lower != nil {
+		inputs = append(inputs, d.lower)
+	}
+	return append(inputs, d.upper)
+}
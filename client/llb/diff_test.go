@@ -0,0 +1,36 @@
+package llb
+
+import (
+	"testing"
+
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffMarshal(t *testing.T) {
+	base := Image("docker.io/library/busybox:latest")
+	installed := base.Run(Shlex("apk add git")).Root()
+
+	def, err := Diff(base, installed).Marshal()
+	require.NoError(t, err)
+
+	var op pb.Op
+	require.NoError(t, op.Unmarshal(def[len(def)-2]))
+	d, ok := op.Op.(*pb.Op_Diff)
+	require.True(t, ok)
+	require.Equal(t, 2, len(op.Inputs))
+	require.Equal(t, pb.InputIndex(0), d.Diff.Lower)
+	require.Equal(t, pb.InputIndex(1), d.Diff.Upper)
+
+	def, err = Diff(Scratch(), installed).Marshal()
+	require.NoError(t, err)
+	op = pb.Op{}
+	require.NoError(t, op.Unmarshal(def[len(def)-2]))
+	d, ok = op.Op.(*pb.Op_Diff)
+	require.True(t, ok)
+	require.Equal(t, 1, len(op.Inputs))
+	require.Equal(t, pb.Empty, d.Diff.Lower)
+	require.Equal(t, pb.InputIndex(0), d.Diff.Upper)
+
+	require.Nil(t, Diff(base, Scratch()).Output())
+}
@@ -0,0 +1,23 @@
+// Package llb builds the low-level build definitions that BuildKit solves.
+//
+// A State is the filesystem and the process configuration of a build step.
+// States start from sources, eg. Image, Git, Local, HTTP or Scratch, and are
+// changed with the methods that return a new State, eg. Dir, AddEnv and User.
+// Run starts a process on a State and returns an ExecState whose Root is the
+// resulting filesystem. AddMount adds other States to the process and
+// returns the State of the mount after the process has completed.
+//
+//	st := llb.Image("docker.io/library/golang:1.9-alpine").
+//		Dir("/src").
+//		Run(llb.Shlex("go build -o /out/app ."), llb.AddMount("/src", llb.Local("context"), llb.Readonly))
+//	out := st.AddMount("/out", llb.Scratch())
+//
+// Marshal serializes a State and the States it depends on as the definition
+// that is passed to the daemon, eg. with WriteTo for `buildctl build`.
+//
+//	dt, err := out.Marshal()
+//	if err != nil {
+//		return err
+//	}
+//	return llb.WriteTo(dt, os.Stdout)
+package llb
@@ -2,9 +2,15 @@ package llb
 
 import (
 	_ "crypto/sha256"
+	"fmt"
+	"net"
+	"path"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/moby/buildkit/solver/pb"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 )
 
@@ -12,6 +18,16 @@ type Meta struct {
 	Args []string
 	Env  EnvList
 	Cwd  string
+	User string
+
+	Hostname   string
+	ExtraHosts []HostIP
+}
+
+// HostIP maps a host name to an IP address in the /etc/hosts file of an exec
+type HostIP struct {
+	Host string
+	IP   net.IP
 }
 
 func NewExecOp(root Output, meta Meta, readOnly bool) *ExecOp {
@@ -33,19 +49,70 @@ func NewExecOp(root Output, meta Meta, readOnly bool) *ExecOp {
 }
 
 type mount struct {
+	target           string
+	readonly         bool
+	source           Output
+	output           Output
+	selector         string
+	contentCache     bool
+	contentSelectors []*pb.Selector
+	cacheID          string
+	cacheSharing     CacheMountSharingMode
+	tmpfs            bool
+	tmpfsSize        int64
+	hostPath         string
+	hostVersion      string
+}
+
+// hasSource returns false for mounts that don't use their source state, like
+// cache, tmpfs and host mounts
+func (m *mount) hasSource() bool {
+	return m.source != nil && m.cacheID == "" && !m.tmpfs && m.hostPath == ""
+}
+
+func (m *mount) hasOutput() bool {
+	return !m.readonly && m.cacheID == "" && !m.tmpfs && m.hostPath == ""
+}
+
+type secret struct {
 	target   string
-	readonly bool
-	source   Output
-	output   Output
-	selector string
-	// hasOutput bool
+	id       string
+	uid      int
+	gid      int
+	mode     int
+	optional bool
+}
+
+type sshSocket struct {
+	target   string
+	id       string
+	uid      int
+	gid      int
+	mode     int
+	optional bool
 }
 
 type ExecOp struct {
-	root     Output
-	mounts   []*mount
-	meta     Meta
-	cachedPB []byte
+	root             Output
+	mounts           []*mount
+	secrets          []*secret
+	ssh              []*sshSocket
+	meta             Meta
+	contentCacheRoot bool
+	contentAttrs     *pb.ContentAttrs
+	resources        *pb.Resources
+	timeout          time.Duration
+	checkpoint       time.Duration
+	retry            *pb.RetryPolicy
+	noProxyEnv       bool
+	proxyEnv         []string
+	network          pb.NetMode
+	security         pb.SecurityMode
+	devices          []string
+	securityProfile  *pb.SecurityProfile
+	platform         *ocispec.Platform
+	workerFilter     []string
+	cachedPB         []byte
 }
 
 func (e *ExecOp) AddMount(target string, source Output, opt ...MountOption) Output {
@@ -57,7 +124,7 @@ func (e *ExecOp) AddMount(target string, source Output, opt ...MountOption) Outp
 		o(m)
 	}
 	e.mounts = append(e.mounts, m)
-	if m.readonly {
+	if !m.hasOutput() {
 		m.output = source
 	} else {
 		m.output = &output{vertex: e, getIndex: e.getMountIndexFn(m)}
@@ -66,6 +133,39 @@ func (e *ExecOp) AddMount(target string, source Output, opt ...MountOption) Outp
 	return m.output
 }
 
+// AddSecret mounts a secret from the client session as a read-only file at
+// target. Secrets are not part of the exec outputs or its cache key.
+func (e *ExecOp) AddSecret(target string, opt ...SecretOption) {
+	s := &secret{
+		target: target,
+		id:     path.Base(target),
+		mode:   0400,
+	}
+	for _, o := range opt {
+		o(s)
+	}
+	e.secrets = append(e.secrets, s)
+	e.cachedPB = nil
+}
+
+// AddSSHSocket forwards an ssh agent from the client session to a unix socket
+// at the target path. Without a target the socket is placed under
+// /run/buildkit. SSH_AUTH_SOCK points to the first socket unless it has been
+// set in the environment.
+func (e *ExecOp) AddSSHSocket(opt ...SSHOption) {
+	s := &sshSocket{
+		mode: 0600,
+	}
+	for _, o := range opt {
+		o(s)
+	}
+	if s.target == "" {
+		s.target = fmt.Sprintf("/run/buildkit/ssh_agent.%d", len(e.ssh))
+	}
+	e.ssh = append(e.ssh, s)
+	e.cachedPB = nil
+}
+
 func (e *ExecOp) GetMount(target string) Output {
 	for _, m := range e.mounts {
 		if m.target == target {
@@ -109,7 +209,31 @@ func (e *ExecOp) Marshal() ([]byte, error) {
 			Args: e.meta.Args,
 			Env:  e.meta.Env.ToArray(),
 			Cwd:  e.meta.Cwd,
+			User: e.meta.User,
+
+			Hostname: e.meta.Hostname,
 		},
+		ContentCacheRoot: e.contentCacheRoot,
+		ContentAttrs:     e.contentAttrs,
+		Resources:        e.resources,
+		Retry:            e.retry,
+		ReadonlyRootfs:   e.mounts[0].readonly,
+		NoProxyEnv:       e.noProxyEnv,
+		ProxyEnv:         e.proxyEnv,
+		Network:          e.network,
+		Security:         e.security,
+		Devices:          e.devices,
+		SecurityProfile:  e.securityProfile,
+	}
+	for _, h := range e.meta.ExtraHosts {
+		peo.Meta.ExtraHosts = append(peo.Meta.ExtraHosts, &pb.HostIP{Host: h.Host, IP: h.IP.String()})
+	}
+	if e.timeout > 0 {
+		// round up so that a short timeout doesn't disable it
+		peo.Timeout = int64((e.timeout + time.Second - 1) / time.Second)
+	}
+	if e.checkpoint > 0 {
+		peo.CheckpointInterval = int64((e.checkpoint + time.Second - 1) / time.Second)
 	}
 
 	pop := &pb.Op{
@@ -117,11 +241,17 @@ func (e *ExecOp) Marshal() ([]byte, error) {
 			Exec: peo,
 		},
 	}
+	if e.platform != nil {
+		pop.Platform = pb.PlatformFromSpec(*e.platform)
+	}
+	if len(e.workerFilter) > 0 {
+		pop.Constraints = &pb.WorkerConstraints{Filter: e.workerFilter}
+	}
 
 	outIndex := 0
 	for _, m := range e.mounts {
 		inputIndex := pb.InputIndex(len(pop.Inputs))
-		if m.source != nil {
+		if m.hasSource() {
 			inp, err := m.source.ToInput()
 			if err != nil {
 				return nil, err
@@ -145,21 +275,87 @@ func (e *ExecOp) Marshal() ([]byte, error) {
 		}
 
 		outputIndex := pb.OutputIndex(-1)
-		if !m.readonly {
+		if m.hasOutput() {
 			outputIndex = pb.OutputIndex(outIndex)
 			outIndex++
 		}
 
 		pm := &pb.Mount{
-			Input:    inputIndex,
-			Dest:     m.target,
-			Readonly: m.readonly,
-			Output:   outputIndex,
-			Selector: m.selector,
+			Input:            inputIndex,
+			Dest:             m.target,
+			Readonly:         m.readonly,
+			Output:           outputIndex,
+			Selector:         m.selector,
+			ContentCache:     m.contentCache,
+			ContentSelectors: m.contentSelectors,
+		}
+		if m.tmpfs {
+			pm.MountType = pb.MountType_TMPFS
+			pm.TmpfsOpt = &pb.TmpfsOpt{
+				Size_: m.tmpfsSize,
+			}
+		}
+		if m.cacheID != "" {
+			pm.MountType = pb.MountType_CACHE
+			pm.CacheOpt = &pb.CacheOpt{
+				ID: m.cacheID,
+			}
+			switch m.cacheSharing {
+			case CacheMountShared:
+				pm.CacheOpt.Sharing = pb.CacheSharingOpt_SHARED
+			case CacheMountPrivate:
+				pm.CacheOpt.Sharing = pb.CacheSharingOpt_PRIVATE
+			case CacheMountLocked:
+				pm.CacheOpt.Sharing = pb.CacheSharingOpt_LOCKED
+			}
+		}
+		if m.hostPath != "" {
+			pm.MountType = pb.MountType_HOST
+			pm.Readonly = true
+			pm.HostOpt = &pb.HostOpt{
+				Path:    m.hostPath,
+				Version: m.hostVersion,
+			}
 		}
 		peo.Mounts = append(peo.Mounts, pm)
 	}
 
+	sort.Slice(e.secrets, func(i, j int) bool {
+		return e.secrets[i].target < e.secrets[j].target
+	})
+	for _, s := range e.secrets {
+		peo.Mounts = append(peo.Mounts, &pb.Mount{
+			Input:     pb.Empty,
+			Dest:      s.target,
+			Readonly:  true,
+			Output:    pb.SkipOutput,
+			MountType: pb.MountType_SECRET,
+			SecretOpt: &pb.SecretOpt{
+				ID:       s.id,
+				Uid:      uint32(s.uid),
+				Gid:      uint32(s.gid),
+				Mode:     uint32(s.mode),
+				Optional: s.optional,
+			},
+		})
+	}
+
+	for _, s := range e.ssh {
+		peo.Mounts = append(peo.Mounts, &pb.Mount{
+			Input:     pb.Empty,
+			Dest:      s.target,
+			Output:    pb.SkipOutput,
+			MountType: pb.MountType_SSH,
+			SSHOpt: &pb.SSHOpt{
+				ID:       s.id,
+				Uid:      uint32(s.uid),
+				Gid:      uint32(s.gid),
+				Mode:     uint32(s.mode),
+				Optional: s.optional,
+			},
+		})
+	}
+
 	dt, err := pop.Marshal()
 	if err != nil {
 		return nil, err
@@ -175,7 +371,7 @@ func (e *ExecOp) Output() Output {
 func (e *ExecOp) Inputs() (inputs []Output) {
 	mm := map[Output]struct{}{}
 	for _, m := range e.mounts {
-		if m.source != nil {
+		if m.hasSource() {
 			mm[m.source] = struct{}{}
 		}
 	}
@@ -194,7 +390,7 @@ func (e *ExecOp) getMountIndexFn(m *mount) func() (pb.OutputIndex, error) {
 
 		i := 0
 		for _, m2 := range e.mounts {
-			if m2.readonly {
+			if !m2.hasOutput() {
 				continue
 			}
 			if m == m2 {
@@ -229,6 +425,127 @@ func Readonly(m *mount) {
 	m.readonly = true
 }
 
+// ContentCache makes the cache of the exec depend on the content of a
+// writable mount instead of how it was built. It has no effect on mounts with
+// a source path.
+func ContentCache(m *mount) {
+	m.contentCache = true
+}
+
+// ContentSelector limits the content based cache key of a read-only mount to
+// the paths matching pattern in the mounted directory. In the pattern "**"
+// matches any number of directories. The targets of the matched symlinks are
+// used if followLinks is set. The option can be repeated to select more paths.
+func ContentSelector(pattern string, followLinks bool) MountOption {
+	return func(m *mount) {
+		m.contentSelectors = append(m.contentSelectors, &pb.Selector{
+			Path:        pattern,
+			Wildcard:    strings.ContainsAny(pattern, `*?[\`),
+			FollowLinks: followLinks,
+		})
+	}
+}
+
+// AsPersistentCacheDir mounts a persistent directory identified by id instead
+// of the source. The directory is not part of the exec outputs and its
+// content is kept between builds. The source of the mount is ignored.
+func AsPersistentCacheDir(id string, sharing CacheMountSharingMode) MountOption {
+	return func(m *mount) {
+		m.cacheID = id
+		m.cacheSharing = sharing
+	}
+}
+
+// Tmpfs mounts an in-memory directory instead of the source. The content of
+// the directory is discarded after the exec. A size of zero uses the default
+// size limit.
+func Tmpfs(size int64) MountOption {
+	return func(m *mount) {
+		m.tmpfs = true
+		m.tmpfsSize = size
+	}
+}
+
+// HostBind mounts the host path read-only instead of the source. The daemon
+// must allow the path. The version of the content replaces the path in the
+// cache key so the cache is shared by the hosts with the same content.
+func HostBind(path, version string) MountOption {
+	return func(m *mount) {
+		m.hostPath = path
+		m.hostVersion = version
+		m.readonly = true
+	}
+}
+
+type SecretOption func(*secret)
+
+// SecretID sets the ID of the secret in the client session. By default the
+// base name of the target path is used.
+func SecretID(id string) SecretOption {
+	return func(s *secret) {
+		s.id = id
+	}
+}
+
+// SecretFileOpt sets the owner and the permission bits of the secret file
+func SecretFileOpt(uid, gid, mode int) SecretOption {
+	return func(s *secret) {
+		s.uid = uid
+		s.gid = gid
+		s.mode = mode
+	}
+}
+
+// SecretOptional doesn't fail the exec if the client doesn't provide the
+// secret
+func SecretOptional(s *secret) {
+	s.optional = true
+}
+
+type SSHOption func(*sshSocket)
+
+// SSHID sets the ID of the forwarded agent in the client session. The
+// default agent is used if no ID is set.
+func SSHID(id string) SSHOption {
+	return func(s *sshSocket) {
+		s.id = id
+	}
+}
+
+// SSHSocketTarget sets the path of the socket in the container
+func SSHSocketTarget(target string) SSHOption {
+	return func(s *sshSocket) {
+		s.target = target
+	}
+}
+
+// SSHSocketOpt sets the path, the owner and the permission bits of the
+// socket
+func SSHSocketOpt(target string, uid, gid, mode int) SSHOption {
+	return func(s *sshSocket) {
+		s.target = target
+		s.uid = uid
+		s.gid = gid
+		s.mode = mode
+	}
+}
+
+// SSHOptional doesn't fail the exec if the client doesn't forward the agent
+func SSHOptional(s *sshSocket) {
+	s.optional = true
+}
+
+type CacheMountSharingMode int
+
+const (
+	// CacheMountShared allows the directory to be used by concurrent builds
+	CacheMountShared CacheMountSharingMode = iota
+	// CacheMountPrivate creates a new directory if the existing one is in use
+	CacheMountPrivate
+	// CacheMountLocked waits until other builds have released the directory
+	CacheMountLocked
+)
+
 func SourcePath(src string) MountOption {
 	return func(m *mount) {
 		m.selector = src
@@ -254,6 +571,18 @@ func Args(a []string) RunOption {
 	}
 }
 
+// DefaultShell is the shell of ShellForm for unix platforms
+var DefaultShell = []string{"/bin/sh", "-c"}
+
+// ShellForm runs the command line cmd with the shell, DefaultShell if it is
+// not set, like the shell form of a RUN instruction of a Dockerfile
+func ShellForm(cmd string, shell ...string) RunOption {
+	if len(shell) == 0 {
+		shell = DefaultShell
+	}
+	return Args(append(append([]string{}, shell...), cmd))
+}
+
 func AddEnv(key, value string) RunOption {
 	return AddEnvf(key, value)
 }
@@ -275,6 +604,13 @@ func Dirf(str string, v ...interface{}) RunOption {
 	}
 }
 
+func User(str string) RunOption {
+	return func(ei ExecInfo) ExecInfo {
+		ei.State = ei.State.User(str)
+		return ei
+	}
+}
+
 func Reset(s State) RunOption {
 	return func(ei ExecInfo) ExecInfo {
 		ei.State = ei.State.Reset(s)
@@ -296,15 +632,253 @@ func AddMount(dest string, mountState State, opts ...MountOption) RunOption {
 	}
 }
 
+func AddSecret(target string, opts ...SecretOption) RunOption {
+	return func(ei ExecInfo) ExecInfo {
+		ei.Secrets = append(ei.Secrets, SecretInfo{target, opts})
+		return ei
+	}
+}
+
+func AddSSHSocket(opts ...SSHOption) RunOption {
+	return func(ei ExecInfo) ExecInfo {
+		ei.SSH = append(ei.SSH, SSHInfo{opts})
+		return ei
+	}
+}
+
+// MemoryLimit limits the memory of the exec in bytes
+func MemoryLimit(n int64) RunOption {
+	return func(ei ExecInfo) ExecInfo {
+		ei.Resources.Memory = n
+		return ei
+	}
+}
+
+// CPUShares sets the relative cpu weight of the exec
+func CPUShares(n int64) RunOption {
+	return func(ei ExecInfo) ExecInfo {
+		ei.Resources.CpuShares = n
+		return ei
+	}
+}
+
+// CPUQuota limits the exec to quota microseconds of cpu time in every period
+// microseconds
+func CPUQuota(quota, period int64) RunOption {
+	return func(ei ExecInfo) ExecInfo {
+		ei.Resources.CpuQuota = quota
+		ei.Resources.CpuPeriod = period
+		return ei
+	}
+}
+
+// PidsLimit limits the number of processes in the exec
+func PidsLimit(n int64) RunOption {
+	return func(ei ExecInfo) ExecInfo {
+		ei.Resources.PidsLimit = n
+		return ei
+	}
+}
+
+// Ulimit sets a POSIX rlimit, eg. "nofile", for the exec
+func Ulimit(name string, soft, hard int64) RunOption {
+	return func(ei ExecInfo) ExecInfo {
+		ei.Resources.Ulimits = append(append([]*pb.Ulimit{}, ei.Resources.Ulimits...), &pb.Ulimit{
+			Name: name,
+			Soft: soft,
+			Hard: hard,
+		})
+		return ei
+	}
+}
+
+// Timeout kills the exec if it hasn't completed in d. The timeout is rounded
+// up to full seconds.
+func Timeout(d time.Duration) RunOption {
+	return func(ei ExecInfo) ExecInfo {
+		ei.Timeout = d
+		return ei
+	}
+}
+
+// CheckpointInterval saves the root filesystem of the exec every d while it
+// is running. If the daemon stops before the exec has completed, the exec is
+// run again on top of the files of the latest checkpoint, so it should be a
+// command that can continue from partial results, e.g. an incremental build.
+// The interval is rounded up to full seconds.
+func CheckpointInterval(d time.Duration) RunOption {
+	return func(ei ExecInfo) ExecInfo {
+		ei.CheckpointInterval = d
+		return ei
+	}
+}
+
+// Retry runs a failed exec again up to maxRetries times. If exit codes are
+// set only the failures with one of the codes are retried.
+func Retry(maxRetries int, exitCodes ...int) RunOption {
+	return func(ei ExecInfo) ExecInfo {
+		ei.Retry = &pb.RetryPolicy{
+			MaxRetries: int32(maxRetries),
+		}
+		for _, c := range exitCodes {
+			ei.Retry.ExitCodes = append(ei.Retry.ExitCodes, int32(c))
+		}
+		return ei
+	}
+}
+
 func ReadonlyRootFS(ei ExecInfo) ExecInfo {
 	ei.ReadonlyRootFS = true
 	return ei
 }
 
+// NoProxyEnv disables the proxy variables that the daemon adds to the
+// environment of the exec
+func NoProxyEnv(ei ExecInfo) ExecInfo {
+	ei.NoProxyEnv = true
+	return ei
+}
+
+// WithProxyEnv sets the proxy variables of the exec as KEY=VALUE pairs. Unlike
+// the other environment variables they are not part of the cache key. Only
+// the HTTP_PROXY, HTTPS_PROXY, FTP_PROXY and NO_PROXY variables, in upper or
+// lower case, can be set.
+func WithProxyEnv(env ...string) RunOption {
+	return func(ei ExecInfo) ExecInfo {
+		ei.ProxyEnv = append(append([]string{}, ei.ProxyEnv...), env...)
+		return ei
+	}
+}
+
+// Network sets the network namespace of the exec. pb.NetMode_NET_HOST
+// requires the network.host entitlement.
+func Network(m pb.NetMode) RunOption {
+	return func(ei ExecInfo) ExecInfo {
+		ei.Network = m
+		return ei
+	}
+}
+
+// Security sets the sandbox of the exec. pb.SecurityMode_INSECURE requires
+// the security.insecure entitlement.
+func Security(m pb.SecurityMode) RunOption {
+	return func(ei ExecInfo) ExecInfo {
+		ei.Security = m
+		return ei
+	}
+}
+
+// AddDevice gives the exec access to the host device at path. The devices
+// require the device entitlement.
+func AddDevice(path string) RunOption {
+	return func(ei ExecInfo) ExecInfo {
+		ei.Devices = append(append([]string{}, ei.Devices...), path)
+		return ei
+	}
+}
+
+// WithSecurityProfile confines the exec with the seccomp, AppArmor and
+// SELinux profiles set in p instead of the ones of the daemon. It requires
+// the security.profile entitlement.
+func WithSecurityProfile(p pb.SecurityProfile) RunOption {
+	return func(ei ExecInfo) ExecInfo {
+		ei.SecurityProfile = &p
+		return ei
+	}
+}
+
+// AddExtraHost adds the host to the /etc/hosts file of the exec
+func AddExtraHost(host string, ip net.IP) RunOption {
+	return func(ei ExecInfo) ExecInfo {
+		ei.ExtraHosts = append(append([]HostIP{}, ei.ExtraHosts...), HostIP{Host: host, IP: ip})
+		return ei
+	}
+}
+
+// WorkerFilter runs the exec on a worker of the daemon that matches all the
+// filters, e.g. labels."org.mobyproject.buildkit.worker.executor"==runc
+func WorkerFilter(filters ...string) RunOption {
+	return func(ei ExecInfo) ExecInfo {
+		ei.WorkerFilter = append(append([]string{}, ei.WorkerFilter...), filters...)
+		return ei
+	}
+}
+
+// ContentCacheRoot makes the cache of the exec depend on the content of the
+// root filesystem instead of how it was built. Rebuilding a base with identical
+// content will then match the cache.
+func ContentCacheRoot(ei ExecInfo) ExecInfo {
+	ei.ContentCacheRoot = true
+	return ei
+}
+
+// ContentAttr is a file attribute that can be made part of the content based
+// cache keys. By default only the contents, the file types, the permission
+// bits, the link targets and the extended attributes are.
+type ContentAttr int
+
+const (
+	// ContentAttrOwnership is the uid and gid of the files
+	ContentAttrOwnership ContentAttr = iota
+	// ContentAttrXattrs are all the extended attributes of the files of the
+	// sources that exclude them, e.g. with ExcludeXattrs
+	ContentAttrXattrs
+	// ContentAttrCapabilities is the security.capability extended attribute
+	ContentAttrCapabilities
+	// ContentAttrModTime is the modification time of the files
+	ContentAttrModTime
+)
+
+func contentAttrs(attrs []ContentAttr) *pb.ContentAttrs {
+	if len(attrs) == 0 {
+		return nil
+	}
+	ca := &pb.ContentAttrs{}
+	for _, a := range attrs {
+		switch a {
+		case ContentAttrOwnership:
+			ca.Ownership = true
+		case ContentAttrXattrs:
+			ca.Xattrs = true
+		case ContentAttrCapabilities:
+			ca.Capabilities = true
+		case ContentAttrModTime:
+			ca.ModTime = true
+		}
+	}
+	return ca
+}
+
+// WithContentAttrs makes the attributes of the files of the content keyed
+// mounts part of the cache key, e.g. to rerun the exec when only the owner of
+// the files has changed
+func WithContentAttrs(attrs ...ContentAttr) RunOption {
+	return func(ei ExecInfo) ExecInfo {
+		ei.ContentAttrs = contentAttrs(attrs)
+		return ei
+	}
+}
+
 type ExecInfo struct {
-	State          State
-	Mounts         []MountInfo
-	ReadonlyRootFS bool
+	State              State
+	Mounts             []MountInfo
+	Secrets            []SecretInfo
+	SSH                []SSHInfo
+	ReadonlyRootFS     bool
+	ContentCacheRoot   bool
+	ContentAttrs       *pb.ContentAttrs
+	Resources          pb.Resources
+	Timeout            time.Duration
+	CheckpointInterval time.Duration
+	Retry              *pb.RetryPolicy
+	NoProxyEnv         bool
+	ProxyEnv           []string
+	Network            pb.NetMode
+	Security           pb.SecurityMode
+	Devices            []string
+	SecurityProfile    *pb.SecurityProfile
+	ExtraHosts         []HostIP
+	WorkerFilter       []string
 }
 
 type MountInfo struct {
@@ -312,3 +886,12 @@ type MountInfo struct {
 	Source Output
 	Opts   []MountOption
 }
+
+type SecretInfo struct {
+	Target string
+	Opts   []SecretOption
+}
+
+type SSHInfo struct {
+	Opts []SSHOption
+}
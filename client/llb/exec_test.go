@@ -0,0 +1,138 @@
+package llb
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentSelectorMarshal(t *testing.T) {
+	src := Local("context")
+	st := Image("docker.io/library/busybox:latest").Run(Shlex("true"))
+	st.AddMount("/src", src, SourcePath("app"), Readonly, ContentSelector("**/*.go", false), ContentSelector("go.mod", true))
+	def, err := st.Root().Marshal()
+	require.NoError(t, err)
+
+	op := &pb.Op{}
+	require.NoError(t, op.Unmarshal(def[len(def)-2]))
+	exec := op.GetExec()
+	require.NotNil(t, exec)
+
+	var m *pb.Mount
+	for _, em := range exec.Mounts {
+		if em.Dest == "/src" {
+			m = em
+		}
+	}
+	require.NotNil(t, m)
+	require.Equal(t, "app", m.Selector)
+	require.Equal(t, []*pb.Selector{
+		{Path: "**/*.go", Wildcard: true},
+		{Path: "go.mod", FollowLinks: true},
+	}, m.ContentSelectors)
+}
+
+func TestContentAttrsMarshal(t *testing.T) {
+	st := Image("docker.io/library/busybox:latest").Run(Shlex("true"), WithContentAttrs(ContentAttrOwnership, ContentAttrCapabilities), ContentCacheRoot)
+	def, err := st.Root().Marshal()
+	require.NoError(t, err)
+
+	op := &pb.Op{}
+	require.NoError(t, op.Unmarshal(def[len(def)-2]))
+	exec := op.GetExec()
+	require.NotNil(t, exec)
+	require.Equal(t, &pb.ContentAttrs{Ownership: true, Capabilities: true}, exec.ContentAttrs)
+
+	st2 := Scratch().File(Copy(Local("context"), "foo", "bar", CopyContentAttrs(ContentAttrModTime)))
+	def, err = st2.Marshal()
+	require.NoError(t, err)
+
+	op = &pb.Op{}
+	require.NoError(t, op.Unmarshal(def[len(def)-2]))
+	file := op.GetFile()
+	require.NotNil(t, file)
+	require.Equal(t, &pb.ContentAttrs{ModTime: true}, file.Actions[0].GetCopy().ContentAttrs)
+}
+
+func TestCheckpointIntervalMarshal(t *testing.T) {
+	st := Image("docker.io/library/busybox:latest").Run(Shlex("make"), CheckpointInterval(90*time.Second+time.Millisecond))
+	def, err := st.Root().Marshal()
+	require.NoError(t, err)
+
+	op := &pb.Op{}
+	require.NoError(t, op.Unmarshal(def[len(def)-2]))
+	exec := op.GetExec()
+	require.NotNil(t, exec)
+	require.Equal(t, int64(91), exec.CheckpointInterval)
+}
+
+func TestEntitlementsMarshal(t *testing.T) {
+	st := Image("docker.io/library/busybox:latest").Run(Shlex("modprobe fuse"),
+		Network(pb.NetMode_NET_HOST), Security(pb.SecurityMode_INSECURE), AddDevice("/dev/fuse"),
+		WithSecurityProfile(pb.SecurityProfile{Apparmor: "unconfined"}))
+	def, err := st.Root().Marshal()
+	require.NoError(t, err)
+
+	op := &pb.Op{}
+	require.NoError(t, op.Unmarshal(def[len(def)-2]))
+	exec := op.GetExec()
+	require.NotNil(t, exec)
+	require.Equal(t, pb.NetMode_NET_HOST, exec.Network)
+	require.Equal(t, pb.SecurityMode_INSECURE, exec.Security)
+	require.Equal(t, []string{"/dev/fuse"}, exec.Devices)
+	require.Equal(t, &pb.SecurityProfile{Apparmor: "unconfined"}, exec.SecurityProfile)
+}
+
+func TestHostsMarshal(t *testing.T) {
+	st := Image("docker.io/library/busybox:latest").Hostname("builder").Run(Shlex("ping -c1 mirror"),
+		AddExtraHost("mirror", net.ParseIP("10.0.0.2")))
+	def, err := st.Root().Marshal()
+	require.NoError(t, err)
+
+	op := &pb.Op{}
+	require.NoError(t, op.Unmarshal(def[len(def)-2]))
+	exec := op.GetExec()
+	require.NotNil(t, exec)
+	require.Equal(t, "builder", exec.Meta.Hostname)
+	require.Equal(t, []*pb.HostIP{{Host: "mirror", IP: "10.0.0.2"}}, exec.Meta.ExtraHosts)
+}
+
+func TestHostBindMarshal(t *testing.T) {
+	st := Image("docker.io/library/busybox:latest").Run(Shlex("apt-get update"), AddMount("/mirror", Scratch(), HostBind("/srv/mirror", "2018-05")))
+	def, err := st.Root().Marshal()
+	require.NoError(t, err)
+
+	op := &pb.Op{}
+	require.NoError(t, op.Unmarshal(def[len(def)-2]))
+	exec := op.GetExec()
+	require.NotNil(t, exec)
+	require.Equal(t, 2, len(exec.Mounts))
+	require.Equal(t, &pb.Mount{
+		Input:     pb.Empty,
+		Dest:      "/mirror",
+		Output:    pb.SkipOutput,
+		Readonly:  true,
+		MountType: pb.MountType_HOST,
+		HostOpt:   &pb.HostOpt{Path: "/srv/mirror", Version: "2018-05"},
+	}, exec.Mounts[1])
+}
+
+func TestShellFormMarshal(t *testing.T) {
+	for _, tc := range []struct {
+		opt  RunOption
+		args []string
+	}{
+		{ShellForm("echo $HOME > /out"), []string{"/bin/sh", "-c", "echo $HOME > /out"}},
+		{ShellForm("echo %USERPROFILE%", "cmd", "/S", "/C"), []string{"cmd", "/S", "/C", "echo %USERPROFILE%"}},
+	} {
+		def, err := Image("docker.io/library/busybox:latest").Run(tc.opt).Root().Marshal()
+		require.NoError(t, err)
+		op := &pb.Op{}
+		require.NoError(t, op.Unmarshal(def[len(def)-2]))
+		require.Equal(t, tc.args, op.GetExec().Meta.Args)
+	}
+	require.Equal(t, []string{"/bin/sh", "-c"}, DefaultShell)
+}
@@ -0,0 +1,384 @@
+package llb
+
+import (
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/pkg/errors"
+)
+
+// FileAction is a chain of file actions created with Mkdir, Mkfile, Rm,
+// Symlink and Copy. The chain is run on a state with State.File. Relative
+// paths are resolved from the working directory of the state.
+type FileAction struct {
+	prev   *FileAction
+	action *pb.FileAction
+	// src is the source state of a copy
+	src *State
+}
+
+// FileOption sets the options of the file actions. Options that don't apply
+// to an action are ignored.
+type FileOption func(*fileInfo)
+
+type fileInfo struct {
+	owner          *pb.ChownOpt
+	mode           int32
+	timestamp      int64
+	makeParents    bool
+	followSymlink  bool
+	copyContents   bool
+	createDestPath bool
+	allowWildcard  bool
+	allowNotFound  bool
+	contentAttrs   *pb.ContentAttrs
+}
+
+func newFileInfo(opts []FileOption) *fileInfo {
+	fi := &fileInfo{}
+	for _, o := range opts {
+		o(fi)
+	}
+	return fi
+}
+
+// WithUIDGID sets the owner of the created or copied files
+func WithUIDGID(uid, gid int) FileOption {
+	return func(fi *fileInfo) {
+		fi.owner = &pb.ChownOpt{Uid: uint32(uid), Gid: uint32(gid)}
+	}
+}
+
+// WithCreatedTime sets the modification time of the created or copied files
+func WithCreatedTime(t time.Time) FileOption {
+	return func(fi *fileInfo) {
+		fi.timestamp = t.UnixNano()
+	}
+}
+
+// WithParents makes Mkdir create the missing parent directories and not fail
+// if the directory exists, and Mkfile create the missing parents of the file
+func WithParents() FileOption {
+	return func(fi *fileInfo) {
+		fi.makeParents = true
+	}
+}
+
+// CopyMode overrides the permission bits of the copied files
+func CopyMode(mode os.FileMode) FileOption {
+	return func(fi *fileInfo) {
+		fi.mode = int32(mode & os.ModePerm)
+	}
+}
+
+// FollowSymlinks makes Copy copy the target of a symlink source
+func FollowSymlinks() FileOption {
+	return func(fi *fileInfo) {
+		fi.followSymlink = true
+	}
+}
+
+// CopyDirContents makes Copy copy the contents of a source directory instead
+// of the directory itself
+func CopyDirContents() FileOption {
+	return func(fi *fileInfo) {
+		fi.copyContents = true
+	}
+}
+
+// CreateDestPath makes Copy create the missing parents of the destination
+func CreateDestPath() FileOption {
+	return func(fi *fileInfo) {
+		fi.createDestPath = true
+	}
+}
+
+// AllowWildcard matches the last component of the Copy source or the Rm path
+// as a pattern
+func AllowWildcard() FileOption {
+	return func(fi *fileInfo) {
+		fi.allowWildcard = true
+	}
+}
+
+// CopyContentAttrs makes the attributes of the copied files part of the cache
+// key of the copy
+func CopyContentAttrs(attrs ...ContentAttr) FileOption {
+	return func(fi *fileInfo) {
+		fi.contentAttrs = contentAttrs(attrs)
+	}
+}
+
+// AllowNotFound makes Rm not fail if the path doesn't exist
+func AllowNotFound() FileOption {
+	return func(fi *fileInfo) {
+		fi.allowNotFound = true
+	}
+}
+
+// Mkdir creates the directory p
+func Mkdir(p string, mode os.FileMode, opts ...FileOption) *FileAction {
+	fi := newFileInfo(opts)
+	return &FileAction{action: &pb.FileAction{Action: &pb.FileAction_Mkdir{Mkdir: &pb.FileActionMkDir{
+		Path:        p,
+		Mode:        int32(mode & os.ModePerm),
+		MakeParents: fi.makeParents,
+		Owner:       fi.owner,
+		Timestamp:   fi.timestamp,
+	}}}}
+}
+
+// Mkfile creates the file p with the contents dt. The contents are part of
+// the definition, eg. for the inline scripts and configs of frontends.
+func Mkfile(p string, mode os.FileMode, dt []byte, opts ...FileOption) *FileAction {
+	fi := newFileInfo(opts)
+	return &FileAction{action: &pb.FileAction{Action: &pb.FileAction_Mkfile{Mkfile: &pb.FileActionMkFile{
+		Path:        p,
+		Mode:        int32(mode & os.ModePerm),
+		Data:        dt,
+		Owner:       fi.owner,
+		Timestamp:   fi.timestamp,
+		MakeParents: fi.makeParents,
+	}}}}
+}
+
+// Rm removes p and everything under it
+func Rm(p string, opts ...FileOption) *FileAction {
+	fi := newFileInfo(opts)
+	return &FileAction{action: &pb.FileAction{Action: &pb.FileAction_Rm{Rm: &pb.FileActionRm{
+		Path:          p,
+		AllowNotFound: fi.allowNotFound,
+		AllowWildcard: fi.allowWildcard,
+	}}}}
+}
+
+// Symlink creates a symlink at newpath pointing to oldpath
+func Symlink(oldpath, newpath string, opts ...FileOption) *FileAction {
+	fi := newFileInfo(opts)
+	return &FileAction{action: &pb.FileAction{Action: &pb.FileAction_Symlink{Symlink: &pb.FileActionSymlink{
+		Oldpath:   oldpath,
+		Newpath:   newpath,
+		Owner:     fi.owner,
+		Timestamp: fi.timestamp,
+	}}}}
+}
+
+// Copy copies src from the state input to dest. A dest with a trailing slash
+// is a directory the source is copied into. Relative src paths are resolved
+// from the working directory of input.
+func Copy(input State, src, dest string, opts ...FileOption) *FileAction {
+	fi := newFileInfo(opts)
+	return &FileAction{
+		src: &input,
+		action: &pb.FileAction{Action: &pb.FileAction_Copy{Copy: &pb.FileActionCopy{
+			Src:             src,
+			Dest:            dest,
+			Owner:           fi.owner,
+			Mode:            fi.mode,
+			FollowSymlink:   fi.followSymlink,
+			DirCopyContents: fi.copyContents,
+			CreateDestPath:  fi.createDestPath,
+			AllowWildcard:   fi.allowWildcard,
+			Timestamp:       fi.timestamp,
+			ContentAttrs:    fi.contentAttrs,
+		}}},
+	}
+}
+
+func (fa *FileAction) Mkdir(p string, mode os.FileMode, opts ...FileOption) *FileAction {
+	return fa.chain(Mkdir(p, mode, opts...))
+}
+
+func (fa *FileAction) Mkfile(p string, mode os.FileMode, dt []byte, opts ...FileOption) *FileAction {
+	return fa.chain(Mkfile(p, mode, dt, opts...))
+}
+
+func (fa *FileAction) Rm(p string, opts ...FileOption) *FileAction {
+	return fa.chain(Rm(p, opts...))
+}
+
+func (fa *FileAction) Symlink(oldpath, newpath string, opts ...FileOption) *FileAction {
+	return fa.chain(Symlink(oldpath, newpath, opts...))
+}
+
+func (fa *FileAction) Copy(input State, src, dest string, opts ...FileOption) *FileAction {
+	return fa.chain(Copy(input, src, dest, opts...))
+}
+
+func (fa *FileAction) chain(next *FileAction) *FileAction {
+	next.prev = fa
+	return next
+}
+
+func (fa *FileAction) list() []*FileAction {
+	var l []*FileAction
+	for a := fa; a != nil; a = a.prev {
+		l = append([]*FileAction{a}, l...)
+	}
+	return l
+}
+
+// File runs the file actions on the state without a container
+func (s State) File(a *FileAction) State {
+	fo := &FileOp{base: s.Output(), dir: getDir(s), action: a}
+	fo.output = &output{vertex: fo}
+	return s.WithOutput(fo.output)
+}
+
+type FileOp struct {
+	base     Output
+	dir      string
+	action   *FileAction
+	output   Output
+	cachedPB []byte
+}
+
+func (f *FileOp) Validate() error {
+	if f.action == nil {
+		return errors.Errorf("file op without actions")
+	}
+	for _, a := range f.action.list() {
+		if a.src != nil && a.src.Output() == nil {
+			return errors.Errorf("copy from an empty state is not allowed")
+		}
+	}
+	for _, inp := range f.Inputs() {
+		if err := inp.Vertex().Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// absPath resolves p from the working directory dir. The trailing slash of
+// a directory is kept.
+func absPath(dir, p string) string {
+	if path.IsAbs(p) {
+		return p
+	}
+	abs := path.Join("/", dir, p)
+	if strings.HasSuffix(p, "/") && abs != "/" {
+		abs += "/"
+	}
+	return abs
+}
+
+func (f *FileOp) Marshal() ([]byte, error) {
+	if f.cachedPB != nil {
+		return f.cachedPB, nil
+	}
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	pfo := &pb.FileOp{}
+	pop := &pb.Op{
+		Op: &pb.Op_File{
+			File: pfo,
+		},
+	}
+
+	addInput := func(o Output) (pb.InputIndex, error) {
+		if o == nil {
+			return pb.Empty, nil
+		}
+		inp, err := o.ToInput()
+		if err != nil {
+			return 0, err
+		}
+		for i, inp2 := range pop.Inputs {
+			if *inp == *inp2 {
+				return pb.InputIndex(i), nil
+			}
+		}
+		pop.Inputs = append(pop.Inputs, inp)
+		return pb.InputIndex(len(pop.Inputs) - 1), nil
+	}
+
+	// the inputs are added before the actions so the results of the actions
+	// can be indexed after them
+	actions := f.action.list()
+	base, err := addInput(f.base)
+	if err != nil {
+		return nil, err
+	}
+	srcs := make([]pb.InputIndex, len(actions))
+	for i, a := range actions {
+		srcs[i] = pb.Empty
+		if a.src != nil {
+			if srcs[i], err = addInput(a.src.Output()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for i, a := range actions {
+		pa := &pb.FileAction{
+			Input:          base,
+			SecondaryInput: srcs[i],
+			Output:         pb.SkipOutput,
+		}
+		if i > 0 {
+			pa.Input = pb.InputIndex(len(pop.Inputs) + i - 1)
+		}
+		if i == len(actions)-1 {
+			pa.Output = 0
+		}
+		switch act := a.action.Action.(type) {
+		case *pb.FileAction_Mkdir:
+			m := *act.Mkdir
+			m.Path = absPath(f.dir, m.Path)
+			pa.Action = &pb.FileAction_Mkdir{Mkdir: &m}
+		case *pb.FileAction_Mkfile:
+			m := *act.Mkfile
+			m.Path = absPath(f.dir, m.Path)
+			pa.Action = &pb.FileAction_Mkfile{Mkfile: &m}
+		case *pb.FileAction_Rm:
+			r := *act.Rm
+			r.Path = absPath(f.dir, r.Path)
+			pa.Action = &pb.FileAction_Rm{Rm: &r}
+		case *pb.FileAction_Symlink:
+			s := *act.Symlink
+			s.Newpath = absPath(f.dir, s.Newpath)
+			pa.Action = &pb.FileAction_Symlink{Symlink: &s}
+		case *pb.FileAction_Copy:
+			c := *act.Copy
+			c.Src = absPath(getDir(*a.src), c.Src)
+			c.Dest = absPath(f.dir, c.Dest)
+			pa.Action = &pb.FileAction_Copy{Copy: &c}
+		}
+		pfo.Actions = append(pfo.Actions, pa)
+	}
+
+	dt, err := pop.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	f.cachedPB = dt
+	return dt, nil
+}
+
+func (f *FileOp) Output() Output {
+	return f.output
+}
+
+func (f *FileOp) Inputs() (inputs []Output) {
+	mm := map[Output]struct{}{}
+	if f.base != nil {
+		mm[f.base] = struct{}{}
+		inputs = append(inputs, f.base)
+	}
+	for _, a := range f.action.list() {
+		if a.src == nil || a.src.Output() == nil {
+			continue
+		}
+		if _, ok := mm[a.src.Output()]; !ok {
+			mm[a.src.Output()] = struct{}{}
+			inputs = append(inputs, a.src.Output())
+		}
+	}
+	return
+}
@@ -0,0 +1,60 @@
+package llb
+
+import (
+	"testing"
+
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileOpMarshal(t *testing.T) {
+	src := Image("docker.io/library/busybox:latest").Dir("/src")
+	st := Scratch().Dir("/work").File(
+		Mkdir("dir", 0700, WithParents()).
+			Copy(src, "foo", "dest/", CreateDestPath()).
+			Rm("/tmp/*", AllowWildcard()),
+	)
+
+	def, err := st.Marshal()
+	require.NoError(t, err)
+	require.Equal(t, 3, len(def))
+
+	var op pb.Op
+	require.NoError(t, op.Unmarshal(def[1]))
+	f, ok := op.Op.(*pb.Op_File)
+	require.True(t, ok)
+	require.Equal(t, 1, len(op.Inputs))
+	require.Equal(t, 3, len(f.File.Actions))
+
+	a := f.File.Actions
+	require.Equal(t, pb.Empty, a[0].Input)
+	require.Equal(t, pb.SkipOutput, a[0].Output)
+	require.Equal(t, "/work/dir", a[0].GetMkdir().Path)
+	require.True(t, a[0].GetMkdir().MakeParents)
+
+	require.Equal(t, pb.InputIndex(1), a[1].Input)
+	require.Equal(t, pb.InputIndex(0), a[1].SecondaryInput)
+	require.Equal(t, "/src/foo", a[1].GetCopy().Src)
+	require.Equal(t, "/work/dest/", a[1].GetCopy().Dest)
+	require.True(t, a[1].GetCopy().CreateDestPath)
+
+	require.Equal(t, pb.InputIndex(2), a[2].Input)
+	require.Equal(t, pb.Empty, a[2].SecondaryInput)
+	require.Equal(t, pb.OutputIndex(0), a[2].Output)
+	require.Equal(t, "/tmp/*", a[2].GetRm().Path)
+	require.True(t, a[2].GetRm().AllowWildcard)
+
+	_, err = Scratch().File(Copy(Scratch(), "foo", "bar")).Marshal()
+	require.Error(t, err)
+
+	def, err = Scratch().File(Mkfile("/etc/app/run.sh", 0755, []byte("#!/bin/sh\n"), WithParents(), WithUIDGID(1, 2))).Marshal()
+	require.NoError(t, err)
+	require.NoError(t, op.Unmarshal(def[0]))
+	require.Equal(t, &pb.FileActionMkFile{
+		Path:        "/etc/app/run.sh",
+		Mode:        0755,
+		Data:        []byte("#!/bin/sh\n"),
+		Owner:       &pb.ChownOpt{Uid: 1, Gid: 2},
+		MakeParents: true,
+	}, op.GetFile().Actions[0].GetMkfile())
+}
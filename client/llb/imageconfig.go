@@ -0,0 +1,40 @@
+package llb
+
+import (
+	"encoding/json"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// ImageConfig returns the JSON image config with the metadata of the state:
+// the env, working dir, user, entrypoint, cmd, labels and exposed ports. It
+// can be passed to the image exporter with the result of the state as the
+// "containerimage.config" exporter attribute. The layers are set by the
+// exporter.
+func (s State) ImageConfig() ([]byte, error) {
+	var img ocispec.Image
+	if p := s.GetPlatform(); p != nil {
+		img.Architecture = p.Architecture
+		img.OS = p.OS
+	}
+	if env := getEnv(s); len(env) > 0 {
+		img.Config.Env = env.ToArray()
+	}
+	img.Config.WorkingDir = getDir(s)
+	img.Config.User = getUser(s)
+	img.Config.Entrypoint = getEntrypoint(s)
+	img.Config.Cmd = getCmd(s)
+	img.Config.Labels = getLabels(s)
+	if ports := getPorts(s); len(ports) > 0 {
+		img.Config.ExposedPorts = map[string]struct{}{}
+		for _, p := range ports {
+			img.Config.ExposedPorts[p] = struct{}{}
+		}
+	}
+	dt, err := json.Marshal(img)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal image config")
+	}
+	return dt, nil
+}
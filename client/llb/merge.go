@@ -0,0 +1,108 @@
+package llb
+
+import (
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/pkg/errors"
+)
+
+// Merge layers the filesystems of the states on top of each other. The files
+// of the later states replace the files of the earlier ones. Scratch states
+// are ignored. The metadata of the result is taken from the last state.
+func Merge(states []State) State {
+	var inputs []State
+	for _, s := range states {
+		if s.Output() != nil {
+			inputs = append(inputs, s)
+		}
+	}
+	if len(inputs) == 0 {
+		if len(states) == 0 {
+			return Scratch()
+		}
+		return states[len(states)-1]
+	}
+	if len(inputs) == 1 {
+		return inputs[0]
+	}
+	m := &MergeOp{}
+	for _, s := range inputs {
+		m.inputs = append(m.inputs, s.Output())
+	}
+	m.output = &output{vertex: m}
+	return states[len(states)-1].WithOutput(m.output)
+}
+
+type MergeOp struct {
+	inputs   []Output
+	output   Output
+	cachedPB []byte
+}
+
+func (m *MergeOp) Validate() error {
+	if len(m.inputs) < 2 {
+		return errors.Errorf("merge op requires at least two inputs")
+	}
+	for _, inp := range m.inputs {
+		if err := inp.Vertex().Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MergeOp) Marshal() ([]byte, error) {
+	if m.cachedPB != nil {
+		return m.cachedPB, nil
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	pm := &pb.MergeOp{}
+	pop := &pb.Op{
+		Op: &pb.Op_Merge{
+			Merge: pm,
+		},
+	}
+	// the same input can be layered more than once
+	for _, o := range m.inputs {
+		inp, err := o.ToInput()
+		if err != nil {
+			return nil, err
+		}
+		idx := -1
+		for i, inp2 := range pop.Inputs {
+			if *inp == *inp2 {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			pop.Inputs = append(pop.Inputs, inp)
+			idx = len(pop.Inputs) - 1
+		}
+		pm.Inputs = append(pm.Inputs, &pb.MergeInput{Input: pb.InputIndex(idx)})
+	}
+
+	dt, err := pop.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	m.cachedPB = dt
+	return dt, nil
+}
+
+func (m *MergeOp) Output() Output {
+	return m.output
+}
+
+func (m *MergeOp) Inputs() (inputs []Output) {
+	mm := map[Output]struct{}{}
+	for _, o := range m.inputs {
+		if _, ok := mm[o]; !ok {
+			mm[o] = struct{}{}
+			inputs = append(inputs, o)
+		}
+	}
+	return
+}
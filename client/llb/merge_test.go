@@ -0,0 +1,30 @@
+package llb
+
+import (
+	"testing"
+
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeMarshal(t *testing.T) {
+	a := Image("docker.io/library/busybox:latest")
+	b := Local("context")
+
+	st := Merge([]State{a, Scratch(), b, a.Dir("/work")})
+	require.Equal(t, "/work", getDir(st))
+
+	def, err := st.Marshal()
+	require.NoError(t, err)
+	require.Equal(t, 4, len(def))
+
+	var op pb.Op
+	require.NoError(t, op.Unmarshal(def[2]))
+	m, ok := op.Op.(*pb.Op_Merge)
+	require.True(t, ok)
+	require.Equal(t, 2, len(op.Inputs))
+	require.Equal(t, []*pb.MergeInput{{Input: 0}, {Input: 1}, {Input: 0}}, m.Merge.Inputs)
+
+	require.Equal(t, b, Merge([]State{Scratch(), b}))
+	require.Nil(t, Merge(nil).Output())
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/google/shlex"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 type contextKeyT string
@@ -12,6 +13,16 @@ var (
 	keyArgs = contextKeyT("llb.exec.args")
 	keyDir  = contextKeyT("llb.exec.dir")
 	keyEnv  = contextKeyT("llb.exec.env")
+	keyUser = contextKeyT("llb.exec.user")
+
+	keyHostname = contextKeyT("llb.exec.hostname")
+
+	keyEntrypoint = contextKeyT("llb.image.entrypoint")
+	keyCmd        = contextKeyT("llb.image.cmd")
+	keyLabels     = contextKeyT("llb.image.labels")
+	keyPorts      = contextKeyT("llb.image.ports")
+
+	keyPlatform = contextKeyT("llb.platform")
 )
 
 func addEnv(key, value string) StateOption {
@@ -42,6 +53,26 @@ func dirf(str string, v ...interface{}) StateOption {
 	}
 }
 
+func user(str string) StateOption {
+	return func(s State) State {
+		return s.WithValue(keyUser, str)
+	}
+}
+
+func hostname(str string) StateOption {
+	return func(s State) State {
+		return s.WithValue(keyHostname, str)
+	}
+}
+
+func getHostname(s State) string {
+	v := s.Value(keyHostname)
+	if v != nil {
+		return v.(string)
+	}
+	return ""
+}
+
 func reset(s_ State) StateOption {
 	return func(s State) State {
 		s = NewState(s.Output())
@@ -66,6 +97,96 @@ func getDir(s State) string {
 	return ""
 }
 
+func getUser(s State) string {
+	v := s.Value(keyUser)
+	if v != nil {
+		return v.(string)
+	}
+	return ""
+}
+
+func entrypoint(args ...string) StateOption {
+	return func(s State) State {
+		return s.WithValue(keyEntrypoint, args)
+	}
+}
+
+func getEntrypoint(s State) []string {
+	v := s.Value(keyEntrypoint)
+	if v != nil {
+		return v.([]string)
+	}
+	return nil
+}
+
+func cmd(args ...string) StateOption {
+	return func(s State) State {
+		return s.WithValue(keyCmd, args)
+	}
+}
+
+func getCmd(s State) []string {
+	v := s.Value(keyCmd)
+	if v != nil {
+		return v.([]string)
+	}
+	return nil
+}
+
+func addLabel(key, value string) StateOption {
+	return func(s State) State {
+		labels := map[string]string{}
+		for k, v := range getLabels(s) {
+			labels[k] = v
+		}
+		labels[key] = value
+		return s.WithValue(keyLabels, labels)
+	}
+}
+
+func getLabels(s State) map[string]string {
+	v := s.Value(keyLabels)
+	if v != nil {
+		return v.(map[string]string)
+	}
+	return nil
+}
+
+func expose(port string) StateOption {
+	return func(s State) State {
+		ports := getPorts(s)
+		for _, p := range ports {
+			if p == port {
+				return s
+			}
+		}
+		return s.WithValue(keyPorts, append(append([]string(nil), ports...), port))
+	}
+}
+
+func getPorts(s State) []string {
+	v := s.Value(keyPorts)
+	if v != nil {
+		return v.([]string)
+	}
+	return nil
+}
+
+func platform(p ocispec.Platform) StateOption {
+	return func(s State) State {
+		return s.WithValue(keyPlatform, p)
+	}
+}
+
+func getPlatform(s State) *ocispec.Platform {
+	v := s.Value(keyPlatform)
+	if v != nil {
+		p := v.(ocispec.Platform)
+		return &p
+	}
+	return nil
+}
+
 func getArgs(s State) []string {
 	v := s.Value(keyArgs)
 	if v != nil {
@@ -0,0 +1,65 @@
+package llb
+
+import (
+	"testing"
+
+	"github.com/moby/buildkit/solver/pb"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlatformMarshal(t *testing.T) {
+	arm64 := ocispec.Platform{OS: "linux", Architecture: "arm64"}
+
+	s := Image("docker.io/library/busybox:latest", ImagePlatform(arm64))
+	require.Equal(t, &arm64, s.GetPlatform())
+	require.Nil(t, Image("docker.io/library/busybox:latest").GetPlatform())
+
+	def, err := s.Run(Shlex("true")).Root().Marshal()
+	require.NoError(t, err)
+
+	var source, exec *pb.Op
+	for _, dt := range def {
+		op := &pb.Op{}
+		require.NoError(t, op.Unmarshal(dt))
+		switch op.Op.(type) {
+		case *pb.Op_Source:
+			source = op
+		case *pb.Op_Exec:
+			exec = op
+		}
+	}
+	require.NotNil(t, source)
+	require.NotNil(t, exec)
+	require.Equal(t, pb.PlatformFromSpec(arm64), source.Platform)
+	require.Equal(t, pb.PlatformFromSpec(arm64), exec.Platform)
+
+	// the platform of the state is used for the processes run on it
+	armv7 := ocispec.Platform{OS: "linux", Architecture: "arm", Variant: "v7"}
+	def, err = s.Platform(armv7).Run(Shlex("true")).Root().Marshal()
+	require.NoError(t, err)
+	op := &pb.Op{}
+	require.NoError(t, op.Unmarshal(def[len(def)-2]))
+	_, ok := op.Op.(*pb.Op_Exec)
+	require.True(t, ok)
+	require.Equal(t, pb.PlatformFromSpec(armv7), op.Platform)
+}
+
+func TestWorkerFilterMarshal(t *testing.T) {
+	filter := `labels."org.mobyproject.buildkit.worker.executor"==runc`
+	def, err := Image("docker.io/library/busybox:latest").Run(Shlex("true"), WorkerFilter(filter)).Root().Marshal()
+	require.NoError(t, err)
+
+	op := &pb.Op{}
+	require.NoError(t, op.Unmarshal(def[len(def)-2]))
+	_, ok := op.Op.(*pb.Op_Exec)
+	require.True(t, ok)
+	require.NotNil(t, op.Constraints)
+	require.Equal(t, []string{filter}, op.Constraints.Filter)
+
+	def, err = Image("docker.io/library/busybox:latest").Run(Shlex("true")).Root().Marshal()
+	require.NoError(t, err)
+	op = &pb.Op{}
+	require.NoError(t, op.Unmarshal(def[len(def)-2]))
+	require.Nil(t, op.Constraints)
+}
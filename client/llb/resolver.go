@@ -10,10 +10,12 @@ import (
 	"github.com/BurntSushi/locker"
 	"github.com/containerd/containerd/content"
 	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/platforms"
 	"github.com/containerd/containerd/remotes"
 	"github.com/containerd/containerd/remotes/docker"
 	"github.com/moby/buildkit/util/imageutil"
 	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 	netcontext "golang.org/x/net/context"
 )
@@ -31,8 +33,11 @@ func WithDefaultMetaResolver(ii *ImageInfo) {
 	ii.metaResolver = DefaultImageMetaResolver()
 }
 
+// ImageMetaResolver resolves the manifest digest and the config of an image.
+// The image of the default platform is used from a manifest list if platform
+// is nil.
 type ImageMetaResolver interface {
-	ResolveImageConfig(ctx netcontext.Context, ref string) ([]byte, error)
+	ResolveImageConfig(ctx netcontext.Context, ref string, platform *ocispec.Platform) (digest.Digest, []byte, error)
 }
 
 func NewImageMetaResolver() ImageMetaResolver {
@@ -41,7 +46,7 @@ func NewImageMetaResolver() ImageMetaResolver {
 			Client: http.DefaultClient,
 		}),
 		ingester: newInMemoryIngester(),
-		cache:    map[string][]byte{},
+		cache:    map[string]resolveResult{},
 		locker:   locker.NewLocker(),
 	}
 }
@@ -57,24 +62,33 @@ type imageMetaResolver struct {
 	resolver remotes.Resolver
 	ingester *inMemoryIngester
 	locker   *locker.Locker
-	cache    map[string][]byte
+	cache    map[string]resolveResult
 }
 
-func (imr *imageMetaResolver) ResolveImageConfig(ctx netcontext.Context, ref string) ([]byte, error) {
-	imr.locker.Lock(ref)
-	defer imr.locker.Unlock(ref)
+type resolveResult struct {
+	dgst   digest.Digest
+	config []byte
+}
+
+func (imr *imageMetaResolver) ResolveImageConfig(ctx netcontext.Context, ref string, platform *ocispec.Platform) (digest.Digest, []byte, error) {
+	key := ref
+	if platform != nil {
+		key += "@" + platforms.Format(*platform)
+	}
+	imr.locker.Lock(key)
+	defer imr.locker.Unlock(key)
 
-	if img, ok := imr.cache[ref]; ok {
-		return img, nil
+	if res, ok := imr.cache[key]; ok {
+		return res.dgst, res.config, nil
 	}
 
-	img, err := imageutil.Config(ctx, ref, imr.resolver, imr.ingester)
+	dgst, config, err := imageutil.Config(ctx, ref, imr.resolver, imr.ingester, platform)
 	if err != nil {
-		return nil, err
+		return "", nil, err
 	}
 
-	imr.cache[ref] = img
-	return img, nil
+	imr.cache[key] = resolveResult{dgst: dgst, config: config}
+	return dgst, config, nil
 }
 
 type inMemoryIngester struct {
@@ -4,10 +4,13 @@ import (
 	"context"
 	_ "crypto/sha256"
 	"encoding/json"
+	"os"
+	"strconv"
 	"strings"
 
 	"github.com/docker/distribution/reference"
 	"github.com/moby/buildkit/solver/pb"
+	digest "github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 )
@@ -15,6 +18,7 @@ import (
 type SourceOp struct {
 	id       string
 	attrs    map[string]string
+	platform *ocispec.Platform
 	output   Output
 	cachedPB []byte
 	err      error
@@ -52,6 +56,9 @@ func (s *SourceOp) Marshal() ([]byte, error) {
 			Source: &pb.SourceOp{Identifier: s.id, Attrs: s.attrs},
 		},
 	}
+	if s.platform != nil {
+		proto.Platform = pb.PlatformFromSpec(*s.platform)
+	}
 	dt, err := proto.Marshal()
 	if err != nil {
 		return nil, err
@@ -85,8 +92,16 @@ func Image(ref string, opts ...ImageOption) State {
 	for _, opt := range opts {
 		opt(&info)
 	}
+	src.platform = info.platform
+	newState := func() State {
+		st := NewState(src.Output())
+		if info.platform != nil {
+			st = st.Platform(*info.platform)
+		}
+		return st
+	}
 	if info.metaResolver != nil {
-		dt, err := info.metaResolver.ResolveImageConfig(context.TODO(), ref)
+		_, dt, err := info.metaResolver.ResolveImageConfig(context.TODO(), ref, info.platform)
 		if err != nil {
 			src.err = err
 		} else {
@@ -94,7 +109,7 @@ func Image(ref string, opts ...ImageOption) State {
 			if err := json.Unmarshal(dt, &img); err != nil {
 				src.err = err
 			} else {
-				st := NewState(src.Output())
+				st := newState()
 				for _, env := range img.Config.Env {
 					parts := strings.SplitN(env, "=", 2)
 					if len(parts[0]) > 0 {
@@ -110,13 +125,22 @@ func Image(ref string, opts ...ImageOption) State {
 			}
 		}
 	}
-	return NewState(src.Output())
+	return newState()
 }
 
 type ImageOption func(*ImageInfo)
 
 type ImageInfo struct {
 	metaResolver ImageMetaResolver
+	platform     *ocispec.Platform
+}
+
+// ImagePlatform selects the image for platform p from a manifest list. The
+// execs on the image run for the same platform.
+func ImagePlatform(p ocispec.Platform) ImageOption {
+	return func(ii *ImageInfo) {
+		ii.platform = &p
+	}
 }
 
 func Git(remote, ref string, opts ...GitOption) State {
@@ -167,6 +191,15 @@ func Local(name string, opts ...LocalOption) State {
 	if gi.IncludePatterns != "" {
 		attrs[pb.AttrIncludePatterns] = gi.IncludePatterns
 	}
+	if gi.ExcludePatterns != "" {
+		attrs[pb.AttrExcludePatterns] = gi.ExcludePatterns
+	}
+	if gi.FollowPaths != "" {
+		attrs[pb.AttrFollowPaths] = gi.FollowPaths
+	}
+	if gi.ExcludeXattrs {
+		attrs[pb.AttrExcludeXattrs] = "true"
+	}
 
 	source := NewSource("local://"+name, attrs)
 	return NewState(source.Output())
@@ -187,7 +220,143 @@ func IncludePatterns(p []string) LocalOption {
 	}
 }
 
+func ExcludePatterns(p []string) LocalOption {
+	return func(li *LocalInfo) {
+		dt, _ := json.Marshal(p) // empty on error
+		li.ExcludePatterns = string(dt)
+	}
+}
+
+// FollowPaths are always part of the content checksum of the local source.
+// Symlinks on these paths are resolved.
+func FollowPaths(p []string) LocalOption {
+	return func(li *LocalInfo) {
+		dt, _ := json.Marshal(p) // empty on error
+		li.FollowPaths = string(dt)
+	}
+}
+
+// ExcludeXattrs leaves the extended attributes of the files out of the
+// content checksum of the local source
+func ExcludeXattrs() LocalOption {
+	return func(li *LocalInfo) {
+		li.ExcludeXattrs = true
+	}
+}
+
 type LocalInfo struct {
 	SessionID       string
 	IncludePatterns string
+	ExcludePatterns string
+	FollowPaths     string
+	ExcludeXattrs   bool
+}
+
+// HTTP downloads url into a single file
+func HTTP(url string, opts ...HTTPOption) State {
+	hi := &HTTPInfo{}
+	for _, o := range opts {
+		o(hi)
+	}
+	attrs := map[string]string{}
+	if hi.Checksum != "" {
+		attrs[pb.AttrHTTPChecksum] = hi.Checksum.String()
+	}
+	if hi.Filename != "" {
+		attrs[pb.AttrHTTPFilename] = hi.Filename
+	}
+	if hi.Perm != 0 {
+		attrs[pb.AttrHTTPPerm] = "0" + strconv.FormatInt(int64(hi.Perm), 8)
+	}
+	if hi.UID != 0 {
+		attrs[pb.AttrHTTPUID] = strconv.Itoa(hi.UID)
+	}
+	if hi.GID != 0 {
+		attrs[pb.AttrHTTPGID] = strconv.Itoa(hi.GID)
+	}
+
+	source := NewSource(url, attrs)
+	return NewState(source.Output())
+}
+
+type HTTPInfo struct {
+	Checksum digest.Digest
+	Filename string
+	Perm     int
+	UID      int
+	GID      int
+}
+
+type HTTPOption func(*HTTPInfo)
+
+// Checksum sets the expected digest of the downloaded file. The download is
+// cached by the checksum instead of revalidating the URL.
+func Checksum(dgst digest.Digest) HTTPOption {
+	return func(hi *HTTPInfo) {
+		hi.Checksum = dgst
+	}
+}
+
+// Chmod sets the permissions of the downloaded file
+func Chmod(perm os.FileMode) HTTPOption {
+	return func(hi *HTTPInfo) {
+		hi.Perm = int(perm) & 0777
+	}
+}
+
+// Filename sets the name of the downloaded file. Defaults to the last
+// element of the URL path.
+func Filename(name string) HTTPOption {
+	return func(hi *HTTPInfo) {
+		hi.Filename = name
+	}
+}
+
+// Chown sets the owner of the downloaded file
+func Chown(uid, gid int) HTTPOption {
+	return func(hi *HTTPInfo) {
+		hi.UID = uid
+		hi.GID = gid
+	}
+}
+
+// OCILayout uses an image from an OCI image layout directory. The reference
+// is the path of the layout, relative to the OCI layout root of the daemon,
+// followed by an optional :tag or @digest.
+func OCILayout(ref string, opts ...OCILayoutOption) State {
+	oi := &OCILayoutInfo{}
+	for _, o := range opts {
+		o(oi)
+	}
+	attrs := map[string]string{}
+	if oi.Local {
+		attrs[pb.AttrOCILayoutLocal] = "true"
+	}
+	if oi.SessionID != "" {
+		attrs[pb.AttrLocalSessionID] = oi.SessionID
+	}
+
+	source := NewSource("oci-layout://"+ref, attrs)
+	return NewState(source.Output())
+}
+
+type OCILayoutOption func(*OCILayoutInfo)
+
+type OCILayoutInfo struct {
+	Local     bool
+	SessionID string
+}
+
+// FromLocalDir reads the OCI layout from the local directory of the client
+// with the name of the path instead of the filesystem of the daemon
+func FromLocalDir() OCILayoutOption {
+	return func(oi *OCILayoutInfo) {
+		oi.Local = true
+	}
+}
+
+func OCILayoutSessionID(id string) OCILayoutOption {
+	return func(oi *OCILayoutInfo) {
+		oi.SessionID = id
+	}
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/moby/buildkit/solver/pb"
 	"github.com/moby/buildkit/util/system"
 	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 type StateOption func(State) State
@@ -116,12 +117,39 @@ func (s State) Run(ro ...RunOption) ExecState {
 		Args: getArgs(ei.State),
 		Cwd:  getDir(ei.State),
 		Env:  getEnv(ei.State),
+		User: getUser(ei.State),
+
+		Hostname:   getHostname(ei.State),
+		ExtraHosts: ei.ExtraHosts,
 	}
 
 	exec := NewExecOp(s.Output(), meta, ei.ReadonlyRootFS)
+	exec.platform = getPlatform(ei.State)
+	exec.contentCacheRoot = ei.ContentCacheRoot
+	exec.contentAttrs = ei.ContentAttrs
+	exec.timeout = ei.Timeout
+	exec.checkpoint = ei.CheckpointInterval
+	exec.retry = ei.Retry
+	exec.noProxyEnv = ei.NoProxyEnv
+	exec.proxyEnv = ei.ProxyEnv
+	exec.network = ei.Network
+	exec.security = ei.Security
+	exec.devices = ei.Devices
+	exec.securityProfile = ei.SecurityProfile
+	exec.workerFilter = ei.WorkerFilter
+	if ei.Resources.Size() > 0 {
+		r := ei.Resources
+		exec.resources = &r
+	}
 	for _, m := range ei.Mounts {
 		exec.AddMount(m.Target, m.Source, m.Opts...)
 	}
+	for _, s := range ei.Secrets {
+		exec.AddSecret(s.Target, s.Opts...)
+	}
+	for _, s := range ei.SSH {
+		exec.AddSSHSocket(s.Opts...)
+	}
 
 	return ExecState{
 		State: s.WithOutput(exec.Output()),
@@ -144,6 +172,53 @@ func (s State) Dirf(str string, v ...interface{}) State {
 	return dirf(str, v...)(s)
 }
 
+// User sets the user, and optionally the group as user:group, that the
+// following execs run as
+func (s State) User(str string) State {
+	return user(str)(s)
+}
+
+// Hostname sets the hostname of the following execs
+func (s State) Hostname(str string) State {
+	return hostname(str)(s)
+}
+
+// Platform sets the platform that the following execs run for. The platform
+// of the daemon is used if it isn't set.
+func (s State) Platform(p ocispec.Platform) State {
+	return platform(p)(s)
+}
+
+// Entrypoint sets the entrypoint of the image config of the state. It
+// doesn't change the args of the following execs.
+func (s State) Entrypoint(args ...string) State {
+	return entrypoint(args...)(s)
+}
+
+// Cmd sets the default arguments of the image config of the state
+func (s State) Cmd(args ...string) State {
+	return cmd(args...)(s)
+}
+
+// AddLabel adds a label to the image config of the state
+func (s State) AddLabel(key, value string) State {
+	return addLabel(key, value)(s)
+}
+
+// Expose adds a port, eg. "80/tcp", to the exposed ports of the image config
+// of the state
+func (s State) Expose(port string) State {
+	return expose(port)(s)
+}
+
+func (s State) GetPlatform() *ocispec.Platform {
+	return getPlatform(s)
+}
+
+func (s State) GetUser() string {
+	return getUser(s)
+}
+
 func (s State) GetEnv(key string) (string, bool) {
 	return getEnv(s).Get(key)
 }
@@ -156,6 +231,19 @@ func (s State) GetArgs() []string {
 	return getArgs(s)
 }
 
+func (s State) GetEntrypoint() []string {
+	return getEntrypoint(s)
+}
+
+func (s State) GetCmd() []string {
+	return getCmd(s)
+}
+
+func (s State) GetLabel(key string) (string, bool) {
+	v, ok := getLabels(s)[key]
+	return v, ok
+}
+
 func (s State) Reset(s2 State) State {
 	return reset(s2)(s)
 }
@@ -1,8 +1,10 @@
 package llb
 
 import (
+	"encoding/json"
 	"testing"
 
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -16,6 +18,10 @@ func TestStateMeta(t *testing.T) {
 
 	assert.Equal(t, "/foo/bar", s.GetDir())
 
+	assert.Equal(t, "", s.GetUser())
+	s = s.User("daemon:adm")
+	assert.Equal(t, "daemon:adm", s.GetUser())
+
 	s2 := Source("foo2")
 	s2 = s2.AddEnv("BAZ", "def").Reset(s)
 
@@ -26,3 +32,36 @@ func TestStateMeta(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, "abc", v)
 }
+
+func TestStateImageConfig(t *testing.T) {
+	s := Image("foo").
+		AddEnv("PATH", "/bin").
+		Dir("/app").
+		User("daemon").
+		Entrypoint("/bin/app").
+		Cmd("--serve").
+		AddLabel("a", "1").
+		Expose("80/tcp").
+		Expose("80/tcp")
+	s2 := s.AddLabel("b", "2")
+
+	_, ok := s.GetLabel("b")
+	assert.False(t, ok)
+	v, ok := s2.GetLabel("a")
+	assert.True(t, ok)
+	assert.Equal(t, "1", v)
+
+	dt, err := s2.Platform(ocispec.Platform{OS: "linux", Architecture: "arm64"}).ImageConfig()
+	assert.NoError(t, err)
+	var img ocispec.Image
+	assert.NoError(t, json.Unmarshal(dt, &img))
+	assert.Equal(t, "arm64", img.Architecture)
+	assert.Equal(t, "linux", img.OS)
+	assert.Equal(t, []string{"PATH=/bin"}, img.Config.Env)
+	assert.Equal(t, "/app", img.Config.WorkingDir)
+	assert.Equal(t, "daemon", img.Config.User)
+	assert.Equal(t, []string{"/bin/app"}, img.Config.Entrypoint)
+	assert.Equal(t, []string{"--serve"}, img.Config.Cmd)
+	assert.Equal(t, map[string]string{"a": "1", "b": "2"}, img.Config.Labels)
+	assert.Equal(t, map[string]struct{}{"80/tcp": {}}, img.Config.ExposedPorts)
+}
@@ -0,0 +1,86 @@
+package client
+
+import (
+	"context"
+	"io"
+	"time"
+
+	controlapi "github.com/moby/buildkit/api/services/control"
+	"github.com/pkg/errors"
+)
+
+// Prune removes the cache records that are not in use. The removed records
+// are sent to ch while the prune progresses. ch is closed when Prune returns.
+func (c *Client) Prune(ctx context.Context, ch chan UsageInfo, opts ...PruneOption) error {
+	defer func() {
+		if ch != nil {
+			close(ch)
+		}
+	}()
+
+	info := &PruneInfo{}
+	for _, o := range opts {
+		o(info)
+	}
+
+	req := &controlapi.PruneRequest{
+		Filter:       info.Filter,
+		KeepDuration: int64(info.KeepDuration),
+		KeepBytes:    info.KeepBytes,
+	}
+	stream, err := c.controlClient().Prune(ctx, req)
+	if err != nil {
+		return errors.Wrap(err, "failed to call prune")
+	}
+
+	for {
+		d, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return errors.Wrap(err, "failed to receive prune progress")
+		}
+		if ch != nil {
+			select {
+			case ch <- fromControlUsageRecord(d):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+type PruneOption func(*PruneInfo)
+
+type PruneInfo struct {
+	// Filter selects the records to remove. The values are in key=value
+	// format where the key is one of id (prefix of the record ID),
+	// description (substring of the description), type (mutable or
+	// immutable) or unused-for (duration since the last use, eg. 24h). A
+	// value without a key is matched as an ID prefix.
+	Filter []string
+	// KeepDuration keeps the records that have been used within the duration
+	KeepDuration time.Duration
+	// KeepBytes stops removing the least recently used records once the
+	// total size of the cache is below the limit
+	KeepBytes int64
+}
+
+func WithPruneFilter(f ...string) PruneOption {
+	return func(pi *PruneInfo) {
+		pi.Filter = append(pi.Filter, f...)
+	}
+}
+
+func WithKeepDuration(d time.Duration) PruneOption {
+	return func(pi *PruneInfo) {
+		pi.KeepDuration = d
+	}
+}
+
+func WithKeepBytes(b int64) PruneOption {
+	return func(pi *PruneInfo) {
+		pi.KeepBytes = b
+	}
+}
@@ -0,0 +1,32 @@
+package client
+
+import (
+	"context"
+
+	controlapi "github.com/moby/buildkit/api/services/control"
+	"github.com/pkg/errors"
+)
+
+// QueueStatus is the state of the queue of the builds of the daemon
+type QueueStatus struct {
+	// Position is the position of the build in the queue, starting at 1, or
+	// 0 if the build is running
+	Position int
+	Running  int
+	Waiting  int
+}
+
+// QueueStatus returns the position of the build ref, see SolveOpt.Ref, in
+// the queue of the daemon. It returns an error if the build is neither
+// waiting nor running.
+func (c *Client) QueueStatus(ctx context.Context, ref string) (*QueueStatus, error) {
+	resp, err := c.controlClient().QueueStatus(ctx, &controlapi.QueueStatusRequest{Ref: ref})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get queue status of %s", ref)
+	}
+	return &QueueStatus{
+		Position: int(resp.Position),
+		Running:  int(resp.Running),
+		Waiting:  int(resp.Waiting),
+	}, nil
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,6 +18,7 @@ import (
 	"github.com/moby/buildkit/session/filesync"
 	"github.com/moby/buildkit/session/grpchijack"
 	"github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/util/entitlements"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
@@ -24,59 +27,235 @@ import (
 type SolveOpt struct {
 	Exporter      string
 	ExporterAttrs map[string]string
-	LocalDirs     map[string]string
-	SharedKey     string
-	Frontend      string
+	// ExporterOutput receives the tarball of the tar and oci exporters
+	ExporterOutput io.Writer
+	LocalDirs      map[string]string
+	SharedKey      string
+	Frontend       string
+	// FrontendAttrs are the options of the frontend, eg. "build-arg:<key>",
+	// "target" and "label:<key>" for the Dockerfile frontend. The attributes
+	// are not part of the cache keys themselves, only the definitions the
+	// frontend creates with them are.
 	FrontendAttrs map[string]string
-	// Session string
+	// ExportCache is the registry reference the cache of all the steps of
+	// the build is exported to. It is the same as a CacheExports entry of
+	// the registry type with the max mode.
+	ExportCache string
+	// ImportCache is the registry reference the cache is imported from
+	ImportCache string
+	// CacheImports are the sources the cache is imported from before the
+	// build
+	CacheImports []CacheOptionsEntry
+	// CacheExports are the targets the cache is exported to after the build
+	CacheExports []CacheOptionsEntry
+	Session      []session.Attachable
+	// Results are named results of the build, serialized like the main
+	// definition, that are solved together with it
+	Results map[string][][]byte
+	// FrontendInputs are named definitions that replace inputs of the
+	// frontend, eg. "context" or the name of a base image for the Dockerfile
+	// frontend. The local directories the definitions use are synced like
+	// the ones of the frontend, from LocalDirs.
+	FrontendInputs map[string][][]byte
+	// Exports run more exporters on the results of the build
+	Exports []ExportEntry
+	// Ref is the ID of the build, eg. for QueueStatus, AttachStatus and
+	// CancelJob while the build is running. A random ID is used if it is
+	// empty, WithRefFunc returns it.
+	Ref string
+	// Priority orders the builds that wait in the queue of the daemon.
+	// Higher priorities run first.
+	Priority int
+	// ClientID is sent with the build.
+	//
+	// Deprecated: the daemon identifies the clients for the limits of the
+	// builds per client by the uid of their process or by their TLS
+	// certificate.
+	ClientID string
+	// AllowedEntitlements are requested for the execs of the build. The
+	// build fails if the daemon doesn't allow them.
+	AllowedEntitlements []entitlements.Entitlement
+	// CgroupParent is the cgroup the build steps are created under, relative
+	// to the cgroup of the daemon, eg. the name of a tenant. Its cgroups can
+	// be used to account for the resources of the build and to clean it up.
+	CgroupParent string
 }
 
-func (c *Client) Solve(ctx context.Context, r io.Reader, opt SolveOpt, statusChan chan *SolveStatus) error {
+// ExportEntry runs an exporter on a result of the build
+type ExportEntry struct {
+	// Result is the name of the exported result in SolveOpt.Results. The
+	// main result is exported if it is empty.
+	Result string
+	Type   string
+	Attrs  map[string]string
+	// Output receives the tarball of the tar and oci exporters
+	Output io.Writer
+}
+
+// SolveResponse is the metadata of the exports of a solve
+type SolveResponse struct {
+	// ExporterResponse is the response of the exporter of the main result,
+	// eg. ExporterImageDigest and ExporterImageDescriptor for the image
+	// exporters
+	ExporterResponse map[string]string
+	// Exports are the responses of the exporters of SolveOpt.Exports in the
+	// same order
+	Exports []map[string]string
+	// Summary describes the work done by the build
+	Summary *BuildSummary
+}
+
+// WithRefFunc calls fn with the ref of the build before the build is sent to
+// the daemon, e.g. for QueueStatus, AttachStatus and CancelJob with the
+// random ref that is generated if SolveOpt.Ref is empty
+func WithRefFunc(fn func(ref string)) SolveOption {
+	return func(so *solveOptions) {
+		so.refFunc = fn
+	}
+}
+
+// Solve builds the definition read from r, or the result of opt.Frontend,
+// and runs the exporters on the result. The status of the build is sent to
+// statusChan, which is closed when Solve returns.
+func (c *Client) Solve(ctx context.Context, r io.Reader, opt SolveOpt, statusChan chan *SolveStatus, opts ...SolveOption) (*SolveResponse, error) {
 	defer func() {
 		if statusChan != nil {
 			close(statusChan)
 		}
 	}()
 
+	var so solveOptions
+	for _, o := range opts {
+		o(&so)
+	}
+
+	ref := opt.Ref
+	if ref == "" {
+		ref = generateID()
+	}
+	if so.refFunc != nil {
+		so.refFunc(ref)
+	}
+
 	var def [][]byte
 	var err error
 	if opt.Frontend == "" {
 		def, err = llb.ReadFrom(r)
 		if err != nil {
-			return errors.Wrap(err, "failed to parse input")
+			return nil, errors.Wrap(err, "failed to parse input")
 		}
 
 		if len(def) == 0 {
-			return errors.New("invalid empty definition")
+			return nil, errors.New("invalid empty definition")
 		}
 	}
 
-	syncedDirs, err := prepareSyncedDirs(def, opt.LocalDirs)
+	allDefs := def
+	var results []*controlapi.Result
+	for name, rdef := range opt.Results {
+		if len(rdef) == 0 {
+			return nil, errors.Errorf("invalid empty definition for result %s", name)
+		}
+		results = append(results, &controlapi.Result{Name: name, Definition: rdef})
+		if opt.Frontend == "" {
+			allDefs = append(allDefs, rdef...)
+		}
+	}
+
+	var inputs []*controlapi.Result
+	capDefs := allDefs
+	for name, idef := range opt.FrontendInputs {
+		if opt.Frontend == "" {
+			return nil, errors.Errorf("frontend input %s requires a frontend", name)
+		}
+		if len(idef) == 0 {
+			return nil, errors.Errorf("invalid empty definition for frontend input %s", name)
+		}
+		inputs = append(inputs, &controlapi.Result{Name: name, Definition: idef})
+		capDefs = append(capDefs, idef...)
+	}
+
+	ents := make([]string, 0, len(opt.AllowedEntitlements))
+	for _, e := range opt.AllowedEntitlements {
+		ents = append(ents, string(e))
+	}
+
+	if err := c.checkCapabilities(ctx, capDefs, opt); err != nil {
+		return nil, err
+	}
+
+	syncedDirs, err := prepareSyncedDirs(allDefs, opt.LocalDirs)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	cacheOpt, cacheDirs, targets, err := cacheOptions(opt)
+	if err != nil {
+		return nil, err
+	}
+	syncedDirs = append(syncedDirs, cacheDirs...)
+
+	if opt.Exporter != "" {
+		t, err := exportTarget(opt.Exporter, opt.ExporterAttrs, opt.ExporterOutput)
+		if err != nil {
+			return nil, err
+		}
+		if t != nil {
+			targets = append(targets, *t)
+		}
+	}
+	exports := make([]*controlapi.Export, 0, len(opt.Exports))
+	for i, e := range opt.Exports {
+		if _, ok := opt.Results[e.Result]; !ok && e.Result != "" {
+			return nil, errors.Errorf("result %s not found for export", e.Result)
+		}
+		t, err := exportTarget(e.Type, e.Attrs, e.Output)
+		if err != nil {
+			return nil, err
+		}
+		attrs := e.Attrs
+		if t != nil {
+			// the exporters find the target of their files by its name
+			t.Name = fmt.Sprintf("export-%d", i)
+			attrs = make(map[string]string, len(e.Attrs)+1)
+			for k, v := range e.Attrs {
+				attrs[k] = v
+			}
+			attrs[exporterTargetName] = t.Name
+			targets = append(targets, *t)
+		}
+		exports = append(exports, &controlapi.Export{
+			Result:   e.Result,
+			Exporter: e.Type,
+			Attrs:    attrs,
+		})
 	}
 
-	ref := generateID()
+	clientID := opt.ClientID
+	if clientID == "" {
+		clientID = defaultSessionName()
+	}
 	eg, ctx := errgroup.WithContext(ctx)
+	var res *SolveResponse
 
 	statusContext, cancelStatus := context.WithCancel(context.Background())
 	defer cancelStatus()
 
 	s, err := session.NewSession(defaultSessionName(), opt.SharedKey)
 	if err != nil {
-		return errors.Wrap(err, "failed to create session")
+		return nil, errors.Wrap(err, "failed to create session")
 	}
 
 	if len(syncedDirs) > 0 {
 		s.Allow(filesync.NewFSSyncProvider(syncedDirs))
 	}
 
-	if opt.Exporter == ExporterLocal {
-		outputDir, ok := opt.ExporterAttrs[exporterLocalOutputDir]
-		if !ok {
-			return errors.Errorf("output directory is required for local exporter")
-		}
-		s.Allow(filesync.NewFSSyncTarget(outputDir))
+	if len(targets) > 0 {
+		s.Allow(filesync.NewFSSyncTargets(targets))
+	}
+
+	for _, a := range opt.Session {
+		s.Allow(a)
 	}
 
 	eg.Go(func() error {
@@ -92,76 +271,91 @@ func (c *Client) Solve(ctx context.Context, r io.Reader, opt SolveOpt, statusCha
 			logrus.Debugf("stopping session")
 			s.Close()
 		}()
-		_, err = c.controlClient().Solve(ctx, &controlapi.SolveRequest{
-			Ref:           ref,
-			Definition:    def,
-			Exporter:      opt.Exporter,
-			ExporterAttrs: opt.ExporterAttrs,
-			Session:       s.ID(),
-			Frontend:      opt.Frontend,
-			FrontendAttrs: opt.FrontendAttrs,
+		resp, err := c.controlClient().Solve(ctx, &controlapi.SolveRequest{
+			Ref:            ref,
+			Definition:     def,
+			Exporter:       opt.Exporter,
+			ExporterAttrs:  opt.ExporterAttrs,
+			Session:        s.ID(),
+			Frontend:       opt.Frontend,
+			FrontendAttrs:  opt.FrontendAttrs,
+			Cache:          cacheOpt,
+			DryRun:         so.dryRun,
+			Results:        results,
+			Exports:        exports,
+			Priority:       int32(opt.Priority),
+			Client:         clientID,
+			FrontendInputs: inputs,
+			Entitlements:   ents,
+			CgroupParent:   opt.CgroupParent,
 		})
 		if err != nil {
 			return errors.Wrap(err, "failed to solve")
 		}
+		if so.dryRunReport != nil {
+			*so.dryRunReport = fromControlDryRunReport(resp.DryRun)
+		}
+		res = &SolveResponse{
+			ExporterResponse: resp.ExporterResponse,
+			Summary:          fromControlBuildSummary(resp.Summary),
+		}
+		for _, e := range resp.Exports {
+			res.Exports = append(res.Exports, e.Response)
+		}
 		return nil
 	})
 
 	eg.Go(func() error {
-		stream, err := c.controlClient().Status(statusContext, &controlapi.StatusRequest{
-			Ref: ref,
-		})
+		return c.status(statusContext, ref, statusChan)
+	})
+
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// status sends the status of the build ref to statusChan until the build
+// has completed
+func (c *Client) status(ctx context.Context, ref string, statusChan chan *SolveStatus) error {
+	stream, err := c.controlClient().Status(ctx, &controlapi.StatusRequest{
+		Ref: ref,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to get status")
+	}
+	for {
+		resp, err := stream.Recv()
 		if err != nil {
-			return errors.Wrap(err, "failed to get status")
-		}
-		for {
-			resp, err := stream.Recv()
-			if err != nil {
-				if err == io.EOF {
-					return nil
-				}
-				return errors.Wrap(err, "failed to receive status")
-			}
-			s := SolveStatus{}
-			for _, v := range resp.Vertexes {
-				s.Vertexes = append(s.Vertexes, &Vertex{
-					Digest:    v.Digest,
-					Inputs:    v.Inputs,
-					Name:      v.Name,
-					Started:   v.Started,
-					Completed: v.Completed,
-					Error:     v.Error,
-					Cached:    v.Cached,
-					Parent:    v.Parent,
-				})
-			}
-			for _, v := range resp.Statuses {
-				s.Statuses = append(s.Statuses, &VertexStatus{
-					ID:        v.ID,
-					Vertex:    v.Vertex,
-					Name:      v.Name,
-					Total:     v.Total,
-					Current:   v.Current,
-					Timestamp: v.Timestamp,
-					Started:   v.Started,
-					Completed: v.Completed,
-				})
-			}
-			for _, v := range resp.Logs {
-				s.Logs = append(s.Logs, &VertexLog{
-					Vertex:    v.Vertex,
-					Stream:    int(v.Stream),
-					Data:      v.Msg,
-					Timestamp: v.Timestamp,
-				})
-			}
-			if statusChan != nil {
-				statusChan <- &s
+			if err == io.EOF {
+				return nil
 			}
+			return errors.Wrap(err, "failed to receive status")
 		}
-	})
-
-	return eg.Wait()
+		s := SolveStatus{}
+		for _, v := range resp.Vertexes {
+			s.Vertexes = append(s.Vertexes, fromControlVertex(v))
+		}
+		for _, v := range resp.Statuses {
+			s.Statuses = append(s.Statuses, &VertexStatus{
+				ID:        v.ID,
+				Vertex:    v.Vertex,
+				Name:      v.Name,
+				Total:     v.Total,
+				Current:   v.Current,
+				Timestamp: v.Timestamp,
+				Started:   v.Started,
+				Completed: v.Completed,
+			})
+		}
+		for _, v := range resp.Logs {
+			s.Logs = append(s.Logs, fromControlVertexLog(v))
+		}
+		s.Summary = fromControlBuildSummary(resp.Summary)
+		if statusChan != nil {
+			statusChan <- &s
+		}
+	}
 }
 
 func generateID() string {
@@ -172,6 +366,33 @@ func generateID() string {
 	return hex.EncodeToString(b)
 }
 
+// exportTarget returns the target on the client for the files of an
+// exporter, or nil if the exporter doesn't send files to the client
+func exportTarget(exporter string, attrs map[string]string, w io.Writer) (*filesync.FSSyncTarget, error) {
+	switch exporter {
+	case ExporterLocal:
+		outputDir, ok := attrs[exporterLocalOutputDir]
+		if !ok {
+			return nil, errors.Errorf("output directory is required for local exporter")
+		}
+		t := &filesync.FSSyncTarget{OutDir: outputDir}
+		if v, ok := attrs[exporterLocalIncremental]; ok {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "non-bool value specified for %s", exporterLocalIncremental)
+			}
+			t.Incremental = b
+		}
+		return t, nil
+	case ExporterTar, ExporterOCI:
+		if w == nil {
+			return nil, errors.Errorf("output writer is required for %s exporter", exporter)
+		}
+		return &filesync.FSSyncTarget{Writer: w}, nil
+	}
+	return nil, nil
+}
+
 func prepareSyncedDirs(defs [][]byte, localDirs map[string]string) ([]filesync.SyncedDir, error) {
 	for _, d := range localDirs {
 		fi, err := os.Stat(d)
@@ -202,11 +423,31 @@ func prepareSyncedDirs(defs [][]byte, localDirs map[string]string) ([]filesync.S
 				}
 				dirs = append(dirs, filesync.SyncedDir{Name: name, Dir: d}) // TODO: excludes
 			}
+			if strings.HasPrefix(src.Identifier, "oci-layout://") && src.Attrs[pb.AttrOCILayoutLocal] == "true" {
+				name := ociLayoutName(strings.TrimPrefix(src.Identifier, "oci-layout://"))
+				d, ok := localDirs[name]
+				if !ok {
+					return nil, errors.Errorf("local directory %s not enabled", name)
+				}
+				dirs = append(dirs, filesync.SyncedDir{Name: name, Dir: d})
+			}
 		}
 	}
 	return dirs, nil
 }
 
+// ociLayoutName returns the path of an OCI layout reference without the tag
+// or digest
+func ociLayoutName(ref string) string {
+	if i := strings.LastIndex(ref, "@"); i != -1 {
+		return ref[:i]
+	}
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		return ref[:i]
+	}
+	return ref
+}
+
 func defaultSessionName() string {
 	wd, err := os.Getwd()
 	if err != nil {
@@ -214,3 +455,82 @@ func defaultSessionName() string {
 	}
 	return filepath.Base(wd)
 }
+
+func fromControlVertex(v *controlapi.Vertex) *Vertex {
+	return &Vertex{
+		Digest:    v.Digest,
+		Inputs:    v.Inputs,
+		Name:      v.Name,
+		Started:   v.Started,
+		Completed: v.Completed,
+		Error:     v.Error,
+		Cached:    v.Cached,
+		Parent:    v.Parent,
+		ExecError: fromControlExecError(v.ExecError),
+
+		CacheMissReason: fromControlCacheMissReason(v.CacheMissReason),
+		Internal:        v.Internal,
+		Group:           v.Group,
+		CacheKey:        v.CacheKey,
+		Timings:         fromControlVertexTimings(v.Timings),
+	}
+}
+
+func fromControlBuildSummary(s *controlapi.BuildSummary) *BuildSummary {
+	if s == nil {
+		return nil
+	}
+	return &BuildSummary{
+		Vertexes:         int(s.Vertexes),
+		Cached:           int(s.Cached),
+		Executed:         int(s.Executed),
+		ExecTime:         time.Duration(s.ExecTime),
+		TransferredBytes: s.TransferredBytes,
+		ExportedBytes:    s.ExportedBytes,
+	}
+}
+
+func fromControlVertexTimings(t *controlapi.VertexTimings) *VertexTimings {
+	if t == nil {
+		return nil
+	}
+	return &VertexTimings{
+		CacheLookup: time.Duration(t.CacheLookup),
+		ContentHash: time.Duration(t.ContentHash),
+		Exec:        time.Duration(t.Exec),
+		Commit:      time.Duration(t.Commit),
+	}
+}
+
+func fromControlVertexLog(l *controlapi.VertexLog) *VertexLog {
+	return &VertexLog{
+		Vertex:    l.Vertex,
+		Stream:    int(l.Stream),
+		Data:      l.Msg,
+		Timestamp: l.Timestamp,
+	}
+}
+
+func fromControlCacheMissReason(r *controlapi.CacheMissReason) *CacheMissReason {
+	if r == nil {
+		return nil
+	}
+	return &CacheMissReason{
+		Reason: r.Reason,
+		Keys:   r.Keys,
+		Inputs: r.Inputs,
+	}
+}
+
+func fromControlExecError(e *controlapi.ExecError) *ExecError {
+	if e == nil {
+		return nil
+	}
+	return &ExecError{
+		Vertex:   e.Vertex,
+		Args:     e.Args,
+		ExitCode: e.ExitCode,
+		Signal:   int(e.Signal),
+		Stderr:   e.Stderr,
+	}
+}
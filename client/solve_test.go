@@ -0,0 +1,36 @@
+package client
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSolveRefFunc(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "buildkit-client")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	// the ref is returned before the daemon is reached
+	c, err := New(filepath.Join(tmpdir, "nosuchdaemon.sock"))
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+
+	var ref string
+	_, err = c.Solve(ctx, nil, SolveOpt{Frontend: "dockerfile.v0"}, nil, WithRefFunc(func(r string) {
+		ref = r
+	}))
+	require.Error(t, err)
+	require.NotEmpty(t, ref)
+
+	_, err = c.Solve(ctx, nil, SolveOpt{Frontend: "dockerfile.v0", Ref: "foo"}, nil, WithRefFunc(func(r string) {
+		ref = r
+	}))
+	require.Error(t, err)
+	require.Equal(t, "foo", ref)
+}
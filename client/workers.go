@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+
+	"github.com/containerd/containerd/platforms"
+	controlapi "github.com/moby/buildkit/api/services/control"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// WorkerInfo describes a worker of the daemon
+type WorkerInfo struct {
+	ID        string
+	Labels    map[string]string
+	Platforms []ocispec.Platform
+	// EmulatedPlatforms are the platforms the worker runs with emulation
+	EmulatedPlatforms []ocispec.Platform
+}
+
+// ListWorkers returns the workers of the daemon. The first worker is the
+// default worker.
+func (c *Client) ListWorkers(ctx context.Context, opts ...ListWorkersOption) ([]*WorkerInfo, error) {
+	info := &ListWorkersInfo{}
+	for _, o := range opts {
+		o(info)
+	}
+
+	req := &controlapi.ListWorkersRequest{Filter: info.Filter}
+	resp, err := c.controlClient().ListWorkers(ctx, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list workers")
+	}
+
+	var workers []*WorkerInfo
+
+	for _, w := range resp.Record {
+		wi := &WorkerInfo{
+			ID:     w.ID,
+			Labels: w.Labels,
+		}
+		if wi.Platforms, err = parsePlatforms(w.ID, w.Platforms); err != nil {
+			return nil, err
+		}
+		if wi.EmulatedPlatforms, err = parsePlatforms(w.ID, w.EmulatedPlatforms); err != nil {
+			return nil, err
+		}
+		workers = append(workers, wi)
+	}
+
+	return workers, nil
+}
+
+func parsePlatforms(id string, ps []string) ([]ocispec.Platform, error) {
+	var out []ocispec.Platform
+	for _, p := range ps {
+		m, err := platforms.Parse(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid platform %s of worker %s", p, id)
+		}
+		out = append(out, m.Spec())
+	}
+	return out, nil
+}
+
+type ListWorkersOption func(*ListWorkersInfo)
+
+type ListWorkersInfo struct {
+	Filter []string
+}
+
+// WithWorkerFilter only lists the workers matching all the filters. The
+// filters can match the id, the labels and the platforms of the workers, e.g.
+// labels."org.mobyproject.buildkit.worker.executor"==runc.
+func WithWorkerFilter(f ...string) ListWorkersOption {
+	return func(wi *ListWorkersInfo) {
+		wi.Filter = append(wi.Filter, f...)
+	}
+}
@@ -3,13 +3,26 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"strings"
+	"text/tabwriter"
 
+	"github.com/containerd/console"
 	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/client/llb"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/auth/authprovider"
+	"github.com/moby/buildkit/session/debugshell"
+	"github.com/moby/buildkit/session/secrets"
+	"github.com/moby/buildkit/session/secrets/secretsprovider"
+	"github.com/moby/buildkit/session/sshforward/sshprovider"
 	"github.com/moby/buildkit/util/appcontext"
+	"github.com/moby/buildkit/util/entitlements"
 	"github.com/moby/buildkit/util/progress/progressui"
+	"github.com/moby/buildkit/util/tracing"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
@@ -33,6 +46,11 @@ var buildCommand = cli.Command{
 			Name:  "no-progress",
 			Usage: "Don't show interactive progress",
 		},
+		cli.StringFlag{
+			Name:  "progress",
+			Usage: "Set type of progress (auto, tty, plain). Use plain to show the logs of all the build steps",
+			Value: "auto",
+		},
 		cli.StringFlag{
 			Name:  "trace",
 			Usage: "Path to trace file. e.g. /dev/null. Defaults to /tmp/buildctlXXXXXXXXX.",
@@ -49,6 +67,66 @@ var buildCommand = cli.Command{
 			Name:  "frontend-opt",
 			Usage: "Define custom options for frontend",
 		},
+		cli.StringSliceFlag{
+			Name:  "frontend-input",
+			Usage: "Replace an input of the frontend, e.g. name=docker-image://ref, name=local:dir or name=file with a definition",
+		},
+		cli.StringFlag{
+			Name:  "export-cache",
+			Usage: "Reference to export build cache to",
+		},
+		cli.StringFlag{
+			Name:  "import-cache",
+			Usage: "Reference to import build cache from, a cache export or an image with inline cache",
+		},
+		cli.StringSliceFlag{
+			Name:  "cache-from",
+			Usage: "Import build cache from a source. Format type=registry,ref=<ref>|type=local,src=<dir>",
+		},
+		cli.StringSliceFlag{
+			Name:  "cache-to",
+			Usage: "Export build cache to a target. Format type=registry,ref=<ref>|type=local,dest=<dir>[,mode=min|max]",
+		},
+		cli.StringSliceFlag{
+			Name:  "secret",
+			Usage: "Secret value exposed to the build. Format id=secretname,src=filepath",
+		},
+		cli.StringSliceFlag{
+			Name:  "ssh",
+			Usage: "Allow forwarding SSH agent to the builder. Format default|<id>[=<socket>]",
+		},
+		cli.StringSliceFlag{
+			Name:  "allow",
+			Usage: "Allow an extra privileged entitlement, e.g. network.host, security.insecure, device, security.profile",
+		},
+		cli.StringFlag{
+			Name:  "cgroup-parent",
+			Usage: "Create the cgroups of the build steps under the cgroup, relative to the cgroup of the daemon",
+		},
+		cli.StringFlag{
+			Name:  "debug-shell",
+			Usage: "Run a shell, eg. /bin/sh, in the rootfs of a failed build step before its mounts are released",
+		},
+		cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "Show which build steps are cached without running them",
+		},
+		cli.StringFlag{
+			Name:  "ref",
+			Usage: "Set the ID of the build, eg. for buildctl jobs. A random ID is used by default",
+		},
+		cli.IntFlag{
+			Name:  "priority",
+			Usage: "Priority of the build in the queue of the daemon, higher priorities run first",
+		},
+		cli.StringFlag{
+			Name:  "metadata-file",
+			Usage: "Write the response of the exporter, eg. the digest of the image, as JSON to the file",
+		},
+		cli.StringFlag{
+			Name:  "trace-id",
+			Usage: "Set the ID of the trace of the build steps on the daemon, shown in its debug logs and on /debug/requests",
+		},
 	},
 }
 
@@ -76,13 +154,43 @@ func build(clicontext *cli.Context) error {
 
 	ch := make(chan *client.SolveStatus)
 	displayCh := make(chan *client.SolveStatus)
-	eg, ctx := errgroup.WithContext(appcontext.Context())
+	ctx := appcontext.Context()
+	if id := clicontext.String("trace-id"); id != "" {
+		ctx = tracing.ContextWithTraceID(ctx, id)
+	}
+	eg, ctx := errgroup.WithContext(ctx)
 
 	exporterAttrs, err := attrMap(clicontext.StringSlice("exporter-opt"))
 	if err != nil {
 		return errors.Wrap(err, "invalid exporter-opt")
 	}
 
+	noProgress := clicontext.Bool("no-progress")
+	cons, err := progressConsole(clicontext.String("progress"))
+	if err != nil {
+		return err
+	}
+	var exporterOutput io.Writer
+	if exporter := clicontext.String("exporter"); exporter == client.ExporterTar || exporter == client.ExporterOCI {
+		output := exporterAttrs["output"]
+		delete(exporterAttrs, "output")
+		if output == "" || output == "-" {
+			if _, err := console.ConsoleFromFile(os.Stdout); err == nil {
+				return errors.Errorf("output file is required for %s exporter when stdout is a terminal. refusing to write to console", exporter)
+			}
+			exporterOutput = os.Stdout
+			// the progress is shown on stdout
+			noProgress = true
+		} else {
+			f, err := os.Create(output)
+			if err != nil {
+				return errors.Wrap(err, "failed to create output file")
+			}
+			defer f.Close()
+			exporterOutput = f
+		}
+	}
+
 	frontendAttrs, err := attrMap(clicontext.StringSlice("frontend-opt"))
 	if err != nil {
 		return errors.Wrap(err, "invalid frontend-opt")
@@ -93,14 +201,89 @@ func build(clicontext *cli.Context) error {
 		return errors.Wrap(err, "invalid local")
 	}
 
+	frontendInputs, err := parseFrontendInputs(clicontext.StringSlice("frontend-input"), localDirs)
+	if err != nil {
+		return errors.Wrap(err, "invalid frontend-input")
+	}
+
+	cacheImports, err := parseCacheOptions(clicontext.StringSlice("cache-from"))
+	if err != nil {
+		return err
+	}
+
+	cacheExports, err := parseCacheOptions(clicontext.StringSlice("cache-to"))
+	if err != nil {
+		return err
+	}
+
+	secretStore, err := parseSecrets(clicontext.StringSlice("secret"))
+	if err != nil {
+		return errors.Wrap(err, "invalid secret")
+	}
+
+	var allowed []entitlements.Entitlement
+	for _, a := range clicontext.StringSlice("allow") {
+		e, err := entitlements.Parse(a)
+		if err != nil {
+			return errors.Wrap(err, "invalid allow")
+		}
+		allowed = append(allowed, e)
+	}
+
+	dockerConfig, err := authprovider.LoadDockerConfig()
+	if err != nil {
+		return err
+	}
+
+	attachable := []session.Attachable{
+		secretsprovider.NewSecretProvider(secretStore),
+		authprovider.NewDockerAuthProvider(dockerConfig),
+	}
+
+	if ssh := clicontext.StringSlice("ssh"); len(ssh) > 0 {
+		sp, err := parseSSHSpecs(ssh)
+		if err != nil {
+			return errors.Wrap(err, "invalid ssh")
+		}
+		attachable = append(attachable, sp)
+	}
+
+	if shell := clicontext.String("debug-shell"); shell != "" {
+		attachable = append(attachable, debugshell.NewShellProvider([]string{shell}, os.Stdin, os.Stdout, os.Stderr))
+	}
+
+	solveOpts := []client.SolveOption{client.WithRefFunc(func(ref string) {
+		logrus.Debugf("build ref %s", ref)
+	})}
+	var report *client.DryRunReport
+	if clicontext.Bool("dry-run") {
+		report = &client.DryRunReport{}
+		solveOpts = append(solveOpts, client.WithDryRun(report))
+	}
+
+	var resp *client.SolveResponse
 	eg.Go(func() error {
-		return c.Solve(ctx, os.Stdin, client.SolveOpt{
-			Exporter:      clicontext.String("exporter"),
-			ExporterAttrs: exporterAttrs,
-			LocalDirs:     localDirs,
-			Frontend:      clicontext.String("frontend"),
-			FrontendAttrs: frontendAttrs,
-		}, ch)
+		var err error
+		resp, err = c.Solve(ctx, os.Stdin, client.SolveOpt{
+			Exporter:       clicontext.String("exporter"),
+			ExporterAttrs:  exporterAttrs,
+			ExporterOutput: exporterOutput,
+			LocalDirs:      localDirs,
+			Frontend:       clicontext.String("frontend"),
+			FrontendAttrs:  frontendAttrs,
+			FrontendInputs: frontendInputs,
+			ExportCache:    clicontext.String("export-cache"),
+			ImportCache:    clicontext.String("import-cache"),
+			CacheImports:   cacheImports,
+			CacheExports:   cacheExports,
+			Session:        attachable,
+			Ref:            clicontext.String("ref"),
+			Priority:       clicontext.Int("priority"),
+
+			AllowedEntitlements: allowed,
+			CgroupParent:        clicontext.String("cgroup-parent"),
+		}, ch, solveOpts...)
+		return err
 	})
 
 	eg.Go(func() error {
@@ -115,7 +298,7 @@ func build(clicontext *cli.Context) error {
 	})
 
 	eg.Go(func() error {
-		if clicontext.Bool("no-progress") {
+		if noProgress {
 			for s := range displayCh {
 				for _, v := range s.Vertexes {
 					logrus.Debugf("vertex: %s %s %v %v", v.Digest, v.Name, v.Started, v.Completed)
@@ -130,10 +313,54 @@ func build(clicontext *cli.Context) error {
 			return nil
 		}
 		// not using shared context to not disrupt display but let is finish reporting errors
-		return progressui.DisplaySolveStatus(context.TODO(), displayCh)
+		return progressui.DisplaySolveStatus(context.TODO(), cons, os.Stderr, displayCh)
 	})
 
-	return eg.Wait()
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+	if report != nil {
+		printDryRunReport(report)
+	}
+	if f := clicontext.String("metadata-file"); f != "" && resp != nil {
+		dt, err := json.MarshalIndent(resp.ExporterResponse, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(f, dt, 0644); err != nil {
+			return errors.Wrap(err, "failed to write metadata file")
+		}
+	}
+	return nil
+}
+
+// progressConsole returns the console for the progress of the mode or nil if
+// the progress is printed as plain text
+func progressConsole(mode string) (console.Console, error) {
+	switch mode {
+	case "plain":
+		return nil, nil
+	case "auto", "tty":
+		c, err := console.ConsoleFromFile(os.Stdout)
+		if err != nil {
+			if mode == "tty" {
+				return nil, errors.Wrap(err, "failed to get console for tty progress")
+			}
+			return nil, nil
+		}
+		return c, nil
+	}
+	return nil, errors.Errorf("invalid progress mode %s", mode)
+}
+
+func printDryRunReport(report *client.DryRunReport) {
+	tw := tabwriter.NewWriter(os.Stdout, 1, 8, 1, '\t', 0)
+	fmt.Fprintln(tw, "DIGEST\tCACHED\tREQUIRED\tNAME")
+	for _, v := range report.Vertexes {
+		fmt.Fprintf(tw, "%s\t%v\t%v\t%s\n", v.Digest, v.Cached, v.Required, v.Name)
+	}
+	tw.Flush()
+	fmt.Printf("%d of %d steps would run\n", report.Work, len(report.Vertexes))
 }
 
 func attrMap(sl []string) (map[string]string, error) {
@@ -147,3 +374,102 @@ func attrMap(sl []string) (map[string]string, error) {
 	}
 	return m, nil
 }
+
+// parseFrontendInputs returns the definitions of the frontend inputs in the
+// form of name=value. The value is an image reference with the
+// docker-image:// prefix, a local directory with the local: prefix that is
+// added to localDirs, or a file with a marshaled definition.
+func parseFrontendInputs(sl []string, localDirs map[string]string) (map[string][][]byte, error) {
+	inputs := make(map[string][][]byte, len(sl))
+	for _, v := range sl {
+		parts := strings.SplitN(v, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, errors.Errorf("invalid value %s", v)
+		}
+		name, value := parts[0], parts[1]
+		var def [][]byte
+		var err error
+		switch {
+		case strings.HasPrefix(value, "docker-image://"):
+			def, err = llb.Image(strings.TrimPrefix(value, "docker-image://")).Marshal()
+		case strings.HasPrefix(value, "local:"):
+			local := "input:" + name
+			localDirs[local] = strings.TrimPrefix(value, "local:")
+			def, err = llb.Local(local).Marshal()
+		default:
+			var f *os.File
+			f, err = os.Open(value)
+			if err != nil {
+				return nil, err
+			}
+			def, err = llb.ReadFrom(f)
+			f.Close()
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load input %s", name)
+		}
+		inputs[name] = def
+	}
+	return inputs, nil
+}
+
+func parseCacheOptions(sl []string) ([]client.CacheOptionsEntry, error) {
+	entries := make([]client.CacheOptionsEntry, 0, len(sl))
+	for _, v := range sl {
+		e := client.CacheOptionsEntry{Attrs: map[string]string{}}
+		for _, field := range strings.Split(v, ",") {
+			parts := strings.SplitN(field, "=", 2)
+			if len(parts) != 2 {
+				return nil, errors.Errorf("invalid field %s in cache option %s", field, v)
+			}
+			if strings.ToLower(parts[0]) == "type" {
+				e.Type = parts[1]
+				continue
+			}
+			e.Attrs[strings.ToLower(parts[0])] = parts[1]
+		}
+		if e.Type == "" {
+			return nil, errors.Errorf("type required for cache option %s", v)
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+func parseSecrets(sl []string) (secrets.SecretStore, error) {
+	fs := make([]secretsprovider.FileSource, 0, len(sl))
+	for _, v := range sl {
+		var src secretsprovider.FileSource
+		for _, field := range strings.Split(v, ",") {
+			parts := strings.SplitN(field, "=", 2)
+			if len(parts) != 2 {
+				return nil, errors.Errorf("invalid field %s in secret %s", field, v)
+			}
+			switch strings.ToLower(parts[0]) {
+			case "id":
+				src.ID = parts[1]
+			case "src", "source":
+				src.FilePath = parts[1]
+			default:
+				return nil, errors.Errorf("unexpected key %s in secret %s", parts[0], v)
+			}
+		}
+		fs = append(fs, src)
+	}
+	return secretsprovider.NewFileStore(fs)
+}
+
+func parseSSHSpecs(sl []string) (session.Attachable, error) {
+	configs := make([]sshprovider.AgentConfig, 0, len(sl))
+	for _, v := range sl {
+		parts := strings.SplitN(v, "=", 2)
+		cfg := sshprovider.AgentConfig{
+			ID: parts[0],
+		}
+		if len(parts) > 1 {
+			cfg.Socket = parts[1]
+		}
+		configs = append(configs, cfg)
+	}
+	return sshprovider.NewSSHAgentProvider(configs)
+}
@@ -11,5 +11,7 @@ var debugCommand = cli.Command{
 	Subcommands: []cli.Command{
 		debug.DumpLLBCommand,
 		debug.DumpMetadataCommand,
+		debugWorkersCommand,
+		debugGraphCommand,
 	},
 }
@@ -108,6 +108,12 @@ func attr(dgst digest.Digest, op pb.Op) (string, string) {
 		return strings.Join(op.Exec.Meta.Args, " "), "box"
 	case *pb.Op_Build:
 		return "build", "box3d"
+	case *pb.Op_File:
+		return "file", "note"
+	case *pb.Op_Merge:
+		return "merge", "invtriangle"
+	case *pb.Op_Diff:
+		return "diff", "triangle"
 	default:
 		return dgst.String(), "plaintext"
 	}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"text/tabwriter"
 
 	units "github.com/docker/go-units"
@@ -59,14 +60,24 @@ func printKV(w io.Writer, k string, v interface{}) {
 }
 
 func printVerbose(tw *tabwriter.Writer, du []*client.UsageInfo) {
+	records := make(map[string]*client.UsageInfo, len(du))
+	for _, di := range du {
+		records[di.ID] = di
+	}
 	for _, di := range du {
 		printKV(tw, "ID", di.ID)
 		if di.Parent != "" {
 			printKV(tw, "Parent", di.Parent)
+			if chain := parentChain(records, di); len(chain) > 1 {
+				printKV(tw, "Parent chain", strings.Join(chain, " <- "))
+			}
 		}
 		printKV(tw, "Created at", di.CreatedAt)
 		printKV(tw, "Mutable", di.Mutable)
 		printKV(tw, "Reclaimable", !di.InUse)
+		if di.InUse {
+			printKV(tw, "References", di.Refs)
+		}
 		printKV(tw, "Size", units.HumanSize(float64(di.Size)))
 		if di.Description != "" {
 			printKV(tw, "Description", di.Description)
@@ -82,21 +93,39 @@ func printVerbose(tw *tabwriter.Writer, du []*client.UsageInfo) {
 	tw.Flush()
 }
 
+// parentChain returns the IDs of the ancestors of di, closest first, as far
+// as they are known from records
+func parentChain(records map[string]*client.UsageInfo, di *client.UsageInfo) []string {
+	var chain []string
+	for p := di.Parent; p != ""; {
+		chain = append(chain, p)
+		parent, ok := records[p]
+		if !ok {
+			break
+		}
+		p = parent.Parent
+	}
+	return chain
+}
+
 func printTable(tw *tabwriter.Writer, du []*client.UsageInfo) {
 	fmt.Fprintln(tw, "ID\tRECLAIMABLE\tSIZE\tLAST ACCESSED")
 
 	for _, di := range du {
-		id := di.ID
-		if di.Mutable {
-			id += "*"
-		}
-		fmt.Fprintf(tw, "%s\t%v\t%s\t\n", id, !di.InUse, units.HumanSize(float64(di.Size)))
-
+		printTableRow(tw, di)
 	}
 
 	tw.Flush()
 }
 
+func printTableRow(tw *tabwriter.Writer, di *client.UsageInfo) {
+	id := di.ID
+	if di.Mutable {
+		id += "*"
+	}
+	fmt.Fprintf(tw, "%s\t%v\t%s\t\n", id, !di.InUse, units.HumanSize(float64(di.Size)))
+}
+
 func printSummary(tw *tabwriter.Writer, du []*client.UsageInfo) {
 	total := int64(0)
 	reclaimable := int64(0)
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/util/appcontext"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var debugGraphCommand = cli.Command{
+	Name:      "graph",
+	Usage:     "print the dependency graph of LLB with the predicted cache state of the vertexes. LLB can be also passed via stdin. Nothing is built.",
+	ArgsUsage: "<llbfile>",
+	Action:    printGraph,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "format",
+			Usage: "Output format (dot, json)",
+			Value: "dot",
+		},
+	},
+}
+
+func printGraph(clicontext *cli.Context) error {
+	format := clicontext.String("format")
+	if format != "dot" && format != "json" {
+		return errors.Errorf("invalid format %s", format)
+	}
+
+	var r io.Reader = os.Stdin
+	if llbFile := clicontext.Args().First(); llbFile != "" && llbFile != "-" {
+		f, err := os.Open(llbFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	c, err := resolveClient(clicontext)
+	if err != nil {
+		return err
+	}
+
+	report := &client.DryRunReport{}
+	if _, err := c.Solve(appcontext.Context(), r, client.SolveOpt{}, nil, client.WithDryRun(report)); err != nil {
+		return err
+	}
+
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	return report.WriteDot(os.Stdout)
+}
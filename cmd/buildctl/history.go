@@ -0,0 +1,230 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	units "github.com/docker/go-units"
+	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/util/appcontext"
+	"github.com/moby/buildkit/util/progress/progressui"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+	"golang.org/x/sync/errgroup"
+)
+
+var historyCommand = cli.Command{
+	Name:  "history",
+	Usage: "inspect the builds of the daemon",
+	Subcommands: []cli.Command{
+		{
+			Name:   "ls",
+			Usage:  "list builds",
+			Action: listBuilds,
+		},
+		{
+			Name:      "inspect",
+			Usage:     "show the details of a build",
+			ArgsUsage: "REF",
+			Action:    inspectBuild,
+		},
+		{
+			Name:      "logs",
+			Usage:     "print the logs of a build",
+			ArgsUsage: "REF",
+			Action:    buildLogs,
+		},
+	},
+}
+
+func listBuilds(clicontext *cli.Context) error {
+	c, err := resolveClient(clicontext)
+	if err != nil {
+		return err
+	}
+
+	builds, err := c.ListBuilds(appcontext.Context())
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 1, 8, 1, '\t', 0)
+	fmt.Fprintln(tw, "REF\tCREATED AT\tDURATION\tSTATUS")
+	for _, b := range builds {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", b.Ref, b.CreatedAt.Format(time.RFC3339), buildDuration(b), buildStatus(b))
+	}
+	return tw.Flush()
+}
+
+func inspectBuild(clicontext *cli.Context) error {
+	ref, err := buildRef(clicontext)
+	if err != nil {
+		return err
+	}
+	c, err := resolveClient(clicontext)
+	if err != nil {
+		return err
+	}
+
+	b, err := c.InspectBuild(appcontext.Context(), ref)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 1, 8, 1, '\t', 0)
+	printKV(tw, "Ref", b.Ref)
+	if b.Definition != "" {
+		printKV(tw, "Definition", b.Definition)
+	}
+	if b.Frontend != "" {
+		printKV(tw, "Frontend", b.Frontend)
+		printAttrs(tw, "Frontend attrs", b.FrontendAttrs)
+	}
+	if b.Exporter != "" {
+		printKV(tw, "Exporter", b.Exporter)
+		printAttrs(tw, "Exporter attrs", b.ExporterAttrs)
+	}
+	printKV(tw, "Created at", b.CreatedAt.Format(time.RFC3339))
+	printKV(tw, "Duration", buildDuration(b))
+	printKV(tw, "Status", buildStatus(b))
+	if b.Error != "" {
+		printKV(tw, "Error", b.Error)
+	}
+	logSize := units.HumanSize(float64(b.LogSize))
+	if b.LogsTruncated {
+		logSize += " (truncated)"
+	}
+	printKV(tw, "Logs", logSize)
+	if s := b.Summary; s != nil {
+		printKV(tw, "Steps", fmt.Sprintf("%d cached, %d executed of %d", s.Cached, s.Executed, s.Vertexes))
+		printKV(tw, "Exec time", s.ExecTime.Round(time.Millisecond).String())
+		printKV(tw, "Transferred", units.HumanSize(float64(s.TransferredBytes)))
+		printKV(tw, "Exported", units.HumanSize(float64(s.ExportedBytes)))
+	}
+
+	fmt.Fprintf(tw, "\nSTEP\tDURATION\tSTATUS\n")
+	for _, v := range b.Vertexes {
+		status := "pending"
+		duration := ""
+		switch {
+		case v.Error != "":
+			status = "error"
+		case v.Cached:
+			status = "cached"
+		case v.Completed != nil:
+			status = "done"
+		case v.Started != nil:
+			status = "running"
+		}
+		if v.Started != nil && v.Completed != nil {
+			duration = fmt.Sprintf("%.1fs", v.Completed.Sub(*v.Started).Seconds())
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", v.Name, duration, status)
+	}
+	return tw.Flush()
+}
+
+func buildLogs(clicontext *cli.Context) error {
+	ref, err := buildRef(clicontext)
+	if err != nil {
+		return err
+	}
+	c, err := resolveClient(clicontext)
+	if err != nil {
+		return err
+	}
+
+	ctx := appcontext.Context()
+	b, err := c.InspectBuild(ctx, ref)
+	if err != nil {
+		return err
+	}
+
+	// the logs are printed like the plain progress of the build with the
+	// steps numbered in the order they were started
+	ch := make(chan *client.SolveStatus)
+	logs := make(chan *client.VertexLog)
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.Go(func() error {
+		return c.BuildLogs(ctx, ref, logs)
+	})
+	eg.Go(func() error {
+		defer close(ch)
+		started := make([]*client.Vertex, 0, len(b.Vertexes))
+		for _, v := range b.Vertexes {
+			if v.Started != nil {
+				sv := *v
+				sv.Completed = nil
+				started = append(started, &sv)
+			}
+		}
+		sort.SliceStable(started, func(i, j int) bool {
+			return started[i].Started.Before(*started[j].Started)
+		})
+		send := func(ss *client.SolveStatus) error {
+			select {
+			case ch <- ss:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		if err := send(&client.SolveStatus{Vertexes: started}); err != nil {
+			return err
+		}
+		for l := range logs {
+			if err := send(&client.SolveStatus{Logs: []*client.VertexLog{l}}); err != nil {
+				return err
+			}
+		}
+		return send(&client.SolveStatus{Vertexes: b.Vertexes})
+	})
+	eg.Go(func() error {
+		return progressui.PrintSolveStatus(ctx, os.Stdout, ch)
+	})
+	return eg.Wait()
+}
+
+func buildRef(clicontext *cli.Context) (string, error) {
+	if clicontext.NArg() != 1 {
+		return "", errors.Errorf("a build ref is required")
+	}
+	return clicontext.Args().First(), nil
+}
+
+func buildDuration(b *client.BuildInfo) string {
+	end := time.Now()
+	if b.CompletedAt != nil {
+		end = *b.CompletedAt
+	}
+	return fmt.Sprintf("%.1fs", end.Sub(b.CreatedAt).Seconds())
+}
+
+func buildStatus(b *client.BuildInfo) string {
+	switch {
+	case b.CompletedAt == nil:
+		return "running"
+	case b.Error != "":
+		return "error"
+	default:
+		return "completed"
+	}
+}
+
+func printAttrs(tw *tabwriter.Writer, name string, attrs map[string]string) {
+	if len(attrs) == 0 {
+		return
+	}
+	fmt.Fprintf(tw, "%s:\n", name)
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(tw, "\t%s:\t%s\n", k, attrs[k])
+	}
+}
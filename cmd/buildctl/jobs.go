@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/util/appcontext"
+	"github.com/moby/buildkit/util/progress/progressui"
+	"github.com/urfave/cli"
+	"golang.org/x/sync/errgroup"
+)
+
+var jobsCommand = cli.Command{
+	Name:  "jobs",
+	Usage: "manage the builds that are running on the daemon",
+	Subcommands: []cli.Command{
+		{
+			Name:   "ls",
+			Usage:  "list the running and queued builds",
+			Action: listJobs,
+		},
+		{
+			Name:      "attach",
+			Usage:     "show the progress of a build until it completes",
+			ArgsUsage: "REF",
+			Action:    attachJob,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "progress",
+					Usage: "Set type of progress (auto, tty, plain)",
+					Value: "auto",
+				},
+			},
+		},
+		{
+			Name:      "cancel",
+			Usage:     "cancel a build",
+			ArgsUsage: "REF",
+			Action:    cancelJob,
+		},
+	},
+}
+
+func listJobs(clicontext *cli.Context) error {
+	c, err := resolveClient(clicontext)
+	if err != nil {
+		return err
+	}
+
+	jobs, err := c.ListJobs(appcontext.Context())
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 1, 8, 1, '\t', 0)
+	fmt.Fprintln(tw, "REF\tCLIENT\tCREATED AT\tSTATUS")
+	for _, j := range jobs {
+		status := "running"
+		if j.Position > 0 {
+			status = fmt.Sprintf("queued (%d)", j.Position)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", j.Ref, j.Client, j.CreatedAt.Format(time.RFC3339), status)
+	}
+	return tw.Flush()
+}
+
+func attachJob(clicontext *cli.Context) error {
+	ref, err := buildRef(clicontext)
+	if err != nil {
+		return err
+	}
+	c, err := resolveClient(clicontext)
+	if err != nil {
+		return err
+	}
+	cons, err := progressConsole(clicontext.String("progress"))
+	if err != nil {
+		return err
+	}
+
+	ch := make(chan *client.SolveStatus)
+	eg, ctx := errgroup.WithContext(appcontext.Context())
+	eg.Go(func() error {
+		return c.AttachStatus(ctx, ref, ch)
+	})
+	eg.Go(func() error {
+		// not using shared context to not disrupt display but let is finish reporting errors
+		return progressui.DisplaySolveStatus(context.TODO(), cons, os.Stderr, ch)
+	})
+	return eg.Wait()
+}
+
+func cancelJob(clicontext *cli.Context) error {
+	ref, err := buildRef(clicontext)
+	if err != nil {
+		return err
+	}
+	c, err := resolveClient(clicontext)
+	if err != nil {
+		return err
+	}
+	return c.CancelJob(appcontext.Context(), ref)
+}
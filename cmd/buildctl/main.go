@@ -30,8 +30,11 @@ func main() {
 
 	app.Commands = []cli.Command{
 		diskUsageCommand,
+		pruneCommand,
 		buildCommand,
 		debugCommand,
+		historyCommand,
+		jobsCommand,
 	}
 
 	app.Before = func(context *cli.Context) error {
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	units "github.com/docker/go-units"
+	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/util/appcontext"
+	"github.com/urfave/cli"
+	"golang.org/x/sync/errgroup"
+)
+
+var pruneCommand = cli.Command{
+	Name:   "prune",
+	Usage:  "clean up build cache",
+	Action: prune,
+	Flags: []cli.Flag{
+		cli.StringSliceFlag{
+			Name:  "filter, f",
+			Usage: "Filter records (id=, description=, type=mutable|immutable, unused-for=)",
+		},
+		cli.DurationFlag{
+			Name:  "keep-duration",
+			Usage: "Keep records used within the duration",
+		},
+		cli.StringFlag{
+			Name:  "keep-storage",
+			Usage: "Keep the total size of the cache below the limit (eg. 10GB)",
+		},
+		cli.BoolFlag{
+			Name:  "verbose, v",
+			Usage: "Verbose output",
+		},
+	},
+}
+
+func prune(clicontext *cli.Context) error {
+	c, err := resolveClient(clicontext)
+	if err != nil {
+		return err
+	}
+
+	opts := []client.PruneOption{
+		client.WithPruneFilter(clicontext.StringSlice("filter")...),
+		client.WithKeepDuration(clicontext.Duration("keep-duration")),
+	}
+	if v := clicontext.String("keep-storage"); v != "" {
+		s, err := units.FromHumanSize(v)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, client.WithKeepBytes(s))
+	}
+
+	ch := make(chan client.UsageInfo)
+
+	eg, ctx := errgroup.WithContext(appcontext.Context())
+	eg.Go(func() error {
+		return c.Prune(ctx, ch, opts...)
+	})
+
+	var total int64
+	eg.Go(func() error {
+		tw := tabwriter.NewWriter(os.Stdout, 1, 8, 1, '\t', 0)
+		if !clicontext.Bool("verbose") {
+			fmt.Fprintln(tw, "ID\tRECLAIMABLE\tSIZE\tLAST ACCESSED")
+		}
+		for di := range ch {
+			if clicontext.Bool("verbose") {
+				printVerbose(tw, []*client.UsageInfo{&di})
+			} else {
+				printTableRow(tw, &di)
+				tw.Flush()
+			}
+			total += di.Size
+		}
+		return nil
+	})
+
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 1, 8, 1, '\t', 0)
+	fmt.Fprintf(tw, "Total:\t%s\n", units.HumanSize(float64(total)))
+	tw.Flush()
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/containerd/containerd/platforms"
+	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/util/appcontext"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/urfave/cli"
+)
+
+var debugWorkersCommand = cli.Command{
+	Name:   "workers",
+	Usage:  "list workers",
+	Action: listWorkers,
+	Flags: []cli.Flag{
+		cli.StringSliceFlag{
+			Name:  "filter, f",
+			Usage: "Filter workers, e.g. labels.\"org.mobyproject.buildkit.worker.executor\"==runc",
+		},
+		cli.BoolFlag{
+			Name:  "verbose, v",
+			Usage: "Verbose output",
+		},
+	},
+}
+
+func listWorkers(clicontext *cli.Context) error {
+	c, err := resolveClient(clicontext)
+	if err != nil {
+		return err
+	}
+
+	workers, err := c.ListWorkers(appcontext.Context(), client.WithWorkerFilter(clicontext.StringSlice("filter")...))
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 1, 8, 1, '\t', 0)
+
+	if clicontext.Bool("verbose") {
+		printWorkersVerbose(tw, workers)
+	} else {
+		printWorkersTable(tw, workers)
+	}
+	return nil
+}
+
+func printWorkersVerbose(tw *tabwriter.Writer, winfo []*client.WorkerInfo) {
+	for _, wi := range winfo {
+		printKV(tw, "ID", wi.ID)
+		printKV(tw, "Platforms", joinPlatforms(wi.Platforms))
+		if len(wi.EmulatedPlatforms) > 0 {
+			printKV(tw, "Emulated platforms", joinPlatforms(wi.EmulatedPlatforms))
+		}
+		fmt.Fprintf(tw, "Labels:\n")
+		keys := make([]string, 0, len(wi.Labels))
+		for k := range wi.Labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(tw, "\t%s:\t%s\n", k, wi.Labels[k])
+		}
+		fmt.Fprintf(tw, "\n")
+	}
+
+	tw.Flush()
+}
+
+func printWorkersTable(tw *tabwriter.Writer, winfo []*client.WorkerInfo) {
+	fmt.Fprintln(tw, "ID\tPLATFORMS")
+
+	for _, wi := range winfo {
+		fmt.Fprintf(tw, "%s\t%s\n", wi.ID, joinPlatforms(wi.Platforms))
+	}
+
+	tw.Flush()
+}
+
+func joinPlatforms(platformList []ocispec.Platform) string {
+	ps := make([]string, 0, len(platformList))
+	for _, p := range platformList {
+		ps = append(ps, platforms.Format(p))
+	}
+	return strings.Join(ps, ",")
+}
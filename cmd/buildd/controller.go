@@ -0,0 +1,132 @@
+// +build standalone containerd
+
+package main
+
+import (
+	"github.com/moby/buildkit/control"
+	"github.com/moby/buildkit/util/entitlements"
+	"github.com/moby/buildkit/util/resolver"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+// controllerOpts returns the options of the controller that are shared by
+// all the workers
+func controllerOpts(c *cli.Context) ([]control.ControllerOpt, error) {
+	rp, err := resourcePolicy(c)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []control.ControllerOpt{control.WithResourcePolicy(*rp)}
+	if c.GlobalBool("exec-readonly-rootfs") {
+		opts = append(opts, control.WithReadonlyRootFS())
+	}
+	switch n := c.GlobalString("exec-network"); n {
+	case "":
+	case "none":
+		opts = append(opts, control.WithNoNetwork())
+	case "host":
+		opts = append(opts, control.WithHostNetwork())
+	default:
+		return nil, errors.Errorf("invalid exec network %s, none or host is supported", n)
+	}
+	if c.GlobalBool("exec-proxy-env") {
+		opts = append(opts, control.WithProxyEnv(proxyEnv()))
+	}
+	if paths := c.GlobalStringSlice("exec-allow-host-mount"); len(paths) > 0 {
+		opts = append(opts, control.WithHostMounts(paths))
+	}
+	sp, err := securityProfile(c)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, control.WithSecurityProfile(*sp))
+	dns, err := dnsConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	if dns != nil {
+		opts = append(opts, control.WithDNS(*dns))
+	}
+	if p := c.GlobalString("cgroup-parent"); p != "" {
+		opts = append(opts, control.WithCgroupParent(p))
+	}
+	if names := c.GlobalStringSlice("allow-insecure-entitlement"); len(names) > 0 {
+		ents := make([]entitlements.Entitlement, 0, len(names))
+		for _, n := range names {
+			e, err := entitlements.Parse(n)
+			if err != nil {
+				return nil, err
+			}
+			ents = append(ents, e)
+		}
+		opts = append(opts, control.WithEntitlements(ents))
+	}
+	if addrs := c.GlobalStringSlice("remote-worker"); len(addrs) > 0 {
+		cfg := control.RemoteWorkerConfig{
+			Addresses: addrs,
+			CACert:    c.GlobalString("tlscacert"),
+			Cert:      c.GlobalString("tlscert"),
+			Key:       c.GlobalString("tlskey"),
+		}
+		if cfg.CACert == "" || cfg.Cert == "" || cfg.Key == "" {
+			return nil, errors.New("--remote-worker requires --tlscacert, --tlscert and --tlskey")
+		}
+		opts = append(opts, control.WithRemoteWorkers(cfg))
+	}
+	if root := c.GlobalString("oci-layout-root"); root != "" {
+		opts = append(opts, control.WithOCILayoutRoot(root))
+	}
+	if c.GlobalString("remote-exec-addr") != "" {
+		opts = append(opts, control.WithRemoteExec())
+	}
+	if c.GlobalBool("rootless") {
+		opts = append(opts, control.WithRootless())
+	}
+	if s := c.GlobalString("snapshotter"); s != "" {
+		opts = append(opts, control.WithSnapshotterDriver(s))
+	}
+	if c.GlobalBool("provenance") {
+		opts = append(opts, control.WithProvenance())
+	}
+	if c.GlobalBool("keep-completed") {
+		opts = append(opts, control.WithKeepCompleted())
+	}
+	if c.GlobalBool("disable-history") {
+		opts = append(opts, control.WithoutHistory())
+	}
+	if c.GlobalBool("dedup-commits") {
+		opts = append(opts, control.WithDedupCommits())
+	}
+	if n := c.GlobalInt("max-concurrent-downloads"); n > 0 {
+		opts = append(opts, control.WithMaxConcurrentDownloads(n))
+	}
+	if c.GlobalBool("estargz") {
+		opts = append(opts, control.WithEStargz())
+	}
+	if n := c.GlobalInt("max-parallelism"); n > 0 {
+		opts = append(opts, control.WithMaxParallelism(n))
+	}
+	if n, m := c.GlobalInt("max-concurrent-solves"), c.GlobalInt("max-solves-per-client"); n > 0 || m > 0 {
+		opts = append(opts, control.WithQueuePolicy(control.QueuePolicy{MaxConcurrent: n, MaxPerClient: m}))
+	}
+	if ttl := c.GlobalDuration("http-cache-ttl"); ttl > 0 {
+		opts = append(opts, control.WithHTTPCacheTTL(ttl))
+	}
+	if p := c.GlobalString("registry-config"); p != "" {
+		cfg, err := resolver.LoadConfig(p)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, control.WithRegistryConfig(cfg))
+	}
+	gc, err := gcPolicy(c)
+	if err != nil {
+		return nil, err
+	}
+	if gc != nil {
+		opts = append(opts, control.WithGCPolicy(*gc))
+	}
+	return opts, nil
+}
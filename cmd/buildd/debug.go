@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/pprof"
 
+	"github.com/moby/buildkit/util/metrics"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/net/trace"
 )
@@ -20,6 +21,7 @@ func setupDebugHandlers(addr string) error {
 	m.Handle("/debug/pprof/trace", http.HandlerFunc(pprof.Trace))
 	m.Handle("/debug/requests", http.HandlerFunc(trace.Traces))
 	m.Handle("/debug/events", http.HandlerFunc(trace.Events))
+	m.Handle("/metrics", metrics.Handler())
 
 	// setting debugaddr is opt-in. permission is defined by listener address
 	trace.AuthRequest = func(_ *http.Request) (bool, bool) {
@@ -34,3 +36,16 @@ func setupDebugHandlers(addr string) error {
 	go http.Serve(l, m)
 	return nil
 }
+
+func setupMetricsHandler(addr string) error {
+	m := http.NewServeMux()
+	m.Handle("/metrics", metrics.Handler())
+
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	logrus.Debugf("metrics handler listening at %s", addr)
+	go http.Serve(l, m)
+	return nil
+}
@@ -1,19 +1,34 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/containerd/containerd/sys"
+	units "github.com/docker/go-units"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/solver"
 	"github.com/moby/buildkit/util/appcontext"
 	"github.com/moby/buildkit/util/appdefaults"
+	"github.com/moby/buildkit/util/metrics"
+	"github.com/moby/buildkit/util/peercred"
 	"github.com/moby/buildkit/util/profiler"
+	"github.com/moby/buildkit/util/tracing"
+	"github.com/moby/buildkit/worker"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func main() {
@@ -41,6 +56,164 @@ func main() {
 			Usage: "Debugging address (eg. 0.0.0.0:6060)",
 			Value: "",
 		},
+		cli.StringFlag{
+			Name:  "metrics-addr",
+			Usage: "Address of the Prometheus metrics endpoint /metrics (eg. 0.0.0.0:9090)",
+		},
+		cli.StringFlag{
+			Name:  "exec-default-memory",
+			Usage: "default memory limit for build steps (eg. 512m)",
+		},
+		cli.StringFlag{
+			Name:  "exec-max-memory",
+			Usage: "maximum memory limit for build steps (eg. 2g)",
+		},
+		cli.Int64Flag{
+			Name:  "exec-max-pids",
+			Usage: "maximum number of processes in a build step",
+		},
+		cli.Float64Flag{
+			Name:  "exec-max-cpus",
+			Usage: "maximum number of CPUs a build step can use (eg. 1.5)",
+		},
+		cli.BoolFlag{
+			Name:  "exec-readonly-rootfs",
+			Usage: "run all build steps with a read-only root filesystem",
+		},
+		cli.StringFlag{
+			Name:  "exec-network",
+			Usage: "network of the build steps that don't set one: the network of the worker by default, none for a network namespace with only a loopback interface, or host",
+		},
+		cli.BoolFlag{
+			Name:  "exec-proxy-env",
+			Usage: "pass the proxy environment variables of the daemon to build steps",
+		},
+		cli.StringSliceFlag{
+			Name:  "exec-allow-host-mount",
+			Usage: "allow build steps to mount the host path, or a path under it, read-only",
+		},
+		cli.StringFlag{
+			Name:  "exec-seccomp-profile",
+			Usage: "confine build steps with the seccomp configuration of the OCI runtime spec in the JSON file, or unconfined",
+		},
+		cli.StringFlag{
+			Name:  "exec-apparmor-profile",
+			Usage: "confine build steps with the AppArmor profile loaded on the host",
+		},
+		cli.StringFlag{
+			Name:  "exec-selinux-label",
+			Usage: "run build steps with the SELinux process label",
+		},
+		cli.StringFlag{
+			Name:  "cgroup-parent",
+			Usage: "create the cgroups of build steps, and of the builds that set their own, under the cgroup",
+		},
+		cli.StringSliceFlag{
+			Name:  "dns",
+			Usage: "nameserver of build steps instead of the ones of the host",
+		},
+		cli.StringSliceFlag{
+			Name:  "dns-search",
+			Usage: "search domain of build steps instead of the ones of the host",
+		},
+		cli.StringSliceFlag{
+			Name:  "dns-option",
+			Usage: "resolver option of build steps instead of the ones of the host",
+		},
+		cli.StringSliceFlag{
+			Name:  "allow-insecure-entitlement",
+			Usage: "allow builds to request an entitlement: network.host, security.insecure, device, security.profile",
+		},
+		cli.StringFlag{
+			Name:  "oci-layout-root",
+			Usage: "directory of the OCI image layouts of the daemon that builds can use, by their path relative to it",
+		},
+		cli.StringSliceFlag{
+			Name:  "remote-worker",
+			Usage: "tcp://host:port address of a buildd whose default worker runs the build steps selecting its platform or labels, requires the TLS flags",
+		},
+		cli.StringFlag{
+			Name:  "remote-exec-addr",
+			Usage: "TCP address (eg. 0.0.0.0:1235) that runs the build steps of the remote workers of other buildd, requires the TLS flags",
+		},
+		cli.StringFlag{
+			Name:  "tlscacert",
+			Usage: "CA certificate verifying the remote workers and the clients of the remote exec address",
+		},
+		cli.StringFlag{
+			Name:  "tlscert",
+			Usage: "TLS certificate of the daemon for the remote workers and the remote exec address",
+		},
+		cli.StringFlag{
+			Name:  "tlskey",
+			Usage: "TLS key of the daemon for the remote workers and the remote exec address",
+		},
+		cli.BoolFlag{
+			Name:  "provenance",
+			Usage: "record how build steps were run and pass the record to the exporters",
+		},
+		cli.BoolFlag{
+			Name:  "disable-history",
+			Usage: "don't keep the records and the logs of the builds",
+		},
+		cli.BoolFlag{
+			Name:  "keep-completed",
+			Usage: "keep the results of completed build steps in the cache when a build fails",
+		},
+		cli.BoolFlag{
+			Name:  "dedup-commits",
+			Usage: "reuse the cache record of a build step with identical changes instead of storing a duplicate snapshot",
+		},
+		cli.IntFlag{
+			Name:  "max-concurrent-downloads",
+			Usage: "maximum number of layers downloaded in parallel for an image pull (default 3)",
+		},
+		cli.BoolFlag{
+			Name:  "estargz",
+			Usage: "read the files of eStargz base images without unpacking them",
+		},
+		cli.IntFlag{
+			Name:  "max-parallelism",
+			Usage: "maximum number of build steps that run at the same time in all the builds (default unlimited)",
+		},
+		cli.IntFlag{
+			Name:  "max-concurrent-solves",
+			Usage: "maximum number of builds that run at the same time, the other builds wait in a queue (default unlimited)",
+		},
+		cli.IntFlag{
+			Name:  "max-solves-per-client",
+			Usage: "maximum number of builds of a client that run at the same time (default unlimited)",
+		},
+		cli.StringFlag{
+			Name:  "registry-config",
+			Usage: "path of the JSON registry configuration with mirrors, TLS and credential helpers per host",
+		},
+		cli.DurationFlag{
+			Name:  "http-cache-ttl",
+			Usage: "time a downloaded URL is reused without revalidating it with the server",
+		},
+		cli.StringFlag{
+			Name:  "gc-keep-storage",
+			Usage: "size of the unused cache that is kept by the garbage collection (eg. 10GB)",
+		},
+		cli.DurationFlag{
+			Name:  "gc-keep-duration",
+			Usage: "time the unused cache is kept by the garbage collection after last use",
+		},
+		cli.StringSliceFlag{
+			Name:  "gc-filter",
+			Usage: "limit the garbage collection to the cache records matching the filter",
+		},
+		cli.DurationFlag{
+			Name:  "gc-interval",
+			Usage: "how often the garbage collection runs",
+			Value: 5 * time.Minute,
+		},
+		cli.DurationFlag{
+			Name:  "shutdown-timeout",
+			Usage: "time the running and queued builds are waited for on shutdown before they are canceled",
+			Value: time.Minute,
+		},
 	}
 
 	app.Flags = appendFlags(app.Flags)
@@ -54,7 +227,13 @@ func main() {
 			}
 		}
 
-		server := grpc.NewServer(unaryInterceptor(ctx))
+		// the requests are canceled after the running builds are drained
+		reqCtx, reqCancel := context.WithCancel(context.Background())
+		defer reqCancel()
+
+		// the clients of the socket are identified by their uid for the
+		// limits per client
+		server := grpc.NewServer(grpc.Creds(peercred.NewCredentials()), unaryInterceptor(reqCtx, nil))
 
 		// relative path does not work with nightlyone/lockfile
 		root, err := filepath.Abs(c.GlobalString("root"))
@@ -72,11 +251,25 @@ func main() {
 		}
 
 		controller.Register(server)
+		metrics.MustRegister(controller.Collectors()...)
+
+		if metricsAddr := c.GlobalString("metrics-addr"); metricsAddr != "" {
+			if err := setupMetricsHandler(metricsAddr); err != nil {
+				return err
+			}
+		}
 
 		errCh := make(chan error, 1)
 		if err := serveGRPC(server, c.GlobalString("socket"), errCh); err != nil {
 			return err
 		}
+		var execServer *grpc.Server
+		if addr := c.GlobalString("remote-exec-addr"); addr != "" {
+			execServer, err = serveRemoteExec(c, controller, addr, reqCtx, errCh)
+			if err != nil {
+				return err
+			}
+		}
 
 		select {
 		case serverErr := <-errCh:
@@ -87,7 +280,16 @@ func main() {
 		}
 
 		logrus.Infof("stopping server")
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), c.GlobalDuration("shutdown-timeout"))
+		if err := controller.Shutdown(shutdownCtx); err != nil {
+			logrus.Errorf("failed to shut down controller: %+v", err)
+		}
+		shutdownCancel()
+		reqCancel()
 		server.GracefulStop()
+		if execServer != nil {
+			execServer.GracefulStop()
+		}
 
 		return err
 	}
@@ -122,9 +324,14 @@ func serveGRPC(server *grpc.Server, path string, errCh chan error) error {
 	return nil
 }
 
-func unaryInterceptor(globalCtx context.Context) grpc.ServerOption {
+// unaryInterceptor cancels the requests with globalCtx. Only the methods are
+// served if they are not nil.
+func unaryInterceptor(globalCtx context.Context, methods map[string]struct{}) grpc.ServerOption {
 	return grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
-		ctx, cancel := context.WithCancel(ctx)
+		if _, ok := methods[info.FullMethod]; methods != nil && !ok {
+			return nil, status.Errorf(codes.PermissionDenied, "%s is not served on this address", info.FullMethod)
+		}
+		ctx, cancel := context.WithCancel(tracing.FromIncomingContext(ctx))
 		defer cancel()
 
 		go func() {
@@ -142,3 +349,109 @@ func unaryInterceptor(globalCtx context.Context) grpc.ServerOption {
 		return
 	})
 }
+
+var proxyEnvKeys = []string{"HTTP_PROXY", "HTTPS_PROXY", "FTP_PROXY", "NO_PROXY"}
+
+// proxyEnv returns the proxy variables set in the environment of the daemon
+func proxyEnv() []string {
+	var env []string
+	for _, k := range proxyEnvKeys {
+		for _, k := range []string{k, strings.ToLower(k)} {
+			if v, ok := os.LookupEnv(k); ok {
+				env = append(env, k+"="+v)
+			}
+		}
+	}
+	return env
+}
+
+// gcPolicy returns nil if garbage collection is not configured
+func gcPolicy(c *cli.Context) (*cache.GCPolicy, error) {
+	p := &cache.GCPolicy{
+		MaxKeepDuration: c.GlobalDuration("gc-keep-duration"),
+		Filter:          c.GlobalStringSlice("gc-filter"),
+		Interval:        c.GlobalDuration("gc-interval"),
+	}
+	if v := c.GlobalString("gc-keep-storage"); v != "" {
+		s, err := units.FromHumanSize(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid storage limit %s", v)
+		}
+		p.MaxSize = uint64(s)
+	}
+	if p.MaxSize == 0 && p.MaxKeepDuration == 0 && len(p.Filter) == 0 {
+		return nil, nil
+	}
+	return p, nil
+}
+
+const cpuPeriod = 100000
+
+func resourcePolicy(c *cli.Context) (*solver.ResourcePolicy, error) {
+	rp := &solver.ResourcePolicy{}
+	if v := c.GlobalString("exec-default-memory"); v != "" {
+		m, err := units.RAMInBytes(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid memory limit %s", v)
+		}
+		rp.Default.Memory = m
+	}
+	if v := c.GlobalString("exec-max-memory"); v != "" {
+		m, err := units.RAMInBytes(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid memory limit %s", v)
+		}
+		rp.Max.Memory = m
+	}
+	rp.Max.PidsLimit = c.GlobalInt64("exec-max-pids")
+	if cpus := c.GlobalFloat64("exec-max-cpus"); cpus > 0 {
+		rp.Max.CPUQuota = int64(cpus * cpuPeriod)
+		rp.Default.CPUPeriod = cpuPeriod
+		rp.Max.CPUPeriod = cpuPeriod
+	}
+	return rp, nil
+}
+
+// dnsConfig returns the resolver configuration of the build steps, nil to
+// use the one of the host
+func dnsConfig(c *cli.Context) (*worker.DNSConfig, error) {
+	dns := &worker.DNSConfig{
+		Nameservers:   c.GlobalStringSlice("dns"),
+		SearchDomains: c.GlobalStringSlice("dns-search"),
+		Options:       c.GlobalStringSlice("dns-option"),
+	}
+	if len(dns.Nameservers) == 0 && len(dns.SearchDomains) == 0 && len(dns.Options) == 0 {
+		return nil, nil
+	}
+	for _, ns := range dns.Nameservers {
+		if net.ParseIP(ns) == nil {
+			return nil, errors.Errorf("invalid nameserver %s", ns)
+		}
+	}
+	return dns, nil
+}
+
+// securityProfile returns the profiles that confine the build steps. The
+// seccomp profile is read from a file, unless it is "unconfined".
+func securityProfile(c *cli.Context) (*worker.SecurityProfile, error) {
+	sp := &worker.SecurityProfile{
+		AppArmor:     c.GlobalString("exec-apparmor-profile"),
+		SELinuxLabel: c.GlobalString("exec-selinux-label"),
+	}
+	switch p := c.GlobalString("exec-seccomp-profile"); p {
+	case "":
+	case worker.Unconfined:
+		sp.Seccomp = p
+	default:
+		dt, err := ioutil.ReadFile(p)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read seccomp profile")
+		}
+		var sc specs.LinuxSeccomp
+		if err := json.Unmarshal(dt, &sc); err != nil {
+			return nil, errors.Wrapf(err, "invalid seccomp profile %s", p)
+		}
+		sp.Seccomp = string(dt)
+	}
+	return sp, nil
+}
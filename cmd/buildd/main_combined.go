@@ -0,0 +1,50 @@
+// +build standalone,containerd
+
+package main
+
+import (
+	"github.com/moby/buildkit/control"
+	"github.com/moby/buildkit/util/appdefaults"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+func appendFlags(f []cli.Flag) []cli.Flag {
+	return append(f, []cli.Flag{
+		cli.StringFlag{
+			Name:  "worker",
+			Usage: "worker running the builds: runc, or containerd to share the snapshots, content and images with a containerd daemon",
+			Value: "runc",
+		},
+		cli.StringFlag{
+			Name:  "containerd",
+			Usage: "containerd socket of the containerd worker",
+			Value: appdefaults.ContainerdAddress,
+		},
+		cli.BoolFlag{
+			Name:  "rootless",
+			Usage: "run the build steps without privileges on the host. buildd needs to run as root in a user namespace, e.g. with rootlesskit",
+		},
+		cli.StringFlag{
+			Name:  "snapshotter",
+			Usage: "driver storing the snapshots of the runc worker: overlayfs, native, btrfs, or auto to detect it from the filesystem of the root directory",
+			Value: "auto",
+		},
+	}...)
+}
+
+// root must be an absolute path
+func newController(c *cli.Context, root string) (*control.Controller, error) {
+	opts, err := controllerOpts(c)
+	if err != nil {
+		return nil, err
+	}
+	switch w := c.GlobalString("worker"); w {
+	case "runc":
+		return control.NewStandalone(root, opts...)
+	case "containerd":
+		return control.NewContainerd(root, c.GlobalString("containerd"), opts...)
+	default:
+		return nil, errors.Errorf("invalid worker %q, expected runc or containerd", w)
+	}
+}
@@ -4,6 +4,7 @@ package main
 
 import (
 	"github.com/moby/buildkit/control"
+	"github.com/moby/buildkit/util/appdefaults"
 	"github.com/urfave/cli"
 )
 
@@ -12,14 +13,16 @@ func appendFlags(f []cli.Flag) []cli.Flag {
 		cli.StringFlag{
 			Name:  "containerd",
 			Usage: "containerd socket",
-			Value: "/run/containerd/containerd.sock",
+			Value: appdefaults.ContainerdAddress,
 		},
 	}...)
 }
 
 // root must be an absolute path
 func newController(c *cli.Context, root string) (*control.Controller, error) {
-	socket := c.GlobalString("containerd")
-
-	return control.NewContainerd(root, socket)
+	opts, err := controllerOpts(c)
+	if err != nil {
+		return nil, err
+	}
+	return control.NewContainerd(root, c.GlobalString("containerd"), opts...)
 }
@@ -8,10 +8,24 @@ import (
 )
 
 func appendFlags(f []cli.Flag) []cli.Flag {
-	return f
+	return append(f, []cli.Flag{
+		cli.BoolFlag{
+			Name:  "rootless",
+			Usage: "run the build steps without privileges on the host. buildd needs to run as root in a user namespace, e.g. with rootlesskit",
+		},
+		cli.StringFlag{
+			Name:  "snapshotter",
+			Usage: "driver storing the snapshots of the runc worker: overlayfs, native, btrfs, or auto to detect it from the filesystem of the root directory",
+			Value: "auto",
+		},
+	}...)
 }
 
 // root must be an absolute path
 func newController(c *cli.Context, root string) (*control.Controller, error) {
-	return control.NewStandalone(root)
+	opts, err := controllerOpts(c)
+	if err != nil {
+		return nil, err
+	}
+	return control.NewStandalone(root, opts...)
 }
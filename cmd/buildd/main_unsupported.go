@@ -1,4 +1,4 @@
-// +build !standalone,!containerd standalone,containerd
+// +build !standalone,!containerd
 
 package main
 
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net"
+
+	"github.com/moby/buildkit/control"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+)
+
+// remoteExecMethods are the methods served on the remote exec address, the
+// ones the remote workers of other daemons use
+var remoteExecMethods = map[string]struct{}{
+	"/moby.buildkit.v1.Control/Exec":        {},
+	"/moby.buildkit.v1.Control/ListWorkers": {},
+}
+
+// serveRemoteExec serves the execs of the remote workers of other daemons on
+// the TCP address addr. The clients have to authenticate with a certificate
+// signed by the CA of --tlscacert.
+func serveRemoteExec(c *cli.Context, controller *control.Controller, addr string, reqCtx context.Context, errCh chan error) (*grpc.Server, error) {
+	cfg, err := serverTLSConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	server := grpc.NewServer(grpc.Creds(credentials.NewTLS(cfg)), unaryInterceptor(reqCtx, remoteExecMethods), grpc.StreamInterceptor(func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if _, ok := remoteExecMethods[info.FullMethod]; !ok {
+			return status.Errorf(codes.PermissionDenied, "%s is not served on this address", info.FullMethod)
+		}
+		return handler(srv, ss)
+	}))
+	controller.Register(server)
+
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to listen on %s", addr)
+	}
+	go func() {
+		defer l.Close()
+		logrus.Infof("running remote exec server on %s", addr)
+		errCh <- server.Serve(l)
+	}()
+	return server, nil
+}
+
+// serverTLSConfig returns the TLS configuration of the remote exec address
+// that requires verified client certificates
+func serverTLSConfig(c *cli.Context) (*tls.Config, error) {
+	ca, certFile, keyFile := c.GlobalString("tlscacert"), c.GlobalString("tlscert"), c.GlobalString("tlskey")
+	if ca == "" || certFile == "" || keyFile == "" {
+		return nil, errors.New("--remote-exec-addr requires --tlscacert, --tlscert and --tlskey")
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read certificate/key")
+	}
+	dt, err := ioutil.ReadFile(ca)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read ca certificate")
+	}
+	certPool := x509.NewCertPool()
+	if ok := certPool.AppendCertsFromPEM(dt); !ok {
+		return nil, errors.New("failed to append ca certs")
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    certPool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
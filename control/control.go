@@ -1,55 +1,236 @@
 package control
 
 import (
+	"sort"
+	"time"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/platforms"
+	"github.com/containerd/containerd/rootfs"
 	"github.com/containerd/containerd/snapshot"
 	controlapi "github.com/moby/buildkit/api/services/control"
 	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/cache/cacheimport"
+	"github.com/moby/buildkit/cache/metadata"
 	"github.com/moby/buildkit/client"
 	"github.com/moby/buildkit/exporter"
 	"github.com/moby/buildkit/frontend"
+	"github.com/moby/buildkit/history"
+	"github.com/moby/buildkit/identity"
 	"github.com/moby/buildkit/session"
 	"github.com/moby/buildkit/session/grpchijack"
 	"github.com/moby/buildkit/solver"
+	"github.com/moby/buildkit/solver/pb"
 	"github.com/moby/buildkit/source"
+	"github.com/moby/buildkit/util/entitlements"
+	"github.com/moby/buildkit/util/metrics"
+	"github.com/moby/buildkit/util/peercred"
+	"github.com/moby/buildkit/util/resolver"
 	"github.com/moby/buildkit/worker"
+	"github.com/moby/buildkit/worker/remoteworker"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/net/context"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type Opt struct {
 	Snapshotter      snapshot.Snapshotter
 	CacheManager     cache.Manager
-	Worker           worker.Worker
+	MetadataStore    *metadata.Store
+	Workers          *worker.Controller
 	SourceManager    *source.Manager
 	InstructionCache solver.InstructionCache
 	Exporters        map[string]exporter.Exporter
 	SessionManager   *session.Manager
 	Frontends        map[string]frontend.Frontend
 	ImageSource      source.Source
+	CacheExporter    *cacheimport.CacheExporter
+	CacheImporter    *cacheimport.CacheImporter
+	ResourcePolicy   solver.ResourcePolicy
+	ReadonlyRootFS   bool
+	ProxyEnv         []string
+	Provenance       bool
+	GCPolicy         cache.GCPolicy
+	// DefaultNetMode is the network of the execs that don't set one
+	DefaultNetMode worker.NetMode
+	// MaxParallelism limits the number of ops that run at the same time in
+	// all the builds
+	MaxParallelism int
+	// HostMounts are the host paths that the builds can bind read-only into
+	// their execs
+	HostMounts []string
+	// Entitlements are the entitlements the builds can request
+	Entitlements []entitlements.Entitlement
+	// SecurityProfile confines the execs that don't set their own profiles
+	SecurityProfile worker.SecurityProfile
+	// CgroupParent is the cgroup the execs and the cgroups of the builds are
+	// created under
+	CgroupParent string
+	// DNS configures the resolv.conf of the execs instead of the one of the
+	// host
+	DNS *worker.DNSConfig
+	// DedupCommits reuses the cache records with identical changes instead of
+	// storing duplicate snapshots
+	DedupCommits bool
+	// KeepCompleted keeps the results of the completed build steps in the
+	// cache when a build fails
+	KeepCompleted bool
+	// Differ and Applier create and apply the layer diffs of the snapshots
+	Differ  rootfs.MountDiffer
+	Applier rootfs.Applier
+	// ContentStore stores the blobs of the Differ
+	ContentStore content.Store
+	// MaxConcurrentDownloads limits the parallel layer downloads of a pull
+	MaxConcurrentDownloads int
+	// EStargz reads the files of eStargz base images without unpacking them
+	EStargz bool
+	// RegistryConfig configures the access to the registries for pulling
+	// images and pushing the cache
+	RegistryConfig resolver.Config
+	// HTTPCacheTTL is the time a downloaded URL is reused without
+	// revalidating it with the server
+	HTTPCacheTTL time.Duration
+	// Rootless runs the daemon and the processes of the builds without
+	// privileges on the host
+	Rootless bool
+	// History stores the records and the logs of the builds. The history is
+	// not recorded if it is nil.
+	History *history.Store
+	// DisableHistory doesn't create the History of the daemon
+	DisableHistory bool
+	// QueuePolicy limits the number of builds that run at the same time
+	QueuePolicy QueuePolicy
+	// OCILayoutRoot is the directory of the OCI image layouts of the daemon
+	// that the builds can use. The builds can only use the layouts of the
+	// clients if it is empty.
+	OCILayoutRoot string
+	// RemoteWorkers are the daemons that are added as remote workers
+	RemoteWorkers RemoteWorkerConfig
+	// RemoteExec runs the processes of the remote workers of other daemons
+	// that authenticate with a TLS client certificate
+	RemoteExec bool
+	// SnapshotterDriver is the name of the driver that stores the snapshots
+	// of the standalone daemon, see the drivers package. The driver is
+	// detected if it is empty.
+	SnapshotterDriver string
+	// MergeOverlay stacks the inputs of the merge ops with overlayfs. It is
+	// set when the snapshotter is overlayfs.
+	MergeOverlay bool
+}
+
+// RemoteWorkerConfig are the addresses of the daemons whose default workers
+// are added as remote workers and the TLS files the connections to them are
+// authenticated with. The addresses are tcp://host:port.
+type RemoteWorkerConfig struct {
+	Addresses []string
+	CACert    string
+	Cert      string
+	Key       string
 }
 
 type Controller struct { // TODO: ControlService
 	opt    Opt
 	solver *solver.Solver
+	queue  *solveQueue
 }
 
 func NewController(opt Opt) (*Controller, error) {
 	c := &Controller{
-		opt: opt,
+		opt:   opt,
+		queue: newSolveQueue(opt.QueuePolicy),
 		solver: solver.NewLLBSolver(solver.LLBOpt{
 			SourceManager:    opt.SourceManager,
 			CacheManager:     opt.CacheManager,
-			Worker:           opt.Worker,
+			MetadataStore:    opt.MetadataStore,
+			Workers:          opt.Workers,
 			InstructionCache: opt.InstructionCache,
 			ImageSource:      opt.ImageSource,
+			MaxParallelism:   opt.MaxParallelism,
+			CacheExporter:    opt.CacheExporter,
+			CacheImporter:    opt.CacheImporter,
+			SessionManager:   opt.SessionManager,
+			ResourcePolicy:   opt.ResourcePolicy,
+			ReadonlyRootFS:   opt.ReadonlyRootFS,
+			DefaultNetMode:   opt.DefaultNetMode,
+			ProxyEnv:         opt.ProxyEnv,
+			HostMounts:       opt.HostMounts,
+			SecurityProfile:  opt.SecurityProfile,
+			CgroupParent:     opt.CgroupParent,
+			DNS:              opt.DNS,
+			Provenance:       opt.Provenance,
+			KeepCompleted:    opt.KeepCompleted,
+			Differ:           opt.Differ,
+			Applier:          opt.Applier,
+			ContentStore:     opt.ContentStore,
+			MergeOverlay:     opt.MergeOverlay,
 		}),
 	}
 	return c, nil
 }
 
+// shutdownCancelTimeout is the time Shutdown waits for the builds it cancels
+const shutdownCancelTimeout = 10 * time.Second
+
+// Shutdown stops admitting builds and waits for the running and the queued
+// builds to complete, so that their results are committed to the cache,
+// before closing the workers and the stores of the controller. The builds that are still
+// running or queued when ctx is done are canceled.
+func (c *Controller) Shutdown(ctx context.Context) error {
+	c.queue.close()
+	if err := c.queue.wait(ctx); err != nil {
+		running, waiting := c.queue.counts()
+		logrus.Warnf("canceling %d running and %d queued builds", running, waiting)
+		c.queue.cancelAll()
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownCancelTimeout)
+		defer cancel()
+		if err := c.queue.wait(ctx); err != nil {
+			return errors.Wrap(err, "failed to wait for the canceled builds")
+		}
+	}
+	if c.opt.Workers != nil {
+		if err := c.opt.Workers.Close(); err != nil {
+			return errors.Wrap(err, "failed to close workers")
+		}
+	}
+	if c.opt.History != nil {
+		if err := c.opt.History.Close(); err != nil {
+			return errors.Wrap(err, "failed to close history")
+		}
+	}
+	if c.opt.CacheManager != nil {
+		if err := c.opt.CacheManager.Close(); err != nil {
+			return errors.Wrap(err, "failed to close cache")
+		}
+	}
+	return nil
+}
+
+// cacheSizeTTL is the time the size of the cache is reused for the metrics,
+// the disk usage walks all the records of the cache
+const cacheSizeTTL = 30 * time.Second
+
+// Collectors returns the metrics of the cache of the controller
+func (c *Controller) Collectors() []metrics.Collector {
+	return []metrics.Collector{
+		metrics.NewCachedGaugeFunc("buildkit_cache_size_bytes", "Size of the records of the cache.", cacheSizeTTL, func() float64 {
+			du, err := c.opt.CacheManager.DiskUsage(context.TODO(), client.DiskUsageInfo{})
+			if err != nil {
+				logrus.Errorf("failed to get disk usage for metrics: %v", err)
+				return 0
+			}
+			var size int64
+			for _, d := range du {
+				size += d.Size
+			}
+			return float64(size)
+		}),
+	}
+}
+
 func (c *Controller) Register(server *grpc.Server) error {
 	controlapi.RegisterControlServer(server, c)
 	return nil
@@ -65,21 +246,53 @@ func (c *Controller) DiskUsage(ctx context.Context, r *controlapi.DiskUsageReque
 
 	resp := &controlapi.DiskUsageResponse{}
 	for _, r := range du {
-		resp.Record = append(resp.Record, &controlapi.UsageRecord{
-			ID:          r.ID,
-			Mutable:     r.Mutable,
-			InUse:       r.InUse,
-			Size_:       r.Size,
-			Parent:      r.Parent,
-			UsageCount:  int64(r.UsageCount),
-			Description: r.Description,
-			CreatedAt:   r.CreatedAt,
-			LastUsedAt:  r.LastUsedAt,
-		})
+		resp.Record = append(resp.Record, toControlUsageRecord(*r))
 	}
 	return resp, nil
 }
 
+// toControlUsageRecord returns the record of the API for the cache record r,
+// including the parent so the clients can follow the chains of the records
+func toControlUsageRecord(r client.UsageInfo) *controlapi.UsageRecord {
+	return &controlapi.UsageRecord{
+		ID:          r.ID,
+		Mutable:     r.Mutable,
+		InUse:       r.InUse,
+		Size_:       r.Size,
+		Parent:      r.Parent,
+		UsageCount:  int64(r.UsageCount),
+		Description: r.Description,
+		CreatedAt:   r.CreatedAt,
+		LastUsedAt:  r.LastUsedAt,
+		Refs:        int64(r.Refs),
+	}
+}
+
+func (c *Controller) Prune(req *controlapi.PruneRequest, stream controlapi.Control_PruneServer) error {
+	ch := make(chan client.UsageInfo)
+
+	eg, ctx := errgroup.WithContext(stream.Context())
+	eg.Go(func() error {
+		defer close(ch)
+		return c.opt.CacheManager.Prune(ctx, ch, client.PruneInfo{
+			Filter:       req.Filter,
+			KeepDuration: time.Duration(req.KeepDuration),
+			KeepBytes:    req.KeepBytes,
+		})
+	})
+
+	eg.Go(func() error {
+		for r := range ch {
+			if err := stream.Send(toControlUsageRecord(r)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return eg.Wait()
+}
+
 func (c *Controller) Solve(ctx context.Context, req *controlapi.SolveRequest) (*controlapi.SolveResponse, error) {
 	var frontend frontend.Frontend
 	if req.Frontend != "" {
@@ -114,10 +327,158 @@ func (c *Controller) Solve(ctx context.Context, req *controlapi.SolveRequest) (*
 		}
 	}
 
-	if err := c.solver.Solve(ctx, req.Ref, frontend, vertex, expi, req.FrontendAttrs); err != nil {
+	results := make(map[string]solver.Vertex, len(req.Results))
+	for _, r := range req.Results {
+		if _, ok := results[r.Name]; ok || r.Name == "" {
+			return nil, errors.Errorf("invalid result name %q", r.Name)
+		}
+		v, err := solver.LoadLLB(r.Definition)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load llb definition of result %s", r.Name)
+		}
+		results[r.Name] = v
+	}
+
+	inputs := make(map[string][][]byte, len(req.FrontendInputs))
+	for _, r := range req.FrontendInputs {
+		if _, ok := inputs[r.Name]; ok || r.Name == "" {
+			return nil, errors.Errorf("invalid frontend input name %q", r.Name)
+		}
+		if frontend == nil {
+			return nil, errors.Errorf("frontend input %s requires a frontend", r.Name)
+		}
+		if _, err := solver.LoadLLB(r.Definition); err != nil {
+			return nil, errors.Wrapf(err, "failed to load llb definition of frontend input %s", r.Name)
+		}
+		inputs[r.Name] = r.Definition
+	}
+
+	requested := make([]entitlements.Entitlement, 0, len(req.Entitlements))
+	for _, e := range req.Entitlements {
+		ent, err := entitlements.Parse(e)
+		if err != nil {
+			return nil, err
+		}
+		requested = append(requested, ent)
+	}
+	ents, err := entitlements.WhiteList(c.opt.Entitlements, requested)
+	if err != nil {
+		return nil, err
+	}
+
+	exports := make([]solver.Export, 0, len(req.Exports))
+	for _, e := range req.Exports {
+		exp, ok := c.opt.Exporters[e.Exporter]
+		if !ok {
+			return nil, errors.Errorf("exporter %q could not be found", e.Exporter)
+		}
+		expi, err := exp.Resolve(ctx, e.Attrs)
+		if err != nil {
+			return nil, err
+		}
+		exports = append(exports, solver.Export{Result: e.Result, Exporter: expi})
+	}
+
+	sreq := solver.SolveRequest{
+		Frontend:       frontend,
+		Definition:     vertex,
+		Exporter:       expi,
+		FrontendOpt:    req.FrontendAttrs,
+		FrontendInputs: inputs,
+		CacheImports:   cacheEntries(req.Cache.ImportRef, req.Cache.Imports, ""),
+		CacheExports:   cacheEntries(req.Cache.ExportRef, req.Cache.Exports, cacheimport.ModeMax),
+		Results:        results,
+		Exports:        exports,
+		Entitlements:   ents,
+		CgroupParent:   req.CgroupParent,
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// the dry runs also use the cache, so they are queued as well for the
+	// shutdown to wait for them. Their refs are not the refs of the builds.
+	queueRef := req.Ref
+	if req.DryRun {
+		queueRef = "dryrun-" + identity.NewID()
+	}
+	// the limits per client use the identity of the peer instead of the
+	// name the client sends, which it could change for every build
+	release, err := c.queue.acquire(ctx, queueRef, peercred.Identity(ctx), int(req.Priority), cancel)
+	if err != nil {
+		if err == errQueueClosed {
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
+		return nil, err
+	}
+	defer release()
+
+	if req.DryRun {
+		report, err := c.solver.DryRun(ctx, req.Ref, sreq)
+		if err != nil {
+			return nil, err
+		}
+		return &controlapi.SolveResponse{DryRun: toControlDryRunReport(report)}, nil
+	}
+
+	var historyDone chan struct{}
+	if c.opt.History != nil {
+		rec := history.Record{
+			Ref:           req.Ref,
+			Frontend:      req.Frontend,
+			FrontendAttrs: req.FrontendAttrs,
+			Exporter:      req.Exporter,
+			ExporterAttrs: req.ExporterAttrs,
+		}
+		if vertex != nil {
+			rec.Definition = vertex.Digest()
+		}
+		if err := c.opt.History.Start(rec); err != nil {
+			logrus.Errorf("failed to start history record %s: %+v", req.Ref, err)
+		} else {
+			// the solve closes the channel even if it fails before the job
+			// is created
+			ch := make(chan *client.SolveStatus, 8)
+			sreq.Status = ch
+			historyDone = make(chan struct{})
+			go func() {
+				defer close(historyDone)
+				c.recordHistory(req.Ref, ch)
+			}()
+		}
+	}
+
+	resp, err := c.solver.Solve(ctx, req.Ref, sreq)
+	if historyDone != nil {
+		<-historyDone
+		var exporterResponse map[string]string
+		if err == nil {
+			exporterResponse = resp.ExporterResponse
+		}
+		if err := c.opt.History.Finish(req.Ref, exporterResponse, err); err != nil {
+			logrus.Errorf("failed to finish history record %s: %+v", req.Ref, err)
+		}
+	}
+	if err != nil {
 		return nil, err
 	}
-	return &controlapi.SolveResponse{}, nil
+	exportResps := make([]*controlapi.ExportResponse, 0, len(resp.Exports))
+	for _, r := range resp.Exports {
+		exportResps = append(exportResps, &controlapi.ExportResponse{Response: r})
+	}
+	return &controlapi.SolveResponse{
+		ExporterResponse: resp.ExporterResponse,
+		Exports:          exportResps,
+		Summary:          toControlBuildSummary(resp.Summary),
+	}, nil
+}
+
+// recordHistory saves the progress of the solve ref until ch is closed
+func (c *Controller) recordHistory(ref string, ch chan *client.SolveStatus) {
+	for ss := range ch {
+		if err := c.opt.History.Update(ref, ss); err != nil {
+			logrus.Errorf("failed to update history record %s: %+v", ref, err)
+		}
+	}
 }
 
 func (c *Controller) Status(req *controlapi.StatusRequest, stream controlapi.Control_StatusServer) error {
@@ -125,6 +486,10 @@ func (c *Controller) Status(req *controlapi.StatusRequest, stream controlapi.Con
 
 	eg, ctx := errgroup.WithContext(stream.Context())
 	eg.Go(func() error {
+		// the build is not started while it is in the queue
+		if err := c.queue.waitStarted(ctx, req.Ref, statusGracePeriod); err != nil {
+			return err
+		}
 		return c.solver.Status(ctx, req.Ref, ch)
 	})
 
@@ -139,16 +504,7 @@ func (c *Controller) Status(req *controlapi.StatusRequest, stream controlapi.Con
 				}
 				sr := controlapi.StatusResponse{}
 				for _, v := range ss.Vertexes {
-					sr.Vertexes = append(sr.Vertexes, &controlapi.Vertex{
-						Digest:    v.Digest,
-						Inputs:    v.Inputs,
-						Name:      v.Name,
-						Started:   v.Started,
-						Completed: v.Completed,
-						Error:     v.Error,
-						Cached:    v.Cached,
-						Parent:    v.Parent,
-					})
+					sr.Vertexes = append(sr.Vertexes, toControlVertex(v))
 				}
 				for _, v := range ss.Statuses {
 					sr.Statuses = append(sr.Statuses, &controlapi.VertexStatus{
@@ -163,13 +519,9 @@ func (c *Controller) Status(req *controlapi.StatusRequest, stream controlapi.Con
 					})
 				}
 				for _, v := range ss.Logs {
-					sr.Logs = append(sr.Logs, &controlapi.VertexLog{
-						Vertex:    v.Vertex,
-						Stream:    int64(v.Stream),
-						Msg:       v.Data,
-						Timestamp: v.Timestamp,
-					})
+					sr.Logs = append(sr.Logs, toControlVertexLog(v))
 				}
+				sr.Summary = toControlBuildSummary(ss.Summary)
 				if err := stream.SendMsg(&sr); err != nil {
 					return err
 				}
@@ -188,3 +540,334 @@ func (c *Controller) Session(stream controlapi.Control_SessionServer) error {
 	logrus.Debugf("session finished: %v", err)
 	return err
 }
+
+// Exec runs the process of a remote worker of another daemon with the
+// default worker. The daemon has to authenticate with a TLS client
+// certificate and its processes wait in the queue of the builds.
+func (c *Controller) Exec(stream controlapi.Control_ExecServer) error {
+	if !c.opt.RemoteExec {
+		return status.Error(codes.PermissionDenied, "remote execs are not enabled")
+	}
+	ctx := stream.Context()
+	if _, ok := peercred.Certificate(ctx); !ok {
+		return status.Error(codes.Unauthenticated, "remote execs require a TLS client certificate")
+	}
+	if c.opt.Workers == nil {
+		return errors.Errorf("no workers for running processes")
+	}
+	w, err := c.opt.Workers.Default()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	release, err := c.queue.acquire(ctx, "exec-"+identity.NewID(), peercred.Identity(ctx), 0, cancel)
+	if err != nil {
+		if err == errQueueClosed {
+			return status.Error(codes.Unavailable, err.Error())
+		}
+		return err
+	}
+	defer release()
+
+	return remoteworker.Serve(ctx, stream, w, remoteworker.ServeOpt{
+		Entitlements: c.opt.Entitlements,
+		CgroupParent: c.opt.CgroupParent,
+		Resources:    c.opt.ResourcePolicy.Apply,
+
+		CacheManager:  c.opt.CacheManager,
+		MetadataStore: c.opt.MetadataStore,
+		Scope:         peercred.Identity(ctx),
+	})
+}
+
+func (c *Controller) ListWorkers(ctx context.Context, r *controlapi.ListWorkersRequest) (*controlapi.ListWorkersResponse, error) {
+	resp := &controlapi.ListWorkersResponse{}
+	if c.opt.Workers == nil {
+		return resp, nil
+	}
+	workers, err := c.opt.Workers.List(r.Filter...)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range workers {
+		rec := &controlapi.WorkerRecord{
+			ID:     w.ID,
+			Labels: w.Labels,
+		}
+		for _, p := range w.Platforms {
+			rec.Platforms = append(rec.Platforms, platforms.Format(p))
+		}
+		for _, p := range w.EmulatedPlatforms {
+			rec.EmulatedPlatforms = append(rec.EmulatedPlatforms, platforms.Format(p))
+		}
+		resp.Record = append(resp.Record, rec)
+	}
+	return resp, nil
+}
+
+func (c *Controller) Capabilities(ctx context.Context, r *controlapi.CapabilitiesRequest) (*controlapi.CapabilitiesResponse, error) {
+	resp := &controlapi.CapabilitiesResponse{}
+	var infos []worker.Info
+	if c.opt.Workers != nil {
+		var err error
+		if infos, err = c.opt.Workers.List(); err != nil {
+			return nil, err
+		}
+	}
+	for _, t := range pb.OpTypes {
+		// the execs need a worker and the diffs the differ of the daemon
+		if (t == "exec" && len(infos) == 0) || (t == "diff" && (c.opt.Differ == nil || c.opt.Applier == nil)) {
+			continue
+		}
+		resp.Ops = append(resp.Ops, t)
+	}
+	// the mount types are the ones of any of the platforms of the workers
+	seen := map[string]struct{}{}
+	for _, info := range infos {
+		ps := info.Platforms
+		if len(ps) == 0 {
+			ps = append(ps, platforms.Default())
+		}
+		for _, p := range ps {
+			for _, t := range pb.MountTypes(p.OS, len(c.opt.HostMounts) > 0) {
+				if _, ok := seen[t]; !ok {
+					seen[t] = struct{}{}
+					resp.MountTypes = append(resp.MountTypes, t)
+				}
+			}
+		}
+	}
+	for name := range c.opt.Exporters {
+		resp.Exporters = append(resp.Exporters, name)
+	}
+	sort.Strings(resp.Exporters)
+	for name := range c.opt.Frontends {
+		resp.Frontends = append(resp.Frontends, name)
+	}
+	sort.Strings(resp.Frontends)
+	if c.opt.CacheExporter != nil && c.opt.CacheImporter != nil {
+		resp.CacheFormats = []string{cacheimport.TypeLocal, cacheimport.TypeRegistry}
+	}
+	return resp, nil
+}
+
+func (c *Controller) QueueStatus(ctx context.Context, r *controlapi.QueueStatusRequest) (*controlapi.QueueStatusResponse, error) {
+	pos, ok := c.queue.position(r.Ref)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "build %s is not queued", r.Ref)
+	}
+	running, waiting := c.queue.counts()
+	return &controlapi.QueueStatusResponse{
+		Position: int32(pos),
+		Running:  int32(running),
+		Waiting:  int32(waiting),
+	}, nil
+}
+
+func (c *Controller) ListJobs(ctx context.Context, r *controlapi.ListJobsRequest) (*controlapi.ListJobsResponse, error) {
+	resp := &controlapi.ListJobsResponse{}
+	for _, j := range c.queue.jobs() {
+		resp.Jobs = append(resp.Jobs, &controlapi.Job{
+			Ref:       j.ref,
+			Client:    j.client,
+			Priority:  int32(j.priority),
+			CreatedAt: j.createdAt,
+			Position:  int32(j.position),
+		})
+	}
+	return resp, nil
+}
+
+func (c *Controller) Cancel(ctx context.Context, r *controlapi.CancelRequest) (*controlapi.CancelResponse, error) {
+	if !c.queue.cancel(r.Ref) {
+		return nil, status.Errorf(codes.NotFound, "build %s is not running", r.Ref)
+	}
+	return &controlapi.CancelResponse{}, nil
+}
+
+func (c *Controller) ListBuilds(ctx context.Context, r *controlapi.ListBuildsRequest) (*controlapi.ListBuildsResponse, error) {
+	resp := &controlapi.ListBuildsResponse{}
+	if c.opt.History == nil {
+		return resp, nil
+	}
+	var records []*history.Record
+	if r.Ref != "" {
+		rec, err := c.opt.History.Get(r.Ref)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	} else {
+		var err error
+		records, err = c.opt.History.List()
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, rec := range records {
+		resp.Record = append(resp.Record, toControlBuildRecord(rec))
+	}
+	return resp, nil
+}
+
+func (c *Controller) BuildLogs(req *controlapi.BuildLogsRequest, stream controlapi.Control_BuildLogsServer) error {
+	if c.opt.History == nil {
+		return errors.Errorf("build history is not enabled")
+	}
+	const batch = 64
+	resp := &controlapi.BuildLogsResponse{}
+	if err := c.opt.History.Logs(req.Ref, func(l *client.VertexLog) error {
+		resp.Logs = append(resp.Logs, toControlVertexLog(l))
+		if len(resp.Logs) < batch {
+			return nil
+		}
+		err := stream.Send(resp)
+		resp = &controlapi.BuildLogsResponse{}
+		return err
+	}); err != nil {
+		return err
+	}
+	if len(resp.Logs) == 0 {
+		return nil
+	}
+	return stream.Send(resp)
+}
+
+// cacheEntries returns the cache imports or exports of a request. ref is the
+// registry reference of the older clients, it is exported with mode.
+func cacheEntries(ref string, entries []*controlapi.CacheOptionsEntry, mode string) []cacheimport.Entry {
+	var out []cacheimport.Entry
+	if ref != "" {
+		e := cacheimport.Entry{Type: cacheimport.TypeRegistry, Attrs: map[string]string{cacheimport.AttrRef: ref}}
+		if mode != "" {
+			e.Attrs[cacheimport.AttrMode] = mode
+		}
+		out = append(out, e)
+	}
+	for _, e := range entries {
+		out = append(out, cacheimport.Entry{Type: e.Type, Attrs: e.Attrs})
+	}
+	return out
+}
+
+func toControlBuildRecord(rec *history.Record) *controlapi.BuildRecord {
+	r := &controlapi.BuildRecord{
+		Ref:           rec.Ref,
+		Definition:    rec.Definition,
+		Frontend:      rec.Frontend,
+		FrontendAttrs: rec.FrontendAttrs,
+		Exporter:      rec.Exporter,
+		ExporterAttrs: rec.ExporterAttrs,
+		CreatedAt:     rec.CreatedAt,
+		CompletedAt:   rec.CompletedAt,
+		Error:         rec.Error,
+		LogSize:       rec.LogSize,
+		LogsTruncated: rec.LogsTruncated,
+		Summary:       toControlBuildSummary(rec.Summary),
+
+		ExporterResponse: rec.ExporterResponse,
+	}
+	for _, v := range rec.Vertexes {
+		r.Vertexes = append(r.Vertexes, toControlVertex(v))
+	}
+	return r
+}
+
+func toControlVertex(v *client.Vertex) *controlapi.Vertex {
+	return &controlapi.Vertex{
+		Digest:    v.Digest,
+		Inputs:    v.Inputs,
+		Name:      v.Name,
+		Started:   v.Started,
+		Completed: v.Completed,
+		Error:     v.Error,
+		Cached:    v.Cached,
+		Parent:    v.Parent,
+		ExecError: toControlExecError(v.ExecError),
+
+		CacheMissReason: toControlCacheMissReason(v.CacheMissReason),
+		Internal:        v.Internal,
+		Group:           v.Group,
+		CacheKey:        v.CacheKey,
+		Timings:         toControlVertexTimings(v.Timings),
+	}
+}
+
+func toControlBuildSummary(s *client.BuildSummary) *controlapi.BuildSummary {
+	if s == nil {
+		return nil
+	}
+	return &controlapi.BuildSummary{
+		Vertexes:         int64(s.Vertexes),
+		Cached:           int64(s.Cached),
+		Executed:         int64(s.Executed),
+		ExecTime:         int64(s.ExecTime),
+		TransferredBytes: s.TransferredBytes,
+		ExportedBytes:    s.ExportedBytes,
+	}
+}
+
+func toControlVertexTimings(t *client.VertexTimings) *controlapi.VertexTimings {
+	if t == nil {
+		return nil
+	}
+	return &controlapi.VertexTimings{
+		CacheLookup: int64(t.CacheLookup),
+		ContentHash: int64(t.ContentHash),
+		Exec:        int64(t.Exec),
+		Commit:      int64(t.Commit),
+	}
+}
+
+func toControlVertexLog(l *client.VertexLog) *controlapi.VertexLog {
+	return &controlapi.VertexLog{
+		Vertex:    l.Vertex,
+		Stream:    int64(l.Stream),
+		Msg:       l.Data,
+		Timestamp: l.Timestamp,
+	}
+}
+
+func toControlDryRunReport(r *solver.DryRunReport) *controlapi.DryRunReport {
+	report := &controlapi.DryRunReport{Work: int64(r.Work)}
+	for _, v := range r.Vertexes {
+		report.Vertexes = append(report.Vertexes, &controlapi.DryRunVertex{
+			Digest:      v.Digest,
+			Name:        v.Name,
+			OpType:      v.OpType,
+			Inputs:      v.Inputs,
+			CacheKey:    v.CacheKey,
+			InputKeys:   v.InputKeys,
+			ContentKeys: v.ContentKeys,
+			Cached:      v.Cached,
+			Required:    v.Required,
+		})
+	}
+	return report
+}
+
+func toControlCacheMissReason(r *client.CacheMissReason) *controlapi.CacheMissReason {
+	if r == nil {
+		return nil
+	}
+	return &controlapi.CacheMissReason{
+		Reason: r.Reason,
+		Keys:   r.Keys,
+		Inputs: r.Inputs,
+	}
+}
+
+func toControlExecError(e *client.ExecError) *controlapi.ExecError {
+	if e == nil {
+		return nil
+	}
+	return &controlapi.ExecError{
+		Vertex:   e.Vertex,
+		Args:     e.Args,
+		ExitCode: e.ExitCode,
+		Signal:   int32(e.Signal),
+		Stderr:   e.Stderr,
+	}
+}
@@ -14,11 +14,15 @@ import (
 	"github.com/pkg/errors"
 )
 
-func NewContainerd(root, address string) (*Controller, error) {
+func NewContainerd(root, address string, opts ...ControllerOpt) (*Controller, error) {
 	if err := os.MkdirAll(root, 0700); err != nil {
 		return nil, errors.Wrapf(err, "failed to create %s", root)
 	}
 
+	if newOpt(opts).Rootless {
+		return nil, errors.Errorf("rootless mode is not supported by the containerd worker")
+	}
+
 	// TODO: take lock to make sure there are no duplicates
 	client, err := containerd.New(address, containerd.WithDefaultNamespace("buildkit"))
 	if err != nil {
@@ -27,12 +31,17 @@ func NewContainerd(root, address string) (*Controller, error) {
 
 	pd := newContainerdPullDeps(client)
 
-	opt, err := defaultControllerOpts(root, *pd)
+	opt, err := defaultControllerOpts(root, *pd, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	opt.Worker = containerdworker.New(client)
+	wc, err := newWorkerController(root, containerdworker.New(client), "containerd", containerd.DefaultSnapshotter, opt.RemoteWorkers)
+	if err != nil {
+		return nil, err
+	}
+	opt.MergeOverlay = containerd.DefaultSnapshotter == "overlayfs"
+	opt.Workers = wc
 
 	return NewController(*opt)
 }
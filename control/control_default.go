@@ -3,29 +3,261 @@
 package control
 
 import (
+	"io/ioutil"
+	"net/url"
+	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/containerd/containerd/content"
 	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/platforms"
 	"github.com/containerd/containerd/rootfs"
 	ctdsnapshot "github.com/containerd/containerd/snapshot"
 	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/cache/cacheimport"
 	"github.com/moby/buildkit/cache/instructioncache"
 	"github.com/moby/buildkit/cache/metadata"
 	"github.com/moby/buildkit/client"
 	"github.com/moby/buildkit/exporter"
 	imageexporter "github.com/moby/buildkit/exporter/containerimage"
 	localexporter "github.com/moby/buildkit/exporter/local"
+	ociexporter "github.com/moby/buildkit/exporter/oci"
+	tarexporter "github.com/moby/buildkit/exporter/tar"
 	"github.com/moby/buildkit/frontend"
 	"github.com/moby/buildkit/frontend/dockerfile"
+	"github.com/moby/buildkit/frontend/gateway"
+	"github.com/moby/buildkit/history"
+	"github.com/moby/buildkit/identity"
 	"github.com/moby/buildkit/session"
 	"github.com/moby/buildkit/snapshot/blobmapping"
+	"github.com/moby/buildkit/solver"
 	"github.com/moby/buildkit/source"
 	"github.com/moby/buildkit/source/containerimage"
 	"github.com/moby/buildkit/source/git"
+	httpsource "github.com/moby/buildkit/source/http"
 	"github.com/moby/buildkit/source/local"
+	"github.com/moby/buildkit/util/entitlements"
+	"github.com/moby/buildkit/util/resolver"
+	"github.com/moby/buildkit/worker"
+	"github.com/moby/buildkit/worker/remoteworker"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
 )
 
+// ControllerOpt changes the options of a controller created with the
+// default components
+type ControllerOpt func(*Opt)
+
+// WithResourcePolicy sets the default and maximum resources of exec ops
+func WithResourcePolicy(p solver.ResourcePolicy) ControllerOpt {
+	return func(opt *Opt) {
+		opt.ResourcePolicy = p
+	}
+}
+
+// WithReadonlyRootFS runs all execs with a read-only root filesystem
+func WithReadonlyRootFS() ControllerOpt {
+	return func(opt *Opt) {
+		opt.ReadonlyRootFS = true
+	}
+}
+
+// WithHostNetwork runs the execs that don't set a network with the network
+// of the host, without the network.host entitlement
+func WithHostNetwork() ControllerOpt {
+	return func(opt *Opt) {
+		opt.DefaultNetMode = worker.NetModeHost
+	}
+}
+
+// WithNoNetwork runs the execs that don't set a network in a new network
+// namespace with only a loopback interface
+func WithNoNetwork() ControllerOpt {
+	return func(opt *Opt) {
+		opt.DefaultNetMode = worker.NetModeNone
+	}
+}
+
+// WithMaxParallelism limits the number of ops that run at the same time in
+// all the builds
+func WithMaxParallelism(n int) ControllerOpt {
+	return func(opt *Opt) {
+		opt.MaxParallelism = n
+	}
+}
+
+// WithProxyEnv sets KEY=VALUE proxy variables that are added to the
+// environment of the execs
+func WithProxyEnv(env []string) ControllerOpt {
+	return func(opt *Opt) {
+		opt.ProxyEnv = env
+	}
+}
+
+// WithHostMounts allows the execs to bind the host paths, and the paths under
+// them, read-only
+func WithHostMounts(paths []string) ControllerOpt {
+	return func(opt *Opt) {
+		opt.HostMounts = paths
+	}
+}
+
+// WithEntitlements allows the builds to request the entitlements
+func WithEntitlements(ents []entitlements.Entitlement) ControllerOpt {
+	return func(opt *Opt) {
+		opt.Entitlements = ents
+	}
+}
+
+// WithSecurityProfile confines the execs with the seccomp, AppArmor and
+// SELinux profiles unless they set their own
+func WithSecurityProfile(p worker.SecurityProfile) ControllerOpt {
+	return func(opt *Opt) {
+		opt.SecurityProfile = p
+	}
+}
+
+// WithCgroupParent creates the cgroups of the execs and of the builds under
+// the cgroup p
+func WithCgroupParent(p string) ControllerOpt {
+	return func(opt *Opt) {
+		opt.CgroupParent = p
+	}
+}
+
+// WithDNS sets the nameservers, search domains and options of the
+// resolv.conf of the execs instead of the ones of the host
+func WithDNS(dns worker.DNSConfig) ControllerOpt {
+	return func(opt *Opt) {
+		opt.DNS = &dns
+	}
+}
+
+// WithProvenance passes a record of how the build steps were run to the
+// exporters
+func WithProvenance() ControllerOpt {
+	return func(opt *Opt) {
+		opt.Provenance = true
+	}
+}
+
+// WithKeepCompleted keeps the results of the completed build steps in the
+// cache when a build fails so that retrying it continues from them
+func WithKeepCompleted() ControllerOpt {
+	return func(opt *Opt) {
+		opt.KeepCompleted = true
+	}
+}
+
+// WithoutHistory doesn't record the history of the builds
+func WithoutHistory() ControllerOpt {
+	return func(opt *Opt) {
+		opt.DisableHistory = true
+	}
+}
+
+// WithGCPolicy sets the policy of the cache garbage collection
+func WithGCPolicy(p cache.GCPolicy) ControllerOpt {
+	return func(opt *Opt) {
+		opt.GCPolicy = p
+	}
+}
+
+// WithDedupCommits reuses an existing cache record when a build step
+// produces the same changes to its parent instead of storing a duplicate
+// snapshot
+func WithDedupCommits() ControllerOpt {
+	return func(opt *Opt) {
+		opt.DedupCommits = true
+	}
+}
+
+// WithQueuePolicy limits the number of builds that run at the same time
+func WithQueuePolicy(p QueuePolicy) ControllerOpt {
+	return func(opt *Opt) {
+		opt.QueuePolicy = p
+	}
+}
+
+// WithMaxConcurrentDownloads limits the number of layers downloaded in
+// parallel by an image pull
+func WithMaxConcurrentDownloads(n int) ControllerOpt {
+	return func(opt *Opt) {
+		opt.MaxConcurrentDownloads = n
+	}
+}
+
+// WithEStargz reads the files of the base images with eStargz layers, like
+// the configs of the frontends, from the layer blobs without unpacking the
+// images
+func WithEStargz() ControllerOpt {
+	return func(opt *Opt) {
+		opt.EStargz = true
+	}
+}
+
+// WithRegistryConfig sets the mirrors, TLS and credential options of the
+// registries
+func WithRegistryConfig(cfg resolver.Config) ControllerOpt {
+	return func(opt *Opt) {
+		opt.RegistryConfig = cfg
+	}
+}
+
+// WithHTTPCacheTTL reuses the downloads of the http sources for ttl
+// without revalidating them
+func WithHTTPCacheTTL(ttl time.Duration) ControllerOpt {
+	return func(opt *Opt) {
+		opt.HTTPCacheTTL = ttl
+	}
+}
+
+// WithRootless runs the processes without privileges on the host. The daemon
+// needs to run as root in a user namespace.
+func WithRootless() ControllerOpt {
+	return func(opt *Opt) {
+		opt.Rootless = true
+	}
+}
+
+// WithSnapshotterDriver sets the driver of the snapshots of the standalone
+// daemon, eg. overlayfs, native or btrfs
+func WithSnapshotterDriver(name string) ControllerOpt {
+	return func(opt *Opt) {
+		opt.SnapshotterDriver = name
+	}
+}
+
+// WithRemoteWorkers adds the default workers of the daemons as remote
+// workers. The execs run by them are selected with the platforms or the
+// worker labels of the vertices.
+func WithRemoteWorkers(cfg RemoteWorkerConfig) ControllerOpt {
+	return func(opt *Opt) {
+		opt.RemoteWorkers = cfg
+	}
+}
+
+// WithOCILayoutRoot allows the builds to use the OCI image layouts of the
+// daemon under the directory
+func WithOCILayoutRoot(root string) ControllerOpt {
+	return func(opt *Opt) {
+		opt.OCILayoutRoot = root
+	}
+}
+
+// WithRemoteExec runs the processes of the remote workers of other daemons.
+// The daemon has to serve the controller with TLS credentials that verify
+// the client certificates.
+func WithRemoteExec() ControllerOpt {
+	return func(opt *Opt) {
+		opt.RemoteExec = true
+	}
+}
+
 type pullDeps struct {
 	Snapshotter  ctdsnapshot.Snapshotter
 	ContentStore content.Store
@@ -34,7 +266,17 @@ type pullDeps struct {
 	Images       images.Store
 }
 
-func defaultControllerOpts(root string, pd pullDeps) (*Opt, error) {
+func newOpt(opts []ControllerOpt) *Opt {
+	opt := &Opt{}
+	for _, o := range opts {
+		o(opt)
+	}
+	return opt
+}
+
+func defaultControllerOpts(root string, pd pullDeps, opts ...ControllerOpt) (*Opt, error) {
+	opt := newOpt(opts)
+
 	md, err := metadata.NewStore(filepath.Join(root, "metadata.db"))
 	if err != nil {
 		return nil, err
@@ -52,6 +294,8 @@ func defaultControllerOpts(root string, pd pullDeps) (*Opt, error) {
 	cm, err := cache.NewManager(cache.ManagerOpt{
 		Snapshotter:   snapshotter,
 		MetadataStore: md,
+		GCPolicy:      opt.GCPolicy,
+		DedupCommits:  opt.DedupCommits,
 	})
 	if err != nil {
 		return nil, err
@@ -62,6 +306,16 @@ func defaultControllerOpts(root string, pd pullDeps) (*Opt, error) {
 		Cache:         cm,
 	}
 
+	registries, err := resolver.NewRegistries(opt.RegistryConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	sessm, err := session.NewManager()
+	if err != nil {
+		return nil, err
+	}
+
 	sm, err := source.NewManager()
 	if err != nil {
 		return nil, err
@@ -72,6 +326,12 @@ func defaultControllerOpts(root string, pd pullDeps) (*Opt, error) {
 		ContentStore:  pd.ContentStore,
 		Applier:       pd.Applier,
 		CacheAccessor: cm,
+		MetadataStore: md,
+
+		MaxConcurrentDownloads: opt.MaxConcurrentDownloads,
+		Registries:             registries,
+		SessionManager:         sessm,
+		EStargz:                opt.EStargz,
 	})
 	if err != nil {
 		return nil, err
@@ -89,9 +349,18 @@ func defaultControllerOpts(root string, pd pullDeps) (*Opt, error) {
 
 	sm.Register(gs)
 
-	sessm, err := session.NewManager()
-	if err != nil {
-		return nil, err
+	for _, scheme := range []string{source.HttpsScheme, source.HttpScheme} {
+		hs, err := httpsource.NewSource(httpsource.Opt{
+			CacheAccessor: cm,
+			MetadataStore: md,
+			CacheTTL:      opt.HTTPCacheTTL,
+			Scheme:        scheme,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		sm.Register(hs)
 	}
 
 	ss, err := local.NewSource(local.Opt{
@@ -104,14 +373,29 @@ func defaultControllerOpts(root string, pd pullDeps) (*Opt, error) {
 	}
 	sm.Register(ss)
 
-	exporters := map[string]exporter.Exporter{}
-
-	imageExporter, err := imageexporter.New(imageexporter.Opt{
+	ols, err := containerimage.NewOCILayoutSource(containerimage.SourceOpt{
 		Snapshotter:   snapshotter,
 		ContentStore:  pd.ContentStore,
-		Differ:        pd.Differ,
+		Applier:       pd.Applier,
 		CacheAccessor: cm,
-		Images:        pd.Images,
+
+		MaxConcurrentDownloads: opt.MaxConcurrentDownloads,
+	}, ss, opt.OCILayoutRoot)
+	if err != nil {
+		return nil, err
+	}
+	sm.Register(ols)
+
+	exporters := map[string]exporter.Exporter{}
+
+	imageExporter, err := imageexporter.New(imageexporter.Opt{
+		Snapshotter:    snapshotter,
+		ContentStore:   pd.ContentStore,
+		Differ:         pd.Differ,
+		CacheAccessor:  cm,
+		Images:         pd.Images,
+		Registries:     registries,
+		SessionManager: sessm,
 	})
 	if err != nil {
 		return nil, err
@@ -126,17 +410,214 @@ func defaultControllerOpts(root string, pd pullDeps) (*Opt, error) {
 	}
 	exporters[client.ExporterLocal] = localExporter
 
+	tarExporter, err := tarexporter.New(tarexporter.Opt{
+		SessionManager: sessm,
+	})
+	if err != nil {
+		return nil, err
+	}
+	exporters[client.ExporterTar] = tarExporter
+
+	imageWriter, err := imageexporter.NewImageWriter(imageexporter.WriterOpt{
+		Snapshotter:  snapshotter,
+		ContentStore: pd.ContentStore,
+		Differ:       pd.Differ,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ociExporter, err := ociexporter.New(ociexporter.Opt{
+		SessionManager: sessm,
+		ImageWriter:    imageWriter,
+	})
+	if err != nil {
+		return nil, err
+	}
+	exporters[client.ExporterOCI] = ociExporter
+
+	ce := cacheimport.NewCacheExporter(cacheimport.ExporterOpt{
+		Snapshotter:    snapshotter,
+		ContentStore:   pd.ContentStore,
+		Differ:         pd.Differ,
+		Registries:     registries,
+		SessionManager: sessm,
+	})
+
+	ci := cacheimport.NewCacheImporter(cacheimport.ImporterOpt{
+		Snapshotter:    snapshotter,
+		ContentStore:   pd.ContentStore,
+		Applier:        pd.Applier,
+		CacheAccessor:  cm,
+		Registries:     registries,
+		SessionManager: sessm,
+	})
+
+	if !opt.DisableHistory {
+		hist, err := history.NewStore(filepath.Join(root, "history.db"))
+		if err != nil {
+			return nil, err
+		}
+		opt.History = hist
+	}
+
 	frontends := map[string]frontend.Frontend{}
 	frontends["dockerfile.v0"] = dockerfile.NewDockerfileFrontend()
+	frontends["gateway.v0"] = gateway.NewGatewayFrontend()
+
+	opt.Snapshotter = snapshotter
+	opt.CacheManager = cm
+	opt.MetadataStore = md
+	opt.SourceManager = sm
+	opt.InstructionCache = ic
+	opt.Exporters = exporters
+	opt.SessionManager = sessm
+	opt.Frontends = frontends
+	opt.ImageSource = is
+	opt.CacheExporter = ce
+	opt.CacheImporter = ci
+	opt.Differ = pd.Differ
+	opt.Applier = pd.Applier
+	opt.ContentStore = pd.ContentStore
+	return opt, nil
+}
+
+// newWorkerController registers the worker of the daemon. The workers of the
+// remote daemons are added while the daemons can be reached. The id of the
+// worker is kept in root so it doesn't change when the daemon is restarted.
+func newWorkerController(root string, w worker.Worker, executor, snapshotter string, remotes RemoteWorkerConfig) (*worker.Controller, error) {
+	idFile := filepath.Join(root, "workerid")
+	dt, err := ioutil.ReadFile(idFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, errors.Wrapf(err, "failed to read %s", idFile)
+		}
+		dt = []byte(identity.NewID())
+		if err := ioutil.WriteFile(idFile, dt, 0400); err != nil {
+			return nil, errors.Wrapf(err, "failed to write %s", idFile)
+		}
+	}
+
+	labels := map[string]string{
+		worker.LabelExecutor:    executor,
+		worker.LabelSnapshotter: snapshotter,
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		labels[worker.LabelHostname] = hostname
+	}
+
+	wc := &worker.Controller{}
+	if err := wc.Add(w, worker.Info{
+		ID:        strings.TrimSpace(string(dt)),
+		Labels:    labels,
+		Platforms: []ocispec.Platform{platforms.Default()},
+
+		EmulatedPlatforms: worker.EmulatedPlatforms(worker.BinfmtMiscDir),
+	}); err != nil {
+		return nil, err
+	}
+	if len(remotes.Addresses) > 0 && (remotes.CACert == "" || remotes.Cert == "" || remotes.Key == "") {
+		return nil, errors.New("remote workers require a CA certificate, a certificate and a key")
+	}
+	for _, addr := range remotes.Addresses {
+		if err := addRemoteWorker(wc, addr, remotes); err != nil {
+			return nil, err
+		}
+	}
+	return wc, nil
+}
+
+// remoteWorkerInterval is the interval of the checks of the daemons of the
+// remote workers
+const remoteWorkerInterval = 10 * time.Second
+
+// addRemoteWorker registers the default worker of the daemon at addr as a
+// remote worker while the daemon can be reached. The worker keeps the id, the
+// labels and the platforms it has in the remote daemon.
+func addRemoteWorker(wc *worker.Controller, addr string, cfg RemoteWorkerConfig) error {
+	u, err := url.Parse(addr)
+	if err != nil || u.Scheme != "tcp" || u.Host == "" {
+		return errors.Errorf("invalid remote worker address %s, tcp://host:port is required", addr)
+	}
+	c, err := client.New(addr, client.WithCredentials(u.Hostname(), cfg.CACert, cfg.Cert, cfg.Key))
+	if err != nil {
+		return errors.Wrapf(err, "failed to connect to remote worker %s", addr)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		watchRemoteWorker(ctx, wc, c, addr)
+	}()
+	wc.OnClose(func() error {
+		cancel()
+		<-done
+		return c.Close()
+	})
+	return nil
+}
+
+// watchRemoteWorker checks the daemon at addr every remoteWorkerInterval
+// until ctx is done. Its worker is removed while the daemon can't be reached
+// and added again when it can.
+func watchRemoteWorker(ctx context.Context, wc *worker.Controller, c *client.Client, addr string) {
+	var id string
+	defer func() {
+		if id != "" {
+			wc.Remove(id)
+		}
+	}()
+	for {
+		info, err := remoteWorkerInfo(ctx, c, addr)
+		if err != nil && id != "" {
+			logrus.Warnf("removing remote worker %s: %v", id, err)
+			wc.Remove(id)
+			id = ""
+		} else if err != nil {
+			logrus.Debugf("remote worker %s is not available: %v", addr, err)
+		} else if info.ID != id {
+			if id != "" {
+				wc.Remove(id)
+			}
+			id = ""
+			if err := wc.Add(remoteworker.New(c.ControlClient()), info); err != nil {
+				logrus.Errorf("failed to add remote worker %s: %v", addr, err)
+			} else {
+				logrus.Infof("added remote worker %s of %s", info.ID, addr)
+				id = info.ID
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(remoteWorkerInterval):
+		}
+	}
+}
+
+// remoteWorkerInfo returns the info of the default worker of the daemon at
+// addr
+func remoteWorkerInfo(ctx context.Context, c *client.Client, addr string) (worker.Info, error) {
+	ctx, cancel := context.WithTimeout(ctx, remoteWorkerInterval)
+	defer cancel()
+	workers, err := c.ListWorkers(ctx)
+	if err != nil {
+		return worker.Info{}, errors.Wrapf(err, "failed to list the workers of %s", addr)
+	}
+	if len(workers) == 0 {
+		return worker.Info{}, errors.Errorf("no workers registered in %s", addr)
+	}
+	info := workers[0]
+	labels := map[string]string{}
+	for k, v := range info.Labels {
+		labels[k] = v
+	}
+	labels[worker.LabelRemote] = addr
+	return worker.Info{
+		ID:        info.ID,
+		Labels:    labels,
+		Platforms: info.Platforms,
 
-	return &Opt{
-		Snapshotter:      snapshotter,
-		CacheManager:     cm,
-		SourceManager:    sm,
-		InstructionCache: ic,
-		Exporters:        exporters,
-		SessionManager:   sessm,
-		Frontends:        frontends,
-		ImageSource:      is,
+		EmulatedPlatforms: info.EmulatedPlatforms,
 	}, nil
 }
@@ -4,48 +4,131 @@ package control
 
 import (
 	"context"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/containerd/containerd/content/local"
 	"github.com/containerd/containerd/differ"
 	"github.com/containerd/containerd/mount"
 	"github.com/containerd/containerd/namespaces"
 	ctdsnapshot "github.com/containerd/containerd/snapshot"
-	"github.com/containerd/containerd/snapshot/overlay"
+	"github.com/moby/buildkit/snapshot/drivers"
 	"github.com/moby/buildkit/worker/runcworker"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
-func NewStandalone(root string) (*Controller, error) {
+func NewStandalone(root string, opts ...ControllerOpt) (*Controller, error) {
 	if err := os.MkdirAll(root, 0700); err != nil {
 		return nil, errors.Wrapf(err, "failed to create %s", root)
 	}
 
 	// TODO: take lock to make sure there are no duplicates
 
-	pd, err := newStandalonePullDeps(root)
+	o := newOpt(opts)
+	var workerOpts []runcworker.Opt
+	if o.Rootless {
+		workerOpts = append(workerOpts, runcworker.WithRootless())
+	}
+	d, err := snapshotterDriver(root, o.SnapshotterDriver, o.Rootless)
+	if err != nil {
+		return nil, err
+	}
+	snapshotter := d.Name
+	logrus.Infof("using %s snapshotter", snapshotter)
+
+	w, err := runcworker.New(filepath.Join(root, "runc"), workerOpts...)
 	if err != nil {
 		return nil, err
 	}
 
-	opt, err := defaultControllerOpts(root, *pd)
+	pd, err := newStandalonePullDeps(root, d)
 	if err != nil {
 		return nil, err
 	}
 
-	w, err := runcworker.New(filepath.Join(root, "runc"))
+	opt, err := defaultControllerOpts(root, *pd, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	opt.Worker = w
+	wc, err := newWorkerController(root, w, "runc", snapshotter, opt.RemoteWorkers)
+	if err != nil {
+		return nil, err
+	}
+	opt.MergeOverlay = snapshotter == drivers.Overlay
+	opt.Workers = wc
 
 	return NewController(*opt)
 }
 
-func newStandalonePullDeps(root string) (*pullDeps, error) {
-	s, err := overlay.NewSnapshotter(filepath.Join(root, "snapshots"))
+// snapshotterDriverFile stores the name of the driver of the snapshots in the
+// root directory. The metadata of the cache is shared by all the drivers, so
+// the snapshots of the records would be lost if the driver changed.
+const snapshotterDriverFile = "snapshotter"
+
+// snapshotterDriver returns the driver the snapshots of root are stored with.
+// The driver detected or set the first time the daemon uses root is kept
+// when name is auto, and a different driver can't be set.
+func snapshotterDriver(root, name string, rootless bool) (drivers.Driver, error) {
+	p := filepath.Join(root, snapshotterDriverFile)
+	var saved string
+	dt, err := ioutil.ReadFile(p)
+	switch {
+	case err == nil:
+		saved = strings.TrimSpace(string(dt))
+	case os.IsNotExist(err):
+		// the daemons before the drivers used overlayfs, or native for the
+		// rootless daemons
+		if _, err := os.Stat(filepath.Join(root, "metadata.db")); err == nil {
+			for _, n := range []string{drivers.Overlay, drivers.Native} {
+				if _, err := os.Stat(snapshotsDir(root, n)); err == nil {
+					saved = n
+					break
+				}
+			}
+		}
+	default:
+		return drivers.Driver{}, errors.Wrapf(err, "failed to read %s", p)
+	}
+
+	auto := name == "" || name == drivers.Auto
+	switch {
+	case saved != "" && auto:
+		name = saved
+	case saved != "" && name != saved:
+		return drivers.Driver{}, errors.Errorf("the snapshots of %s are stored with the %s snapshotter, use another root directory for %s", root, saved, name)
+	case auto && rootless:
+		// overlayfs can't be mounted in a user namespace
+		name = drivers.Native
+	}
+	d, err := drivers.Get(name, root)
+	if err != nil {
+		return drivers.Driver{}, err
+	}
+	if saved == "" {
+		if err := ioutil.WriteFile(p, []byte(d.Name), 0600); err != nil {
+			return drivers.Driver{}, errors.Wrapf(err, "failed to write %s", p)
+		}
+	}
+	return d, nil
+}
+
+// snapshotsDir returns the directory of the snapshots of the driver. The
+// overlayfs snapshots are stored in the directory of the first versions of
+// the daemon.
+func snapshotsDir(root, driver string) string {
+	dir := filepath.Join(root, "snapshots")
+	if driver != drivers.Overlay {
+		dir += "-" + driver
+	}
+	return dir
+}
+
+func newStandalonePullDeps(root string, d drivers.Driver) (*pullDeps, error) {
+	s, err := d.New(snapshotsDir(root, d.Name))
 	if err != nil {
 		return nil, err
 	}
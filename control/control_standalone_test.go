@@ -16,6 +16,7 @@ import (
 	"github.com/moby/buildkit/client"
 	"github.com/moby/buildkit/snapshot"
 	"github.com/moby/buildkit/snapshot/blobmapping"
+	"github.com/moby/buildkit/snapshot/drivers"
 	"github.com/moby/buildkit/source"
 	"github.com/moby/buildkit/source/containerimage"
 	"github.com/moby/buildkit/worker"
@@ -32,7 +33,9 @@ func TestControl(t *testing.T) {
 	assert.NoError(t, err)
 	defer os.RemoveAll(tmpdir)
 
-	cd, err := newStandalonePullDeps(tmpdir)
+	d, err := drivers.Get(drivers.Overlay, tmpdir)
+	assert.NoError(t, err)
+	cd, err := newStandalonePullDeps(tmpdir, d)
 	assert.NoError(t, err)
 
 	md, err := metadata.NewStore(filepath.Join(tmpdir, "metadata.db"))
@@ -115,7 +118,7 @@ func TestControl(t *testing.T) {
 
 	stderr := bytes.NewBuffer(nil)
 
-	err = w.Exec(ctx, meta, snap, nil, nil, &nopCloser{stderr})
+	err = w.Exec(ctx, meta, snap, nil, nil, nil, &nopCloser{stderr})
 	assert.Error(t, err) // Read-only root
 	// typical error is like `mkdir /.../rootfs/proc: read-only file system`.
 	// make sure the error is caused before running `echo foo > /bar`.
@@ -124,7 +127,7 @@ func TestControl(t *testing.T) {
 	root, err := cm.New(ctx, snap)
 	assert.NoError(t, err)
 
-	err = w.Exec(ctx, meta, root, nil, nil, nil)
+	err = w.Exec(ctx, meta, root, nil, nil, nil, nil)
 	assert.NoError(t, err)
 
 	rf, err := root.Commit(ctx)
@@ -164,3 +167,29 @@ type nopCloser struct {
 func (n *nopCloser) Close() error {
 	return nil
 }
+
+func TestSnapshotterDriver(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "controltest")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	d, err := snapshotterDriver(tmpdir, drivers.Native, false)
+	assert.NoError(t, err)
+	assert.Equal(t, drivers.Native, d.Name)
+
+	// the driver of the first start is kept
+	d, err = snapshotterDriver(tmpdir, drivers.Auto, false)
+	assert.NoError(t, err)
+	assert.Equal(t, drivers.Native, d.Name)
+	_, err = snapshotterDriver(tmpdir, drivers.Overlay, false)
+	assert.Error(t, err)
+
+	// the state of the daemons before the drivers uses overlayfs
+	legacy, err := ioutil.TempDir("", "controltest")
+	assert.NoError(t, err)
+	defer os.RemoveAll(legacy)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(legacy, "metadata.db"), nil, 0600))
+	assert.NoError(t, os.Mkdir(filepath.Join(legacy, "snapshots"), 0700))
+	_, err = snapshotterDriver(legacy, drivers.Native, false)
+	assert.Error(t, err)
+}
@@ -0,0 +1,105 @@
+package control
+
+import (
+	gocontext "context"
+	"io"
+	"testing"
+
+	controlapi "github.com/moby/buildkit/api/services/control"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/worker"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func TestExecDisabled(t *testing.T) {
+	c := &Controller{queue: newSolveQueue(QueuePolicy{})}
+	err := c.Exec(&testExecStream{ctx: context.TODO()})
+	require.Error(t, err)
+	require.Equal(t, codes.PermissionDenied, grpc.Code(err))
+}
+
+func TestExecRequiresCertificate(t *testing.T) {
+	c := &Controller{opt: Opt{RemoteExec: true}, queue: newSolveQueue(QueuePolicy{})}
+	err := c.Exec(&testExecStream{ctx: context.TODO()})
+	require.Error(t, err)
+	require.Equal(t, codes.Unauthenticated, grpc.Code(err))
+}
+
+type testExecStream struct {
+	controlapi.Control_ExecServer
+	ctx context.Context
+}
+
+func (s *testExecStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestDiskUsageParent(t *testing.T) {
+	c := &Controller{opt: Opt{CacheManager: &testCacheManager{du: []*client.UsageInfo{
+		{ID: "base"},
+		{ID: "child", Parent: "base"},
+	}}}}
+	resp, err := c.DiskUsage(context.TODO(), &controlapi.DiskUsageRequest{})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(resp.Record))
+	require.Equal(t, "", resp.Record[0].Parent)
+	require.Equal(t, "child", resp.Record[1].ID)
+	require.Equal(t, "base", resp.Record[1].Parent)
+}
+
+type testCacheManager struct {
+	cache.Manager
+	du []*client.UsageInfo
+}
+
+func (m *testCacheManager) DiskUsage(ctx gocontext.Context, opt client.DiskUsageInfo) ([]*client.UsageInfo, error) {
+	return m.du, nil
+}
+
+func TestCapabilitiesHostMounts(t *testing.T) {
+	wc := &worker.Controller{}
+	require.NoError(t, wc.Add(&testWorker{}, worker.Info{ID: "w1"}))
+	c := &Controller{opt: Opt{Workers: wc}}
+	resp, err := c.Capabilities(context.TODO(), &controlapi.CapabilitiesRequest{})
+	require.NoError(t, err)
+	require.NotContains(t, resp.MountTypes, "host")
+
+	c.opt.HostMounts = []string{"/etc/ssl/certs"}
+	resp, err = c.Capabilities(context.TODO(), &controlapi.CapabilitiesRequest{})
+	require.NoError(t, err)
+	require.Contains(t, resp.MountTypes, "host")
+}
+
+func TestCapabilitiesWorkers(t *testing.T) {
+	c := &Controller{}
+	resp, err := c.Capabilities(context.TODO(), &controlapi.CapabilitiesRequest{})
+	require.NoError(t, err)
+	require.NotContains(t, resp.Ops, "exec")
+	require.NotContains(t, resp.Ops, "diff")
+	require.Empty(t, resp.MountTypes)
+
+	wc := &worker.Controller{}
+	require.NoError(t, wc.Add(&testWorker{}, worker.Info{ID: "w1", Platforms: []ocispec.Platform{{OS: "windows", Architecture: "amd64"}}}))
+	c.opt.Workers = wc
+	resp, err = c.Capabilities(context.TODO(), &controlapi.CapabilitiesRequest{})
+	require.NoError(t, err)
+	require.Contains(t, resp.Ops, "exec")
+	require.Contains(t, resp.MountTypes, "cache")
+	require.NotContains(t, resp.MountTypes, "tmpfs")
+
+	require.NoError(t, wc.Add(&testWorker{}, worker.Info{ID: "w2", Platforms: []ocispec.Platform{{OS: "linux", Architecture: "amd64"}}}))
+	resp, err = c.Capabilities(context.TODO(), &controlapi.CapabilitiesRequest{})
+	require.NoError(t, err)
+	require.Contains(t, resp.MountTypes, "tmpfs")
+}
+
+type testWorker struct{}
+
+func (w *testWorker) Exec(ctx context.Context, meta worker.Meta, rootfs cache.Mountable, mounts []worker.Mount, stdin io.ReadCloser, stdout, stderr io.WriteCloser) error {
+	return nil
+}
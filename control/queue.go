@@ -0,0 +1,299 @@
+package control
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// statusGracePeriod is the time the status of a build that is not in the
+// queue waits for the build to be requested
+const statusGracePeriod = 3 * time.Second
+
+// errQueueClosed is returned for the builds that are requested after the
+// queue has been closed
+var errQueueClosed = errors.New("daemon is shutting down")
+
+// QueuePolicy limits the number of builds that are solved at the same time.
+// The other builds wait in a queue that is ordered by their priority and by
+// the time they were requested.
+type QueuePolicy struct {
+	// MaxConcurrent is the maximum number of running builds. The number of
+	// builds is not limited if it is 0.
+	MaxConcurrent int
+	// MaxPerClient is the maximum number of running builds of a client. The
+	// builds of clients that reached it don't block the builds of the other
+	// clients in the queue.
+	MaxPerClient int
+}
+
+type queuedSolve struct {
+	ref       string
+	client    string
+	priority  int
+	createdAt time.Time
+	// cancel cancels the context of the build
+	cancel  func()
+	started chan struct{}
+}
+
+// queuedJob describes a build in the queue
+type queuedJob struct {
+	ref       string
+	client    string
+	priority  int
+	createdAt time.Time
+	// position is 0 if the build is running
+	position int
+}
+
+// solveQueue admits the builds according to a QueuePolicy
+type solveQueue struct {
+	mu      sync.Mutex
+	policy  QueuePolicy
+	running map[string]*queuedSolve
+	clients map[string]int
+	waiting []*queuedSolve
+	// changed is closed when a build is queued, started or removed
+	changed chan struct{}
+	closed  bool
+}
+
+func newSolveQueue(p QueuePolicy) *solveQueue {
+	return &solveQueue{
+		policy:  p,
+		running: map[string]*queuedSolve{},
+		clients: map[string]int{},
+		changed: make(chan struct{}),
+	}
+}
+
+// acquire waits until the build ref can run and returns the function that
+// releases it after the build has completed. cancel is called when the build
+// is canceled with cancel.
+func (q *solveQueue) acquire(ctx context.Context, ref, client string, priority int, cancel func()) (func(), error) {
+	qs := &queuedSolve{
+		ref:       ref,
+		client:    client,
+		priority:  priority,
+		createdAt: time.Now(),
+		cancel:    cancel,
+		started:   make(chan struct{}),
+	}
+
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return nil, errQueueClosed
+	}
+	if _, ok := q.running[ref]; ok || q.index(ref) >= 0 {
+		q.mu.Unlock()
+		return nil, errors.Errorf("build %s is already queued", ref)
+	}
+	// higher priorities first, in the order of the requests
+	i := sort.Search(len(q.waiting), func(i int) bool {
+		return q.waiting[i].priority < priority
+	})
+	q.waiting = append(q.waiting, nil)
+	copy(q.waiting[i+1:], q.waiting[i:])
+	q.waiting[i] = qs
+	q.schedule()
+	q.notify()
+	q.mu.Unlock()
+
+	select {
+	case <-qs.started:
+	case <-ctx.Done():
+		q.mu.Lock()
+		select {
+		case <-qs.started:
+			q.mu.Unlock()
+			q.release(qs)
+		default:
+			if i := q.index(ref); i >= 0 {
+				q.waiting = append(q.waiting[:i], q.waiting[i+1:]...)
+			}
+			q.schedule()
+			q.notify()
+			q.mu.Unlock()
+		}
+		return nil, errors.Wrapf(ctx.Err(), "build %s canceled in queue", ref)
+	}
+	return func() { q.release(qs) }, nil
+}
+
+func (q *solveQueue) release(qs *queuedSolve) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.running[qs.ref] != qs {
+		return
+	}
+	delete(q.running, qs.ref)
+	if q.clients[qs.client]--; q.clients[qs.client] == 0 {
+		delete(q.clients, qs.client)
+	}
+	q.schedule()
+	q.notify()
+}
+
+// schedule starts the waiting builds that the policy allows. The caller
+// holds the lock.
+func (q *solveQueue) schedule() {
+	waiting := q.waiting[:0]
+	for _, qs := range q.waiting {
+		if (q.policy.MaxConcurrent > 0 && len(q.running) >= q.policy.MaxConcurrent) ||
+			(q.policy.MaxPerClient > 0 && q.clients[qs.client] >= q.policy.MaxPerClient) {
+			waiting = append(waiting, qs)
+			continue
+		}
+		q.running[qs.ref] = qs
+		q.clients[qs.client]++
+		close(qs.started)
+	}
+	for i := len(waiting); i < len(q.waiting); i++ {
+		q.waiting[i] = nil
+	}
+	q.waiting = waiting
+}
+
+func (q *solveQueue) notify() {
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
+
+func (q *solveQueue) index(ref string) int {
+	for i, qs := range q.waiting {
+		if qs.ref == ref {
+			return i
+		}
+	}
+	return -1
+}
+
+// position returns the position of the build ref in the queue, starting at
+// 1, or 0 if it is running
+func (q *solveQueue) position(ref string) (int, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.running[ref]; ok {
+		return 0, true
+	}
+	if i := q.index(ref); i >= 0 {
+		return i + 1, true
+	}
+	return 0, false
+}
+
+// counts returns the number of running and waiting builds
+func (q *solveQueue) counts() (int, int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.running), len(q.waiting)
+}
+
+// jobs returns the running builds in the order they were requested followed
+// by the waiting builds in the order of the queue
+func (q *solveQueue) jobs() []queuedJob {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	jobs := make([]queuedJob, 0, len(q.running)+len(q.waiting))
+	for _, qs := range q.running {
+		jobs = append(jobs, queuedJob{ref: qs.ref, client: qs.client, priority: qs.priority, createdAt: qs.createdAt})
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].createdAt.Before(jobs[j].createdAt)
+	})
+	for i, qs := range q.waiting {
+		jobs = append(jobs, queuedJob{ref: qs.ref, client: qs.client, priority: qs.priority, createdAt: qs.createdAt, position: i + 1})
+	}
+	return jobs
+}
+
+// cancel cancels the build ref. It returns false if the build is neither
+// running nor waiting.
+func (q *solveQueue) cancel(ref string) bool {
+	q.mu.Lock()
+	qs, ok := q.running[ref]
+	if !ok {
+		if i := q.index(ref); i >= 0 {
+			qs, ok = q.waiting[i], true
+		}
+	}
+	q.mu.Unlock()
+	if ok && qs.cancel != nil {
+		qs.cancel()
+	}
+	return ok
+}
+
+// waitStarted waits while the build ref is in the queue. Builds that are not
+// queued after grace are not waited for.
+func (q *solveQueue) waitStarted(ctx context.Context, ref string, grace time.Duration) error {
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+	expired := false
+	for {
+		q.mu.Lock()
+		_, running := q.running[ref]
+		queued := q.index(ref) >= 0
+		changed := q.changed
+		q.mu.Unlock()
+		if running || (!queued && expired) {
+			return nil
+		}
+		select {
+		case <-changed:
+		case <-timer.C:
+			expired = true
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// close stops admitting builds. The builds that are already in the queue are
+// drained: the waiting builds still start when the running ones complete.
+func (q *solveQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+}
+
+// cancelAll cancels the running and the waiting builds
+func (q *solveQueue) cancelAll() {
+	q.mu.Lock()
+	cancels := make([]func(), 0, len(q.running)+len(q.waiting))
+	for _, qs := range q.running {
+		cancels = append(cancels, qs.cancel)
+	}
+	for _, qs := range q.waiting {
+		cancels = append(cancels, qs.cancel)
+	}
+	q.mu.Unlock()
+	for _, cancel := range cancels {
+		if cancel != nil {
+			cancel()
+		}
+	}
+}
+
+// wait waits until no build is running or waiting
+func (q *solveQueue) wait(ctx context.Context) error {
+	for {
+		q.mu.Lock()
+		empty := len(q.running) == 0 && len(q.waiting) == 0
+		changed := q.changed
+		q.mu.Unlock()
+		if empty {
+			return nil
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
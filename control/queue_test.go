@@ -0,0 +1,164 @@
+package control
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestSolveQueue(t *testing.T) {
+	q := newSolveQueue(QueuePolicy{MaxConcurrent: 2, MaxPerClient: 1})
+	ctx := context.TODO()
+
+	releaseA, err := q.acquire(ctx, "a", "c1", 0, nil)
+	require.NoError(t, err)
+	_, err = q.acquire(ctx, "a", "c1", 0, nil)
+	require.Error(t, err)
+
+	started := map[string]chan func(){}
+	queue := func(ref, client string, priority int) {
+		ch := make(chan func(), 1)
+		started[ref] = ch
+		go func() {
+			release, err := q.acquire(ctx, ref, client, priority, nil)
+			require.NoError(t, err)
+			ch <- release
+		}()
+		waitQueued(t, q, ref)
+	}
+	// c1 has reached its limit, the build of c2 runs
+	queue("b", "c1", 0)
+	queue("c", "c2", 0)
+	releaseC := <-started["c"]
+	queue("d", "c1", 0)
+	queue("e", "c3", 1)
+
+	pos, ok := q.position("b")
+	require.True(t, ok)
+	require.Equal(t, 2, pos)
+	pos, _ = q.position("e")
+	require.Equal(t, 1, pos)
+	running, waiting := q.counts()
+	require.Equal(t, 2, running)
+	require.Equal(t, 3, waiting)
+
+	// the higher priority runs first
+	releaseC()
+	releaseE := <-started["e"]
+	releaseA()
+	releaseB := <-started["b"]
+	releaseE()
+	releaseB()
+	releaseD := <-started["d"]
+	releaseD()
+
+	_, ok = q.position("d")
+	require.False(t, ok)
+}
+
+func TestSolveQueueCancel(t *testing.T) {
+	q := newSolveQueue(QueuePolicy{MaxConcurrent: 1})
+	release, err := q.acquire(context.TODO(), "a", "", 0, nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := q.acquire(ctx, "b", "", 0, nil)
+		errCh <- err
+	}()
+	waitQueued(t, q, "b")
+
+	waitCh := make(chan error, 1)
+	go func() {
+		waitCh <- q.waitStarted(context.TODO(), "b", time.Millisecond)
+	}()
+	cancel()
+	require.Error(t, <-errCh)
+	require.NoError(t, <-waitCh)
+	_, ok := q.position("b")
+	require.False(t, ok)
+
+	release()
+	require.NoError(t, q.waitStarted(context.TODO(), "c", time.Millisecond))
+}
+
+func TestSolveQueueJobs(t *testing.T) {
+	q := newSolveQueue(QueuePolicy{MaxConcurrent: 1})
+	ctx, cancel := context.WithCancel(context.TODO())
+	release, err := q.acquire(ctx, "a", "c1", 0, cancel)
+	require.NoError(t, err)
+	defer release()
+
+	ctxB, cancelB := context.WithCancel(context.TODO())
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := q.acquire(ctxB, "b", "c2", 1, cancelB)
+		errCh <- err
+	}()
+	waitQueued(t, q, "b")
+
+	jobs := q.jobs()
+	require.Equal(t, 2, len(jobs))
+	require.Equal(t, "a", jobs[0].ref)
+	require.Equal(t, 0, jobs[0].position)
+	require.Equal(t, "b", jobs[1].ref)
+	require.Equal(t, "c2", jobs[1].client)
+	require.Equal(t, 1, jobs[1].priority)
+	require.Equal(t, 1, jobs[1].position)
+
+	require.True(t, q.cancel("a"))
+	require.Error(t, ctx.Err())
+	require.True(t, q.cancel("b"))
+	require.Error(t, <-errCh)
+	require.False(t, q.cancel("b"))
+}
+
+func TestSolveQueueClose(t *testing.T) {
+	q := newSolveQueue(QueuePolicy{MaxConcurrent: 1})
+	release, err := q.acquire(context.TODO(), "a", "", 0, nil)
+	require.NoError(t, err)
+
+	startedB := make(chan func(), 1)
+	go func() {
+		release, err := q.acquire(context.TODO(), "b", "", 0, nil)
+		require.NoError(t, err)
+		startedB <- release
+	}()
+	waitQueued(t, q, "b")
+
+	// the queued builds are drained, the new ones are rejected
+	q.close()
+	_, err = q.acquire(context.TODO(), "c", "", 0, nil)
+	require.Equal(t, errQueueClosed, err)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+	defer cancel()
+	require.Error(t, q.wait(ctx))
+
+	waitCh := make(chan error, 1)
+	go func() {
+		waitCh <- q.wait(context.TODO())
+	}()
+	release()
+	releaseB := <-startedB
+	select {
+	case err := <-waitCh:
+		t.Fatalf("wait returned before the queued build completed: %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+	releaseB()
+	require.NoError(t, <-waitCh)
+}
+
+func waitQueued(t *testing.T, q *solveQueue, ref string) {
+	for i := 0; i < 100; i++ {
+		if _, ok := q.position(ref); ok {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("%s was not queued", ref)
+}
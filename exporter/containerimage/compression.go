@@ -0,0 +1,377 @@
+package containerimage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/images"
+	"github.com/klauspost/compress/zstd"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// keyCompression is the compression of the layers: gzip, zstd or
+	// uncompressed
+	keyCompression = "compression"
+	// keyCompressionLevel is the gzip or zstd level of the layers from 1 to 9
+	keyCompressionLevel = "compression-level"
+	// keyForceCompression recompresses the layers that already have a blob
+	// with the right compression but maybe not with the right level
+	keyForceCompression = "force-compression"
+	// keyOCIMediaTypes selects the OCI media types, the Docker ones are used
+	// if it is false
+	keyOCIMediaTypes = "oci-mediatypes"
+	// keyCompressionWorkers is the number of layers that are compressed in
+	// parallel
+	keyCompressionWorkers = "compression-workers"
+)
+
+const (
+	// Gzip compresses the layers with gzip
+	Gzip = "gzip"
+	// Zstd compresses the layers with zstd. The zstd layers only have OCI
+	// media types, so the OCI media types are always used with it.
+	Zstd = "zstd"
+	// Uncompressed exports the layers as plain tars
+	Uncompressed = "uncompressed"
+)
+
+// mediaTypeImageLayerZstd is the OCI media type of the zstd layers
+const mediaTypeImageLayerZstd = "application/vnd.oci.image.layer.v1.tar+zstd"
+
+// compressionLabelPrefix followed by the compression is the label of a blob
+// with the digest of its converted blob
+const compressionLabelPrefix = "buildkit/compression."
+
+// CommitOpt sets how the layers and the manifests of an image are written
+type CommitOpt struct {
+	// Compression of the layers, Gzip if it is empty
+	Compression string
+	// CompressionLevel is the gzip or zstd level, the default level is used if
+	// it is 0
+	CompressionLevel int
+	// ForceCompression converts the blobs that already have the compression
+	ForceCompression bool
+	// DockerMediaTypes writes the image with the Docker media types instead
+	// of the OCI ones
+	DockerMediaTypes bool
+	// Workers is the number of layers converted in parallel,
+	// runtime.NumCPU() if it is 0
+	Workers int
+	// SourceDateEpoch makes the image reproducible, the timestamps of the
+	// image are set to it if it is not nil
+	SourceDateEpoch *time.Time
+}
+
+// Parse sets the option of the exporter attribute k. It returns false if k is
+// not an attribute of the commit.
+func (co *CommitOpt) Parse(k, v string) (bool, error) {
+	switch k {
+	case keyCompression:
+		switch v {
+		case Gzip, Zstd, Uncompressed:
+			co.Compression = v
+		default:
+			return true, errors.Errorf("invalid compression %s", v)
+		}
+	case keyCompressionLevel:
+		l, err := strconv.Atoi(v)
+		if err != nil || l < gzip.BestSpeed || l > gzip.BestCompression {
+			return true, errors.Errorf("invalid compression level %s", v)
+		}
+		co.CompressionLevel = l
+	case keyForceCompression:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return true, errors.Wrapf(err, "non-bool value specified for %s", k)
+		}
+		co.ForceCompression = b
+	case keyOCIMediaTypes:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return true, errors.Wrapf(err, "non-bool value specified for %s", k)
+		}
+		co.DockerMediaTypes = !b
+	case keyCompressionWorkers:
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return true, errors.Errorf("invalid number of compression workers %s", v)
+		}
+		co.Workers = n
+	case keySourceDateEpoch:
+		sec, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || sec < 0 {
+			return true, errors.Errorf("invalid %s %s", k, v)
+		}
+		tm := time.Unix(sec, 0).UTC()
+		co.SourceDateEpoch = &tm
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+func (co CommitOpt) compression() string {
+	if co.Compression == "" {
+		return Gzip
+	}
+	return co.Compression
+}
+
+// dockerMediaTypes returns true if the image is written with the Docker media
+// types, which have no zstd layers
+func (co CommitOpt) dockerMediaTypes() bool {
+	return co.DockerMediaTypes && co.compression() != Zstd
+}
+
+func (co CommitOpt) layerMediaType() string {
+	switch {
+	case co.compression() == Zstd:
+		return mediaTypeImageLayerZstd
+	case co.compression() == Uncompressed && co.dockerMediaTypes():
+		return images.MediaTypeDockerSchema2Layer
+	case co.compression() == Uncompressed:
+		return ocispec.MediaTypeImageLayer
+	case co.dockerMediaTypes():
+		return images.MediaTypeDockerSchema2LayerGzip
+	default:
+		return ocispec.MediaTypeImageLayerGzip
+	}
+}
+
+func (co CommitOpt) configMediaType() string {
+	if co.dockerMediaTypes() {
+		return images.MediaTypeDockerSchema2Config
+	}
+	return ocispec.MediaTypeImageConfig
+}
+
+func (co CommitOpt) manifestMediaType() string {
+	if co.dockerMediaTypes() {
+		return images.MediaTypeDockerSchema2Manifest
+	}
+	return ocispec.MediaTypeImageManifest
+}
+
+func (co CommitOpt) indexMediaType() string {
+	if co.dockerMediaTypes() {
+		return images.MediaTypeDockerSchema2ManifestList
+	}
+	return ocispec.MediaTypeImageIndex
+}
+
+// label is the label of the source blobs of the conversions with these
+// options
+func (co CommitOpt) label() string {
+	if co.compression() != Uncompressed && co.CompressionLevel != 0 {
+		return fmt.Sprintf("%s%s-%d", compressionLabelPrefix, co.compression(), co.CompressionLevel)
+	}
+	return compressionLabelPrefix + co.compression()
+}
+
+// convertLayers returns the descriptors of the blobs with the compression of
+// co. The blobs are converted in parallel by co.Workers.
+func convertLayers(ctx context.Context, cs content.Store, blobs []digest.Digest, co CommitOpt) ([]ocispec.Descriptor, error) {
+	descs := make([]ocispec.Descriptor, len(blobs))
+	if err := co.parallel(ctx, len(blobs), func(ctx context.Context, i int) error {
+		desc, err := convertLayer(ctx, cs, blobs[i], co)
+		if err != nil {
+			return err
+		}
+		descs[i] = desc
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return descs, nil
+}
+
+// parallel calls fn for every index up to n with at most co.Workers calls
+// running at the same time
+func (co CommitOpt) parallel(ctx context.Context, n int, fn func(context.Context, int) error) error {
+	workers := co.Workers
+	if workers == 0 {
+		workers = runtime.NumCPU()
+	}
+	sem := make(chan struct{}, workers)
+	eg, ctx := errgroup.WithContext(ctx)
+	for i := 0; i < n; i++ {
+		i := i
+		eg.Go(func() error {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			defer func() { <-sem }()
+			return fn(ctx, i)
+		})
+	}
+	return eg.Wait()
+}
+
+func convertLayer(ctx context.Context, cs content.Store, blob digest.Digest, co CommitOpt) (ocispec.Descriptor, error) {
+	info, err := cs.Info(ctx, blob)
+	if err != nil {
+		return ocispec.Descriptor{}, errors.Wrapf(err, "could not get blob %s", blob)
+	}
+	desc := ocispec.Descriptor{
+		Digest:    blob,
+		Size:      info.Size,
+		MediaType: co.layerMediaType(),
+	}
+
+	ra, err := cs.ReaderAt(ctx, blob)
+	if err != nil {
+		return ocispec.Descriptor{}, errors.Wrapf(err, "failed to open blob %s", blob)
+	}
+	defer ra.Close()
+	sr := io.NewSectionReader(ra, 0, info.Size)
+	compression, err := detectCompression(sr)
+	if err != nil {
+		return ocispec.Descriptor{}, errors.Wrapf(err, "failed to read blob %s", blob)
+	}
+	if compression == co.compression() && !co.ForceCompression {
+		return desc, nil
+	}
+
+	// the blob was converted by a previous export
+	if dgst, ok := info.Labels[co.label()]; ok {
+		if converted, err := cs.Info(ctx, digest.Digest(dgst)); err == nil {
+			desc.Digest = converted.Digest
+			desc.Size = converted.Size
+			return desc, nil
+		}
+	}
+
+	done := OneOffProgress(ctx, fmt.Sprintf("compressing %s to %s", blob, co.compression()))
+	converted, err := writeConverted(ctx, cs, sr, compression, co)
+	if err != nil {
+		return ocispec.Descriptor{}, done(errors.Wrapf(err, "failed to convert blob %s", blob))
+	}
+	// the stores that don't support labels convert the blob on every export
+	if converted.Digest != blob {
+		info.Labels = map[string]string{co.label(): converted.Digest.String()}
+		if _, err := cs.Update(ctx, info, "labels."+co.label()); err != nil {
+			logrus.Debugf("failed to label blob %s with its conversion: %v", blob, err)
+		}
+	}
+	done(nil)
+	desc.Digest = converted.Digest
+	desc.Size = converted.Size
+	return desc, nil
+}
+
+// writeConverted writes the uncompressed content of r with the compression of
+// co to the content store
+func writeConverted(ctx context.Context, cs content.Store, r io.Reader, compression string, co CommitOpt) (content.Info, error) {
+	dr, err := decompress(r, compression)
+	if err != nil {
+		return content.Info{}, err
+	}
+	defer dr.Close()
+
+	pr, pw := io.Pipe()
+	go func() {
+		if co.compression() == Uncompressed {
+			_, err := io.Copy(pw, dr)
+			pw.CloseWithError(err)
+			return
+		}
+		zw, err := co.compressor(pw)
+		if err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		if _, err := io.Copy(zw, dr); err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		pw.CloseWithError(zw.Close())
+	}()
+	defer pr.Close()
+	return ingest(ctx, cs, "convert-"+co.label(), pr)
+}
+
+// compressor returns a writer compressing to w with the compression and the
+// level of co
+func (co CommitOpt) compressor(w io.Writer) (io.WriteCloser, error) {
+	if co.compression() == Zstd {
+		level := zstd.SpeedDefault
+		if co.CompressionLevel != 0 {
+			level = zstd.EncoderLevelFromZstd(co.CompressionLevel)
+		}
+		return zstd.NewWriter(w, zstd.WithEncoderLevel(level))
+	}
+	level := co.CompressionLevel
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+	return gzip.NewWriterLevel(w, level)
+}
+
+// decompress returns the uncompressed content of r with the compression
+// detected by detectCompression
+func decompress(r io.Reader, compression string) (io.ReadCloser, error) {
+	switch compression {
+	case Gzip:
+		return gzip.NewReader(r)
+	case Zstd:
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return zr.IOReadCloser(), nil
+	default:
+		return ioutil.NopCloser(r), nil
+	}
+}
+
+// ingest writes the content of r to the content store. ref is made unique
+// for the concurrent writes of the same content.
+func ingest(ctx context.Context, cs content.Store, ref string, r io.Reader) (content.Info, error) {
+	w, err := cs.Writer(ctx, fmt.Sprintf("%s-%d", ref, time.Now().UnixNano()), 0, "")
+	if err != nil {
+		return content.Info{}, err
+	}
+	defer w.Close()
+	size, err := io.Copy(w, r)
+	if err != nil {
+		return content.Info{}, err
+	}
+	dgst := w.Digest()
+	if err := w.Commit(ctx, size, dgst); err != nil && !errdefs.IsAlreadyExists(err) {
+		return content.Info{}, err
+	}
+	return content.Info{Digest: dgst, Size: size}, nil
+}
+
+// detectCompression returns Gzip or Zstd if the content of r starts with the
+// magic bytes of gzip or zstd and Uncompressed otherwise
+func detectCompression(r io.ReaderAt) (string, error) {
+	magic := make([]byte, 4)
+	n, err := r.ReadAt(magic, 0)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	switch {
+	case bytes.HasPrefix(magic[:n], []byte{0x1f, 0x8b}):
+		return Gzip, nil
+	case bytes.Equal(magic[:n], []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		return Zstd, nil
+	}
+	return Uncompressed, nil
+}
@@ -0,0 +1,173 @@
+package containerimage
+
+import (
+	"bytes"
+	"compress/gzip"
+	gocontext "context"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/content/local"
+	"github.com/containerd/containerd/images"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestParseCommitOpt(t *testing.T) {
+	var co CommitOpt
+	for k, v := range map[string]string{
+		"compression":         "uncompressed",
+		"compression-level":   "9",
+		"force-compression":   "true",
+		"oci-mediatypes":      "false",
+		"compression-workers": "2",
+		"source-date-epoch":   "1000",
+	} {
+		ok, err := co.Parse(k, v)
+		require.True(t, ok)
+		require.NoError(t, err)
+	}
+	epoch := time.Unix(1000, 0).UTC()
+	require.Equal(t, CommitOpt{Compression: Uncompressed, CompressionLevel: 9, ForceCompression: true, DockerMediaTypes: true, Workers: 2, SourceDateEpoch: &epoch}, co)
+
+	ok, err := co.Parse("name", "foo")
+	require.False(t, ok)
+	require.NoError(t, err)
+
+	for k, v := range map[string]string{
+		"compression":         "brotli",
+		"compression-level":   "10",
+		"compression-workers": "0",
+		"source-date-epoch":   "yesterday",
+	} {
+		_, err := co.Parse(k, v)
+		require.Error(t, err)
+	}
+}
+
+func TestConvertLayers(t *testing.T) {
+	root, err := ioutil.TempDir("", "buildkit-export")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	store, err := local.NewStore(root)
+	require.NoError(t, err)
+	cs := &labelStore{Store: store, labels: map[digest.Digest]map[string]string{}}
+	ctx := context.TODO()
+
+	tarDt := bytes.Repeat([]byte("layer"), 1000)
+	buf := &bytes.Buffer{}
+	zw := gzip.NewWriter(buf)
+	_, err = zw.Write(tarDt)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	gzDt := buf.Bytes()
+
+	tarDgst := digest.FromBytes(tarDt)
+	gzDgst := digest.FromBytes(gzDt)
+	require.NoError(t, content.WriteBlob(ctx, cs, "tar", bytes.NewReader(tarDt), int64(len(tarDt)), tarDgst))
+	require.NoError(t, content.WriteBlob(ctx, cs, "gz", bytes.NewReader(gzDt), int64(len(gzDt)), gzDgst))
+
+	// the gzipped blob is reused, the uncompressed one is compressed
+	descs, err := convertLayers(ctx, cs, []digest.Digest{gzDgst, tarDgst}, CommitOpt{})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(descs))
+	require.Equal(t, ocispec.Descriptor{Digest: gzDgst, Size: int64(len(gzDt)), MediaType: ocispec.MediaTypeImageLayerGzip}, descs[0])
+	require.NotEqual(t, tarDgst, descs[1].Digest)
+	require.Equal(t, ocispec.MediaTypeImageLayerGzip, descs[1].MediaType)
+	dt, err := content.ReadBlob(ctx, cs, descs[1].Digest)
+	require.NoError(t, err)
+	zr, err := gzip.NewReader(bytes.NewReader(dt))
+	require.NoError(t, err)
+	dt, err = ioutil.ReadAll(zr)
+	require.NoError(t, err)
+	require.Equal(t, tarDt, dt)
+
+	// the conversion is reused by the next export
+	info, err := cs.Info(ctx, tarDgst)
+	require.NoError(t, err)
+	require.Equal(t, descs[1].Digest.String(), info.Labels["buildkit/compression.gzip"])
+	again, err := convertLayers(ctx, cs, []digest.Digest{tarDgst}, CommitOpt{Workers: 1})
+	require.NoError(t, err)
+	require.Equal(t, descs[1], again[0])
+
+	descs, err = convertLayers(ctx, cs, []digest.Digest{gzDgst, tarDgst}, CommitOpt{Compression: Uncompressed, DockerMediaTypes: true})
+	require.NoError(t, err)
+	require.Equal(t, ocispec.Descriptor{Digest: tarDgst, Size: int64(len(tarDt)), MediaType: images.MediaTypeDockerSchema2Layer}, descs[0])
+	require.Equal(t, descs[0], descs[1])
+
+	descs, err = convertLayers(ctx, cs, []digest.Digest{gzDgst}, CommitOpt{CompressionLevel: 1, ForceCompression: true})
+	require.NoError(t, err)
+	require.NotEqual(t, gzDgst, descs[0].Digest)
+	info, err = cs.Info(ctx, gzDgst)
+	require.NoError(t, err)
+	require.Equal(t, descs[0].Digest.String(), info.Labels["buildkit/compression.gzip-1"])
+
+	// the zstd layers always have the OCI media type
+	co := CommitOpt{DockerMediaTypes: true}
+	ok, err := co.Parse("compression", "zstd")
+	require.True(t, ok)
+	require.NoError(t, err)
+	require.Equal(t, ocispec.MediaTypeImageManifest, co.manifestMediaType())
+	descs, err = convertLayers(ctx, cs, []digest.Digest{gzDgst, tarDgst}, co)
+	require.NoError(t, err)
+	require.Equal(t, descs[0], descs[1])
+	require.Equal(t, "application/vnd.oci.image.layer.v1.tar+zstd", descs[0].MediaType)
+	ra, err := cs.ReaderAt(ctx, descs[0].Digest)
+	require.NoError(t, err)
+	compression, err := detectCompression(ra)
+	require.NoError(t, err)
+	require.Equal(t, Zstd, compression)
+	zr2, err := decompress(io.NewSectionReader(ra, 0, descs[0].Size), compression)
+	require.NoError(t, err)
+	dt, err = ioutil.ReadAll(zr2)
+	require.NoError(t, err)
+	require.Equal(t, tarDt, dt)
+	require.NoError(t, zr2.Close())
+	require.NoError(t, ra.Close())
+
+	// the zstd blobs are reused
+	again, err = convertLayers(ctx, cs, []digest.Digest{descs[0].Digest}, co)
+	require.NoError(t, err)
+	require.Equal(t, descs[0], again[0])
+}
+
+// labelStore keeps the labels that the local store doesn't support
+type labelStore struct {
+	content.Store
+	mu     sync.Mutex
+	labels map[digest.Digest]map[string]string
+}
+
+func (s *labelStore) Info(ctx gocontext.Context, dgst digest.Digest) (content.Info, error) {
+	info, err := s.Store.Info(ctx, dgst)
+	if err != nil {
+		return info, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info.Labels = map[string]string{}
+	for k, v := range s.labels[dgst] {
+		info.Labels[k] = v
+	}
+	return info, nil
+}
+
+func (s *labelStore) Update(ctx gocontext.Context, info content.Info, fieldpaths ...string) (content.Info, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.labels[info.Digest] == nil {
+		s.labels[info.Digest] = map[string]string{}
+	}
+	for k, v := range info.Labels {
+		s.labels[info.Digest][k] = v
+	}
+	return info, nil
+}
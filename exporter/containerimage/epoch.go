@@ -0,0 +1,185 @@
+package containerimage
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/containerd/containerd/content"
+	"github.com/moby/buildkit/cache/blobs"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+)
+
+// keySourceDateEpoch is the unix time in seconds the timestamps of the image
+// are set to, as the SOURCE_DATE_EPOCH of reproducible builds
+const keySourceDateEpoch = "source-date-epoch"
+
+// epochLabelPrefix followed by the epoch is the label of a blob with the
+// digest of the blob with its timestamps rewritten
+const epochLabelPrefix = "buildkit/source-date-epoch."
+
+// setEpoch sets the creation time of img and of its history to epoch
+func setEpoch(img *ocispec.Image, epoch time.Time) {
+	img.Created = &epoch
+	for i := range img.History {
+		img.History[i].Created = &epoch
+	}
+}
+
+// rewriteLayers returns the diff pairs of the layers with their timestamps
+// rewritten to the epoch of co
+func rewriteLayers(ctx context.Context, cs content.Store, diffPairs []blobs.DiffPair, co CommitOpt) ([]blobs.DiffPair, error) {
+	rewritten := make([]blobs.DiffPair, len(diffPairs))
+	if err := co.parallel(ctx, len(diffPairs), func(ctx context.Context, i int) error {
+		blob, err := rewriteLayer(ctx, cs, diffPairs[i].Blobsum, *co.SourceDateEpoch)
+		if err != nil {
+			return err
+		}
+		// the rewritten blobs are uncompressed
+		rewritten[i] = blobs.DiffPair{DiffID: blob, Blobsum: blob}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return rewritten, nil
+}
+
+func rewriteLayer(ctx context.Context, cs content.Store, blob digest.Digest, epoch time.Time) (digest.Digest, error) {
+	info, err := cs.Info(ctx, blob)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not get blob %s", blob)
+	}
+	label := fmt.Sprintf("%s%d", epochLabelPrefix, epoch.Unix())
+	if dgst, ok := info.Labels[label]; ok {
+		if _, err := cs.Info(ctx, digest.Digest(dgst)); err == nil {
+			return digest.Digest(dgst), nil
+		}
+	}
+
+	ra, err := cs.ReaderAt(ctx, blob)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to open blob %s", blob)
+	}
+	defer ra.Close()
+	sr := io.NewSectionReader(ra, 0, info.Size)
+	compression, err := detectCompression(sr)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read blob %s", blob)
+	}
+	r, err := decompress(sr, compression)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read blob %s", blob)
+	}
+	defer r.Close()
+
+	done := OneOffProgress(ctx, "rewriting timestamps of "+blob.String())
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(canonicalTar(pw, r, epoch))
+	}()
+	defer pr.Close()
+	rewritten, err := ingest(ctx, cs, "rewrite-"+label, pr)
+	if err != nil {
+		return "", done(errors.Wrapf(err, "failed to rewrite blob %s", blob))
+	}
+	if rewritten.Digest != blob {
+		info.Labels = map[string]string{label: rewritten.Digest.String()}
+		if _, err := cs.Update(ctx, info, "labels."+label); err != nil {
+			logrus.Debugf("failed to label blob %s with its rewrite: %v", blob, err)
+		}
+	}
+	return rewritten.Digest, done(nil)
+}
+
+// canonicalTar writes the entries of the tar r to w sorted by name, with the
+// modification times later than epoch set to epoch and without the access
+// and change times and the user and group names
+func canonicalTar(w io.Writer, r io.Reader, epoch time.Time) error {
+	// the content is spooled to a file for sorting the entries
+	f, err := ioutil.TempFile("", "buildkit-layer")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	type entry struct {
+		hdr    *tar.Header
+		offset int64
+	}
+	var entries []entry
+	var offset int64
+	tr := tar.NewReader(r)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to read tar")
+		}
+		n, err := io.Copy(f, tr)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry{hdr: h, offset: offset})
+		offset += n
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].hdr.Name < entries[j].hdr.Name
+	})
+	// hardlinks are moved after their targets
+	for i := 0; i < len(entries); i++ {
+		h := entries[i].hdr
+		if h.Typeflag != tar.TypeLink {
+			continue
+		}
+		for j := i + 1; j < len(entries); j++ {
+			if entries[j].hdr.Name == h.Linkname {
+				e := entries[i]
+				copy(entries[i:j], entries[i+1:j+1])
+				entries[j] = e
+				i--
+				break
+			}
+		}
+	}
+
+	tw := tar.NewWriter(w)
+	for _, e := range entries {
+		h := e.hdr
+		if h.ModTime.After(epoch) {
+			h.ModTime = epoch
+		}
+		h.AccessTime = time.Time{}
+		h.ChangeTime = time.Time{}
+		h.Uname = ""
+		h.Gname = ""
+		// only the xattrs are kept from the pax records
+		for k := range h.PAXRecords {
+			if !strings.HasPrefix(k, "SCHILY.xattr.") {
+				delete(h.PAXRecords, k)
+			}
+		}
+		h.Format = tar.FormatUnknown
+		if err := tw.WriteHeader(h); err != nil {
+			return errors.Wrapf(err, "failed to write header of %s", h.Name)
+		}
+		if h.Size > 0 {
+			if _, err := io.Copy(tw, io.NewSectionReader(f, e.offset, h.Size)); err != nil {
+				return errors.Wrapf(err, "failed to write %s", h.Name)
+			}
+		}
+	}
+	return tw.Close()
+}
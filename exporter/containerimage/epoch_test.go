@@ -0,0 +1,78 @@
+package containerimage
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalTar(t *testing.T) {
+	epoch := time.Unix(1000, 0).UTC()
+	old := time.Unix(500, 0).UTC()
+
+	layer := func(names []string, now time.Time) []byte {
+		buf := &bytes.Buffer{}
+		tw := tar.NewWriter(buf)
+		for _, name := range names {
+			h := &tar.Header{Name: name, Mode: 0644, ModTime: now, Uname: "user", Gname: "group", Format: tar.FormatPAX, AccessTime: now}
+			switch name {
+			case "a/":
+				h.Typeflag = tar.TypeDir
+			case "link":
+				h.Typeflag = tar.TypeLink
+				h.Linkname = "z"
+			case "old":
+				h.Typeflag = tar.TypeReg
+				h.ModTime = old
+			default:
+				h.Typeflag = tar.TypeReg
+				h.Size = int64(len(name))
+				h.PAXRecords = map[string]string{"SCHILY.xattr.user.foo": "bar"}
+			}
+			require.NoError(t, tw.WriteHeader(h))
+			if h.Size > 0 {
+				_, err := tw.Write([]byte(name))
+				require.NoError(t, err)
+			}
+		}
+		require.NoError(t, tw.Close())
+		return buf.Bytes()
+	}
+
+	canonical := func(dt []byte) []byte {
+		buf := &bytes.Buffer{}
+		require.NoError(t, canonicalTar(buf, bytes.NewReader(dt), epoch))
+		return buf.Bytes()
+	}
+
+	out1 := canonical(layer([]string{"z", "a/", "a/b", "old", "link"}, time.Now()))
+	out2 := canonical(layer([]string{"a/", "a/b", "z", "link", "old"}, time.Now().Add(time.Hour)))
+	require.Equal(t, out1, out2)
+
+	tr := tar.NewReader(bytes.NewReader(out1))
+	var names []string
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, h.Name)
+		if h.Name == "old" {
+			require.True(t, old.Equal(h.ModTime))
+		} else {
+			require.True(t, epoch.Equal(h.ModTime))
+		}
+		require.Equal(t, "", h.Uname)
+		require.True(t, h.AccessTime.IsZero())
+		if h.Name == "z" {
+			require.Equal(t, "bar", h.PAXRecords["SCHILY.xattr.user.foo"])
+		}
+	}
+	// the hardlink follows its target
+	require.Equal(t, []string{"a/", "a/b", "old", "z", "link"}, names)
+}
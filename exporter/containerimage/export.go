@@ -1,36 +1,44 @@
 package containerimage
 
 import (
-	"bytes"
-	gocontext "context"
 	"encoding/json"
-	"runtime"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/containerd/containerd/content"
 	"github.com/containerd/containerd/errdefs"
 	"github.com/containerd/containerd/images"
-	"github.com/containerd/containerd/mount"
+	"github.com/containerd/containerd/platforms"
 	"github.com/containerd/containerd/rootfs"
 	"github.com/moby/buildkit/cache"
 	"github.com/moby/buildkit/cache/metadata"
 	"github.com/moby/buildkit/exporter"
-	"github.com/moby/buildkit/frontend/dockerfile/dockerfile2llb"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/auth"
 	"github.com/moby/buildkit/snapshot"
-	"github.com/moby/buildkit/util/flightcontrol"
-	"github.com/moby/buildkit/util/progress"
-	"github.com/moby/buildkit/util/system"
-	digest "github.com/opencontainers/go-digest"
+	"github.com/moby/buildkit/util/push"
+	"github.com/moby/buildkit/util/resolver"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/net/context"
-	"golang.org/x/sync/errgroup"
 )
 
 const (
-	keyImageName        = "name"
-	exporterImageConfig = "containerimage.config"
+	keyImageName = "name"
+	keyPush      = "push"
+	// keyPlatform sets the platform of the main result. The image is
+	// exported as a manifest list if the platform of the main result or of
+	// one of the named results is set.
+	keyPlatform = "platform"
+	// keyResultPlatformPrefix followed by the name of a result of the build
+	// adds the result to the manifest list with the platform of the value
+	keyResultPlatformPrefix = "platform:"
+	// keyInlineCache embeds the cache config of the build in the image config
+	// so that the image can be imported as the cache of the next build
+	keyInlineCache = "inline-cache"
 )
 
 type Opt struct {
@@ -40,254 +48,263 @@ type Opt struct {
 	CacheAccessor cache.Accessor
 	MetadataStore metadata.Store
 	Images        images.Store
+	// Registries configures the access to the registries the images are
+	// pushed to. The defaults are used if it is nil.
+	Registries *resolver.Registries
+	// SessionManager is used for requesting the registry credentials from
+	// the client of the build
+	SessionManager *session.Manager
 }
 
 type imageExporter struct {
-	blobmap blobmapper
-	opt     Opt
-	g       flightcontrol.Group
-}
-
-type diffPair struct {
-	diffID  digest.Digest
-	blobsum digest.Digest
-}
-
-type blobmapper interface {
-	GetBlob(ctx gocontext.Context, key string) (digest.Digest, error)
-	SetBlob(ctx gocontext.Context, key string, blob digest.Digest) error
+	opt    Opt
+	writer *ImageWriter
 }
 
 func New(opt Opt) (exporter.Exporter, error) {
-	blobmap, ok := opt.Snapshotter.(blobmapper)
-	if !ok {
-		return nil, errors.Errorf("image exporter requires snapshotter with blobs mapping support")
+	w, err := NewImageWriter(WriterOpt{
+		Snapshotter:  opt.Snapshotter,
+		ContentStore: opt.ContentStore,
+		Differ:       opt.Differ,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	im := &imageExporter{opt: opt, blobmap: blobmap}
+	im := &imageExporter{opt: opt, writer: w}
 	return im, nil
 }
 
 func (e *imageExporter) Resolve(ctx context.Context, opt map[string]string) (exporter.ExporterInstance, error) {
 	i := &imageExporterInstance{imageExporter: e}
 	for k, v := range opt {
-		switch k {
-		case keyImageName:
-			i.targetName = v
-		default:
-			logrus.Warnf("unknown exporter option %s", k)
-		}
-	}
-	return i, nil
-}
-
-func (e *imageExporter) getBlobs(ctx context.Context, ref cache.ImmutableRef) ([]diffPair, error) {
-	eg, ctx := errgroup.WithContext(ctx)
-	var diffPairs []diffPair
-	var currentPair diffPair
-	parent := ref.Parent()
-	if parent != nil {
-		defer parent.Release(context.TODO())
-		eg.Go(func() error {
-			dp, err := e.getBlobs(ctx, parent)
-			if err != nil {
-				return err
-			}
-			diffPairs = dp
-			return nil
-		})
-	}
-	eg.Go(func() error {
-		dp, err := e.g.Do(ctx, ref.ID(), func(ctx context.Context) (interface{}, error) {
-			blob, err := e.blobmap.GetBlob(ctx, ref.ID())
+		if strings.HasPrefix(k, keyResultPlatformPrefix) {
+			p, err := parsePlatform(v)
 			if err != nil {
 				return nil, err
 			}
-			if blob != "" {
-				diffID, err := digest.Parse(ref.ID())
-				if err != nil {
-					diffID = blob
-				}
-				return diffPair{diffID: diffID, blobsum: blob}, nil
-			}
-			// reference needs to be committed
-			parent := ref.Parent()
-			var lower []mount.Mount
-			if parent != nil {
-				defer parent.Release(context.TODO())
-				lower, err = parent.Mount(ctx, true)
-				if err != nil {
-					return nil, err
-				}
+			if i.resultPlatforms == nil {
+				i.resultPlatforms = map[string]ocispec.Platform{}
 			}
-			upper, err := ref.Mount(ctx, true)
+			i.resultPlatforms[strings.TrimPrefix(k, keyResultPlatformPrefix)] = p
+			continue
+		}
+		if ok, err := i.commitOpt.Parse(k, v); ok {
 			if err != nil {
 				return nil, err
 			}
-			descr, err := e.opt.Differ.DiffMounts(ctx, lower, upper, ocispec.MediaTypeImageLayer, ref.ID())
+			continue
+		}
+		switch k {
+		case keyPlatform:
+			p, err := parsePlatform(v)
 			if err != nil {
 				return nil, err
 			}
-			if err := e.blobmap.SetBlob(ctx, ref.ID(), descr.Digest); err != nil {
-				return nil, err
+			i.platform = &p
+		case exporterImageConfig:
+			if !json.Valid([]byte(v)) {
+				return nil, errors.Errorf("invalid image config %s", v)
 			}
-			return diffPair{diffID: descr.Digest, blobsum: descr.Digest}, nil
-		})
-		if err != nil {
-			return err
+			i.config = []byte(v)
+		case keyImageName:
+			i.targetName = v
+		case keyPush:
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "non-bool value specified for %s", k)
+			}
+			i.push = b
+		case keyInlineCache:
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "non-bool value specified for %s", k)
+			}
+			i.inlineCache = b
+		default:
+			logrus.Warnf("unknown exporter option %s", k)
 		}
-		currentPair = dp.(diffPair)
-		return nil
-	})
-	err := eg.Wait()
+	}
+	if i.push && i.targetName == "" {
+		return nil, errors.Errorf("image name required for push")
+	}
+	return i, nil
+}
+
+func parsePlatform(v string) (ocispec.Platform, error) {
+	m, err := platforms.Parse(v)
 	if err != nil {
-		return nil, err
+		return ocispec.Platform{}, errors.Wrapf(err, "invalid platform %s", v)
 	}
-	return append(diffPairs, currentPair), nil
+	return m.Spec(), nil
 }
 
 type imageExporterInstance struct {
 	*imageExporter
-	targetName string
+	targetName      string
+	push            bool
+	platform        *ocispec.Platform
+	resultPlatforms map[string]ocispec.Platform
+	commitOpt       CommitOpt
+	inlineCache     bool
+	// config is the image config of the exporter attributes. The config
+	// set by the frontend is used instead if there is one.
+	config []byte
 }
 
 func (e *imageExporterInstance) Name() string {
 	return "exporting to image"
 }
 
-func (e *imageExporterInstance) Export(ctx context.Context, ref cache.ImmutableRef, opt map[string]interface{}) error {
-	layersDone := oneOffProgress(ctx, "exporting layers")
-	diffPairs, err := e.getBlobs(ctx, ref)
-	if err != nil {
-		return err
-	}
-	layersDone(nil)
+func (e *imageExporterInstance) InlineCache() bool {
+	return e.inlineCache
+}
 
-	diffIDs := make([]digest.Digest, 0, len(diffPairs))
-	for _, dp := range diffPairs {
-		diffIDs = append(diffIDs, dp.diffID)
-	}
+func (e *imageExporterInstance) Platform() *ocispec.Platform {
+	return e.platform
+}
 
-	var dt []byte
-	if imgInterface, ok := opt[exporterImageConfig]; ok {
-		img, ok := imgInterface.(*dockerfile2llb.Image)
-		if !ok {
-			return errors.Errorf("invalid image config")
+func (e *imageExporterInstance) Export(ctx context.Context, ref cache.ImmutableRef, opt map[string]interface{}) (map[string]string, error) {
+	var desc *ocispec.Descriptor
+	var manifests []ocispec.Descriptor
+	var err error
+	if _, ok := opt[exporterImageConfig]; !ok && e.config != nil {
+		copt := map[string]interface{}{exporterImageConfig: e.config}
+		for k, v := range opt {
+			copt[k] = v
 		}
-		setDiffIDs(img, diffIDs)
-		dt, err = json.Marshal(img)
+		opt = copt
+	}
+	if e.platform == nil && len(e.resultPlatforms) == 0 {
+		desc, err = e.writer.Commit(ctx, ref, opt, e.commitOpt)
 		if err != nil {
-			return errors.Wrap(err, "failed to marshal image config")
+			return nil, err
 		}
+		manifests = []ocispec.Descriptor{*desc}
 	} else {
-		dt, err = json.Marshal(imageConfig(diffIDs))
+		manifests, err = e.commitPlatforms(ctx, ref, opt)
 		if err != nil {
-			return errors.Wrap(err, "failed to marshal image config")
+			return nil, err
 		}
-	}
-
-	dgst := digest.FromBytes(dt)
-	configDone := oneOffProgress(ctx, "exporting config "+dgst.String())
-
-	if err := content.WriteBlob(ctx, e.opt.ContentStore, dgst.String(), bytes.NewReader(dt), int64(len(dt)), dgst); err != nil {
-		return configDone(errors.Wrap(err, "error writing config blob"))
-	}
-	configDone(nil)
-
-	mfst := ocispec.Manifest{
-		Config: ocispec.Descriptor{
-			Digest:    dgst,
-			Size:      int64(len(dt)),
-			MediaType: ocispec.MediaTypeImageConfig,
-		},
-	}
-	mfst.SchemaVersion = 2
-
-	for _, dp := range diffPairs {
-		info, err := e.opt.ContentStore.Info(ctx, dp.blobsum)
+		desc, err = e.writer.CommitIndex(ctx, manifests, e.commitOpt)
 		if err != nil {
-			return configDone(errors.Wrapf(err, "could not get blob %s", dp.blobsum))
+			return nil, err
 		}
-		mfst.Layers = append(mfst.Layers, ocispec.Descriptor{
-			Digest:    dp.blobsum,
-			Size:      info.Size,
-			MediaType: ocispec.MediaTypeImageLayerGzip,
-		})
 	}
 
-	dt, err = json.Marshal(mfst)
-	if err != nil {
-		return errors.Wrap(err, "failed to marshal manifest")
-	}
-
-	dgst = digest.FromBytes(dt)
-	mfstDone := oneOffProgress(ctx, "exporting manifest "+dgst.String())
-
-	if err := content.WriteBlob(ctx, e.opt.ContentStore, dgst.String(), bytes.NewReader(dt), int64(len(dt)), dgst); err != nil {
-		return mfstDone(errors.Wrap(err, "error writing manifest blob"))
-	}
-
-	mfstDone(nil)
-
 	if e.opt.Images != nil && e.targetName != "" {
-		tagDone := oneOffProgress(ctx, "naming to "+e.targetName)
+		tagDone := OneOffProgress(ctx, "naming to "+e.targetName)
 		imgrec := images.Image{
-			Name: e.targetName,
-			Target: ocispec.Descriptor{
-				Digest:    dgst,
-				Size:      int64(len(dt)),
-				MediaType: ocispec.MediaTypeImageManifest,
-			},
+			Name:      e.targetName,
+			Target:    *desc,
 			CreatedAt: time.Now(),
 		}
 		_, err := e.opt.Images.Update(ctx, imgrec)
 		if err != nil {
 			if !errdefs.IsNotFound(err) {
-				return tagDone(err)
+				return nil, tagDone(err)
 			}
 
 			_, err := e.opt.Images.Create(ctx, imgrec)
 			if err != nil {
-				return tagDone(err)
+				return nil, tagDone(err)
 			}
 		}
 		tagDone(nil)
 	}
 
-	return err
+	if e.push {
+		// layers and config are pushed before the manifest that refers to
+		// them and the manifests before the list
+		var descs []ocispec.Descriptor
+		for _, m := range manifests {
+			mfst, err := e.writer.Manifest(ctx, m)
+			if err != nil {
+				return nil, err
+			}
+			descs = append(append(descs, mfst.Layers...), mfst.Config, m)
+		}
+		if len(manifests) > 1 || manifests[0].Digest != desc.Digest {
+			descs = append(descs, *desc)
+		}
+		if err := e.pushImage(ctx, descs); err != nil {
+			return nil, err
+		}
+	}
+
+	return DescriptorResponse(*desc)
 }
 
-// this is temporary: should move to dockerfile frontend
-func imageConfig(diffIDs []digest.Digest) ocispec.Image {
-	img := ocispec.Image{
-		Architecture: runtime.GOARCH,
-		OS:           runtime.GOOS,
+// DescriptorResponse returns the response of an image exporter with the
+// digest and the descriptor of the exported manifest desc
+func DescriptorResponse(desc ocispec.Descriptor) (map[string]string, error) {
+	dt, err := json.Marshal(desc)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal descriptor")
 	}
-	img.RootFS.Type = "layers"
-	img.RootFS.DiffIDs = diffIDs
-	img.Config.WorkingDir = "/"
-	img.Config.Env = []string{"PATH=" + system.DefaultPathEnv}
-	return img
+	return map[string]string{
+		exporter.ImageDigestKey:     desc.Digest.String(),
+		exporter.ImageDescriptorKey: string(dt),
+	}, nil
 }
 
-func setDiffIDs(img *dockerfile2llb.Image, diffIDs []digest.Digest) {
-	img.RootFS.Type = "layers"
-	img.RootFS.DiffIDs = diffIDs
+// commitPlatforms writes the images of the main result and of the named
+// results that have a platform set and returns the descriptors of their
+// manifests with the platforms
+func (e *imageExporterInstance) commitPlatforms(ctx context.Context, ref cache.ImmutableRef, opt map[string]interface{}) ([]ocispec.Descriptor, error) {
+	var manifests []ocispec.Descriptor
+	commit := func(ref cache.ImmutableRef, p ocispec.Platform, opt map[string]interface{}) error {
+		popt := map[string]interface{}{}
+		for k, v := range opt {
+			popt[k] = v
+		}
+		popt[exporterImagePlatform] = p
+		desc, err := e.writer.Commit(ctx, ref, popt, e.commitOpt)
+		if err != nil {
+			return err
+		}
+		desc.Platform = &p
+		manifests = append(manifests, *desc)
+		return nil
+	}
+
+	if e.platform != nil {
+		if err := commit(ref, *e.platform, opt); err != nil {
+			return nil, err
+		}
+	}
+
+	results, _ := opt[exporter.ResultsKey].(map[string]cache.ImmutableRef)
+	names := make([]string, 0, len(e.resultPlatforms))
+	for name := range e.resultPlatforms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		r, ok := results[name]
+		if !ok {
+			return nil, errors.Errorf("result %s not found for platform %s", name, platforms.Format(e.resultPlatforms[name]))
+		}
+		// the image config of the frontend is only for the main result
+		if err := commit(r, e.resultPlatforms[name], nil); err != nil {
+			return nil, err
+		}
+	}
+	return manifests, nil
 }
 
-func oneOffProgress(ctx context.Context, id string) func(err error) error {
-	pw, _, _ := progress.FromContext(ctx)
-	now := time.Now()
-	st := progress.Status{
-		Started: &now,
+func (e *imageExporterInstance) pushImage(ctx context.Context, descs []ocispec.Descriptor) error {
+	pushDone := OneOffProgress(ctx, "pushing "+e.targetName)
+	resolver := e.opt.Registries.Resolver(auth.SessionCredentials(ctx, e.opt.SessionManager))
+	pusher, err := resolver.Pusher(ctx, e.targetName)
+	if err != nil {
+		return pushDone(err)
 	}
-	pw.Write(id, st)
-	return func(err error) error {
-		// TODO: set error on status
-		now := time.Now()
-		st.Completed = &now
-		pw.Write(id, st)
-		pw.Close()
-		return err
+	handler := push.Handler(e.opt.ContentStore, pusher)
+	for _, desc := range descs {
+		if _, err := handler(ctx, desc); err != nil {
+			return pushDone(errors.Wrapf(err, "failed to push %s", desc.Digest))
+		}
 	}
+	return pushDone(nil)
 }
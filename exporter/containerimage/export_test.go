@@ -0,0 +1,115 @@
+package containerimage
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/content/local"
+	"github.com/containerd/containerd/images"
+	"github.com/moby/buildkit/exporter"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestResolvePlatforms(t *testing.T) {
+	e := &imageExporter{}
+	inst, err := e.Resolve(context.TODO(), map[string]string{
+		"name":              "example.com/foo:latest",
+		"platform":          "linux/amd64",
+		"platform:armhf":    "linux/arm/v7",
+		"platform:arm64-v8": "linux/arm64",
+	})
+	require.NoError(t, err)
+	i := inst.(*imageExporterInstance)
+	require.Equal(t, &ocispec.Platform{OS: "linux", Architecture: "amd64"}, i.platform)
+	require.Equal(t, map[string]ocispec.Platform{
+		"armhf":    {OS: "linux", Architecture: "arm", Variant: "v7"},
+		"arm64-v8": {OS: "linux", Architecture: "arm64"},
+	}, i.resultPlatforms)
+
+	_, err = e.Resolve(context.TODO(), map[string]string{"platform": "linux/amd64/v1/extra"})
+	require.Error(t, err)
+}
+
+func TestResolveImageConfig(t *testing.T) {
+	e := &imageExporter{}
+	inst, err := e.Resolve(context.TODO(), map[string]string{
+		"containerimage.config": `{"config":{"Cmd":["sh"]}}`,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []byte(`{"config":{"Cmd":["sh"]}}`), inst.(*imageExporterInstance).config)
+
+	_, err = e.Resolve(context.TODO(), map[string]string{"containerimage.config": "{"})
+	require.Error(t, err)
+}
+
+func TestResolveInlineCache(t *testing.T) {
+	e := &imageExporter{}
+	inst, err := e.Resolve(context.TODO(), map[string]string{"inline-cache": "true"})
+	require.NoError(t, err)
+	require.True(t, inst.(exporter.InlineCacheExporter).InlineCache())
+
+	inst, err = e.Resolve(context.TODO(), map[string]string{})
+	require.NoError(t, err)
+	require.False(t, inst.(exporter.InlineCacheExporter).InlineCache())
+
+	_, err = e.Resolve(context.TODO(), map[string]string{"inline-cache": "maybe"})
+	require.Error(t, err)
+}
+
+func TestDescriptorResponse(t *testing.T) {
+	desc := ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Digest:    digest.FromBytes([]byte("manifest")),
+		Size:      8,
+	}
+	resp, err := DescriptorResponse(desc)
+	require.NoError(t, err)
+	require.Equal(t, desc.Digest.String(), resp["containerimage.digest"])
+
+	var d ocispec.Descriptor
+	require.NoError(t, json.Unmarshal([]byte(resp["containerimage.descriptor"]), &d))
+	require.Equal(t, desc, d)
+}
+
+func TestCommitIndex(t *testing.T) {
+	root, err := ioutil.TempDir("", "buildkit-export")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	cs, err := local.NewStore(root)
+	require.NoError(t, err)
+	w := &ImageWriter{opt: WriterOpt{ContentStore: cs}}
+
+	manifests := []ocispec.Descriptor{
+		{MediaType: ocispec.MediaTypeImageManifest, Digest: "sha256:0000000000000000000000000000000000000000000000000000000000000001", Size: 10, Platform: &ocispec.Platform{OS: "linux", Architecture: "amd64"}},
+		{MediaType: ocispec.MediaTypeImageManifest, Digest: "sha256:0000000000000000000000000000000000000000000000000000000000000002", Size: 20, Platform: &ocispec.Platform{OS: "linux", Architecture: "arm64"}},
+	}
+	desc, err := w.CommitIndex(context.TODO(), manifests, CommitOpt{})
+	require.NoError(t, err)
+	require.Equal(t, ocispec.MediaTypeImageIndex, desc.MediaType)
+
+	dt, err := content.ReadBlob(context.TODO(), cs, desc.Digest)
+	require.NoError(t, err)
+	require.Equal(t, desc.Size, int64(len(dt)))
+	var idx ocispec.Index
+	require.NoError(t, json.Unmarshal(dt, &idx))
+	require.Equal(t, 2, idx.SchemaVersion)
+	require.Equal(t, manifests, idx.Manifests)
+
+	desc, err = w.CommitIndex(context.TODO(), manifests, CommitOpt{DockerMediaTypes: true})
+	require.NoError(t, err)
+	require.Equal(t, images.MediaTypeDockerSchema2ManifestList, desc.MediaType)
+	dt, err = content.ReadBlob(context.TODO(), cs, desc.Digest)
+	require.NoError(t, err)
+	var list struct {
+		MediaType string `json:"mediaType"`
+	}
+	require.NoError(t, json.Unmarshal(dt, &list))
+	require.Equal(t, images.MediaTypeDockerSchema2ManifestList, list.MediaType)
+}
@@ -0,0 +1,378 @@
+package containerimage
+
+import (
+	"bytes"
+	gocontext "context"
+	"encoding/json"
+	"runtime"
+	"time"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/rootfs"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/cache/blobs"
+	"github.com/moby/buildkit/cache/cacheimport"
+	"github.com/moby/buildkit/exporter"
+	"github.com/moby/buildkit/frontend/dockerfile/dockerfile2llb"
+	"github.com/moby/buildkit/snapshot"
+	"github.com/moby/buildkit/util/progress"
+	"github.com/moby/buildkit/util/system"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+const (
+	exporterImageConfig = exporter.ImageConfigKey
+	// exporterImagePlatform is the ocispec.Platform of the image that is set
+	// in the image config
+	exporterImagePlatform = "containerimage.platform"
+	// provenanceLabel is the label of the image config with the provenance
+	// of the build
+	provenanceLabel = "moby.buildkit.provenance.v0"
+)
+
+type WriterOpt struct {
+	Snapshotter  snapshot.Snapshotter
+	ContentStore content.Store
+	Differ       rootfs.MountDiffer
+}
+
+type blobmapper interface {
+	GetBlob(ctx gocontext.Context, key string) (digest.Digest, error)
+	SetBlob(ctx gocontext.Context, key string, blob digest.Digest) error
+}
+
+func NewImageWriter(opt WriterOpt) (*ImageWriter, error) {
+	if _, ok := opt.Snapshotter.(blobmapper); !ok {
+		return nil, errors.Errorf("image exporter requires snapshotter with blobs mapping support")
+	}
+	return &ImageWriter{opt: opt}, nil
+}
+
+// ImageWriter writes the images of the references to the content store
+type ImageWriter struct {
+	opt WriterOpt
+}
+
+// Commit writes the layer blobs, the config and the manifest of the image of
+// ref to the content store and returns the descriptor of the manifest
+func (ic *ImageWriter) Commit(ctx context.Context, ref cache.ImmutableRef, opt map[string]interface{}, co CommitOpt) (*ocispec.Descriptor, error) {
+	layersDone := OneOffProgress(ctx, "exporting layers")
+	diffPairs, err := blobs.GetDiffPairs(ctx, ic.opt.Snapshotter, ic.opt.Differ, ref)
+	if err != nil {
+		return nil, layersDone(err)
+	}
+	layersDone(nil)
+
+	if co.SourceDateEpoch != nil {
+		diffPairs, err = rewriteLayers(ctx, ic.opt.ContentStore, diffPairs, co)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	diffIDs := make([]digest.Digest, 0, len(diffPairs))
+	blobsums := make([]digest.Digest, 0, len(diffPairs))
+	for _, dp := range diffPairs {
+		diffIDs = append(diffIDs, dp.DiffID)
+		blobsums = append(blobsums, dp.Blobsum)
+	}
+	layers, err := convertLayers(ctx, ic.opt.ContentStore, blobsums, co)
+	if err != nil {
+		return nil, err
+	}
+
+	var dt []byte
+	if imgInterface, ok := opt[exporterImageConfig]; ok {
+		var img *dockerfile2llb.Image
+		switch v := imgInterface.(type) {
+		case *dockerfile2llb.Image:
+			img = v
+		case []byte:
+			// frontends running in containers pass the config as JSON
+			img = &dockerfile2llb.Image{}
+			if err := json.Unmarshal(v, img); err != nil {
+				return nil, errors.Wrap(err, "failed to parse image config")
+			}
+		default:
+			return nil, errors.Errorf("invalid image config")
+		}
+		setDiffIDs(img, diffIDs)
+		if img.Architecture == "" && img.OS == "" {
+			img.Architecture = runtime.GOARCH
+			img.OS = runtime.GOOS
+		}
+		if p, ok := opt[exporterImagePlatform].(ocispec.Platform); ok {
+			img.Architecture = p.Architecture
+			img.OS = p.OS
+		}
+		img.History = normalizeHistory(img.History, len(diffIDs))
+		// the creation time is only set by an epoch, otherwise the time of
+		// the config, eg. of the base image, is kept so that the rebuilds of
+		// the same result have the same config
+		if co.SourceDateEpoch != nil {
+			setEpoch(&img.Image, *co.SourceDateEpoch)
+		}
+		dt, err = json.Marshal(img)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal image config")
+		}
+	} else {
+		img := imageConfig(diffIDs)
+		if p, ok := opt[exporterImagePlatform].(ocispec.Platform); ok {
+			img.Architecture = p.Architecture
+			img.OS = p.OS
+		}
+		if co.SourceDateEpoch != nil {
+			setEpoch(&img, *co.SourceDateEpoch)
+		}
+		dt, err = json.Marshal(img)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal image config")
+		}
+	}
+
+	if records, ok := opt[exporter.InlineCacheKey].(cacheimport.Records); ok {
+		exported := make([]blobs.DiffPair, 0, len(layers))
+		for i, l := range layers {
+			exported = append(exported, blobs.DiffPair{DiffID: diffIDs[i], Blobsum: l.Digest})
+		}
+		dt, err = ic.inlineCache(ctx, dt, ref, records, exported)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if prov, ok := opt[exporter.ProvenanceKey].([]byte); ok {
+		dt, err = withProvenance(dt, prov)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dgst := digest.FromBytes(dt)
+	configDone := OneOffProgress(ctx, "exporting config "+dgst.String())
+
+	if err := content.WriteBlob(ctx, ic.opt.ContentStore, dgst.String(), bytes.NewReader(dt), int64(len(dt)), dgst); err != nil {
+		return nil, configDone(errors.Wrap(err, "error writing config blob"))
+	}
+	configDone(nil)
+
+	mfst := manifest{
+		MediaType: co.manifestMediaType(),
+		Manifest: ocispec.Manifest{
+			Config: ocispec.Descriptor{
+				Digest:    dgst,
+				Size:      int64(len(dt)),
+				MediaType: co.configMediaType(),
+			},
+			Layers: layers,
+		},
+	}
+	mfst.SchemaVersion = 2
+
+	dt, err = json.Marshal(mfst)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal manifest")
+	}
+
+	dgst = digest.FromBytes(dt)
+	mfstDone := OneOffProgress(ctx, "exporting manifest "+dgst.String())
+
+	if err := content.WriteBlob(ctx, ic.opt.ContentStore, dgst.String(), bytes.NewReader(dt), int64(len(dt)), dgst); err != nil {
+		return nil, mfstDone(errors.Wrap(err, "error writing manifest blob"))
+	}
+	mfstDone(nil)
+
+	return &ocispec.Descriptor{
+		Digest:    dgst,
+		Size:      int64(len(dt)),
+		MediaType: co.manifestMediaType(),
+	}, nil
+}
+
+// inlineCache adds the cache config of the records in the chain of ref to the
+// image config dt
+func (ic *ImageWriter) inlineCache(ctx context.Context, dt []byte, ref cache.ImmutableRef, records cacheimport.Records, exported []blobs.DiffPair) ([]byte, error) {
+	cacheDone := OneOffProgress(ctx, "exporting inline cache")
+	cfg, err := cacheimport.InlineConfig(ctx, ref, records, exported)
+	if err != nil {
+		return nil, cacheDone(err)
+	}
+	if cfg == nil {
+		return dt, cacheDone(nil)
+	}
+	var img map[string]json.RawMessage
+	if err := json.Unmarshal(dt, &img); err != nil {
+		return nil, cacheDone(errors.Wrap(err, "failed to parse image config"))
+	}
+	img[cacheimport.InlineConfigKey] = cfg
+	dt, err = json.Marshal(img)
+	if err != nil {
+		return nil, cacheDone(errors.Wrap(err, "failed to marshal image config"))
+	}
+	return dt, cacheDone(nil)
+}
+
+// withProvenance adds the provenance prov to the labels of the image config
+// dt
+func withProvenance(dt, prov []byte) ([]byte, error) {
+	var img map[string]json.RawMessage
+	if err := json.Unmarshal(dt, &img); err != nil {
+		return nil, errors.Wrap(err, "failed to parse image config")
+	}
+	var cfg map[string]json.RawMessage
+	if c, ok := img["config"]; ok {
+		if err := json.Unmarshal(c, &cfg); err != nil {
+			return nil, errors.Wrap(err, "failed to parse image config")
+		}
+	}
+	if cfg == nil {
+		cfg = map[string]json.RawMessage{}
+	}
+	var labels map[string]string
+	if l, ok := cfg["Labels"]; ok {
+		if err := json.Unmarshal(l, &labels); err != nil {
+			return nil, errors.Wrap(err, "failed to parse image labels")
+		}
+	}
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[provenanceLabel] = string(prov)
+
+	var err error
+	if cfg["Labels"], err = json.Marshal(labels); err != nil {
+		return nil, err
+	}
+	if img["config"], err = json.Marshal(cfg); err != nil {
+		return nil, err
+	}
+	return json.Marshal(img)
+}
+
+// CommitIndex writes a manifest list of the manifests to the content store and
+// returns the descriptor of the list. The descriptors of the manifests should
+// have their platforms set.
+func (ic *ImageWriter) CommitIndex(ctx context.Context, manifests []ocispec.Descriptor, co CommitOpt) (*ocispec.Descriptor, error) {
+	idx := index{
+		MediaType: co.indexMediaType(),
+		Index:     ocispec.Index{Manifests: manifests},
+	}
+	idx.SchemaVersion = 2
+
+	dt, err := json.Marshal(idx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal manifest list")
+	}
+
+	dgst := digest.FromBytes(dt)
+	idxDone := OneOffProgress(ctx, "exporting manifest list "+dgst.String())
+
+	if err := content.WriteBlob(ctx, ic.opt.ContentStore, dgst.String(), bytes.NewReader(dt), int64(len(dt)), dgst); err != nil {
+		return nil, idxDone(errors.Wrap(err, "error writing manifest list blob"))
+	}
+	idxDone(nil)
+
+	return &ocispec.Descriptor{
+		Digest:    dgst,
+		Size:      int64(len(dt)),
+		MediaType: co.indexMediaType(),
+	}, nil
+}
+
+// manifest is an ocispec.Manifest with the mediaType field that the Docker
+// manifests require
+type manifest struct {
+	MediaType string `json:"mediaType,omitempty"`
+	ocispec.Manifest
+}
+
+// index is an ocispec.Index with the mediaType field that the Docker manifest
+// lists require
+type index struct {
+	MediaType string `json:"mediaType,omitempty"`
+	ocispec.Index
+}
+
+// Manifest reads back the manifest written by Commit
+func (ic *ImageWriter) Manifest(ctx context.Context, desc ocispec.Descriptor) (*ocispec.Manifest, error) {
+	dt, err := content.ReadBlob(ctx, ic.opt.ContentStore, desc.Digest)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read manifest %s", desc.Digest)
+	}
+	var mfst ocispec.Manifest
+	if err := json.Unmarshal(dt, &mfst); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse manifest %s", desc.Digest)
+	}
+	return &mfst, nil
+}
+
+func (ic *ImageWriter) ContentStore() content.Store {
+	return ic.opt.ContentStore
+}
+
+// this is temporary: should move to dockerfile frontend
+func imageConfig(diffIDs []digest.Digest) ocispec.Image {
+	img := ocispec.Image{
+		Architecture: runtime.GOARCH,
+		OS:           runtime.GOOS,
+	}
+	img.RootFS.Type = "layers"
+	img.RootFS.DiffIDs = diffIDs
+	img.History = normalizeHistory(nil, len(diffIDs))
+	img.Config.WorkingDir = "/"
+	img.Config.Env = []string{"PATH=" + system.DefaultPathEnv}
+	return img
+}
+
+func setDiffIDs(img *dockerfile2llb.Image, diffIDs []digest.Digest) {
+	img.RootFS.Type = "layers"
+	img.RootFS.DiffIDs = diffIDs
+}
+
+// normalizeHistory makes sure that history has an entry for each of the
+// layers. The entries of the layers the history doesn't describe are added
+// to the end.
+func normalizeHistory(history []ocispec.History, layers int) []ocispec.History {
+	var withLayer int
+	for _, h := range history {
+		if !h.EmptyLayer {
+			withLayer++
+		}
+	}
+	if withLayer > layers {
+		// the history can't be matched with the layers
+		history = nil
+		withLayer = 0
+	}
+	now := time.Now()
+	for ; withLayer < layers; withLayer++ {
+		history = append(history, ocispec.History{
+			Created:   &now,
+			CreatedBy: "buildkit",
+		})
+	}
+	return history
+}
+
+// OneOffProgress writes the start of the progress status id and returns a
+// function that completes it
+func OneOffProgress(ctx context.Context, id string) func(err error) error {
+	pw, _, _ := progress.FromContext(ctx)
+	now := time.Now()
+	st := progress.Status{
+		Started: &now,
+	}
+	pw.Write(id, st)
+	return func(err error) error {
+		// TODO: set error on status
+		now := time.Now()
+		st.Completed = &now
+		pw.Write(id, st)
+		pw.Close()
+		return err
+	}
+}
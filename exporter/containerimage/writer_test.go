@@ -0,0 +1,64 @@
+package containerimage
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeHistory(t *testing.T) {
+	history := normalizeHistory(nil, 2)
+	require.Equal(t, 2, len(history))
+	for _, h := range history {
+		require.False(t, h.EmptyLayer)
+		require.NotNil(t, h.Created)
+	}
+
+	history = normalizeHistory([]ocispec.History{
+		{CreatedBy: "base"},
+		{CreatedBy: "env", EmptyLayer: true},
+	}, 3)
+	require.Equal(t, 4, len(history))
+	require.Equal(t, "base", history[0].CreatedBy)
+	require.Equal(t, "env", history[1].CreatedBy)
+	require.Equal(t, "buildkit", history[2].CreatedBy)
+	require.Equal(t, "buildkit", history[3].CreatedBy)
+
+	// history describing more layers than the image has is dropped
+	history = normalizeHistory([]ocispec.History{
+		{CreatedBy: "a"},
+		{CreatedBy: "b"},
+	}, 1)
+	require.Equal(t, 1, len(history))
+	require.Equal(t, "buildkit", history[0].CreatedBy)
+}
+
+func TestImageConfigCreated(t *testing.T) {
+	// the time of the build isn't part of the config without an epoch
+	img := imageConfig(nil)
+	require.Nil(t, img.Created)
+
+	epoch := time.Unix(1500000000, 0)
+	setEpoch(&img, epoch)
+	require.Equal(t, epoch, *img.Created)
+}
+
+func TestWithProvenance(t *testing.T) {
+	prov := []byte(`{"type":"provenance"}`)
+	for cfg, labels := range map[string]map[string]string{
+		`{"architecture":"amd64"}`:                                                 {provenanceLabel: string(prov)},
+		`{"architecture":"amd64","config":{"Labels":null}}`:                        {provenanceLabel: string(prov)},
+		`{"architecture":"amd64","config":{"Env":["A=B"],"Labels":{"foo":"bar"}}}`: {provenanceLabel: string(prov), "foo": "bar"},
+	} {
+		dt, err := withProvenance([]byte(cfg), prov)
+		require.NoError(t, err)
+
+		var img ocispec.Image
+		require.NoError(t, json.Unmarshal(dt, &img))
+		require.Equal(t, "amd64", img.Architecture)
+		require.Equal(t, labels, img.Config.Labels)
+	}
+}
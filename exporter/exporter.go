@@ -2,14 +2,61 @@ package exporter
 
 import (
 	"github.com/moby/buildkit/cache"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"golang.org/x/net/context"
 )
 
+// ResultsKey is the key of the named results of the build in the options
+// passed to Export. The value is a map[string]cache.ImmutableRef.
+const ResultsKey = "buildkit.results"
+
+// ImageConfigKey is the key of the image config of the result in the options
+// passed to Export and in the exporter attributes of the image exporters.
+// Frontends can set it to the JSON image config, eg. from
+// llb.State.ImageConfig, with the ref of the result.
+const ImageConfigKey = "containerimage.config"
+
+const (
+	// ImageDigestKey is the key of the digest of the exported manifest, or
+	// manifest list, in the response of the image exporters
+	ImageDigestKey = "containerimage.digest"
+	// ImageDescriptorKey is the key of the JSON descriptor of the exported
+	// manifest, or manifest list, in the response of the image exporters
+	ImageDescriptorKey = "containerimage.descriptor"
+)
+
+// InlineCacheKey is the key of the cache records of the build in the options
+// passed to the exporters that implement InlineCacheExporter. The value is a
+// cacheimport.Records.
+const InlineCacheKey = "buildkit.inlinecache"
+
+// ProvenanceKey is the key of the JSON encoded provenance of the build in the
+// options passed to Export if the daemon records it
+const ProvenanceKey = "buildkit.provenance"
+
+// InlineCacheExporter is implemented by the exporter instances that can embed
+// the build cache in their result
+type InlineCacheExporter interface {
+	// InlineCache returns true if the cache records should be passed to
+	// Export
+	InlineCache() bool
+}
+
+// PlatformExporter is implemented by the exporter instances that export the
+// result for a platform
+type PlatformExporter interface {
+	// Platform returns the platform of the main result or nil if it isn't
+	// set
+	Platform() *ocispec.Platform
+}
+
 type Exporter interface {
 	Resolve(context.Context, map[string]string) (ExporterInstance, error)
 }
 
 type ExporterInstance interface {
 	Name() string
-	Export(context.Context, cache.ImmutableRef, map[string]interface{}) error
+	// Export exports the ref and returns the metadata of the export that is
+	// sent to the client, eg. the digest of the image
+	Export(context.Context, cache.ImmutableRef, map[string]interface{}) (map[string]string, error)
 }
@@ -14,6 +14,9 @@ import (
 	"golang.org/x/time/rate"
 )
 
+// keyTargetName selects the target of the client the files are sent to
+const keyTargetName = "target"
+
 type Opt struct {
 	SessionManager *session.Manager
 }
@@ -42,35 +45,36 @@ func (e *localExporter) Resolve(ctx context.Context, opt map[string]string) (exp
 		return nil, err
 	}
 
-	li := &localExporterInstance{localExporter: e, caller: caller}
+	li := &localExporterInstance{localExporter: e, caller: caller, target: opt[keyTargetName]}
 	return li, nil
 }
 
 type localExporterInstance struct {
 	*localExporter
 	caller session.Caller
+	target string
 }
 
 func (e *localExporterInstance) Name() string {
 	return "exporting to client"
 }
 
-func (e *localExporterInstance) Export(ctx context.Context, ref cache.ImmutableRef, opt map[string]interface{}) error {
+func (e *localExporterInstance) Export(ctx context.Context, ref cache.ImmutableRef, opt map[string]interface{}) (map[string]string, error) {
 	mount, err := ref.Mount(ctx, true)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	lm := snapshot.LocalMounter(mount)
 
 	dest, err := lm.Mount()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer lm.Unmount()
 
 	progress := newProgressHandler(ctx, "copying files")
-	return filesync.CopyToCaller(ctx, dest, e.caller, progress)
+	return nil, filesync.CopyToCaller(ctx, dest, e.caller, e.target, progress)
 }
 
 func newProgressHandler(ctx context.Context, id string) func(int, bool) {
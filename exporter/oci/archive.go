@@ -0,0 +1,117 @@
+package oci
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"io"
+	"path"
+	"time"
+
+	"github.com/containerd/containerd/content"
+	digest "github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// dockerManifest is the manifest.json entry that lets `docker load` import
+// the archive
+type dockerManifest struct {
+	Config   string
+	RepoTags []string
+	Layers   []string
+}
+
+// writeArchive writes the image of desc as an OCI image layout tarball to w.
+// The archive also contains a Docker manifest.json. The files of the archive
+// are modified at modTime.
+func writeArchive(ctx context.Context, w io.Writer, provider content.Provider, desc ocispec.Descriptor, mfst *ocispec.Manifest, name string, modTime time.Time) error {
+	tw := tar.NewWriter(w)
+
+	if name != "" {
+		desc.Annotations = map[string]string{ocispec.AnnotationRefName: name}
+	}
+
+	layout, err := json.Marshal(ocispec.ImageLayout{Version: ocispec.ImageLayoutVersion})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal oci layout")
+	}
+	if err := writeFile(tw, ocispec.ImageLayoutFile, layout, modTime); err != nil {
+		return err
+	}
+
+	idx, err := json.Marshal(ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		Manifests: []ocispec.Descriptor{desc},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal index")
+	}
+	if err := writeFile(tw, "index.json", idx, modTime); err != nil {
+		return err
+	}
+
+	dm := dockerManifest{Config: blobPath(mfst.Config.Digest)}
+	if name != "" {
+		dm.RepoTags = []string{name}
+	}
+	for _, l := range mfst.Layers {
+		dm.Layers = append(dm.Layers, blobPath(l.Digest))
+	}
+	dt, err := json.Marshal([]dockerManifest{dm})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal docker manifest")
+	}
+	if err := writeFile(tw, "manifest.json", dt, modTime); err != nil {
+		return err
+	}
+
+	for _, d := range append([]ocispec.Descriptor{desc, mfst.Config}, mfst.Layers...) {
+		if err := writeBlob(ctx, tw, provider, d, modTime); err != nil {
+			return err
+		}
+	}
+
+	return tw.Close()
+}
+
+func blobPath(dgst digest.Digest) string {
+	return path.Join("blobs", dgst.Algorithm().String(), dgst.Hex())
+}
+
+func writeFile(tw *tar.Writer, name string, dt []byte, modTime time.Time) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0444,
+		Size:    int64(len(dt)),
+		ModTime: modTime,
+	}); err != nil {
+		return errors.Wrapf(err, "failed to write header for %s", name)
+	}
+	if _, err := tw.Write(dt); err != nil {
+		return errors.Wrapf(err, "failed to write %s", name)
+	}
+	return nil
+}
+
+func writeBlob(ctx context.Context, tw *tar.Writer, provider content.Provider, desc ocispec.Descriptor, modTime time.Time) error {
+	ra, err := provider.ReaderAt(ctx, desc.Digest)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read blob %s", desc.Digest)
+	}
+	defer ra.Close()
+
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    blobPath(desc.Digest),
+		Mode:    0444,
+		Size:    desc.Size,
+		ModTime: modTime,
+	}); err != nil {
+		return errors.Wrapf(err, "failed to write header for %s", desc.Digest)
+	}
+	if _, err := io.Copy(tw, io.NewSectionReader(ra, 0, desc.Size)); err != nil {
+		return errors.Wrapf(err, "failed to write blob %s", desc.Digest)
+	}
+	return nil
+}
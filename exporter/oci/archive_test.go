@@ -0,0 +1,80 @@
+package oci
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/content/local"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteArchive(t *testing.T) {
+	ctx := context.TODO()
+
+	tmpdir, err := ioutil.TempDir("", "buildkit-oci")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	cs, err := local.NewStore(tmpdir)
+	require.NoError(t, err)
+
+	writeBlob := func(dt []byte, mediaType string) ocispec.Descriptor {
+		dgst := digest.FromBytes(dt)
+		require.NoError(t, content.WriteBlob(ctx, cs, dgst.String(), bytes.NewReader(dt), int64(len(dt)), dgst))
+		return ocispec.Descriptor{MediaType: mediaType, Digest: dgst, Size: int64(len(dt))}
+	}
+
+	mfst := &ocispec.Manifest{
+		Config: writeBlob([]byte("config"), ocispec.MediaTypeImageConfig),
+		Layers: []ocispec.Descriptor{writeBlob([]byte("layer"), ocispec.MediaTypeImageLayerGzip)},
+	}
+	desc := writeBlob([]byte("manifest"), ocispec.MediaTypeImageManifest)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, writeArchive(ctx, buf, cs, desc, mfst, "docker.io/library/foo:latest", time.Now()))
+
+	files := map[string][]byte{}
+	tr := tar.NewReader(buf)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		dt, err := ioutil.ReadAll(tr)
+		require.NoError(t, err)
+		files[h.Name] = dt
+	}
+
+	require.Equal(t, 6, len(files))
+	require.Equal(t, "config", string(files[blobPath(mfst.Config.Digest)]))
+	require.Equal(t, "layer", string(files[blobPath(mfst.Layers[0].Digest)]))
+	require.Equal(t, "manifest", string(files[blobPath(desc.Digest)]))
+
+	var layout ocispec.ImageLayout
+	require.NoError(t, json.Unmarshal(files[ocispec.ImageLayoutFile], &layout))
+	require.Equal(t, ocispec.ImageLayoutVersion, layout.Version)
+
+	var idx ocispec.Index
+	require.NoError(t, json.Unmarshal(files["index.json"], &idx))
+	require.Equal(t, 1, len(idx.Manifests))
+	require.Equal(t, desc.Digest, idx.Manifests[0].Digest)
+	require.Equal(t, "docker.io/library/foo:latest", idx.Manifests[0].Annotations[ocispec.AnnotationRefName])
+
+	var dm []dockerManifest
+	require.NoError(t, json.Unmarshal(files["manifest.json"], &dm))
+	require.Equal(t, 1, len(dm))
+	require.Equal(t, blobPath(mfst.Config.Digest), dm[0].Config)
+	require.Equal(t, []string{"docker.io/library/foo:latest"}, dm[0].RepoTags)
+	require.Equal(t, []string{blobPath(mfst.Layers[0].Digest)}, dm[0].Layers)
+}
@@ -0,0 +1,115 @@
+package oci
+
+import (
+	"time"
+
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/exporter"
+	"github.com/moby/buildkit/exporter/containerimage"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/filesync"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+)
+
+const (
+	keyImageName = "name"
+	// keyTargetName selects the target of the client the tarball is sent to
+	keyTargetName = "target"
+)
+
+type Opt struct {
+	SessionManager *session.Manager
+	ImageWriter    *containerimage.ImageWriter
+}
+
+type imageExporter struct {
+	opt Opt
+}
+
+// New creates an exporter that sends the image of the result to the client
+// as an OCI image layout tarball
+func New(opt Opt) (exporter.Exporter, error) {
+	im := &imageExporter{opt: opt}
+	return im, nil
+}
+
+func (e *imageExporter) Resolve(ctx context.Context, opt map[string]string) (exporter.ExporterInstance, error) {
+	id := session.FromContext(ctx)
+	if id == "" {
+		return nil, errors.New("could not access local files without session")
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	caller, err := e.opt.SessionManager.Get(timeoutCtx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	i := &imageExporterInstance{imageExporter: e, caller: caller}
+	for k, v := range opt {
+		if ok, err := i.commitOpt.Parse(k, v); ok {
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		switch k {
+		case keyImageName:
+			i.name = v
+		case keyTargetName:
+			i.target = v
+		default:
+			logrus.Warnf("oci exporter: unknown option %s", k)
+		}
+	}
+	return i, nil
+}
+
+type imageExporterInstance struct {
+	*imageExporter
+	caller    session.Caller
+	name      string
+	target    string
+	commitOpt containerimage.CommitOpt
+}
+
+func (e *imageExporterInstance) Name() string {
+	return "exporting to oci image format"
+}
+
+func (e *imageExporterInstance) Export(ctx context.Context, ref cache.ImmutableRef, opt map[string]interface{}) (map[string]string, error) {
+	desc, err := e.opt.ImageWriter.Commit(ctx, ref, opt, e.commitOpt)
+	if err != nil {
+		return nil, err
+	}
+	mfst, err := e.opt.ImageWriter.Manifest(ctx, *desc)
+	if err != nil {
+		return nil, err
+	}
+
+	done := containerimage.OneOffProgress(ctx, "sending tarball")
+	w, err := filesync.CopyFileWriter(ctx, e.caller, e.target)
+	if err != nil {
+		return nil, done(err)
+	}
+	modTime := time.Now()
+	if e.commitOpt.SourceDateEpoch != nil {
+		modTime = *e.commitOpt.SourceDateEpoch
+	}
+	if err := writeArchive(ctx, w, e.opt.ImageWriter.ContentStore(), *desc, mfst, e.name, modTime); err != nil {
+		w.Close()
+		return nil, done(err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, done(err)
+	}
+	resp, err := containerimage.DescriptorResponse(*desc)
+	if err != nil {
+		return nil, done(err)
+	}
+	return resp, done(nil)
+}
@@ -0,0 +1,88 @@
+package tar
+
+import (
+	"time"
+
+	"github.com/containerd/containerd/archive"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/exporter"
+	"github.com/moby/buildkit/exporter/containerimage"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/filesync"
+	"github.com/moby/buildkit/snapshot"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// keyTargetName selects the target of the client the tarball is sent to
+const keyTargetName = "target"
+
+type Opt struct {
+	SessionManager *session.Manager
+}
+
+type tarExporter struct {
+	opt Opt
+}
+
+// New creates an exporter that sends the files of the result to the client
+// as a tarball
+func New(opt Opt) (exporter.Exporter, error) {
+	te := &tarExporter{opt: opt}
+	return te, nil
+}
+
+func (e *tarExporter) Resolve(ctx context.Context, opt map[string]string) (exporter.ExporterInstance, error) {
+	id := session.FromContext(ctx)
+	if id == "" {
+		return nil, errors.New("could not access local files without session")
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	caller, err := e.opt.SessionManager.Get(timeoutCtx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ti := &tarExporterInstance{tarExporter: e, caller: caller, target: opt[keyTargetName]}
+	return ti, nil
+}
+
+type tarExporterInstance struct {
+	*tarExporter
+	caller session.Caller
+	target string
+}
+
+func (e *tarExporterInstance) Name() string {
+	return "exporting to client"
+}
+
+func (e *tarExporterInstance) Export(ctx context.Context, ref cache.ImmutableRef, opt map[string]interface{}) (map[string]string, error) {
+	mount, err := ref.Mount(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	lm := snapshot.LocalMounter(mount)
+
+	src, err := lm.Mount()
+	if err != nil {
+		return nil, err
+	}
+	defer lm.Unmount()
+
+	done := containerimage.OneOffProgress(ctx, "sending tarball")
+	w, err := filesync.CopyFileWriter(ctx, e.caller, e.target)
+	if err != nil {
+		return nil, done(err)
+	}
+	// an empty lower directory produces a tarball of all the files
+	if err := archive.WriteDiff(ctx, w, "", src); err != nil {
+		w.Close()
+		return nil, done(err)
+	}
+	return nil, done(w.Close())
+}
@@ -6,11 +6,13 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/containerd/containerd/platforms"
 	"github.com/moby/buildkit/cache"
 	"github.com/moby/buildkit/client/llb"
 	"github.com/moby/buildkit/frontend"
 	"github.com/moby/buildkit/frontend/dockerfile/dockerfile2llb"
 	"github.com/moby/buildkit/snapshot"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 	"golang.org/x/net/context"
 )
@@ -18,7 +20,13 @@ import (
 const (
 	keyTarget           = "target"
 	keyFilename         = "filename"
+	keyPlatform         = "platform"
+	buildArgPrefix      = "build-arg:"
+	labelPrefix         = "label:"
 	exporterImageConfig = "containerimage.config"
+	// inputDockerfile is the name of the input that replaces the local
+	// source of the Dockerfile
+	inputDockerfile = "dockerfile"
 )
 
 type dfFrontend struct{}
@@ -27,7 +35,7 @@ func NewDockerfileFrontend() frontend.Frontend {
 	return &dfFrontend{}
 }
 
-func (f *dfFrontend) Solve(ctx context.Context, llbBridge frontend.FrontendLLBBridge, opts map[string]string) (retRef cache.ImmutableRef, exporterAttr map[string]interface{}, retErr error) {
+func (f *dfFrontend) Solve(ctx context.Context, llbBridge frontend.FrontendLLBBridge, opts map[string]string, inputs map[string][][]byte) (retRef cache.ImmutableRef, exporterAttr map[string]interface{}, retErr error) {
 
 	filename := opts[keyFilename]
 	if filename == "" {
@@ -37,7 +45,30 @@ func (f *dfFrontend) Solve(ctx context.Context, llbBridge frontend.FrontendLLBBr
 		return nil, nil, errors.Errorf("invalid filename %s", filename)
 	}
 
-	src := llb.Local("dockerfile", llb.IncludePatterns([]string{filename}))
+	var platform *ocispec.Platform
+	if v := opts[keyPlatform]; v != "" {
+		m, err := platforms.Parse(v)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "invalid platform %s", v)
+		}
+		p := m.Spec()
+		platform = &p
+	}
+
+	states := make(map[string]llb.State, len(inputs))
+	for name, def := range inputs {
+		st, err := llb.FromDefinition(def)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "invalid frontend input %s", name)
+		}
+		states[name] = st
+	}
+
+	src, ok := states[inputDockerfile]
+	if !ok {
+		src = llb.Local("dockerfile", llb.IncludePatterns([]string{filename}))
+	}
+	delete(states, inputDockerfile)
 	dt, err := src.Marshal()
 	if err != nil {
 		return nil, nil, err
@@ -90,7 +121,10 @@ func (f *dfFrontend) Solve(ctx context.Context, llbBridge frontend.FrontendLLBBr
 	st, img, err := dockerfile2llb.Dockerfile2LLB(ctx, dtDockerfile, dockerfile2llb.ConvertOpt{
 		Target:       opts[keyTarget],
 		MetaResolver: llbBridge,
-		BuildArgs:    filterBuildArgs(opts),
+		BuildArgs:    filter(opts, buildArgPrefix),
+		Labels:       filter(opts, labelPrefix),
+		Inputs:       states,
+		Platform:     platform,
 	})
 
 	if err != nil {
@@ -112,11 +146,12 @@ func (f *dfFrontend) Solve(ctx context.Context, llbBridge frontend.FrontendLLBBr
 	}, nil
 }
 
-func filterBuildArgs(opt map[string]string) map[string]string {
+// filter returns the options with the key prefix, without the prefix
+func filter(opt map[string]string, prefix string) map[string]string {
 	m := map[string]string{}
 	for k, v := range opt {
-		if strings.HasPrefix(k, "build-arg:") {
-			m[strings.TrimPrefix(k, "build-arg:")] = v
+		if strings.HasPrefix(k, prefix) {
+			m[strings.TrimPrefix(k, prefix)] = v
 		}
 	}
 	return m
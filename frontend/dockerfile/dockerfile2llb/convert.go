@@ -15,20 +15,41 @@ import (
 	"github.com/docker/docker/pkg/signal"
 	"github.com/docker/go-connections/nat"
 	"github.com/moby/buildkit/client/llb"
+	"github.com/moby/buildkit/util/system"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 	"golang.org/x/sync/errgroup"
 )
 
 const (
 	emptyImageName = "scratch"
+	// InputContext is the name of the input that replaces the build context
+	InputContext = "context"
 )
 
 type ConvertOpt struct {
 	Target       string
 	MetaResolver llb.ImageMetaResolver
 	BuildArgs    map[string]string
+	// Labels are added to the labels of the image
+	Labels map[string]string
+	// Inputs replace the build context, InputContext, the stages and the
+	// base images with the same names. The image config of a replaced base
+	// image is not resolved. InputContext only replaces the build context,
+	// a stage or an image named "context" can't be replaced. An input that
+	// doesn't replace anything is an error.
+	Inputs map[string]llb.State
+	// Platform selects the base images from the manifest lists and is the
+	// platform the RUN commands run for. The platform of the daemon is used
+	// if it is nil.
+	Platform *ocispec.Platform
 }
 
+// proxyArgs are the build arguments that are passed to the RUN commands
+// without declaring them with ARG, like in docker build. Undeclared proxy
+// arguments are not part of the cache key.
+var proxyArgs = []string{"HTTP_PROXY", "HTTPS_PROXY", "FTP_PROXY", "NO_PROXY"}
+
 func Dockerfile2LLB(ctx context.Context, dt []byte, opt ConvertOpt) (*llb.State, *Image, error) {
 	if len(dt) == 0 {
 		return nil, nil, errors.Errorf("the Dockerfile cannot be empty")
@@ -53,6 +74,14 @@ func Dockerfile2LLB(ctx context.Context, dt []byte, opt ConvertOpt) (*llb.State,
 	var allStages []*dispatchState
 	stagesByName := map[string]*dispatchState{}
 
+	// used are the names of the inputs that replace something
+	used := map[string]struct{}{}
+	buildContext := llb.Local("context")
+	if st, ok := opt.Inputs[InputContext]; ok {
+		buildContext = st
+		used[InputContext] = struct{}{}
+	}
+
 	for _, st := range stages {
 		name, err := shlex.ProcessWord(st.BaseName, combineArgs([]string{}, metaArgs))
 		if err != nil {
@@ -61,15 +90,29 @@ func Dockerfile2LLB(ctx context.Context, dt []byte, opt ConvertOpt) (*llb.State,
 		st.BaseName = name
 		state := llb.Scratch()
 		if st.BaseName != emptyImageName {
-			state = llb.Image(st.BaseName)
+			var opts []llb.ImageOption
+			if opt.Platform != nil {
+				opts = append(opts, llb.ImagePlatform(*opt.Platform))
+			}
+			state = llb.Image(st.BaseName, opts...)
+		} else if opt.Platform != nil {
+			state = state.Platform(*opt.Platform)
 		}
 
 		ds := &dispatchState{
 			state: state,
 			stage: st,
 		}
-		if d, ok := stagesByName[st.BaseName]; ok {
+		stageName := strings.ToLower(st.Name)
+		if input, ok := opt.Inputs[stageName]; ok && stageName != "" && stageName != InputContext {
+			ds.state = input
+			ds.replaced = true
+			used[stageName] = struct{}{}
+		} else if d, ok := stagesByName[st.BaseName]; ok {
 			ds.base = d
+		} else if input, ok := imageInput(opt.Inputs, st.BaseName, used); ok {
+			ds.state = input
+			ds.inputBase = true
 		}
 
 		allStages = append(allStages, ds)
@@ -85,7 +128,7 @@ func Dockerfile2LLB(ctx context.Context, dt []byte, opt ConvertOpt) (*llb.State,
 
 	eg, ctx := errgroup.WithContext(ctx)
 	for i, d := range allStages {
-		if d.base == nil && d.stage.BaseName != emptyImageName {
+		if d.base == nil && !d.replaced && !d.inputBase && d.stage.BaseName != emptyImageName {
 			func(i int, d *dispatchState) {
 				eg.Go(func() error {
 					ref, err := reference.ParseNormalizedNamed(d.stage.BaseName)
@@ -94,7 +137,7 @@ func Dockerfile2LLB(ctx context.Context, dt []byte, opt ConvertOpt) (*llb.State,
 					}
 					d.stage.BaseName = reference.TagNameOnly(ref).String()
 					if metaResolver != nil {
-						dt, err := metaResolver.ResolveImageConfig(ctx, d.stage.BaseName)
+						_, dt, err := metaResolver.ResolveImageConfig(ctx, d.stage.BaseName, opt.Platform)
 						if err != nil {
 							return nil // handle the error while builder is actually running
 							// TODO: detect unreachable stages so config is not pulled for them
@@ -116,6 +159,9 @@ func Dockerfile2LLB(ctx context.Context, dt []byte, opt ConvertOpt) (*llb.State,
 	}
 
 	for _, d := range allStages {
+		if d.replaced {
+			continue
+		}
 		if d.base != nil {
 			d.state = d.base.state
 			d.image = clone(d.base.image)
@@ -123,6 +169,9 @@ func Dockerfile2LLB(ctx context.Context, dt []byte, opt ConvertOpt) (*llb.State,
 
 		var args []instructions.ArgCommand
 
+		// like in docker build, an image without PATH gets the default one
+		// of its platform
+		d.image.Config.Env = addEnv(d.image.Config.Env, "PATH", system.DefaultPathEnvForOS(d.image.OS), false)
 		for _, env := range d.image.Config.Env {
 			parts := strings.SplitN(env, "=", 2)
 			v := ""
@@ -138,8 +187,22 @@ func Dockerfile2LLB(ctx context.Context, dt []byte, opt ConvertOpt) (*llb.State,
 				return nil, nil, err
 			}
 		}
+		if d.image.Config.User != "" {
+			if err = dispatchUser(d, &instructions.UserCommand{User: d.image.Config.User}); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		// the ONBUILD triggers of the base run before the commands of the
+		// stage and are not inherited further
+		commands, err := parseOnbuildTriggers(d.image.Config.OnBuild)
+		if err != nil {
+			return nil, nil, err
+		}
+		d.image.Config.OnBuild = nil
+		commands = append(commands, d.stage.Commands...)
 
-		for _, cmd := range d.stage.Commands {
+		for _, cmd := range commands {
 			if ex, ok := cmd.(instructions.SupportsSingleWordExpansion); ok {
 				err := ex.Expand(func(word string) (string, error) {
 					return shlex.ProcessWord(word, combineArgs(d.image.Config.Env, args))
@@ -153,11 +216,11 @@ func Dockerfile2LLB(ctx context.Context, dt []byte, opt ConvertOpt) (*llb.State,
 			case *instructions.EnvCommand:
 				err = dispatchEnv(d, c)
 			case *instructions.RunCommand:
-				err = dispatchRun(d, c, args)
+				err = dispatchRun(d, c, args, opt.BuildArgs)
 			case *instructions.WorkdirCommand:
 				err = dispatchWorkdir(d, c)
 			case *instructions.AddCommand:
-				err = dispatchCopy(d, c.SourcesAndDest, llb.Local("context"))
+				err = dispatchCopy(d, c.SourcesAndDest, buildContext)
 			case *instructions.LabelCommand:
 				err = dispatchLabel(d, c)
 			case *instructions.OnbuildCommand:
@@ -181,15 +244,17 @@ func Dockerfile2LLB(ctx context.Context, dt []byte, opt ConvertOpt) (*llb.State,
 			case *instructions.ArgCommand:
 				args, err = dispatchArg(d, c, args, metaArgs, opt.BuildArgs)
 			case *instructions.CopyCommand:
-				l := llb.Local("context")
+				l := buildContext
 				if c.From != "" {
 					index, err := strconv.Atoi(c.From)
 					if err != nil {
-						stn, ok := stagesByName[strings.ToLower(c.From)]
-						if !ok {
+						if stn, ok := stagesByName[strings.ToLower(c.From)]; ok {
+							l = stn.state
+						} else if input, ok := imageInput(opt.Inputs, c.From, used); ok {
+							l = input
+						} else {
 							return nil, nil, errors.Errorf("stage %s not found", c.From)
 						}
-						l = stn.state
 					} else {
 						if index >= len(allStages) {
 							return nil, nil, errors.Errorf("invalid stage index %d", index)
@@ -208,15 +273,27 @@ func Dockerfile2LLB(ctx context.Context, dt []byte, opt ConvertOpt) (*llb.State,
 
 	}
 
-	if opt.Target == "" {
-		return &allStages[len(allStages)-1].state, &allStages[len(allStages)-1].image, nil
+	for name := range opt.Inputs {
+		if _, ok := used[name]; !ok {
+			return nil, nil, errors.Errorf("frontend input %s doesn't match a stage or an image of the Dockerfile", name)
+		}
 	}
 
-	state, ok := stagesByName[strings.ToLower(opt.Target)]
-	if !ok {
-		return nil, nil, errors.Errorf("target stage %s could not be found", opt.Target)
+	target := allStages[len(allStages)-1]
+	if opt.Target != "" {
+		var ok bool
+		target, ok = stagesByName[strings.ToLower(opt.Target)]
+		if !ok {
+			return nil, nil, errors.Errorf("target stage %s could not be found", opt.Target)
+		}
 	}
-	return &state.state, &state.image, nil
+	if len(opt.Labels) > 0 && target.image.Config.Labels == nil {
+		target.image.Config.Labels = make(map[string]string, len(opt.Labels))
+	}
+	for k, v := range opt.Labels {
+		target.image.Config.Labels[k] = v
+	}
+	return &target.state, &target.image, nil
 }
 
 type dispatchState struct {
@@ -224,6 +301,33 @@ type dispatchState struct {
 	image Image
 	stage instructions.Stage
 	base  *dispatchState
+	// replaced is set if the stage is replaced by an input, its commands
+	// don't run
+	replaced bool
+	// inputBase is set if the base image is replaced by an input
+	inputBase bool
+}
+
+// imageInput returns the input with the name of the image, as written or
+// normalized like the image references, and adds its name to used
+func imageInput(inputs map[string]llb.State, name string, used map[string]struct{}) (llb.State, bool) {
+	if name == InputContext {
+		return llb.State{}, false
+	}
+	if st, ok := inputs[name]; ok {
+		used[name] = struct{}{}
+		return st, true
+	}
+	ref, err := reference.ParseNormalizedNamed(name)
+	if err != nil {
+		return llb.State{}, false
+	}
+	name = reference.TagNameOnly(ref).String()
+	st, ok := inputs[name]
+	if ok {
+		used[name] = struct{}{}
+	}
+	return st, ok
 }
 
 func dispatchEnv(d *dispatchState, c *instructions.EnvCommand) error {
@@ -234,17 +338,18 @@ func dispatchEnv(d *dispatchState, c *instructions.EnvCommand) error {
 	return nil
 }
 
-func dispatchRun(d *dispatchState, c *instructions.RunCommand, buildArgs []instructions.ArgCommand) error {
-	var args []string = c.CmdLine
+func dispatchRun(d *dispatchState, c *instructions.RunCommand, buildArgs []instructions.ArgCommand, values map[string]string) error {
+	run := llb.Args(append(d.image.Config.Entrypoint, c.CmdLine...))
 	if c.PrependShell {
-		args = append(defaultShell(), strings.Join(args, " "))
-	} else if d.image.Config.Entrypoint != nil {
-		args = append(d.image.Config.Entrypoint, args...)
+		run = llb.ShellForm(strings.Join(c.CmdLine, " "), defaultShell()...)
 	}
-	opt := []llb.RunOption{llb.Args(args)}
+	opt := []llb.RunOption{run}
 	for _, arg := range buildArgs {
 		opt = append(opt, llb.AddEnv(arg.Key, getArgValue(arg)))
 	}
+	if env := undeclaredProxyEnv(buildArgs, values); len(env) > 0 {
+		opt = append(opt, llb.WithProxyEnv(env...))
+	}
 	d.state = d.state.Run(opt...).Root()
 	return nil
 }
@@ -268,12 +373,16 @@ func dispatchCopy(d *dispatchState, c instructions.SourcesAndDest, sourceState l
 	mounts := make([]llb.RunOption, 0, len(c.Sources()))
 	for i, src := range c.Sources() {
 		d, f := splitWildcards(src)
+		opts := []llb.MountOption{llb.SourcePath(d), llb.Readonly}
 		if f == "" {
 			f = path.Base(src)
+		} else {
+			// only the matched files are part of the cache key
+			opts = append(opts, llb.ContentSelector(f, false))
 		}
 		target := path.Join(fmt.Sprintf("/src-%d", i), f)
 		args = append(args, target)
-		mounts = append(mounts, llb.AddMount(target, sourceState, llb.SourcePath(d), llb.Readonly))
+		mounts = append(mounts, llb.AddMount(target, sourceState, opts...))
 	}
 
 	args = append(args, dest)
@@ -356,6 +465,7 @@ func dispatchExpose(d *dispatchState, c *instructions.ExposeCommand) error {
 	return nil
 }
 func dispatchUser(d *dispatchState, c *instructions.UserCommand) error {
+	d.state = d.state.User(c.User)
 	d.image.Config.User = c.User
 	return nil
 }
@@ -399,6 +509,25 @@ func dispatchArg(d *dispatchState, c *instructions.ArgCommand, args []instructio
 	return args, nil
 }
 
+func parseOnbuildTriggers(triggers []string) ([]instructions.Command, error) {
+	var commands []instructions.Command
+	for _, trigger := range triggers {
+		ast, err := parser.Parse(strings.NewReader(trigger))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse onbuild trigger %q", trigger)
+		}
+		if len(ast.AST.Children) != 1 {
+			return nil, errors.Errorf("onbuild trigger %q should be a single expression", trigger)
+		}
+		cmd, err := instructions.ParseCommand(ast.AST.Children[0])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid onbuild trigger %q", trigger)
+		}
+		commands = append(commands, cmd)
+	}
+	return commands, nil
+}
+
 func splitWildcards(name string) (string, string) {
 	i := 0
 	for ; i < len(name); i++ {
@@ -412,7 +541,8 @@ func splitWildcards(name string) (string, string) {
 	if i == len(name) {
 		return name, ""
 	}
-	return path.Dir(name[:i]), path.Base(name[:i]) + name[i:]
+	dir, base := path.Split(name[:i])
+	return path.Clean(dir), base + name[i:]
 }
 
 func addEnv(env []string, k, v string, override bool) []string {
@@ -445,6 +575,27 @@ func setArgValue(c instructions.ArgCommand, values map[string]string) instructio
 	return c
 }
 
+// undeclaredProxyEnv returns the proxy build arguments that are not declared
+// with ARG as KEY=VALUE pairs
+func undeclaredProxyEnv(args []instructions.ArgCommand, values map[string]string) []string {
+	declared := map[string]struct{}{}
+	for _, arg := range args {
+		declared[arg.Key] = struct{}{}
+	}
+	var env []string
+	for _, k := range proxyArgs {
+		for _, k := range []string{k, strings.ToLower(k)} {
+			if _, ok := declared[k]; ok {
+				continue
+			}
+			if v, ok := values[k]; ok {
+				env = append(env, k+"="+v)
+			}
+		}
+	}
+	return env
+}
+
 func combineArgs(env []string, args []instructions.ArgCommand) []string {
 	for _, arg := range args {
 		env = addEnv(env, arg.Key, getArgValue(arg), false)
@@ -1,10 +1,21 @@
 package dockerfile2llb
 
 import (
+	"encoding/json"
+	"sort"
+	"strings"
 	"testing"
 
+	"github.com/moby/buildkit/client/llb"
+	"github.com/moby/buildkit/solver/pb"
 	"github.com/moby/buildkit/util/appcontext"
+	"github.com/moby/buildkit/util/system"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
 )
 
 func TestDockerfileParsing(t *testing.T) {
@@ -41,3 +52,288 @@ COPY --from=0 f2 /
 	})
 	assert.Error(t, err)
 }
+
+type testMetaResolver map[string]Image
+
+func (r testMetaResolver) ResolveImageConfig(ctx context.Context, ref string, platform *ocispec.Platform) (digest.Digest, []byte, error) {
+	img, ok := r[ref]
+	if !ok {
+		return "", nil, errors.Errorf("%s not found", ref)
+	}
+	dt, err := json.Marshal(img)
+	if err != nil {
+		return "", nil, err
+	}
+	return digest.FromBytes(dt), dt, nil
+}
+
+func TestDockerfilePlatform(t *testing.T) {
+	df := `FROM busybox
+RUN true
+FROM scratch
+COPY --from=0 /bin /bin
+RUN true
+`
+	p := ocispec.Platform{OS: "linux", Architecture: "arm64"}
+	var resolved []*ocispec.Platform
+	resolver := testPlatformResolver(func(platform *ocispec.Platform) {
+		resolved = append(resolved, platform)
+	})
+	st, _, err := Dockerfile2LLB(appcontext.Context(), []byte(df), ConvertOpt{
+		MetaResolver: resolver,
+		Platform:     &p,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []*ocispec.Platform{&p}, resolved)
+
+	def, err := st.Marshal()
+	require.NoError(t, err)
+	var images, execs int
+	for _, dt := range def[:len(def)-1] {
+		var op pb.Op
+		require.NoError(t, op.Unmarshal(dt))
+		switch {
+		case op.GetSource() != nil && strings.HasPrefix(op.GetSource().Identifier, "docker-image://docker.io/library/busybox"):
+			images++
+		case op.GetExec() != nil && op.GetExec().Meta.Args[len(op.GetExec().Meta.Args)-1] == "true":
+			execs++
+		default:
+			continue
+		}
+		require.NotNil(t, op.Platform)
+		require.Equal(t, "arm64", op.Platform.Architecture)
+	}
+	require.Equal(t, 1, images)
+	require.Equal(t, 2, execs)
+}
+
+type testPlatformResolver func(*ocispec.Platform)
+
+func (r testPlatformResolver) ResolveImageConfig(ctx context.Context, ref string, platform *ocispec.Platform) (digest.Digest, []byte, error) {
+	r(platform)
+	dt, err := json.Marshal(Image{})
+	if err != nil {
+		return "", nil, err
+	}
+	return digest.FromBytes(dt), dt, nil
+}
+
+func TestDockerfileOnbuild(t *testing.T) {
+	var base Image
+	base.Config.Env = []string{"PATH=/bin"}
+	base.Config.OnBuild = []string{"ENV FOO=bar", "LABEL foo=bar"}
+
+	df := `FROM busybox AS build
+ONBUILD ENV BAZ=qux
+FROM build
+`
+	_, img, err := Dockerfile2LLB(appcontext.Context(), []byte(df), ConvertOpt{
+		MetaResolver: testMetaResolver{"docker.io/library/busybox:latest": base},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"PATH=/bin", "FOO=bar", "BAZ=qux"}, img.Config.Env)
+	require.Equal(t, "bar", img.Config.Labels["foo"])
+	require.Equal(t, 0, len(img.Config.OnBuild))
+
+	base.Config.OnBuild = []string{"FROM scratch"}
+	_, _, err = Dockerfile2LLB(appcontext.Context(), []byte("FROM busybox\n"), ConvertOpt{
+		MetaResolver: testMetaResolver{"docker.io/library/busybox:latest": base},
+	})
+	require.Error(t, err)
+}
+
+func TestDockerfileProxyArgs(t *testing.T) {
+	df := `FROM scratch
+ARG https_proxy
+RUN true
+`
+	st, _, err := Dockerfile2LLB(appcontext.Context(), []byte(df), ConvertOpt{
+		BuildArgs: map[string]string{
+			"HTTP_PROXY":  "http://proxy:3128",
+			"https_proxy": "http://secure:3128",
+			"FOO":         "bar",
+		},
+	})
+	require.NoError(t, err)
+
+	def, err := st.Marshal()
+	require.NoError(t, err)
+	var op pb.Op
+	require.NoError(t, op.Unmarshal(def[len(def)-2]))
+	exec := op.GetExec()
+	require.NotNil(t, exec)
+	require.Equal(t, []string{"HTTP_PROXY=http://proxy:3128"}, exec.ProxyEnv)
+	require.Contains(t, exec.Meta.Env, "https_proxy=http://secure:3128")
+	for _, env := range exec.Meta.Env {
+		require.NotContains(t, env, "FOO")
+		require.NotContains(t, env, "HTTP_PROXY")
+	}
+}
+
+func TestDockerfileLabels(t *testing.T) {
+	df := `FROM scratch AS build
+LABEL foo=bar
+FROM scratch
+`
+	_, img, err := Dockerfile2LLB(appcontext.Context(), []byte(df), ConvertOpt{
+		Target: "build",
+		Labels: map[string]string{"foo": "override", "bar": "baz"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"foo": "override", "bar": "baz"}, img.Config.Labels)
+}
+
+func TestDockerfileCopyWildcard(t *testing.T) {
+	df := `FROM scratch
+COPY src/*.go foo /app/
+`
+	st, _, err := Dockerfile2LLB(appcontext.Context(), []byte(df), ConvertOpt{})
+	require.NoError(t, err)
+
+	def, err := st.Marshal()
+	require.NoError(t, err)
+	var op pb.Op
+	require.NoError(t, op.Unmarshal(def[len(def)-2]))
+	exec := op.GetExec()
+	require.NotNil(t, exec)
+
+	mounts := map[string]*pb.Mount{}
+	for _, m := range exec.Mounts {
+		mounts[m.Dest] = m
+	}
+	m, ok := mounts["/src-0/*.go"]
+	require.True(t, ok)
+	require.Equal(t, "src", m.Selector)
+	require.Equal(t, []*pb.Selector{{Path: "*.go", Wildcard: true}}, m.ContentSelectors)
+
+	m, ok = mounts["/src-1/foo"]
+	require.True(t, ok)
+	require.Equal(t, "foo", m.Selector)
+	require.Nil(t, m.ContentSelectors)
+}
+
+func TestSplitWildcards(t *testing.T) {
+	for _, tc := range []struct {
+		name, dir, pattern string
+	}{
+		{"src/*.go", "src", "*.go"},
+		{"src/foo*.go", "src", "foo*.go"},
+		{"*.go", ".", "*.go"},
+		{"foo", "foo", ""},
+	} {
+		dir, pattern := splitWildcards(tc.name)
+		require.Equal(t, tc.dir, dir, tc.name)
+		require.Equal(t, tc.pattern, pattern, tc.name)
+	}
+}
+
+func TestDockerfileInputs(t *testing.T) {
+	df := `FROM busybox AS base
+RUN false
+FROM alpine
+COPY --from=base /a /a
+COPY --from=other /b /b
+COPY c /c
+`
+	inputs := map[string]llb.State{
+		"base":                            llb.Image("docker.io/library/debian:latest"),
+		"docker.io/library/alpine:latest": llb.Local("alpine"),
+		"other":                           llb.Local("other"),
+		InputContext:                      llb.Local("ctx"),
+	}
+	st, _, err := Dockerfile2LLB(appcontext.Context(), []byte(df), ConvertOpt{
+		MetaResolver: testMetaResolver{},
+		Inputs:       inputs,
+	})
+	require.NoError(t, err)
+
+	def, err := st.Marshal()
+	require.NoError(t, err)
+	var sources []string
+	for _, dt := range def {
+		var op pb.Op
+		require.NoError(t, op.Unmarshal(dt))
+		if src := op.GetSource(); src != nil && !strings.HasPrefix(src.Identifier, "docker-image://docker.io/tonistiigi/copy") {
+			sources = append(sources, src.Identifier)
+		}
+		if exec := op.GetExec(); exec != nil {
+			require.NotContains(t, exec.Meta.Args, "false", "the commands of a replaced stage don't run")
+		}
+	}
+	sort.Strings(sources)
+	require.Equal(t, []string{"docker-image://docker.io/library/debian:latest", "local://alpine", "local://ctx", "local://other"}, sources)
+
+	_, _, err = Dockerfile2LLB(appcontext.Context(), []byte(df), ConvertOpt{MetaResolver: testMetaResolver{}})
+	require.Error(t, err)
+
+	// an input that doesn't replace anything is an error
+	inputs["unused"] = llb.Local("unused")
+	_, _, err = Dockerfile2LLB(appcontext.Context(), []byte(df), ConvertOpt{
+		MetaResolver: testMetaResolver{},
+		Inputs:       inputs,
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unused")
+}
+
+func TestDockerfileContextStage(t *testing.T) {
+	df := `FROM busybox AS context
+RUN true
+FROM alpine
+COPY --from=context /a /a
+COPY b /b
+`
+	st, _, err := Dockerfile2LLB(appcontext.Context(), []byte(df), ConvertOpt{
+		MetaResolver: testMetaResolver{},
+		Inputs:       map[string]llb.State{InputContext: llb.Local("ctx")},
+	})
+	require.NoError(t, err)
+
+	def, err := st.Marshal()
+	require.NoError(t, err)
+	var sources []string
+	var execs int
+	for _, dt := range def {
+		var op pb.Op
+		require.NoError(t, op.Unmarshal(dt))
+		if src := op.GetSource(); src != nil && !strings.HasPrefix(src.Identifier, "docker-image://docker.io/tonistiigi/copy") {
+			sources = append(sources, src.Identifier)
+		}
+		if exec := op.GetExec(); exec != nil && len(exec.Meta.Args) > 0 && exec.Meta.Args[len(exec.Meta.Args)-1] == "true" {
+			execs++
+		}
+	}
+	sort.Strings(sources)
+	// the input replaces the build context, not the stage named context
+	require.Equal(t, []string{"docker-image://docker.io/library/alpine:latest", "docker-image://docker.io/library/busybox:latest", "local://ctx"}, sources)
+	require.Equal(t, 1, execs)
+}
+
+func TestDockerfileDefaultPath(t *testing.T) {
+	var base Image
+	base.OS = "windows"
+	base.Config.Env = []string{"FOO=bar"}
+
+	df := `FROM scratch AS empty
+FROM busybox AS unix
+FROM nanoserver AS windows
+`
+	for _, tc := range []struct {
+		target string
+		env    []string
+	}{
+		{"empty", []string{"PATH=" + system.DefaultPathEnvUnix}},
+		{"unix", []string{"PATH=/bin"}},
+		{"windows", []string{"FOO=bar", "PATH=" + system.DefaultPathEnvWindows}},
+	} {
+		_, img, err := Dockerfile2LLB(appcontext.Context(), []byte(df), ConvertOpt{
+			Target: tc.target,
+			MetaResolver: testMetaResolver{
+				"docker.io/library/busybox:latest":    Image{Config: ImageConfig{ImageConfig: ocispec.ImageConfig{Env: []string{"PATH=/bin"}}}},
+				"docker.io/library/nanoserver:latest": base,
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, tc.env, img.Config.Env)
+	}
+}
@@ -1,15 +1,28 @@
 package frontend
 
 import (
+	"io"
+
 	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/worker"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"golang.org/x/net/context"
 )
 
 type Frontend interface {
-	Solve(ctx context.Context, llb FrontendLLBBridge, opt map[string]string) (cache.ImmutableRef, map[string]interface{}, error)
+	// Solve builds the result of the frontend. inputs are named definitions
+	// that replace the inputs of the frontend with the same names.
+	Solve(ctx context.Context, llb FrontendLLBBridge, opt map[string]string, inputs map[string][][]byte) (cache.ImmutableRef, map[string]interface{}, error)
 }
 
 type FrontendLLBBridge interface {
 	Solve(ctx context.Context, vtx [][]byte) (cache.ImmutableRef, error)
-	ResolveImageConfig(ctx context.Context, ref string) ([]byte, error)
+	// ResolveImageConfig returns the digest of the manifest and the JSON
+	// config of the image ref for platform, the default platform if it is
+	// nil
+	ResolveImageConfig(ctx context.Context, ref string, platform *ocispec.Platform) (digest.Digest, []byte, error)
+	// Exec runs a process with the rootfs, like the frontends that are
+	// images themselves
+	Exec(ctx context.Context, meta worker.Meta, rootfs cache.ImmutableRef, stdin io.ReadCloser, stdout, stderr io.WriteCloser) error
 }
@@ -0,0 +1,201 @@
+package client
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containerd/containerd/platforms"
+	pb "github.com/moby/buildkit/frontend/gateway/pb"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/tonistiigi/fsutil"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+const frontendOptEnv = "BUILDKIT_FRONTEND_OPT_"
+
+// Client is used by the frontends running in a container to solve
+// definitions with the daemon that started them
+type Client struct {
+	client pb.LLBBridgeClient
+	opts   map[string]string
+}
+
+// Current connects to the daemon over the stdin and stdout of the process
+func Current() (*Client, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	conn := &stdioConn{}
+	cc, err := grpc.DialContext(ctx, "", grpc.WithDialer(func(addr string, d time.Duration) (net.Conn, error) {
+		return conn, nil
+	}), grpc.WithInsecure())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create grpc client")
+	}
+
+	c := pb.NewLLBBridgeClient(cc)
+	if _, err := c.Ping(ctx, &pb.PingRequest{}); err != nil {
+		return nil, errors.Wrap(err, "failed to connect to the gateway")
+	}
+
+	return &Client{client: c, opts: opts()}, nil
+}
+
+// Opts returns the options that were passed to the frontend
+func (c *Client) Opts() map[string]string {
+	return c.opts
+}
+
+// Inputs returns the named definitions that replace the inputs of the
+// frontend with the same names
+func (c *Client) Inputs(ctx context.Context) (map[string][][]byte, error) {
+	resp, err := c.client.Inputs(ctx, &pb.InputsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	inputs := make(map[string][][]byte, len(resp.Definitions))
+	for name, def := range resp.Definitions {
+		inputs[name] = def.Def
+	}
+	return inputs, nil
+}
+
+// ResolveImageConfig returns the digest of the manifest and the JSON config
+// of the image ref for platform, the platform of the daemon if it is nil
+func (c *Client) ResolveImageConfig(ctx context.Context, ref string, platform *ocispec.Platform) (digest.Digest, []byte, error) {
+	req := &pb.ResolveImageConfigRequest{Ref: ref}
+	if platform != nil {
+		req.Platform = platforms.Format(*platform)
+	}
+	resp, err := c.client.ResolveImageConfig(ctx, req)
+	if err != nil {
+		return "", nil, err
+	}
+	return resp.Digest, resp.Config, nil
+}
+
+// ExporterImageConfig is the exporter attribute of the JSON image config of
+// the result, eg. from llb.State.ImageConfig
+const ExporterImageConfig = "containerimage.config"
+
+// Solve solves the definition def and returns the ID of the result. The
+// result of the frontend is the result of the last final definition,
+// exporterAttr are passed to the exporter with it.
+func (c *Client) Solve(ctx context.Context, def [][]byte, exporterAttr map[string][]byte, final bool) (string, error) {
+	resp, err := c.client.Solve(ctx, &pb.SolveRequest{
+		Definition:   def,
+		Final:        final,
+		ExporterAttr: exporterAttr,
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.Ref, nil
+}
+
+// FileRange is the part of a file that is read. Length of 0 reads to the
+// end of the file.
+type FileRange struct {
+	Offset int64
+	Length int64
+}
+
+// ReadFile returns the contents of the file at fp in the result ref. The whole
+// file is read if r is nil. The daemon returns an error when more than about
+// 4MB would be read, larger files are read with ranges.
+func (c *Client) ReadFile(ctx context.Context, ref, fp string, r *FileRange) ([]byte, error) {
+	req := &pb.ReadFileRequest{Ref: ref, FilePath: fp}
+	if r != nil {
+		req.Range = &pb.FileRange{Offset: r.Offset, Length: r.Length}
+	}
+	resp, err := c.client.ReadFile(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// ReadDir lists the entries of the directory at dir in the result ref. If
+// pattern is set, only the entries with a name matching it are returned.
+func (c *Client) ReadDir(ctx context.Context, ref, dir, pattern string) ([]*fsutil.Stat, error) {
+	resp, err := c.client.ReadDir(ctx, &pb.ReadDirRequest{Ref: ref, DirPath: dir, IncludePattern: pattern})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Entries, nil
+}
+
+// StatFile returns the metadata of fp in the result ref without following a
+// symlink at fp
+func (c *Client) StatFile(ctx context.Context, ref, fp string) (*fsutil.Stat, error) {
+	resp, err := c.client.StatFile(ctx, &pb.StatFileRequest{Ref: ref, Path: fp})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Stat, nil
+}
+
+func opts() map[string]string {
+	m := map[string]string{}
+	for _, env := range os.Environ() {
+		parts := strings.SplitN(env, "=", 3)
+		if len(parts) != 3 || !strings.HasPrefix(parts[0], frontendOptEnv) {
+			continue
+		}
+		if _, err := strconv.Atoi(strings.TrimPrefix(parts[0], frontendOptEnv)); err != nil {
+			continue
+		}
+		m[parts[1]] = parts[2]
+	}
+	return m
+}
+
+type stdioConn struct{}
+
+func (s *stdioConn) Read(b []byte) (int, error) {
+	return os.Stdin.Read(b)
+}
+
+func (s *stdioConn) Write(b []byte) (int, error) {
+	return os.Stdout.Write(b)
+}
+
+func (s *stdioConn) Close() error {
+	return nil
+}
+
+func (s *stdioConn) LocalAddr() net.Addr {
+	return stdioAddr{}
+}
+
+func (s *stdioConn) RemoteAddr() net.Addr {
+	return stdioAddr{}
+}
+
+func (s *stdioConn) SetDeadline(t time.Time) error {
+	return nil
+}
+
+func (s *stdioConn) SetReadDeadline(t time.Time) error {
+	return nil
+}
+
+func (s *stdioConn) SetWriteDeadline(t time.Time) error {
+	return nil
+}
+
+type stdioAddr struct{}
+
+func (stdioAddr) Network() string {
+	return "pipe"
+}
+
+func (stdioAddr) String() string {
+	return "stdio"
+}
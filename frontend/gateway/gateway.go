@@ -0,0 +1,467 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containerd/containerd/fs"
+	"github.com/containerd/containerd/platforms"
+	"github.com/docker/distribution/reference"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/client/llb"
+	"github.com/moby/buildkit/frontend"
+	pb "github.com/moby/buildkit/frontend/gateway/pb"
+	"github.com/moby/buildkit/identity"
+	"github.com/moby/buildkit/snapshot"
+	"github.com/moby/buildkit/worker"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/tonistiigi/fsutil"
+	"golang.org/x/net/context"
+	"golang.org/x/net/http2"
+	"google.golang.org/grpc"
+)
+
+const (
+	keySource = "source"
+	// frontendOptEnv is the prefix of the environment variables that pass
+	// the frontend options to the container
+	frontendOptEnv = "BUILDKIT_FRONTEND_OPT_"
+	// maxReadFileSize limits the data returned by ReadFile below the default
+	// size limit of the grpc messages. Larger files are read with ranges.
+	maxReadFileSize = 4<<20 - 64<<10
+)
+
+type gatewayFrontend struct{}
+
+// NewGatewayFrontend returns a frontend that runs the image in the "source"
+// option as the frontend. The container talks to the LLBBridge service over
+// its stdin and stdout.
+func NewGatewayFrontend() frontend.Frontend {
+	return &gatewayFrontend{}
+}
+
+func (gf *gatewayFrontend) Solve(ctx context.Context, llbBridge frontend.FrontendLLBBridge, opts map[string]string, inputs map[string][][]byte) (retRef cache.ImmutableRef, exporterAttr map[string]interface{}, retErr error) {
+	source, ok := opts[keySource]
+	if !ok {
+		return nil, nil, errors.Errorf("no source specified for gateway")
+	}
+
+	ref, err := reference.ParseNormalizedNamed(source)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "invalid gateway source %s", source)
+	}
+	source = reference.TagNameOnly(ref).String()
+
+	_, dt, err := llbBridge.ResolveImageConfig(ctx, source, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	var img ocispec.Image
+	if err := json.Unmarshal(dt, &img); err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to parse image config of %s", source)
+	}
+
+	def, err := llb.Image(source).Marshal()
+	if err != nil {
+		return nil, nil, err
+	}
+	rootFS, err := llbBridge.Solve(ctx, def)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rootFS.Release(context.TODO())
+
+	args := append(append([]string{}, img.Config.Entrypoint...), img.Config.Cmd...)
+	if len(args) == 0 {
+		return nil, nil, errors.Errorf("no entrypoint or cmd in gateway source %s", source)
+	}
+
+	lbf := newLLBBridgeForwarder(ctx, llbBridge, inputs)
+	defer lbf.release()
+
+	meta := worker.Meta{
+		Args:           args,
+		Env:            append(append([]string{}, img.Config.Env...), frontendOptsEnv(opts)...),
+		Cwd:            img.Config.WorkingDir,
+		User:           img.Config.User,
+		ReadonlyRootFS: true,
+	}
+	if meta.Cwd == "" {
+		meta.Cwd = "/"
+	}
+
+	if err := llbBridge.Exec(ctx, meta, rootFS, lbf.stdin, lbf.stdout, nopWriteCloser{os.Stderr}); err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to run gateway source %s", source)
+	}
+
+	return lbf.result()
+}
+
+// frontendOptsEnv returns the options of the frontend, other than the
+// source, as numbered environment variables in the form of key=value
+func frontendOptsEnv(opts map[string]string) []string {
+	keys := make([]string, 0, len(opts))
+	for k := range opts {
+		if k != keySource {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	env := make([]string, 0, len(keys))
+	for i, k := range keys {
+		env = append(env, fmt.Sprintf("%s%d=%s=%s", frontendOptEnv, i, k, opts[k]))
+	}
+	return env
+}
+
+// llbBridgeForwarder serves the LLBBridge service for a single container
+type llbBridgeForwarder struct {
+	llbBridge frontend.FrontendLLBBridge
+	inputs    map[string][][]byte
+	server    *grpc.Server
+	conn      net.Conn
+	stdin     io.ReadCloser
+	stdout    io.WriteCloser
+
+	mu           sync.Mutex
+	refs         map[string]cache.ImmutableRef
+	lastRef      string
+	exporterAttr map[string][]byte
+}
+
+func newLLBBridgeForwarder(ctx context.Context, llbBridge frontend.FrontendLLBBridge, inputs map[string][][]byte) *llbBridgeForwarder {
+	stdinR, stdinW := io.Pipe()
+	stdoutR, stdoutW := io.Pipe()
+	lbf := &llbBridgeForwarder{
+		llbBridge: llbBridge,
+		inputs:    inputs,
+		server:    grpc.NewServer(),
+		conn:      &pipeConn{r: stdoutR, w: stdinW},
+		stdin:     stdinR,
+		stdout:    stdoutW,
+		refs:      map[string]cache.ImmutableRef{},
+	}
+	pb.RegisterLLBBridgeServer(lbf.server, lbf)
+
+	go func() {
+		<-ctx.Done()
+		lbf.conn.Close()
+	}()
+	go func() {
+		logrus.Debugf("serving gateway connection")
+		(&http2.Server{}).ServeConn(lbf.conn, &http2.ServeConnOpts{Handler: lbf.server})
+	}()
+	return lbf
+}
+
+// result returns the last final result. The caller of the frontend releases
+// it, all other results are released by release.
+func (lbf *llbBridgeForwarder) result() (cache.ImmutableRef, map[string]interface{}, error) {
+	lbf.mu.Lock()
+	defer lbf.mu.Unlock()
+	ref, ok := lbf.refs[lbf.lastRef]
+	if !ok {
+		return nil, nil, errors.Errorf("no result returned by the gateway frontend")
+	}
+	delete(lbf.refs, lbf.lastRef)
+
+	exporterAttr := map[string]interface{}{}
+	for k, v := range lbf.exporterAttr {
+		exporterAttr[k] = v
+	}
+	return ref, exporterAttr, nil
+}
+
+func (lbf *llbBridgeForwarder) release() {
+	lbf.conn.Close()
+	lbf.server.Stop()
+	lbf.mu.Lock()
+	defer lbf.mu.Unlock()
+	for id, ref := range lbf.refs {
+		ref.Release(context.TODO())
+		delete(lbf.refs, id)
+	}
+}
+
+func (lbf *llbBridgeForwarder) ResolveImageConfig(ctx context.Context, req *pb.ResolveImageConfigRequest) (*pb.ResolveImageConfigResponse, error) {
+	var platform *ocispec.Platform
+	if req.Platform != "" {
+		m, err := platforms.Parse(req.Platform)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid platform %s", req.Platform)
+		}
+		p := m.Spec()
+		platform = &p
+	}
+	dgst, dt, err := lbf.llbBridge.ResolveImageConfig(ctx, req.Ref, platform)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ResolveImageConfigResponse{Digest: dgst, Config: dt}, nil
+}
+
+func (lbf *llbBridgeForwarder) Solve(ctx context.Context, req *pb.SolveRequest) (*pb.SolveResponse, error) {
+	ref, err := lbf.llbBridge.Solve(ctx, req.Definition)
+	if err != nil {
+		return nil, err
+	}
+
+	id := identity.NewID()
+	lbf.mu.Lock()
+	lbf.refs[id] = ref
+	if req.Final {
+		lbf.lastRef = id
+		lbf.exporterAttr = req.ExporterAttr
+	}
+	lbf.mu.Unlock()
+	return &pb.SolveResponse{Ref: id}, nil
+}
+
+func (lbf *llbBridgeForwarder) ReadFile(ctx context.Context, req *pb.ReadFileRequest) (*pb.ReadFileResponse, error) {
+	lbf.mu.Lock()
+	ref := lbf.refs[req.Ref]
+	lbf.mu.Unlock()
+	if fr, ok := ref.(cache.FileReader); ok && (req.Range == nil || req.Range.Offset >= 0) {
+		dt, err := fr.ReadFile(ctx, req.FilePath)
+		if err == nil {
+			dt = fileRange(dt, req.Range)
+			if len(dt) > maxReadFileSize {
+				return nil, errReadFileSize(req.FilePath)
+			}
+			return &pb.ReadFileResponse{Data: dt}, nil
+		}
+		logrus.Debugf("failed to read %s without mounting: %v", req.FilePath, err)
+	}
+
+	var dt []byte
+	err := lbf.withMount(ctx, req.Ref, func(root string) error {
+		p, err := fs.RootPath(root, req.FilePath)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %s", req.FilePath)
+		}
+		defer f.Close()
+
+		// one byte more than the limit is read to detect larger files
+		limit := int64(maxReadFileSize + 1)
+		if r := req.Range; r != nil {
+			if _, err := f.Seek(r.Offset, io.SeekStart); err != nil {
+				return errors.Wrapf(err, "failed to seek %s", req.FilePath)
+			}
+			if r.Length > 0 && r.Length < limit {
+				limit = r.Length
+			}
+		}
+		dt, err = ioutil.ReadAll(io.LimitReader(f, limit))
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %s", req.FilePath)
+		}
+		if len(dt) > maxReadFileSize {
+			return errReadFileSize(req.FilePath)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ReadFileResponse{Data: dt}, nil
+}
+
+func errReadFileSize(p string) error {
+	return errors.Errorf("%s is larger than %d bytes, read it with ranges", p, maxReadFileSize)
+}
+
+// fileRange returns the part of the file dt that is read by r
+func fileRange(dt []byte, r *pb.FileRange) []byte {
+	if r == nil {
+		return dt
+	}
+	if r.Offset >= int64(len(dt)) {
+		return []byte{}
+	}
+	dt = dt[r.Offset:]
+	if r.Length > 0 && r.Length < int64(len(dt)) {
+		dt = dt[:r.Length]
+	}
+	return dt
+}
+
+func (lbf *llbBridgeForwarder) ReadDir(ctx context.Context, req *pb.ReadDirRequest) (*pb.ReadDirResponse, error) {
+	pattern := req.IncludePattern
+	if pattern == "" {
+		pattern = "*"
+	}
+	var entries []*fsutil.Stat
+	err := lbf.withMount(ctx, req.Ref, func(root string) error {
+		p, err := fs.RootPath(root, req.DirPath)
+		if err != nil {
+			return err
+		}
+		// the pattern doesn't match paths with a separator so only the
+		// direct children of the directory are walked
+		return fsutil.Walk(ctx, p, &fsutil.WalkOpt{IncludePaths: []string{pattern}}, func(path string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			entries = append(entries, fi.Sys().(*fsutil.Stat))
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ReadDirResponse{Entries: entries}, nil
+}
+
+func (lbf *llbBridgeForwarder) StatFile(ctx context.Context, req *pb.StatFileRequest) (*pb.StatFileResponse, error) {
+	var st *fsutil.Stat
+	err := lbf.withMount(ctx, req.Ref, func(root string) error {
+		// symlinks are resolved in the parent only so the link itself is
+		// returned
+		name := filepath.Base(filepath.Clean("/" + req.Path))
+		if name == string(filepath.Separator) {
+			return errors.Errorf("stat of the root is not supported")
+		}
+		dir, err := fs.RootPath(root, filepath.Dir(req.Path))
+		if err != nil {
+			return err
+		}
+		if _, err := os.Lstat(filepath.Join(dir, name)); err != nil {
+			return errors.Wrapf(err, "failed to stat %s", req.Path)
+		}
+		return fsutil.Walk(ctx, dir, &fsutil.WalkOpt{IncludePaths: []string{globEscape(name)}}, func(path string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			st = fi.Sys().(*fsutil.Stat)
+			st.Path = req.Path
+			return filepath.SkipDir
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if st == nil {
+		return nil, errors.Errorf("failed to stat %s", req.Path)
+	}
+	return &pb.StatFileResponse{Stat: st}, nil
+}
+
+// withMount calls fn with the read-only mounted root of the result id
+func (lbf *llbBridgeForwarder) withMount(ctx context.Context, id string, fn func(root string) error) error {
+	lbf.mu.Lock()
+	ref, ok := lbf.refs[id]
+	lbf.mu.Unlock()
+	if !ok {
+		return errors.Errorf("no such ref: %s", id)
+	}
+
+	mount, err := ref.Mount(ctx, true)
+	if err != nil {
+		return err
+	}
+	lm := snapshot.LocalMounter(mount)
+	root, err := lm.Mount()
+	if err != nil {
+		return err
+	}
+	defer lm.Unmount()
+	return fn(root)
+}
+
+// globEscape quotes the characters of name that are special in the patterns
+// of filepath.Match
+func globEscape(name string) string {
+	var b strings.Builder
+	for _, c := range name {
+		switch c {
+		case '*', '?', '[', '\\':
+			b.WriteRune('\\')
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+func (lbf *llbBridgeForwarder) Ping(context.Context, *pb.PingRequest) (*pb.PongResponse, error) {
+	return &pb.PongResponse{}, nil
+}
+
+func (lbf *llbBridgeForwarder) Inputs(context.Context, *pb.InputsRequest) (*pb.InputsResponse, error) {
+	defs := make(map[string]*pb.Definition, len(lbf.inputs))
+	for name, def := range lbf.inputs {
+		defs[name] = &pb.Definition{Def: def}
+	}
+	return &pb.InputsResponse{Definitions: defs}, nil
+}
+
+// pipeConn is a net.Conn over the stdio pipes of the container
+type pipeConn struct {
+	r *io.PipeReader
+	w *io.PipeWriter
+}
+
+func (c *pipeConn) Read(b []byte) (int, error) {
+	return c.r.Read(b)
+}
+
+func (c *pipeConn) Write(b []byte) (int, error) {
+	return c.w.Write(b)
+}
+
+func (c *pipeConn) Close() error {
+	c.r.Close()
+	return c.w.Close()
+}
+
+func (c *pipeConn) LocalAddr() net.Addr {
+	return pipeAddr{}
+}
+
+func (c *pipeConn) RemoteAddr() net.Addr {
+	return pipeAddr{}
+}
+
+func (c *pipeConn) SetDeadline(t time.Time) error {
+	return nil
+}
+
+func (c *pipeConn) SetReadDeadline(t time.Time) error {
+	return nil
+}
+
+func (c *pipeConn) SetWriteDeadline(t time.Time) error {
+	return nil
+}
+
+type pipeAddr struct{}
+
+func (pipeAddr) Network() string {
+	return "pipe"
+}
+
+func (pipeAddr) String() string {
+	return "pipe"
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,191 @@
+package gateway
+
+import (
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/containerd/containerd/mount"
+	"github.com/moby/buildkit/cache"
+	pb "github.com/moby/buildkit/frontend/gateway/pb"
+	"github.com/moby/buildkit/worker"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+func TestFrontendOptsEnv(t *testing.T) {
+	env := frontendOptsEnv(map[string]string{
+		keySource:       "docker.io/library/frontend:latest",
+		"filename":      "Dockerfile",
+		"build-arg:foo": "bar=baz",
+	})
+	require.Equal(t, []string{
+		"BUILDKIT_FRONTEND_OPT_0=build-arg:foo=bar=baz",
+		"BUILDKIT_FRONTEND_OPT_1=filename=Dockerfile",
+	}, env)
+}
+
+func TestLLBBridgeForwarder(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bridge := &testBridge{config: []byte(`{"config":{}}`)}
+	inputs := map[string][][]byte{"context": {[]byte("foo"), []byte("bar")}}
+	lbf := newLLBBridgeForwarder(ctx, bridge, inputs)
+	defer lbf.release()
+	c := testClient(ctx, t, lbf)
+
+	_, err := c.Ping(ctx, &pb.PingRequest{})
+	require.NoError(t, err)
+
+	inputsResp, err := c.Inputs(ctx, &pb.InputsRequest{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]*pb.Definition{"context": {Def: inputs["context"]}}, inputsResp.Definitions)
+
+	resp, err := c.ResolveImageConfig(ctx, &pb.ResolveImageConfigRequest{Ref: "docker.io/library/busybox:latest"})
+	require.NoError(t, err)
+	require.Equal(t, bridge.config, resp.Config)
+	require.Equal(t, digest.FromBytes(bridge.config), resp.Digest)
+	require.Nil(t, bridge.platform)
+
+	_, err = c.ResolveImageConfig(ctx, &pb.ResolveImageConfigRequest{Ref: "docker.io/library/busybox:latest", Platform: "linux/arm64"})
+	require.NoError(t, err)
+	require.Equal(t, &ocispec.Platform{OS: "linux", Architecture: "arm64"}, bridge.platform)
+
+	_, err = c.ResolveImageConfig(ctx, &pb.ResolveImageConfigRequest{Ref: "docker.io/library/busybox:latest", Platform: "linux/a*"})
+	require.Error(t, err)
+
+	_, err = c.Solve(ctx, &pb.SolveRequest{Final: true, ExporterAttr: map[string][]byte{"foo": []byte("bar")}})
+	require.NoError(t, err)
+	_, err = c.Solve(ctx, &pb.SolveRequest{})
+	require.NoError(t, err)
+
+	ref, exporterAttr, err := lbf.result()
+	require.NoError(t, err)
+	require.Equal(t, bridge.refs[0], ref)
+	require.Equal(t, map[string]interface{}{"foo": []byte("bar")}, exporterAttr)
+
+	lbf.release()
+	require.False(t, bridge.refs[0].(*testRef).released)
+	require.True(t, bridge.refs[1].(*testRef).released)
+}
+
+func TestLLBBridgeForwarderFiles(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tmpdir, err := ioutil.TempDir("", "buildkit-gateway")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpdir, "dir/sub"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tmpdir, "dir/foo"), []byte("foo0123"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tmpdir, "dir/sub/bar"), []byte("bar"), 0600))
+	require.NoError(t, os.Symlink("foo", filepath.Join(tmpdir, "dir/link")))
+
+	bridge := &testBridge{dir: tmpdir}
+	lbf := newLLBBridgeForwarder(ctx, bridge, nil)
+	defer lbf.release()
+	c := testClient(ctx, t, lbf)
+
+	resp, err := c.Solve(ctx, &pb.SolveRequest{})
+	require.NoError(t, err)
+	ref := resp.Ref
+
+	rf, err := c.ReadFile(ctx, &pb.ReadFileRequest{Ref: ref, FilePath: "dir/foo"})
+	require.NoError(t, err)
+	require.Equal(t, "foo0123", string(rf.Data))
+
+	rf, err = c.ReadFile(ctx, &pb.ReadFileRequest{Ref: ref, FilePath: "/dir/link", Range: &pb.FileRange{Offset: 3, Length: 2}})
+	require.NoError(t, err)
+	require.Equal(t, "01", string(rf.Data))
+
+	_, err = c.ReadFile(ctx, &pb.ReadFileRequest{Ref: "nosuchref", FilePath: "dir/foo"})
+	require.Error(t, err)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tmpdir, "large"), make([]byte, maxReadFileSize+1), 0600))
+	_, err = c.ReadFile(ctx, &pb.ReadFileRequest{Ref: ref, FilePath: "large"})
+	require.Error(t, err)
+	rf, err = c.ReadFile(ctx, &pb.ReadFileRequest{Ref: ref, FilePath: "large", Range: &pb.FileRange{Offset: 1}})
+	require.NoError(t, err)
+	require.Equal(t, maxReadFileSize, len(rf.Data))
+
+	rd, err := c.ReadDir(ctx, &pb.ReadDirRequest{Ref: ref, DirPath: "dir"})
+	require.NoError(t, err)
+	var names []string
+	for _, e := range rd.Entries {
+		names = append(names, e.Path)
+	}
+	require.Equal(t, []string{"foo", "link", "sub"}, names)
+
+	rd, err = c.ReadDir(ctx, &pb.ReadDirRequest{Ref: ref, DirPath: "dir", IncludePattern: "f*"})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(rd.Entries))
+	require.Equal(t, "foo", rd.Entries[0].Path)
+
+	st, err := c.StatFile(ctx, &pb.StatFileRequest{Ref: ref, Path: "dir/link"})
+	require.NoError(t, err)
+	require.Equal(t, "dir/link", st.Stat.Path)
+	require.Equal(t, "foo", st.Stat.Linkname)
+	require.True(t, os.FileMode(st.Stat.Mode)&os.ModeSymlink != 0)
+
+	st, err = c.StatFile(ctx, &pb.StatFileRequest{Ref: ref, Path: "dir/sub"})
+	require.NoError(t, err)
+	require.True(t, os.FileMode(st.Stat.Mode).IsDir())
+
+	_, err = c.StatFile(ctx, &pb.StatFileRequest{Ref: ref, Path: "dir/nosuchfile"})
+	require.Error(t, err)
+}
+
+func testClient(ctx context.Context, t *testing.T, lbf *llbBridgeForwarder) pb.LLBBridgeClient {
+	conn := &pipeConn{r: lbf.stdin.(*io.PipeReader), w: lbf.stdout.(*io.PipeWriter)}
+	cc, err := grpc.DialContext(ctx, "", grpc.WithDialer(func(string, time.Duration) (net.Conn, error) {
+		return conn, nil
+	}), grpc.WithInsecure())
+	require.NoError(t, err)
+	return pb.NewLLBBridgeClient(cc)
+}
+
+type testBridge struct {
+	config   []byte
+	platform *ocispec.Platform
+	dir      string
+	refs     []cache.ImmutableRef
+}
+
+func (b *testBridge) Solve(ctx context.Context, def [][]byte) (cache.ImmutableRef, error) {
+	ref := &testRef{dir: b.dir}
+	b.refs = append(b.refs, ref)
+	return ref, nil
+}
+
+func (b *testBridge) ResolveImageConfig(ctx context.Context, ref string, platform *ocispec.Platform) (digest.Digest, []byte, error) {
+	b.platform = platform
+	return digest.FromBytes(b.config), b.config, nil
+}
+
+func (b *testBridge) Exec(ctx context.Context, meta worker.Meta, rootfs cache.ImmutableRef, stdin io.ReadCloser, stdout, stderr io.WriteCloser) error {
+	return nil
+}
+
+type testRef struct {
+	cache.ImmutableRef
+	dir      string
+	released bool
+}
+
+func (r *testRef) Mount(ctx context.Context, readonly bool) ([]mount.Mount, error) {
+	return []mount.Mount{{Type: "bind", Source: r.dir, Options: []string{"rbind"}}}, nil
+}
+
+func (r *testRef) Release(ctx context.Context) error {
+	r.released = true
+	return nil
+}
@@ -0,0 +1,455 @@
+package history
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/moby/buildkit/client"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+const (
+	recordsBucket = "_records"
+	logsBucket    = "_logs"
+
+	defaultMaxRecords = 100
+	defaultMaxLogSize = 4 << 20
+)
+
+// errInterrupted is the error of the records that were not completed when
+// the daemon stopped
+var errInterrupted = errors.New("build was interrupted by a daemon restart")
+
+// Record describes a solve of the daemon
+type Record struct {
+	Ref string
+	// Definition is the digest of the terminal vertex of the LLB definition.
+	// It's empty for frontend builds.
+	Definition    digest.Digest     `json:",omitempty"`
+	Frontend      string            `json:",omitempty"`
+	FrontendAttrs map[string]string `json:",omitempty"`
+	Exporter      string            `json:",omitempty"`
+	ExporterAttrs map[string]string `json:",omitempty"`
+	CreatedAt     time.Time
+	// CompletedAt is nil while the solve is running
+	CompletedAt *time.Time `json:",omitempty"`
+	Error       string     `json:",omitempty"`
+	// Vertexes are the latest states of the vertexes in the order they
+	// were loaded
+	Vertexes []*client.Vertex `json:",omitempty"`
+	// LogSize is the number of bytes of the stored logs
+	LogSize int64
+	// LogsTruncated is set if some logs were dropped because the logs of the
+	// solve were too large
+	LogsTruncated bool `json:",omitempty"`
+	// Summary is set when the solve has completed successfully
+	Summary *client.BuildSummary `json:",omitempty"`
+	// ExporterResponse is the response of the exporter of a successful solve
+	ExporterResponse map[string]string `json:",omitempty"`
+}
+
+// Store persists the records and the logs of the solves. The oldest completed
+// records are removed when there are too many of them.
+type Store struct {
+	db         *bolt.DB
+	mu         sync.Mutex
+	maxRecords int
+	maxLogSize int64
+	// active are the records of the running solves. Their updates are kept
+	// in memory and written with the record when the solve finishes.
+	active map[string]*activeRecord
+}
+
+type activeRecord struct {
+	rec  Record
+	logs []*client.VertexLog
+}
+
+// StoreOpt is an option of a Store
+type StoreOpt func(*Store)
+
+// WithMaxRecords sets the number of records that are kept
+func WithMaxRecords(n int) StoreOpt {
+	return func(s *Store) {
+		s.maxRecords = n
+	}
+}
+
+// WithMaxLogSize sets the number of log bytes that are kept for a solve
+func WithMaxLogSize(n int64) StoreOpt {
+	return func(s *Store) {
+		s.maxLogSize = n
+	}
+}
+
+// NewStore opens the store in the database file dbPath. Records that were not
+// completed are marked as interrupted.
+func NewStore(dbPath string, opts ...StoreOpt) (*Store, error) {
+	db, err := bolt.Open(dbPath, 0600, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open database file %s", dbPath)
+	}
+	s := &Store{db: db, maxRecords: defaultMaxRecords, maxLogSize: defaultMaxLogSize, active: map[string]*activeRecord{}}
+	for _, o := range opts {
+		o(s)
+	}
+	if err := s.interruptRunning(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Close closes the database of the store
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Start saves a new record for a running solve
+func (s *Store) Start(rec Record) error {
+	if rec.Ref == "" {
+		return errors.Errorf("history record without a ref")
+	}
+	rec.CreatedAt = time.Now()
+	rec.CompletedAt = nil
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.active[rec.Ref]; ok {
+		return errors.Errorf("history record %s exists", rec.Ref)
+	}
+	if err := s.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(recordsBucket))
+		if err != nil {
+			return err
+		}
+		if b.Get([]byte(rec.Ref)) != nil {
+			return errors.Errorf("history record %s exists", rec.Ref)
+		}
+		return putRecord(b, &rec)
+	}); err != nil {
+		return err
+	}
+	s.active[rec.Ref] = &activeRecord{rec: rec}
+	return nil
+}
+
+// Update adds the vertexes and the logs of a status update of the running
+// solve ref. The update is not written to the database until Finish.
+func (s *Store) Update(ref string, ss *client.SolveStatus) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.active[ref]
+	if !ok {
+		return errors.Errorf("history record %s is not running", ref)
+	}
+	rec := &a.rec
+	for _, v := range ss.Vertexes {
+		v := *v
+		replaced := false
+		for i, prev := range rec.Vertexes {
+			if prev.Digest == v.Digest {
+				rec.Vertexes[i] = &v
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			rec.Vertexes = append(rec.Vertexes, &v)
+		}
+	}
+	if ss.Summary != nil {
+		rec.Summary = ss.Summary
+	}
+	for _, l := range ss.Logs {
+		if rec.LogSize+int64(len(l.Data)) > s.maxLogSize {
+			rec.LogsTruncated = true
+			continue
+		}
+		a.logs = append(a.logs, l)
+		rec.LogSize += int64(len(l.Data))
+	}
+	return nil
+}
+
+// Finish completes the record of the solve ref with the result of the solve,
+// writes the record with its logs and removes the oldest records over the
+// limit. exporterResponse is the response of the exporter of the solve.
+func (s *Store) Finish(ref string, exporterResponse map[string]string, solveErr error) error {
+	s.mu.Lock()
+	a, ok := s.active[ref]
+	delete(s.active, ref)
+	s.mu.Unlock()
+
+	if err := s.update(ref, func(tx *bolt.Tx, rec *Record) error {
+		if ok {
+			*rec = a.rec
+		}
+		now := time.Now()
+		rec.CompletedAt = &now
+		if solveErr != nil {
+			rec.Error = solveErr.Error()
+		}
+		rec.ExporterResponse = exporterResponse
+		if !ok || len(a.logs) == 0 {
+			return nil
+		}
+		return putLogs(tx, ref, a.logs)
+	}); err != nil {
+		return err
+	}
+	return s.prune()
+}
+
+// Get returns the record of the solve ref
+func (s *Store) Get(ref string) (*Record, error) {
+	if rec, ok := s.getActive(ref); ok {
+		return rec, nil
+	}
+	var rec *Record
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		rec, err = getRecord(tx, ref)
+		return err
+	})
+	return rec, err
+}
+
+// List returns the records from the oldest to the newest
+func (s *Store) List() ([]*Record, error) {
+	var out []*Record
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(recordsBucket))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(_, dt []byte) error {
+			var rec Record
+			if err := json.Unmarshal(dt, &rec); err != nil {
+				return err
+			}
+			out = append(out, &rec)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, rec := range out {
+		if active, ok := s.getActive(rec.Ref); ok {
+			out[i] = active
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].CreatedAt.Before(out[j].CreatedAt)
+	})
+	return out, nil
+}
+
+// Logs calls fn for the stored logs of the solve ref in the order they were
+// written
+func (s *Store) Logs(ref string, fn func(*client.VertexLog) error) error {
+	s.mu.Lock()
+	a, ok := s.active[ref]
+	var logs []*client.VertexLog
+	if ok {
+		logs = append(logs, a.logs...)
+	}
+	s.mu.Unlock()
+	if ok {
+		for _, l := range logs {
+			if err := fn(l); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := s.db.View(func(tx *bolt.Tx) error {
+		if _, err := getRecord(tx, ref); err != nil {
+			return err
+		}
+		b := tx.Bucket([]byte(logsBucket))
+		if b == nil {
+			return nil
+		}
+		b = b.Bucket([]byte(ref))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(_, dt []byte) error {
+			var l client.VertexLog
+			if err := json.Unmarshal(dt, &l); err != nil {
+				return err
+			}
+			logs = append(logs, &l)
+			return nil
+		})
+	}); err != nil {
+		return err
+	}
+	// the callback is called outside of the transaction so that a slow
+	// reader doesn't block the writers
+	for _, l := range logs {
+		if err := fn(l); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete removes the record and the logs of the solve ref
+func (s *Store) Delete(ref string) error {
+	s.mu.Lock()
+	delete(s.active, ref)
+	s.mu.Unlock()
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if _, err := getRecord(tx, ref); err != nil {
+			return err
+		}
+		return deleteRecord(tx, ref)
+	})
+}
+
+// getActive returns a copy of the record of the running solve ref
+func (s *Store) getActive(ref string) (*Record, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.active[ref]
+	if !ok {
+		return nil, false
+	}
+	rec := a.rec
+	rec.Vertexes = append([]*client.Vertex(nil), a.rec.Vertexes...)
+	return &rec, true
+}
+
+func (s *Store) update(ref string, fn func(*bolt.Tx, *Record) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.db.Update(func(tx *bolt.Tx) error {
+		rec, err := getRecord(tx, ref)
+		if err != nil {
+			return err
+		}
+		if err := fn(tx, rec); err != nil {
+			return err
+		}
+		return putRecord(tx.Bucket([]byte(recordsBucket)), rec)
+	})
+}
+
+func (s *Store) prune() error {
+	if s.maxRecords <= 0 {
+		return nil
+	}
+	records, err := s.List()
+	if err != nil {
+		return err
+	}
+	var completed []*Record
+	for _, rec := range records {
+		if rec.CompletedAt != nil {
+			completed = append(completed, rec)
+		}
+	}
+	if len(completed) <= s.maxRecords {
+		return nil
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		for _, rec := range completed[:len(completed)-s.maxRecords] {
+			if err := deleteRecord(tx, rec.Ref); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Store) interruptRunning() error {
+	records, err := s.List()
+	if err != nil {
+		return err
+	}
+	for _, rec := range records {
+		if rec.CompletedAt == nil {
+			if err := s.Finish(rec.Ref, nil, errInterrupted); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func getRecord(tx *bolt.Tx, ref string) (*Record, error) {
+	b := tx.Bucket([]byte(recordsBucket))
+	if b == nil {
+		return nil, errors.Errorf("history record %s not found", ref)
+	}
+	dt := b.Get([]byte(ref))
+	if dt == nil {
+		return nil, errors.Errorf("history record %s not found", ref)
+	}
+	var rec Record
+	if err := json.Unmarshal(dt, &rec); err != nil {
+		return nil, errors.Wrapf(err, "invalid history record %s", ref)
+	}
+	return &rec, nil
+}
+
+func putRecord(b *bolt.Bucket, rec *Record) error {
+	dt, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	return b.Put([]byte(rec.Ref), dt)
+}
+
+func deleteRecord(tx *bolt.Tx, ref string) error {
+	if b := tx.Bucket([]byte(recordsBucket)); b != nil {
+		if err := b.Delete([]byte(ref)); err != nil {
+			return err
+		}
+	}
+	if b := tx.Bucket([]byte(logsBucket)); b != nil && b.Bucket([]byte(ref)) != nil {
+		if err := b.DeleteBucket([]byte(ref)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func putLogs(tx *bolt.Tx, ref string, logs []*client.VertexLog) error {
+	lb, err := tx.CreateBucketIfNotExists([]byte(logsBucket))
+	if err != nil {
+		return err
+	}
+	b, err := lb.CreateBucketIfNotExists([]byte(ref))
+	if err != nil {
+		return err
+	}
+	for _, l := range logs {
+		dt, err := json.Marshal(l)
+		if err != nil {
+			return err
+		}
+		seq, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		if err := b.Put(seqKey(seq), dt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func seqKey(seq uint64) []byte {
+	k := make([]byte, 8)
+	binary.BigEndian.PutUint64(k, seq)
+	return k
+}
@@ -0,0 +1,170 @@
+package history
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/moby/buildkit/client"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "history")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	s, err := NewStore(filepath.Join(tmpdir, "history.db"), WithMaxLogSize(10))
+	require.NoError(t, err)
+
+	err = s.Start(Record{Ref: "foo", Frontend: "dockerfile.v0", FrontendAttrs: map[string]string{"target": "bar"}})
+	require.NoError(t, err)
+	err = s.Start(Record{Ref: "foo"})
+	require.Error(t, err)
+
+	now := time.Now()
+	v1 := digest.FromBytes([]byte("v1"))
+	v2 := digest.FromBytes([]byte("v2"))
+	err = s.Update("foo", &client.SolveStatus{
+		Vertexes: []*client.Vertex{
+			{Digest: v1, Name: "v1", Started: &now},
+			{Digest: v2, Name: "v2"},
+		},
+		Logs: []*client.VertexLog{
+			{Vertex: v1, Stream: client.StreamStdout, Data: []byte("hello\n"), Timestamp: now},
+		},
+	})
+	require.NoError(t, err)
+	err = s.Update("foo", &client.SolveStatus{
+		Vertexes: []*client.Vertex{
+			{Digest: v1, Name: "v1", Started: &now, Completed: &now},
+		},
+		Logs: []*client.VertexLog{
+			{Vertex: v1, Stream: client.StreamStderr, Data: []byte("err\n"), Timestamp: now},
+			{Vertex: v1, Stream: client.StreamStdout, Data: []byte("too long\n"), Timestamp: now},
+		},
+		Summary: &client.BuildSummary{Vertexes: 2, Executed: 1},
+	})
+	require.NoError(t, err)
+
+	rec, err := s.Get("foo")
+	require.NoError(t, err)
+	require.Nil(t, rec.CompletedAt)
+	require.Equal(t, "dockerfile.v0", rec.Frontend)
+	require.Equal(t, "bar", rec.FrontendAttrs["target"])
+	require.Equal(t, 2, len(rec.Vertexes))
+	require.Equal(t, v1, rec.Vertexes[0].Digest)
+	require.NotNil(t, rec.Vertexes[0].Completed)
+	require.Equal(t, v2, rec.Vertexes[1].Digest)
+	require.Equal(t, int64(10), rec.LogSize)
+	require.True(t, rec.LogsTruncated)
+	require.Equal(t, &client.BuildSummary{Vertexes: 2, Executed: 1}, rec.Summary)
+
+	var logs []string
+	err = s.Logs("foo", func(l *client.VertexLog) error {
+		require.Equal(t, v1, l.Vertex)
+		logs = append(logs, string(l.Data))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"hello\n", "err\n"}, logs)
+
+	err = s.Finish("foo", nil, errors.New("failed"))
+	require.NoError(t, err)
+	rec, err = s.Get("foo")
+	require.NoError(t, err)
+	require.NotNil(t, rec.CompletedAt)
+	require.Equal(t, "failed", rec.Error)
+	require.Equal(t, 2, len(rec.Vertexes))
+	require.Equal(t, int64(10), rec.LogSize)
+
+	logs = nil
+	err = s.Logs("foo", func(l *client.VertexLog) error {
+		logs = append(logs, string(l.Data))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"hello\n", "err\n"}, logs)
+
+	_, err = s.Get("bar")
+	require.Error(t, err)
+	err = s.Logs("bar", func(*client.VertexLog) error { return nil })
+	require.Error(t, err)
+
+	err = s.Delete("foo")
+	require.NoError(t, err)
+	_, err = s.Get("foo")
+	require.Error(t, err)
+	require.NoError(t, s.Close())
+}
+
+func TestStorePrune(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "history")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	s, err := NewStore(filepath.Join(tmpdir, "history.db"), WithMaxRecords(2))
+	require.NoError(t, err)
+
+	for _, ref := range []string{"a", "b", "c"} {
+		require.NoError(t, s.Start(Record{Ref: ref}))
+		require.NoError(t, s.Update(ref, &client.SolveStatus{
+			Logs: []*client.VertexLog{{Data: []byte(ref)}},
+		}))
+	}
+	// the running records are kept
+	require.NoError(t, s.Finish("a", nil, nil))
+	require.NoError(t, s.Finish("b", nil, nil))
+
+	records, err := s.List()
+	require.NoError(t, err)
+	require.Equal(t, 3, len(records))
+
+	require.NoError(t, s.Finish("c", map[string]string{"containerimage.digest": "sha256:c"}, nil))
+	records, err = s.List()
+	require.NoError(t, err)
+	require.Equal(t, 2, len(records))
+	require.Equal(t, "b", records[0].Ref)
+	require.Equal(t, "c", records[1].Ref)
+	require.Equal(t, map[string]string{"containerimage.digest": "sha256:c"}, records[1].ExporterResponse)
+
+	err = s.Logs("a", func(*client.VertexLog) error { return nil })
+	require.Error(t, err)
+	require.NoError(t, s.Close())
+}
+
+func TestStoreInterrupted(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "history")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	dbPath := filepath.Join(tmpdir, "history.db")
+	s, err := NewStore(dbPath)
+	require.NoError(t, err)
+	require.NoError(t, s.Start(Record{Ref: "done"}))
+	require.NoError(t, s.Finish("done", nil, nil))
+	require.NoError(t, s.Start(Record{Ref: "running"}))
+	require.NoError(t, s.Update("running", &client.SolveStatus{
+		Logs: []*client.VertexLog{{Data: []byte("running")}},
+	}))
+	require.NoError(t, s.Close())
+
+	s, err = NewStore(dbPath)
+	require.NoError(t, err)
+	defer s.Close()
+
+	rec, err := s.Get("done")
+	require.NoError(t, err)
+	require.Equal(t, "", rec.Error)
+
+	rec, err = s.Get("running")
+	require.NoError(t, err)
+	require.NotNil(t, rec.CompletedAt)
+	require.Equal(t, errInterrupted.Error(), rec.Error)
+	// the updates of the running records are only written when they finish
+	require.Equal(t, int64(0), rec.LogSize)
+}
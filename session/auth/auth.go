@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/moby/buildkit/session"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// CredentialsFunc returns a function that requests the registry credentials
+// of a host from the client session. Empty credentials are returned if the
+// client doesn't forward credentials.
+func CredentialsFunc(ctx context.Context, c session.Caller) func(string) (string, string, error) {
+	return func(host string) (string, string, error) {
+		method := session.MethodURL(_Auth_serviceDesc.ServiceName, "Credentials")
+		if !c.Supports(method) {
+			return "", "", nil
+		}
+		client := NewAuthClient(c.Conn())
+		resp, err := client.Credentials(ctx, &CredentialsRequest{
+			Host: host,
+		})
+		if err != nil {
+			return "", "", errors.Wrapf(err, "failed to get credentials for %s", host)
+		}
+		return resp.Username, resp.Secret, nil
+	}
+}
+
+// SessionCredentials returns a function that requests the registry
+// credentials from the session of the build that is running in ctx. Nil is
+// returned if ctx has no session.
+func SessionCredentials(ctx context.Context, sm *session.Manager) func(string) (string, string, error) {
+	id := session.FromContext(ctx)
+	if id == "" || sm == nil {
+		return nil
+	}
+	return func(host string) (string, string, error) {
+		// the credentials are requested outside of the context of the caller
+		ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
+		defer cancel()
+
+		caller, err := sm.Get(ctx, id)
+		if err != nil {
+			return "", "", err
+		}
+		return CredentialsFunc(ctx, caller)(host)
+	}
+}
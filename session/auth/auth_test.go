@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/testutil"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+)
+
+type testProvider struct {
+	username string
+}
+
+func (p *testProvider) Register(server *grpc.Server) {
+	RegisterAuthServer(server, p)
+}
+
+func (p *testProvider) Credentials(ctx context.Context, req *CredentialsRequest) (*CredentialsResponse, error) {
+	return &CredentialsResponse{Username: p.username, Secret: req.Host}, nil
+}
+
+func TestSessionCredentials(t *testing.T) {
+	m, err := session.NewManager()
+	require.NoError(t, err)
+
+	s1, err := session.NewSession("foo", "bar")
+	require.NoError(t, err)
+	s1.Allow(&testProvider{username: "user1"})
+	s2, err := session.NewSession("foo", "bar")
+	require.NoError(t, err)
+	s2.Allow(&testProvider{username: "user2"})
+
+	// without a session no credentials are requested
+	require.Nil(t, SessionCredentials(context.TODO(), m))
+
+	g, ctx := errgroup.WithContext(context.Background())
+	g.Go(func() error {
+		return s1.Run(ctx, session.Dialer(testutil.TestStream(testutil.Handler(m.HandleConn))))
+	})
+	g.Go(func() error {
+		return s2.Run(ctx, session.Dialer(testutil.TestStream(testutil.Handler(m.HandleConn))))
+	})
+
+	g.Go(func() error {
+		defer s1.Close()
+		defer s2.Close()
+
+		// every build gets the credentials of its own session
+		user, secret, err := SessionCredentials(session.NewContext(ctx, s1.ID()), m)("example.com")
+		if err != nil {
+			return err
+		}
+		require.Equal(t, "user1", user)
+		require.Equal(t, "example.com", secret)
+
+		user, _, err = SessionCredentials(session.NewContext(ctx, s2.ID()), m)("example.com")
+		if err != nil {
+			return err
+		}
+		require.Equal(t, "user2", user)
+		return nil
+	})
+
+	require.NoError(t, g.Wait())
+}
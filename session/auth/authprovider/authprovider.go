@@ -0,0 +1,119 @@
+package authprovider
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/auth"
+	"github.com/moby/buildkit/util/resolver"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+const dockerHubConfigKey = "https://index.docker.io/v1/"
+
+// DockerConfig is the part of the docker CLI config file that stores the
+// registry credentials
+type DockerConfig struct {
+	Auths       map[string]DockerAuth `json:"auths"`
+	CredsStore  string                `json:"credsStore,omitempty"`
+	CredHelpers map[string]string     `json:"credHelpers,omitempty"`
+}
+
+// DockerAuth is a credential stored in the docker CLI config file
+type DockerAuth struct {
+	Auth          string `json:"auth,omitempty"`
+	IdentityToken string `json:"identitytoken,omitempty"`
+}
+
+// LoadDockerConfig reads the config file of the docker CLI from
+// $DOCKER_CONFIG or ~/.docker. An empty config is returned if the file
+// doesn't exist.
+func LoadDockerConfig() (*DockerConfig, error) {
+	dir := os.Getenv("DOCKER_CONFIG")
+	if dir == "" {
+		dir = filepath.Join(os.Getenv("HOME"), ".docker")
+	}
+	p := filepath.Join(dir, "config.json")
+	dt, err := ioutil.ReadFile(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &DockerConfig{}, nil
+		}
+		return nil, errors.Wrapf(err, "failed to read %s", p)
+	}
+	cfg := &DockerConfig{}
+	if err := json.Unmarshal(dt, cfg); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", p)
+	}
+	return cfg, nil
+}
+
+type authProvider struct {
+	config *DockerConfig
+}
+
+// NewDockerAuthProvider creates a session attachable that forwards the
+// registry credentials stored by the docker CLI to the daemon
+func NewDockerAuthProvider(cfg *DockerConfig) session.Attachable {
+	return &authProvider{
+		config: cfg,
+	}
+}
+
+func (ap *authProvider) Register(server *grpc.Server) {
+	auth.RegisterAuthServer(server, ap)
+}
+
+func (ap *authProvider) Credentials(ctx context.Context, req *auth.CredentialsRequest) (*auth.CredentialsResponse, error) {
+	user, secret, err := ap.credentials(req.Host)
+	if err != nil {
+		return nil, err
+	}
+	return &auth.CredentialsResponse{Username: user, Secret: secret}, nil
+}
+
+func (ap *authProvider) credentials(host string) (string, string, error) {
+	key := configKey(host)
+	if helper, ok := ap.config.CredHelpers[key]; ok {
+		return resolver.CredentialHelper(helper)(key)
+	}
+	if a, ok := ap.config.Auths[key]; ok {
+		if a.IdentityToken != "" {
+			return "", a.IdentityToken, nil
+		}
+		if a.Auth != "" {
+			return decodeAuth(a.Auth)
+		}
+	}
+	if ap.config.CredsStore != "" {
+		return resolver.CredentialHelper(ap.config.CredsStore)(key)
+	}
+	return "", "", nil
+}
+
+// configKey returns the key of the host in the docker CLI config
+func configKey(host string) string {
+	if host == "registry-1.docker.io" || host == "docker.io" {
+		return dockerHubConfigKey
+	}
+	return host
+}
+
+func decodeAuth(v string) (string, string, error) {
+	dt, err := base64.StdEncoding.DecodeString(v)
+	if err != nil {
+		return "", "", errors.Wrap(err, "invalid auth")
+	}
+	parts := strings.SplitN(string(dt), ":", 2)
+	if len(parts) != 2 {
+		return "", "", errors.New("invalid auth")
+	}
+	return parts[0], parts[1], nil
+}
@@ -0,0 +1,36 @@
+package authprovider
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredentials(t *testing.T) {
+	ap := &authProvider{config: &DockerConfig{
+		Auths: map[string]DockerAuth{
+			dockerHubConfigKey: {Auth: base64.StdEncoding.EncodeToString([]byte("foo:bar:baz"))},
+			"example.com":      {IdentityToken: "token"},
+			"invalid.com":      {Auth: base64.StdEncoding.EncodeToString([]byte("foo"))},
+		},
+	}}
+
+	user, secret, err := ap.credentials("registry-1.docker.io")
+	require.NoError(t, err)
+	require.Equal(t, "foo", user)
+	require.Equal(t, "bar:baz", secret)
+
+	user, secret, err = ap.credentials("example.com")
+	require.NoError(t, err)
+	require.Equal(t, "", user)
+	require.Equal(t, "token", secret)
+
+	user, secret, err = ap.credentials("other.com")
+	require.NoError(t, err)
+	require.Equal(t, "", user)
+	require.Equal(t, "", secret)
+
+	_, _, err = ap.credentials("invalid.com")
+	require.Error(t, err)
+}
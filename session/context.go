@@ -13,10 +13,20 @@ func NewContext(ctx context.Context, id string) context.Context {
 	return ctx
 }
 
+// NewContextFunc returns a context that resolves the session ID by calling fn
+// every time it is read. This allows work that is shared by multiple callers
+// to switch to another session when the original caller goes away.
+func NewContextFunc(ctx context.Context, fn func() string) context.Context {
+	return context.WithValue(ctx, contextKey, fn)
+}
+
 func FromContext(ctx context.Context) string {
-	v := ctx.Value(contextKey)
-	if v == nil {
+	switch v := ctx.Value(contextKey).(type) {
+	case string:
+		return v
+	case func() string:
+		return v()
+	default:
 		return ""
 	}
-	return v.(string)
 }
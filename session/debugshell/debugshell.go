@@ -0,0 +1,109 @@
+package debugshell
+
+import (
+	"io"
+	"sync"
+
+	"github.com/moby/buildkit/session"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// ExecFunc runs the debug process with the given stdio and returns its exit
+// code
+type ExecFunc func(ctx context.Context, args []string, stdin io.ReadCloser, stdout, stderr io.WriteCloser) (int, error)
+
+// Supported returns true if the client session accepts debug shells for
+// failed execs
+func Supported(c session.Caller) bool {
+	return c.Supports(session.MethodURL(_DebugShell_serviceDesc.ServiceName, "Shell"))
+}
+
+// Run asks the client whether to debug a failed exec and runs the debug
+// process with exec. The stdio of the process is forwarded to the client.
+func Run(ctx context.Context, c session.Caller, req *PrepareRequest, exec ExecFunc) error {
+	client := NewDebugShellClient(c.Conn())
+	resp, err := client.Prepare(ctx, req)
+	if err != nil {
+		return errors.Wrap(err, "failed to prepare debug shell")
+	}
+	if len(resp.Args) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := client.Shell(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to start debug shell")
+	}
+
+	stdinR, stdinW := io.Pipe()
+	recvDone := make(chan struct{})
+	go func() {
+		defer close(recvDone)
+		stdinClosed := false
+		for {
+			m, err := stream.Recv()
+			if err != nil {
+				stdinW.CloseWithError(err)
+				return
+			}
+			if m.Fd != 0 || stdinClosed {
+				continue
+			}
+			if len(m.Data) == 0 {
+				stdinW.Close()
+				stdinClosed = true
+				continue
+			}
+			if _, err := stdinW.Write(m.Data); err != nil {
+				stdinClosed = true
+			}
+		}
+	}()
+
+	var mu sync.Mutex
+	stdout := &streamWriter{stream: stream, mu: &mu, fd: 1}
+	stderr := &streamWriter{stream: stream, mu: &mu, fd: 2}
+
+	exitCode, err := exec(ctx, resp.Args, stdinR, stdout, stderr)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if err := stream.Send(&Message{Exited: true, ExitCode: int32(exitCode)}); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := stream.CloseSend(); err != nil {
+		return errors.WithStack(err)
+	}
+	// wait for the client to finish the stream
+	select {
+	case <-recvDone:
+	case <-ctx.Done():
+	}
+	return nil
+}
+
+type streamWriter struct {
+	stream DebugShell_ShellClient
+	mu     *sync.Mutex
+	fd     int32
+}
+
+func (sw *streamWriter) Write(dt []byte) (int, error) {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	if err := sw.stream.Send(&Message{Fd: sw.fd, Data: append([]byte{}, dt...)}); err != nil {
+		return 0, err
+	}
+	return len(dt), nil
+}
+
+func (sw *streamWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,1178 @@
+// Code generated by protoc-gen-gogo.
+// source: debugshell.proto
+// DO NOT EDIT!
+
+/*
+Package debugshell is a generated protocol buffer package.
+
+It is generated from these files:
+	debugshell.proto
+
+It has these top-level messages:
+	PrepareRequest
+	PrepareResponse
+	Message
+*/
+package debugshell
+
+import proto "github.com/gogo/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import bytes "bytes"
+
+import strings "strings"
+import reflect "reflect"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+import io "io"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
+
+type PrepareRequest struct {
+	// vertex is the digest of the failed vertex
+	Vertex string   `protobuf:"bytes,1,opt,name=vertex,proto3" json:"vertex,omitempty"`
+	Args   []string `protobuf:"bytes,2,rep,name=args" json:"args,omitempty"`
+	Error  string   `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (m *PrepareRequest) Reset()                    { *m = PrepareRequest{} }
+func (*PrepareRequest) ProtoMessage()               {}
+func (*PrepareRequest) Descriptor() ([]byte, []int) { return fileDescriptorDebugshell, []int{0} }
+
+func (m *PrepareRequest) GetVertex() string {
+	if m != nil {
+		return m.Vertex
+	}
+	return ""
+}
+
+func (m *PrepareRequest) GetArgs() []string {
+	if m != nil {
+		return m.Args
+	}
+	return nil
+}
+
+func (m *PrepareRequest) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type PrepareResponse struct {
+	// args of the debug process, no process is started if args are empty
+	Args []string `protobuf:"bytes,1,rep,name=args" json:"args,omitempty"`
+}
+
+func (m *PrepareResponse) Reset()                    { *m = PrepareResponse{} }
+func (*PrepareResponse) ProtoMessage()               {}
+func (*PrepareResponse) Descriptor() ([]byte, []int) { return fileDescriptorDebugshell, []int{1} }
+
+func (m *PrepareResponse) GetArgs() []string {
+	if m != nil {
+		return m.Args
+	}
+	return nil
+}
+
+// Message carries stdio data of the debug process. An empty stdin message
+// closes stdin of the process. The daemon sends the exit code in the last
+// message.
+type Message struct {
+	// fd is 0 for stdin, 1 for stdout and 2 for stderr
+	Fd       int32  `protobuf:"varint,1,opt,name=fd,proto3" json:"fd,omitempty"`
+	Data     []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	Exited   bool   `protobuf:"varint,3,opt,name=exited,proto3" json:"exited,omitempty"`
+	ExitCode int32  `protobuf:"varint,4,opt,name=exitCode,proto3" json:"exitCode,omitempty"`
+}
+
+func (m *Message) Reset()                    { *m = Message{} }
+func (*Message) ProtoMessage()               {}
+func (*Message) Descriptor() ([]byte, []int) { return fileDescriptorDebugshell, []int{2} }
+
+func (m *Message) GetFd() int32 {
+	if m != nil {
+		return m.Fd
+	}
+	return 0
+}
+
+func (m *Message) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *Message) GetExited() bool {
+	if m != nil {
+		return m.Exited
+	}
+	return false
+}
+
+func (m *Message) GetExitCode() int32 {
+	if m != nil {
+		return m.ExitCode
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*PrepareRequest)(nil), "moby.buildkit.debugshell.v1.PrepareRequest")
+	proto.RegisterType((*PrepareResponse)(nil), "moby.buildkit.debugshell.v1.PrepareResponse")
+	proto.RegisterType((*Message)(nil), "moby.buildkit.debugshell.v1.Message")
+}
+func (this *PrepareRequest) Equal(that interface{}) bool {
+	if that == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	}
+
+	that1, ok := that.(*PrepareRequest)
+	if !ok {
+		that2, ok := that.(PrepareRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	} else if this == nil {
+		return false
+	}
+	if this.Vertex != that1.Vertex {
+		return false
+	}
+	if len(this.Args) != len(that1.Args) {
+		return false
+	}
+	for i := range this.Args {
+		if this.Args[i] != that1.Args[i] {
+			return false
+		}
+	}
+	if this.Error != that1.Error {
+		return false
+	}
+	return true
+}
+func (this *PrepareResponse) Equal(that interface{}) bool {
+	if that == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	}
+
+	that1, ok := that.(*PrepareResponse)
+	if !ok {
+		that2, ok := that.(PrepareResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	} else if this == nil {
+		return false
+	}
+	if len(this.Args) != len(that1.Args) {
+		return false
+	}
+	for i := range this.Args {
+		if this.Args[i] != that1.Args[i] {
+			return false
+		}
+	}
+	return true
+}
+func (this *Message) Equal(that interface{}) bool {
+	if that == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	}
+
+	that1, ok := that.(*Message)
+	if !ok {
+		that2, ok := that.(Message)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return true
+		}
+		return false
+	} else if this == nil {
+		return false
+	}
+	if this.Fd != that1.Fd {
+		return false
+	}
+	if !bytes.Equal(this.Data, that1.Data) {
+		return false
+	}
+	if this.Exited != that1.Exited {
+		return false
+	}
+	if this.ExitCode != that1.ExitCode {
+		return false
+	}
+	return true
+}
+func (this *PrepareRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&debugshell.PrepareRequest{")
+	s = append(s, "Vertex: "+fmt.Sprintf("%#v", this.Vertex)+",\n")
+	s = append(s, "Args: "+fmt.Sprintf("%#v", this.Args)+",\n")
+	s = append(s, "Error: "+fmt.Sprintf("%#v", this.Error)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *PrepareResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&debugshell.PrepareResponse{")
+	s = append(s, "Args: "+fmt.Sprintf("%#v", this.Args)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Message) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&debugshell.Message{")
+	s = append(s, "Fd: "+fmt.Sprintf("%#v", this.Fd)+",\n")
+	s = append(s, "Data: "+fmt.Sprintf("%#v", this.Data)+",\n")
+	s = append(s, "Exited: "+fmt.Sprintf("%#v", this.Exited)+",\n")
+	s = append(s, "ExitCode: "+fmt.Sprintf("%#v", this.ExitCode)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func valueToGoStringDebugshell(v interface{}, typ string) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("func(v %v) *%v { return &v } ( %#v )", typ, typ, pv)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for DebugShell service
+
+type DebugShellClient interface {
+	Prepare(ctx context.Context, in *PrepareRequest, opts ...grpc.CallOption) (*PrepareResponse, error)
+	Shell(ctx context.Context, opts ...grpc.CallOption) (DebugShell_ShellClient, error)
+}
+
+type debugShellClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewDebugShellClient(cc *grpc.ClientConn) DebugShellClient {
+	return &debugShellClient{cc}
+}
+
+func (c *debugShellClient) Prepare(ctx context.Context, in *PrepareRequest, opts ...grpc.CallOption) (*PrepareResponse, error) {
+	out := new(PrepareResponse)
+	err := grpc.Invoke(ctx, "/moby.buildkit.debugshell.v1.DebugShell/Prepare", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *debugShellClient) Shell(ctx context.Context, opts ...grpc.CallOption) (DebugShell_ShellClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_DebugShell_serviceDesc.Streams[0], c.cc, "/moby.buildkit.debugshell.v1.DebugShell/Shell", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &debugShellShellClient{stream}
+	return x, nil
+}
+
+type DebugShell_ShellClient interface {
+	Send(*Message) error
+	Recv() (*Message, error)
+	grpc.ClientStream
+}
+
+type debugShellShellClient struct {
+	grpc.ClientStream
+}
+
+func (x *debugShellShellClient) Send(m *Message) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *debugShellShellClient) Recv() (*Message, error) {
+	m := new(Message)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Server API for DebugShell service
+
+type DebugShellServer interface {
+	Prepare(context.Context, *PrepareRequest) (*PrepareResponse, error)
+	Shell(DebugShell_ShellServer) error
+}
+
+func RegisterDebugShellServer(s *grpc.Server, srv DebugShellServer) {
+	s.RegisterService(&_DebugShell_serviceDesc, srv)
+}
+
+func _DebugShell_Prepare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PrepareRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DebugShellServer).Prepare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/moby.buildkit.debugshell.v1.DebugShell/Prepare",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DebugShellServer).Prepare(ctx, req.(*PrepareRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DebugShell_Shell_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DebugShellServer).Shell(&debugShellShellServer{stream})
+}
+
+type DebugShell_ShellServer interface {
+	Send(*Message) error
+	Recv() (*Message, error)
+	grpc.ServerStream
+}
+
+type debugShellShellServer struct {
+	grpc.ServerStream
+}
+
+func (x *debugShellShellServer) Send(m *Message) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *debugShellShellServer) Recv() (*Message, error) {
+	m := new(Message)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _DebugShell_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "moby.buildkit.debugshell.v1.DebugShell",
+	HandlerType: (*DebugShellServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Prepare",
+			Handler:    _DebugShell_Prepare_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Shell",
+			Handler:       _DebugShell_Shell_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "debugshell.proto",
+}
+
+func (m *PrepareRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PrepareRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Vertex) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintDebugshell(dAtA, i, uint64(len(m.Vertex)))
+		i += copy(dAtA[i:], m.Vertex)
+	}
+	if len(m.Args) > 0 {
+		for _, s := range m.Args {
+			dAtA[i] = 0x12
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if len(m.Error) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintDebugshell(dAtA, i, uint64(len(m.Error)))
+		i += copy(dAtA[i:], m.Error)
+	}
+	return i, nil
+}
+
+func (m *PrepareResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PrepareResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Args) > 0 {
+		for _, s := range m.Args {
+			dAtA[i] = 0xa
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	return i, nil
+}
+
+func (m *Message) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Message) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Fd != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintDebugshell(dAtA, i, uint64(m.Fd))
+	}
+	if len(m.Data) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintDebugshell(dAtA, i, uint64(len(m.Data)))
+		i += copy(dAtA[i:], m.Data)
+	}
+	if m.Exited {
+		dAtA[i] = 0x18
+		i++
+		if m.Exited {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.ExitCode != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintDebugshell(dAtA, i, uint64(m.ExitCode))
+	}
+	return i, nil
+}
+
+func encodeFixed64Debugshell(dAtA []byte, offset int, v uint64) int {
+	dAtA[offset] = uint8(v)
+	dAtA[offset+1] = uint8(v >> 8)
+	dAtA[offset+2] = uint8(v >> 16)
+	dAtA[offset+3] = uint8(v >> 24)
+	dAtA[offset+4] = uint8(v >> 32)
+	dAtA[offset+5] = uint8(v >> 40)
+	dAtA[offset+6] = uint8(v >> 48)
+	dAtA[offset+7] = uint8(v >> 56)
+	return offset + 8
+}
+func encodeFixed32Debugshell(dAtA []byte, offset int, v uint32) int {
+	dAtA[offset] = uint8(v)
+	dAtA[offset+1] = uint8(v >> 8)
+	dAtA[offset+2] = uint8(v >> 16)
+	dAtA[offset+3] = uint8(v >> 24)
+	return offset + 4
+}
+func encodeVarintDebugshell(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *PrepareRequest) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Vertex)
+	if l > 0 {
+		n += 1 + l + sovDebugshell(uint64(l))
+	}
+	if len(m.Args) > 0 {
+		for _, s := range m.Args {
+			l = len(s)
+			n += 1 + l + sovDebugshell(uint64(l))
+		}
+	}
+	l = len(m.Error)
+	if l > 0 {
+		n += 1 + l + sovDebugshell(uint64(l))
+	}
+	return n
+}
+
+func (m *PrepareResponse) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Args) > 0 {
+		for _, s := range m.Args {
+			l = len(s)
+			n += 1 + l + sovDebugshell(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Message) Size() (n int) {
+	var l int
+	_ = l
+	if m.Fd != 0 {
+		n += 1 + sovDebugshell(uint64(m.Fd))
+	}
+	l = len(m.Data)
+	if l > 0 {
+		n += 1 + l + sovDebugshell(uint64(l))
+	}
+	if m.Exited {
+		n += 2
+	}
+	if m.ExitCode != 0 {
+		n += 1 + sovDebugshell(uint64(m.ExitCode))
+	}
+	return n
+}
+
+func sovDebugshell(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozDebugshell(x uint64) (n int) {
+	return sovDebugshell(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (this *PrepareRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&PrepareRequest{`,
+		`Vertex:` + fmt.Sprintf("%v", this.Vertex) + `,`,
+		`Args:` + fmt.Sprintf("%v", this.Args) + `,`,
+		`Error:` + fmt.Sprintf("%v", this.Error) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *PrepareResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&PrepareResponse{`,
+		`Args:` + fmt.Sprintf("%v", this.Args) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Message) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Message{`,
+		`Fd:` + fmt.Sprintf("%v", this.Fd) + `,`,
+		`Data:` + fmt.Sprintf("%v", this.Data) + `,`,
+		`Exited:` + fmt.Sprintf("%v", this.Exited) + `,`,
+		`ExitCode:` + fmt.Sprintf("%v", this.ExitCode) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func valueToStringDebugshell(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("*%v", pv)
+}
+func (m *PrepareRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDebugshell
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PrepareRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PrepareRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Vertex", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDebugshell
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDebugshell
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Vertex = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Args", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDebugshell
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDebugshell
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Args = append(m.Args, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Error", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDebugshell
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDebugshell
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Error = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDebugshell(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDebugshell
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PrepareResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDebugshell
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PrepareResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PrepareResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Args", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDebugshell
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDebugshell
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Args = append(m.Args, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDebugshell(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDebugshell
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Message) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDebugshell
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Message: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Message: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Fd", wireType)
+			}
+			m.Fd = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDebugshell
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Fd |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDebugshell
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDebugshell
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Data = append(m.Data[:0], dAtA[iNdEx:postIndex]...)
+			if m.Data == nil {
+				m.Data = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Exited", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDebugshell
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Exited = bool(v != 0)
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExitCode", wireType)
+			}
+			m.ExitCode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDebugshell
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ExitCode |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDebugshell(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDebugshell
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipDebugshell(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowDebugshell
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowDebugshell
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+			return iNdEx, nil
+		case 1:
+			iNdEx += 8
+			return iNdEx, nil
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowDebugshell
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			iNdEx += length
+			if length < 0 {
+				return 0, ErrInvalidLengthDebugshell
+			}
+			return iNdEx, nil
+		case 3:
+			for {
+				var innerWire uint64
+				var start int = iNdEx
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return 0, ErrIntOverflowDebugshell
+					}
+					if iNdEx >= l {
+						return 0, io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					innerWire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				innerWireType := int(innerWire & 0x7)
+				if innerWireType == 4 {
+					break
+				}
+				next, err := skipDebugshell(dAtA[start:])
+				if err != nil {
+					return 0, err
+				}
+				iNdEx = start + next
+			}
+			return iNdEx, nil
+		case 4:
+			return iNdEx, nil
+		case 5:
+			iNdEx += 4
+			return iNdEx, nil
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+	}
+	panic("unreachable")
+}
+
+var (
+	ErrInvalidLengthDebugshell = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowDebugshell   = fmt.Errorf("proto: integer overflow")
+)
+
+func init() { proto.RegisterFile("debugshell.proto", fileDescriptorDebugshell) }
+
+var fileDescriptorDebugshell = []byte{
+	// 318 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x52, 0xbb, 0x4a, 0x33, 0x41,
+	0x14, 0xde, 0xb3, 0xb9, 0x1f, 0x7e, 0xf2, 0xcb, 0x20, 0xb2, 0x44, 0x18, 0x42, 0x50, 0x58, 0x50,
+	0x16, 0x2f, 0x8d, 0xb5, 0xda, 0x0a, 0x32, 0x16, 0x82, 0xdd, 0x2e, 0x73, 0x12, 0x83, 0xd1, 0x8d,
+	0x33, 0x93, 0x10, 0x3b, 0x1f, 0xc1, 0xc7, 0xf0, 0x51, 0x04, 0x9b, 0x94, 0x96, 0x66, 0x6c, 0x2c,
+	0xf3, 0x08, 0xb2, 0x93, 0x65, 0xa3, 0x4d, 0x48, 0x77, 0x3e, 0xe6, 0xbb, 0x9c, 0xef, 0x30, 0xb8,
+	0x21, 0x29, 0x19, 0xf5, 0xf4, 0x2d, 0x0d, 0x06, 0xd1, 0x50, 0xa5, 0x26, 0x65, 0xdb, 0xf7, 0x69,
+	0xf2, 0x14, 0x25, 0xa3, 0xfe, 0x40, 0xde, 0xf5, 0x4d, 0xf4, 0xeb, 0x7d, 0x7c, 0xd8, 0x11, 0xd8,
+	0xbc, 0x54, 0x34, 0x8c, 0x15, 0x09, 0x7a, 0x1c, 0x91, 0x36, 0x6c, 0x0b, 0xab, 0x63, 0x52, 0x86,
+	0x26, 0x01, 0xb4, 0x21, 0x6c, 0x88, 0x1c, 0x31, 0x86, 0xe5, 0x58, 0xf5, 0x74, 0xe0, 0xb7, 0x4b,
+	0x61, 0x43, 0xb8, 0x99, 0x6d, 0x62, 0x85, 0x94, 0x4a, 0x55, 0x50, 0x72, 0xd4, 0x05, 0xe8, 0xec,
+	0xe2, 0xff, 0xc2, 0x53, 0x0f, 0xd3, 0x07, 0x4d, 0x85, 0x18, 0x96, 0xe2, 0x4e, 0x8c, 0xb5, 0x0b,
+	0xd2, 0x3a, 0xee, 0x11, 0x6b, 0xa2, 0xdf, 0x95, 0x2e, 0xaf, 0x22, 0xfc, 0xae, 0xcc, 0xe8, 0x32,
+	0x36, 0x71, 0xe0, 0xb7, 0x21, 0xfc, 0x27, 0xdc, 0x9c, 0xed, 0x45, 0x93, 0xbe, 0x21, 0xe9, 0xc2,
+	0xea, 0x22, 0x47, 0xac, 0x85, 0xf5, 0x6c, 0x3a, 0x4b, 0x25, 0x05, 0x65, 0xe7, 0x50, 0xe0, 0xa3,
+	0x77, 0x40, 0x3c, 0xcf, 0xfa, 0x5e, 0x65, 0x7d, 0x99, 0xc4, 0x5a, 0xbe, 0x18, 0xdb, 0x8b, 0x56,
+	0x5c, 0x25, 0xfa, 0x7b, 0x92, 0xd6, 0xfe, 0x7a, 0xe4, 0xbc, 0xeb, 0x35, 0x56, 0x16, 0x71, 0x3b,
+	0x2b, 0x65, 0x79, 0xf7, 0xd6, 0x5a, 0xac, 0x10, 0x0e, 0xe0, 0xf4, 0x64, 0x3a, 0xe3, 0xde, 0xc7,
+	0x8c, 0x7b, 0xf3, 0x19, 0x87, 0x67, 0xcb, 0xe1, 0xd5, 0x72, 0x78, 0xb3, 0x1c, 0xa6, 0x96, 0xc3,
+	0xa7, 0xe5, 0xf0, 0x6d, 0xb9, 0x37, 0xb7, 0x1c, 0x5e, 0xbe, 0xb8, 0x77, 0x83, 0x4b, 0xa3, 0xa4,
+	0xea, 0x7e, 0xc2, 0xf1, 0xcf, 0x00, 0xa4, 0xf9, 0x46, 0x30, 0x1d, 0x02, 0x00, 0x00,
+}
@@ -0,0 +1,69 @@
+package debugshell
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/testutil"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+	"golang.org/x/sync/errgroup"
+)
+
+func TestDebugShell(t *testing.T) {
+	s, err := session.NewSession("foo", "bar")
+	require.NoError(t, err)
+
+	m, err := session.NewManager()
+	require.NoError(t, err)
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	s.Allow(NewShellProvider([]string{"sh"}, strings.NewReader("echo hi\n"), stdout, stderr))
+
+	dialer := session.Dialer(testutil.TestStream(testutil.Handler(m.HandleConn)))
+
+	g, ctx := errgroup.WithContext(context.Background())
+
+	g.Go(func() error {
+		return s.Run(ctx, dialer)
+	})
+
+	g.Go(func() error {
+		c, err := m.Get(ctx, s.ID())
+		if err != nil {
+			return err
+		}
+		require.True(t, Supported(c))
+
+		var stdin []byte
+		var args []string
+		err = Run(ctx, c, &PrepareRequest{Args: []string{"false"}, Error: "exit code 1"}, func(ctx context.Context, a []string, in io.ReadCloser, out, errOut io.WriteCloser) (int, error) {
+			args = a
+			dt, err := ioutil.ReadAll(in)
+			if err != nil {
+				return 0, err
+			}
+			stdin = dt
+			out.Write([]byte("hello"))
+			return 3, nil
+		})
+		if err != nil {
+			return err
+		}
+		require.Equal(t, []string{"sh"}, args)
+		require.Equal(t, "echo hi\n", string(stdin))
+		return s.Close()
+	})
+
+	err = g.Wait()
+	require.NoError(t, err)
+
+	require.Equal(t, "hello", stdout.String())
+	require.Contains(t, stderr.String(), "starting debug shell [sh]")
+	require.Contains(t, stderr.String(), "exited with code 3")
+}
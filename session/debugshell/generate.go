@@ -0,0 +1,3 @@
+package debugshell
+
+//go:generate protoc --gogoslick_out=plugins=grpc:. debugshell.proto
@@ -0,0 +1,97 @@
+package debugshell
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/moby/buildkit/session"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+type shellProvider struct {
+	args   []string
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	mu     sync.Mutex
+}
+
+// NewShellProvider creates a session attachable that runs args inside the
+// rootfs and mounts of execs that fail during the build. The stdio of the
+// process is connected to stdin, stdout and stderr.
+func NewShellProvider(args []string, stdin io.Reader, stdout, stderr io.Writer) session.Attachable {
+	return &shellProvider{
+		args:   args,
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+	}
+}
+
+func (sp *shellProvider) Register(server *grpc.Server) {
+	RegisterDebugShellServer(server, sp)
+}
+
+func (sp *shellProvider) Prepare(ctx context.Context, req *PrepareRequest) (*PrepareResponse, error) {
+	fmt.Fprintf(sp.stderr, "%v failed: %s\nstarting debug shell %v\n", req.Args, req.Error, sp.args)
+	return &PrepareResponse{Args: sp.args}, nil
+}
+
+func (sp *shellProvider) Shell(stream DebugShell_ShellServer) error {
+	// only one shell can use the terminal at a time
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	done := make(chan struct{})
+	defer close(done)
+
+	var sendMu sync.Mutex
+	go func() {
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := sp.stdin.Read(buf)
+			select {
+			case <-done:
+				// the data belongs to the next reader of stdin
+				return
+			default:
+			}
+			sendMu.Lock()
+			if n > 0 {
+				if err := stream.Send(&Message{Fd: 0, Data: append([]byte{}, buf[:n]...)}); err != nil {
+					sendMu.Unlock()
+					return
+				}
+			}
+			if err != nil {
+				stream.Send(&Message{Fd: 0})
+				sendMu.Unlock()
+				return
+			}
+			sendMu.Unlock()
+		}
+	}()
+
+	for {
+		m, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return errors.WithStack(err)
+		}
+		if m.Exited {
+			fmt.Fprintf(sp.stderr, "debug shell exited with code %d\n", m.ExitCode)
+			return nil
+		}
+		switch m.Fd {
+		case 1:
+			sp.stdout.Write(m.Data)
+		case 2:
+			sp.stderr.Write(m.Data)
+		}
+	}
+}
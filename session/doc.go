@@ -0,0 +1,23 @@
+// Package session implements the long running connection between a client
+// and the daemon that the daemon uses to call back to the client during a
+// build.
+//
+// The client creates a Session, allows the attachables it provides, eg. the
+// local directories of filesync, the registry credentials of auth, secrets
+// and sshforward, and runs it with a Dialer, eg. grpchijack.Dialer for the
+// control API. The attachables are gRPC services that are served over the
+// single connection of the session.
+//
+//	s, err := session.NewSession("myproject", sharedKey)
+//	if err != nil {
+//		return err
+//	}
+//	s.Allow(secretsprovider.NewSecretProvider(store))
+//	go s.Run(ctx, grpchijack.Dialer(controlClient))
+//	defer s.Close()
+//
+// The daemon accepts the connections with a Manager and gets the Caller for
+// the session ID of a build request from it. The sources and exporters call
+// the methods of the attachables through the Caller, after checking with
+// Supports that the client allowed the attachable.
+package session
@@ -1,9 +1,11 @@
 package filesync
 
 import (
+	"io"
 	"os"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/tonistiigi/fsutil"
 	"google.golang.org/grpc"
@@ -35,12 +37,14 @@ func recvDiffCopy(ds grpc.Stream, dest string, cu CacheUpdater, progress progres
 	})
 }
 
-func syncTargetDiffCopy(ds grpc.Stream, dest string) error {
+func syncTargetDiffCopy(ds grpc.Stream, dest string, incremental bool) error {
 	if err := os.MkdirAll(dest, 0700); err != nil {
 		return err
 	}
 	return fsutil.Receive(ds.Context(), ds, dest, fsutil.ReceiveOpt{
-		Merge: true,
+		// without merge the files already in dest are compared with the
+		// sent ones and only the changed files are transferred
+		Merge: !incremental,
 		Filter: func() func(*fsutil.Stat) bool {
 			uid := os.Getuid()
 			gid := os.Getgid()
@@ -52,3 +56,57 @@ func syncTargetDiffCopy(ds grpc.Stream, dest string) error {
 		}(),
 	})
 }
+
+func writeTargetFile(ds grpc.Stream, w io.Writer) error {
+	for {
+		bm := BytesMessage{}
+		if err := ds.RecvMsg(&bm); err != nil {
+			if errors.Cause(err) == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if _, err := w.Write(bm.Data); err != nil {
+			return err
+		}
+	}
+}
+
+// maxChunkSize keeps the messages below the default grpc message size limit
+const maxChunkSize = 32 * 1024
+
+type streamWriterCloser struct {
+	grpc.ClientStream
+}
+
+func newStreamWriter(cs grpc.ClientStream) io.WriteCloser {
+	return &streamWriterCloser{ClientStream: cs}
+}
+
+func (wc *streamWriterCloser) Write(dt []byte) (int, error) {
+	var n int
+	for len(dt) > 0 {
+		chunk := dt
+		if len(chunk) > maxChunkSize {
+			chunk = chunk[:maxChunkSize]
+		}
+		if err := wc.ClientStream.SendMsg(&BytesMessage{Data: chunk}); err != nil {
+			return n, err
+		}
+		n += len(chunk)
+		dt = dt[len(chunk):]
+	}
+	return n, nil
+}
+
+func (wc *streamWriterCloser) Close() error {
+	if err := wc.ClientStream.CloseSend(); err != nil {
+		return err
+	}
+	// wait for the target to finish writing
+	var bm BytesMessage
+	if err := wc.ClientStream.RecvMsg(&bm); err != nil && errors.Cause(err) != io.EOF {
+		return err
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package filesync
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -16,7 +17,9 @@ import (
 const (
 	keyOverrideExcludes = "override-excludes"
 	keyIncludePatterns  = "include-patterns"
+	keyExcludePatterns  = "exclude-patterns"
 	keyDirName          = "dir-name"
+	keyTargetName       = "target-name"
 )
 
 type fsSyncProvider struct {
@@ -81,6 +84,7 @@ func (sp *fsSyncProvider) handle(method string, stream grpc.ServerStream) error
 	if len(opts[keyOverrideExcludes]) == 0 || opts[keyOverrideExcludes][0] != "true" {
 		excludes = dir.Excludes
 	}
+	excludes = append(append([]string{}, excludes...), opts[keyExcludePatterns]...)
 	includes := opts[keyIncludePatterns]
 
 	var progress progressCb
@@ -137,6 +141,7 @@ var supportedProtocols = []protocol{
 type FSSendRequestOpt struct {
 	Name             string
 	IncludePatterns  []string
+	ExcludePatterns  []string
 	OverrideExcludes bool
 	DestDir          string
 	CacheUpdater     CacheUpdater
@@ -172,6 +177,10 @@ func FSSync(ctx context.Context, c session.Caller, opt FSSendRequestOpt) error {
 		opts[keyIncludePatterns] = opt.IncludePatterns
 	}
 
+	if opt.ExcludePatterns != nil {
+		opts[keyExcludePatterns] = opt.ExcludePatterns
+	}
+
 	opts[keyDirName] = []string{opt.Name}
 
 	ctx, cancel := context.WithCancel(ctx)
@@ -205,14 +214,48 @@ func FSSync(ctx context.Context, c session.Caller, opt FSSendRequestOpt) error {
 
 // NewFSSyncTarget allows writing into a directory
 func NewFSSyncTarget(outdir string) session.Attachable {
+	return NewFSSyncTargets([]FSSyncTarget{{OutDir: outdir}})
+}
+
+// NewFSSyncTargetIncremental allows writing into a directory that is kept in
+// sync with the sent files. Only the files that have changed are
+// transferred and the files that were not sent are removed from outdir.
+func NewFSSyncTargetIncremental(outdir string) session.Attachable {
+	return NewFSSyncTargets([]FSSyncTarget{{OutDir: outdir, Incremental: true}})
+}
+
+// NewFSSyncTargetFile allows writing a single stream of data, like a
+// tarball, into w
+func NewFSSyncTargetFile(w io.Writer) session.Attachable {
+	return NewFSSyncTargets([]FSSyncTarget{{Writer: w}})
+}
+
+// FSSyncTarget is a destination for the files sent by an exporter. The
+// exporter selects the target by its name.
+type FSSyncTarget struct {
+	Name string
+	// OutDir is the directory the files are written to
+	OutDir string
+	// Incremental keeps OutDir in sync with the sent files, see
+	// NewFSSyncTargetIncremental
+	Incremental bool
+	// Writer receives a single stream of data instead of the files if set
+	Writer io.Writer
+}
+
+// NewFSSyncTargets allows writing into multiple targets
+func NewFSSyncTargets(targets []FSSyncTarget) session.Attachable {
 	p := &fsSyncTarget{
-		outdir: outdir,
+		targets: make(map[string]FSSyncTarget, len(targets)),
+	}
+	for _, t := range targets {
+		p.targets[t.Name] = t
 	}
 	return p
 }
 
 type fsSyncTarget struct {
-	outdir string
+	targets map[string]FSSyncTarget
 }
 
 func (sp *fsSyncTarget) Register(server *grpc.Server) {
@@ -220,10 +263,24 @@ func (sp *fsSyncTarget) Register(server *grpc.Server) {
 }
 
 func (sp *fsSyncTarget) DiffCopy(stream FileSend_DiffCopyServer) error {
-	return syncTargetDiffCopy(stream, sp.outdir)
+	opts, _ := metadata.FromContext(stream.Context()) // if no metadata continue with empty object
+
+	var name string
+	if v := opts[keyTargetName]; len(v) == 1 {
+		name = v[0]
+	}
+	t, ok := sp.targets[name]
+	if !ok {
+		return errors.Errorf("no target %q", name)
+	}
+	if t.Writer != nil {
+		return writeTargetFile(stream, t.Writer)
+	}
+	return syncTargetDiffCopy(stream, t.OutDir, t.Incremental)
 }
 
-func CopyToCaller(ctx context.Context, srcPath string, c session.Caller, progress func(int, bool)) error {
+// CopyToCaller sends the files of srcPath to the target of the caller
+func CopyToCaller(ctx context.Context, srcPath string, c session.Caller, target string, progress func(int, bool)) error {
 	method := session.MethodURL(_FileSend_serviceDesc.ServiceName, "diffcopy")
 	if !c.Supports(method) {
 		return errors.Errorf("method %s not supported by the client", method)
@@ -231,6 +288,7 @@ func CopyToCaller(ctx context.Context, srcPath string, c session.Caller, progres
 
 	client := NewFileSendClient(c.Conn())
 
+	ctx = metadata.NewContext(ctx, map[string][]string{keyTargetName: {target}})
 	cc, err := client.DiffCopy(ctx)
 	if err != nil {
 		return err
@@ -238,3 +296,22 @@ func CopyToCaller(ctx context.Context, srcPath string, c session.Caller, progres
 
 	return sendDiffCopy(cc, srcPath, nil, nil, progress)
 }
+
+// CopyFileWriter returns a writer that streams the written data to the
+// target file of the caller. The stream is finished with Close.
+func CopyFileWriter(ctx context.Context, c session.Caller, target string) (io.WriteCloser, error) {
+	method := session.MethodURL(_FileSend_serviceDesc.ServiceName, "diffcopy")
+	if !c.Supports(method) {
+		return nil, errors.Errorf("method %s not supported by the client", method)
+	}
+
+	client := NewFileSendClient(c.Conn())
+
+	ctx = metadata.NewContext(ctx, map[string][]string{keyTargetName: {target}})
+	cc, err := client.DiffCopy(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return newStreamWriter(cc), nil
+}
@@ -1,8 +1,10 @@
 package filesync
 
 import (
+	"bytes"
 	"context"
 	"io/ioutil"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -70,3 +72,250 @@ func TestFileSyncIncludePatterns(t *testing.T) {
 	err = g.Wait()
 	require.NoError(t, err)
 }
+
+func TestFileSyncExcludePatterns(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "fsynctest")
+	require.NoError(t, err)
+
+	destDir, err := ioutil.TempDir("", "fsynctest")
+	require.NoError(t, err)
+
+	for _, f := range []string{"foo", "bar", "baz"} {
+		err = ioutil.WriteFile(filepath.Join(tmpDir, f), []byte("content-"+f), 0600)
+		require.NoError(t, err)
+	}
+
+	s, err := session.NewSession("foo", "bar")
+	require.NoError(t, err)
+
+	m, err := session.NewManager()
+	require.NoError(t, err)
+
+	fs := NewFSSyncProvider([]SyncedDir{{Name: "test0", Dir: tmpDir, Excludes: []string{"foo"}}})
+	s.Allow(fs)
+
+	dialer := session.Dialer(testutil.TestStream(testutil.Handler(m.HandleConn)))
+
+	g, ctx := errgroup.WithContext(context.Background())
+
+	g.Go(func() error {
+		return s.Run(ctx, dialer)
+	})
+
+	g.Go(func() (reterr error) {
+		c, err := m.Get(ctx, s.ID())
+		if err != nil {
+			return err
+		}
+		if err := FSSync(ctx, c, FSSendRequestOpt{
+			Name:            "test0",
+			DestDir:         destDir,
+			ExcludePatterns: []string{"baz"},
+		}); err != nil {
+			return err
+		}
+
+		for _, f := range []string{"foo", "baz"} {
+			_, err = ioutil.ReadFile(filepath.Join(destDir, f))
+			assert.Error(t, err)
+		}
+
+		dt, err := ioutil.ReadFile(filepath.Join(destDir, "bar"))
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, "content-bar", string(dt))
+		return s.Close()
+	})
+
+	err = g.Wait()
+	require.NoError(t, err)
+}
+
+func TestFileSyncTargetIncremental(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "fsynctest")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	destDir, err := ioutil.TempDir("", "fsynctest")
+	require.NoError(t, err)
+	defer os.RemoveAll(destDir)
+
+	for _, f := range []string{"foo", "bar", "baz"} {
+		err = ioutil.WriteFile(filepath.Join(tmpDir, f), []byte("content-"+f), 0600)
+		require.NoError(t, err)
+	}
+
+	s, err := session.NewSession("foo", "bar")
+	require.NoError(t, err)
+
+	m, err := session.NewManager()
+	require.NoError(t, err)
+
+	s.Allow(NewFSSyncTargetIncremental(destDir))
+
+	dialer := session.Dialer(testutil.TestStream(testutil.Handler(m.HandleConn)))
+
+	g, ctx := errgroup.WithContext(context.Background())
+
+	g.Go(func() error {
+		return s.Run(ctx, dialer)
+	})
+
+	g.Go(func() (reterr error) {
+		c, err := m.Get(ctx, s.ID())
+		if err != nil {
+			return err
+		}
+		if err := CopyToCaller(ctx, tmpDir, c, "", nil); err != nil {
+			return err
+		}
+
+		fi1, err := os.Stat(filepath.Join(destDir, "foo"))
+		if err != nil {
+			return err
+		}
+
+		if err := os.Remove(filepath.Join(tmpDir, "bar")); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filepath.Join(tmpDir, "baz"), []byte("content-baz2"), 0600); err != nil {
+			return err
+		}
+
+		if err := CopyToCaller(ctx, tmpDir, c, "", nil); err != nil {
+			return err
+		}
+
+		// unchanged file is not rewritten
+		fi2, err := os.Stat(filepath.Join(destDir, "foo"))
+		if err != nil {
+			return err
+		}
+		assert.True(t, os.SameFile(fi1, fi2))
+
+		_, err = os.Stat(filepath.Join(destDir, "bar"))
+		assert.True(t, os.IsNotExist(err))
+
+		dt, err := ioutil.ReadFile(filepath.Join(destDir, "baz"))
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, "content-baz2", string(dt))
+		return s.Close()
+	})
+
+	err = g.Wait()
+	require.NoError(t, err)
+}
+
+func TestFileSyncTargetFile(t *testing.T) {
+	s, err := session.NewSession("foo", "bar")
+	require.NoError(t, err)
+
+	m, err := session.NewManager()
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	s.Allow(NewFSSyncTargetFile(buf))
+
+	dialer := session.Dialer(testutil.TestStream(testutil.Handler(m.HandleConn)))
+
+	g, ctx := errgroup.WithContext(context.Background())
+
+	g.Go(func() error {
+		return s.Run(ctx, dialer)
+	})
+
+	// larger than a single message
+	data := bytes.Repeat([]byte("0123456789"), 10000)
+
+	g.Go(func() (reterr error) {
+		c, err := m.Get(ctx, s.ID())
+		if err != nil {
+			return err
+		}
+		w, err := CopyFileWriter(ctx, c, "")
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+		if err := w.Close(); err != nil {
+			return err
+		}
+		assert.Equal(t, data, buf.Bytes())
+		return s.Close()
+	})
+
+	err = g.Wait()
+	require.NoError(t, err)
+}
+
+func TestFileSyncTargets(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "fsynctest")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	destDir, err := ioutil.TempDir("", "fsynctest")
+	require.NoError(t, err)
+	defer os.RemoveAll(destDir)
+
+	err = ioutil.WriteFile(filepath.Join(tmpDir, "foo"), []byte("content1"), 0600)
+	require.NoError(t, err)
+
+	s, err := session.NewSession("foo", "bar")
+	require.NoError(t, err)
+
+	m, err := session.NewManager()
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	s.Allow(NewFSSyncTargets([]FSSyncTarget{
+		{Name: "dir", OutDir: destDir},
+		{Name: "file", Writer: buf},
+	}))
+
+	dialer := session.Dialer(testutil.TestStream(testutil.Handler(m.HandleConn)))
+
+	g, ctx := errgroup.WithContext(context.Background())
+
+	g.Go(func() error {
+		return s.Run(ctx, dialer)
+	})
+
+	g.Go(func() (reterr error) {
+		c, err := m.Get(ctx, s.ID())
+		if err != nil {
+			return err
+		}
+		if err := CopyToCaller(ctx, tmpDir, c, "dir", nil); err != nil {
+			return err
+		}
+		dt, err := ioutil.ReadFile(filepath.Join(destDir, "foo"))
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, "content1", string(dt))
+
+		w, err := CopyFileWriter(ctx, c, "file")
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write([]byte("content2")); err != nil {
+			return err
+		}
+		if err := w.Close(); err != nil {
+			return err
+		}
+		assert.Equal(t, "content2", buf.String())
+
+		err = CopyToCaller(ctx, tmpDir, c, "missing", nil)
+		assert.Error(t, err)
+		return s.Close()
+	})
+
+	err = g.Wait()
+	require.NoError(t, err)
+}
@@ -0,0 +1,36 @@
+package secrets
+
+import (
+	"github.com/moby/buildkit/session"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SecretStore provides the secret values that can be requested by the build
+type SecretStore interface {
+	GetSecret(context.Context, string) ([]byte, error)
+}
+
+// ErrNotFound is returned when a secret is not known to the client
+var ErrNotFound = errors.Errorf("not found")
+
+// GetSecret requests the value of the secret id from the client session
+func GetSecret(ctx context.Context, c session.Caller, id string) ([]byte, error) {
+	method := session.MethodURL(_Secrets_serviceDesc.ServiceName, "GetSecret")
+	if !c.Supports(method) {
+		return nil, errors.Errorf("secrets not supported by the client")
+	}
+	client := NewSecretsClient(c.Conn())
+	resp, err := client.GetSecret(ctx, &GetSecretRequest{
+		ID: id,
+	})
+	if err != nil {
+		if st, ok := status.FromError(err); ok && st.Code() == codes.NotFound {
+			return nil, errors.Wrapf(ErrNotFound, "secret %s", id)
+		}
+		return nil, errors.Wrapf(err, "failed to get secret %s", id)
+	}
+	return resp.Data, nil
+}
@@ -0,0 +1,40 @@
+package secretsprovider
+
+import (
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/secrets"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type secretProvider struct {
+	store secrets.SecretStore
+}
+
+// NewSecretProvider creates a session attachable that gives the build access
+// to the secrets in store
+func NewSecretProvider(store secrets.SecretStore) session.Attachable {
+	return &secretProvider{
+		store: store,
+	}
+}
+
+func (sp *secretProvider) Register(server *grpc.Server) {
+	secrets.RegisterSecretsServer(server, sp)
+}
+
+func (sp *secretProvider) GetSecret(ctx context.Context, req *secrets.GetSecretRequest) (*secrets.GetSecretResponse, error) {
+	dt, err := sp.store.GetSecret(ctx, req.ID)
+	if err != nil {
+		if errors.Cause(err) == secrets.ErrNotFound {
+			return nil, status.Errorf(codes.NotFound, "%v", err)
+		}
+		return nil, err
+	}
+	return &secrets.GetSecretResponse{
+		Data: dt,
+	}, nil
+}
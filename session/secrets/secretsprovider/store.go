@@ -0,0 +1,49 @@
+package secretsprovider
+
+import (
+	"io/ioutil"
+
+	"github.com/moby/buildkit/session/secrets"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// FileSource maps a secret ID to a file on the client
+type FileSource struct {
+	ID       string
+	FilePath string
+}
+
+// NewFileStore creates a secret store that reads the secret values from
+// files. The files are read when a secret is requested.
+func NewFileStore(files []FileSource) (secrets.SecretStore, error) {
+	m := map[string]FileSource{}
+	for _, f := range files {
+		if f.ID == "" {
+			return nil, errors.Errorf("secret with file %s requires an id", f.FilePath)
+		}
+		if f.FilePath == "" {
+			f.FilePath = f.ID
+		}
+		m[f.ID] = f
+	}
+	return &fileStore{
+		m: m,
+	}, nil
+}
+
+type fileStore struct {
+	m map[string]FileSource
+}
+
+func (fs *fileStore) GetSecret(ctx context.Context, id string) ([]byte, error) {
+	v, ok := fs.m[id]
+	if !ok {
+		return nil, errors.WithStack(secrets.ErrNotFound)
+	}
+	dt, err := ioutil.ReadFile(v.FilePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read secret %s", id)
+	}
+	return dt, nil
+}
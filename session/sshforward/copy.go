@@ -0,0 +1,82 @@
+package sshforward
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+)
+
+// Copy proxies the data between a connection and a stream of BytesMessage
+// until either side closes
+func Copy(ctx context.Context, conn io.ReadWriteCloser, stream grpc.Stream) error {
+	g, ctx := errgroup.WithContext(ctx)
+	recvDone := make(chan struct{})
+
+	g.Go(func() (retErr error) {
+		p := &BytesMessage{}
+		for {
+			if err := stream.RecvMsg(p); err != nil {
+				if err == io.EOF {
+					// the other side has finished, closing the connection
+					// also ends the read loop
+					close(recvDone)
+					conn.Close()
+					return nil
+				}
+				conn.Close()
+				return err
+			}
+			select {
+			case <-ctx.Done():
+				conn.Close()
+				return ctx.Err()
+			default:
+			}
+			if _, err := conn.Write(p.Data); err != nil {
+				conn.Close()
+				return err
+			}
+			p.Data = p.Data[:0]
+		}
+	})
+
+	g.Go(func() (retErr error) {
+		defer func() {
+			// let the other side know that no more data will be sent
+			if cs, ok := stream.(grpc.ClientStream); ok {
+				if err := cs.CloseSend(); err != nil && retErr == nil {
+					retErr = err
+				}
+			}
+		}()
+		for {
+			buf := make([]byte, 32*1024)
+			n, err := conn.Read(buf)
+			if err != nil {
+				select {
+				case <-recvDone:
+					return nil
+				default:
+				}
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+			p := &BytesMessage{Data: buf[:n]}
+			if err := stream.SendMsg(p); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+	})
+
+	return g.Wait()
+}
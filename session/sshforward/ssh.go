@@ -0,0 +1,139 @@
+package sshforward
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/moby/buildkit/session"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultID is the default ssh ID
+const DefaultID = "default"
+
+// ErrNotFound is returned when the client doesn't forward an agent with the
+// requested ID
+var ErrNotFound = errors.Errorf("not found")
+
+// KeySSHID is the metadata key for the ID of the forwarded agent
+const KeySSHID = "buildkit.ssh.id"
+
+type server struct {
+	caller session.Caller
+}
+
+func (s *server) run(ctx context.Context, l net.Listener, id string) error {
+	eg, ctx := errgroup.WithContext(ctx)
+
+	eg.Go(func() error {
+		<-ctx.Done()
+		l.Close()
+		return ctx.Err()
+	})
+
+	eg.Go(func() error {
+		opts := make(map[string][]string)
+		opts[KeySSHID] = []string{id}
+		ctx := metadata.NewContext(ctx, opts)
+		client := NewSSHClient(s.caller.Conn())
+
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return err
+			}
+
+			stream, err := client.ForwardAgent(ctx)
+			if err != nil {
+				conn.Close()
+				return err
+			}
+
+			go Copy(ctx, conn, stream)
+		}
+	})
+
+	return eg.Wait()
+}
+
+// SocketOpt defines the location and permissions of a forwarded agent socket
+type SocketOpt struct {
+	ID   string
+	UID  int
+	GID  int
+	Mode int
+}
+
+// MountSSHSocket creates a unix socket that forwards the connections to the
+// ssh agent in the client session. The returned function removes the socket
+// and needs to be called once the socket is not used anymore.
+func MountSSHSocket(ctx context.Context, c session.Caller, opt SocketOpt) (sockPath string, closer func() error, err error) {
+	dir, err := ioutil.TempDir("", ".buildkit-ssh-sock")
+	if err != nil {
+		return "", nil, errors.WithStack(err)
+	}
+
+	defer func() {
+		if err != nil {
+			os.RemoveAll(dir)
+		}
+	}()
+
+	if err := os.Chmod(dir, 0711); err != nil {
+		return "", nil, errors.WithStack(err)
+	}
+
+	sockPath = filepath.Join(dir, "ssh_auth_sock")
+
+	l, err := net.Listen("unix", sockPath)
+	if err != nil {
+		return "", nil, errors.WithStack(err)
+	}
+
+	if err := os.Chown(sockPath, opt.UID, opt.GID); err != nil {
+		l.Close()
+		return "", nil, errors.WithStack(err)
+	}
+	if err := os.Chmod(sockPath, os.FileMode(opt.Mode)); err != nil {
+		l.Close()
+		return "", nil, errors.WithStack(err)
+	}
+
+	s := &server{caller: c}
+
+	id := opt.ID
+	if id == "" {
+		id = DefaultID
+	}
+
+	go s.run(ctx, l, id) // erroring per connection allowed
+
+	return sockPath, func() error {
+		err := l.Close()
+		os.RemoveAll(dir)
+		return err
+	}, nil
+}
+
+// CheckSSHID verifies that the client session can forward the agent id
+func CheckSSHID(ctx context.Context, c session.Caller, id string) error {
+	method := session.MethodURL(_SSH_serviceDesc.ServiceName, "ForwardAgent")
+	if !c.Supports(method) {
+		return errors.Errorf("ssh forwarding not supported by the client")
+	}
+	client := NewSSHClient(c.Conn())
+	if _, err := client.CheckAgent(ctx, &CheckAgentRequest{ID: id}); err != nil {
+		if st, ok := status.FromError(err); ok && st.Code() == codes.NotFound {
+			return errors.Wrapf(ErrNotFound, "ssh agent %s", id)
+		}
+		return errors.Wrapf(err, "failed to check ssh agent %s", id)
+	}
+	return nil
+}
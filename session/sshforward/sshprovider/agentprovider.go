@@ -0,0 +1,90 @@
+package sshprovider
+
+import (
+	"net"
+	"os"
+
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/sshforward"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// AgentConfig is the config for a single exposed SSH agent
+type AgentConfig struct {
+	ID     string
+	Socket string
+}
+
+// NewSSHAgentProvider creates a session provider that allows access to ssh
+// agent sockets on the client. An empty socket defaults to SSH_AUTH_SOCK.
+func NewSSHAgentProvider(confs []AgentConfig) (session.Attachable, error) {
+	m := map[string]string{}
+	for _, conf := range confs {
+		if conf.ID == "" {
+			conf.ID = sshforward.DefaultID
+		}
+		if _, ok := m[conf.ID]; ok {
+			return nil, errors.Errorf("invalid duplicate ID %s", conf.ID)
+		}
+		if conf.Socket == "" {
+			conf.Socket = os.Getenv("SSH_AUTH_SOCK")
+			if conf.Socket == "" {
+				return nil, errors.Errorf("invalid empty ssh agent socket for %s, make sure SSH_AUTH_SOCK is set", conf.ID)
+			}
+		}
+		if _, err := os.Stat(conf.Socket); err != nil {
+			return nil, errors.Wrapf(err, "failed to stat ssh agent socket %s", conf.Socket)
+		}
+		m[conf.ID] = conf.Socket
+	}
+	return &socketProvider{
+		m: m,
+	}, nil
+}
+
+type socketProvider struct {
+	m map[string]string
+}
+
+func (sp *socketProvider) Register(server *grpc.Server) {
+	sshforward.RegisterSSHServer(server, sp)
+}
+
+func (sp *socketProvider) CheckAgent(ctx context.Context, req *sshforward.CheckAgentRequest) (*sshforward.CheckAgentResponse, error) {
+	id := sshforward.DefaultID
+	if req.ID != "" {
+		id = req.ID
+	}
+	if _, ok := sp.m[id]; !ok {
+		return nil, status.Errorf(codes.NotFound, "unset ssh forward key %s", id)
+	}
+	return &sshforward.CheckAgentResponse{}, nil
+}
+
+func (sp *socketProvider) ForwardAgent(stream sshforward.SSH_ForwardAgentServer) error {
+	id := sshforward.DefaultID
+
+	opts, _ := metadata.FromContext(stream.Context()) // if no metadata continue with empty object
+
+	if v, ok := opts[sshforward.KeySSHID]; ok && len(v) > 0 && v[0] != "" {
+		id = v[0]
+	}
+
+	socket, ok := sp.m[id]
+	if !ok {
+		return status.Errorf(codes.NotFound, "unset ssh forward key %s", id)
+	}
+
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return errors.Wrapf(err, "failed to connect to %s", socket)
+	}
+	defer conn.Close()
+
+	return sshforward.Copy(context.TODO(), conn, stream)
+}
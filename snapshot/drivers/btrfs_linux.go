@@ -0,0 +1,255 @@
+package drivers
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/containerd/containerd/fs"
+	"github.com/containerd/containerd/log"
+	"github.com/containerd/containerd/mount"
+	"github.com/containerd/containerd/snapshot"
+	"github.com/containerd/containerd/snapshot/storage"
+	"github.com/pkg/errors"
+)
+
+// Btrfs stores every snapshot in a btrfs subvolume that is a copy-on-write
+// snapshot of the subvolume of its parent
+const Btrfs = "btrfs"
+
+const btrfsSuperMagic = 0x9123683e
+
+func init() {
+	Register(Driver{
+		Name:      Btrfs,
+		Priority:  20,
+		Supported: btrfsSupported,
+		New:       newBtrfsSnapshotter,
+	})
+}
+
+func btrfsSupported(root string) error {
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return err
+	}
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(root, &st); err != nil {
+		return errors.Wrapf(err, "failed to stat filesystem of %s", root)
+	}
+	if uint32(st.Type) != btrfsSuperMagic {
+		return errors.Errorf("%s is not on a btrfs filesystem", root)
+	}
+	if _, err := exec.LookPath("btrfs"); err != nil {
+		return errors.Wrap(err, "btrfs tools are not installed")
+	}
+	return nil
+}
+
+type btrfsSnapshotter struct {
+	root string
+	ms   *storage.MetaStore
+}
+
+func newBtrfsSnapshotter(root string) (snapshot.Snapshotter, error) {
+	if err := btrfsSupported(root); err != nil {
+		return nil, err
+	}
+	ms, err := storage.NewMetaStore(filepath.Join(root, "metadata.db"))
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Mkdir(filepath.Join(root, "snapshots"), 0700); err != nil && !os.IsExist(err) {
+		return nil, err
+	}
+	return &btrfsSnapshotter{root: root, ms: ms}, nil
+}
+
+func (b *btrfsSnapshotter) Stat(ctx context.Context, key string) (snapshot.Info, error) {
+	ctx, t, err := b.ms.TransactionContext(ctx, false)
+	if err != nil {
+		return snapshot.Info{}, err
+	}
+	defer t.Rollback()
+	_, info, _, err := storage.GetInfo(ctx, key)
+	return info, err
+}
+
+func (b *btrfsSnapshotter) Update(ctx context.Context, info snapshot.Info, fieldpaths ...string) (snapshot.Info, error) {
+	ctx, t, err := b.ms.TransactionContext(ctx, true)
+	if err != nil {
+		return snapshot.Info{}, err
+	}
+	info, err = storage.UpdateInfo(ctx, info, fieldpaths...)
+	if err != nil {
+		t.Rollback()
+		return snapshot.Info{}, err
+	}
+	if err := t.Commit(); err != nil {
+		return snapshot.Info{}, err
+	}
+	return info, nil
+}
+
+func (b *btrfsSnapshotter) Usage(ctx context.Context, key string) (snapshot.Usage, error) {
+	ctx, t, err := b.ms.TransactionContext(ctx, false)
+	if err != nil {
+		return snapshot.Usage{}, err
+	}
+	defer t.Rollback()
+	id, info, usage, err := storage.GetInfo(ctx, key)
+	if err != nil {
+		return snapshot.Usage{}, err
+	}
+	if info.Kind == snapshot.KindActive {
+		du, err := fs.DiskUsage(b.subvolume(id))
+		if err != nil {
+			return snapshot.Usage{}, err
+		}
+		usage = snapshot.Usage(du)
+	}
+	return usage, nil
+}
+
+func (b *btrfsSnapshotter) Prepare(ctx context.Context, key, parent string, opts ...snapshot.Opt) ([]mount.Mount, error) {
+	return b.createSnapshot(ctx, snapshot.KindActive, key, parent, opts)
+}
+
+func (b *btrfsSnapshotter) View(ctx context.Context, key, parent string, opts ...snapshot.Opt) ([]mount.Mount, error) {
+	return b.createSnapshot(ctx, snapshot.KindView, key, parent, opts)
+}
+
+func (b *btrfsSnapshotter) Mounts(ctx context.Context, key string) ([]mount.Mount, error) {
+	ctx, t, err := b.ms.TransactionContext(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	s, err := storage.GetSnapshot(ctx, key)
+	t.Rollback()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get snapshot mount")
+	}
+	return b.mounts(s), nil
+}
+
+// Commit makes the subvolume of the active snapshot read-only
+func (b *btrfsSnapshotter) Commit(ctx context.Context, name, key string, opts ...snapshot.Opt) error {
+	ctx, t, err := b.ms.TransactionContext(ctx, true)
+	if err != nil {
+		return err
+	}
+	id, _, _, err := storage.GetInfo(ctx, key)
+	if err != nil {
+		t.Rollback()
+		return err
+	}
+	usage, err := fs.DiskUsage(b.subvolume(id))
+	if err != nil {
+		t.Rollback()
+		return err
+	}
+	if _, err := storage.CommitActive(ctx, key, name, snapshot.Usage(usage), opts...); err != nil {
+		t.Rollback()
+		return errors.Wrap(err, "failed to commit snapshot")
+	}
+	if err := btrfs("property", "set", "-ts", b.subvolume(id), "ro", "true"); err != nil {
+		t.Rollback()
+		return err
+	}
+	return t.Commit()
+}
+
+func (b *btrfsSnapshotter) Remove(ctx context.Context, key string) error {
+	ctx, t, err := b.ms.TransactionContext(ctx, true)
+	if err != nil {
+		return err
+	}
+	id, _, err := storage.Remove(ctx, key)
+	if err != nil {
+		t.Rollback()
+		return errors.Wrap(err, "failed to remove")
+	}
+	if err := t.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit")
+	}
+	path := b.subvolume(id)
+	if _, err := os.Stat(path); err != nil {
+		return nil
+	}
+	// the committed subvolumes are read-only
+	if err := btrfs("property", "set", "-ts", path, "ro", "false"); err != nil {
+		log.G(ctx).WithError(err).WithField("path", path).Warn("failed to make subvolume writable")
+	}
+	if err := btrfs("subvolume", "delete", path); err != nil {
+		log.G(ctx).WithError(err).WithField("path", path).Warn("failed to delete subvolume")
+	}
+	return nil
+}
+
+func (b *btrfsSnapshotter) Walk(ctx context.Context, fn func(context.Context, snapshot.Info) error) error {
+	ctx, t, err := b.ms.TransactionContext(ctx, false)
+	if err != nil {
+		return err
+	}
+	defer t.Rollback()
+	return storage.WalkInfo(ctx, fn)
+}
+
+func (b *btrfsSnapshotter) createSnapshot(ctx context.Context, kind snapshot.Kind, key, parent string, opts []snapshot.Opt) ([]mount.Mount, error) {
+	ctx, t, err := b.ms.TransactionContext(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	s, err := storage.CreateSnapshot(ctx, kind, key, parent, opts...)
+	if err != nil {
+		t.Rollback()
+		return nil, errors.Wrap(err, "failed to create snapshot")
+	}
+	// the views of a parent use its read-only subvolume
+	if kind == snapshot.KindActive || len(s.ParentIDs) == 0 {
+		path := b.subvolume(s.ID)
+		if len(s.ParentIDs) > 0 {
+			err = btrfs("subvolume", "snapshot", b.subvolume(s.ParentIDs[0]), path)
+		} else {
+			err = btrfs("subvolume", "create", path)
+		}
+		if err != nil {
+			t.Rollback()
+			return nil, err
+		}
+	}
+	if err := t.Commit(); err != nil {
+		return nil, errors.Wrap(err, "commit failed")
+	}
+	return b.mounts(s), nil
+}
+
+func (b *btrfsSnapshotter) subvolume(id string) string {
+	return filepath.Join(b.root, "snapshots", id)
+}
+
+func (b *btrfsSnapshotter) mounts(s storage.Snapshot) []mount.Mount {
+	roFlag := "rw"
+	if s.Kind == snapshot.KindView {
+		roFlag = "ro"
+	}
+	source := b.subvolume(s.ID)
+	if s.Kind == snapshot.KindView && len(s.ParentIDs) > 0 {
+		source = b.subvolume(s.ParentIDs[0])
+	}
+	return []mount.Mount{{
+		Source:  source,
+		Type:    "bind",
+		Options: []string{roFlag, "rbind"},
+	}}
+}
+
+func btrfs(args ...string) error {
+	out, err := exec.Command("btrfs", args...).CombinedOutput()
+	if err != nil {
+		return errors.Wrapf(err, "btrfs %s failed: %s", strings.Join(args, " "), strings.TrimSpace(string(out)))
+	}
+	return nil
+}
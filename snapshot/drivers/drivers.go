@@ -0,0 +1,94 @@
+// Package drivers selects the storage strategy of the snapshots of the
+// standalone daemon. A driver is selected by name or detected from the
+// filesystem of the root directory of the daemon.
+package drivers
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/containerd/containerd/snapshot"
+	"github.com/pkg/errors"
+)
+
+// Auto is the name that selects the driver with Detect
+const Auto = "auto"
+
+// Driver creates the snapshotters of a storage strategy
+type Driver struct {
+	Name string
+	// Priority orders the drivers for Detect, the highest first
+	Priority int
+	// Supported returns an error if the snapshots can't be stored on the
+	// filesystem of root
+	Supported func(root string) error
+	// New returns a snapshotter that stores the snapshots under root
+	New func(root string) (snapshot.Snapshotter, error)
+}
+
+var (
+	mu      sync.Mutex
+	drivers = map[string]Driver{}
+)
+
+// Register makes a driver available by its name
+func Register(d Driver) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := drivers[d.Name]; ok {
+		panic("snapshot driver " + d.Name + " is already registered")
+	}
+	drivers[d.Name] = d
+}
+
+// Names returns the names of the registered drivers
+func Names() []string {
+	mu.Lock()
+	defer mu.Unlock()
+	names := make([]string, 0, len(drivers))
+	for name := range drivers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get returns the driver name. Auto returns the driver detected for root.
+func Get(name, root string) (Driver, error) {
+	if name == Auto || name == "" {
+		return Detect(root)
+	}
+	mu.Lock()
+	d, ok := drivers[name]
+	mu.Unlock()
+	if !ok {
+		return Driver{}, errors.Errorf("unknown snapshotter %s, expected one of %v", name, Names())
+	}
+	if err := d.Supported(root); err != nil {
+		return Driver{}, errors.Wrapf(err, "snapshotter %s is not supported", name)
+	}
+	return d, nil
+}
+
+// Detect returns the driver with the highest priority that supports the
+// filesystem of root
+func Detect(root string) (Driver, error) {
+	mu.Lock()
+	sorted := make([]Driver, 0, len(drivers))
+	for _, d := range drivers {
+		sorted = append(sorted, d)
+	}
+	mu.Unlock()
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Priority != sorted[j].Priority {
+			return sorted[i].Priority > sorted[j].Priority
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	for _, d := range sorted {
+		if err := d.Supported(root); err == nil {
+			return d, nil
+		}
+	}
+	return Driver{}, errors.Errorf("no snapshotter supports %s", root)
+}
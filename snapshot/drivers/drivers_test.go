@@ -0,0 +1,107 @@
+package drivers
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containerd/containerd/namespaces"
+	"github.com/containerd/containerd/snapshot"
+	buildkitsnapshot "github.com/moby/buildkit/snapshot"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "drivers")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	require.Contains(t, Names(), Native)
+
+	d, err := Get(Native, tmpdir)
+	require.NoError(t, err)
+	require.Equal(t, Native, d.Name)
+
+	_, err = Get("invalid", tmpdir)
+	require.Error(t, err)
+
+	auto, err := Get(Auto, tmpdir)
+	require.NoError(t, err)
+	detected, err := Detect(tmpdir)
+	require.NoError(t, err)
+	require.Equal(t, detected.Name, auto.Name)
+	require.NoError(t, detected.Supported(tmpdir))
+}
+
+func BenchmarkCommit(b *testing.B) {
+	benchmarkDrivers(b, func(b *testing.B, ctx context.Context, s snapshot.Snapshotter) {
+		parent := ""
+		for i := 0; i < b.N; i++ {
+			// the number of overlayfs layers is limited
+			if i%50 == 0 {
+				parent = ""
+			}
+			key := fmt.Sprintf("active%d", i)
+			m, err := s.Prepare(ctx, key, parent)
+			require.NoError(b, err)
+			lm := buildkitsnapshot.LocalMounter(m)
+			dir, err := lm.Mount()
+			require.NoError(b, err)
+			require.NoError(b, ioutil.WriteFile(filepath.Join(dir, key), []byte(key), 0600))
+			require.NoError(b, lm.Unmount())
+			parent = fmt.Sprintf("committed%d", i)
+			require.NoError(b, s.Commit(ctx, parent, key))
+		}
+	})
+}
+
+func BenchmarkMount(b *testing.B) {
+	benchmarkDrivers(b, func(b *testing.B, ctx context.Context, s snapshot.Snapshotter) {
+		m, err := s.Prepare(ctx, "active", "")
+		require.NoError(b, err)
+		lm := buildkitsnapshot.LocalMounter(m)
+		dir, err := lm.Mount()
+		require.NoError(b, err)
+		for i := 0; i < 100; i++ {
+			require.NoError(b, ioutil.WriteFile(filepath.Join(dir, fmt.Sprintf("file%d", i)), make([]byte, 4096), 0600))
+		}
+		require.NoError(b, lm.Unmount())
+		require.NoError(b, s.Commit(ctx, "base", "active"))
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			key := fmt.Sprintf("mount%d", i)
+			m, err := s.Prepare(ctx, key, "base")
+			require.NoError(b, err)
+			lm := buildkitsnapshot.LocalMounter(m)
+			_, err = lm.Mount()
+			require.NoError(b, err)
+			require.NoError(b, lm.Unmount())
+			require.NoError(b, s.Remove(ctx, key))
+		}
+	})
+}
+
+// benchmarkDrivers runs fn with every driver that is supported on the
+// filesystem of the temporary directory
+func benchmarkDrivers(b *testing.B, fn func(*testing.B, context.Context, snapshot.Snapshotter)) {
+	ctx := namespaces.WithNamespace(context.Background(), "buildkit-test")
+	for _, name := range Names() {
+		b.Run(name, func(b *testing.B) {
+			tmpdir, err := ioutil.TempDir("", "drivers")
+			require.NoError(b, err)
+			defer os.RemoveAll(tmpdir)
+
+			d, err := Get(name, tmpdir)
+			if err != nil {
+				b.Skip(err)
+			}
+			s, err := d.New(filepath.Join(tmpdir, "snapshots"))
+			require.NoError(b, err)
+			fn(b, ctx, s)
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package drivers
+
+import (
+	"github.com/containerd/containerd/snapshot/naive"
+)
+
+// Native copies the parent of every snapshot. It is supported by every
+// filesystem and by the daemons running in a user namespace.
+const Native = "native"
+
+func init() {
+	Register(Driver{
+		Name:      Native,
+		Supported: func(string) error { return nil },
+		New:       naive.NewSnapshotter,
+	})
+}
@@ -0,0 +1,64 @@
+package drivers
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/containerd/containerd/fs"
+	"github.com/containerd/containerd/mount"
+	"github.com/containerd/containerd/snapshot/overlay"
+	"github.com/pkg/errors"
+)
+
+// Overlay stacks the changes of the snapshots on their parents with
+// overlayfs
+const Overlay = "overlayfs"
+
+func init() {
+	Register(Driver{
+		Name:      Overlay,
+		Priority:  10,
+		Supported: overlaySupported,
+		New:       overlay.NewSnapshotter,
+	})
+}
+
+// overlaySupported mounts a test overlay under root
+func overlaySupported(root string) error {
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return err
+	}
+	dtype, err := fs.SupportsDType(root)
+	if err != nil {
+		return err
+	}
+	if !dtype {
+		return errors.Errorf("%s does not support d_type", root)
+	}
+	td, err := ioutil.TempDir(root, "overlay-check")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(td)
+	for _, dir := range []string{"lower", "upper", "work", "merged"} {
+		if err := os.Mkdir(filepath.Join(td, dir), 0700); err != nil {
+			return err
+		}
+	}
+	m := mount.Mount{
+		Type:   "overlay",
+		Source: "overlay",
+		Options: []string{
+			fmt.Sprintf("lowerdir=%s", filepath.Join(td, "lower")),
+			fmt.Sprintf("upperdir=%s", filepath.Join(td, "upper")),
+			fmt.Sprintf("workdir=%s", filepath.Join(td, "work")),
+		},
+	}
+	dest := filepath.Join(td, "merged")
+	if err := m.Mount(dest); err != nil {
+		return errors.Wrap(err, "failed to mount overlay")
+	}
+	return mount.Unmount(dest, 0)
+}
@@ -55,11 +55,10 @@ func (b *buildOp) Run(ctx context.Context, inputs []Reference) (outputs []Refere
 		return nil, errors.Errorf("no llb definition input %s found", pb.LLBDefinitionInput)
 	}
 
-	i := int(llbDef.Input)
-	if i >= len(inputs) {
-		return nil, errors.Errorf("invalid index %v", i) // TODO: this should be validated before
+	if err := checkInputIndex(vertexDigest(b.v), "builder input "+pb.LLBDefinitionInput, llbDef.Input, len(inputs)); err != nil {
+		return nil, errors.WithStack(err)
 	}
-	inp := inputs[i]
+	inp := inputs[int(llbDef.Input)]
 
 	ref, ok := toImmutableRef(inp)
 	if !ok {
@@ -131,6 +130,8 @@ func (b *buildOp) Run(ctx context.Context, inputs []Reference) (outputs []Refere
 	return []Reference{newref}, err
 }
 
+func (b *buildOp) solvesNested() {}
+
 func (b *buildOp) ContentKeys(context.Context, [][]digest.Digest, []Reference) ([]digest.Digest, error) {
 	return nil, nil
 }
@@ -0,0 +1,183 @@
+package solver
+
+import (
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/cache/cacheimport"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+)
+
+// mergedCache combines the local instruction cache with caches imported from
+// remote sources. New records are only written to the local cache. Records
+// found from remote caches are added to the local cache so they can be
+// exported again and don't need to be imported on the next build.
+type mergedCache struct {
+	local   InstructionCache
+	remotes []InstructionCache
+}
+
+func mergeRemoteCache(local InstructionCache, remotes ...InstructionCache) InstructionCache {
+	return &mergedCache{local: local, remotes: remotes}
+}
+
+func (mc *mergedCache) Probe(ctx context.Context, key digest.Digest) (bool, error) {
+	v, err := mc.local.Probe(ctx, key)
+	if err != nil || v {
+		return v, err
+	}
+	for _, r := range mc.remotes {
+		v, err := r.Probe(ctx, key)
+		if err != nil || v {
+			return v, err
+		}
+	}
+	return false, nil
+}
+
+func (mc *mergedCache) Lookup(ctx context.Context, key digest.Digest) (interface{}, error) {
+	v, err := mc.local.Lookup(ctx, key)
+	if err != nil || v != nil {
+		return v, err
+	}
+	for _, r := range mc.remotes {
+		v, err := r.Lookup(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if v != nil {
+			if err := mc.local.Set(key, v); err != nil {
+				logrus.Errorf("failed to save imported cache for %s: %v", key, err)
+			}
+			return v, nil
+		}
+	}
+	return nil, nil
+}
+
+func (mc *mergedCache) Set(key digest.Digest, ref interface{}) error {
+	return mc.local.Set(key, ref)
+}
+
+func (mc *mergedCache) SetContentMapping(contentKey, key digest.Digest) error {
+	return mc.local.SetContentMapping(contentKey, key)
+}
+
+func (mc *mergedCache) GetContentMapping(dgst digest.Digest) ([]digest.Digest, error) {
+	dgsts, err := mc.local.GetContentMapping(dgst)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range mc.remotes {
+		d, err := r.GetContentMapping(dgst)
+		if err != nil {
+			return nil, err
+		}
+		dgsts = append(dgsts, d...)
+	}
+	return dgsts, nil
+}
+
+// cacheRecords returns the records from c for the results of the job, and
+// for all the vertexes loaded by the job if intermediate is set. The returned
+// references need to be released by the caller.
+func (j *job) cacheRecords(ctx context.Context, c InstructionCache, intermediate bool) ([]cacheimport.CacheRecord, []cacheimport.ContentMapping, error) {
+	j.l.mu.Lock()
+	defer j.l.mu.Unlock()
+
+	type output struct {
+		dgst  digest.Digest
+		index Index
+	}
+	outputs := map[output]struct{}{}
+	for _, inp := range j.roots {
+		outputs[output{inp.vertex.Digest(), inp.index}] = struct{}{}
+	}
+	for _, st := range j.l.actives {
+		if _, ok := st.jobs[j]; !ok || !intermediate {
+			continue
+		}
+		if vs, ok := st.solver.(*vertexSolver); ok {
+			for _, inp := range vs.v.inputs {
+				outputs[output{inp.vertex.Digest(), inp.index}] = struct{}{}
+			}
+		}
+	}
+
+	var records []cacheimport.CacheRecord
+	var mappings []cacheimport.ContentMapping
+	seenKeys := map[digest.Digest]struct{}{}
+	seenMappings := map[cacheimport.ContentMapping]struct{}{}
+
+	for o := range outputs {
+		st, ok := j.l.actives[o.dgst]
+		if !ok {
+			continue
+		}
+		vs, ok := st.solver.(*vertexSolver)
+		if !ok {
+			continue
+		}
+		keys, contentKeys, err := vs.exportKeys()
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, ck := range contentKeys {
+			cks, err := c.GetContentMapping(ck)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, k := range cks {
+				m := cacheimport.ContentMapping{ContentKey: ck, CacheKey: k}
+				if _, ok := seenMappings[m]; !ok {
+					mappings = append(mappings, m)
+					seenMappings[m] = struct{}{}
+				}
+				keys = append(keys, k)
+			}
+		}
+		for _, k := range keys {
+			k = cacheKeyForIndex(k, o.index)
+			if _, ok := seenKeys[k]; ok {
+				continue
+			}
+			seenKeys[k] = struct{}{}
+			v, err := c.Lookup(ctx, k)
+			if err != nil {
+				return nil, nil, err
+			}
+			if v == nil {
+				continue
+			}
+			ref, ok := v.(cache.ImmutableRef)
+			if !ok {
+				continue
+			}
+			records = append(records, cacheimport.CacheRecord{CacheKey: k, Reference: ref})
+		}
+	}
+	return records, mappings, nil
+}
+
+// exportKeys returns the cache keys, without the output index, that the
+// results of the vertex may be stored with
+func (vs *vertexSolver) exportKeys() ([]digest.Digest, []digest.Digest, error) {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+	if vs.baseKey == "" {
+		return nil, nil, nil
+	}
+	main, err := vs.mainCacheKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	last, err := vs.lastCacheKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	keys := []digest.Digest{main}
+	if last != main {
+		keys = append(keys, last)
+	}
+	return keys, vs.contentKeys, nil
+}
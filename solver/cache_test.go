@@ -0,0 +1,84 @@
+package solver
+
+import (
+	"testing"
+
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestMergedCache(t *testing.T) {
+	ctx := context.Background()
+	local := newTestCache()
+	remote := newTestCache()
+	remote.records["foo"] = "fooref"
+	remote.mappings["cfoo"] = []digest.Digest{"foo"}
+	local.mappings["cfoo"] = []digest.Digest{"bar"}
+
+	mc := mergeRemoteCache(local, remote)
+
+	ok, err := mc.Probe(ctx, "foo")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = mc.Probe(ctx, "baz")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	v, err := mc.Lookup(ctx, "foo")
+	require.NoError(t, err)
+	require.Equal(t, "fooref", v)
+
+	// remote records are saved to the local cache
+	require.Equal(t, "fooref", local.records["foo"])
+
+	err = mc.Set("baz", "bazref")
+	require.NoError(t, err)
+	require.Equal(t, "bazref", local.records["baz"])
+	_, ok = remote.records["baz"]
+	require.False(t, ok)
+
+	keys, err := mc.GetContentMapping("cfoo")
+	require.NoError(t, err)
+	require.Equal(t, []digest.Digest{"bar", "foo"}, keys)
+}
+
+type testCache struct {
+	records  map[digest.Digest]interface{}
+	mappings map[digest.Digest][]digest.Digest
+}
+
+func newTestCache() *testCache {
+	return &testCache{
+		records:  map[digest.Digest]interface{}{},
+		mappings: map[digest.Digest][]digest.Digest{},
+	}
+}
+
+func (tc *testCache) Probe(ctx context.Context, key digest.Digest) (bool, error) {
+	_, ok := tc.records[key]
+	return ok, nil
+}
+
+func (tc *testCache) Lookup(ctx context.Context, key digest.Digest) (interface{}, error) {
+	return tc.records[key], nil
+}
+
+func (tc *testCache) Set(key digest.Digest, ref interface{}) error {
+	if ref == nil {
+		return errors.Errorf("invalid ref")
+	}
+	tc.records[key] = ref
+	return nil
+}
+
+func (tc *testCache) SetContentMapping(contentKey, key digest.Digest) error {
+	tc.mappings[contentKey] = append(tc.mappings[contentKey], key)
+	return nil
+}
+
+func (tc *testCache) GetContentMapping(dgst digest.Digest) ([]digest.Digest, error) {
+	return tc.mappings[dgst], nil
+}
@@ -0,0 +1,131 @@
+package solver
+
+import (
+	"encoding/json"
+	"sync"
+
+	digest "github.com/opencontainers/go-digest"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+)
+
+// maxCacheKeyVersions is the number of cache key versions of an op, including
+// the current one, that are tried when looking up results from the cache
+const maxCacheKeyVersions = 2
+
+// cacheKeyVersions is the registry of cache key versions for every kind of
+// op, newest first. Changing how the keys of an op are computed should add a
+// new version to the front so the results saved with the previous versions
+// can still be found and migrated.
+var cacheKeyVersions = map[string][]string{}
+
+func registerCacheKeyVersions(kind string, versions ...string) {
+	cacheKeyVersions[kind] = versions
+}
+
+// previousCacheKeyVersions returns the versions of kind, other than the
+// current one, that are looked up from the cache
+func previousCacheKeyVersions(kind string) []string {
+	versions := cacheKeyVersions[kind]
+	if len(versions) > maxCacheKeyVersions {
+		versions = versions[:maxCacheKeyVersions]
+	}
+	if len(versions) < 2 {
+		return nil
+	}
+	return versions[1:]
+}
+
+// versionedOp is implemented by the ops that have registered multiple cache
+// key versions
+type versionedOp interface {
+	cacheKeyKind() string
+	// versionedCacheKey returns the base cache key of the op computed with a
+	// specific version
+	versionedCacheKey(ctx context.Context, version string) (digest.Digest, error)
+}
+
+// cacheKeyWithInputs combines the base key of an op with the keys of its
+// inputs
+func cacheKeyWithInputs(inputKeys []digest.Digest, base digest.Digest) (digest.Digest, error) {
+	dt, err := json.Marshal(struct {
+		Inputs   []digest.Digest
+		CacheKey digest.Digest
+	}{Inputs: inputKeys, CacheKey: base})
+	if err != nil {
+		return "", err
+	}
+	return digest.FromBytes(dt), nil
+}
+
+type cacheKeyMigration struct {
+	from, to digest.Digest
+}
+
+// cacheKeyMigrator saves the results found with a previous cache key version
+// with the current key. The records of the previous version are left in place
+// so that they can still be used by older daemons.
+type cacheKeyMigrator struct {
+	mu      sync.Mutex
+	pending map[cacheKeyMigration]InstructionCache
+	running bool
+}
+
+func newCacheKeyMigrator() *cacheKeyMigrator {
+	return &cacheKeyMigrator{pending: map[cacheKeyMigration]InstructionCache{}}
+}
+
+// add queues the results of the from key in c to be saved with the to key
+func (m *cacheKeyMigrator) add(c InstructionCache, from, to digest.Digest) {
+	if from == to {
+		return
+	}
+	m.mu.Lock()
+	m.pending[cacheKeyMigration{from: from, to: to}] = c
+	m.mu.Unlock()
+}
+
+// run migrates the queued records until the queue is empty. Only one call
+// does the work at a time, concurrent calls return immediately.
+func (m *cacheKeyMigrator) run(ctx context.Context) {
+	m.mu.Lock()
+	if m.running {
+		m.mu.Unlock()
+		return
+	}
+	m.running = true
+	for len(m.pending) > 0 {
+		pending := m.pending
+		m.pending = map[cacheKeyMigration]InstructionCache{}
+		m.mu.Unlock()
+		for mg, c := range pending {
+			if err := migrateCacheKey(ctx, c, mg.from, mg.to); err != nil {
+				logrus.Errorf("failed to migrate cache key %s to %s: %v", mg.from, mg.to, err)
+			}
+		}
+		m.mu.Lock()
+	}
+	m.running = false
+	m.mu.Unlock()
+}
+
+// migrateCacheKey saves the results of all the outputs of the from key with
+// the to key
+func migrateCacheKey(ctx context.Context, c InstructionCache, from, to digest.Digest) error {
+	for i := Index(0); ; i++ {
+		v, err := c.Lookup(ctx, cacheKeyForIndex(from, i))
+		if err != nil {
+			return err
+		}
+		if v == nil {
+			return nil
+		}
+		err = c.Set(cacheKeyForIndex(to, i), v)
+		if ref, ok := v.(Reference); ok {
+			ref.Release(context.TODO())
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package solver
+
+import (
+	"testing"
+
+	"github.com/moby/buildkit/solver/pb"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestPreviousCacheKeyVersions(t *testing.T) {
+	require.Equal(t, []string{execCacheTypeV0}, previousCacheKeyVersions("exec"))
+	require.Nil(t, previousCacheKeyVersions("unknown"))
+
+	defer delete(cacheKeyVersions, "test")
+	registerCacheKeyVersions("test", "v2", "v1", "v0")
+	require.Equal(t, []string{"v1"}, previousCacheKeyVersions("test"))
+}
+
+func TestExecVersionedCacheKey(t *testing.T) {
+	e := &execOp{op: &pb.ExecOp{
+		Meta: &pb.Meta{Args: []string{"true"}, Env: []string{"B=2", "A=1"}},
+	}}
+	k1, err := e.CacheKey(context.TODO())
+	require.NoError(t, err)
+	k0, err := e.versionedCacheKey(context.TODO(), execCacheTypeV0)
+	require.NoError(t, err)
+	require.NotEqual(t, k1, k0)
+
+	// the previous version doesn't normalize the environment
+	e.op.Meta.Env = []string{"A=1", "B=2"}
+	k, err := e.CacheKey(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, k1, k)
+	k, err = e.versionedCacheKey(context.TODO(), execCacheTypeV0)
+	require.NoError(t, err)
+	require.NotEqual(t, k0, k)
+
+	_, err = e.versionedCacheKey(context.TODO(), "buildkit.exec.v100")
+	require.Error(t, err)
+}
+
+func TestCacheKeyMigrator(t *testing.T) {
+	c := newTestCache()
+	c.records[cacheKeyForIndex("old", 0)] = "ref0"
+	c.records[cacheKeyForIndex("old", 1)] = "ref1"
+
+	m := newCacheKeyMigrator()
+	m.add(c, "old", "new")
+	m.add(c, "same", "same")
+	m.run(context.TODO())
+
+	require.Equal(t, "ref0", c.records[cacheKeyForIndex("new", 0)])
+	require.Equal(t, "ref1", c.records[cacheKeyForIndex("new", 1)])
+	require.Equal(t, "ref0", c.records[cacheKeyForIndex("old", 0)])
+	require.Equal(t, 4, len(c.records))
+	require.Equal(t, 0, len(m.pending))
+}
+
+func TestPreviousCacheKeysMigrate(t *testing.T) {
+	e := &execOp{op: &pb.ExecOp{
+		Meta: &pb.Meta{Args: []string{"true"}, Env: []string{"B=2", "A=1"}},
+	}}
+	base, err := e.CacheKey(context.TODO())
+	require.NoError(t, err)
+	base0, err := e.versionedCacheKey(context.TODO(), execCacheTypeV0)
+	require.NoError(t, err)
+	k0, err := cacheKeyWithInputs(nil, base0)
+	require.NoError(t, err)
+
+	c := newTestCache()
+	c.records[cacheKeyForIndex(k0, 0)] = "ref0"
+	m := newCacheKeyMigrator()
+	vs := &vertexSolver{op: e, cache: c, migrator: m, baseKey: base}
+
+	keys, err := vs.previousCacheKeys(context.TODO(), nil, false)
+	require.NoError(t, err)
+	require.Equal(t, []digest.Digest{k0}, keys)
+	require.Equal(t, 0, len(m.pending))
+
+	keys, err = vs.previousCacheKeys(context.TODO(), nil, true)
+	require.NoError(t, err)
+	require.Equal(t, []digest.Digest{k0}, keys)
+	require.Equal(t, 1, len(m.pending))
+	k, err := vs.mainCacheKey()
+	require.NoError(t, err)
+	require.Contains(t, m.pending, cacheKeyMigration{from: k0, to: k})
+
+	// the results are found with the last keys of the inputs and saved with
+	// the main key of the vertex
+	main, last := digest.FromString("main"), digest.FromString("last")
+	k0, err = cacheKeyWithInputs([]digest.Digest{last}, base0)
+	require.NoError(t, err)
+	c.records[cacheKeyForIndex(k0, 0)] = "ref1"
+	m = newCacheKeyMigrator()
+	vs = &vertexSolver{op: e, cache: c, migrator: m, baseKey: base, inputs: []*vertexInput{{cacheKeys: []digest.Digest{main, last}}}}
+
+	keys, err = vs.previousCacheKeys(context.TODO(), []digest.Digest{last}, true)
+	require.NoError(t, err)
+	require.Equal(t, []digest.Digest{k0}, keys)
+	k, err = cacheKeyWithInputs([]digest.Digest{main}, base)
+	require.NoError(t, err)
+	require.Equal(t, map[cacheKeyMigration]InstructionCache{{from: k0, to: k}: c}, m.pending)
+}
@@ -0,0 +1,91 @@
+package solver
+
+import (
+	"sync"
+
+	"github.com/moby/buildkit/client"
+	digest "github.com/opencontainers/go-digest"
+	"golang.org/x/net/context"
+)
+
+// pruneTracker records the cache keys that were found from the cache but
+// whose results could not be loaded anymore
+type pruneTracker struct {
+	InstructionCache
+	mu     sync.Mutex
+	pruned map[digest.Digest]struct{}
+}
+
+func newPruneTracker(c InstructionCache) *pruneTracker {
+	return &pruneTracker{InstructionCache: c, pruned: map[digest.Digest]struct{}{}}
+}
+
+func (pt *pruneTracker) Lookup(ctx context.Context, key digest.Digest) (interface{}, error) {
+	// the stale key may be cleared by the lookup so it needs to be probed first
+	exists, err := pt.InstructionCache.Probe(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	v, err := pt.InstructionCache.Lookup(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil && exists {
+		pt.mu.Lock()
+		pt.pruned[key] = struct{}{}
+		pt.mu.Unlock()
+	}
+	return v, nil
+}
+
+func (pt *pruneTracker) isPruned(key digest.Digest) bool {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	_, ok := pt.pruned[key]
+	return ok
+}
+
+// cacheMissReason explains why the vertex needs to run. contentMatched is set
+// if any of the contentKeys had cached results. Hold vs.mu before calling.
+func (vs *vertexSolver) cacheMissReason(contentKeys []digest.Digest, contentMatched bool) *client.CacheMissReason {
+	if pt, ok := vs.cache.(*pruneTracker); ok {
+		keys := append([]digest.Digest{}, vs.results...)
+		for _, last := range []bool{false, true} {
+			if k, err := vs.currentCacheKey(last); err == nil {
+				keys = append(keys, k)
+			}
+		}
+		for _, k := range keys {
+			for index := range vs.indexes {
+				if pt.isPruned(cacheKeyForIndex(k, index)) {
+					return &client.CacheMissReason{
+						Reason: client.CacheMissPruned,
+						Keys:   []digest.Digest{k},
+					}
+				}
+			}
+		}
+	}
+
+	var changed []digest.Digest
+	for i, inp := range vs.inputs {
+		if inp.changed {
+			changed = append(changed, vs.v.inputs[i].vertex.Digest())
+		}
+	}
+
+	if len(contentKeys) > 0 && !contentMatched {
+		return &client.CacheMissReason{
+			Reason: client.CacheMissContentChanged,
+			Keys:   contentKeys,
+			Inputs: changed,
+		}
+	}
+	if len(changed) > 0 {
+		return &client.CacheMissReason{
+			Reason: client.CacheMissInputChanged,
+			Inputs: changed,
+		}
+	}
+	return &client.CacheMissReason{Reason: client.CacheMissNoMatch}
+}
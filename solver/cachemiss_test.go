@@ -0,0 +1,65 @@
+package solver
+
+import (
+	"testing"
+
+	"github.com/moby/buildkit/client"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestPruneTracker(t *testing.T) {
+	c := newTestCache()
+	c.records["foo"] = "fooref"
+	c.records["pruned"] = nil
+	pt := newPruneTracker(c)
+
+	v, err := pt.Lookup(context.TODO(), "foo")
+	require.NoError(t, err)
+	require.Equal(t, "fooref", v)
+
+	for _, k := range []digest.Digest{"pruned", "missing"} {
+		v, err = pt.Lookup(context.TODO(), k)
+		require.NoError(t, err)
+		require.Nil(t, v)
+	}
+
+	require.True(t, pt.isPruned("pruned"))
+	require.False(t, pt.isPruned("missing"))
+	require.False(t, pt.isPruned("foo"))
+}
+
+func TestCacheMissReason(t *testing.T) {
+	pt := newPruneTracker(newTestCache())
+	inp := &vertex{digest: "input"}
+	vs := &vertexSolver{
+		v:       &vertex{inputs: []*input{{vertex: inp}}},
+		inputs:  []*vertexInput{{cacheKeys: []digest.Digest{"inputkey"}}},
+		cache:   pt,
+		baseKey: "base",
+		indexes: map[Index]struct{}{0: {}},
+	}
+
+	r := vs.cacheMissReason(nil, false)
+	require.Equal(t, client.CacheMissNoMatch, r.Reason)
+
+	vs.inputs[0].changed = true
+	r = vs.cacheMissReason(nil, false)
+	require.Equal(t, client.CacheMissInputChanged, r.Reason)
+	require.Equal(t, []digest.Digest{"input"}, r.Inputs)
+
+	r = vs.cacheMissReason([]digest.Digest{"content"}, false)
+	require.Equal(t, client.CacheMissContentChanged, r.Reason)
+	require.Equal(t, []digest.Digest{"content"}, r.Keys)
+
+	r = vs.cacheMissReason([]digest.Digest{"content"}, true)
+	require.Equal(t, client.CacheMissInputChanged, r.Reason)
+
+	k, err := vs.lastCacheKey()
+	require.NoError(t, err)
+	pt.pruned[cacheKeyForIndex(k, 0)] = struct{}{}
+	r = vs.cacheMissReason(nil, false)
+	require.Equal(t, client.CacheMissPruned, r.Reason)
+	require.Equal(t, []digest.Digest{k}, r.Keys)
+}
@@ -0,0 +1,190 @@
+package solver
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/boltdb/bolt"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/cache/metadata"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+)
+
+const keyCacheDir = "cache-dir"
+
+// cacheMounts manages the persistent directories used by cache mounts. The
+// directories are retained mutable refs that are found with the cache ID
+// from the metadata store.
+type cacheMounts struct {
+	cm     cache.Manager
+	md     *metadata.Store
+	mu     sync.Mutex
+	cond   *sync.Cond
+	shared map[string]*sharedCacheMount
+}
+
+type sharedCacheMount struct {
+	ref   cache.MutableRef
+	count int
+}
+
+func newCacheMounts(cm cache.Manager, md *metadata.Store) *cacheMounts {
+	c := &cacheMounts{
+		cm:     cm,
+		md:     md,
+		shared: map[string]*sharedCacheMount{},
+	}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// get returns a mutable ref for the cache directory id. The returned function
+// needs to be called after the ref is not used anymore.
+func (c *cacheMounts) get(ctx context.Context, id string, sharing pb.CacheSharingOpt) (cache.MutableRef, func(), error) {
+	if id == "" {
+		return nil, nil, errors.Errorf("cache mount requires an id")
+	}
+	if c.md == nil {
+		return nil, nil, errors.Errorf("cache mounts are not supported")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch sharing {
+	case pb.CacheSharingOpt_SHARED:
+		if s, ok := c.shared[id]; ok {
+			s.count++
+			return s.ref, c.releaseShared(id, s), nil
+		}
+		ref, err := c.getFree(ctx, id, true)
+		if err != nil {
+			return nil, nil, err
+		}
+		s := &sharedCacheMount{ref: ref, count: 1}
+		c.shared[id] = s
+		return ref, c.releaseShared(id, s), nil
+	case pb.CacheSharingOpt_PRIVATE:
+		ref, err := c.getFree(ctx, id, true)
+		if err != nil {
+			return nil, nil, err
+		}
+		return ref, c.release(ref), nil
+	case pb.CacheSharingOpt_LOCKED:
+		for {
+			ref, err := c.getFree(ctx, id, false)
+			if err != nil {
+				return nil, nil, err
+			}
+			if ref != nil {
+				return ref, c.release(ref), nil
+			}
+			if err := c.wait(ctx); err != nil {
+				return nil, nil, err
+			}
+		}
+	default:
+		return nil, nil, errors.Errorf("invalid cache sharing option %v", sharing)
+	}
+}
+
+// getFree returns a cache directory for id that is not in use. If all
+// existing directories are in use a new one is only created if create is set,
+// otherwise nil is returned. Hold c.mu before calling.
+func (c *cacheMounts) getFree(ctx context.Context, id string, create bool) (cache.MutableRef, error) {
+	key := keyCacheDir + "::" + id
+	sis, err := c.md.Search(key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to search metadata for cache mount %s", id)
+	}
+	for _, si := range sis {
+		ref, err := c.cm.GetMutable(ctx, si.ID())
+		if err != nil {
+			if cache.IsLocked(err) {
+				continue
+			}
+			if cache.IsNotFound(err) {
+				c.md.Clear(si.ID())
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to get mutable ref for cache mount %s", id)
+		}
+		return ref, nil
+	}
+	if len(sis) > 0 && !create {
+		return nil, nil
+	}
+
+	ref, err := c.cm.New(ctx, nil, cache.CachePolicyRetain, cache.WithDescription(fmt.Sprintf("cached mount %s", id)))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create new cache mount for %s", id)
+	}
+	si, _ := c.md.Get(ref.ID())
+	v, err := metadata.NewValue(key)
+	if err != nil {
+		ref.Release(context.TODO())
+		return nil, err
+	}
+	v.Index = key
+	if err := si.Update(func(b *bolt.Bucket) error {
+		return si.SetValue(b, keyCacheDir, v)
+	}); err != nil {
+		ref.Release(context.TODO())
+		return nil, err
+	}
+	return ref, nil
+}
+
+// wait blocks until a cache directory has been released. Hold c.mu before
+// calling.
+func (c *cacheMounts) wait(ctx context.Context) error {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.mu.Lock()
+			c.cond.Broadcast()
+			c.mu.Unlock()
+		case <-done:
+		}
+	}()
+	c.cond.Wait()
+	return ctx.Err()
+}
+
+func (c *cacheMounts) release(ref cache.MutableRef) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			if err := ref.Release(context.TODO()); err != nil {
+				logrus.Errorf("failed to release cache mount %s: %v", ref.ID(), err)
+			}
+			c.cond.Broadcast()
+		})
+	}
+}
+
+func (c *cacheMounts) releaseShared(id string, s *sharedCacheMount) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			s.count--
+			if s.count > 0 {
+				return
+			}
+			delete(c.shared, id)
+			if err := s.ref.Release(context.TODO()); err != nil {
+				logrus.Errorf("failed to release cache mount %s: %v", s.ref.ID(), err)
+			}
+			c.cond.Broadcast()
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package solver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/containerd/containerd/namespaces"
+	"github.com/containerd/containerd/snapshot/naive"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/cache/metadata"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestCacheMounts(t *testing.T) {
+	ctx := namespaces.WithNamespace(context.Background(), "buildkit-test")
+
+	tmpdir, err := ioutil.TempDir("", "cachemounts")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	cms := newTestCacheMounts(t, tmpdir)
+
+	ref1, release1, err := cms.get(ctx, "foo", pb.CacheSharingOpt_SHARED)
+	require.NoError(t, err)
+	ref2, release2, err := cms.get(ctx, "foo", pb.CacheSharingOpt_SHARED)
+	require.NoError(t, err)
+	require.Equal(t, ref1.ID(), ref2.ID())
+
+	// private mount doesn't reuse the directory in use
+	ref3, release3, err := cms.get(ctx, "foo", pb.CacheSharingOpt_PRIVATE)
+	require.NoError(t, err)
+	require.NotEqual(t, ref1.ID(), ref3.ID())
+	release3()
+
+	release1()
+	release2()
+
+	// released directories are kept and reused
+	ref4, release4, err := cms.get(ctx, "foo", pb.CacheSharingOpt_LOCKED)
+	require.NoError(t, err)
+
+	locked := make(chan string)
+	go func() {
+		ref, release, err := cms.get(ctx, "foo", pb.CacheSharingOpt_LOCKED)
+		require.NoError(t, err)
+		locked <- ref.ID()
+		release()
+	}()
+
+	// the other private directory can be used
+	id := <-locked
+	require.NotEqual(t, ref4.ID(), id)
+	require.Contains(t, []string{ref1.ID(), ref3.ID()}, id)
+
+	ref5, release5, err := cms.get(ctx, "foo", pb.CacheSharingOpt_LOCKED)
+	require.NoError(t, err)
+
+	go func() {
+		ref, release, err := cms.get(ctx, "foo", pb.CacheSharingOpt_LOCKED)
+		require.NoError(t, err)
+		locked <- ref.ID()
+		release()
+	}()
+
+	select {
+	case <-locked:
+		t.Fatal("locked cache mount was acquired twice")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	release5()
+	require.Equal(t, ref5.ID(), <-locked)
+	release4()
+
+	ctx2, cancel := context.WithCancel(ctx)
+	_, release6, err := cms.get(ctx, "foo", pb.CacheSharingOpt_LOCKED)
+	require.NoError(t, err)
+	_, release7, err := cms.get(ctx, "foo", pb.CacheSharingOpt_LOCKED)
+	require.NoError(t, err)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	_, _, err = cms.get(ctx2, "foo", pb.CacheSharingOpt_LOCKED)
+	require.Equal(t, context.Canceled, err)
+	release6()
+	release7()
+
+	_, _, err = cms.get(ctx, "", pb.CacheSharingOpt_SHARED)
+	require.Error(t, err)
+}
+
+func newTestCacheMounts(t *testing.T, tmpdir string) *cacheMounts {
+	snapshotter, err := naive.NewSnapshotter(filepath.Join(tmpdir, "snapshots"))
+	require.NoError(t, err)
+
+	md, err := metadata.NewStore(filepath.Join(tmpdir, "metadata.db"))
+	require.NoError(t, err)
+
+	cm, err := cache.NewManager(cache.ManagerOpt{
+		Snapshotter:   snapshotter,
+		MetadataStore: md,
+	})
+	require.NoError(t, err)
+	return newCacheMounts(cm, md)
+}
@@ -0,0 +1,52 @@
+package solver
+
+import (
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// defaultCgroupParent is the cgroup of the daemon the cgroups of the builds
+// are created under if the daemon has not set one
+const defaultCgroupParent = "/buildkit"
+
+type cgroupParentKeyT string
+
+var cgroupParentKey = cgroupParentKeyT("buildkit/solver/cgroupparent")
+
+func withCgroupParentFunc(ctx context.Context, fn func() string) context.Context {
+	return context.WithValue(ctx, cgroupParentKey, fn)
+}
+
+// cgroupParentFromContext returns the cgroup of the build the op runs for
+func cgroupParentFromContext(ctx context.Context) string {
+	if fn, ok := ctx.Value(cgroupParentKey).(func() string); ok {
+		return fn()
+	}
+	return ""
+}
+
+// validateCgroupParent checks that the cgroup of a build is a relative path
+// that stays under the cgroup of the daemon
+func validateCgroupParent(p string) error {
+	if p == "" {
+		return nil
+	}
+	if path.IsAbs(p) || path.Clean(p) != p || p == "." || strings.HasPrefix(p, "../") || p == ".." {
+		return errors.Errorf("invalid cgroup parent %s, a relative path is required", p)
+	}
+	return nil
+}
+
+// cgroupParent returns the cgroup the execs of a build are created under
+func cgroupParent(daemon, build string) string {
+	if build == "" {
+		return daemon
+	}
+	if daemon == "" {
+		daemon = defaultCgroupParent
+	}
+	return path.Join(daemon, build)
+}
@@ -0,0 +1,38 @@
+package solver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moby/buildkit/client"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestCgroupParent(t *testing.T) {
+	for _, p := range []string{"", "tenant", "tenant/build-1"} {
+		require.NoError(t, validateCgroupParent(p))
+	}
+	for _, p := range []string{"/tenant", "..", "../tenant", "tenant/../..", "tenant/", "."} {
+		require.Error(t, validateCgroupParent(p), p)
+	}
+
+	require.Equal(t, "", cgroupParent("", ""))
+	require.Equal(t, "/daemon", cgroupParent("/daemon", ""))
+	require.Equal(t, "/daemon/tenant", cgroupParent("/daemon", "tenant"))
+	require.Equal(t, "/buildkit/tenant", cgroupParent("", "tenant"))
+}
+
+func TestSolveStatusClosedOnEarlyError(t *testing.T) {
+	s := &Solver{jobs: newJobList(nil)}
+	ch := make(chan *client.SolveStatus)
+	_, err := s.Solve(context.TODO(), "ref", SolveRequest{CgroupParent: "/tenant", Status: ch})
+	require.Error(t, err)
+
+	select {
+	case _, ok := <-ch:
+		require.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("status of the failed solve was not closed")
+	}
+}
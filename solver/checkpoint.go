@@ -0,0 +1,243 @@
+package solver
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/boltdb/bolt"
+	"github.com/containerd/containerd/fs"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/cache/metadata"
+	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/snapshot"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+const keyCheckpoint = "exec-checkpoint"
+
+type cacheKeyT string
+
+var cacheKeyKey = cacheKeyT("buildkit/solver/cachekey")
+
+// withCacheKey sets the cache key of the vertex that is run with ctx
+func withCacheKey(ctx context.Context, key digest.Digest) context.Context {
+	return context.WithValue(ctx, cacheKeyKey, key)
+}
+
+func cacheKeyFromContext(ctx context.Context) digest.Digest {
+	key, _ := ctx.Value(cacheKeyKey).(digest.Digest)
+	return key
+}
+
+// checkpoints manages the intermediate snapshots of the root mounts of long
+// running execs. The snapshots are committed refs that are found with the
+// cache key of the exec from the metadata store, so an exec that didn't
+// complete before the daemon stopped can be run again on top of the files
+// it had created.
+type checkpoints struct {
+	cm cache.Manager
+	md *metadata.Store
+}
+
+func newCheckpoints(cm cache.Manager, md *metadata.Store) *checkpoints {
+	if md == nil {
+		return nil
+	}
+	return &checkpoints{cm: cm, md: md}
+}
+
+func checkpointIndex(key digest.Digest) string {
+	return keyCheckpoint + "::" + key.String()
+}
+
+// latest returns the most recent checkpoint for key and its sequence number.
+// The ref is nil if there are no checkpoints.
+func (c *checkpoints) latest(ctx context.Context, key digest.Digest) (cache.ImmutableRef, int, error) {
+	sis, err := c.md.Search(checkpointIndex(key))
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "failed to search checkpoints of %s", key)
+	}
+	var latest cache.ImmutableRef
+	var latestSeq int
+	for _, si := range sis {
+		var seq int
+		if v := si.Get(keyCheckpoint); v != nil {
+			if err := v.Unmarshal(&seq); err != nil {
+				continue
+			}
+		}
+		if latest != nil && seq <= latestSeq {
+			continue
+		}
+		ref, err := c.cm.Get(ctx, si.ID())
+		if err != nil {
+			if cache.IsNotFound(err) {
+				c.md.Clear(si.ID())
+				continue
+			}
+			return nil, 0, errors.Wrapf(err, "failed to get checkpoint %s", si.ID())
+		}
+		if latest != nil {
+			latest.Release(context.TODO())
+		}
+		latest, latestSeq = ref, seq
+	}
+	return latest, latestSeq, nil
+}
+
+// save stores the changes of active as the checkpoint seq for key. The
+// checkpoint is a ref on top of the previous checkpoint of key, or of base
+// for the first one, so only the changes since the previous checkpoint are
+// copied.
+func (c *checkpoints) save(ctx context.Context, key digest.Digest, seq int, base cache.ImmutableRef, active cache.MutableRef) error {
+	parent := base
+	prev, _, err := c.latest(ctx, key)
+	if err != nil {
+		return err
+	}
+	if prev != nil {
+		defer prev.Release(context.TODO())
+		parent = prev
+	}
+	ref, err := c.cm.New(ctx, parent, cache.WithDescription(fmt.Sprintf("checkpoint %d of exec %s", seq, key)))
+	if err != nil {
+		return err
+	}
+	if err := copyChanges(ctx, ref, parent, active); err != nil {
+		ref.Release(context.TODO())
+		return errors.Wrapf(err, "failed to copy changes of %s", active.ID())
+	}
+	cp, err := ref.Commit(ctx)
+	if err != nil {
+		ref.Release(context.TODO())
+		return err
+	}
+	defer cp.Release(context.TODO())
+	// finalized refs are kept after they are released
+	if err := cp.Finalize(ctx); err != nil {
+		return err
+	}
+
+	si, _ := c.md.Get(cp.ID())
+	v, err := metadata.NewValue(seq)
+	if err != nil {
+		return err
+	}
+	v.Index = checkpointIndex(key)
+	return si.Update(func(b *bolt.Bucket) error {
+		return si.SetValue(b, keyCheckpoint, v)
+	})
+}
+
+// clear removes the checkpoints of key. The refs of the checkpoints are
+// deleted unless they are still used, e.g. by an exec that resumed from them.
+// The newest checkpoints are deleted first as they are the children of the
+// older ones.
+func (c *checkpoints) clear(ctx context.Context, key digest.Digest) error {
+	sis, err := c.md.Search(checkpointIndex(key))
+	if err != nil {
+		return errors.Wrapf(err, "failed to search checkpoints of %s", key)
+	}
+	seqs := make(map[string]int, len(sis))
+	for _, si := range sis {
+		var seq int
+		if v := si.Get(keyCheckpoint); v != nil {
+			v.Unmarshal(&seq)
+		}
+		seqs[si.ID()] = seq
+	}
+	sort.Slice(sis, func(i, j int) bool {
+		return seqs[sis[i].ID()] > seqs[sis[j].ID()]
+	})
+	for _, si := range sis {
+		if err := si.Update(func(b *bolt.Bucket) error {
+			return si.SetValue(b, keyCheckpoint, nil)
+		}); err != nil {
+			return err
+		}
+		if err := c.cm.Prune(ctx, nil, client.PruneInfo{Filter: []string{"id=" + si.ID()}}); err != nil {
+			return errors.Wrapf(err, "failed to delete checkpoint %s", si.ID())
+		}
+	}
+	return nil
+}
+
+// copyChanges applies the changes between parent and src to dest, which has
+// the files of parent
+func copyChanges(ctx context.Context, dest cache.Mountable, parent cache.ImmutableRef, src cache.Mountable) error {
+	destDir, unmountDest, err := mountDir(ctx, dest, false)
+	if err != nil {
+		return err
+	}
+	defer unmountDest()
+	srcDir, unmountSrc, err := mountDir(ctx, src, true)
+	if err != nil {
+		return err
+	}
+	defer unmountSrc()
+	var parentDir string
+	if parent != nil {
+		var unmount func() error
+		parentDir, unmount, err = mountDir(ctx, parent, true)
+		if err != nil {
+			return err
+		}
+		defer unmount()
+	}
+
+	return fs.Changes(ctx, parentDir, srcDir, func(kind fs.ChangeKind, p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(destDir, p)
+		if kind == fs.ChangeKindDelete {
+			return os.RemoveAll(target)
+		}
+		if tfi, err := os.Lstat(target); err == nil && !(fi.IsDir() && tfi.IsDir()) {
+			if err := os.RemoveAll(target); err != nil {
+				return err
+			}
+		}
+		link := false
+		switch {
+		case fi.IsDir():
+			if err := os.Mkdir(target, defaultDirMode); err != nil && !os.IsExist(err) {
+				return errors.Wrapf(err, "failed to create directory %s", target)
+			}
+		case fi.Mode()&os.ModeSymlink != 0:
+			link = true
+			l, err := os.Readlink(filepath.Join(srcDir, p))
+			if err != nil {
+				return errors.Wrapf(err, "failed to read link %s", p)
+			}
+			if err := os.Symlink(l, target); err != nil {
+				return errors.Wrapf(err, "failed to create symlink %s", target)
+			}
+		case fi.Mode().IsRegular():
+			if err := copyFileContent(filepath.Join(srcDir, p), target); err != nil {
+				return err
+			}
+		default:
+			return errors.Errorf("unsupported file type of %s", p)
+		}
+		mode := fi.Mode() & (os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky)
+		return setFileMeta(target, mode, fileOwner(fi), fi.ModTime(), link)
+	})
+}
+
+func mountDir(ctx context.Context, m cache.Mountable, readonly bool) (string, func() error, error) {
+	mounts, err := m.Mount(ctx, readonly)
+	if err != nil {
+		return "", nil, err
+	}
+	lm := snapshot.LocalMounter(mounts)
+	dir, err := lm.Mount()
+	if err != nil {
+		return "", nil, err
+	}
+	return dir, lm.Unmount, nil
+}
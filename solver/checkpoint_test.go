@@ -0,0 +1,121 @@
+package solver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containerd/containerd/fs"
+	"github.com/containerd/containerd/namespaces"
+	"github.com/moby/buildkit/cache"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestCheckpoints(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("requires root to mount the refs read-only")
+	}
+	ctx := namespaces.WithNamespace(context.Background(), "buildkit-test")
+
+	tmpdir, err := ioutil.TempDir("", "checkpoints")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	cms := newTestCacheMounts(t, tmpdir)
+	cps := newCheckpoints(cms.cm, cms.md)
+	key := digest.FromBytes([]byte("exec"))
+
+	ref, seq, err := cps.latest(ctx, key)
+	require.NoError(t, err)
+	require.Nil(t, ref)
+
+	m, err := cms.cm.New(ctx, nil)
+	require.NoError(t, err)
+	writeTestFiles(t, ctx, m, map[string]string{"a": "a", "b": "b"}, nil)
+	base, err := m.Commit(ctx)
+	require.NoError(t, err)
+	defer base.Release(context.TODO())
+
+	active, err := cms.cm.New(ctx, base)
+	require.NoError(t, err)
+	defer active.Release(context.TODO())
+	writeTestFiles(t, ctx, active, map[string]string{"b": "b2", "dir/c": "c"}, []string{"a"})
+
+	require.NoError(t, cps.save(ctx, key, 1, base, active))
+	ref, _, err = cps.latest(ctx, key)
+	require.NoError(t, err)
+	first := ref.ID()
+	require.NoError(t, ref.Release(context.TODO()))
+	writeTestFiles(t, ctx, active, map[string]string{"d": "d"}, nil)
+	require.NoError(t, cps.save(ctx, key, 2, base, active))
+
+	// the latest checkpoint is used and is on top of the previous one
+	ref, seq, err = cps.latest(ctx, key)
+	require.NoError(t, err)
+	require.NotNil(t, ref)
+	require.Equal(t, 2, seq)
+	prev := ref.Parent()
+	require.NotNil(t, prev)
+	require.Equal(t, first, prev.ID())
+	require.Equal(t, base.ID(), prev.Parent().ID())
+
+	// only the changes since the previous checkpoint are copied
+	prevDir, unmountPrev, err := mountDir(ctx, prev, true)
+	require.NoError(t, err)
+	refDir, unmountRef, err := mountDir(ctx, ref, true)
+	require.NoError(t, err)
+	var changes []string
+	require.NoError(t, fs.Changes(ctx, prevDir, refDir, func(kind fs.ChangeKind, p string, fi os.FileInfo, err error) error {
+		changes = append(changes, kind.String()+" "+p)
+		return err
+	}))
+	require.Equal(t, []string{"add /d"}, changes)
+	require.NoError(t, unmountRef())
+	require.NoError(t, unmountPrev())
+	require.NoError(t, prev.Release(context.TODO()))
+
+	dir, unmount, err := mountDir(ctx, ref, true)
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dir, "a"))
+	require.True(t, os.IsNotExist(err))
+	for p, dt := range map[string]string{"b": "b2", "dir/c": "c", "d": "d"} {
+		got, err := ioutil.ReadFile(filepath.Join(dir, p))
+		require.NoError(t, err)
+		require.Equal(t, dt, string(got))
+	}
+	require.NoError(t, unmount())
+	latest := ref.ID()
+	require.NoError(t, ref.Release(context.TODO()))
+
+	require.NoError(t, cps.clear(ctx, key))
+	ref, _, err = cps.latest(ctx, key)
+	require.NoError(t, err)
+	require.Nil(t, ref)
+	// all the checkpoints of the chain are deleted
+	for _, id := range []string{latest, first} {
+		_, err = cms.cm.Get(ctx, id)
+		require.True(t, cache.IsNotFound(err))
+	}
+}
+
+func TestCacheKeyFromContext(t *testing.T) {
+	require.Equal(t, digest.Digest(""), cacheKeyFromContext(context.TODO()))
+	key := digest.FromBytes([]byte("foo"))
+	require.Equal(t, key, cacheKeyFromContext(withCacheKey(context.TODO(), key)))
+}
+
+func writeTestFiles(t *testing.T, ctx context.Context, m cache.Mountable, files map[string]string, remove []string) {
+	dir, unmount, err := mountDir(ctx, m, false)
+	require.NoError(t, err)
+	defer unmount()
+	for p, dt := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, p)), 0755))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, p), []byte(dt), 0644))
+	}
+	for _, p := range remove {
+		require.NoError(t, os.Remove(filepath.Join(dir, p)))
+	}
+}
@@ -0,0 +1,46 @@
+package solver
+
+import (
+	"io"
+
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/session/debugshell"
+	"github.com/moby/buildkit/worker"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+)
+
+// debug starts a debug shell with the rootfs and mounts of a failed exec if
+// the client session supports it. It returns after the shell has exited so
+// the mounts stay alive until then.
+func (e *execOp) debug(ctx context.Context, meta worker.Meta, root cache.Mountable, mounts []worker.Mount, execErr error) {
+	if e.sm == nil {
+		return
+	}
+	caller, err := sessionCaller(ctx, e.sm)
+	if err != nil || !debugshell.Supported(caller) {
+		return
+	}
+	req := &debugshell.PrepareRequest{
+		Args:  meta.Args,
+		Error: execErr.Error(),
+	}
+	if e.v != nil {
+		req.Vertex = e.v.Digest().String()
+	}
+	err = debugshell.Run(ctx, caller, req, func(ctx context.Context, args []string, stdin io.ReadCloser, stdout, stderr io.WriteCloser) (int, error) {
+		m := meta
+		m.Args = args
+		if err := e.w.Exec(ctx, m, root, mounts, stdin, stdout, stderr); err != nil {
+			if exitErr, ok := errors.Cause(err).(*worker.ExitError); ok {
+				return int(exitErr.ExitCode), nil
+			}
+			return 0, err
+		}
+		return 0, nil
+	})
+	if err != nil {
+		logrus.Errorf("debug shell for %v failed: %v", meta.Args, err)
+	}
+}
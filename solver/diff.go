@@ -0,0 +1,246 @@
+package solver
+
+import (
+	gocontext "context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/differ"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/mount"
+	"github.com/containerd/containerd/rootfs"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/identity"
+	"github.com/moby/buildkit/solver/pb"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+)
+
+const diffCacheType = "buildkit.diff.v0"
+
+// diffOp creates the changes between two inputs with the differ used for the
+// image layers and applies them to a new snapshot without a parent. Deletions
+// can't be represented in a snapshot and are not part of the result.
+type diffOp struct {
+	v       Vertex
+	op      *pb.DiffOp
+	cm      cache.Manager
+	differ  rootfs.MountDiffer
+	applier rootfs.Applier
+	// cs is the content store of the differ. The diffs are written to it by a
+	// walking differ that tracks the blobs it creates, so that they can be
+	// removed after they are applied. Without it the blobs are kept.
+	cs content.Store
+}
+
+func newDiffOp(v Vertex, op *pb.Op_Diff, cm cache.Manager, differ rootfs.MountDiffer, applier rootfs.Applier, cs content.Store) (Op, error) {
+	numInputs := len(v.Inputs())
+	if op.Diff.Lower != pb.Empty {
+		if err := checkInputIndex(vertexDigest(v), "lower", op.Diff.Lower, numInputs); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	if err := checkInputIndex(vertexDigest(v), "upper", op.Diff.Upper, numInputs); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &diffOp{
+		v:       v,
+		op:      op.Diff,
+		cm:      cm,
+		differ:  differ,
+		applier: applier,
+		cs:      cs,
+	}, nil
+}
+
+func (d *diffOp) CacheKey(ctx context.Context) (digest.Digest, error) {
+	dt, err := json.Marshal(struct {
+		Type string
+		Diff *pb.DiffOp
+	}{
+		Type: diffCacheType,
+		Diff: d.op,
+	})
+	if err != nil {
+		return "", err
+	}
+	return digest.FromBytes(dt), nil
+}
+
+func (d *diffOp) ContentKeys(context.Context, [][]digest.Digest, []Reference) ([]digest.Digest, error) {
+	return nil, nil
+}
+
+func (d *diffOp) Run(ctx context.Context, inputs []Reference) ([]Reference, error) {
+	if d.differ == nil || d.applier == nil {
+		return nil, errors.Errorf("diff op is not supported by the worker")
+	}
+
+	upper, ok := toImmutableRef(inputs[d.op.Upper])
+	if !ok {
+		return nil, errors.Errorf("invalid reference for diff op %T", inputs[d.op.Upper])
+	}
+	var lower cache.ImmutableRef
+	if d.op.Lower != pb.Empty {
+		lower, ok = toImmutableRef(inputs[d.op.Lower])
+		if !ok {
+			return nil, errors.Errorf("invalid reference for diff op %T", inputs[d.op.Lower])
+		}
+	}
+
+	// a ref without a parent is its own diff from an empty state
+	if lower == nil {
+		resolved, err := cache.Resolve(ctx, upper)
+		if err != nil {
+			return nil, err
+		}
+		parent := resolved.Parent()
+		if parent == nil {
+			return []Reference{newSharedRef(upper).Clone()}, nil
+		}
+		parent.Release(context.TODO())
+	}
+
+	var lowerMounts []mount.Mount
+	if lower != nil {
+		m, err := lower.Mount(ctx, true)
+		if err != nil {
+			return nil, err
+		}
+		lowerMounts = m
+	}
+	upperMounts, err := upper.Mount(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	df := d.differ
+	var ts *trackingStore
+	if d.cs != nil {
+		ts = &trackingStore{Store: d.cs, blobs: diffBlobs}
+		wd, err := differ.NewWalkingDiff(ts)
+		if err != nil {
+			return nil, err
+		}
+		df = wd
+	}
+	// the ingests of the concurrent diffs of the same ref can't be shared
+	desc, err := df.DiffMounts(ctx, lowerMounts, upperMounts, ocispec.MediaTypeImageLayer, fmt.Sprintf("diff-%s-%s", upper.ID(), identity.NewID()))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to compute diff")
+	}
+	if ts != nil {
+		defer ts.release(context.TODO())
+	}
+
+	active, err := d.cm.New(ctx, nil, cache.WithDescription(fmt.Sprintf("diff %s", desc.Digest)))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if active != nil {
+			active.Release(context.TODO())
+		}
+	}()
+
+	mounts, err := active.Mount(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := d.applier.Apply(ctx, desc, mounts); err != nil {
+		return nil, errors.Wrapf(err, "failed to apply diff %s", desc.Digest)
+	}
+
+	ref, err := active.Commit(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error committing %s", active.ID())
+	}
+	active = nil
+	return []Reference{ref}, nil
+}
+
+// diffBlobs are the blobs created by the diffs that are running
+var diffBlobs = &blobRefs{refs: map[digest.Digest]int{}}
+
+// blobRefs counts the running diffs that use the blobs that one of them
+// created. A blob is removed when the last of them is done with it.
+type blobRefs struct {
+	mu   sync.Mutex
+	refs map[digest.Digest]int
+}
+
+// trackingStore is the content store of the walking differ of a diff. It
+// records the blob the diff wrote if it wasn't in the store before.
+type trackingStore struct {
+	content.Store
+	blobs *blobRefs
+	dgst  digest.Digest
+}
+
+func (s *trackingStore) Writer(ctx gocontext.Context, ref string, size int64, expected digest.Digest) (content.Writer, error) {
+	w, err := s.Store.Writer(ctx, ref, size, expected)
+	if err != nil {
+		return nil, err
+	}
+	dgstr := digest.Canonical.Digester()
+	return &trackingWriter{Writer: w, store: s, digester: dgstr, w: io.MultiWriter(w, dgstr.Hash())}, nil
+}
+
+// release removes the blob the diff created once no other running diff uses
+// it
+func (s *trackingStore) release(ctx context.Context) {
+	if s.dgst == "" {
+		return
+	}
+	s.blobs.mu.Lock()
+	defer s.blobs.mu.Unlock()
+	s.blobs.refs[s.dgst]--
+	if s.blobs.refs[s.dgst] > 0 {
+		return
+	}
+	delete(s.blobs.refs, s.dgst)
+	if err := s.Store.Delete(ctx, s.dgst); err != nil && !errdefs.IsNotFound(err) {
+		logrus.Warnf("failed to remove diff blob %s: %v", s.dgst, err)
+	}
+}
+
+type trackingWriter struct {
+	content.Writer
+	store    *trackingStore
+	digester digest.Digester
+	w        io.Writer
+}
+
+func (w *trackingWriter) Write(p []byte) (int, error) {
+	return w.w.Write(p)
+}
+
+func (w *trackingWriter) Commit(ctx gocontext.Context, size int64, expected digest.Digest, opts ...content.Opt) error {
+	dgst := w.digester.Digest()
+	b := w.store.blobs
+	// the check of the blob and the commit can't interleave with the ones
+	// of the other diffs
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.refs[dgst] == 0 {
+		if _, err := w.store.Info(ctx, dgst); err == nil {
+			// eg. the layer of an image, it is kept
+			return w.Writer.Commit(ctx, size, expected, opts...)
+		} else if !errdefs.IsNotFound(err) {
+			return err
+		}
+	}
+	// the blob of another running diff is the same content
+	if err := w.Writer.Commit(ctx, size, expected, opts...); err != nil && !(b.refs[dgst] > 0 && errdefs.IsAlreadyExists(err)) {
+		return err
+	}
+	b.refs[dgst]++
+	w.store.dgst = dgst
+	return nil
+}
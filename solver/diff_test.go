@@ -0,0 +1,86 @@
+package solver
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/content/local"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/moby/buildkit/solver/pb"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestDiffOpInputs(t *testing.T) {
+	v := &vertex{inputs: []*input{{}, {}}}
+
+	_, err := newDiffOp(v, &pb.Op_Diff{Diff: &pb.DiffOp{Lower: 0, Upper: 1}}, nil, nil, nil, nil)
+	require.NoError(t, err)
+	_, err = newDiffOp(v, &pb.Op_Diff{Diff: &pb.DiffOp{Lower: pb.Empty, Upper: 1}}, nil, nil, nil, nil)
+	require.NoError(t, err)
+	_, err = newDiffOp(v, &pb.Op_Diff{Diff: &pb.DiffOp{Lower: 0, Upper: 2}}, nil, nil, nil, nil)
+	require.Error(t, err)
+	_, err = newDiffOp(v, &pb.Op_Diff{Diff: &pb.DiffOp{Lower: 2, Upper: 0}}, nil, nil, nil, nil)
+	require.Error(t, err)
+
+	// the worker needs the differ to run the op
+	op, err := newDiffOp(v, &pb.Op_Diff{Diff: &pb.DiffOp{Lower: 0, Upper: 1}}, nil, nil, nil, nil)
+	require.NoError(t, err)
+	_, err = op.Run(context.TODO(), nil)
+	require.Error(t, err)
+}
+
+func TestDiffOpRemoveBlob(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "diffblob")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	cs, err := local.NewStore(tmpdir)
+	require.NoError(t, err)
+	ctx := context.TODO()
+	blobs := &blobRefs{refs: map[digest.Digest]int{}}
+
+	// a blob that was in the store before the diff is kept
+	image := digest.FromString("image")
+	require.NoError(t, content.WriteBlob(ctx, cs, "image", strings.NewReader("image"), 0, ""))
+	s := &trackingStore{Store: cs, blobs: blobs}
+	writeDiffBlob(t, s, "diff-1", "image")
+	require.Equal(t, digest.Digest(""), s.dgst)
+	s.release(ctx)
+	_, err = cs.Info(ctx, image)
+	require.NoError(t, err)
+
+	// the blob created by the diff is removed
+	s = &trackingStore{Store: cs, blobs: blobs}
+	writeDiffBlob(t, s, "diff-2", "created")
+	require.Equal(t, digest.FromString("created"), s.dgst)
+	s.release(ctx)
+	_, err = cs.Info(ctx, digest.FromString("created"))
+	require.True(t, errdefs.IsNotFound(err))
+
+	// the blob created by two diffs is removed after both are done
+	s1 := &trackingStore{Store: cs, blobs: blobs}
+	s2 := &trackingStore{Store: cs, blobs: blobs}
+	writeDiffBlob(t, s1, "diff-3", "shared")
+	writeDiffBlob(t, s2, "diff-4", "shared")
+	s1.release(ctx)
+	_, err = cs.Info(ctx, digest.FromString("shared"))
+	require.NoError(t, err)
+	s2.release(ctx)
+	_, err = cs.Info(ctx, digest.FromString("shared"))
+	require.True(t, errdefs.IsNotFound(err))
+	require.Equal(t, 0, len(blobs.refs))
+}
+
+func writeDiffBlob(t *testing.T, s *trackingStore, ref, data string) {
+	w, err := s.Writer(context.TODO(), ref, 0, "")
+	require.NoError(t, err)
+	_, err = w.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, w.Commit(context.TODO(), 0, ""))
+	require.NoError(t, w.Close())
+}
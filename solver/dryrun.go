@@ -0,0 +1,244 @@
+package solver
+
+import (
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/util/progress"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// DryRunReport describes how a definition would be solved
+type DryRunReport struct {
+	// Vertexes are sorted so that the inputs of a vertex come before it
+	Vertexes []*DryRunVertex
+	// Work is the number of vertexes that would need to run
+	Work int
+}
+
+// DryRunVertex describes the cache state of a single vertex
+type DryRunVertex struct {
+	Digest digest.Digest
+	Name   string
+	// OpType is the type of the operation of the vertex, eg. "exec"
+	OpType string
+	Inputs []digest.Digest
+	// CacheKey is the cache key of the vertex without the output index
+	CacheKey digest.Digest
+	// InputKeys are the cache keys of the inputs that CacheKey was computed
+	// from
+	InputKeys []digest.Digest
+	// ContentKeys are only computed if all the inputs are cached
+	ContentKeys []digest.Digest
+	Cached      bool
+	// Required is set if the vertex would need to run
+	Required bool
+}
+
+// DryRun computes the cache keys of the definition in req and reports which
+// vertexes would be loaded from the cache. Nothing is executed. Frontends and
+// exporters are not supported as they would need the build results.
+func (s *Solver) DryRun(ctx context.Context, id string, req SolveRequest) (*DryRunReport, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pr, ctx, closeProgressWriter := progress.NewContext(ctx)
+	defer closeProgressWriter()
+
+	v := req.Definition
+	if v == nil || req.Frontend != nil {
+		return nil, errors.Errorf("dry run requires a definition without a frontend")
+	}
+	if req.Exporter != nil || len(req.Exports) > 0 || len(req.CacheExports) > 0 {
+		return nil, errors.Errorf("dry run can't be exported")
+	}
+	if len(v.Inputs()) == 0 {
+		return nil, errors.New("required vertex needs to have inputs")
+	}
+	index := v.Inputs()[0].Index
+	vv := toInternalVertex(v.Inputs()[0].Vertex)
+
+	cache, releaseCache, err := s.solveCache(ctx, req, buildPlatform(vv, nil))
+	if err != nil {
+		return nil, err
+	}
+	defer releaseCache()
+
+	ctx, j, err := s.jobs.new(ctx, id, pr, cache, req.Entitlements, req.CgroupParent)
+	if err != nil {
+		return nil, err
+	}
+	defer j.discard()
+
+	if err := j.load(vv, s.resolve); err != nil {
+		return nil, err
+	}
+
+	dr := &dryRun{
+		j:        j,
+		cache:    cache,
+		indexes:  map[digest.Digest]map[Index]struct{}{},
+		vertexes: map[digest.Digest]*DryRunVertex{},
+	}
+	dr.addIndex(vv, index)
+	report := &DryRunReport{}
+	if err := dr.walk(ctx, vv, report); err != nil {
+		return nil, err
+	}
+	report.Work = dr.markRequired(vv)
+	return report, nil
+}
+
+type dryRun struct {
+	j        *job
+	cache    InstructionCache
+	indexes  map[digest.Digest]map[Index]struct{} // outputs used from every vertex
+	vertexes map[digest.Digest]*DryRunVertex
+}
+
+func (dr *dryRun) addIndex(v *vertex, index Index) {
+	m, ok := dr.indexes[v.Digest()]
+	if !ok {
+		m = map[Index]struct{}{}
+		dr.indexes[v.Digest()] = m
+	}
+	m[index] = struct{}{}
+}
+
+// walk adds the vertexes, inputs first, to report
+func (dr *dryRun) walk(ctx context.Context, v *vertex, report *DryRunReport) error {
+	if _, ok := dr.vertexes[v.Digest()]; ok {
+		return nil
+	}
+	for _, inp := range v.inputs {
+		dr.addIndex(inp.vertex, inp.index)
+		if err := dr.walk(ctx, inp.vertex, report); err != nil {
+			return err
+		}
+	}
+	dv, err := dr.check(ctx, v)
+	if err != nil {
+		return err
+	}
+	dr.vertexes[v.Digest()] = dv
+	report.Vertexes = append(report.Vertexes, dv)
+	return nil
+}
+
+// check computes the cache keys of v and looks them up from the cache
+func (dr *dryRun) check(ctx context.Context, v *vertex) (*DryRunVertex, error) {
+	dr.j.l.mu.Lock()
+	s, err := dr.j.getSolver(v.Digest())
+	dr.j.l.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	vs, ok := s.(*vertexSolver)
+	if !ok {
+		return nil, errors.Errorf("invalid solver %T for dry run", s)
+	}
+
+	dv := &DryRunVertex{
+		Digest: v.Digest(),
+		Name:   v.Name(),
+		OpType: pb.OpType(v.Sys()),
+	}
+	for _, inp := range v.inputs {
+		dv.Inputs = append(dv.Inputs, inp.vertex.Digest())
+	}
+
+	if _, err := vs.CacheKey(ctx, 0); err != nil {
+		return nil, err
+	}
+	vs.mu.Lock()
+	dv.CacheKey, err = vs.lastCacheKey()
+	for _, inp := range vs.inputs {
+		dv.InputKeys = append(dv.InputKeys, inp.cacheKeys[len(inp.cacheKeys)-1])
+	}
+	vs.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []digest.Digest{dv.CacheKey}
+	// a dry run doesn't change the cache
+	previousKeys, err := vs.previousCacheKeys(ctx, dv.InputKeys, false)
+	if err != nil {
+		return nil, err
+	}
+	keys = append(keys, previousKeys...)
+
+	contentKeys, err := dr.contentKeys(ctx, vs, dv.InputKeys)
+	if err != nil {
+		return nil, err
+	}
+	dv.ContentKeys = contentKeys
+	for _, ck := range contentKeys {
+		cks, err := dr.cache.GetContentMapping(ck)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, cks...)
+	}
+
+	for _, k := range keys {
+		cached, err := dr.probe(ctx, k, dr.indexes[v.Digest()])
+		if err != nil {
+			return nil, err
+		}
+		if cached {
+			dv.Cached = true
+			break
+		}
+	}
+	return dv, nil
+}
+
+// probe returns true if all the outputs in indexes are cached with key
+func (dr *dryRun) probe(ctx context.Context, key digest.Digest, indexes map[Index]struct{}) (bool, error) {
+	for index := range indexes {
+		ok, err := dr.cache.Probe(ctx, cacheKeyForIndex(key, index))
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// contentKeys returns the content based cache keys of vs if all of its inputs
+// can be loaded from the cache
+func (dr *dryRun) contentKeys(ctx context.Context, vs *vertexSolver, inputKeys []digest.Digest) ([]digest.Digest, error) {
+	refs := make([]Reference, 0, len(inputKeys))
+	defer func() {
+		for _, r := range refs {
+			r.Release(context.TODO())
+		}
+	}()
+	for _, k := range inputKeys {
+		v, err := dr.cache.Lookup(ctx, k)
+		if err != nil {
+			return nil, err
+		}
+		ref, ok := v.(Reference)
+		if !ok {
+			return nil, nil
+		}
+		refs = append(refs, ref)
+	}
+	return vs.op.ContentKeys(ctx, [][]digest.Digest{inputKeys}, refs)
+}
+
+// markRequired marks the vertexes that would need to run to solve v and
+// returns their count
+func (dr *dryRun) markRequired(v *vertex) int {
+	dv := dr.vertexes[v.Digest()]
+	if dv.Cached || dv.Required {
+		return 0
+	}
+	dv.Required = true
+	n := 1
+	for _, inp := range v.inputs {
+		n += dr.markRequired(inp.vertex)
+	}
+	return n
+}
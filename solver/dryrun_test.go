@@ -0,0 +1,31 @@
+package solver
+
+import (
+	"testing"
+
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRunMarkRequired(t *testing.T) {
+	base := &vertex{digest: "base"}
+	cached := &vertex{digest: "cached", inputs: []*input{{vertex: base}}}
+	other := &vertex{digest: "other"}
+	root := &vertex{digest: "root", inputs: []*input{{vertex: cached}, {vertex: other}, {vertex: other, index: 1}}}
+
+	dr := &dryRun{vertexes: map[digest.Digest]*DryRunVertex{
+		"base":   {},
+		"cached": {Cached: true},
+		"other":  {},
+		"root":   {},
+	}}
+	require.Equal(t, 2, dr.markRequired(root))
+	require.True(t, dr.vertexes["root"].Required)
+	require.True(t, dr.vertexes["other"].Required)
+	require.False(t, dr.vertexes["cached"].Required)
+	require.False(t, dr.vertexes["base"].Required)
+
+	dr.vertexes["root"].Cached = true
+	dr.vertexes["root"].Required = false
+	require.Equal(t, 0, dr.markRequired(root))
+}
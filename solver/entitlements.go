@@ -0,0 +1,40 @@
+package solver
+
+import (
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/util/entitlements"
+	"github.com/pkg/errors"
+)
+
+// checkEntitlements returns an error if the vertex is an exec that requires
+// entitlements that were not granted to the build
+func checkEntitlements(v *vertex, ents entitlements.Set) error {
+	op, ok := v.Sys().(*pb.Op_Exec)
+	if !ok {
+		return nil
+	}
+	for _, e := range execEntitlements(op.Exec) {
+		if err := ents.Allowed(e); err != nil {
+			return errors.Wrapf(err, "failed to run %s", v.Name())
+		}
+	}
+	return nil
+}
+
+// execEntitlements returns the entitlements required by an exec
+func execEntitlements(e *pb.ExecOp) []entitlements.Entitlement {
+	var l []entitlements.Entitlement
+	if e.Network == pb.NetMode_NET_HOST {
+		l = append(l, entitlements.EntitlementNetworkHost)
+	}
+	if e.Security == pb.SecurityMode_INSECURE {
+		l = append(l, entitlements.EntitlementSecurityInsecure)
+	}
+	if len(e.Devices) > 0 {
+		l = append(l, entitlements.EntitlementDevice)
+	}
+	if p := e.SecurityProfile; p != nil && (p.Seccomp != "" || p.Apparmor != "" || p.SelinuxLabel != "") {
+		l = append(l, entitlements.EntitlementSecurityProfile)
+	}
+	return l
+}
@@ -0,0 +1,55 @@
+package solver
+
+import (
+	"testing"
+
+	"github.com/containerd/containerd/namespaces"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/util/entitlements"
+	"github.com/moby/buildkit/util/progress"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestJobEntitlements(t *testing.T) {
+	ctx := namespaces.WithNamespace(context.Background(), "buildkit-test")
+	_, ic, cleanup := newTestInstructionCache(t)
+	defer cleanup()
+
+	exec := &pb.ExecOp{Meta: &pb.Meta{Args: []string{"true"}}, Network: pb.NetMode_NET_HOST, Devices: []string{"/dev/fuse"}}
+	v := &vertex{digest: "exec", name: "true", sys: &pb.Op_Exec{Exec: exec}}
+	resolve := func(Vertex) (Op, error) { return &failOp{}, nil }
+
+	jl := newJobList(newScheduler(0))
+	newJob := func(id string, ents ...entitlements.Entitlement) *job {
+		s, err := entitlements.WhiteList(ents, ents)
+		require.NoError(t, err)
+		pr, ctx, _ := progress.NewContext(ctx)
+		_, j, err := jl.new(ctx, id, pr, ic, s, "")
+		require.NoError(t, err)
+		return j
+	}
+
+	err := newJob("none").load(v, resolve)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "network.host is not allowed")
+
+	err = newJob("network", entitlements.EntitlementNetworkHost).load(v, resolve)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "device is not allowed")
+
+	j := newJob("all", entitlements.EntitlementNetworkHost, entitlements.EntitlementDevice)
+	require.NoError(t, j.load(v, resolve))
+	defer releaseTestJob(j)
+
+	// the vertex loaded by the other job isn't shared without the entitlements
+	require.Error(t, newJob("shared").load(v, resolve))
+
+	profile := &vertex{digest: "profile", sys: &pb.Op_Exec{Exec: &pb.ExecOp{SecurityProfile: &pb.SecurityProfile{Seccomp: "unconfined"}}}}
+	err = newJob("profile").load(profile, resolve)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "security.profile is not allowed")
+	require.NoError(t, newJob("profile-allowed", entitlements.EntitlementSecurityProfile).load(profile, resolve))
+
+	require.NoError(t, newJob("sandbox").load(&vertex{digest: "sandbox", sys: &pb.Op_Exec{Exec: &pb.ExecOp{Network: pb.NetMode_NET_NONE}}}, resolve))
+}
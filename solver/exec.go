@@ -6,41 +6,142 @@ import (
 	"path"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/containerd/containerd/mount"
+	"github.com/containerd/containerd/platforms"
 	"github.com/moby/buildkit/cache"
 	"github.com/moby/buildkit/cache/contenthash"
+	"github.com/moby/buildkit/session"
 	"github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/util/progress"
 	"github.com/moby/buildkit/util/progress/logs"
+	"github.com/moby/buildkit/util/system"
 	"github.com/moby/buildkit/worker"
 	digest "github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	"golang.org/x/net/context"
 	"golang.org/x/sync/errgroup"
 )
 
-const execCacheType = "buildkit.exec.v0"
+const (
+	execCacheTypeV0 = "buildkit.exec.v0"
+	// v1 keys are computed from the normalized meta of the exec
+	execCacheType = "buildkit.exec.v1"
+)
+
+func init() {
+	registerCacheKeyVersions("exec", execCacheType, execCacheTypeV0)
+}
 
 type execOp struct {
-	op *pb.ExecOp
-	cm cache.Manager
-	w  worker.Worker
+	v           Vertex
+	op          *pb.ExecOp
+	cm          cache.Manager
+	w           worker.Worker
+	cacheMounts *cacheMounts
+	sm          *session.Manager
+	opt         execOpt
 }
 
-func newExecOp(_ Vertex, op *pb.Op_Exec, cm cache.Manager, w worker.Worker) (Op, error) {
+// execOpt is the daemon configuration for running execs
+type execOpt struct {
+	resources ResourcePolicy
+	// readonlyRootFS enforces read-only root filesystems for all execs
+	readonlyRootFS bool
+	// netMode is the network of the execs that don't set one
+	netMode worker.NetMode
+	// proxyEnv is added to the environment of the execs that haven't set the
+	// variables themselves or opted out with NoProxyEnv
+	proxyEnv []string
+	// checkpoints stores the checkpoints of the execs with a checkpoint
+	// interval, nil if they are not supported
+	checkpoints *checkpoints
+	// hostMounts are the host paths that can be mounted by the execs, the
+	// host mounts are not allowed if it is empty
+	hostMounts []string
+	// securityProfile confines the execs that don't set their own profiles
+	securityProfile worker.SecurityProfile
+	// cgroupParent is the cgroup the execs are created under, the default
+	// cgroup of the worker if it is empty
+	cgroupParent string
+	// dns configures the resolv.conf of the execs. Like the proxy variables
+	// it is not part of the cache key.
+	dns *worker.DNSConfig
+	// workerInfo describes the platforms of the worker running the exec
+	workerInfo worker.Info
+}
+
+func newExecOp(v Vertex, op *pb.Op_Exec, cm cache.Manager, w worker.Worker, cacheMounts *cacheMounts, sm *session.Manager, opt execOpt) (Op, error) {
+	for _, m := range op.Exec.Mounts {
+		if _, err := contenthash.NormalizeSelector(m.Selector); err != nil {
+			return nil, errors.Wrapf(err, "invalid mount %s", m.Dest)
+		}
+		if len(m.ContentSelectors) > 0 && !m.Readonly {
+			return nil, errors.Errorf("invalid mount %s: content selectors require a read-only mount", m.Dest)
+		}
+		if _, err := contentSelectors(m, nil); err != nil {
+			return nil, errors.Wrapf(err, "invalid mount %s", m.Dest)
+		}
+		if m.MountType == pb.MountType_HOST {
+			if err := validateHostMount(m, opt.hostMounts); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if p := op.Exec.SecurityProfile; p != nil && p.Seccomp != "" && p.Seccomp != worker.Unconfined {
+		var sc specs.LinuxSeccomp
+		if err := json.Unmarshal([]byte(p.Seccomp), &sc); err != nil {
+			return nil, errors.Wrap(err, "invalid seccomp profile")
+		}
+	}
+	for _, kv := range op.Exec.ProxyEnv {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 || !isProxyEnvKey(parts[0]) {
+			return nil, errors.Errorf("invalid proxy variable %q", kv)
+		}
+	}
 	return &execOp{
-		op: op.Exec,
-		cm: cm,
-		w:  w,
+		v:           v,
+		op:          normalizeExecPaths(platformOS(vertexPlatform(v)), op.Exec),
+		cm:          cm,
+		w:           w,
+		cacheMounts: cacheMounts,
+		sm:          sm,
+		opt:         opt,
 	}, nil
 }
 
 func (e *execOp) CacheKey(ctx context.Context) (digest.Digest, error) {
+	return e.versionedCacheKey(ctx, execCacheType)
+}
+
+func (e *execOp) cacheKeyKind() string {
+	return "exec"
+}
+
+func (e *execOp) versionedCacheKey(ctx context.Context, version string) (digest.Digest, error) {
+	op := e.cacheKeyOp()
+	switch version {
+	case execCacheType:
+	case execCacheTypeV0:
+		op.Meta = e.op.Meta
+		op.ReadonlyRootfs = e.op.ReadonlyRootfs
+	default:
+		return "", errors.Errorf("unknown exec cache key version %s", version)
+	}
 	dt, err := json.Marshal(struct {
-		Type string
-		Exec *pb.ExecOp
+		Type          string
+		Exec          *pb.ExecOp
+		Platform      *pb.Platform `json:",omitempty"`
+		WorkerFilters []string     `json:",omitempty"`
 	}{
-		Type: execCacheType,
-		Exec: e.op,
+		Type:          version,
+		Exec:          op,
+		Platform:      vertexPlatform(e.v),
+		WorkerFilters: e.workerFilters(),
 	})
 	if err != nil {
 		return "", err
@@ -49,25 +150,189 @@ func (e *execOp) CacheKey(ctx context.Context) (digest.Digest, error) {
 	return digest.FromBytes(dt), nil
 }
 
+// cacheKeyOp returns the definition of the exec that is used for the cache
+// keys. Secret and ssh mounts are left out so that the secrets or the agent
+// can be changed without invalidating the cache. The timeout and the retry
+// policy don't change the result and are also left out, as is the checkpoint
+// interval. Proxy variables are added when running the exec and are not part
+// of the cache key so the opt-out isn't either. The host mounts are keyed by
+// their version instead of their path. A read-only rootfs that is enforced
+// by the daemon is keyed like one set by the exec.
+func (e *execOp) cacheKeyOp() *pb.ExecOp {
+	op := *e.op
+	op.Meta = normalizeMeta(op.Meta)
+	op.ReadonlyRootfs = op.ReadonlyRootfs || e.opt.readonlyRootFS
+	op.Timeout = 0
+	op.Retry = nil
+	op.CheckpointInterval = 0
+	op.NoProxyEnv = false
+	op.ProxyEnv = nil
+	op.Mounts = nil
+	for _, m := range e.op.Mounts {
+		if m.MountType == pb.MountType_SECRET || m.MountType == pb.MountType_SSH {
+			continue
+		}
+		if m.MountType == pb.MountType_HOST && m.HostOpt != nil {
+			hm := *m
+			hm.HostOpt = &pb.HostOpt{Version: m.HostOpt.Version}
+			m = &hm
+		}
+		op.Mounts = append(op.Mounts, m)
+	}
+	return &op
+}
+
+// workerFilters returns the sorted filters of the worker constraints of the
+// exec. The worker they select is the environment the exec runs in, so they
+// are part of the cache keys.
+func (e *execOp) workerFilters() []string {
+	c := vertexConstraints(e.v)
+	if c == nil || len(c.Filter) == 0 {
+		return nil
+	}
+	filters := append([]string{}, c.Filter...)
+	sort.Strings(filters)
+	return filters
+}
+
 func (e *execOp) Run(ctx context.Context, inputs []Reference) ([]Reference, error) {
+	emulated, err := checkPlatform(vertexPlatform(e.v), e.opt.workerInfo)
+	if err != nil {
+		return nil, err
+	}
+	if emulated {
+		reportStatus(ctx, fmt.Sprintf("[warning] running %s with emulation", platforms.Format(vertexPlatform(e.v).Spec())))
+	}
+	var retries int
+	if e.op.Retry != nil {
+		retries = int(e.op.Retry.MaxRetries)
+	}
+	for attempt := 1; ; attempt++ {
+		// failures are only debugged if the exec isn't retried
+		last := func(err error) bool {
+			return attempt > retries || !e.retryable(err)
+		}
+		refs, err := e.run(ctx, inputs, last)
+		if err == nil || attempt > retries || ctx.Err() != nil || !e.retryable(err) {
+			return refs, err
+		}
+		logrus.Debugf("retrying %v after error: %v", e.op.Meta.Args, err)
+		reportAttempt(ctx, attempt+1, retries+1)
+	}
+}
+
+// retryable returns true if failure err from a previous attempt matches the
+// retry policy
+func (e *execOp) retryable(err error) bool {
+	if e.op.Retry == nil || len(e.op.Retry.ExitCodes) == 0 {
+		return true
+	}
+	execErr, ok := errors.Cause(err).(*ExecError)
+	if !ok {
+		return false
+	}
+	for _, c := range e.op.Retry.ExitCodes {
+		if uint32(c) == execErr.ExitCode {
+			return true
+		}
+	}
+	return false
+}
+
+func reportAttempt(ctx context.Context, attempt, total int) {
+	reportStatus(ctx, fmt.Sprintf("attempt %d/%d", attempt, total))
+}
+
+func reportStatus(ctx context.Context, id string) {
+	pw, _, _ := progress.FromContext(ctx)
+	defer pw.Close()
+	now := time.Now()
+	pw.Write(id, progress.Status{
+		Started:   &now,
+		Completed: &now,
+	})
+}
+
+// run runs a single attempt of the exec. If the exec fails and debug returns
+// true for the error a debug shell is started before the mounts are released.
+func (e *execOp) run(ctx context.Context, inputs []Reference, debug func(error) bool) ([]Reference, error) {
 	var mounts []worker.Mount
 	var outputs []Reference
 	var root cache.Mountable
+	var rootBase cache.ImmutableRef
+	var sshSocket string
+	readonlyRoot := e.opt.readonlyRootFS || e.op.ReadonlyRootfs
+
+	// the root mount is saved periodically and a previous run of the exec
+	// with the same cache key continues from its latest checkpoint
+	var checkpoint *worker.Checkpoint
+	checkpointKey := cacheKeyFromContext(ctx)
+	checkpointing := e.op.CheckpointInterval > 0 && e.opt.checkpoints != nil && checkpointKey != ""
 
 	defer func() {
 		for _, o := range outputs {
 			if o != nil {
-				go o.Release(ctx)
+				cache.ReleaseDetached(o)
 			}
 		}
 	}()
 
 	for _, m := range e.op.Mounts {
+		switch m.MountType {
+		case pb.MountType_CACHE:
+			if m.CacheOpt == nil {
+				return nil, errors.Errorf("missing cache mount options for %s", m.Dest)
+			}
+			ref, release, err := e.cacheMounts.get(ctx, m.CacheOpt.ID, m.CacheOpt.Sharing)
+			if err != nil {
+				return nil, err
+			}
+			defer release()
+			mounts = append(mounts, worker.Mount{Src: ref, Dest: m.Dest})
+			continue
+		case pb.MountType_TMPFS:
+			mounts = append(mounts, worker.Mount{Src: newTmpfs(m.TmpfsOpt), Dest: m.Dest, Readonly: m.Readonly})
+			continue
+		case pb.MountType_SECRET:
+			dt, err := getSecret(ctx, e.sm, m.SecretOpt)
+			if err != nil {
+				return nil, err
+			}
+			if dt == nil {
+				continue
+			}
+			sm := newSecretMount(dt, m.SecretOpt)
+			defer sm.release()
+			mounts = append(mounts, worker.Mount{Src: sm, Dest: m.Dest, Readonly: true})
+			continue
+		case pb.MountType_SSH:
+			sm, err := newSSHMount(ctx, e.sm, m.SSHOpt)
+			if err != nil {
+				return nil, err
+			}
+			if sm == nil {
+				continue
+			}
+			defer sm.release()
+			mounts = append(mounts, worker.Mount{Src: sm, Dest: m.Dest})
+			if sshSocket == "" {
+				sshSocket = m.Dest
+			}
+			continue
+		case pb.MountType_HOST:
+			p, err := allowedHostPath(m.HostOpt.Path, e.opt.hostMounts)
+			if err != nil {
+				return nil, err
+			}
+			mounts = append(mounts, worker.Mount{Src: &hostMount{path: p}, Dest: m.Dest, Readonly: true})
+			continue
+		}
+
 		var mountable cache.Mountable
-		var ref cache.ImmutableRef
+		var ref, mountBase cache.ImmutableRef
 		if m.Input != pb.Empty {
-			if int(m.Input) > len(inputs) {
-				return nil, errors.Errorf("missing input %d", m.Input)
+			if err := checkInputIndex(vertexDigest(e.v), "mount "+m.Dest, m.Input, len(inputs)); err != nil {
+				return nil, errors.WithStack(err)
 			}
 			inp := inputs[int(m.Input)]
 			var ok bool
@@ -77,22 +342,54 @@ func (e *execOp) Run(ctx context.Context, inputs []Reference) ([]Reference, erro
 			}
 			mountable = ref
 		}
+		if m.Dest == pb.RootMount && readonlyRoot {
+			if ref == nil {
+				return nil, errors.Errorf("read-only rootfs requires a root input")
+			}
+			if m.Output != pb.SkipOutput {
+				outputs = append(outputs, newSharedRef(ref).Clone())
+			}
+			root = ref
+			continue
+		}
 		if m.Output != pb.SkipOutput {
 			if m.Readonly && ref != nil && m.Dest != pb.RootMount { // exclude read-only rootfs
 				outputs = append(outputs, newSharedRef(ref).Clone())
 			} else {
-				active, err := e.cm.New(ctx, ref, cache.WithDescription(fmt.Sprintf("mount %s from exec %s", m.Dest, strings.Join(e.op.Meta.Args, " ")))) // TODO: should be method
+				base := ref
+				var seq int
+				if checkpointing && m.Dest == pb.RootMount {
+					resumed, n, err := e.opt.checkpoints.latest(ctx, checkpointKey)
+					if err != nil {
+						return nil, err
+					}
+					if resumed != nil {
+						defer resumed.Release(context.TODO())
+						base, seq = resumed, n
+						reportStatus(ctx, fmt.Sprintf("resuming from checkpoint %d", seq))
+					}
+				}
+				active, err := e.cm.New(ctx, base, cache.WithDescription(fmt.Sprintf("mount %s from exec %s", m.Dest, strings.Join(e.op.Meta.Args, " ")))) // TODO: should be method
 				if err != nil {
 					return nil, err
 				}
 				outputs = append(outputs, active)
-				mountable = active
+				mountable, mountBase = active, base
+				if checkpointing && m.Dest == pb.RootMount {
+					checkpoint = &worker.Checkpoint{
+						Interval: time.Duration(e.op.CheckpointInterval) * time.Second,
+						Save: func(ctx context.Context) error {
+							seq++
+							return e.opt.checkpoints.save(ctx, checkpointKey, seq, base, active)
+						},
+					}
+				}
 			}
 		}
 		if m.Dest == pb.RootMount {
-			root = mountable
+			root, rootBase = mountable, mountBase
 		} else {
-			mounts = append(mounts, worker.Mount{Src: mountable, Dest: m.Dest, Readonly: m.Readonly, Selector: m.Selector})
+			mounts = append(mounts, worker.Mount{Src: mountable, Dest: m.Dest, Readonly: m.Readonly, Selector: m.Selector, Base: mountBase})
 		}
 	}
 
@@ -100,56 +397,157 @@ func (e *execOp) Run(ctx context.Context, inputs []Reference) ([]Reference, erro
 		return mounts[i].Dest < mounts[j].Dest
 	})
 
+	pbMeta := normalizeMeta(e.op.Meta)
 	meta := worker.Meta{
-		Args: e.op.Meta.Args,
-		Env:  e.op.Meta.Env,
-		Cwd:  e.op.Meta.Cwd,
+		Args:      pbMeta.Args,
+		Env:       pbMeta.Env,
+		Cwd:       pbMeta.Cwd,
+		User:      pbMeta.User,
+		Resources: e.opt.resources.Apply(resourcesFromPB(e.op.Resources)),
+		Hostname:  pbMeta.Hostname,
+		DNS:       e.opt.dns,
+
+		ReadonlyRootFS: readonlyRoot,
+		Checkpoint:     checkpoint,
+		RootBase:       rootBase,
+		NetMode:        netMode(e.op.Network, e.opt.netMode),
+		Insecure:       e.op.Security == pb.SecurityMode_INSECURE,
+		Devices:        e.op.Devices,
+
+		SecurityProfile: securityProfile(e.opt.securityProfile, e.op.SecurityProfile),
+		CgroupParent:    cgroupParent(e.opt.cgroupParent, cgroupParentFromContext(ctx)),
+	}
+	for _, h := range pbMeta.ExtraHosts {
+		meta.ExtraHosts = append(meta.ExtraHosts, worker.HostIP{Host: h.Host, IP: h.IP})
+	}
+	meta.Env = addDefaultEnv(meta.Env, "PATH", defaultPathEnv(vertexPlatform(e.v)))
+	if sshSocket != "" {
+		meta.Env = addDefaultEnv(meta.Env, "SSH_AUTH_SOCK", sshSocket)
 	}
+	meta.Env = e.addProxyEnv(meta.Env)
 
-	stdout, stderr := logs.NewLogStreams(ctx)
+	stdout, logStderr := logs.NewLogStreams(ctx)
 	defer stdout.Close()
-	defer stderr.Close()
+	defer logStderr.Close()
+	stderr := newTailWriter(logStderr, execErrorStderrSize)
 
-	if err := e.w.Exec(ctx, meta, root, mounts, stdout, stderr); err != nil {
-		return nil, errors.Wrapf(err, "worker failed running %v", meta.Args)
+	execCtx := ctx
+	if e.op.Timeout > 0 {
+		var cancel func()
+		execCtx, cancel = context.WithTimeout(ctx, time.Duration(e.op.Timeout)*time.Second)
+		defer cancel()
+	}
+
+	wl := workerLabel(e.w)
+	metricExecs.Inc(wl)
+	execStarted := time.Now()
+	err := e.w.Exec(execCtx, meta, root, mounts, nil, stdout, stderr)
+	recordPhase(ctx, phaseExec, execStarted)
+	metricExecs.Dec(wl)
+	if err != nil {
+		if exitErr, ok := errors.Cause(err).(*worker.ExitError); ok {
+			metricExecFailures.Inc(wl, "exit")
+			if checkpointing {
+				e.clearCheckpoints(ctx, checkpointKey)
+			}
+			var dgst digest.Digest
+			if e.v != nil {
+				dgst = e.v.Digest()
+			}
+			err = errors.WithStack(newExecError(dgst, meta.Args, exitErr.ExitCode, stderr.Bytes()))
+		} else {
+			metricExecFailures.Inc(wl, "error")
+			err = errors.Wrapf(err, "worker failed running %v", meta.Args)
+		}
+		if execCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+			err = errors.Wrapf(err, "timed out after %s", time.Duration(e.op.Timeout)*time.Second)
+		}
+		if ctx.Err() == nil && debug(err) {
+			e.debug(ctx, meta, root, mounts, err)
+		}
+		return nil, err
 	}
 
 	refs := []Reference{}
+	commitStarted := time.Now()
+	defer recordPhase(ctx, phaseCommit, commitStarted)
 	for i, o := range outputs {
 		if mutable, ok := o.(cache.MutableRef); ok {
 			ref, err := mutable.Commit(ctx)
 			if err != nil {
+				for _, r := range refs {
+					cache.ReleaseDetached(r)
+				}
 				return nil, errors.Wrapf(err, "error committing %s", mutable.ID())
 			}
+			updateChecksums(ctx, ref)
 			refs = append(refs, ref)
 		} else {
 			refs = append(refs, o)
 		}
 		outputs[i] = nil
 	}
+	if checkpointing {
+		e.clearCheckpoints(ctx, checkpointKey)
+	}
 	return refs, nil
 }
 
+// clearCheckpoints removes the checkpoints of an exec that completed. An exec
+// that exited with an error is not resumed either, it would fail the same way.
+func (e *execOp) clearCheckpoints(ctx context.Context, key digest.Digest) {
+	if err := e.opt.checkpoints.clear(ctx, key); err != nil {
+		logrus.Errorf("failed to clear checkpoints of %s: %v", key, err)
+	}
+}
+
+// updateChecksums makes the checksums of a committed ref derive from the
+// checksums of its parent so only the changed files are hashed for the
+// content keys
+func updateChecksums(ctx context.Context, ref cache.ImmutableRef) {
+	if err := contenthash.UpdateFromParent(ctx, ref); err != nil {
+		logrus.Warnf("failed to update checksums of %s from parent: %v", ref.ID(), err)
+	}
+}
+
 func (e *execOp) ContentKeys(ctx context.Context, inputs [][]digest.Digest, refs []Reference) ([]digest.Digest, error) {
 	if len(refs) == 0 {
 		return nil, nil
 	}
-	// contentKey for exec uses content based checksum for mounts and definition
-	// based checksum for root
+	// contentKey for exec uses content based checksum for read-only mounts and
+	// definition based checksum for root and writable mounts, unless content
+	// based cache has been enabled for them
 
 	skipped := make([]int, 0)
 
 	type src struct {
-		index    pb.InputIndex
-		selector string
+		index     pb.InputIndex
+		selector  string
+		selectors []contenthash.Selector
+		key       string
 	}
 
 	skip := true
-	srcsMap := make(map[src]struct{}, len(refs))
+	withSelectors := false
+	srcsMap := make(map[string]src, len(refs))
 	for _, m := range e.op.Mounts {
 		if m.Input != pb.Empty {
-			if m.Dest != pb.RootMount && m.Readonly { // could also include rw if they don't have a selector, but not sure if helps performance
-				srcsMap[src{m.Input, path.Join("/", m.Selector)}] = struct{}{}
+			if e.contentKeyed(m) {
+				sels, err := contentSelectors(m, e.op.ContentAttrs)
+				if err != nil {
+					return nil, err
+				}
+				s := src{index: m.Input, selector: sels[0].Path, selectors: sels}
+				if len(m.ContentSelectors) > 0 {
+					s.selector = path.Join("/", m.Selector)
+					withSelectors = true
+				}
+				keys := make([]string, len(sels))
+				for i, sel := range sels {
+					keys[i] = sel.String()
+				}
+				s.key = fmt.Sprintf("%d:%s", m.Input, strings.Join(keys, ","))
+				srcsMap[s.key] = s
 				skip = false
 			} else {
 				skipped = append(skipped, int(m.Input))
@@ -161,17 +559,32 @@ func (e *execOp) ContentKeys(ctx context.Context, inputs [][]digest.Digest, refs
 	}
 
 	srcs := make([]src, 0, len(srcsMap))
-	for s := range srcsMap {
+	for _, s := range srcsMap {
 		srcs = append(srcs, s)
 	}
 
 	sort.Slice(srcs, func(i, j int) bool {
-		if srcs[i].index == srcs[j].index {
+		if srcs[i].index != srcs[j].index {
+			return srcs[i].index < srcs[j].index
+		}
+		if srcs[i].selector != srcs[j].selector {
 			return srcs[i].selector < srcs[j].selector
 		}
-		return srcs[i].index < srcs[j].index
+		return srcs[i].key < srcs[j].key
 	})
 
+	// the selected paths are only part of the key of the execs that use
+	// content selectors to keep the keys of the other execs stable
+	var selectors [][]string
+	if withSelectors {
+		selectors = make([][]string, len(srcs))
+		for i, s := range srcs {
+			for _, sel := range s.selectors {
+				selectors[i] = append(selectors[i], sel.String())
+			}
+		}
+	}
+
 	dgsts := make([]digest.Digest, len(srcs))
 	eg, ctx := errgroup.WithContext(ctx)
 	for i, s := range srcs {
@@ -181,7 +594,7 @@ func (e *execOp) ContentKeys(ctx context.Context, inputs [][]digest.Digest, refs
 				if !ok {
 					return errors.Errorf("invalid reference")
 				}
-				dgst, err := contenthash.Checksum(ctx, ref, s.selector)
+				dgst, err := contenthash.ChecksumSelectors(ctx, ref, s.selectors)
 				if err != nil {
 					return err
 				}
@@ -201,15 +614,19 @@ func (e *execOp) ContentKeys(ctx context.Context, inputs [][]digest.Digest, refs
 			inputKeys[i] = cacheKeys[skipped[i]]
 		}
 		dt, err := json.Marshal(struct {
-			Type    string
-			Sources []digest.Digest
-			Inputs  []digest.Digest
-			Exec    *pb.ExecOp
+			Type          string
+			Sources       []digest.Digest
+			Selectors     [][]string `json:",omitempty"`
+			Inputs        []digest.Digest
+			Exec          *pb.ExecOp
+			WorkerFilters []string `json:",omitempty"`
 		}{
-			Type:    execCacheType,
-			Sources: dgsts,
-			Inputs:  inputKeys,
-			Exec:    e.op,
+			Type:          execCacheType,
+			Sources:       dgsts,
+			Selectors:     selectors,
+			Inputs:        inputKeys,
+			Exec:          e.cacheKeyOp(),
+			WorkerFilters: e.workerFilters(),
 		})
 		if err != nil {
 			return nil, err
@@ -219,3 +636,254 @@ func (e *execOp) ContentKeys(ctx context.Context, inputs [][]digest.Digest, refs
 
 	return out, nil
 }
+
+// contentSelectors returns the paths of the input of m that are part of its
+// content based cache key with the attributes attrs. The content selectors are
+// relative to the mounted directory.
+func contentSelectors(m *pb.Mount, attrs *pb.ContentAttrs) ([]contenthash.Selector, error) {
+	sel, err := contenthash.NormalizeSelector(m.Selector)
+	if err != nil {
+		return nil, err
+	}
+	if len(m.ContentSelectors) == 0 {
+		return []contenthash.Selector{{Path: sel, FollowLinks: true, Attrs: contentAttrs(attrs)}}, nil
+	}
+	out := make([]contenthash.Selector, 0, len(m.ContentSelectors))
+	for _, s := range m.ContentSelectors {
+		p, err := contenthash.NormalizeSelector(s.Path)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, contenthash.Selector{
+			Path:        path.Join(sel, p),
+			Wildcard:    s.Wildcard,
+			FollowLinks: s.FollowLinks,
+			Attrs:       contentAttrs(attrs),
+		})
+	}
+	return out, nil
+}
+
+// contentAttrs returns the file attributes of a content based cache key
+func contentAttrs(a *pb.ContentAttrs) contenthash.Attrs {
+	var attrs contenthash.Attrs
+	if a == nil {
+		return attrs
+	}
+	if a.Ownership {
+		attrs |= contenthash.AttrOwnership
+	}
+	if a.Xattrs {
+		attrs |= contenthash.AttrXattrs
+	}
+	if a.Capabilities {
+		attrs |= contenthash.AttrCapabilities
+	}
+	if a.ModTime {
+		attrs |= contenthash.AttrModTime
+	}
+	return attrs
+}
+
+// contentKeyed returns true if the input of the mount should be included in the
+// content based cache key. Checksumming writable mounts isn't enabled by default
+// as it may be expensive for big inputs.
+func (e *execOp) contentKeyed(m *pb.Mount) bool {
+	if m.MountType != pb.MountType_BIND {
+		return false
+	}
+	if m.Dest == pb.RootMount {
+		if !e.op.ContentCacheRoot {
+			return false
+		}
+	} else if !m.Readonly && !m.ContentCache {
+		return false
+	}
+	// output of a writable mount contains the whole input, not only the
+	// selected path
+	return m.Readonly || m.Selector == ""
+}
+
+func (e *execOp) describe(pv *ProvenanceVertex) {
+	meta := normalizeMeta(e.op.Meta)
+	if meta != nil {
+		pv.Exec = &ProvenanceExec{
+			Args: meta.Args,
+			Env:  meta.Env,
+			Cwd:  meta.Cwd,
+			User: meta.User,
+		}
+		if e.op.Network != pb.NetMode_NET_UNSET {
+			pv.Exec.Network = strings.ToLower(strings.TrimPrefix(e.op.Network.String(), "NET_"))
+		}
+		if e.op.Security != pb.SecurityMode_SANDBOX {
+			pv.Exec.Security = strings.ToLower(e.op.Security.String())
+		}
+		pv.Exec.Devices = e.op.Devices
+	}
+	if id, ok := e.w.(worker.Identifier); ok {
+		pv.Worker = id.Identity()
+	}
+}
+
+// securityProfile returns the profiles of the daemon with the ones set by the
+// exec replacing them
+func securityProfile(p worker.SecurityProfile, op *pb.SecurityProfile) worker.SecurityProfile {
+	if op == nil {
+		return p
+	}
+	if op.Seccomp != "" {
+		p.Seccomp = op.Seccomp
+	}
+	if op.Apparmor != "" {
+		p.AppArmor = op.Apparmor
+	}
+	if op.SelinuxLabel != "" {
+		p.SELinuxLabel = op.SelinuxLabel
+	}
+	return p
+}
+
+// netMode returns the network of an exec, def for the execs that don't set
+// one
+func netMode(m pb.NetMode, def worker.NetMode) worker.NetMode {
+	switch m {
+	case pb.NetMode_NET_HOST:
+		return worker.NetModeHost
+	case pb.NetMode_NET_NONE:
+		return worker.NetModeNone
+	default:
+		return def
+	}
+}
+
+// addDefaultEnv adds k=v to env unless k has already been set
+func addDefaultEnv(env []string, k, v string) []string {
+	for _, e := range env {
+		if strings.HasPrefix(e, k+"=") {
+			return env
+		}
+	}
+	return append(append([]string{}, env...), k+"="+v)
+}
+
+// addProxyEnv adds the proxy variables of the op and then the ones of the
+// daemon, unless the op opted out of them, to env if env doesn't set them
+func (e *execOp) addProxyEnv(env []string) []string {
+	proxyEnv := e.op.ProxyEnv
+	if !e.op.NoProxyEnv {
+		proxyEnv = append(append([]string{}, proxyEnv...), e.opt.proxyEnv...)
+	}
+	for _, kv := range proxyEnv {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		env = addDefaultEnv(env, parts[0], parts[1])
+	}
+	return env
+}
+
+// defaultPathEnv returns the PATH of the execs for the platform p that don't
+// set one, the platform of the daemon if p is nil
+func defaultPathEnv(p *pb.Platform) string {
+	return system.DefaultPathEnvForOS(platformOS(p))
+}
+
+// isProxyEnvKey returns true for the names of the proxy variables in upper or
+// lower case
+func isProxyEnvKey(k string) bool {
+	if k != strings.ToUpper(k) && k != strings.ToLower(k) {
+		return false
+	}
+	switch strings.ToUpper(k) {
+	case "HTTP_PROXY", "HTTPS_PROXY", "FTP_PROXY", "NO_PROXY":
+		return true
+	}
+	return false
+}
+
+// normalizeMeta returns a copy of m with the environment sorted by key and
+// deduplicated, the working directory cleaned and the extra hosts
+// normalized. The last value of a duplicated variable wins. Execs that only
+// differ in the order of the environment get the same cache key.
+func normalizeMeta(m *pb.Meta) *pb.Meta {
+	if m == nil {
+		return nil
+	}
+	nm := *m
+	if m.Cwd != "" {
+		nm.Cwd = path.Clean(m.Cwd)
+	}
+	nm.ExtraHosts = normalizeExtraHosts(m.ExtraHosts)
+	if len(m.Env) == 0 {
+		return &nm
+	}
+	env := map[string]string{}
+	keys := make([]string, 0, len(m.Env))
+	for _, e := range m.Env {
+		k := strings.SplitN(e, "=", 2)[0]
+		if _, ok := env[k]; !ok {
+			keys = append(keys, k)
+		}
+		env[k] = e
+	}
+	sort.Strings(keys)
+	nm.Env = make([]string, 0, len(keys))
+	for _, k := range keys {
+		nm.Env = append(nm.Env, env[k])
+	}
+	return &nm
+}
+
+// normalizeExtraHosts sorts the extra hosts by their lower case names and
+// removes the duplicates. The order of the addresses of a host is kept as
+// the first one is resolved.
+func normalizeExtraHosts(hosts []*pb.HostIP) []*pb.HostIP {
+	if len(hosts) == 0 {
+		return nil
+	}
+	seen := map[pb.HostIP]struct{}{}
+	nh := make([]*pb.HostIP, 0, len(hosts))
+	for _, h := range hosts {
+		h := pb.HostIP{Host: strings.ToLower(h.Host), IP: h.IP}
+		if _, ok := seen[h]; ok {
+			continue
+		}
+		seen[h] = struct{}{}
+		nh = append(nh, &h)
+	}
+	sort.SliceStable(nh, func(i, j int) bool {
+		return nh[i].Host < nh[j].Host
+	})
+	return nh
+}
+
+// tmpfs is a mountable for an in-memory directory that is discarded after
+// the exec has completed
+type tmpfs struct {
+	size int64
+}
+
+func newTmpfs(opt *pb.TmpfsOpt) cache.Mountable {
+	t := &tmpfs{}
+	if opt != nil {
+		t.size = opt.Size_
+	}
+	return t
+}
+
+func (t *tmpfs) Mount(ctx context.Context, readonly bool) ([]mount.Mount, error) {
+	opts := []string{"nosuid"}
+	if readonly {
+		opts = append(opts, "ro")
+	}
+	if t.size > 0 {
+		opts = append(opts, fmt.Sprintf("size=%d", t.size))
+	}
+	return []mount.Mount{{
+		Type:    "tmpfs",
+		Source:  "tmpfs",
+		Options: opts,
+	}}, nil
+}
@@ -0,0 +1,417 @@
+package solver
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/cache/contenthash"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/util/system"
+	"github.com/moby/buildkit/worker"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestExecContentKeyed(t *testing.T) {
+	e := &execOp{op: &pb.ExecOp{}}
+
+	require.False(t, e.contentKeyed(&pb.Mount{Dest: pb.RootMount}))
+	require.True(t, e.contentKeyed(&pb.Mount{Dest: "/foo", Readonly: true}))
+	require.True(t, e.contentKeyed(&pb.Mount{Dest: "/foo", Readonly: true, Selector: "bar"}))
+	require.False(t, e.contentKeyed(&pb.Mount{Dest: "/foo"}))
+	require.True(t, e.contentKeyed(&pb.Mount{Dest: "/foo", ContentCache: true}))
+	require.False(t, e.contentKeyed(&pb.Mount{Dest: "/foo", ContentCache: true, Selector: "bar"}))
+
+	e.op.ContentCacheRoot = true
+	require.True(t, e.contentKeyed(&pb.Mount{Dest: pb.RootMount}))
+	require.False(t, e.contentKeyed(&pb.Mount{Dest: pb.RootMount, Selector: "bar"}))
+
+	require.False(t, e.contentKeyed(&pb.Mount{Dest: "/foo", Readonly: true, MountType: pb.MountType_TMPFS}))
+}
+
+func TestExecInvalidSelector(t *testing.T) {
+	op := &pb.Op_Exec{Exec: &pb.ExecOp{Mounts: []*pb.Mount{
+		{Dest: pb.RootMount},
+		{Dest: "/foo", Selector: "bar/../../baz", Readonly: true},
+	}}}
+	_, err := newExecOp(nil, op, nil, nil, nil, nil, execOpt{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid mount /foo")
+
+	op.Exec.Mounts[1].Selector = "/bar/baz"
+	_, err = newExecOp(nil, op, nil, nil, nil, nil, execOpt{})
+	require.NoError(t, err)
+}
+
+func TestExecContentSelectors(t *testing.T) {
+	sels, err := contentSelectors(&pb.Mount{Dest: "/foo", Selector: "bar", Readonly: true}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []contenthash.Selector{{Path: "/bar", FollowLinks: true}}, sels)
+
+	sels, err = contentSelectors(&pb.Mount{Dest: "/foo", Selector: "bar", Readonly: true}, &pb.ContentAttrs{Ownership: true, Capabilities: true})
+	require.NoError(t, err)
+	require.Equal(t, []contenthash.Selector{{Path: "/bar", FollowLinks: true, Attrs: contenthash.AttrOwnership | contenthash.AttrCapabilities}}, sels)
+
+	m := &pb.Mount{Dest: "/foo", Selector: "bar", Readonly: true, ContentSelectors: []*pb.Selector{
+		{Path: "**/*.go", Wildcard: true},
+		{Path: "go.mod", FollowLinks: true},
+	}}
+	sels, err = contentSelectors(m, nil)
+	require.NoError(t, err)
+	require.Equal(t, []contenthash.Selector{
+		{Path: "/bar/**/*.go", Wildcard: true},
+		{Path: "/bar/go.mod", FollowLinks: true},
+	}, sels)
+
+	op := &pb.Op_Exec{Exec: &pb.ExecOp{Mounts: []*pb.Mount{{Dest: pb.RootMount}, m}}}
+	_, err = newExecOp(nil, op, nil, nil, nil, nil, execOpt{})
+	require.NoError(t, err)
+
+	m.ContentSelectors[0].Path = "../*.go"
+	_, err = newExecOp(nil, op, nil, nil, nil, nil, execOpt{})
+	require.Error(t, err)
+
+	m.ContentSelectors[0].Path = "*.go"
+	m.Readonly = false
+	_, err = newExecOp(nil, op, nil, nil, nil, nil, execOpt{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "read-only")
+}
+
+func TestTmpfsMount(t *testing.T) {
+	m, err := newTmpfs(&pb.TmpfsOpt{Size_: 1024}).Mount(context.TODO(), true)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(m))
+	require.Equal(t, "tmpfs", m[0].Type)
+	require.Equal(t, []string{"nosuid", "ro", "size=1024"}, m[0].Options)
+
+	m, err = newTmpfs(nil).Mount(context.TODO(), false)
+	require.NoError(t, err)
+	require.Equal(t, []string{"nosuid"}, m[0].Options)
+}
+
+func TestExecCacheKeySessionMounts(t *testing.T) {
+	op := &pb.ExecOp{
+		Meta: &pb.Meta{Args: []string{"true"}},
+		Mounts: []*pb.Mount{
+			{Dest: pb.RootMount},
+		},
+	}
+	e := &execOp{op: op}
+	k1, err := e.CacheKey(context.TODO())
+	require.NoError(t, err)
+
+	op.Mounts = append(op.Mounts, &pb.Mount{
+		Dest:      "/run/secrets/foo",
+		Input:     pb.Empty,
+		Output:    pb.SkipOutput,
+		MountType: pb.MountType_SECRET,
+		SecretOpt: &pb.SecretOpt{ID: "foo"},
+	})
+	op.Mounts = append(op.Mounts, &pb.Mount{
+		Dest:      "/run/buildkit/ssh_agent.0",
+		Input:     pb.Empty,
+		Output:    pb.SkipOutput,
+		MountType: pb.MountType_SSH,
+		SSHOpt:    &pb.SSHOpt{ID: "default"},
+	})
+	k2, err := e.CacheKey(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, k1, k2)
+	require.Equal(t, 3, len(op.Mounts))
+}
+
+func TestExecCacheKeyRetry(t *testing.T) {
+	op := &pb.ExecOp{
+		Meta: &pb.Meta{Args: []string{"true"}},
+	}
+	e := &execOp{op: op}
+	k1, err := e.CacheKey(context.TODO())
+	require.NoError(t, err)
+
+	op.Timeout = 10
+	op.Retry = &pb.RetryPolicy{MaxRetries: 2}
+	op.CheckpointInterval = 60
+	k2, err := e.CacheKey(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, k1, k2)
+	require.Equal(t, int64(10), op.Timeout)
+}
+
+func TestExecCacheKeyReadonlyRootfs(t *testing.T) {
+	op := &pb.ExecOp{
+		Meta: &pb.Meta{Args: []string{"true"}},
+	}
+	e := &execOp{op: op}
+	k1, err := e.CacheKey(context.TODO())
+	require.NoError(t, err)
+
+	e.opt.readonlyRootFS = true
+	k2, err := e.CacheKey(context.TODO())
+	require.NoError(t, err)
+	require.NotEqual(t, k1, k2)
+
+	e.opt.readonlyRootFS = false
+	op.ReadonlyRootfs = true
+	k3, err := e.CacheKey(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, k2, k3)
+}
+
+func TestExecCacheKeyNormalizedMeta(t *testing.T) {
+	e1 := &execOp{op: &pb.ExecOp{
+		Meta: &pb.Meta{Args: []string{"true"}, Env: []string{"B=2", "A=1"}, Cwd: "/foo/"},
+	}}
+	e2 := &execOp{op: &pb.ExecOp{
+		Meta: &pb.Meta{Args: []string{"true"}, Env: []string{"A=0", "B=2", "A=1"}, Cwd: "/foo/./bar/.."},
+	}}
+	k1, err := e1.CacheKey(context.TODO())
+	require.NoError(t, err)
+	k2, err := e2.CacheKey(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, k1, k2)
+
+	e2.op.Meta.Env = []string{"A=0", "B=2"}
+	k2, err = e2.CacheKey(context.TODO())
+	require.NoError(t, err)
+	require.NotEqual(t, k1, k2)
+}
+
+func TestNormalizeMeta(t *testing.T) {
+	m := normalizeMeta(&pb.Meta{Env: []string{"PATH=/bin", "FOO=a=b", "NOVALUE", "FOO=c"}, Cwd: "foo//bar/"})
+	require.Equal(t, []string{"FOO=c", "NOVALUE", "PATH=/bin"}, m.Env)
+	require.Equal(t, "foo/bar", m.Cwd)
+
+	m = normalizeMeta(&pb.Meta{})
+	require.Nil(t, m.Env)
+	require.Equal(t, "", m.Cwd)
+}
+
+func TestExecCacheKeyProxyEnv(t *testing.T) {
+	op := &pb.ExecOp{
+		Meta: &pb.Meta{Args: []string{"true"}},
+	}
+	e := &execOp{op: op}
+	k1, err := e.CacheKey(context.TODO())
+	require.NoError(t, err)
+
+	e.opt.proxyEnv = []string{"HTTP_PROXY=http://proxy:3128"}
+	op.NoProxyEnv = true
+	k2, err := e.CacheKey(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, k1, k2)
+
+	op.ProxyEnv = []string{"http_proxy=http://other:3128"}
+	k3, err := e.CacheKey(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, k1, k3)
+
+	// the variables are still injected when the exec runs
+	op.NoProxyEnv = false
+	env := e.addProxyEnv([]string{"PATH=/bin"})
+	require.Equal(t, []string{"PATH=/bin", "http_proxy=http://other:3128", "HTTP_PROXY=http://proxy:3128"}, env)
+
+	// the variables of the exec are kept
+	env = e.addProxyEnv([]string{"HTTP_PROXY=http://own:3128"})
+	require.Equal(t, []string{"HTTP_PROXY=http://own:3128", "http_proxy=http://other:3128"}, env)
+
+	// only the variables of the op are added with NoProxyEnv
+	op.NoProxyEnv = true
+	env = e.addProxyEnv([]string{"PATH=/bin"})
+	require.Equal(t, []string{"PATH=/bin", "http_proxy=http://other:3128"}, env)
+}
+
+func TestExecCacheKeyWorkerConstraints(t *testing.T) {
+	op := &pb.ExecOp{Meta: &pb.Meta{Args: []string{"true"}}}
+	k1, err := (&execOp{op: op, v: &vertex{}}).CacheKey(context.TODO())
+	require.NoError(t, err)
+	k2, err := (&execOp{op: op, v: &vertex{constraints: &pb.WorkerConstraints{Filter: []string{"gpu=true", "os=linux"}}}}).CacheKey(context.TODO())
+	require.NoError(t, err)
+	require.NotEqual(t, k1, k2)
+
+	// the order of the filters doesn't change the worker
+	k3, err := (&execOp{op: op, v: &vertex{constraints: &pb.WorkerConstraints{Filter: []string{"os=linux", "gpu=true"}}}}).CacheKey(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, k2, k3)
+
+	k4, err := (&execOp{op: op, v: &vertex{constraints: &pb.WorkerConstraints{}}}).CacheKey(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, k1, k4)
+}
+
+func TestExecInvalidProxyEnv(t *testing.T) {
+	_, err := newExecOp(nil, &pb.Op_Exec{Exec: &pb.ExecOp{ProxyEnv: []string{"NO_PROXY=localhost"}}}, nil, nil, nil, nil, execOpt{})
+	require.NoError(t, err)
+	_, err = newExecOp(nil, &pb.Op_Exec{Exec: &pb.ExecOp{ProxyEnv: []string{"PATH=/tmp"}}}, nil, nil, nil, nil, execOpt{})
+	require.Error(t, err)
+	_, err = newExecOp(nil, &pb.Op_Exec{Exec: &pb.ExecOp{ProxyEnv: []string{"Http_Proxy=http://proxy"}}}, nil, nil, nil, nil, execOpt{})
+	require.Error(t, err)
+}
+
+func TestExecRetryable(t *testing.T) {
+	e := &execOp{op: &pb.ExecOp{}}
+	require.True(t, e.retryable(errors.Errorf("foo")))
+
+	e.op.Retry = &pb.RetryPolicy{MaxRetries: 1, ExitCodes: []int32{2, 3}}
+	require.False(t, e.retryable(errors.Errorf("foo")))
+	require.False(t, e.retryable(errors.WithStack(newExecError("", nil, 1, nil))))
+	require.True(t, e.retryable(errors.Wrap(newExecError("", nil, 3, nil), "bar")))
+}
+
+func TestExecError(t *testing.T) {
+	err := newExecError("sha256:foo", []string{"ls"}, 137, nil)
+	require.Equal(t, 9, err.Signal)
+	require.Equal(t, "worker failed running [ls]: exit code 137 (signal 9)", err.Error())
+
+	err = newExecError("sha256:foo", []string{"ls"}, 2, nil)
+	require.Equal(t, 0, err.Signal)
+}
+
+func TestTailWriter(t *testing.T) {
+	buf := &nopWriteCloser{}
+	tw := newTailWriter(buf, 4)
+	tw.Write([]byte("abc"))
+	require.Equal(t, "abc", string(tw.Bytes()))
+	tw.Write([]byte("defg"))
+	require.Equal(t, "defg", string(tw.Bytes()))
+	tw.Write([]byte("h"))
+	require.Equal(t, "efgh", string(tw.Bytes()))
+	require.Equal(t, "abcdefgh", buf.String())
+}
+
+type nopWriteCloser struct {
+	bytes.Buffer
+}
+
+func (*nopWriteCloser) Close() error {
+	return nil
+}
+
+func TestAddDefaultEnv(t *testing.T) {
+	env := []string{"PATH=/bin"}
+	require.Equal(t, []string{"PATH=/bin", "SSH_AUTH_SOCK=/foo"}, addDefaultEnv(env, "SSH_AUTH_SOCK", "/foo"))
+	require.Equal(t, []string{"PATH=/bin"}, env)
+
+	env = []string{"SSH_AUTH_SOCK=/bar"}
+	require.Equal(t, []string{"SSH_AUTH_SOCK=/bar"}, addDefaultEnv(env, "SSH_AUTH_SOCK", "/foo"))
+}
+
+func TestDefaultPathEnv(t *testing.T) {
+	require.Equal(t, system.DefaultPathEnvForOS(runtime.GOOS), defaultPathEnv(nil))
+	require.Equal(t, system.DefaultPathEnvUnix, defaultPathEnv(&pb.Platform{OS: "linux", Architecture: "arm64"}))
+	require.Equal(t, system.DefaultPathEnvWindows, defaultPathEnv(&pb.Platform{OS: "windows", Architecture: "amd64"}))
+}
+
+func TestExecSecurityProfile(t *testing.T) {
+	def := worker.SecurityProfile{Seccomp: `{"defaultAction":"SCMP_ACT_ALLOW"}`, AppArmor: "buildkit-default"}
+	require.Equal(t, def, securityProfile(def, nil))
+	require.Equal(t, worker.SecurityProfile{Seccomp: def.Seccomp, AppArmor: worker.Unconfined, SELinuxLabel: "system_u:system_r:container_t:s0"},
+		securityProfile(def, &pb.SecurityProfile{Apparmor: worker.Unconfined, SelinuxLabel: "system_u:system_r:container_t:s0"}))
+
+	op := &pb.Op_Exec{Exec: &pb.ExecOp{SecurityProfile: &pb.SecurityProfile{Seccomp: "{"}}}
+	_, err := newExecOp(nil, op, nil, nil, nil, nil, execOpt{})
+	require.Error(t, err)
+	op.Exec.SecurityProfile.Seccomp = worker.Unconfined
+	_, err = newExecOp(nil, op, nil, nil, nil, nil, execOpt{})
+	require.NoError(t, err)
+}
+
+func TestExecCacheKeyExtraHosts(t *testing.T) {
+	e1 := &execOp{op: &pb.ExecOp{Meta: &pb.Meta{Args: []string{"true"}, ExtraHosts: []*pb.HostIP{
+		{Host: "b", IP: "10.0.0.2"}, {Host: "A", IP: "10.0.0.1"}, {Host: "a", IP: "10.0.0.3"},
+	}}}}
+	e2 := &execOp{op: &pb.ExecOp{Meta: &pb.Meta{Args: []string{"true"}, ExtraHosts: []*pb.HostIP{
+		{Host: "a", IP: "10.0.0.1"}, {Host: "a", IP: "10.0.0.3"}, {Host: "b", IP: "10.0.0.2"}, {Host: "b", IP: "10.0.0.2"},
+	}}}}
+	k1, err := e1.CacheKey(context.TODO())
+	require.NoError(t, err)
+	k2, err := e2.CacheKey(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, k1, k2)
+
+	// the first address of a host is resolved
+	e2.op.Meta.ExtraHosts[0], e2.op.Meta.ExtraHosts[1] = e2.op.Meta.ExtraHosts[1], e2.op.Meta.ExtraHosts[0]
+	k3, err := e2.CacheKey(context.TODO())
+	require.NoError(t, err)
+	require.NotEqual(t, k1, k3)
+
+	e2.op.Meta.Hostname = "builder"
+	k4, err := e2.CacheKey(context.TODO())
+	require.NoError(t, err)
+	require.NotEqual(t, k3, k4)
+}
+
+func TestExecLazyRoot(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "execlazy")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	cm := newTestCacheMounts(t, tmpdir).cm
+	ctx := context.TODO()
+	lazy := &testLazyRef{pull: func(ctx context.Context) (cache.ImmutableRef, error) {
+		active, err := cm.New(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		return active.Commit(ctx)
+	}}
+
+	op := &pb.Op_Exec{Exec: &pb.ExecOp{
+		Meta:   &pb.Meta{Args: []string{"true"}},
+		Mounts: []*pb.Mount{{Input: 0, Dest: pb.RootMount, Output: 0}},
+	}}
+	e, err := newExecOp(nil, op, cm, &testExecWorker{}, nil, nil, execOpt{})
+	require.NoError(t, err)
+	refs, err := e.Run(ctx, []Reference{lazy})
+	require.NoError(t, err)
+	require.Len(t, refs, 1)
+	require.NotNil(t, lazy.ref)
+
+	ref, ok := toImmutableRef(refs[0])
+	require.True(t, ok)
+	parent := ref.Parent()
+	require.NotNil(t, parent)
+	require.Equal(t, lazy.ref.ID(), parent.ID())
+	require.NoError(t, parent.Release(ctx))
+	require.NoError(t, refs[0].Release(ctx))
+	require.NoError(t, lazy.Release(ctx))
+}
+
+// testLazyRef is an image ref that is only backed by a record once it is
+// pulled
+type testLazyRef struct {
+	cache.ImmutableRef
+	pull func(context.Context) (cache.ImmutableRef, error)
+	ref  cache.ImmutableRef
+}
+
+func (r *testLazyRef) ID() string {
+	return "sha256:unpulled"
+}
+
+func (r *testLazyRef) Resolve(ctx context.Context) (cache.ImmutableRef, error) {
+	if r.ref == nil {
+		ref, err := r.pull(ctx)
+		if err != nil {
+			return nil, err
+		}
+		r.ref = ref
+	}
+	return r.ref, nil
+}
+
+func (r *testLazyRef) Parent() cache.ImmutableRef {
+	return nil
+}
+
+func (r *testLazyRef) Release(ctx context.Context) error {
+	if r.ref == nil {
+		return nil
+	}
+	return r.ref.Release(ctx)
+}
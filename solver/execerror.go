@@ -0,0 +1,83 @@
+package solver
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/moby/buildkit/client"
+	digest "github.com/opencontainers/go-digest"
+)
+
+// execErrorStderrSize is the amount of error output kept for an ExecError
+const execErrorStderrSize = 4 * 1024
+
+// ExecError is returned when the process of an exec op exits with an error
+type ExecError struct {
+	Vertex   digest.Digest
+	Args     []string
+	ExitCode uint32
+	// Signal is set if the process was killed by a signal
+	Signal int
+	// Stderr contains the end of the error output of the process
+	Stderr []byte
+}
+
+func newExecError(v digest.Digest, args []string, exitCode uint32, stderr []byte) *ExecError {
+	e := &ExecError{
+		Vertex:   v,
+		Args:     args,
+		ExitCode: exitCode,
+		Stderr:   stderr,
+	}
+	// processes killed by a signal are reported with exit code 128+signal
+	if exitCode > 128 && exitCode <= 128+64 {
+		e.Signal = int(exitCode - 128)
+	}
+	return e
+}
+
+func (e *ExecError) Error() string {
+	if e.Signal != 0 {
+		return fmt.Sprintf("worker failed running %v: exit code %d (signal %d)", e.Args, e.ExitCode, e.Signal)
+	}
+	return fmt.Sprintf("worker failed running %v: exit code %d", e.Args, e.ExitCode)
+}
+
+func (e *ExecError) toClient() *client.ExecError {
+	return &client.ExecError{
+		Vertex:   e.Vertex,
+		Args:     e.Args,
+		ExitCode: e.ExitCode,
+		Signal:   e.Signal,
+		Stderr:   e.Stderr,
+	}
+}
+
+// tailWriter passes writes through to w and keeps the last size bytes
+type tailWriter struct {
+	io.WriteCloser
+	mu   sync.Mutex
+	buf  []byte
+	size int
+}
+
+func newTailWriter(w io.WriteCloser, size int) *tailWriter {
+	return &tailWriter{WriteCloser: w, size: size}
+}
+
+func (tw *tailWriter) Write(dt []byte) (int, error) {
+	tw.mu.Lock()
+	tw.buf = append(tw.buf, dt...)
+	if len(tw.buf) > tw.size {
+		tw.buf = append([]byte{}, tw.buf[len(tw.buf)-tw.size:]...)
+	}
+	tw.mu.Unlock()
+	return tw.WriteCloser.Write(dt)
+}
+
+func (tw *tailWriter) Bytes() []byte {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	return append([]byte{}, tw.buf...)
+}
@@ -0,0 +1,77 @@
+package solver
+
+import (
+	"path"
+	"runtime"
+	"strings"
+
+	"github.com/moby/buildkit/solver/pb"
+)
+
+// platformOS returns the os of the platform p, the os of the daemon if p is
+// nil
+func platformOS(p *pb.Platform) string {
+	if p != nil && p.OS != "" {
+		return p.OS
+	}
+	return runtime.GOOS
+}
+
+// splitExecPath returns the drive letter and the slash separated path of
+// the path p of an exec for os. Windows paths can use backslashes and start
+// with a drive letter. The system drive C: is removed so that C:\src and
+// /src are the same path, and the root is pb.RootMount.
+func splitExecPath(goos, p string) (string, string) {
+	if goos != "windows" {
+		return "", p
+	}
+	p = strings.Replace(p, `\`, "/", -1)
+	if len(p) < 2 || p[1] != ':' || !isDriveLetter(p[0]) {
+		return "", p
+	}
+	drive := strings.ToUpper(p[:1])
+	if drive == "C" {
+		return "", p[2:]
+	}
+	return drive + ":", p[2:]
+}
+
+func isDriveLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// isAbsExecPath checks that the path p of an exec for os is absolute
+func isAbsExecPath(goos, p string) bool {
+	_, p = splitExecPath(goos, p)
+	return path.IsAbs(p)
+}
+
+// cleanExecPath returns the clean form of the absolute path p of an exec for
+// os, eg. the destination of a mount that the worker converts to the paths
+// of its platform
+func cleanExecPath(goos, p string) string {
+	drive, p := splitExecPath(goos, p)
+	return drive + path.Clean(p)
+}
+
+// normalizeExecPaths returns the exec op with the mount destinations and the
+// working directory of the windows execs in the clean slash separated form.
+// The paths of the other execs are kept as they are part of the cache keys.
+func normalizeExecPaths(goos string, op *pb.ExecOp) *pb.ExecOp {
+	if goos != "windows" {
+		return op
+	}
+	nop := *op
+	if op.Meta != nil && op.Meta.Cwd != "" {
+		meta := *op.Meta
+		meta.Cwd = cleanExecPath(goos, meta.Cwd)
+		nop.Meta = &meta
+	}
+	nop.Mounts = make([]*pb.Mount, len(op.Mounts))
+	for i, m := range op.Mounts {
+		nm := *m
+		nm.Dest = cleanExecPath(goos, m.Dest)
+		nop.Mounts[i] = &nm
+	}
+	return &nop
+}
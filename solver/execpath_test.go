@@ -0,0 +1,39 @@
+package solver
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecPaths(t *testing.T) {
+	require.Equal(t, runtime.GOOS, platformOS(nil))
+	require.Equal(t, "windows", platformOS(&pb.Platform{OS: "windows", Architecture: "amd64"}))
+
+	for _, p := range []string{`C:\src`, `c:/src`, `\src`, `D:\`, "/"} {
+		require.True(t, isAbsExecPath("windows", p), p)
+	}
+	for _, p := range []string{`C:`, `C:src`, `src`, `D:src`} {
+		require.False(t, isAbsExecPath("windows", p), p)
+	}
+	require.False(t, isAbsExecPath("linux", `C:\src`))
+
+	require.Equal(t, "/src", cleanExecPath("windows", `C:\src\`))
+	require.Equal(t, "/", cleanExecPath("windows", `c:\`))
+	require.Equal(t, "D:/src/app", cleanExecPath("windows", `d:\src\\app`))
+	require.Equal(t, `/c:\src`, cleanExecPath("linux", `/c:\src/`))
+
+	op := &pb.ExecOp{
+		Meta:   &pb.Meta{Args: []string{"cmd"}, Cwd: `C:\src`},
+		Mounts: []*pb.Mount{{Dest: `C:\`}, {Dest: `D:\cache\`}},
+	}
+	require.Equal(t, op, normalizeExecPaths("linux", op))
+	nop := normalizeExecPaths("windows", op)
+	require.Equal(t, "/src", nop.Meta.Cwd)
+	require.Equal(t, pb.RootMount, nop.Mounts[0].Dest)
+	require.Equal(t, "D:/cache", nop.Mounts[1].Dest)
+	require.Equal(t, `C:\src`, op.Meta.Cwd)
+	require.Equal(t, `C:\`, op.Mounts[0].Dest)
+}
@@ -0,0 +1,354 @@
+package solver
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/cache/contenthash"
+	"github.com/moby/buildkit/snapshot"
+	"github.com/moby/buildkit/solver/pb"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+	"golang.org/x/sync/errgroup"
+)
+
+const fileCacheType = "buildkit.file.v0"
+
+// fileOp runs the file actions of a FileOp natively, without a container
+type fileOp struct {
+	v         Vertex
+	op        *pb.FileOp
+	cm        cache.Manager
+	numInputs int
+	outputs   int
+}
+
+func newFileOp(v Vertex, op *pb.Op_File, cm cache.Manager) (Op, error) {
+	f := &fileOp{
+		v:         v,
+		op:        op.File,
+		cm:        cm,
+		numInputs: len(v.Inputs()),
+	}
+	if err := f.validate(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// validate checks that the actions only use the op inputs and the results of
+// the previous actions and that every output is set by exactly one action
+func (f *fileOp) validate() error {
+	if len(f.op.Actions) == 0 {
+		return errors.Errorf("file op without actions")
+	}
+	outputs := map[pb.OutputIndex]struct{}{}
+	for i, a := range f.op.Actions {
+		if a.Action == nil {
+			return errors.Errorf("file action %d is empty", i)
+		}
+		// the actions can refer to the results of the previous actions
+		if a.Input != pb.Empty {
+			if err := checkInputIndex(vertexDigest(f.v), fmt.Sprintf("file action %d", i), a.Input, f.numInputs+i); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		if _, ok := a.Action.(*pb.FileAction_Copy); ok {
+			if err := checkInputIndex(vertexDigest(f.v), fmt.Sprintf("copy source of file action %d", i), a.SecondaryInput, f.numInputs+i); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		if a.Output != pb.SkipOutput {
+			if a.Output < 0 {
+				return errors.Errorf("invalid output %d for file action %d", a.Output, i)
+			}
+			if _, ok := outputs[a.Output]; ok {
+				return errors.Errorf("duplicate output %d for file action %d", a.Output, i)
+			}
+			outputs[a.Output] = struct{}{}
+		}
+	}
+	for i := 0; i < len(outputs); i++ {
+		if _, ok := outputs[pb.OutputIndex(i)]; !ok {
+			return errors.Errorf("missing file op output %d", i)
+		}
+	}
+	if len(outputs) == 0 {
+		return errors.Errorf("file op without outputs")
+	}
+	f.outputs = len(outputs)
+	return nil
+}
+
+func (f *fileOp) CacheKey(ctx context.Context) (digest.Digest, error) {
+	dt, err := json.Marshal(struct {
+		Type string
+		File *pb.FileOp
+	}{
+		Type: fileCacheType,
+		File: f.op,
+	})
+	if err != nil {
+		return "", err
+	}
+	return digest.FromBytes(dt), nil
+}
+
+// copySource is a path of an op input that is copied by an action
+type copySource struct {
+	index    pb.InputIndex
+	selector contenthash.Selector
+}
+
+// contentSources returns the copy sources that are part of the content based
+// cache key. The sources with wildcards only include the matched paths.
+func (f *fileOp) contentSources() ([]copySource, error) {
+	var srcs []copySource
+	for _, a := range f.op.Actions {
+		c, ok := a.Action.(*pb.FileAction_Copy)
+		if !ok || int(a.SecondaryInput) >= f.numInputs {
+			continue
+		}
+		sel, err := contenthash.NormalizeSelector(c.Copy.Src)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid copy source %s", c.Copy.Src)
+		}
+		srcs = append(srcs, copySource{index: a.SecondaryInput, selector: contenthash.Selector{
+			Path:        sel,
+			Wildcard:    c.Copy.AllowWildcard,
+			FollowLinks: c.Copy.FollowSymlink,
+			Attrs:       contentAttrs(c.Copy.ContentAttrs),
+		}})
+	}
+	return srcs, nil
+}
+
+// ContentKeys uses the content of the copied paths for the copy sources and
+// the definition based keys for all other inputs
+func (f *fileOp) ContentKeys(ctx context.Context, inputs [][]digest.Digest, refs []Reference) ([]digest.Digest, error) {
+	if len(refs) == 0 {
+		return nil, nil
+	}
+	srcs, err := f.contentSources()
+	if err != nil {
+		return nil, err
+	}
+	if len(srcs) == 0 {
+		return nil, nil
+	}
+
+	// the inputs the actions run on use the definition based keys
+	var skipped []int
+	for _, a := range f.op.Actions {
+		if a.Input != pb.Empty && int(a.Input) < f.numInputs {
+			skipped = append(skipped, int(a.Input))
+		}
+	}
+	sort.Ints(skipped)
+	skipped = uniqueInts(skipped)
+
+	// the selectors are only part of the key of the ops with wildcard copies
+	// to keep the keys of the other ops stable
+	var selectors []string
+	for _, s := range srcs {
+		if s.selector.Wildcard {
+			selectors = make([]string, len(srcs))
+			for i, s := range srcs {
+				selectors[i] = s.selector.String()
+			}
+			break
+		}
+	}
+
+	dgsts := make([]digest.Digest, len(srcs))
+	eg, ctx := errgroup.WithContext(ctx)
+	for i, s := range srcs {
+		func(i int, s copySource, ref Reference) {
+			eg.Go(func() error {
+				ref, ok := toImmutableRef(ref)
+				if !ok {
+					return errors.Errorf("invalid reference")
+				}
+				dgst, err := contenthash.ChecksumSelectors(ctx, ref, []contenthash.Selector{s.selector})
+				if err != nil {
+					return err
+				}
+				dgsts[i] = dgst
+				return nil
+			})
+		}(i, s, refs[int(s.index)])
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	var out []digest.Digest
+	inputKeys := make([]digest.Digest, len(skipped))
+	for _, cacheKeys := range inputs {
+		for i := range inputKeys {
+			inputKeys[i] = cacheKeys[skipped[i]]
+		}
+		dt, err := json.Marshal(struct {
+			Type      string
+			Sources   []digest.Digest
+			Selectors []string `json:",omitempty"`
+			Inputs    []digest.Digest
+			File      *pb.FileOp
+		}{
+			Type:      fileCacheType,
+			Sources:   dgsts,
+			Selectors: selectors,
+			Inputs:    inputKeys,
+			File:      f.op,
+		})
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, digest.FromBytes(dt))
+	}
+	return out, nil
+}
+
+func uniqueInts(s []int) []int {
+	out := s[:0]
+	for i, v := range s {
+		if i == 0 || v != s[i-1] {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func (f *fileOp) Run(ctx context.Context, inputs []Reference) ([]Reference, error) {
+	refs := make([]cache.ImmutableRef, len(inputs))
+	for i, inp := range inputs {
+		ref, ok := toImmutableRef(inp)
+		if !ok {
+			return nil, errors.Errorf("invalid reference for file op %T", inp)
+		}
+		refs[i] = ref
+	}
+
+	// results of the actions that are not returned as outputs are released
+	results := make([]cache.ImmutableRef, len(f.op.Actions))
+	outputs := make([]Reference, f.outputs)
+	defer func() {
+		for i, r := range results {
+			if r != nil && f.op.Actions[i].Output == pb.SkipOutput {
+				cache.ReleaseDetached(r)
+			}
+		}
+	}()
+	releaseOutputs := func() {
+		for _, o := range outputs {
+			if o != nil {
+				cache.ReleaseDetached(o)
+			}
+		}
+	}
+
+	input := func(idx pb.InputIndex) cache.ImmutableRef {
+		if idx == pb.Empty {
+			return nil
+		}
+		if int(idx) < len(refs) {
+			return refs[idx]
+		}
+		return results[int(idx)-len(refs)]
+	}
+
+	for i, a := range f.op.Actions {
+		var src cache.ImmutableRef
+		if _, ok := a.Action.(*pb.FileAction_Copy); ok {
+			src = input(a.SecondaryInput)
+		}
+		ref, err := f.runAction(ctx, a, input(a.Input), src)
+		if err != nil {
+			releaseOutputs()
+			return nil, err
+		}
+		results[i] = ref
+		if a.Output != pb.SkipOutput {
+			outputs[a.Output] = ref
+		}
+	}
+	return outputs, nil
+}
+
+func (f *fileOp) runAction(ctx context.Context, a *pb.FileAction, base, src cache.ImmutableRef) (cache.ImmutableRef, error) {
+	active, err := f.cm.New(ctx, base, cache.WithDescription(fmt.Sprintf("file op %s", fileActionName(a))))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if active != nil {
+			active.Release(context.TODO())
+		}
+	}()
+
+	if err := withMountedRef(ctx, active, false, func(root string) error {
+		if _, ok := a.Action.(*pb.FileAction_Copy); !ok {
+			return runFileAction(a, root, "")
+		}
+		if src == nil {
+			return errors.Errorf("missing copy source")
+		}
+		return withMountedRef(ctx, src, true, func(srcRoot string) error {
+			return runFileAction(a, root, srcRoot)
+		})
+	}); err != nil {
+		return nil, errors.Wrapf(err, "failed to %s", fileActionName(a))
+	}
+
+	ref, err := active.Commit(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error committing %s", active.ID())
+	}
+	active = nil
+	updateChecksums(ctx, ref)
+	return ref, nil
+}
+
+func withMountedRef(ctx context.Context, m cache.Mountable, readonly bool, fn func(string) error) error {
+	mounts, err := m.Mount(ctx, readonly)
+	if err != nil {
+		return err
+	}
+	lm := snapshot.LocalMounter(mounts)
+	root, err := lm.Mount()
+	if err != nil {
+		return err
+	}
+	defer lm.Unmount()
+	return fn(root)
+}
+
+// fileOpName describes the actions of a file op for the progress output
+func fileOpName(op *pb.FileOp) string {
+	names := make([]string, 0, len(op.Actions))
+	for _, a := range op.Actions {
+		names = append(names, fileActionName(a))
+	}
+	return strings.Join(names, ", ")
+}
+
+func fileActionName(a *pb.FileAction) string {
+	switch a := a.Action.(type) {
+	case *pb.FileAction_Copy:
+		return fmt.Sprintf("copy %s %s", a.Copy.Src, a.Copy.Dest)
+	case *pb.FileAction_Mkfile:
+		return fmt.Sprintf("mkfile %s", a.Mkfile.Path)
+	case *pb.FileAction_Mkdir:
+		return fmt.Sprintf("mkdir %s", a.Mkdir.Path)
+	case *pb.FileAction_Rm:
+		return fmt.Sprintf("rm %s", a.Rm.Path)
+	case *pb.FileAction_Symlink:
+		return fmt.Sprintf("symlink %s -> %s", a.Symlink.Newpath, a.Symlink.Oldpath)
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,243 @@
+package solver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileOpValidate(t *testing.T) {
+	mkdir := &pb.FileAction_Mkdir{Mkdir: &pb.FileActionMkDir{Path: "/foo"}}
+	cp := &pb.FileAction_Copy{Copy: &pb.FileActionCopy{Src: "/foo", Dest: "/bar"}}
+
+	f := &fileOp{numInputs: 1, op: &pb.FileOp{Actions: []*pb.FileAction{
+		{Input: 0, Output: pb.SkipOutput, Action: mkdir},
+		{Input: 1, SecondaryInput: 0, Output: 0, Action: cp},
+	}}}
+	require.NoError(t, f.validate())
+	require.Equal(t, 1, f.outputs)
+
+	f.op.Actions[0].Input = 1
+	require.Error(t, f.validate())
+	f.op.Actions[0].Input = pb.Empty
+
+	f.op.Actions[1].SecondaryInput = 2
+	require.Error(t, f.validate())
+	f.op.Actions[1].SecondaryInput = 0
+
+	f.op.Actions[0].Output = 0
+	err := f.validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "duplicate output")
+
+	f.op.Actions[1].Output = 1
+	require.NoError(t, f.validate())
+	require.Equal(t, 2, f.outputs)
+
+	f.op.Actions[0].Output = 2
+	err = f.validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "missing file op output")
+}
+
+func TestFileActions(t *testing.T) {
+	root, err := ioutil.TempDir("", "buildkit-fileop")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	created := time.Unix(1500000000, 0)
+	run := func(action interface{}) error {
+		a := &pb.FileAction{}
+		switch action := action.(type) {
+		case *pb.FileActionMkDir:
+			a.Action = &pb.FileAction_Mkdir{Mkdir: action}
+		case *pb.FileActionMkFile:
+			a.Action = &pb.FileAction_Mkfile{Mkfile: action}
+		case *pb.FileActionRm:
+			a.Action = &pb.FileAction_Rm{Rm: action}
+		case *pb.FileActionSymlink:
+			a.Action = &pb.FileAction_Symlink{Symlink: action}
+		}
+		return runFileAction(a, root, "")
+	}
+
+	require.Error(t, run(&pb.FileActionMkDir{Path: "/foo/bar"}))
+	require.NoError(t, run(&pb.FileActionMkDir{Path: "/foo/bar", MakeParents: true, Mode: 0700, Timestamp: created.UnixNano()}))
+	require.NoError(t, run(&pb.FileActionMkDir{Path: "/foo/bar", MakeParents: true}))
+	fi, err := os.Stat(filepath.Join(root, "foo/bar"))
+	require.NoError(t, err)
+	require.True(t, fi.IsDir())
+	require.Equal(t, os.FileMode(0700), fi.Mode().Perm())
+	require.True(t, created.Equal(fi.ModTime()))
+
+	require.NoError(t, run(&pb.FileActionMkFile{Path: "foo/bar/baz", Data: []byte("data")}))
+	fi, err = os.Stat(filepath.Join(root, "foo/bar/baz"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(defaultFileMode), fi.Mode().Perm())
+	dt, err := ioutil.ReadFile(filepath.Join(root, "foo/bar/baz"))
+	require.NoError(t, err)
+	require.Equal(t, "data", string(dt))
+
+	require.Error(t, run(&pb.FileActionMkFile{Path: "/etc/app/config", Data: []byte("{}")}))
+	require.NoError(t, run(&pb.FileActionMkFile{Path: "/etc/app/config", Data: []byte("{}"), MakeParents: true, Timestamp: created.UnixNano()}))
+	fi, err = os.Stat(filepath.Join(root, "etc/app"))
+	require.NoError(t, err)
+	require.True(t, fi.IsDir())
+	require.Equal(t, os.FileMode(defaultDirMode), fi.Mode().Perm())
+	fi, err = os.Stat(filepath.Join(root, "etc/app/config"))
+	require.NoError(t, err)
+	require.True(t, created.Equal(fi.ModTime()))
+	dt, err = ioutil.ReadFile(filepath.Join(root, "etc/app/config"))
+	require.NoError(t, err)
+	require.Equal(t, "{}", string(dt))
+
+	// symlinks are resolved in the root
+	require.NoError(t, run(&pb.FileActionSymlink{Oldpath: "/foo/bar", Newpath: "/link"}))
+	require.NoError(t, run(&pb.FileActionMkFile{Path: "/link/baz2", Data: []byte("data2"), Mode: 0600}))
+	fi, err = os.Stat(filepath.Join(root, "foo/bar/baz2"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+
+	// rm removes the link, not the target
+	require.NoError(t, run(&pb.FileActionRm{Path: "/link"}))
+	_, err = os.Lstat(filepath.Join(root, "link"))
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(root, "foo/bar/baz2"))
+	require.NoError(t, err)
+
+	require.Error(t, run(&pb.FileActionRm{Path: "/link"}))
+	require.NoError(t, run(&pb.FileActionRm{Path: "/link", AllowNotFound: true}))
+	require.NoError(t, run(&pb.FileActionRm{Path: "/foo/bar/baz*", AllowWildcard: true}))
+	names := readDirNames(t, filepath.Join(root, "foo/bar"))
+	require.Equal(t, 0, len(names))
+
+	require.Error(t, run(&pb.FileActionRm{Path: "/"}))
+}
+
+func TestFileCopy(t *testing.T) {
+	src, err := ioutil.TempDir("", "buildkit-fileop-src")
+	require.NoError(t, err)
+	defer os.RemoveAll(src)
+	dest, err := ioutil.TempDir("", "buildkit-fileop-dest")
+	require.NoError(t, err)
+	defer os.RemoveAll(dest)
+
+	created := time.Unix(1500000000, 0)
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "dir/sub"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(src, "dir/foo"), []byte("foo"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(src, "dir/sub/bar"), []byte("bar"), 0640))
+	require.NoError(t, os.Symlink("dir/foo", filepath.Join(src, "link")))
+	require.NoError(t, os.Chtimes(filepath.Join(src, "dir/sub"), created, created))
+
+	run := func(c *pb.FileActionCopy) error {
+		return runFileAction(&pb.FileAction{Action: &pb.FileAction_Copy{Copy: c}}, dest, src)
+	}
+
+	require.NoError(t, run(&pb.FileActionCopy{Src: "/dir", Dest: "/a"}))
+	dt, err := ioutil.ReadFile(filepath.Join(dest, "a/sub/bar"))
+	require.NoError(t, err)
+	require.Equal(t, "bar", string(dt))
+	fi, err := os.Stat(filepath.Join(dest, "a/sub/bar"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0640), fi.Mode().Perm())
+	fi, err = os.Stat(filepath.Join(dest, "a/sub"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0700), fi.Mode().Perm())
+	require.True(t, created.Equal(fi.ModTime()))
+
+	// existing directories get the source copied into them
+	require.NoError(t, run(&pb.FileActionCopy{Src: "/dir", Dest: "/a"}))
+	require.Equal(t, []string{"dir", "foo", "sub"}, readDirNames(t, filepath.Join(dest, "a")))
+
+	require.NoError(t, run(&pb.FileActionCopy{Src: "/dir", Dest: "/b", DirCopyContents: true, Mode: 0755}))
+	require.Equal(t, []string{"foo", "sub"}, readDirNames(t, filepath.Join(dest, "b")))
+	fi, err = os.Stat(filepath.Join(dest, "b/foo"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0755), fi.Mode().Perm())
+
+	require.Error(t, run(&pb.FileActionCopy{Src: "/dir/foo", Dest: "/c/d/foo"}))
+	require.NoError(t, run(&pb.FileActionCopy{Src: "/dir/foo", Dest: "/c/d/foo", CreateDestPath: true}))
+	dt, err = ioutil.ReadFile(filepath.Join(dest, "c/d/foo"))
+	require.NoError(t, err)
+	require.Equal(t, "foo", string(dt))
+
+	require.NoError(t, run(&pb.FileActionCopy{Src: "/link", Dest: "/e/", CreateDestPath: true}))
+	l, err := os.Readlink(filepath.Join(dest, "e/link"))
+	require.NoError(t, err)
+	require.Equal(t, "dir/foo", l)
+
+	require.NoError(t, run(&pb.FileActionCopy{Src: "/link", Dest: "/f", FollowSymlink: true}))
+	fi, err = os.Lstat(filepath.Join(dest, "f"))
+	require.NoError(t, err)
+	require.True(t, fi.Mode().IsRegular())
+
+	require.NoError(t, run(&pb.FileActionCopy{Src: "/dir/*", Dest: "/g/", AllowWildcard: true, CreateDestPath: true, Timestamp: created.UnixNano()}))
+	require.Equal(t, []string{"foo", "sub"}, readDirNames(t, filepath.Join(dest, "g")))
+	fi, err = os.Stat(filepath.Join(dest, "g/foo"))
+	require.NoError(t, err)
+	require.True(t, created.Equal(fi.ModTime()))
+
+	err = run(&pb.FileActionCopy{Src: "/dir/nomatch*", Dest: "/h/", AllowWildcard: true})
+	require.Error(t, err)
+	require.Error(t, run(&pb.FileActionCopy{Src: "/nosuchfile", Dest: "/h"}))
+}
+
+func TestFileCopySymlinkDest(t *testing.T) {
+	src, err := ioutil.TempDir("", "buildkit-fileop-src")
+	require.NoError(t, err)
+	defer os.RemoveAll(src)
+	dest, err := ioutil.TempDir("", "buildkit-fileop-dest")
+	require.NoError(t, err)
+	defer os.RemoveAll(dest)
+	outside, err := ioutil.TempDir("", "buildkit-fileop-outside")
+	require.NoError(t, err)
+	defer os.RemoveAll(outside)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "dir/sub"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(src, "dir/foo"), []byte("foo"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(src, "dir/sub/bar"), []byte("bar"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(outside, "foo"), []byte("host"), 0644))
+
+	// the destination has symlinks to a file and to a directory outside of
+	// the mount where the copy writes
+	require.NoError(t, os.MkdirAll(filepath.Join(dest, "a"), 0755))
+	require.NoError(t, os.Symlink(filepath.Join(outside, "foo"), filepath.Join(dest, "a/foo")))
+	require.NoError(t, os.Symlink(outside, filepath.Join(dest, "a/sub")))
+
+	err = runFileAction(&pb.FileAction{Action: &pb.FileAction_Copy{Copy: &pb.FileActionCopy{Src: "/dir", Dest: "/a", DirCopyContents: true}}}, dest, src)
+	require.NoError(t, err)
+
+	dt, err := ioutil.ReadFile(filepath.Join(outside, "foo"))
+	require.NoError(t, err)
+	require.Equal(t, "host", string(dt))
+	fi, err := os.Stat(filepath.Join(outside, "foo"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0644), fi.Mode().Perm())
+	require.Equal(t, []string{"foo"}, readDirNames(t, outside))
+
+	fi, err = os.Lstat(filepath.Join(dest, "a/foo"))
+	require.NoError(t, err)
+	require.True(t, fi.Mode().IsRegular())
+	fi, err = os.Lstat(filepath.Join(dest, "a/sub"))
+	require.NoError(t, err)
+	require.True(t, fi.IsDir())
+	dt, err = ioutil.ReadFile(filepath.Join(dest, "a/sub/bar"))
+	require.NoError(t, err)
+	require.Equal(t, "bar", string(dt))
+}
+
+func readDirNames(t *testing.T, dir string) []string {
+	f, err := os.Open(dir)
+	require.NoError(t, err)
+	defer f.Close()
+	names, err := f.Readdirnames(-1)
+	require.NoError(t, err)
+	sort.Strings(names)
+	return names
+}
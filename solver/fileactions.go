@@ -0,0 +1,469 @@
+package solver
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/containerd/containerd/fs"
+	"github.com/docker/docker/pkg/system"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultFileMode = 0644
+	defaultDirMode  = 0755
+)
+
+// runFileAction applies a on the writable mount at root. srcRoot is the
+// read-only mount of the copy source.
+func runFileAction(a *pb.FileAction, root, srcRoot string) error {
+	switch a := a.Action.(type) {
+	case *pb.FileAction_Copy:
+		return fileCopy(root, srcRoot, a.Copy)
+	case *pb.FileAction_Mkfile:
+		return fileMkfile(root, a.Mkfile)
+	case *pb.FileAction_Mkdir:
+		return fileMkdir(root, a.Mkdir)
+	case *pb.FileAction_Rm:
+		return fileRm(root, a.Rm)
+	case *pb.FileAction_Symlink:
+		return fileSymlink(root, a.Symlink)
+	default:
+		return errors.Errorf("unknown file action %T", a)
+	}
+}
+
+// rootPathParent resolves the parent directory of p in root and joins the
+// last component without following it if it is a symlink
+func rootPathParent(root, p string) (string, error) {
+	p = filepath.Join("/", p)
+	if p == "/" {
+		return root, nil
+	}
+	dir, err := fs.RootPath(root, filepath.Dir(p))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, filepath.Base(p)), nil
+}
+
+func fileMode(mode int32, def os.FileMode) os.FileMode {
+	if mode == 0 {
+		return def
+	}
+	return os.FileMode(mode) & os.ModePerm
+}
+
+func fileTime(ts int64) time.Time {
+	if ts == 0 {
+		return time.Now()
+	}
+	return time.Unix(0, ts)
+}
+
+// setFileMeta sets the permissions, the owner and the modification time of p.
+// Symlinks only get the owner and the time set. The metadata is never set on
+// the target of a symlink so it can't change files outside of the mount.
+func setFileMeta(p string, mode os.FileMode, owner *pb.ChownOpt, t time.Time, link bool) error {
+	if !link {
+		fi, err := os.Lstat(p)
+		if err != nil {
+			return errors.Wrapf(err, "failed to stat %s", p)
+		}
+		if fi.Mode()&os.ModeSymlink != 0 {
+			return errors.Errorf("unexpected symlink %s", p)
+		}
+		if err := os.Chmod(p, mode); err != nil {
+			return errors.Wrapf(err, "failed to chmod %s", p)
+		}
+	}
+	if owner != nil {
+		if err := os.Lchown(p, int(owner.Uid), int(owner.Gid)); err != nil {
+			return errors.Wrapf(err, "failed to chown %s", p)
+		}
+	}
+	return setFileTime(p, t, link)
+}
+
+// setFileTime sets the access and modification times of p without following
+// it if it is a symlink
+func setFileTime(p string, t time.Time, link bool) error {
+	ts := syscall.NsecToTimespec(t.UnixNano())
+	if err := system.LUtimesNano(p, []syscall.Timespec{ts, ts}); err != nil {
+		if err != system.ErrNotSupportedPlatform {
+			return errors.Wrapf(err, "failed to set times of %s", p)
+		}
+		if link {
+			return nil
+		}
+		if err := os.Chtimes(p, t, t); err != nil {
+			return errors.Wrapf(err, "failed to set times of %s", p)
+		}
+	}
+	return nil
+}
+
+func fileMkfile(root string, m *pb.FileActionMkFile) error {
+	if m.MakeParents {
+		if err := fileMkdir(root, &pb.FileActionMkDir{
+			Path:        filepath.Dir(filepath.Join("/", m.Path)),
+			MakeParents: true,
+			Owner:       m.Owner,
+			Timestamp:   m.Timestamp,
+		}); err != nil {
+			return err
+		}
+	}
+	p, err := fs.RootPath(root, m.Path)
+	if err != nil {
+		return err
+	}
+	mode := fileMode(m.Mode, defaultFileMode)
+	f, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s", m.Path)
+	}
+	if _, err := f.Write(m.Data); err != nil {
+		f.Close()
+		return errors.Wrapf(err, "failed to write %s", m.Path)
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrapf(err, "failed to write %s", m.Path)
+	}
+	return setFileMeta(p, mode, m.Owner, fileTime(m.Timestamp), false)
+}
+
+func fileMkdir(root string, m *pb.FileActionMkDir) error {
+	mode := fileMode(m.Mode, defaultDirMode)
+	t := fileTime(m.Timestamp)
+	if !m.MakeParents {
+		p, err := rootPathParent(root, m.Path)
+		if err != nil {
+			return err
+		}
+		if err := os.Mkdir(p, mode); err != nil {
+			return errors.Wrapf(err, "failed to create directory %s", m.Path)
+		}
+		return setFileMeta(p, mode, m.Owner, t, false)
+	}
+
+	// the missing parents are created with the same mode, owner and time.
+	// Existing directories are not changed.
+	var dir string
+	for _, c := range strings.Split(filepath.Clean(filepath.Join("/", m.Path)), string(filepath.Separator)) {
+		if c == "" {
+			continue
+		}
+		dir = filepath.Join(dir, c)
+		p, err := fs.RootPath(root, dir)
+		if err != nil {
+			return err
+		}
+		fi, err := os.Stat(p)
+		if err == nil {
+			if !fi.IsDir() {
+				return errors.Errorf("%s is not a directory", dir)
+			}
+			continue
+		}
+		if !os.IsNotExist(err) {
+			return errors.Wrapf(err, "failed to stat %s", dir)
+		}
+		if err := os.Mkdir(p, mode); err != nil {
+			return errors.Wrapf(err, "failed to create directory %s", dir)
+		}
+		if err := setFileMeta(p, mode, m.Owner, t, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func fileRm(root string, r *pb.FileActionRm) error {
+	p, err := rootPathParent(root, r.Path)
+	if err != nil {
+		return err
+	}
+	if p == root {
+		return errors.Errorf("removing the root directory is not allowed")
+	}
+	paths := []string{p}
+	if r.AllowWildcard {
+		paths, err = globBase(p)
+		if err != nil {
+			return errors.Wrapf(err, "invalid pattern %s", r.Path)
+		}
+	} else if _, err := os.Lstat(p); err != nil {
+		paths = nil
+		if !os.IsNotExist(err) {
+			return errors.Wrapf(err, "failed to stat %s", r.Path)
+		}
+	}
+	if len(paths) == 0 {
+		if r.AllowNotFound {
+			return nil
+		}
+		return errors.Errorf("%s not found", r.Path)
+	}
+	for _, p := range paths {
+		if err := os.RemoveAll(p); err != nil {
+			return errors.Wrapf(err, "failed to remove %s", p)
+		}
+	}
+	return nil
+}
+
+func fileSymlink(root string, s *pb.FileActionSymlink) error {
+	p, err := rootPathParent(root, s.Newpath)
+	if err != nil {
+		return err
+	}
+	if err := os.Symlink(s.Oldpath, p); err != nil {
+		return errors.Wrapf(err, "failed to create symlink %s", s.Newpath)
+	}
+	return setFileMeta(p, 0, s.Owner, fileTime(s.Timestamp), true)
+}
+
+func fileCopy(root, srcRoot string, c *pb.FileActionCopy) error {
+	srcs, err := copySourcePaths(srcRoot, c)
+	if err != nil {
+		return err
+	}
+
+	dest, err := fs.RootPath(root, c.Dest)
+	if err != nil {
+		return err
+	}
+	// sources are copied into dest if it is a directory, otherwise dest is
+	// the path of the copy
+	intoDir := strings.HasSuffix(c.Dest, "/") || len(srcs) > 1
+	if fi, err := os.Stat(dest); err == nil && fi.IsDir() {
+		intoDir = true
+	}
+
+	for _, src := range srcs {
+		fi, err := os.Lstat(src)
+		if err != nil {
+			return errors.Wrapf(err, "failed to stat %s", src)
+		}
+		target := dest
+		contents := fi.IsDir() && c.DirCopyContents
+		if intoDir && !contents {
+			target = filepath.Join(dest, filepath.Base(src))
+		}
+		parent := filepath.Dir(target)
+		if contents || intoDir {
+			parent = dest
+		}
+		if c.CreateDestPath {
+			if err := os.MkdirAll(parent, defaultDirMode); err != nil {
+				return errors.Wrapf(err, "failed to create %s", parent)
+			}
+		}
+		if err := copyTree(src, target, contents, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// globBase returns the paths in the directory of p with a name matching the
+// last component of p. Only the last component is a pattern so the mount
+// directory can't affect the matches.
+func globBase(p string) ([]string, error) {
+	dir, pattern := filepath.Split(p)
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	f, err := os.Open(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	names, err := f.Readdirnames(-1)
+	f.Close()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	var matches []string
+	for _, n := range names {
+		if ok, _ := filepath.Match(pattern, n); ok {
+			matches = append(matches, filepath.Join(dir, n))
+		}
+	}
+	return matches, nil
+}
+
+// copySourcePaths returns the paths in srcRoot that are copied by c
+func copySourcePaths(srcRoot string, c *pb.FileActionCopy) ([]string, error) {
+	resolve := func(p string) (string, error) {
+		if c.FollowSymlink {
+			return fs.RootPath(srcRoot, p)
+		}
+		return rootPathParent(srcRoot, p)
+	}
+
+	if !c.AllowWildcard {
+		p, err := resolve(c.Src)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Lstat(p); err != nil {
+			return nil, errors.Wrapf(err, "failed to stat %s", c.Src)
+		}
+		return []string{p}, nil
+	}
+
+	pattern, err := rootPathParent(srcRoot, c.Src)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := globBase(pattern)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid pattern %s", c.Src)
+	}
+	if len(matches) == 0 {
+		return nil, errors.Errorf("no source files found for %s", c.Src)
+	}
+	paths := make([]string, 0, len(matches))
+	for _, m := range matches {
+		rel, err := filepath.Rel(srcRoot, m)
+		if err != nil {
+			return nil, err
+		}
+		p, err := resolve(rel)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, p)
+	}
+	return paths, nil
+}
+
+// copyTree copies src to dest. If contents is set, the entries of the src
+// directory are copied into the existing dest directory.
+func copyTree(src, dest string, contents bool, c *pb.FileActionCopy) error {
+	type dirTime struct {
+		path string
+		t    time.Time
+	}
+	// the times of the directories are set after their contents are copied
+	var dirs []dirTime
+
+	err := filepath.Walk(src, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dest, rel)
+		if contents && rel == "." {
+			if err := os.MkdirAll(target, defaultDirMode); err != nil {
+				return errors.Wrapf(err, "failed to create %s", target)
+			}
+			return nil
+		}
+
+		link := false
+		switch {
+		case fi.IsDir():
+			if tfi, err := os.Lstat(target); err == nil {
+				if tfi.IsDir() {
+					break
+				}
+				// a symlink in the destination is replaced, not followed
+				if err := os.Remove(target); err != nil {
+					return errors.Wrapf(err, "failed to remove %s", target)
+				}
+			}
+			if err := os.Mkdir(target, defaultDirMode); err != nil {
+				return errors.Wrapf(err, "failed to create directory %s", target)
+			}
+		case fi.Mode()&os.ModeSymlink != 0:
+			link = true
+			l, err := os.Readlink(p)
+			if err != nil {
+				return errors.Wrapf(err, "failed to read link %s", p)
+			}
+			if err := os.RemoveAll(target); err != nil {
+				return errors.Wrapf(err, "failed to remove %s", target)
+			}
+			if err := os.Symlink(l, target); err != nil {
+				return errors.Wrapf(err, "failed to create symlink %s", target)
+			}
+		case fi.Mode().IsRegular():
+			if err := copyFileContent(p, target); err != nil {
+				return err
+			}
+		default:
+			return errors.Errorf("unsupported file type of %s", p)
+		}
+
+		mode := fi.Mode() & (os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky)
+		if c.Mode != 0 {
+			mode = os.FileMode(c.Mode) & os.ModePerm
+		}
+		owner := c.Owner
+		if owner == nil {
+			owner = fileOwner(fi)
+		}
+		t := fi.ModTime()
+		if c.Timestamp != 0 {
+			t = time.Unix(0, c.Timestamp)
+		}
+		if fi.IsDir() {
+			dirs = append(dirs, dirTime{target, t})
+		}
+		return setFileMeta(target, mode, owner, t, link)
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if err := setFileTime(dirs[i].path, dirs[i].t, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyFileContent copies the regular file src to dest. An existing dest is
+// removed first and the new file is created exclusively so that a symlink in
+// the destination is never followed.
+func copyFileContent(src, dest string) error {
+	sf, err := os.Open(src)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open %s", src)
+	}
+	defer sf.Close()
+
+	if fi, err := os.Lstat(dest); err == nil {
+		if fi.IsDir() {
+			return errors.Errorf("cannot overwrite directory %s with a file", dest)
+		}
+		if err := os.Remove(dest); err != nil {
+			return errors.Wrapf(err, "failed to remove %s", dest)
+		}
+	}
+	df, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_EXCL, defaultFileMode)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s", dest)
+	}
+	if _, err := io.Copy(df, sf); err != nil {
+		df.Close()
+		return errors.Wrapf(err, "failed to copy %s", src)
+	}
+	return errors.Wrapf(df.Close(), "failed to copy %s", src)
+}
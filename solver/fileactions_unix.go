@@ -0,0 +1,19 @@
+// +build !windows
+
+package solver
+
+import (
+	"os"
+	"syscall"
+
+	"github.com/moby/buildkit/solver/pb"
+)
+
+// fileOwner returns the owner of the file described by fi
+func fileOwner(fi os.FileInfo) *pb.ChownOpt {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+	return &pb.ChownOpt{Uid: st.Uid, Gid: st.Gid}
+}
@@ -0,0 +1,14 @@
+// +build windows
+
+package solver
+
+import (
+	"os"
+
+	"github.com/moby/buildkit/solver/pb"
+)
+
+// fileOwner returns nil as the files don't have a numeric owner on windows
+func fileOwner(fi os.FileInfo) *pb.ChownOpt {
+	return nil
+}
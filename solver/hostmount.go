@@ -0,0 +1,62 @@
+package solver
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/containerd/containerd/mount"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// validateHostMount checks the options of a host mount. The path has to be
+// allowed by the daemon.
+func validateHostMount(m *pb.Mount, allowed []string) error {
+	if m.HostOpt == nil {
+		return errors.Errorf("missing host mount options for %s", m.Dest)
+	}
+	if !m.Readonly || m.Output != pb.SkipOutput {
+		return errors.Errorf("host mount %s must be read-only without output", m.Dest)
+	}
+	if m.HostOpt.Version == "" {
+		return errors.Errorf("host mount %s requires a version for the cache key", m.Dest)
+	}
+	_, err := allowedHostPath(m.HostOpt.Path, allowed)
+	return err
+}
+
+// allowedHostPath returns the path with its symlinks resolved if it is one
+// of the allowed paths or under one of them
+func allowedHostPath(p string, allowed []string) (string, error) {
+	if !filepath.IsAbs(p) {
+		return "", errors.Errorf("host path %s is not absolute", p)
+	}
+	resolved, err := filepath.EvalSymlinks(p)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to resolve host path %s", p)
+	}
+	for _, a := range allowed {
+		a = filepath.Clean(a)
+		if r, err := filepath.EvalSymlinks(a); err == nil {
+			a = r
+		}
+		if resolved == a || strings.HasPrefix(resolved, a+string(filepath.Separator)) || a == string(filepath.Separator) {
+			return resolved, nil
+		}
+	}
+	return "", errors.Errorf("host path %s is not allowed by the daemon", p)
+}
+
+// hostMount is a read-only bind mount of a host path
+type hostMount struct {
+	path string
+}
+
+func (hm *hostMount) Mount(ctx context.Context, readonly bool) ([]mount.Mount, error) {
+	return []mount.Mount{{
+		Type:    "bind",
+		Source:  hm.path,
+		Options: []string{"rbind", "ro"},
+	}}, nil
+}
@@ -0,0 +1,97 @@
+package solver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestHostMount(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "buildkit-hostmount")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+	tmpdir, err = filepath.EvalSymlinks(tmpdir)
+	require.NoError(t, err)
+
+	mirror := filepath.Join(tmpdir, "mirror")
+	require.NoError(t, os.MkdirAll(filepath.Join(mirror, "debian"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpdir, "other"), 0755))
+	require.NoError(t, os.Symlink("../other", filepath.Join(mirror, "escape")))
+
+	newHostMount := func(p string) *pb.Mount {
+		return &pb.Mount{
+			Dest:      "/mirror",
+			Input:     pb.Empty,
+			Output:    pb.SkipOutput,
+			Readonly:  true,
+			MountType: pb.MountType_HOST,
+			HostOpt:   &pb.HostOpt{Path: p, Version: "v1"},
+		}
+	}
+	op := &pb.Op_Exec{Exec: &pb.ExecOp{
+		Meta:   &pb.Meta{Args: []string{"true"}},
+		Mounts: []*pb.Mount{{Dest: pb.RootMount}, newHostMount(filepath.Join(mirror, "debian"))},
+	}}
+	_, err = newExecOp(nil, op, nil, nil, nil, nil, execOpt{})
+	require.Error(t, err)
+	_, err = newExecOp(nil, op, nil, nil, nil, nil, execOpt{hostMounts: []string{mirror}})
+	require.NoError(t, err)
+
+	// the symlinks can't escape the allowed paths
+	op.Exec.Mounts[1] = newHostMount(filepath.Join(mirror, "escape"))
+	_, err = newExecOp(nil, op, nil, nil, nil, nil, execOpt{hostMounts: []string{mirror}})
+	require.Error(t, err)
+
+	op.Exec.Mounts[1] = newHostMount("mirror")
+	_, err = newExecOp(nil, op, nil, nil, nil, nil, execOpt{hostMounts: []string{mirror}})
+	require.Error(t, err)
+
+	m := newHostMount(mirror)
+	m.HostOpt.Version = ""
+	op.Exec.Mounts[1] = m
+	_, err = newExecOp(nil, op, nil, nil, nil, nil, execOpt{hostMounts: []string{mirror}})
+	require.Error(t, err)
+
+	m = newHostMount(mirror)
+	m.Readonly = false
+	op.Exec.Mounts[1] = m
+	_, err = newExecOp(nil, op, nil, nil, nil, nil, execOpt{hostMounts: []string{mirror}})
+	require.Error(t, err)
+
+	mounts, err := (&hostMount{path: mirror}).Mount(context.TODO(), false)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(mounts))
+	require.Equal(t, "bind", mounts[0].Type)
+	require.Equal(t, mirror, mounts[0].Source)
+	require.Equal(t, []string{"rbind", "ro"}, mounts[0].Options)
+}
+
+func TestExecCacheKeyHostMount(t *testing.T) {
+	op := &pb.ExecOp{
+		Meta: &pb.Meta{Args: []string{"true"}},
+		Mounts: []*pb.Mount{
+			{Dest: pb.RootMount},
+			{Dest: "/mirror", Input: pb.Empty, Output: pb.SkipOutput, Readonly: true, MountType: pb.MountType_HOST, HostOpt: &pb.HostOpt{Path: "/srv/mirror", Version: "v1"}},
+		},
+	}
+	e := &execOp{op: op}
+	k1, err := e.CacheKey(context.TODO())
+	require.NoError(t, err)
+
+	// the path is not part of the cache key
+	op.Mounts[1].HostOpt.Path = "/data/mirror"
+	k2, err := e.CacheKey(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, k1, k2)
+	require.Equal(t, "/data/mirror", op.Mounts[1].HostOpt.Path)
+
+	op.Mounts[1].HostOpt.Version = "v2"
+	k3, err := e.CacheKey(context.TODO())
+	require.NoError(t, err)
+	require.NotEqual(t, k1, k3)
+}
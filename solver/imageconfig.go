@@ -0,0 +1,58 @@
+package solver
+
+import (
+	"sync"
+
+	"github.com/BurntSushi/locker"
+	"github.com/containerd/containerd/platforms"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"golang.org/x/net/context"
+)
+
+// imageConfigCache resolves every image config only once for a solve so
+// that the frontends don't resolve the same images with the registry again
+type imageConfigCache struct {
+	resolveImageConfig
+	locker *locker.Locker
+	mu     sync.Mutex
+	cache  map[string]imageConfigResult
+}
+
+type imageConfigResult struct {
+	dgst   digest.Digest
+	config []byte
+}
+
+func newImageConfigCache(r resolveImageConfig) *imageConfigCache {
+	return &imageConfigCache{
+		resolveImageConfig: r,
+		locker:             locker.NewLocker(),
+		cache:              map[string]imageConfigResult{},
+	}
+}
+
+func (c *imageConfigCache) ResolveImageConfig(ctx context.Context, ref string, platform *ocispec.Platform) (digest.Digest, []byte, error) {
+	key := ref
+	if platform != nil {
+		key += "@" + platforms.Format(platforms.Normalize(*platform))
+	}
+	c.locker.Lock(key)
+	defer c.locker.Unlock(key)
+
+	c.mu.Lock()
+	res, ok := c.cache[key]
+	c.mu.Unlock()
+	if ok {
+		return res.dgst, res.config, nil
+	}
+
+	dgst, config, err := c.resolveImageConfig.ResolveImageConfig(ctx, ref, platform)
+	if err != nil {
+		return "", nil, err
+	}
+	c.mu.Lock()
+	c.cache[key] = imageConfigResult{dgst: dgst, config: config}
+	c.mu.Unlock()
+	return dgst, config, nil
+}
@@ -0,0 +1,49 @@
+package solver
+
+import (
+	"testing"
+
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestImageConfigCache(t *testing.T) {
+	r := &testImageConfigResolver{}
+	c := newImageConfigCache(r)
+
+	for i := 0; i < 2; i++ {
+		dgst, dt, err := c.ResolveImageConfig(context.TODO(), "docker.io/library/busybox:latest", nil)
+		require.NoError(t, err)
+		require.Equal(t, digest.FromBytes([]byte("docker.io/library/busybox:latest")), dgst)
+		require.Equal(t, "docker.io/library/busybox:latest", string(dt))
+	}
+	require.Equal(t, 1, r.calls)
+
+	_, _, err := c.ResolveImageConfig(context.TODO(), "docker.io/library/busybox:latest", &ocispec.Platform{OS: "linux", Architecture: "arm64"})
+	require.NoError(t, err)
+	_, _, err = c.ResolveImageConfig(context.TODO(), "docker.io/library/busybox:latest", &ocispec.Platform{OS: "linux", Architecture: "aarch64"})
+	require.NoError(t, err)
+	require.Equal(t, 2, r.calls)
+
+	// errors are not cached
+	for i := 0; i < 2; i++ {
+		_, _, err = c.ResolveImageConfig(context.TODO(), "invalid", nil)
+		require.Error(t, err)
+	}
+	require.Equal(t, 4, r.calls)
+}
+
+type testImageConfigResolver struct {
+	calls int
+}
+
+func (r *testImageConfigResolver) ResolveImageConfig(ctx context.Context, ref string, platform *ocispec.Platform) (digest.Digest, []byte, error) {
+	r.calls++
+	if ref == "invalid" {
+		return "", nil, errors.Errorf("invalid ref %s", ref)
+	}
+	return digest.FromBytes([]byte(ref)), []byte(ref), nil
+}
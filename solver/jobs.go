@@ -5,9 +5,12 @@ import (
 	"sync"
 	"time"
 
+	"github.com/moby/buildkit/cache"
 	"github.com/moby/buildkit/client"
 	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/util/entitlements"
 	"github.com/moby/buildkit/util/progress"
+	"github.com/moby/buildkit/util/tracing"
 	digest "github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -23,24 +26,87 @@ type jobList struct {
 	refs       map[string]*job
 	updateCond *sync.Cond
 	actives    map[digest.Digest]*state
+	sched      *scheduler
+	migrator   *cacheKeyMigrator
+	// keepCompleted retains the results of the vertexes once they are run
+	keepCompleted bool
 }
 
 type state struct {
 	jobs   map[*job]struct{}
 	solver VertexSolver
+	vertex *vertex
 	mpw    *progress.MultiWriter
+	l      *jobList
+	// transferred counts the bytes of the progress of the vertex
+	transferred *byteCounter
 }
 
-func newJobList() *jobList {
+// sessionID returns a session of any job that is still attached to the
+// vertex. This keeps shared vertexes working when the job that first loaded
+// them goes away.
+func (st *state) sessionID() string {
+	st.l.mu.RLock()
+	defer st.l.mu.RUnlock()
+	for j := range st.jobs {
+		if j.session != "" {
+			return j.session
+		}
+	}
+	return ""
+}
+
+// lease returns the lease of a job that is attached to the vertex, like
+// sessionID
+func (st *state) lease() string {
+	st.l.mu.RLock()
+	defer st.l.mu.RUnlock()
+	for j := range st.jobs {
+		if j.lease != "" {
+			return j.lease
+		}
+	}
+	return ""
+}
+
+// span returns the span of the solve of a job that is attached to the vertex,
+// like sessionID
+func (st *state) span() *tracing.Span {
+	st.l.mu.RLock()
+	defer st.l.mu.RUnlock()
+	for j := range st.jobs {
+		if j.span != nil {
+			return j.span
+		}
+	}
+	return nil
+}
+
+// cgroupParent returns the cgroup of a build of a job that is attached to the
+// vertex, like sessionID
+func (st *state) cgroupParent() string {
+	st.l.mu.RLock()
+	defer st.l.mu.RUnlock()
+	for j := range st.jobs {
+		if j.cgroupParent != "" {
+			return j.cgroupParent
+		}
+	}
+	return ""
+}
+
+func newJobList(sched *scheduler) *jobList {
 	jl := &jobList{
-		refs:    make(map[string]*job),
-		actives: make(map[digest.Digest]*state),
+		refs:     make(map[string]*job),
+		actives:  make(map[digest.Digest]*state),
+		sched:    sched,
+		migrator: newCacheKeyMigrator(),
 	}
 	jl.updateCond = sync.NewCond(jl.mu.RLocker())
 	return jl
 }
 
-func (jl *jobList) new(ctx context.Context, id string, pr progress.Reader, cache InstructionCache) (context.Context, *job, error) {
+func (jl *jobList) new(ctx context.Context, id string, pr progress.Reader, ic InstructionCache, ents entitlements.Set, cgroupParent string) (context.Context, *job, error) {
 	jl.mu.Lock()
 	defer jl.mu.Unlock()
 
@@ -50,8 +116,9 @@ func (jl *jobList) new(ctx context.Context, id string, pr progress.Reader, cache
 
 	pw, _, _ := progress.FromContext(ctx) // TODO: remove this
 	sid := session.FromContext(ctx)
+	lease, _ := cache.LeaseID(ctx)
 
-	j := &job{l: jl, pr: progress.NewMultiReader(pr), pw: pw, session: sid, cache: cache}
+	j := &job{id: id, l: jl, pr: progress.NewMultiReader(pr), pw: pw, session: sid, cache: newPruneTracker(ic), entitlements: ents, cgroupParent: cgroupParent, lease: lease, span: tracing.SpanFromContext(ctx)}
 	jl.refs[id] = j
 	jl.updateCond.Broadcast()
 	go func() {
@@ -114,11 +181,23 @@ func (jl *jobList) loadAndSolveChildVertex(ctx context.Context, dgst digest.Dige
 }
 
 type job struct {
+	id      string
 	l       *jobList
 	pr      *progress.MultiReader
 	pw      progress.Writer
 	session string
 	cache   InstructionCache
+	roots   []*input // outputs requested from the job
+	// entitlements are granted to the execs of the job. They are checked for
+	// every job that loads a vertex, also when the vertex is shared.
+	entitlements entitlements.Set
+	// cgroupParent is the cgroup of the build the execs are created under,
+	// relative to the cgroup of the daemon
+	cgroupParent string
+	// lease covers the snapshots created by the vertexes of the job
+	lease string
+	// span is the span of the solve of the job
+	span *tracing.Span
 }
 
 func (j *job) load(v *vertex, f ResolveOpFunc) error {
@@ -136,21 +215,32 @@ func (j *job) loadInternal(v *vertex, f ResolveOpFunc) (*state, error) {
 		}
 	}
 
+	if err := checkEntitlements(v, j.entitlements); err != nil {
+		return nil, err
+	}
+
 	dgst := v.Digest()
 	st, ok := j.l.actives[dgst]
 	if !ok {
 		st = &state{
-			jobs: map[*job]struct{}{},
-			mpw:  progress.NewMultiWriter(progress.WithMetadata("vertex", dgst)),
+			jobs:        map[*job]struct{}{},
+			vertex:      v,
+			mpw:         progress.NewMultiWriter(progress.WithMetadata("vertex", dgst)),
+			l:           j.l,
+			transferred: newByteCounter(),
 		}
+		st.mpw.Add(st.transferred)
 		op, err := f(v)
 		if err != nil {
 			return nil, err
 		}
 		ctx := progress.WithProgress(context.Background(), st.mpw)
-		ctx = session.NewContext(ctx, j.session) // TODO: support multiple
+		ctx = session.NewContextFunc(ctx, st.sessionID)
+		ctx = withCgroupParentFunc(ctx, st.cgroupParent)
+		ctx = cache.WithLeaseFunc(ctx, st.lease)
+		ctx = tracing.ContextWithSpanFunc(ctx, st.span)
 
-		s, err := newVertexSolver(ctx, v, op, j.cache, j.getSolver)
+		s, err := newVertexSolver(ctx, v, op, j.cache, j.getSolver, j.l.sched, j.id, j.l.migrator, j.l.keepCompleted)
 		if err != nil {
 			return nil, err
 		}
@@ -159,7 +249,10 @@ func (j *job) loadInternal(v *vertex, f ResolveOpFunc) (*state, error) {
 		j.l.actives[dgst] = st
 	}
 	if _, ok := st.jobs[j]; !ok {
-		j.pw.Write(v.Digest().String(), v.clientVertex)
+		v.notifyMu.Lock()
+		cv := v.clientVertex
+		v.notifyMu.Unlock()
+		j.pw.Write(v.Digest().String(), cv)
 		st.mpw.Add(j.pw)
 		st.jobs[j] = struct{}{}
 	}
@@ -175,6 +268,7 @@ func (j *job) discard() {
 	for k, st := range j.l.actives {
 		if _, ok := st.jobs[j]; ok {
 			delete(st.jobs, j)
+			st.mpw.Delete(j.pw)
 		}
 		if len(st.jobs) == 0 {
 			go st.solver.Release()
@@ -192,24 +286,44 @@ func (j *job) getSolver(dgst digest.Digest) (VertexSolver, error) {
 }
 
 func (j *job) getRef(ctx context.Context, v *vertex, index Index) (Reference, error) {
+	j.l.mu.Lock()
 	s, err := j.getSolver(v.Digest())
+	if err == nil {
+		j.roots = append(j.roots, &input{index: index, vertex: v})
+	}
+	j.l.mu.Unlock()
 	if err != nil {
 		return nil, err
 	}
 	return getRef(s, ctx, v, index, j.cache)
 }
 
+// lookupCache loads the result of the cache key of v and counts the cache hits
+func lookupCache(ctx context.Context, cache InstructionCache, v *vertex, k digest.Digest) (interface{}, error) {
+	defer v.timings.record(phaseCacheLookup, time.Now())
+	ref, err := cache.Lookup(ctx, k)
+	if err != nil {
+		return nil, err
+	}
+	if ref != nil {
+		metricCacheLookups.Inc("hit")
+	} else {
+		metricCacheLookups.Inc("miss")
+	}
+	return ref, nil
+}
+
 func getRef(s VertexSolver, ctx context.Context, v *vertex, index Index, cache InstructionCache) (Reference, error) {
 	k, err := s.CacheKey(ctx, index)
 	if err != nil {
 		return nil, err
 	}
-	ref, err := cache.Lookup(ctx, k)
+	ref, err := lookupCache(ctx, cache, v, k)
 	if err != nil {
 		return nil, err
 	}
 	if ref != nil {
-		v.notifyCompleted(ctx, true, nil)
+		v.notifyCached(ctx, k)
 		return ref.(Reference), nil
 	}
 
@@ -225,12 +339,12 @@ func getRef(s VertexSolver, ctx context.Context, v *vertex, index Index, cache I
 			return nil, err
 		}
 		if r.CacheKey != "" {
-			ref, err := cache.Lookup(ctx, r.CacheKey)
+			ref, err := lookupCache(ctx, cache, v, r.CacheKey)
 			if err != nil {
 				return nil, err
 			}
 			if ref != nil {
-				v.notifyCompleted(ctx, true, nil)
+				v.notifyCached(ctx, r.CacheKey)
 				return ref.(Reference), nil
 			}
 			continue
@@ -255,6 +369,9 @@ func (j *job) pipe(ctx context.Context, ch chan *client.SolveStatus) error {
 			case client.Vertex:
 				ss.Vertexes = append(ss.Vertexes, &v)
 
+			case client.BuildSummary:
+				ss.Summary = &v
+
 			case progress.Status:
 				vtx, ok := p.Meta("vertex")
 				if !ok {
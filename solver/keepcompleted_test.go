@@ -0,0 +1,176 @@
+package solver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/containerd/containerd/namespaces"
+	"github.com/containerd/containerd/snapshot/naive"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/cache/instructioncache"
+	"github.com/moby/buildkit/cache/metadata"
+	"github.com/moby/buildkit/util/progress"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+// TestKeepCompleted checks that the result of a vertex that completes while
+// its sibling fails is still in the cache after the solve is cleaned up
+func TestKeepCompleted(t *testing.T) {
+	for _, keep := range []bool{false, true} {
+		ctx := namespaces.WithNamespace(context.Background(), "buildkit-test")
+		cm, ic, cleanup := newTestInstructionCache(t)
+		defer cleanup()
+
+		completed := &vertex{digest: "completed"}
+		failing := &vertex{digest: "failing"}
+		root := &vertex{digest: "root", inputs: []*input{{vertex: completed}, {vertex: failing}}}
+
+		started := make(chan struct{})
+		completedOp := &commitOp{cm: cm, started: started}
+		rootOp := &commitOp{cm: cm}
+		ops := map[digest.Digest]Op{
+			completed.digest: completedOp,
+			failing.digest:   &failOp{wait: started},
+			root.digest:      rootOp,
+		}
+		jl := newJobList(newScheduler(0))
+		jl.keepCompleted = keep
+		j, ctx := newTestJob(t, ctx, jl, ic)
+		require.NoError(t, j.load(root, func(v Vertex) (Op, error) { return ops[v.Digest()], nil }))
+
+		_, err := j.getRef(ctx, root, 0)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failing op")
+		releaseTestJob(j)
+		require.Equal(t, "", rootOp.id())
+		require.NotEqual(t, "", completedOp.id())
+
+		_, err = cm.Get(ctx, completedOp.id())
+		if !keep {
+			require.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+
+		// the next solve continues from the cached result without running the op
+		j, ctx = newTestJob(t, ctx, newJobList(newScheduler(0)), ic)
+		require.NoError(t, j.load(completed, func(Vertex) (Op, error) { return &failOp{commit: true}, nil }))
+		ref, err := j.getRef(ctx, completed, 0)
+		require.NoError(t, err)
+		require.NoError(t, ref.Release(ctx))
+	}
+}
+
+func newTestInstructionCache(t *testing.T) (cache.Manager, InstructionCache, func()) {
+	tmpdir, err := ioutil.TempDir("", "keepcompleted")
+	require.NoError(t, err)
+
+	snapshotter, err := naive.NewSnapshotter(filepath.Join(tmpdir, "snapshots"))
+	require.NoError(t, err)
+	md, err := metadata.NewStore(filepath.Join(tmpdir, "metadata.db"))
+	require.NoError(t, err)
+	cm, err := cache.NewManager(cache.ManagerOpt{
+		Snapshotter:   snapshotter,
+		MetadataStore: md,
+	})
+	require.NoError(t, err)
+
+	ic := &instructioncache.LocalStore{MetadataStore: md, Cache: cm}
+	return cm, ic, func() {
+		cm.Close()
+		os.RemoveAll(tmpdir)
+	}
+}
+
+func newTestJob(t *testing.T, ctx context.Context, jl *jobList, ic InstructionCache) (*job, context.Context) {
+	pr, ctx, _ := progress.NewContext(ctx)
+	ctx, j, err := jl.new(ctx, t.Name(), pr, ic, nil, "")
+	require.NoError(t, err)
+	return j, ctx
+}
+
+// releaseTestJob releases the vertexes of the job like job.discard without
+// waiting for the asynchronous release
+func releaseTestJob(j *job) {
+	j.l.mu.Lock()
+	defer j.l.mu.Unlock()
+	for k, st := range j.l.actives {
+		st.solver.Release()
+		delete(j.l.actives, k)
+	}
+}
+
+// commitOp creates an empty result. If started is set it is closed when the
+// op is run and the result is created after the op has been cancelled, like
+// an op that is too far along to stop.
+type commitOp struct {
+	cm      cache.Manager
+	started chan struct{}
+	mu      sync.Mutex
+	ref     string
+}
+
+func (o *commitOp) CacheKey(ctx context.Context) (digest.Digest, error) {
+	return digest.FromBytes([]byte("commit")), nil
+}
+
+func (o *commitOp) ContentKeys(context.Context, [][]digest.Digest, []Reference) ([]digest.Digest, error) {
+	return nil, nil
+}
+
+func (o *commitOp) Run(ctx context.Context, inputs []Reference) ([]Reference, error) {
+	if o.started != nil {
+		close(o.started)
+		<-ctx.Done()
+		ctx = namespaces.WithNamespace(context.Background(), "buildkit-test")
+	}
+	active, err := o.cm.New(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	ref, err := active.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	o.mu.Lock()
+	o.ref = ref.ID()
+	o.mu.Unlock()
+	return []Reference{ref}, nil
+}
+
+func (o *commitOp) id() string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.ref
+}
+
+// failOp fails after wait is closed. The cache key is the same as the key of
+// commitOp if commit is set.
+type failOp struct {
+	wait   chan struct{}
+	commit bool
+}
+
+func (o *failOp) CacheKey(ctx context.Context) (digest.Digest, error) {
+	if o.commit {
+		return (&commitOp{}).CacheKey(ctx)
+	}
+	return digest.FromBytes([]byte("fail")), nil
+}
+
+func (o *failOp) ContentKeys(context.Context, [][]digest.Digest, []Reference) ([]digest.Digest, error) {
+	return nil, nil
+}
+
+func (o *failOp) Run(ctx context.Context, inputs []Reference) ([]Reference, error) {
+	if o.wait != nil {
+		<-o.wait
+	}
+	return nil, errors.Errorf("failing op")
+}
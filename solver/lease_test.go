@@ -0,0 +1,102 @@
+package solver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containerd/containerd/namespaces"
+	"github.com/containerd/containerd/snapshot/naive"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/cache/instructioncache"
+	"github.com/moby/buildkit/cache/metadata"
+	"github.com/moby/buildkit/client"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+// TestSolveLease checks that the snapshot of a running op is covered by the
+// lease of the solve: the garbage collection doesn't remove it while the op
+// runs, and it is collected when the daemon stops before the op completes
+func TestSolveLease(t *testing.T) {
+	ctx := namespaces.WithNamespace(context.Background(), "buildkit-test")
+	tmpdir, err := ioutil.TempDir("", "solvelease")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	cm, ic := openTestCache(t, tmpdir)
+	lctx, _, err := cm.WithLease(ctx, "solve1")
+	require.NoError(t, err)
+	j, jctx := newTestJob(t, lctx, newJobList(newScheduler(0)), ic)
+
+	v := &vertex{digest: "running"}
+	op := &runningOp{cm: cm, started: make(chan string), release: make(chan struct{})}
+	require.NoError(t, j.load(v, func(Vertex) (Op, error) { return op, nil }))
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := j.getRef(jctx, v, 0)
+		errCh <- err
+	}()
+	id := <-op.started
+
+	require.NoError(t, cm.Prune(ctx, nil, client.PruneInfo{}))
+	du, err := cm.DiskUsage(ctx, client.DiskUsageInfo{})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(du))
+	require.Equal(t, id, du[0].ID)
+
+	// the daemon stops without releasing the lease
+	require.NoError(t, cm.Close())
+	cm, _ = openTestCache(t, tmpdir)
+	defer cm.Close()
+	_, err = cm.GetMutable(ctx, id)
+	require.Error(t, err)
+	du, err = cm.DiskUsage(ctx, client.DiskUsageInfo{})
+	require.NoError(t, err)
+	require.Equal(t, 0, len(du))
+
+	close(op.release)
+	require.Error(t, <-errCh)
+}
+
+func openTestCache(t *testing.T, dir string) (cache.Manager, InstructionCache) {
+	snapshotter, err := naive.NewSnapshotter(filepath.Join(dir, "snapshots"))
+	require.NoError(t, err)
+	md, err := metadata.NewStore(filepath.Join(dir, "metadata.db"))
+	require.NoError(t, err)
+	cm, err := cache.NewManager(cache.ManagerOpt{
+		Snapshotter:   snapshotter,
+		MetadataStore: md,
+	})
+	require.NoError(t, err)
+	return cm, &instructioncache.LocalStore{MetadataStore: md, Cache: cm}
+}
+
+// runningOp creates a snapshot like an exec, sends its id to started and
+// fails when release is closed
+type runningOp struct {
+	cm      cache.Manager
+	started chan string
+	release chan struct{}
+}
+
+func (o *runningOp) CacheKey(ctx context.Context) (digest.Digest, error) {
+	return digest.FromBytes([]byte("running")), nil
+}
+
+func (o *runningOp) ContentKeys(context.Context, [][]digest.Digest, []Reference) ([]digest.Digest, error) {
+	return nil, nil
+}
+
+func (o *runningOp) Run(ctx context.Context, inputs []Reference) ([]Reference, error) {
+	active, err := o.cm.New(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	o.started <- active.ID()
+	<-o.release
+	return nil, errors.Errorf("op stopped")
+}
@@ -28,12 +28,20 @@ func LoadLLB(ops [][]byte) (Vertex, error) {
 		allOps[lastDigest] = &op
 	}
 
-	delete(allOps, lastDigest) // avoid loops
+	if err := validateDefinition(lastDigest, allOps); err != nil {
+		return nil, err
+	}
 
 	cache := make(map[digest.Digest]*vertex)
 
-	// TODO: validate the connections
-	return loadLLBVertexRecursive(lastDigest, lastOp, allOps, cache)
+	v, err := loadLLBVertexRecursive(lastDigest, lastOp, allOps, cache)
+	if err != nil {
+		return nil, err
+	}
+	if err := optimize(v); err != nil {
+		return nil, err
+	}
+	return v, nil
 }
 
 func toInternalVertex(v Vertex) *vertex {
@@ -59,7 +67,7 @@ func loadLLBVertexRecursive(dgst digest.Digest, op *pb.Op, all map[digest.Digest
 	if v, ok := cache[dgst]; ok {
 		return v, nil
 	}
-	vtx := &vertex{sys: op.Op, digest: dgst, name: llbOpName(op)}
+	vtx := &vertex{sys: op.Op, digest: dgst, name: llbOpName(op), platform: op.Platform, constraints: op.Constraints}
 	for _, in := range op.Inputs {
 		dgst := digest.Digest(in.Digest)
 		op, ok := all[dgst]
@@ -85,6 +93,12 @@ func llbOpName(op *pb.Op) string {
 		return strings.Join(op.Exec.Meta.Args, " ")
 	case *pb.Op_Build:
 		return "build"
+	case *pb.Op_File:
+		return fileOpName(op.File)
+	case *pb.Op_Merge:
+		return "merge"
+	case *pb.Op_Diff:
+		return "diff"
 	default:
 		return "unknown"
 	}
@@ -0,0 +1,351 @@
+package solver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/containerd/containerd/fs"
+	"github.com/containerd/containerd/mount"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/cache/metadata"
+	"github.com/moby/buildkit/solver/pb"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+)
+
+const mergeCacheType = "buildkit.merge.v0"
+
+// mergeOp layers the inputs of a MergeOp on top of each other. The snapshotters
+// can't create a snapshot with several parents so the first input is used as
+// the parent of the result and the files of the other inputs are copied on
+// top of it. With overlay the inputs are stacked with overlayfs instead, and
+// the files are only copied when the result is used as a parent or exported.
+type mergeOp struct {
+	v       Vertex
+	op      *pb.MergeOp
+	cm      cache.Manager
+	md      *metadata.Store
+	overlay bool
+}
+
+func newMergeOp(v Vertex, op *pb.Op_Merge, cm cache.Manager, md *metadata.Store, overlay bool) (Op, error) {
+	if len(op.Merge.Inputs) == 0 {
+		return nil, errors.Errorf("merge op without inputs")
+	}
+	for i, inp := range op.Merge.Inputs {
+		if err := checkInputIndex(vertexDigest(v), fmt.Sprintf("merge input %d", i), inp.Input, len(v.Inputs())); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	return &mergeOp{
+		v:       v,
+		op:      op.Merge,
+		cm:      cm,
+		md:      md,
+		overlay: overlay,
+	}, nil
+}
+
+func (m *mergeOp) CacheKey(ctx context.Context) (digest.Digest, error) {
+	dt, err := json.Marshal(struct {
+		Type  string
+		Merge *pb.MergeOp
+	}{
+		Type:  mergeCacheType,
+		Merge: m.op,
+	})
+	if err != nil {
+		return "", err
+	}
+	return digest.FromBytes(dt), nil
+}
+
+func (m *mergeOp) ContentKeys(context.Context, [][]digest.Digest, []Reference) ([]digest.Digest, error) {
+	return nil, nil
+}
+
+func (m *mergeOp) Run(ctx context.Context, inputs []Reference) ([]Reference, error) {
+	refs := make([]cache.ImmutableRef, 0, len(m.op.Inputs))
+	for _, inp := range m.op.Inputs {
+		ref, ok := toImmutableRef(inputs[inp.Input])
+		if !ok {
+			return nil, errors.Errorf("invalid reference for merge op %T", inputs[inp.Input])
+		}
+		refs = append(refs, ref)
+	}
+
+	layers, err := mergeLayers(ctx, refs)
+	if err != nil {
+		return nil, err
+	}
+
+	// a single layer already contains all the other inputs
+	if len(layers) == 1 {
+		return []Reference{newSharedRef(layers[0]).Clone()}, nil
+	}
+
+	if m.overlay {
+		ref, err := newOverlayMerge(ctx, m, layers)
+		if err == nil {
+			return []Reference{ref}, nil
+		}
+		logrus.Debugf("copying the files of the merge inputs: %v", err)
+	}
+
+	ref, err := m.copyLayers(ctx, layers)
+	if err != nil {
+		return nil, err
+	}
+	return []Reference{ref}, nil
+}
+
+// copyLayers copies the files of the layers on top of the first one
+func (m *mergeOp) copyLayers(ctx context.Context, layers []cache.ImmutableRef) (cache.ImmutableRef, error) {
+	active, err := m.cm.New(ctx, layers[0], cache.WithDescription("merge"))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if active != nil {
+			active.Release(context.TODO())
+		}
+	}()
+
+	if err := withMountedRef(ctx, active, false, func(root string) error {
+		for _, l := range layers[1:] {
+			if err := withMountedRef(ctx, l, true, func(src string) error {
+				return mergeDir(root, src)
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, errors.Wrap(err, "failed to merge inputs")
+	}
+
+	ref, err := active.Commit(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error committing %s", active.ID())
+	}
+	active = nil
+	return ref, nil
+}
+
+// overlayMerge is the result of a merge that stacks the layers with
+// overlayfs. The read-only mounts are the stack of the layers. The files of
+// the layers are copied when the result is resolved, e.g. when it is used as
+// the parent of a new ref, mounted writable or exported. The parent is nil
+// until the result is resolved.
+type overlayMerge struct {
+	m      *mergeOp
+	id     string
+	md     *metadata.StorageItem
+	layers []Reference
+	mounts []mount.Mount
+
+	mu  sync.Mutex
+	ref cache.ImmutableRef
+}
+
+func newOverlayMerge(ctx context.Context, m *mergeOp, layers []cache.ImmutableRef) (ref *overlayMerge, err error) {
+	r := &overlayMerge{m: m}
+	defer func() {
+		if err != nil {
+			r.Release(context.TODO())
+		}
+	}()
+	ids := make([]string, 0, len(layers))
+	mounts := make([][]mount.Mount, 0, len(layers))
+	for _, l := range layers {
+		r.layers = append(r.layers, newSharedRef(l).Clone())
+		ids = append(ids, l.ID())
+		mnt, err := l.Mount(ctx, true)
+		if err != nil {
+			return nil, err
+		}
+		mounts = append(mounts, mnt)
+	}
+	if r.mounts, err = overlayStack(mounts); err != nil {
+		return nil, err
+	}
+	r.id = "merge-" + digest.FromString(strings.Join(ids, ",")).Hex()
+	if m.md != nil {
+		r.md, _ = m.md.Get(r.id)
+	}
+	return r, nil
+}
+
+// Resolve copies the files of the layers to a new ref if it hasn't been done
+// yet
+func (r *overlayMerge) Resolve(ctx context.Context) (cache.ImmutableRef, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.ref == nil {
+		layers := make([]cache.ImmutableRef, 0, len(r.layers))
+		for _, l := range r.layers {
+			ref, _ := toImmutableRef(l)
+			layers = append(layers, ref)
+		}
+		ref, err := r.m.copyLayers(ctx, layers)
+		if err != nil {
+			return nil, err
+		}
+		r.ref = ref
+	}
+	return r.ref, nil
+}
+
+func (r *overlayMerge) resolved() cache.ImmutableRef {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.ref
+}
+
+func (r *overlayMerge) ID() string {
+	return r.id
+}
+
+func (r *overlayMerge) Mount(ctx context.Context, readonly bool) ([]mount.Mount, error) {
+	if ref := r.resolved(); ref != nil || !readonly {
+		ref, err := r.Resolve(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return ref.Mount(ctx, readonly)
+	}
+	return r.mounts, nil
+}
+
+func (r *overlayMerge) Release(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var rerr error
+	for _, l := range r.layers {
+		if err := l.Release(ctx); err != nil && rerr == nil {
+			rerr = err
+		}
+	}
+	r.layers = nil
+	if r.ref != nil {
+		if err := r.ref.Release(ctx); err != nil && rerr == nil {
+			rerr = err
+		}
+		r.ref = nil
+	}
+	return rerr
+}
+
+func (r *overlayMerge) Size(ctx context.Context) (int64, error) {
+	ref, err := r.Resolve(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return ref.Size(ctx)
+}
+
+func (r *overlayMerge) Parent() cache.ImmutableRef {
+	if ref := r.resolved(); ref != nil {
+		return ref.Parent()
+	}
+	return nil
+}
+
+func (r *overlayMerge) Finalize(ctx context.Context) error {
+	ref, err := r.Resolve(ctx)
+	if err != nil {
+		return err
+	}
+	return ref.Finalize(ctx)
+}
+
+func (r *overlayMerge) Metadata() *metadata.StorageItem {
+	if ref := r.resolved(); ref != nil {
+		return ref.Metadata()
+	}
+	return r.md
+}
+
+// mergeLayers returns the refs that need to be layered for the merge. A ref
+// that is the same as or a parent of a later ref is skipped because the later
+// ref already contains its files.
+func mergeLayers(ctx context.Context, refs []cache.ImmutableRef) ([]cache.ImmutableRef, error) {
+	ancestors := make([]map[string]struct{}, len(refs))
+	for i, ref := range refs {
+		ancestors[i] = map[string]struct{}{}
+		// the parents of lazy refs are only known once they are resolved
+		ref, err := cache.Resolve(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		for p := ref; p != nil; {
+			ancestors[i][p.ID()] = struct{}{}
+			parent := p.Parent()
+			if p != ref {
+				if err := p.Release(context.TODO()); err != nil {
+					return nil, err
+				}
+			}
+			p = parent
+		}
+	}
+
+	var layers []cache.ImmutableRef
+loop:
+	for i, ref := range refs {
+		for _, a := range ancestors[i+1:] {
+			if _, ok := a[ref.ID()]; ok {
+				continue loop
+			}
+		}
+		layers = append(layers, ref)
+	}
+	return layers, nil
+}
+
+// mergeDir copies the files of src on top of dest. Files in dest that
+// conflict with the files of src are replaced, directories are merged.
+func mergeDir(dest, src string) error {
+	if err := filepath.Walk(src, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		target := filepath.Join(dest, rel)
+		tfi, err := os.Lstat(target)
+		if err != nil {
+			if os.IsNotExist(err) {
+				if fi.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			return err
+		}
+		if fi.IsDir() && tfi.IsDir() {
+			return nil
+		}
+		if err := os.RemoveAll(target); err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			return filepath.SkipDir
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	return fs.CopyDir(dest, src)
+}
@@ -0,0 +1,114 @@
+package solver
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/containerd/containerd/mount"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+// maxOverlayOptions is the length of the mount options that fits in the page
+// the kernel copies them to
+const maxOverlayOptions = 4000
+
+// overlayStack returns an overlay mount of the directories of the read-only
+// mounts of layers, the ones of the last layer on top. Only the directories
+// of the overlayfs snapshotter can be stacked. The layers above the first
+// one can't contain whiteouts, they would also remove the files of the
+// layers below them, which the copy of the files doesn't.
+func overlayStack(layers [][]mount.Mount) ([]mount.Mount, error) {
+	var dirs []string
+	seen := map[string]struct{}{}
+	for i := len(layers) - 1; i >= 0; i-- {
+		ld, err := lowerDirs(layers[i])
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range ld {
+			if _, ok := seen[d]; ok {
+				// the parents shared by the layers are only stacked once, at
+				// the top
+				continue
+			}
+			seen[d] = struct{}{}
+			if i > 0 {
+				if err := checkWhiteouts(d); err != nil {
+					return nil, err
+				}
+			}
+			dirs = append(dirs, d)
+		}
+	}
+	if len(dirs) < 2 {
+		return nil, errors.Errorf("overlay merge needs at least two directories")
+	}
+	opt := "lowerdir=" + strings.Join(dirs, ":")
+	if len(opt) > maxOverlayOptions {
+		return nil, errors.Errorf("too many directories for an overlay merge")
+	}
+	return []mount.Mount{{
+		Type:    "overlay",
+		Source:  "overlay",
+		Options: []string{opt},
+	}}, nil
+}
+
+// lowerDirs returns the directories of the read-only mounts of a snapshot,
+// the top one first. The committed refs can still be mounted with the upper
+// directory of the snapshot they were committed from.
+func lowerDirs(mounts []mount.Mount) ([]string, error) {
+	if len(mounts) != 1 {
+		return nil, errors.Errorf("can't stack %d mounts", len(mounts))
+	}
+	m := mounts[0]
+	switch m.Type {
+	case "bind":
+		return []string{m.Source}, nil
+	case "overlay":
+		var upper string
+		var dirs []string
+		for _, o := range m.Options {
+			if strings.HasPrefix(o, "upperdir=") {
+				upper = strings.TrimPrefix(o, "upperdir=")
+			}
+			if strings.HasPrefix(o, "lowerdir=") {
+				dirs = strings.Split(strings.TrimPrefix(o, "lowerdir="), ":")
+			}
+		}
+		if len(dirs) == 0 {
+			return nil, errors.Errorf("overlay mount without lower directories")
+		}
+		if upper != "" {
+			dirs = append([]string{upper}, dirs...)
+		}
+		return dirs, nil
+	default:
+		return nil, errors.Errorf("can't stack %s mounts", m.Type)
+	}
+}
+
+// checkWhiteouts returns an error if dir contains the whiteouts or the opaque
+// directories of overlayfs
+func checkWhiteouts(dir string) error {
+	return filepath.Walk(dir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fi.Mode()&os.ModeCharDevice != 0 {
+			if st, ok := fi.Sys().(*syscall.Stat_t); ok && st.Rdev == 0 {
+				return errors.Errorf("whiteout %s", p)
+			}
+		}
+		if fi.IsDir() {
+			buf := make([]byte, 1)
+			if n, err := unix.Lgetxattr(p, "trusted.overlay.opaque", buf); err == nil && n == 1 && buf[0] == 'y' {
+				return errors.Errorf("opaque directory %s", p)
+			}
+		}
+		return nil
+	})
+}
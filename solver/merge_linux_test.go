@@ -0,0 +1,85 @@
+package solver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containerd/containerd/namespaces"
+	"github.com/containerd/containerd/snapshot/overlay"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestMergeOpOverlay(t *testing.T) {
+	ctx := namespaces.WithNamespace(context.Background(), "buildkit-test")
+	tmpdir, err := ioutil.TempDir("", "mergeoverlay")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	snapshotter, err := overlay.NewSnapshotter(filepath.Join(tmpdir, "snapshots"))
+	require.NoError(t, err)
+	m := newTestMergeOp(t, snapshotter, tmpdir, true)
+	base := writeTestRef(ctx, t, m.cm, nil, map[string]string{"base": "base"})
+	a := writeTestRef(ctx, t, m.cm, base, map[string]string{"a": "a", "c": "a"})
+	b := writeTestRef(ctx, t, m.cm, base, map[string]string{"b": "b", "c": "b"})
+
+	refs, err := m.Run(ctx, []Reference{a, b})
+	require.NoError(t, err)
+	require.Len(t, refs, 1)
+	om, ok := refs[0].(*overlayMerge)
+	require.True(t, ok)
+
+	// the inputs are stacked without copying their files
+	mounts, err := om.Mount(ctx, true)
+	require.NoError(t, err)
+	require.Equal(t, "overlay", mounts[0].Type)
+	require.Nil(t, om.Parent())
+	require.NoError(t, withMountedRef(ctx, om, true, func(dir string) error {
+		require.Equal(t, []string{"a", "b", "base", "c"}, readDirNames(t, dir))
+		dt, err := ioutil.ReadFile(filepath.Join(dir, "c"))
+		require.NoError(t, err)
+		require.Equal(t, "b", string(dt))
+		return nil
+	}))
+
+	// the files are copied when the result is used as a parent
+	child := writeTestRef(ctx, t, m.cm, om, map[string]string{"d": "d"})
+	require.NotNil(t, om.resolved())
+	require.NoError(t, withMountedRef(ctx, child, true, func(dir string) error {
+		require.Equal(t, []string{"a", "b", "base", "c", "d"}, readDirNames(t, dir))
+		return nil
+	}))
+	require.NoError(t, child.Release(ctx))
+	require.NoError(t, om.Release(ctx))
+}
+
+func TestMergeOpOverlayWhiteout(t *testing.T) {
+	ctx := namespaces.WithNamespace(context.Background(), "buildkit-test")
+	tmpdir, err := ioutil.TempDir("", "mergeoverlay")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	snapshotter, err := overlay.NewSnapshotter(filepath.Join(tmpdir, "snapshots"))
+	require.NoError(t, err)
+	m := newTestMergeOp(t, snapshotter, tmpdir, true)
+	a := writeTestRef(ctx, t, m.cm, nil, map[string]string{"a": "a", "c": "a"})
+	b0 := writeTestRef(ctx, t, m.cm, nil, map[string]string{"a": "b", "b": "b", "c": "b"})
+	b := writeTestRef(ctx, t, m.cm, b0, map[string]string{"a": ""})
+
+	// the whiteout of a in b would also remove the a of the first input, so
+	// the files are copied
+	refs, err := m.Run(ctx, []Reference{a, b})
+	require.NoError(t, err)
+	require.Len(t, refs, 1)
+	_, ok := refs[0].(*overlayMerge)
+	require.False(t, ok)
+	ref, ok := toImmutableRef(refs[0])
+	require.True(t, ok)
+	require.NoError(t, withMountedRef(ctx, ref, true, func(dir string) error {
+		require.Equal(t, []string{"a", "b", "c"}, readDirNames(t, dir))
+		return nil
+	}))
+	require.NoError(t, refs[0].Release(ctx))
+}
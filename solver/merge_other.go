@@ -0,0 +1,13 @@
+// +build !linux
+
+package solver
+
+import (
+	"github.com/containerd/containerd/mount"
+	"github.com/pkg/errors"
+)
+
+// overlayStack returns an error as overlayfs only exists on linux
+func overlayStack(layers [][]mount.Mount) ([]mount.Mount, error) {
+	return nil, errors.Errorf("overlay merges are not supported")
+}
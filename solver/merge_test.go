@@ -0,0 +1,182 @@
+package solver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containerd/containerd/namespaces"
+	ctdsnapshot "github.com/containerd/containerd/snapshot"
+	"github.com/containerd/containerd/snapshot/naive"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/cache/metadata"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestMergeDir(t *testing.T) {
+	dest, err := ioutil.TempDir("", "buildkit-merge-dest")
+	require.NoError(t, err)
+	defer os.RemoveAll(dest)
+	src, err := ioutil.TempDir("", "buildkit-merge-src")
+	require.NoError(t, err)
+	defer os.RemoveAll(src)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dest, "dir/sub"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dest, "dir/keep"), []byte("keep"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dest, "dir/foo"), []byte("old"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dest, "todir"), []byte("file"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dest, "tofile/sub"), 0755))
+	require.NoError(t, os.Symlink("dir", filepath.Join(dest, "link")))
+
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "dir"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(src, "dir/foo"), []byte("new"), 0600))
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "todir"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(src, "todir/bar"), []byte("bar"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(src, "tofile"), []byte("file"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "link"), 0755))
+
+	require.NoError(t, mergeDir(dest, src))
+
+	require.Equal(t, []string{"foo", "keep", "sub"}, readDirNames(t, filepath.Join(dest, "dir")))
+	dt, err := ioutil.ReadFile(filepath.Join(dest, "dir/foo"))
+	require.NoError(t, err)
+	require.Equal(t, "new", string(dt))
+	fi, err := os.Stat(filepath.Join(dest, "dir/foo"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+
+	require.Equal(t, []string{"bar"}, readDirNames(t, filepath.Join(dest, "todir")))
+	fi, err = os.Lstat(filepath.Join(dest, "tofile"))
+	require.NoError(t, err)
+	require.True(t, fi.Mode().IsRegular())
+
+	// the symlink is replaced, not followed
+	fi, err = os.Lstat(filepath.Join(dest, "link"))
+	require.NoError(t, err)
+	require.True(t, fi.IsDir())
+	require.Equal(t, []string{"foo", "keep", "sub"}, readDirNames(t, filepath.Join(dest, "dir")))
+}
+
+func TestMergeLayers(t *testing.T) {
+	a := &testParentRef{id: "a"}
+	b := &testParentRef{id: "b", parent: a}
+	c := &testParentRef{id: "c"}
+
+	layers, err := mergeLayers(context.TODO(), []cache.ImmutableRef{a, c, b})
+	require.NoError(t, err)
+	require.Equal(t, []cache.ImmutableRef{c, b}, layers)
+
+	// a parent after its child is still layered on top
+	layers, err = mergeLayers(context.TODO(), []cache.ImmutableRef{b, a})
+	require.NoError(t, err)
+	require.Equal(t, []cache.ImmutableRef{b, a}, layers)
+
+	layers, err = mergeLayers(context.TODO(), []cache.ImmutableRef{a, b, b})
+	require.NoError(t, err)
+	require.Equal(t, []cache.ImmutableRef{b}, layers)
+}
+
+type testParentRef struct {
+	cache.ImmutableRef
+	id     string
+	parent *testParentRef
+}
+
+func (r *testParentRef) ID() string {
+	return r.id
+}
+
+func (r *testParentRef) Parent() cache.ImmutableRef {
+	if r.parent == nil {
+		return nil
+	}
+	return r.parent
+}
+
+func (r *testParentRef) Release(context.Context) error {
+	return nil
+}
+
+func TestMergeOpCopy(t *testing.T) {
+	ctx := namespaces.WithNamespace(context.Background(), "buildkit-test")
+	tmpdir, err := ioutil.TempDir("", "mergecopy")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	snapshotter, err := naive.NewSnapshotter(filepath.Join(tmpdir, "snapshots"))
+	require.NoError(t, err)
+	m := newTestMergeOp(t, snapshotter, tmpdir, false)
+	a := writeTestRef(ctx, t, m.cm, nil, map[string]string{"a": "a", "c": "a"})
+	b := writeTestRef(ctx, t, m.cm, nil, map[string]string{"b": "b", "c": "b"})
+
+	refs, err := m.Run(ctx, []Reference{a, b})
+	require.NoError(t, err)
+	require.Len(t, refs, 1)
+	_, ok := refs[0].(*overlayMerge)
+	require.False(t, ok)
+	ref, ok := toImmutableRef(refs[0])
+	require.True(t, ok)
+	requireMergedFiles(ctx, t, ref)
+
+	// the result is a child of the first input
+	parent := ref.Parent()
+	require.NotNil(t, parent)
+	require.Equal(t, a.ID(), parent.ID())
+	require.NoError(t, parent.Release(ctx))
+	require.NoError(t, refs[0].Release(ctx))
+}
+
+func newTestMergeOp(t *testing.T, snapshotter ctdsnapshot.Snapshotter, tmpdir string, overlay bool) *mergeOp {
+	md, err := metadata.NewStore(filepath.Join(tmpdir, "metadata.db"))
+	require.NoError(t, err)
+	cm, err := cache.NewManager(cache.ManagerOpt{
+		Snapshotter:   snapshotter,
+		MetadataStore: md,
+	})
+	require.NoError(t, err)
+	return &mergeOp{
+		op:      &pb.MergeOp{Inputs: []*pb.MergeInput{{Input: 0}, {Input: 1}}},
+		cm:      cm,
+		md:      md,
+		overlay: overlay,
+	}
+}
+
+// writeTestRef commits a child of parent with files. The files with an empty
+// content are removed.
+func writeTestRef(ctx context.Context, t *testing.T, cm cache.Manager, parent cache.ImmutableRef, files map[string]string) cache.ImmutableRef {
+	active, err := cm.New(ctx, parent)
+	require.NoError(t, err)
+	require.NoError(t, withMountedRef(ctx, active, false, func(dir string) error {
+		for name, dt := range files {
+			if dt == "" {
+				if err := os.Remove(filepath.Join(dir, name)); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(dt), 0644); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+	ref, err := active.Commit(ctx)
+	require.NoError(t, err)
+	return ref
+}
+
+// requireMergedFiles checks that ref contains the files of the inputs of the
+// merge tests, where the second input replaces c
+func requireMergedFiles(ctx context.Context, t *testing.T, ref cache.Mountable) {
+	require.NoError(t, withMountedRef(ctx, ref, true, func(dir string) error {
+		require.Equal(t, []string{"a", "b", "c"}, readDirNames(t, dir))
+		dt, err := ioutil.ReadFile(filepath.Join(dir, "c"))
+		require.NoError(t, err)
+		require.Equal(t, "b", string(dt))
+		return nil
+	}))
+}
@@ -0,0 +1,46 @@
+package solver
+
+import (
+	"github.com/moby/buildkit/util/metrics"
+	"github.com/moby/buildkit/worker"
+)
+
+var (
+	metricSolves = metrics.NewHistogram("buildkit_solver_solve_duration_seconds",
+		"Duration of the solves of the daemon.", nil, "result")
+	metricVertexesExecuted = metrics.NewCounter("buildkit_solver_vertexes_executed_total",
+		"Number of vertexes that were run.")
+	metricVertexesCached = metrics.NewCounter("buildkit_solver_vertexes_cached_total",
+		"Number of vertexes that were loaded from the cache.")
+	metricVertexDuration = metrics.NewHistogram("buildkit_solver_vertex_duration_seconds",
+		"Duration of running the vertexes.", nil, "result")
+	metricQueueLength = metrics.NewGauge("buildkit_solver_queue_length",
+		"Number of vertexes waiting for a free slot of the parallelism limit.")
+	metricCacheLookups = metrics.NewCounter("buildkit_cache_lookups_total",
+		"Number of instruction cache lookups of the solver.", "result")
+	metricExecs = metrics.NewGauge("buildkit_worker_execs_running",
+		"Number of processes running on the worker.", "worker")
+	metricExecFailures = metrics.NewCounter("buildkit_worker_exec_failures_total",
+		"Number of processes that failed on the worker, by exit code or worker error.", "worker", "reason")
+)
+
+func init() {
+	metrics.MustRegister(metricSolves, metricVertexesExecuted, metricVertexesCached, metricVertexDuration,
+		metricQueueLength, metricCacheLookups, metricExecs, metricExecFailures)
+}
+
+// resultLabel is the value of the result label of an operation
+func resultLabel(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
+
+// workerLabel is the value of the worker label of a worker
+func workerLabel(w worker.Worker) string {
+	if id, ok := w.(worker.Identifier); ok {
+		return id.Identity()
+	}
+	return "unknown"
+}
@@ -0,0 +1,148 @@
+package solver
+
+import (
+	"encoding/json"
+
+	"github.com/moby/buildkit/solver/pb"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+// optimize simplifies the graph of the loaded vertex root before it is
+// solved. Vertexes with the same operation on the same inputs are solved
+// once, even if their digests are different, and the merges that only merge
+// the same input are replaced by the input, unless the merge is root. The
+// vertexes that aren't reachable from root are not loaded. Deduplicating
+// doesn't change the cache keys, they don't depend on the digests of the
+// inputs, but a vertex with a collapsed merge as an input gets its cache key
+// from the input of the merge, so it doesn't match the cache of a build
+// before the merge was collapsed.
+func optimize(root *vertex) error {
+	o := &optimizer{
+		visited:   map[*vertex]struct{}{},
+		byKey:     map[digest.Digest]*vertex{},
+		keys:      map[*vertex]digest.Digest{},
+		aliases:   map[*vertex]*vertex{},
+		collapsed: map[*vertex]*input{},
+	}
+	return o.visit(root)
+}
+
+type optimizer struct {
+	visited map[*vertex]struct{}
+	// byKey are the vertexes that are solved by their structural key
+	byKey map[digest.Digest]*vertex
+	keys  map[*vertex]digest.Digest
+	// aliases are the vertexes that are replaced by an identical vertex
+	aliases map[*vertex]*vertex
+	// collapsed are the vertexes that are replaced by one of their inputs
+	collapsed map[*vertex]*input
+}
+
+func (o *optimizer) visit(v *vertex) error {
+	if _, ok := o.visited[v]; ok {
+		return nil
+	}
+	o.visited[v] = struct{}{}
+
+	changed := false
+	for i, in := range v.inputs {
+		if err := o.visit(in.vertex); err != nil {
+			return err
+		}
+		if r := o.replace(in); r != in {
+			v.inputs[i] = r
+			changed = true
+		}
+	}
+	if changed {
+		v.initClientVertex()
+	}
+
+	if v.sys == nil {
+		return nil
+	}
+	key, err := o.structuralKey(v)
+	if err != nil {
+		return err
+	}
+	if a, ok := o.byKey[key]; ok {
+		o.aliases[v] = a
+		return nil
+	}
+	o.byKey[key] = v
+	o.keys[v] = key
+	if in := noopInput(v); in != nil {
+		o.collapsed[v] = in
+	}
+	return nil
+}
+
+// replace returns the input that in is solved as
+func (o *optimizer) replace(in *input) *input {
+	v := in.vertex
+	if a, ok := o.aliases[v]; ok {
+		v = a
+	}
+	if c, ok := o.collapsed[v]; ok {
+		return c
+	}
+	if v == in.vertex {
+		return in
+	}
+	return &input{index: in.index, vertex: v}
+}
+
+// structuralKey identifies the operation of v with the structural keys of
+// its inputs, which have been replaced already
+func (o *optimizer) structuralKey(v *vertex) (digest.Digest, error) {
+	type inputKey struct {
+		Key   digest.Digest
+		Index Index
+	}
+	inputs := make([]inputKey, 0, len(v.inputs))
+	for _, in := range v.inputs {
+		key, ok := o.keys[in.vertex]
+		if !ok {
+			// the input is a vertex without an operation
+			key = in.vertex.Digest()
+		}
+		inputs = append(inputs, inputKey{Key: key, Index: in.index})
+	}
+	dt, err := json.Marshal(struct {
+		Op          interface{}
+		Platform    *pb.Platform
+		Constraints *pb.WorkerConstraints
+		Inputs      []inputKey
+	}{
+		Op:          v.sys,
+		Platform:    v.platform,
+		Constraints: v.constraints,
+		Inputs:      inputs,
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to marshal %s", v.Digest())
+	}
+	return digest.FromBytes(dt), nil
+}
+
+// noopInput returns the input that v can be replaced with if v is a merge
+// whose inputs are all the same input
+func noopInput(v *vertex) *input {
+	op, ok := v.sys.(*pb.Op_Merge)
+	if !ok || op.Merge == nil || len(op.Merge.Inputs) == 0 {
+		return nil
+	}
+	var in *input
+	for _, mi := range op.Merge.Inputs {
+		if int(mi.Input) >= len(v.inputs) {
+			return nil
+		}
+		cur := v.inputs[mi.Input]
+		if in != nil && (cur.vertex != in.vertex || cur.index != in.index) {
+			return nil
+		}
+		in = cur
+	}
+	return in
+}
@@ -0,0 +1,55 @@
+package solver
+
+import (
+	"testing"
+
+	"github.com/moby/buildkit/solver/pb"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadLLBOptimize(t *testing.T) {
+	src := &pb.Op{Op: &pb.Op_Source{Source: &pb.SourceOp{Identifier: "docker-image://docker.io/library/busybox:latest"}}}
+	srcDgst, srcDt := marshalTestOp(t, src)
+	unused := &pb.Op{Op: &pb.Op_Source{Source: &pb.SourceOp{Identifier: "docker-image://docker.io/library/alpine:latest"}}}
+	_, unusedDt := marshalTestOp(t, unused)
+
+	merge := &pb.Op{
+		Inputs: []*pb.Input{{Digest: srcDgst}, {Digest: srcDgst}},
+		Op:     &pb.Op_Merge{Merge: &pb.MergeOp{Inputs: []*pb.MergeInput{{Input: 0}, {Input: 1}}}},
+	}
+	mergeDgst, mergeDt := marshalTestOp(t, merge)
+
+	exec := func(in *pb.Input) *pb.Op {
+		return &pb.Op{
+			Inputs: []*pb.Input{in},
+			Op: &pb.Op_Exec{Exec: &pb.ExecOp{
+				Meta:   &pb.Meta{Args: []string{"true"}},
+				Mounts: []*pb.Mount{{Dest: pb.RootMount, Input: 0, Output: 0}},
+			}},
+		}
+	}
+	// the execs only differ by the merge of their input
+	e1Dgst, e1Dt := marshalTestOp(t, exec(&pb.Input{Digest: srcDgst}))
+	e2Dgst, e2Dt := marshalTestOp(t, exec(&pb.Input{Digest: mergeDgst}))
+	require.NotEqual(t, e1Dgst, e2Dgst)
+
+	final := &pb.Op{
+		Inputs: []*pb.Input{{Digest: e1Dgst}, {Digest: e2Dgst}},
+		Op:     &pb.Op_Merge{Merge: &pb.MergeOp{Inputs: []*pb.MergeInput{{Input: 0}, {Input: 1}}}},
+	}
+	finalDgst, finalDt := marshalTestOp(t, final)
+	_, last := marshalTestOp(t, &pb.Op{Inputs: []*pb.Input{{Digest: finalDgst}}})
+
+	v, err := LoadLLB([][]byte{srcDt, unusedDt, mergeDt, e1Dt, e2Dt, finalDt, last})
+	require.NoError(t, err)
+
+	inputs := v.Inputs()
+	require.Equal(t, 1, len(inputs))
+	require.Equal(t, e1Dgst, inputs[0].Vertex.Digest())
+	require.Equal(t, []digest.Digest{e1Dgst}, v.(*vertex).clientVertex.Inputs)
+
+	inputs = inputs[0].Vertex.Inputs()
+	require.Equal(t, 1, len(inputs))
+	require.Equal(t, srcDgst, inputs[0].Vertex.Digest())
+}
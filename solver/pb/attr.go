@@ -3,4 +3,15 @@ package pb
 const AttrKeepGitDir = "git.keepgitdir"
 const AttrLocalSessionID = "local.session"
 const AttrIncludePatterns = "local.includepattern"
+const AttrExcludePatterns = "local.excludepatterns"
+const AttrFollowPaths = "local.followpaths"
+const AttrExcludeXattrs = "local.excludexattrs"
 const AttrLLBDefinitionFilename = "llbbuild.filename"
+
+const AttrOCILayoutLocal = "oci.local"
+
+const AttrHTTPChecksum = "http.checksum"
+const AttrHTTPFilename = "http.filename"
+const AttrHTTPPerm = "http.perm"
+const AttrHTTPUID = "http.uid"
+const AttrHTTPGID = "http.gid"
@@ -0,0 +1,51 @@
+package pb
+
+import "strings"
+
+// OpTypes are the types of the ops that the solver runs. The daemon only
+// advertises the types its workers can run.
+var OpTypes = []string{"source", "exec", "build", "file", "merge", "diff"}
+
+// OpType returns the type of the operation op of an Op, eg. "exec", or "" if
+// the type is unknown
+func OpType(op interface{}) string {
+	switch op.(type) {
+	case *Op_Source:
+		return "source"
+	case *Op_Exec:
+		return "exec"
+	case *Op_Build:
+		return "build"
+	case *Op_File:
+		return "file"
+	case *Op_Merge:
+		return "merge"
+	case *Op_Diff:
+		return "diff"
+	default:
+		return ""
+	}
+}
+
+// MountTypes returns the names of the mount types of the execs of a worker
+// for os, eg. "cache". Windows has no tmpfs. The host mounts are only
+// included with hostMounts, the daemon rejects them if it doesn't allow any
+// host paths.
+func MountTypes(os string, hostMounts bool) []string {
+	types := make([]string, 0, len(MountType_name))
+	for i := 0; i < len(MountType_name); i++ {
+		if MountType(i) == MountType_HOST && !hostMounts {
+			continue
+		}
+		if MountType(i) == MountType_TMPFS && os == "windows" {
+			continue
+		}
+		types = append(types, MountTypeName(MountType(i)))
+	}
+	return types
+}
+
+// MountTypeName returns the name of the mount type t
+func MountTypeName(t MountType) string {
+	return strings.ToLower(t.String())
+}
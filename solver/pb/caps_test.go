@@ -0,0 +1,19 @@
+package pb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpType(t *testing.T) {
+	require.Equal(t, "exec", OpType(&Op_Exec{}))
+	require.Equal(t, "merge", OpType(&Op_Merge{}))
+	require.Equal(t, "", OpType(nil))
+}
+
+func TestMountTypes(t *testing.T) {
+	require.Equal(t, []string{"bind", "cache", "tmpfs", "secret", "ssh", "host"}, MountTypes("linux", true))
+	require.Equal(t, []string{"bind", "cache", "tmpfs", "secret", "ssh"}, MountTypes("linux", false))
+	require.Equal(t, []string{"bind", "cache", "secret", "ssh"}, MountTypes("windows", false))
+}
@@ -10,12 +10,37 @@
 
 	It has these top-level messages:
 		Op
+		WorkerConstraints
+		Platform
 		Input
 		ExecOp
+		SecurityProfile
+		ContentAttrs
+		RetryPolicy
+		Resources
+		Ulimit
 		Meta
+		HostIP
 		Mount
+		Selector
+		TmpfsOpt
+		SecretOpt
+		HostOpt
+		SSHOpt
+		CacheOpt
 		CopyOp
 		CopySource
+		FileOp
+		FileAction
+		FileActionCopy
+		FileActionMkFile
+		FileActionMkDir
+		FileActionRm
+		FileActionSymlink
+		ChownOpt
+		MergeOp
+		MergeInput
+		DiffOp
 		SourceOp
 		BuildOp
 		BuildInput
@@ -29,8 +54,6 @@ import _ "github.com/gogo/protobuf/gogoproto"
 
 import github_com_opencontainers_go_digest "github.com/opencontainers/go-digest"
 
-import errors "errors"
-
 import io "io"
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -38,6 +61,124 @@ var _ = proto.Marshal
 var _ = fmt.Errorf
 var _ = math.Inf
 
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
+
+// NetMode values are prefixed as they share the scope of MountType_HOST
+type NetMode int32
+
+const (
+	// NET_UNSET uses the network of the worker, the host network by default
+	NetMode_NET_UNSET NetMode = 0
+	NetMode_NET_HOST  NetMode = 1
+	// NET_NONE isolates the process in a network namespace with only a
+	// loopback interface
+	NetMode_NET_NONE NetMode = 2
+)
+
+var NetMode_name = map[int32]string{
+	0: "NET_UNSET",
+	1: "NET_HOST",
+	2: "NET_NONE",
+}
+var NetMode_value = map[string]int32{
+	"NET_UNSET": 0,
+	"NET_HOST":  1,
+	"NET_NONE":  2,
+}
+
+func (x NetMode) String() string {
+	return proto.EnumName(NetMode_name, int32(x))
+}
+func (NetMode) EnumDescriptor() ([]byte, []int) { return fileDescriptorOps, []int{0} }
+
+type SecurityMode int32
+
+const (
+	SecurityMode_SANDBOX SecurityMode = 0
+	// INSECURE runs the process with all the capabilities and access to the
+	// devices of the host
+	SecurityMode_INSECURE SecurityMode = 1
+)
+
+var SecurityMode_name = map[int32]string{
+	0: "SANDBOX",
+	1: "INSECURE",
+}
+var SecurityMode_value = map[string]int32{
+	"SANDBOX":  0,
+	"INSECURE": 1,
+}
+
+func (x SecurityMode) String() string {
+	return proto.EnumName(SecurityMode_name, int32(x))
+}
+func (SecurityMode) EnumDescriptor() ([]byte, []int) { return fileDescriptorOps, []int{1} }
+
+type MountType int32
+
+const (
+	MountType_BIND   MountType = 0
+	MountType_CACHE  MountType = 1
+	MountType_TMPFS  MountType = 2
+	MountType_SECRET MountType = 3
+	MountType_SSH    MountType = 4
+	MountType_HOST   MountType = 5
+)
+
+var MountType_name = map[int32]string{
+	0: "BIND",
+	1: "CACHE",
+	2: "TMPFS",
+	3: "SECRET",
+	4: "SSH",
+	5: "HOST",
+}
+var MountType_value = map[string]int32{
+	"BIND":   0,
+	"CACHE":  1,
+	"TMPFS":  2,
+	"SECRET": 3,
+	"SSH":    4,
+	"HOST":   5,
+}
+
+func (x MountType) String() string {
+	return proto.EnumName(MountType_name, int32(x))
+}
+func (MountType) EnumDescriptor() ([]byte, []int) { return fileDescriptorOps, []int{2} }
+
+// CacheSharingOpt defines how concurrent builds can access a cache mount
+type CacheSharingOpt int32
+
+const (
+	// SHARED cache mounts can be used concurrently by multiple writers
+	CacheSharingOpt_SHARED CacheSharingOpt = 0
+	// PRIVATE creates a new cache mount if the existing ones are in use
+	CacheSharingOpt_PRIVATE CacheSharingOpt = 1
+	// LOCKED waits until the previous writer has released the cache mount
+	CacheSharingOpt_LOCKED CacheSharingOpt = 2
+)
+
+var CacheSharingOpt_name = map[int32]string{
+	0: "SHARED",
+	1: "PRIVATE",
+	2: "LOCKED",
+}
+var CacheSharingOpt_value = map[string]int32{
+	"SHARED":  0,
+	"PRIVATE": 1,
+	"LOCKED":  2,
+}
+
+func (x CacheSharingOpt) String() string {
+	return proto.EnumName(CacheSharingOpt_name, int32(x))
+}
+func (CacheSharingOpt) EnumDescriptor() ([]byte, []int) { return fileDescriptorOps, []int{3} }
+
 type Op struct {
 	Inputs []*Input `protobuf:"bytes,1,rep,name=inputs" json:"inputs,omitempty"`
 	// Types that are valid to be assigned to Op:
@@ -45,12 +186,21 @@ type Op struct {
 	//	*Op_Source
 	//	*Op_Copy
 	//	*Op_Build
+	//	*Op_File
+	//	*Op_Merge
+	//	*Op_Diff
 	Op isOp_Op `protobuf_oneof:"op"`
+	// platform the op is run for. The platform of the daemon is used if not
+	// set.
+	Platform *Platform `protobuf:"bytes,9,opt,name=platform" json:"platform,omitempty"`
+	// constraints select the worker that runs the op
+	Constraints *WorkerConstraints `protobuf:"bytes,10,opt,name=constraints" json:"constraints,omitempty"`
 }
 
-func (m *Op) Reset()         { *m = Op{} }
-func (m *Op) String() string { return proto.CompactTextString(m) }
-func (*Op) ProtoMessage()    {}
+func (m *Op) Reset()                    { *m = Op{} }
+func (m *Op) String() string            { return proto.CompactTextString(m) }
+func (*Op) ProtoMessage()               {}
+func (*Op) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{0} }
 
 type isOp_Op interface {
 	isOp_Op()
@@ -70,11 +220,23 @@ type Op_Copy struct {
 type Op_Build struct {
 	Build *BuildOp `protobuf:"bytes,5,opt,name=build,oneof"`
 }
+type Op_File struct {
+	File *FileOp `protobuf:"bytes,6,opt,name=file,oneof"`
+}
+type Op_Merge struct {
+	Merge *MergeOp `protobuf:"bytes,7,opt,name=merge,oneof"`
+}
+type Op_Diff struct {
+	Diff *DiffOp `protobuf:"bytes,8,opt,name=diff,oneof"`
+}
 
 func (*Op_Exec) isOp_Op()   {}
 func (*Op_Source) isOp_Op() {}
 func (*Op_Copy) isOp_Op()   {}
 func (*Op_Build) isOp_Op()  {}
+func (*Op_File) isOp_Op()   {}
+func (*Op_Merge) isOp_Op()  {}
+func (*Op_Diff) isOp_Op()   {}
 
 func (m *Op) GetOp() isOp_Op {
 	if m != nil {
@@ -118,13 +280,51 @@ func (m *Op) GetBuild() *BuildOp {
 	return nil
 }
 
+func (m *Op) GetFile() *FileOp {
+	if x, ok := m.GetOp().(*Op_File); ok {
+		return x.File
+	}
+	return nil
+}
+
+func (m *Op) GetMerge() *MergeOp {
+	if x, ok := m.GetOp().(*Op_Merge); ok {
+		return x.Merge
+	}
+	return nil
+}
+
+func (m *Op) GetDiff() *DiffOp {
+	if x, ok := m.GetOp().(*Op_Diff); ok {
+		return x.Diff
+	}
+	return nil
+}
+
+func (m *Op) GetPlatform() *Platform {
+	if m != nil {
+		return m.Platform
+	}
+	return nil
+}
+
+func (m *Op) GetConstraints() *WorkerConstraints {
+	if m != nil {
+		return m.Constraints
+	}
+	return nil
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
-func (*Op) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), []interface{}) {
-	return _Op_OneofMarshaler, _Op_OneofUnmarshaler, []interface{}{
+func (*Op) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
+	return _Op_OneofMarshaler, _Op_OneofUnmarshaler, _Op_OneofSizer, []interface{}{
 		(*Op_Exec)(nil),
 		(*Op_Source)(nil),
 		(*Op_Copy)(nil),
 		(*Op_Build)(nil),
+		(*Op_File)(nil),
+		(*Op_Merge)(nil),
+		(*Op_Diff)(nil),
 	}
 }
 
@@ -152,6 +352,21 @@ func _Op_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
 		if err := b.EncodeMessage(x.Build); err != nil {
 			return err
 		}
+	case *Op_File:
+		_ = b.EncodeVarint(6<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.File); err != nil {
+			return err
+		}
+	case *Op_Merge:
+		_ = b.EncodeVarint(7<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Merge); err != nil {
+			return err
+		}
+	case *Op_Diff:
+		_ = b.EncodeVarint(8<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Diff); err != nil {
+			return err
+		}
 	case nil:
 	default:
 		return fmt.Errorf("Op.Op has unexpected type %T", x)
@@ -194,28 +409,208 @@ func _Op_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bo
 		err := b.DecodeMessage(msg)
 		m.Op = &Op_Build{msg}
 		return true, err
+	case 6: // op.file
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(FileOp)
+		err := b.DecodeMessage(msg)
+		m.Op = &Op_File{msg}
+		return true, err
+	case 7: // op.merge
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(MergeOp)
+		err := b.DecodeMessage(msg)
+		m.Op = &Op_Merge{msg}
+		return true, err
+	case 8: // op.diff
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(DiffOp)
+		err := b.DecodeMessage(msg)
+		m.Op = &Op_Diff{msg}
+		return true, err
 	default:
 		return false, nil
 	}
 }
 
+func _Op_OneofSizer(msg proto.Message) (n int) {
+	m := msg.(*Op)
+	// op
+	switch x := m.Op.(type) {
+	case *Op_Exec:
+		s := proto.Size(x.Exec)
+		n += proto.SizeVarint(2<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Op_Source:
+		s := proto.Size(x.Source)
+		n += proto.SizeVarint(3<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Op_Copy:
+		s := proto.Size(x.Copy)
+		n += proto.SizeVarint(4<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Op_Build:
+		s := proto.Size(x.Build)
+		n += proto.SizeVarint(5<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Op_File:
+		s := proto.Size(x.File)
+		n += proto.SizeVarint(6<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Op_Merge:
+		s := proto.Size(x.Merge)
+		n += proto.SizeVarint(7<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Op_Diff:
+		s := proto.Size(x.Diff)
+		n += proto.SizeVarint(8<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case nil:
+	default:
+		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+	}
+	return n
+}
+
+// WorkerConstraints are filters matched against the workers of the daemon.
+// An op can only be run by a worker that matches all the filters.
+type WorkerConstraints struct {
+	Filter []string `protobuf:"bytes,1,rep,name=filter" json:"filter,omitempty"`
+}
+
+func (m *WorkerConstraints) Reset()                    { *m = WorkerConstraints{} }
+func (m *WorkerConstraints) String() string            { return proto.CompactTextString(m) }
+func (*WorkerConstraints) ProtoMessage()               {}
+func (*WorkerConstraints) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{1} }
+
+func (m *WorkerConstraints) GetFilter() []string {
+	if m != nil {
+		return m.Filter
+	}
+	return nil
+}
+
+// Platform is the platform of an image or an exec, matching the platform of
+// the OCI image specification
+type Platform struct {
+	Architecture string   `protobuf:"bytes,1,opt,name=Architecture,proto3" json:"Architecture,omitempty"`
+	OS           string   `protobuf:"bytes,2,opt,name=OS,proto3" json:"OS,omitempty"`
+	Variant      string   `protobuf:"bytes,3,opt,name=Variant,proto3" json:"Variant,omitempty"`
+	OSVersion    string   `protobuf:"bytes,4,opt,name=OSVersion,proto3" json:"OSVersion,omitempty"`
+	OSFeatures   []string `protobuf:"bytes,5,rep,name=OSFeatures" json:"OSFeatures,omitempty"`
+}
+
+func (m *Platform) Reset()                    { *m = Platform{} }
+func (m *Platform) String() string            { return proto.CompactTextString(m) }
+func (*Platform) ProtoMessage()               {}
+func (*Platform) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{2} }
+
+func (m *Platform) GetArchitecture() string {
+	if m != nil {
+		return m.Architecture
+	}
+	return ""
+}
+
+func (m *Platform) GetOS() string {
+	if m != nil {
+		return m.OS
+	}
+	return ""
+}
+
+func (m *Platform) GetVariant() string {
+	if m != nil {
+		return m.Variant
+	}
+	return ""
+}
+
+func (m *Platform) GetOSVersion() string {
+	if m != nil {
+		return m.OSVersion
+	}
+	return ""
+}
+
+func (m *Platform) GetOSFeatures() []string {
+	if m != nil {
+		return m.OSFeatures
+	}
+	return nil
+}
+
 type Input struct {
 	Digest github_com_opencontainers_go_digest.Digest `protobuf:"bytes,1,opt,name=digest,proto3,customtype=github.com/opencontainers/go-digest.Digest" json:"digest"`
 	Index  OutputIndex                                `protobuf:"varint,2,opt,name=index,proto3,customtype=OutputIndex" json:"index"`
 }
 
-func (m *Input) Reset()         { *m = Input{} }
-func (m *Input) String() string { return proto.CompactTextString(m) }
-func (*Input) ProtoMessage()    {}
+func (m *Input) Reset()                    { *m = Input{} }
+func (m *Input) String() string            { return proto.CompactTextString(m) }
+func (*Input) ProtoMessage()               {}
+func (*Input) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{3} }
 
 type ExecOp struct {
 	Meta   *Meta    `protobuf:"bytes,1,opt,name=meta" json:"meta,omitempty"`
 	Mounts []*Mount `protobuf:"bytes,2,rep,name=mounts" json:"mounts,omitempty"`
+	// contentCacheRoot makes the content of the root mount part of the content
+	// based cache key instead of the definition of the root input
+	ContentCacheRoot bool `protobuf:"varint,3,opt,name=contentCacheRoot,proto3" json:"contentCacheRoot,omitempty"`
+	// resources limit the resources the process can use. Limits of the
+	// daemon are applied on top of these.
+	Resources *Resources `protobuf:"bytes,4,opt,name=resources" json:"resources,omitempty"`
+	// timeout in seconds after which the process is killed, zero for no
+	// timeout
+	Timeout int64        `protobuf:"varint,5,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	Retry   *RetryPolicy `protobuf:"bytes,6,opt,name=retry" json:"retry,omitempty"`
+	// readonlyRootfs runs the process with a read-only root filesystem. Only
+	// the other mounts are writable and the root output is the unmodified
+	// root input.
+	ReadonlyRootfs bool `protobuf:"varint,7,opt,name=readonlyRootfs,proto3" json:"readonlyRootfs,omitempty"`
+	// noProxyEnv disables the proxy environment variables that the daemon
+	// may add to the process
+	NoProxyEnv bool `protobuf:"varint,8,opt,name=noProxyEnv,proto3" json:"noProxyEnv,omitempty"`
+	// proxyEnv are KEY=VALUE proxy variables set by the client. They override
+	// the proxy variables of the daemon with the same name and, like them, are
+	// not part of the cache key.
+	ProxyEnv []string `protobuf:"bytes,9,rep,name=proxyEnv" json:"proxyEnv,omitempty"`
+	// contentAttrs are the file attributes of the content keyed mounts that
+	// are part of the cache key
+	ContentAttrs *ContentAttrs `protobuf:"bytes,10,opt,name=contentAttrs" json:"contentAttrs,omitempty"`
+	// checkpointInterval in seconds after which the root mount is saved while
+	// the process is running, zero for no checkpoints. A process that is run
+	// again after the daemon has stopped starts from the latest checkpoint.
+	CheckpointInterval int64 `protobuf:"varint,11,opt,name=checkpointInterval,proto3" json:"checkpointInterval,omitempty"`
+	// network is the network namespace of the process. NetMode_NET_HOST requires
+	// the network.host entitlement.
+	Network NetMode `protobuf:"varint,12,opt,name=network,proto3,enum=pb.NetMode" json:"network,omitempty"`
+	// security is the sandbox of the process. SecurityMode_INSECURE requires
+	// the security.insecure entitlement.
+	Security SecurityMode `protobuf:"varint,13,opt,name=security,proto3,enum=pb.SecurityMode" json:"security,omitempty"`
+	// devices are the paths of the host devices the process can access. They
+	// require the device entitlement.
+	Devices []string `protobuf:"bytes,14,rep,name=devices" json:"devices,omitempty"`
+	// securityProfile overrides the profiles of the daemon that confine the
+	// process. It requires the security.profile entitlement.
+	SecurityProfile *SecurityProfile `protobuf:"bytes,15,opt,name=securityProfile" json:"securityProfile,omitempty"`
 }
 
-func (m *ExecOp) Reset()         { *m = ExecOp{} }
-func (m *ExecOp) String() string { return proto.CompactTextString(m) }
-func (*ExecOp) ProtoMessage()    {}
+func (m *ExecOp) Reset()                    { *m = ExecOp{} }
+func (m *ExecOp) String() string            { return proto.CompactTextString(m) }
+func (*ExecOp) ProtoMessage()               {}
+func (*ExecOp) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{4} }
 
 func (m *ExecOp) GetMeta() *Meta {
 	if m != nil {
@@ -231,841 +626,8382 @@ func (m *ExecOp) GetMounts() []*Mount {
 	return nil
 }
 
-type Meta struct {
-	Args []string `protobuf:"bytes,1,rep,name=args" json:"args,omitempty"`
-	Env  []string `protobuf:"bytes,2,rep,name=env" json:"env,omitempty"`
-	Cwd  string   `protobuf:"bytes,3,opt,name=cwd,proto3" json:"cwd,omitempty"`
+func (m *ExecOp) GetContentCacheRoot() bool {
+	if m != nil {
+		return m.ContentCacheRoot
+	}
+	return false
 }
 
-func (m *Meta) Reset()         { *m = Meta{} }
-func (m *Meta) String() string { return proto.CompactTextString(m) }
-func (*Meta) ProtoMessage()    {}
-
-type Mount struct {
-	Input    InputIndex  `protobuf:"varint,1,opt,name=input,proto3,customtype=InputIndex" json:"input"`
-	Selector string      `protobuf:"bytes,2,opt,name=selector,proto3" json:"selector,omitempty"`
-	Dest     string      `protobuf:"bytes,3,opt,name=dest,proto3" json:"dest,omitempty"`
-	Output   OutputIndex `protobuf:"varint,4,opt,name=output,proto3,customtype=OutputIndex" json:"output"`
-	Readonly bool        `protobuf:"varint,5,opt,name=readonly,proto3" json:"readonly,omitempty"`
+func (m *ExecOp) GetResources() *Resources {
+	if m != nil {
+		return m.Resources
+	}
+	return nil
 }
 
-func (m *Mount) Reset()         { *m = Mount{} }
-func (m *Mount) String() string { return proto.CompactTextString(m) }
-func (*Mount) ProtoMessage()    {}
-
-type CopyOp struct {
-	Src  []*CopySource `protobuf:"bytes,1,rep,name=src" json:"src,omitempty"`
-	Dest string        `protobuf:"bytes,2,opt,name=dest,proto3" json:"dest,omitempty"`
+func (m *ExecOp) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
 }
 
-func (m *CopyOp) Reset()         { *m = CopyOp{} }
-func (m *CopyOp) String() string { return proto.CompactTextString(m) }
-func (*CopyOp) ProtoMessage()    {}
-
-func (m *CopyOp) GetSrc() []*CopySource {
+func (m *ExecOp) GetRetry() *RetryPolicy {
 	if m != nil {
-		return m.Src
+		return m.Retry
 	}
 	return nil
 }
 
-type CopySource struct {
-	Input    InputIndex `protobuf:"varint,1,opt,name=input,proto3,customtype=InputIndex" json:"input"`
-	Selector string     `protobuf:"bytes,2,opt,name=selector,proto3" json:"selector,omitempty"`
+func (m *ExecOp) GetReadonlyRootfs() bool {
+	if m != nil {
+		return m.ReadonlyRootfs
+	}
+	return false
 }
 
-func (m *CopySource) Reset()         { *m = CopySource{} }
-func (m *CopySource) String() string { return proto.CompactTextString(m) }
-func (*CopySource) ProtoMessage()    {}
-
-type SourceOp struct {
-	// source type?
-	Identifier string            `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
-	Attrs      map[string]string `protobuf:"bytes,2,rep,name=attrs" json:"attrs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+func (m *ExecOp) GetNoProxyEnv() bool {
+	if m != nil {
+		return m.NoProxyEnv
+	}
+	return false
 }
 
-func (m *SourceOp) Reset()         { *m = SourceOp{} }
-func (m *SourceOp) String() string { return proto.CompactTextString(m) }
-func (*SourceOp) ProtoMessage()    {}
+func (m *ExecOp) GetProxyEnv() []string {
+	if m != nil {
+		return m.ProxyEnv
+	}
+	return nil
+}
 
-func (m *SourceOp) GetAttrs() map[string]string {
+func (m *ExecOp) GetContentAttrs() *ContentAttrs {
 	if m != nil {
-		return m.Attrs
+		return m.ContentAttrs
 	}
 	return nil
 }
 
-type BuildOp struct {
-	Builder InputIndex             `protobuf:"varint,1,opt,name=builder,proto3,customtype=InputIndex" json:"builder"`
-	Inputs  map[string]*BuildInput `protobuf:"bytes,2,rep,name=inputs" json:"inputs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value"`
-	Def     [][]byte               `protobuf:"bytes,3,rep,name=def" json:"def,omitempty"`
-	Attrs   map[string]string      `protobuf:"bytes,4,rep,name=attrs" json:"attrs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+func (m *ExecOp) GetCheckpointInterval() int64 {
+	if m != nil {
+		return m.CheckpointInterval
+	}
+	return 0
+}
+
+func (m *ExecOp) GetNetwork() NetMode {
+	if m != nil {
+		return m.Network
+	}
+	return NetMode_NET_UNSET
 }
 
-func (m *BuildOp) Reset()         { *m = BuildOp{} }
-func (m *BuildOp) String() string { return proto.CompactTextString(m) }
-func (*BuildOp) ProtoMessage()    {}
+func (m *ExecOp) GetSecurity() SecurityMode {
+	if m != nil {
+		return m.Security
+	}
+	return SecurityMode_SANDBOX
+}
 
-func (m *BuildOp) GetInputs() map[string]*BuildInput {
+func (m *ExecOp) GetDevices() []string {
 	if m != nil {
-		return m.Inputs
+		return m.Devices
 	}
 	return nil
 }
 
-func (m *BuildOp) GetAttrs() map[string]string {
+func (m *ExecOp) GetSecurityProfile() *SecurityProfile {
 	if m != nil {
-		return m.Attrs
+		return m.SecurityProfile
 	}
 	return nil
 }
 
-type BuildInput struct {
-	Input InputIndex `protobuf:"varint,1,opt,name=input,proto3,customtype=InputIndex" json:"input"`
+// SecurityProfile confines an exec. Empty fields use the profiles of the
+// daemon.
+type SecurityProfile struct {
+	// seccomp is the JSON of a seccomp configuration of the OCI runtime spec,
+	// or "unconfined"
+	Seccomp string `protobuf:"bytes,1,opt,name=seccomp,proto3" json:"seccomp,omitempty"`
+	// apparmor is the name of an AppArmor profile loaded on the host, or
+	// "unconfined"
+	Apparmor string `protobuf:"bytes,2,opt,name=apparmor,proto3" json:"apparmor,omitempty"`
+	// selinuxLabel is the SELinux label of the process
+	SelinuxLabel string `protobuf:"bytes,3,opt,name=selinuxLabel,proto3" json:"selinuxLabel,omitempty"`
 }
 
-func (m *BuildInput) Reset()         { *m = BuildInput{} }
-func (m *BuildInput) String() string { return proto.CompactTextString(m) }
-func (*BuildInput) ProtoMessage()    {}
+func (m *SecurityProfile) Reset()                    { *m = SecurityProfile{} }
+func (m *SecurityProfile) String() string            { return proto.CompactTextString(m) }
+func (*SecurityProfile) ProtoMessage()               {}
+func (*SecurityProfile) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{5} }
 
-func init() {
-	proto.RegisterType((*Op)(nil), "pb.Op")
-	proto.RegisterType((*Input)(nil), "pb.Input")
-	proto.RegisterType((*ExecOp)(nil), "pb.ExecOp")
-	proto.RegisterType((*Meta)(nil), "pb.Meta")
-	proto.RegisterType((*Mount)(nil), "pb.Mount")
-	proto.RegisterType((*CopyOp)(nil), "pb.CopyOp")
-	proto.RegisterType((*CopySource)(nil), "pb.CopySource")
-	proto.RegisterType((*SourceOp)(nil), "pb.SourceOp")
-	proto.RegisterType((*BuildOp)(nil), "pb.BuildOp")
-	proto.RegisterType((*BuildInput)(nil), "pb.BuildInput")
-}
-func (m *Op) Marshal() (data []byte, err error) {
-	size := m.Size()
-	data = make([]byte, size)
-	n, err := m.MarshalTo(data)
-	if err != nil {
-		return nil, err
+func (m *SecurityProfile) GetSeccomp() string {
+	if m != nil {
+		return m.Seccomp
 	}
-	return data[:n], nil
+	return ""
 }
 
-func (m *Op) MarshalTo(data []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Inputs) > 0 {
-		for _, msg := range m.Inputs {
-			data[i] = 0xa
-			i++
-			i = encodeVarintOps(data, i, uint64(msg.Size()))
-			n, err := msg.MarshalTo(data[i:])
-			if err != nil {
-				return 0, err
-			}
-			i += n
-		}
-	}
-	if m.Op != nil {
-		nn1, err := m.Op.MarshalTo(data[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += nn1
+func (m *SecurityProfile) GetApparmor() string {
+	if m != nil {
+		return m.Apparmor
 	}
-	return i, nil
+	return ""
 }
 
-func (m *Op_Exec) MarshalTo(data []byte) (int, error) {
-	i := 0
-	if m.Exec != nil {
-		data[i] = 0x12
-		i++
-		i = encodeVarintOps(data, i, uint64(m.Exec.Size()))
-		n2, err := m.Exec.MarshalTo(data[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n2
+func (m *SecurityProfile) GetSelinuxLabel() string {
+	if m != nil {
+		return m.SelinuxLabel
 	}
-	return i, nil
+	return ""
 }
-func (m *Op_Source) MarshalTo(data []byte) (int, error) {
-	i := 0
-	if m.Source != nil {
-		data[i] = 0x1a
-		i++
-		i = encodeVarintOps(data, i, uint64(m.Source.Size()))
-		n3, err := m.Source.MarshalTo(data[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n3
+
+// ContentAttrs are the file attributes that are part of a content based cache
+// key in addition to the contents, the file types, the permission bits, the
+// link targets and the extended attributes
+type ContentAttrs struct {
+	// ownership is the uid and gid of the files
+	Ownership bool `protobuf:"varint,1,opt,name=ownership,proto3" json:"ownership,omitempty"`
+	// xattrs are all the extended attributes, including the capabilities, for
+	// the sources that exclude them
+	Xattrs bool `protobuf:"varint,2,opt,name=xattrs,proto3" json:"xattrs,omitempty"`
+	// capabilities is the security.capability extended attribute
+	Capabilities bool `protobuf:"varint,3,opt,name=capabilities,proto3" json:"capabilities,omitempty"`
+	// modTime is the modification time of the files
+	ModTime bool `protobuf:"varint,4,opt,name=modTime,proto3" json:"modTime,omitempty"`
+}
+
+func (m *ContentAttrs) Reset()                    { *m = ContentAttrs{} }
+func (m *ContentAttrs) String() string            { return proto.CompactTextString(m) }
+func (*ContentAttrs) ProtoMessage()               {}
+func (*ContentAttrs) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{6} }
+
+func (m *ContentAttrs) GetOwnership() bool {
+	if m != nil {
+		return m.Ownership
 	}
-	return i, nil
+	return false
 }
-func (m *Op_Copy) MarshalTo(data []byte) (int, error) {
-	i := 0
-	if m.Copy != nil {
-		data[i] = 0x22
-		i++
-		i = encodeVarintOps(data, i, uint64(m.Copy.Size()))
-		n4, err := m.Copy.MarshalTo(data[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n4
+
+func (m *ContentAttrs) GetXattrs() bool {
+	if m != nil {
+		return m.Xattrs
 	}
-	return i, nil
+	return false
 }
-func (m *Op_Build) MarshalTo(data []byte) (int, error) {
-	i := 0
-	if m.Build != nil {
-		data[i] = 0x2a
-		i++
-		i = encodeVarintOps(data, i, uint64(m.Build.Size()))
-		n5, err := m.Build.MarshalTo(data[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n5
+
+func (m *ContentAttrs) GetCapabilities() bool {
+	if m != nil {
+		return m.Capabilities
 	}
-	return i, nil
+	return false
 }
-func (m *Input) Marshal() (data []byte, err error) {
-	size := m.Size()
-	data = make([]byte, size)
-	n, err := m.MarshalTo(data)
-	if err != nil {
-		return nil, err
+
+func (m *ContentAttrs) GetModTime() bool {
+	if m != nil {
+		return m.ModTime
 	}
-	return data[:n], nil
+	return false
 }
 
-func (m *Input) MarshalTo(data []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Digest) > 0 {
-		data[i] = 0xa
-		i++
-		i = encodeVarintOps(data, i, uint64(len(m.Digest)))
-		i += copy(data[i:], m.Digest)
-	}
-	if m.Index != 0 {
-		data[i] = 0x10
-		i++
-		i = encodeVarintOps(data, i, uint64(m.Index))
-	}
-	return i, nil
+// RetryPolicy defines when a failed exec is run again
+type RetryPolicy struct {
+	// maxRetries is the number of times the exec is run again after the first
+	// attempt has failed
+	MaxRetries int32 `protobuf:"varint,1,opt,name=maxRetries,proto3" json:"maxRetries,omitempty"`
+	// exitCodes limits the retries to processes exiting with one of the codes.
+	// All failures are retried if no codes are set.
+	ExitCodes []int32 `protobuf:"varint,2,rep,packed,name=exitCodes" json:"exitCodes,omitempty"`
 }
 
-func (m *ExecOp) Marshal() (data []byte, err error) {
-	size := m.Size()
-	data = make([]byte, size)
-	n, err := m.MarshalTo(data)
-	if err != nil {
-		return nil, err
+func (m *RetryPolicy) Reset()                    { *m = RetryPolicy{} }
+func (m *RetryPolicy) String() string            { return proto.CompactTextString(m) }
+func (*RetryPolicy) ProtoMessage()               {}
+func (*RetryPolicy) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{7} }
+
+func (m *RetryPolicy) GetMaxRetries() int32 {
+	if m != nil {
+		return m.MaxRetries
 	}
-	return data[:n], nil
+	return 0
 }
 
-func (m *ExecOp) MarshalTo(data []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.Meta != nil {
-		data[i] = 0xa
-		i++
-		i = encodeVarintOps(data, i, uint64(m.Meta.Size()))
-		n6, err := m.Meta.MarshalTo(data[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n6
-	}
-	if len(m.Mounts) > 0 {
-		for _, msg := range m.Mounts {
-			data[i] = 0x12
-			i++
-			i = encodeVarintOps(data, i, uint64(msg.Size()))
-			n, err := msg.MarshalTo(data[i:])
-			if err != nil {
-				return 0, err
-			}
-			i += n
-		}
+func (m *RetryPolicy) GetExitCodes() []int32 {
+	if m != nil {
+		return m.ExitCodes
 	}
-	return i, nil
+	return nil
 }
 
-func (m *Meta) Marshal() (data []byte, err error) {
-	size := m.Size()
-	data = make([]byte, size)
-	n, err := m.MarshalTo(data)
-	if err != nil {
-		return nil, err
-	}
-	return data[:n], nil
+// Resources defines the resource limits of an exec. Zero values use the
+// defaults of the daemon.
+type Resources struct {
+	CpuShares int64 `protobuf:"varint,1,opt,name=cpuShares,proto3" json:"cpuShares,omitempty"`
+	// cpuQuota is the allowed cpu time in microseconds per cpuPeriod
+	CpuQuota  int64 `protobuf:"varint,2,opt,name=cpuQuota,proto3" json:"cpuQuota,omitempty"`
+	CpuPeriod int64 `protobuf:"varint,3,opt,name=cpuPeriod,proto3" json:"cpuPeriod,omitempty"`
+	// memory limit in bytes
+	Memory    int64     `protobuf:"varint,4,opt,name=memory,proto3" json:"memory,omitempty"`
+	PidsLimit int64     `protobuf:"varint,5,opt,name=pidsLimit,proto3" json:"pidsLimit,omitempty"`
+	Ulimits   []*Ulimit `protobuf:"bytes,6,rep,name=ulimits" json:"ulimits,omitempty"`
 }
 
-func (m *Meta) MarshalTo(data []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Args) > 0 {
-		for _, s := range m.Args {
-			data[i] = 0xa
-			i++
-			l = len(s)
-			for l >= 1<<7 {
-				data[i] = uint8(uint64(l)&0x7f | 0x80)
-				l >>= 7
-				i++
-			}
-			data[i] = uint8(l)
-			i++
-			i += copy(data[i:], s)
-		}
-	}
-	if len(m.Env) > 0 {
-		for _, s := range m.Env {
-			data[i] = 0x12
-			i++
-			l = len(s)
-			for l >= 1<<7 {
-				data[i] = uint8(uint64(l)&0x7f | 0x80)
-				l >>= 7
-				i++
-			}
-			data[i] = uint8(l)
-			i++
-			i += copy(data[i:], s)
-		}
-	}
-	if len(m.Cwd) > 0 {
-		data[i] = 0x1a
-		i++
-		i = encodeVarintOps(data, i, uint64(len(m.Cwd)))
-		i += copy(data[i:], m.Cwd)
+func (m *Resources) Reset()                    { *m = Resources{} }
+func (m *Resources) String() string            { return proto.CompactTextString(m) }
+func (*Resources) ProtoMessage()               {}
+func (*Resources) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{8} }
+
+func (m *Resources) GetCpuShares() int64 {
+	if m != nil {
+		return m.CpuShares
 	}
-	return i, nil
+	return 0
 }
 
-func (m *Mount) Marshal() (data []byte, err error) {
-	size := m.Size()
-	data = make([]byte, size)
-	n, err := m.MarshalTo(data)
-	if err != nil {
-		return nil, err
+func (m *Resources) GetCpuQuota() int64 {
+	if m != nil {
+		return m.CpuQuota
 	}
-	return data[:n], nil
+	return 0
 }
 
-func (m *Mount) MarshalTo(data []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.Input != 0 {
-		data[i] = 0x8
-		i++
-		i = encodeVarintOps(data, i, uint64(m.Input))
-	}
-	if len(m.Selector) > 0 {
-		data[i] = 0x12
-		i++
-		i = encodeVarintOps(data, i, uint64(len(m.Selector)))
-		i += copy(data[i:], m.Selector)
-	}
-	if len(m.Dest) > 0 {
-		data[i] = 0x1a
-		i++
-		i = encodeVarintOps(data, i, uint64(len(m.Dest)))
-		i += copy(data[i:], m.Dest)
-	}
-	if m.Output != 0 {
-		data[i] = 0x20
-		i++
-		i = encodeVarintOps(data, i, uint64(m.Output))
-	}
-	if m.Readonly {
-		data[i] = 0x28
-		i++
-		if m.Readonly {
-			data[i] = 1
-		} else {
-			data[i] = 0
-		}
-		i++
+func (m *Resources) GetCpuPeriod() int64 {
+	if m != nil {
+		return m.CpuPeriod
 	}
-	return i, nil
+	return 0
 }
 
-func (m *CopyOp) Marshal() (data []byte, err error) {
-	size := m.Size()
-	data = make([]byte, size)
-	n, err := m.MarshalTo(data)
-	if err != nil {
-		return nil, err
+func (m *Resources) GetMemory() int64 {
+	if m != nil {
+		return m.Memory
 	}
-	return data[:n], nil
+	return 0
 }
 
-func (m *CopyOp) MarshalTo(data []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Src) > 0 {
-		for _, msg := range m.Src {
-			data[i] = 0xa
-			i++
-			i = encodeVarintOps(data, i, uint64(msg.Size()))
-			n, err := msg.MarshalTo(data[i:])
-			if err != nil {
-				return 0, err
-			}
-			i += n
-		}
-	}
-	if len(m.Dest) > 0 {
-		data[i] = 0x12
-		i++
-		i = encodeVarintOps(data, i, uint64(len(m.Dest)))
-		i += copy(data[i:], m.Dest)
+func (m *Resources) GetPidsLimit() int64 {
+	if m != nil {
+		return m.PidsLimit
 	}
-	return i, nil
+	return 0
 }
 
-func (m *CopySource) Marshal() (data []byte, err error) {
-	size := m.Size()
-	data = make([]byte, size)
-	n, err := m.MarshalTo(data)
-	if err != nil {
-		return nil, err
+func (m *Resources) GetUlimits() []*Ulimit {
+	if m != nil {
+		return m.Ulimits
 	}
-	return data[:n], nil
+	return nil
 }
 
-func (m *CopySource) MarshalTo(data []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.Input != 0 {
-		data[i] = 0x8
-		i++
-		i = encodeVarintOps(data, i, uint64(m.Input))
-	}
-	if len(m.Selector) > 0 {
-		data[i] = 0x12
-		i++
-		i = encodeVarintOps(data, i, uint64(len(m.Selector)))
-		i += copy(data[i:], m.Selector)
-	}
-	return i, nil
+// Ulimit sets a POSIX rlimit for the process, eg. name "nofile"
+type Ulimit struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Soft int64  `protobuf:"varint,2,opt,name=soft,proto3" json:"soft,omitempty"`
+	Hard int64  `protobuf:"varint,3,opt,name=hard,proto3" json:"hard,omitempty"`
 }
 
-func (m *SourceOp) Marshal() (data []byte, err error) {
-	size := m.Size()
-	data = make([]byte, size)
-	n, err := m.MarshalTo(data)
-	if err != nil {
-		return nil, err
+func (m *Ulimit) Reset()                    { *m = Ulimit{} }
+func (m *Ulimit) String() string            { return proto.CompactTextString(m) }
+func (*Ulimit) ProtoMessage()               {}
+func (*Ulimit) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{9} }
+
+func (m *Ulimit) GetName() string {
+	if m != nil {
+		return m.Name
 	}
-	return data[:n], nil
+	return ""
 }
 
-func (m *SourceOp) MarshalTo(data []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Identifier) > 0 {
-		data[i] = 0xa
-		i++
-		i = encodeVarintOps(data, i, uint64(len(m.Identifier)))
-		i += copy(data[i:], m.Identifier)
-	}
-	if len(m.Attrs) > 0 {
-		for k, _ := range m.Attrs {
-			data[i] = 0x12
-			i++
-			v := m.Attrs[k]
-			mapSize := 1 + len(k) + sovOps(uint64(len(k))) + 1 + len(v) + sovOps(uint64(len(v)))
-			i = encodeVarintOps(data, i, uint64(mapSize))
-			data[i] = 0xa
-			i++
-			i = encodeVarintOps(data, i, uint64(len(k)))
-			i += copy(data[i:], k)
-			data[i] = 0x12
-			i++
-			i = encodeVarintOps(data, i, uint64(len(v)))
-			i += copy(data[i:], v)
-		}
+func (m *Ulimit) GetSoft() int64 {
+	if m != nil {
+		return m.Soft
 	}
-	return i, nil
+	return 0
 }
 
-func (m *BuildOp) Marshal() (data []byte, err error) {
-	size := m.Size()
-	data = make([]byte, size)
-	n, err := m.MarshalTo(data)
-	if err != nil {
-		return nil, err
+func (m *Ulimit) GetHard() int64 {
+	if m != nil {
+		return m.Hard
 	}
-	return data[:n], nil
+	return 0
 }
 
-func (m *BuildOp) MarshalTo(data []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.Builder != 0 {
-		data[i] = 0x8
-		i++
-		i = encodeVarintOps(data, i, uint64(m.Builder))
+type Meta struct {
+	Args []string `protobuf:"bytes,1,rep,name=args" json:"args,omitempty"`
+	Env  []string `protobuf:"bytes,2,rep,name=env" json:"env,omitempty"`
+	Cwd  string   `protobuf:"bytes,3,opt,name=cwd,proto3" json:"cwd,omitempty"`
+	// user and optionally group the process runs as, resolved with the
+	// passwd and group files of the root filesystem
+	User string `protobuf:"bytes,4,opt,name=user,proto3" json:"user,omitempty"`
+	// hostname of the process, "buildkitsandbox" by default
+	Hostname string `protobuf:"bytes,5,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	// extraHosts are added to the /etc/hosts file the worker creates for the
+	// process
+	ExtraHosts []*HostIP `protobuf:"bytes,6,rep,name=extraHosts" json:"extraHosts,omitempty"`
+}
+
+func (m *Meta) Reset()                    { *m = Meta{} }
+func (m *Meta) String() string            { return proto.CompactTextString(m) }
+func (*Meta) ProtoMessage()               {}
+func (*Meta) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{10} }
+
+func (m *Meta) GetArgs() []string {
+	if m != nil {
+		return m.Args
 	}
-	if len(m.Inputs) > 0 {
-		for k, _ := range m.Inputs {
-			data[i] = 0x12
-			i++
-			v := m.Inputs[k]
-			if v == nil {
-				return 0, errors.New("proto: map has nil element")
-			}
-			msgSize := v.Size()
-			mapSize := 1 + len(k) + sovOps(uint64(len(k))) + 1 + msgSize + sovOps(uint64(msgSize))
-			i = encodeVarintOps(data, i, uint64(mapSize))
-			data[i] = 0xa
-			i++
-			i = encodeVarintOps(data, i, uint64(len(k)))
-			i += copy(data[i:], k)
-			data[i] = 0x12
-			i++
-			i = encodeVarintOps(data, i, uint64(v.Size()))
-			n7, err := v.MarshalTo(data[i:])
-			if err != nil {
-				return 0, err
-			}
-			i += n7
-		}
+	return nil
+}
+
+func (m *Meta) GetEnv() []string {
+	if m != nil {
+		return m.Env
 	}
-	if len(m.Def) > 0 {
-		for _, b := range m.Def {
-			data[i] = 0x1a
-			i++
-			i = encodeVarintOps(data, i, uint64(len(b)))
-			i += copy(data[i:], b)
-		}
+	return nil
+}
+
+func (m *Meta) GetCwd() string {
+	if m != nil {
+		return m.Cwd
 	}
-	if len(m.Attrs) > 0 {
-		for k, _ := range m.Attrs {
-			data[i] = 0x22
-			i++
-			v := m.Attrs[k]
-			mapSize := 1 + len(k) + sovOps(uint64(len(k))) + 1 + len(v) + sovOps(uint64(len(v)))
-			i = encodeVarintOps(data, i, uint64(mapSize))
-			data[i] = 0xa
-			i++
-			i = encodeVarintOps(data, i, uint64(len(k)))
-			i += copy(data[i:], k)
-			data[i] = 0x12
-			i++
-			i = encodeVarintOps(data, i, uint64(len(v)))
-			i += copy(data[i:], v)
-		}
+	return ""
+}
+
+func (m *Meta) GetUser() string {
+	if m != nil {
+		return m.User
 	}
-	return i, nil
+	return ""
 }
 
-func (m *BuildInput) Marshal() (data []byte, err error) {
-	size := m.Size()
-	data = make([]byte, size)
-	n, err := m.MarshalTo(data)
-	if err != nil {
-		return nil, err
+func (m *Meta) GetHostname() string {
+	if m != nil {
+		return m.Hostname
 	}
-	return data[:n], nil
+	return ""
 }
 
-func (m *BuildInput) MarshalTo(data []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.Input != 0 {
-		data[i] = 0x8
-		i++
-		i = encodeVarintOps(data, i, uint64(m.Input))
+func (m *Meta) GetExtraHosts() []*HostIP {
+	if m != nil {
+		return m.ExtraHosts
 	}
-	return i, nil
+	return nil
 }
 
-func encodeFixed64Ops(data []byte, offset int, v uint64) int {
-	data[offset] = uint8(v)
-	data[offset+1] = uint8(v >> 8)
-	data[offset+2] = uint8(v >> 16)
-	data[offset+3] = uint8(v >> 24)
-	data[offset+4] = uint8(v >> 32)
-	data[offset+5] = uint8(v >> 40)
-	data[offset+6] = uint8(v >> 48)
-	data[offset+7] = uint8(v >> 56)
-	return offset + 8
+// HostIP maps a host name to an IP address in /etc/hosts
+type HostIP struct {
+	Host string `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	IP   string `protobuf:"bytes,2,opt,name=IP,proto3" json:"IP,omitempty"`
 }
-func encodeFixed32Ops(data []byte, offset int, v uint32) int {
-	data[offset] = uint8(v)
-	data[offset+1] = uint8(v >> 8)
-	data[offset+2] = uint8(v >> 16)
-	data[offset+3] = uint8(v >> 24)
-	return offset + 4
+
+func (m *HostIP) Reset()                    { *m = HostIP{} }
+func (m *HostIP) String() string            { return proto.CompactTextString(m) }
+func (*HostIP) ProtoMessage()               {}
+func (*HostIP) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{11} }
+
+func (m *HostIP) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
 }
-func encodeVarintOps(data []byte, offset int, v uint64) int {
-	for v >= 1<<7 {
-		data[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+
+func (m *HostIP) GetIP() string {
+	if m != nil {
+		return m.IP
 	}
-	data[offset] = uint8(v)
-	return offset + 1
+	return ""
 }
-func (m *Op) Size() (n int) {
-	var l int
-	_ = l
-	if len(m.Inputs) > 0 {
-		for _, e := range m.Inputs {
-			l = e.Size()
-			n += 1 + l + sovOps(uint64(l))
-		}
+
+type Mount struct {
+	Input    InputIndex  `protobuf:"varint,1,opt,name=input,proto3,customtype=InputIndex" json:"input"`
+	Selector string      `protobuf:"bytes,2,opt,name=selector,proto3" json:"selector,omitempty"`
+	Dest     string      `protobuf:"bytes,3,opt,name=dest,proto3" json:"dest,omitempty"`
+	Output   OutputIndex `protobuf:"varint,4,opt,name=output,proto3,customtype=OutputIndex" json:"output"`
+	Readonly bool        `protobuf:"varint,5,opt,name=readonly,proto3" json:"readonly,omitempty"`
+	// contentCache makes a writable mount without a selector part of the
+	// content based cache key
+	ContentCache bool      `protobuf:"varint,6,opt,name=contentCache,proto3" json:"contentCache,omitempty"`
+	MountType    MountType `protobuf:"varint,7,opt,name=mountType,proto3,enum=pb.MountType" json:"mountType,omitempty"`
+	// contentSelectors limit the content based cache key of a read-only
+	// mount to the selected paths of the mounted directory
+	ContentSelectors []*Selector `protobuf:"bytes,8,rep,name=contentSelectors" json:"contentSelectors,omitempty"`
+	CacheOpt         *CacheOpt   `protobuf:"bytes,20,opt,name=cacheOpt" json:"cacheOpt,omitempty"`
+	TmpfsOpt         *TmpfsOpt   `protobuf:"bytes,21,opt,name=tmpfsOpt" json:"tmpfsOpt,omitempty"`
+	SecretOpt        *SecretOpt  `protobuf:"bytes,22,opt,name=secretOpt" json:"secretOpt,omitempty"`
+	SSHOpt           *SSHOpt     `protobuf:"bytes,23,opt,name=SSHOpt" json:"SSHOpt,omitempty"`
+	HostOpt          *HostOpt    `protobuf:"bytes,24,opt,name=hostOpt" json:"hostOpt,omitempty"`
+}
+
+func (m *Mount) Reset()                    { *m = Mount{} }
+func (m *Mount) String() string            { return proto.CompactTextString(m) }
+func (*Mount) ProtoMessage()               {}
+func (*Mount) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{12} }
+
+func (m *Mount) GetSelector() string {
+	if m != nil {
+		return m.Selector
 	}
-	if m.Op != nil {
-		n += m.Op.Size()
+	return ""
+}
+
+func (m *Mount) GetDest() string {
+	if m != nil {
+		return m.Dest
 	}
-	return n
+	return ""
 }
 
-func (m *Op_Exec) Size() (n int) {
-	var l int
-	_ = l
-	if m.Exec != nil {
-		l = m.Exec.Size()
-		n += 1 + l + sovOps(uint64(l))
+func (m *Mount) GetReadonly() bool {
+	if m != nil {
+		return m.Readonly
 	}
-	return n
+	return false
 }
-func (m *Op_Source) Size() (n int) {
-	var l int
-	_ = l
-	if m.Source != nil {
-		l = m.Source.Size()
-		n += 1 + l + sovOps(uint64(l))
+
+func (m *Mount) GetContentCache() bool {
+	if m != nil {
+		return m.ContentCache
 	}
-	return n
+	return false
 }
-func (m *Op_Copy) Size() (n int) {
-	var l int
-	_ = l
-	if m.Copy != nil {
-		l = m.Copy.Size()
-		n += 1 + l + sovOps(uint64(l))
+
+func (m *Mount) GetMountType() MountType {
+	if m != nil {
+		return m.MountType
 	}
-	return n
+	return MountType_BIND
 }
-func (m *Op_Build) Size() (n int) {
-	var l int
-	_ = l
-	if m.Build != nil {
-		l = m.Build.Size()
-		n += 1 + l + sovOps(uint64(l))
+
+func (m *Mount) GetContentSelectors() []*Selector {
+	if m != nil {
+		return m.ContentSelectors
 	}
-	return n
+	return nil
 }
-func (m *Input) Size() (n int) {
-	var l int
-	_ = l
-	l = len(m.Digest)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
+
+func (m *Mount) GetCacheOpt() *CacheOpt {
+	if m != nil {
+		return m.CacheOpt
 	}
-	if m.Index != 0 {
-		n += 1 + sovOps(uint64(m.Index))
+	return nil
+}
+
+func (m *Mount) GetTmpfsOpt() *TmpfsOpt {
+	if m != nil {
+		return m.TmpfsOpt
 	}
-	return n
+	return nil
 }
 
-func (m *ExecOp) Size() (n int) {
-	var l int
-	_ = l
-	if m.Meta != nil {
-		l = m.Meta.Size()
-		n += 1 + l + sovOps(uint64(l))
+func (m *Mount) GetSecretOpt() *SecretOpt {
+	if m != nil {
+		return m.SecretOpt
 	}
-	if len(m.Mounts) > 0 {
-		for _, e := range m.Mounts {
-			l = e.Size()
-			n += 1 + l + sovOps(uint64(l))
-		}
+	return nil
+}
+
+func (m *Mount) GetSSHOpt() *SSHOpt {
+	if m != nil {
+		return m.SSHOpt
 	}
-	return n
+	return nil
 }
 
-func (m *Meta) Size() (n int) {
-	var l int
-	_ = l
-	if len(m.Args) > 0 {
-		for _, s := range m.Args {
-			l = len(s)
-			n += 1 + l + sovOps(uint64(l))
-		}
+func (m *Mount) GetHostOpt() *HostOpt {
+	if m != nil {
+		return m.HostOpt
 	}
-	if len(m.Env) > 0 {
-		for _, s := range m.Env {
-			l = len(s)
-			n += 1 + l + sovOps(uint64(l))
-		}
+	return nil
+}
+
+// Selector is a path that is part of a content based cache key
+type Selector struct {
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// wildcard matches the path as a pattern, "**" matches any number of
+	// directories
+	Wildcard bool `protobuf:"varint,2,opt,name=wildcard,proto3" json:"wildcard,omitempty"`
+	// followLinks uses the targets of the selected symlinks
+	FollowLinks bool `protobuf:"varint,3,opt,name=followLinks,proto3" json:"followLinks,omitempty"`
+}
+
+func (m *Selector) Reset()                    { *m = Selector{} }
+func (m *Selector) String() string            { return proto.CompactTextString(m) }
+func (*Selector) ProtoMessage()               {}
+func (*Selector) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{13} }
+
+func (m *Selector) GetPath() string {
+	if m != nil {
+		return m.Path
 	}
-	l = len(m.Cwd)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
+	return ""
+}
+
+func (m *Selector) GetWildcard() bool {
+	if m != nil {
+		return m.Wildcard
 	}
-	return n
+	return false
 }
 
-func (m *Mount) Size() (n int) {
-	var l int
-	_ = l
-	if m.Input != 0 {
-		n += 1 + sovOps(uint64(m.Input))
+func (m *Selector) GetFollowLinks() bool {
+	if m != nil {
+		return m.FollowLinks
 	}
-	l = len(m.Selector)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
+	return false
+}
+
+// TmpfsOpt defines options for a tmpfs mount
+type TmpfsOpt struct {
+	// size limit of the mount in bytes, zero for the default size
+	Size_ int64 `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+}
+
+func (m *TmpfsOpt) Reset()                    { *m = TmpfsOpt{} }
+func (m *TmpfsOpt) String() string            { return proto.CompactTextString(m) }
+func (*TmpfsOpt) ProtoMessage()               {}
+func (*TmpfsOpt) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{14} }
+
+func (m *TmpfsOpt) GetSize_() int64 {
+	if m != nil {
+		return m.Size_
 	}
-	l = len(m.Dest)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
+	return 0
+}
+
+// SecretOpt defines options for a secret mount. The secret is loaded from the
+// client session and is never part of the cache key.
+type SecretOpt struct {
+	// ID of the secret in the client session
+	ID string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	// uid and gid of the secret file
+	Uid uint32 `protobuf:"varint,2,opt,name=uid,proto3" json:"uid,omitempty"`
+	Gid uint32 `protobuf:"varint,3,opt,name=gid,proto3" json:"gid,omitempty"`
+	// permission bits of the secret file
+	Mode uint32 `protobuf:"varint,4,opt,name=mode,proto3" json:"mode,omitempty"`
+	// optional secrets don't fail the exec if they are missing
+	Optional bool `protobuf:"varint,5,opt,name=optional,proto3" json:"optional,omitempty"`
+}
+
+func (m *SecretOpt) Reset()                    { *m = SecretOpt{} }
+func (m *SecretOpt) String() string            { return proto.CompactTextString(m) }
+func (*SecretOpt) ProtoMessage()               {}
+func (*SecretOpt) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{15} }
+
+func (m *SecretOpt) GetID() string {
+	if m != nil {
+		return m.ID
 	}
-	if m.Output != 0 {
-		n += 1 + sovOps(uint64(m.Output))
+	return ""
+}
+
+func (m *SecretOpt) GetUid() uint32 {
+	if m != nil {
+		return m.Uid
 	}
-	if m.Readonly {
-		n += 2
+	return 0
+}
+
+func (m *SecretOpt) GetGid() uint32 {
+	if m != nil {
+		return m.Gid
 	}
-	return n
+	return 0
 }
 
-func (m *CopyOp) Size() (n int) {
-	var l int
-	_ = l
-	if len(m.Src) > 0 {
-		for _, e := range m.Src {
-			l = e.Size()
-			n += 1 + l + sovOps(uint64(l))
-		}
+func (m *SecretOpt) GetMode() uint32 {
+	if m != nil {
+		return m.Mode
 	}
-	l = len(m.Dest)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
+	return 0
+}
+
+func (m *SecretOpt) GetOptional() bool {
+	if m != nil {
+		return m.Optional
 	}
-	return n
+	return false
 }
 
-func (m *CopySource) Size() (n int) {
-	var l int
-	_ = l
-	if m.Input != 0 {
-		n += 1 + sovOps(uint64(m.Input))
+// HostOpt defines options for a read-only bind mount of a host path. The
+// daemon only allows the paths of its allowlist. The path is not part of the
+// cache key, the version is.
+type HostOpt struct {
+	// path of the directory or the file on the host
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// version of the content of the path, eg. the date of a package mirror,
+	// that replaces the path in the cache key
+	Version string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (m *HostOpt) Reset()                    { *m = HostOpt{} }
+func (m *HostOpt) String() string            { return proto.CompactTextString(m) }
+func (*HostOpt) ProtoMessage()               {}
+func (*HostOpt) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{16} }
+
+func (m *HostOpt) GetPath() string {
+	if m != nil {
+		return m.Path
 	}
-	l = len(m.Selector)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
+	return ""
+}
+
+func (m *HostOpt) GetVersion() string {
+	if m != nil {
+		return m.Version
 	}
-	return n
+	return ""
 }
 
-func (m *SourceOp) Size() (n int) {
-	var l int
-	_ = l
-	l = len(m.Identifier)
-	if l > 0 {
-		n += 1 + l + sovOps(uint64(l))
+// SSHOpt defines options for a forwarded ssh agent socket mount
+type SSHOpt struct {
+	// ID of the agent in the client session
+	ID string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	// uid and gid of the socket
+	Uid uint32 `protobuf:"varint,2,opt,name=uid,proto3" json:"uid,omitempty"`
+	Gid uint32 `protobuf:"varint,3,opt,name=gid,proto3" json:"gid,omitempty"`
+	// permission bits of the socket
+	Mode uint32 `protobuf:"varint,4,opt,name=mode,proto3" json:"mode,omitempty"`
+	// optional agents don't fail the exec if they are missing
+	Optional bool `protobuf:"varint,5,opt,name=optional,proto3" json:"optional,omitempty"`
+}
+
+func (m *SSHOpt) Reset()                    { *m = SSHOpt{} }
+func (m *SSHOpt) String() string            { return proto.CompactTextString(m) }
+func (*SSHOpt) ProtoMessage()               {}
+func (*SSHOpt) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{17} }
+
+func (m *SSHOpt) GetID() string {
+	if m != nil {
+		return m.ID
 	}
-	if len(m.Attrs) > 0 {
-		for k, v := range m.Attrs {
-			_ = k
-			_ = v
-			mapEntrySize := 1 + len(k) + sovOps(uint64(len(k))) + 1 + len(v) + sovOps(uint64(len(v)))
-			n += mapEntrySize + 1 + sovOps(uint64(mapEntrySize))
-		}
+	return ""
+}
+
+func (m *SSHOpt) GetUid() uint32 {
+	if m != nil {
+		return m.Uid
 	}
-	return n
+	return 0
 }
 
-func (m *BuildOp) Size() (n int) {
-	var l int
-	_ = l
-	if m.Builder != 0 {
-		n += 1 + sovOps(uint64(m.Builder))
+func (m *SSHOpt) GetGid() uint32 {
+	if m != nil {
+		return m.Gid
 	}
-	if len(m.Inputs) > 0 {
-		for k, v := range m.Inputs {
-			_ = k
-			_ = v
-			l = 0
-			if v != nil {
-				l = v.Size()
+	return 0
+}
+
+func (m *SSHOpt) GetMode() uint32 {
+	if m != nil {
+		return m.Mode
+	}
+	return 0
+}
+
+func (m *SSHOpt) GetOptional() bool {
+	if m != nil {
+		return m.Optional
+	}
+	return false
+}
+
+// CacheOpt defines options for a persistent cache mount
+type CacheOpt struct {
+	// ID identifies the cache directory. Mounts with the same ID share data.
+	ID      string          `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Sharing CacheSharingOpt `protobuf:"varint,2,opt,name=sharing,proto3,enum=pb.CacheSharingOpt" json:"sharing,omitempty"`
+}
+
+func (m *CacheOpt) Reset()                    { *m = CacheOpt{} }
+func (m *CacheOpt) String() string            { return proto.CompactTextString(m) }
+func (*CacheOpt) ProtoMessage()               {}
+func (*CacheOpt) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{18} }
+
+func (m *CacheOpt) GetID() string {
+	if m != nil {
+		return m.ID
+	}
+	return ""
+}
+
+func (m *CacheOpt) GetSharing() CacheSharingOpt {
+	if m != nil {
+		return m.Sharing
+	}
+	return CacheSharingOpt_SHARED
+}
+
+type CopyOp struct {
+	Src  []*CopySource `protobuf:"bytes,1,rep,name=src" json:"src,omitempty"`
+	Dest string        `protobuf:"bytes,2,opt,name=dest,proto3" json:"dest,omitempty"`
+}
+
+func (m *CopyOp) Reset()                    { *m = CopyOp{} }
+func (m *CopyOp) String() string            { return proto.CompactTextString(m) }
+func (*CopyOp) ProtoMessage()               {}
+func (*CopyOp) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{19} }
+
+func (m *CopyOp) GetSrc() []*CopySource {
+	if m != nil {
+		return m.Src
+	}
+	return nil
+}
+
+func (m *CopyOp) GetDest() string {
+	if m != nil {
+		return m.Dest
+	}
+	return ""
+}
+
+type CopySource struct {
+	Input    InputIndex `protobuf:"varint,1,opt,name=input,proto3,customtype=InputIndex" json:"input"`
+	Selector string     `protobuf:"bytes,2,opt,name=selector,proto3" json:"selector,omitempty"`
+}
+
+func (m *CopySource) Reset()                    { *m = CopySource{} }
+func (m *CopySource) String() string            { return proto.CompactTextString(m) }
+func (*CopySource) ProtoMessage()               {}
+func (*CopySource) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{20} }
+
+func (m *CopySource) GetSelector() string {
+	if m != nil {
+		return m.Selector
+	}
+	return ""
+}
+
+// FileOp runs file actions on its inputs without a container. The actions
+// run in order, each on a copy of its input.
+type FileOp struct {
+	Actions []*FileAction `protobuf:"bytes,1,rep,name=actions" json:"actions,omitempty"`
+}
+
+func (m *FileOp) Reset()                    { *m = FileOp{} }
+func (m *FileOp) String() string            { return proto.CompactTextString(m) }
+func (*FileOp) ProtoMessage()               {}
+func (*FileOp) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{21} }
+
+func (m *FileOp) GetActions() []*FileAction {
+	if m != nil {
+		return m.Actions
+	}
+	return nil
+}
+
+type FileAction struct {
+	// input is the state the action runs on, or -1 for an empty state. Indexes
+	// starting from the number of op inputs refer to the results of the
+	// previous actions.
+	Input InputIndex `protobuf:"varint,1,opt,name=input,proto3,customtype=InputIndex" json:"input"`
+	// secondaryInput is the source of a copy, indexed like input
+	SecondaryInput InputIndex `protobuf:"varint,2,opt,name=secondaryInput,proto3,customtype=InputIndex" json:"secondaryInput"`
+	// output of the op for the result of the action, or -1 if the result is
+	// only used by the following actions
+	Output OutputIndex `protobuf:"varint,3,opt,name=output,proto3,customtype=OutputIndex" json:"output"`
+	// Types that are valid to be assigned to Action:
+	//	*FileAction_Copy
+	//	*FileAction_Mkfile
+	//	*FileAction_Mkdir
+	//	*FileAction_Rm
+	//	*FileAction_Symlink
+	Action isFileAction_Action `protobuf_oneof:"action"`
+}
+
+func (m *FileAction) Reset()                    { *m = FileAction{} }
+func (m *FileAction) String() string            { return proto.CompactTextString(m) }
+func (*FileAction) ProtoMessage()               {}
+func (*FileAction) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{22} }
+
+type isFileAction_Action interface {
+	isFileAction_Action()
+	MarshalTo([]byte) (int, error)
+	Size() int
+}
+
+type FileAction_Copy struct {
+	Copy *FileActionCopy `protobuf:"bytes,4,opt,name=copy,oneof"`
+}
+type FileAction_Mkfile struct {
+	Mkfile *FileActionMkFile `protobuf:"bytes,5,opt,name=mkfile,oneof"`
+}
+type FileAction_Mkdir struct {
+	Mkdir *FileActionMkDir `protobuf:"bytes,6,opt,name=mkdir,oneof"`
+}
+type FileAction_Rm struct {
+	Rm *FileActionRm `protobuf:"bytes,7,opt,name=rm,oneof"`
+}
+type FileAction_Symlink struct {
+	Symlink *FileActionSymlink `protobuf:"bytes,8,opt,name=symlink,oneof"`
+}
+
+func (*FileAction_Copy) isFileAction_Action()    {}
+func (*FileAction_Mkfile) isFileAction_Action()  {}
+func (*FileAction_Mkdir) isFileAction_Action()   {}
+func (*FileAction_Rm) isFileAction_Action()      {}
+func (*FileAction_Symlink) isFileAction_Action() {}
+
+func (m *FileAction) GetAction() isFileAction_Action {
+	if m != nil {
+		return m.Action
+	}
+	return nil
+}
+
+func (m *FileAction) GetCopy() *FileActionCopy {
+	if x, ok := m.GetAction().(*FileAction_Copy); ok {
+		return x.Copy
+	}
+	return nil
+}
+
+func (m *FileAction) GetMkfile() *FileActionMkFile {
+	if x, ok := m.GetAction().(*FileAction_Mkfile); ok {
+		return x.Mkfile
+	}
+	return nil
+}
+
+func (m *FileAction) GetMkdir() *FileActionMkDir {
+	if x, ok := m.GetAction().(*FileAction_Mkdir); ok {
+		return x.Mkdir
+	}
+	return nil
+}
+
+func (m *FileAction) GetRm() *FileActionRm {
+	if x, ok := m.GetAction().(*FileAction_Rm); ok {
+		return x.Rm
+	}
+	return nil
+}
+
+func (m *FileAction) GetSymlink() *FileActionSymlink {
+	if x, ok := m.GetAction().(*FileAction_Symlink); ok {
+		return x.Symlink
+	}
+	return nil
+}
+
+// XXX_OneofFuncs is for the internal use of the proto package.
+func (*FileAction) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
+	return _FileAction_OneofMarshaler, _FileAction_OneofUnmarshaler, _FileAction_OneofSizer, []interface{}{
+		(*FileAction_Copy)(nil),
+		(*FileAction_Mkfile)(nil),
+		(*FileAction_Mkdir)(nil),
+		(*FileAction_Rm)(nil),
+		(*FileAction_Symlink)(nil),
+	}
+}
+
+func _FileAction_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
+	m := msg.(*FileAction)
+	// action
+	switch x := m.Action.(type) {
+	case *FileAction_Copy:
+		_ = b.EncodeVarint(4<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Copy); err != nil {
+			return err
+		}
+	case *FileAction_Mkfile:
+		_ = b.EncodeVarint(5<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Mkfile); err != nil {
+			return err
+		}
+	case *FileAction_Mkdir:
+		_ = b.EncodeVarint(6<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Mkdir); err != nil {
+			return err
+		}
+	case *FileAction_Rm:
+		_ = b.EncodeVarint(7<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Rm); err != nil {
+			return err
+		}
+	case *FileAction_Symlink:
+		_ = b.EncodeVarint(8<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Symlink); err != nil {
+			return err
+		}
+	case nil:
+	default:
+		return fmt.Errorf("FileAction.Action has unexpected type %T", x)
+	}
+	return nil
+}
+
+func _FileAction_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
+	m := msg.(*FileAction)
+	switch tag {
+	case 4: // action.copy
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(FileActionCopy)
+		err := b.DecodeMessage(msg)
+		m.Action = &FileAction_Copy{msg}
+		return true, err
+	case 5: // action.mkfile
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(FileActionMkFile)
+		err := b.DecodeMessage(msg)
+		m.Action = &FileAction_Mkfile{msg}
+		return true, err
+	case 6: // action.mkdir
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(FileActionMkDir)
+		err := b.DecodeMessage(msg)
+		m.Action = &FileAction_Mkdir{msg}
+		return true, err
+	case 7: // action.rm
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(FileActionRm)
+		err := b.DecodeMessage(msg)
+		m.Action = &FileAction_Rm{msg}
+		return true, err
+	case 8: // action.symlink
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(FileActionSymlink)
+		err := b.DecodeMessage(msg)
+		m.Action = &FileAction_Symlink{msg}
+		return true, err
+	default:
+		return false, nil
+	}
+}
+
+func _FileAction_OneofSizer(msg proto.Message) (n int) {
+	m := msg.(*FileAction)
+	// action
+	switch x := m.Action.(type) {
+	case *FileAction_Copy:
+		s := proto.Size(x.Copy)
+		n += proto.SizeVarint(4<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *FileAction_Mkfile:
+		s := proto.Size(x.Mkfile)
+		n += proto.SizeVarint(5<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *FileAction_Mkdir:
+		s := proto.Size(x.Mkdir)
+		n += proto.SizeVarint(6<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *FileAction_Rm:
+		s := proto.Size(x.Rm)
+		n += proto.SizeVarint(7<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *FileAction_Symlink:
+		s := proto.Size(x.Symlink)
+		n += proto.SizeVarint(8<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case nil:
+	default:
+		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+	}
+	return n
+}
+
+type FileActionCopy struct {
+	// src is the path in the secondary input, dest the path in the input
+	Src  string `protobuf:"bytes,1,opt,name=src,proto3" json:"src,omitempty"`
+	Dest string `protobuf:"bytes,2,opt,name=dest,proto3" json:"dest,omitempty"`
+	// owner of the copied files, the owner of the source is kept if unset
+	Owner *ChownOpt `protobuf:"bytes,3,opt,name=owner" json:"owner,omitempty"`
+	// mode overrides the permission bits of the copied files, zero keeps them
+	Mode int32 `protobuf:"varint,4,opt,name=mode,proto3" json:"mode,omitempty"`
+	// followSymlink copies the target of src if it is a symlink
+	FollowSymlink bool `protobuf:"varint,5,opt,name=followSymlink,proto3" json:"followSymlink,omitempty"`
+	// dirCopyContents copies the contents of a src directory into dest
+	// instead of the directory itself
+	DirCopyContents bool `protobuf:"varint,6,opt,name=dirCopyContents,proto3" json:"dirCopyContents,omitempty"`
+	// createDestPath creates the missing parent directories of dest
+	CreateDestPath bool `protobuf:"varint,7,opt,name=createDestPath,proto3" json:"createDestPath,omitempty"`
+	// allowWildcard matches the last component of src as a pattern
+	AllowWildcard bool `protobuf:"varint,8,opt,name=allowWildcard,proto3" json:"allowWildcard,omitempty"`
+	// timestamp of the copied files in unix nanoseconds, zero keeps them
+	Timestamp int64 `protobuf:"varint,9,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// contentAttrs are the file attributes of src that are part of the cache
+	// key
+	ContentAttrs *ContentAttrs `protobuf:"bytes,10,opt,name=contentAttrs" json:"contentAttrs,omitempty"`
+}
+
+func (m *FileActionCopy) Reset()                    { *m = FileActionCopy{} }
+func (m *FileActionCopy) String() string            { return proto.CompactTextString(m) }
+func (*FileActionCopy) ProtoMessage()               {}
+func (*FileActionCopy) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{23} }
+
+func (m *FileActionCopy) GetSrc() string {
+	if m != nil {
+		return m.Src
+	}
+	return ""
+}
+
+func (m *FileActionCopy) GetDest() string {
+	if m != nil {
+		return m.Dest
+	}
+	return ""
+}
+
+func (m *FileActionCopy) GetOwner() *ChownOpt {
+	if m != nil {
+		return m.Owner
+	}
+	return nil
+}
+
+func (m *FileActionCopy) GetMode() int32 {
+	if m != nil {
+		return m.Mode
+	}
+	return 0
+}
+
+func (m *FileActionCopy) GetFollowSymlink() bool {
+	if m != nil {
+		return m.FollowSymlink
+	}
+	return false
+}
+
+func (m *FileActionCopy) GetDirCopyContents() bool {
+	if m != nil {
+		return m.DirCopyContents
+	}
+	return false
+}
+
+func (m *FileActionCopy) GetCreateDestPath() bool {
+	if m != nil {
+		return m.CreateDestPath
+	}
+	return false
+}
+
+func (m *FileActionCopy) GetAllowWildcard() bool {
+	if m != nil {
+		return m.AllowWildcard
+	}
+	return false
+}
+
+func (m *FileActionCopy) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *FileActionCopy) GetContentAttrs() *ContentAttrs {
+	if m != nil {
+		return m.ContentAttrs
+	}
+	return nil
+}
+
+type FileActionMkFile struct {
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// mode of the file, zero for 0644
+	Mode  int32     `protobuf:"varint,2,opt,name=mode,proto3" json:"mode,omitempty"`
+	Data  []byte    `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	Owner *ChownOpt `protobuf:"bytes,4,opt,name=owner" json:"owner,omitempty"`
+	// timestamp of the file in unix nanoseconds, zero for the current time
+	Timestamp int64 `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// makeParents creates the missing parent directories with the owner and
+	// the timestamp of the file
+	MakeParents bool `protobuf:"varint,6,opt,name=makeParents,proto3" json:"makeParents,omitempty"`
+}
+
+func (m *FileActionMkFile) Reset()                    { *m = FileActionMkFile{} }
+func (m *FileActionMkFile) String() string            { return proto.CompactTextString(m) }
+func (*FileActionMkFile) ProtoMessage()               {}
+func (*FileActionMkFile) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{24} }
+
+func (m *FileActionMkFile) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *FileActionMkFile) GetMode() int32 {
+	if m != nil {
+		return m.Mode
+	}
+	return 0
+}
+
+func (m *FileActionMkFile) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *FileActionMkFile) GetOwner() *ChownOpt {
+	if m != nil {
+		return m.Owner
+	}
+	return nil
+}
+
+func (m *FileActionMkFile) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *FileActionMkFile) GetMakeParents() bool {
+	if m != nil {
+		return m.MakeParents
+	}
+	return false
+}
+
+type FileActionMkDir struct {
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// mode of the directory, zero for 0755
+	Mode int32 `protobuf:"varint,2,opt,name=mode,proto3" json:"mode,omitempty"`
+	// makeParents creates the missing parents and doesn't fail if the
+	// directory exists
+	MakeParents bool      `protobuf:"varint,3,opt,name=makeParents,proto3" json:"makeParents,omitempty"`
+	Owner       *ChownOpt `protobuf:"bytes,4,opt,name=owner" json:"owner,omitempty"`
+	// timestamp of the directory in unix nanoseconds, zero for the current
+	// time
+	Timestamp int64 `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+}
+
+func (m *FileActionMkDir) Reset()                    { *m = FileActionMkDir{} }
+func (m *FileActionMkDir) String() string            { return proto.CompactTextString(m) }
+func (*FileActionMkDir) ProtoMessage()               {}
+func (*FileActionMkDir) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{25} }
+
+func (m *FileActionMkDir) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *FileActionMkDir) GetMode() int32 {
+	if m != nil {
+		return m.Mode
+	}
+	return 0
+}
+
+func (m *FileActionMkDir) GetMakeParents() bool {
+	if m != nil {
+		return m.MakeParents
+	}
+	return false
+}
+
+func (m *FileActionMkDir) GetOwner() *ChownOpt {
+	if m != nil {
+		return m.Owner
+	}
+	return nil
+}
+
+func (m *FileActionMkDir) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+type FileActionRm struct {
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// allowNotFound doesn't fail if the path doesn't exist
+	AllowNotFound bool `protobuf:"varint,2,opt,name=allowNotFound,proto3" json:"allowNotFound,omitempty"`
+	// allowWildcard matches the last component of path as a pattern
+	AllowWildcard bool `protobuf:"varint,3,opt,name=allowWildcard,proto3" json:"allowWildcard,omitempty"`
+}
+
+func (m *FileActionRm) Reset()                    { *m = FileActionRm{} }
+func (m *FileActionRm) String() string            { return proto.CompactTextString(m) }
+func (*FileActionRm) ProtoMessage()               {}
+func (*FileActionRm) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{26} }
+
+func (m *FileActionRm) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *FileActionRm) GetAllowNotFound() bool {
+	if m != nil {
+		return m.AllowNotFound
+	}
+	return false
+}
+
+func (m *FileActionRm) GetAllowWildcard() bool {
+	if m != nil {
+		return m.AllowWildcard
+	}
+	return false
+}
+
+type FileActionSymlink struct {
+	Oldpath string    `protobuf:"bytes,1,opt,name=oldpath,proto3" json:"oldpath,omitempty"`
+	Newpath string    `protobuf:"bytes,2,opt,name=newpath,proto3" json:"newpath,omitempty"`
+	Owner   *ChownOpt `protobuf:"bytes,3,opt,name=owner" json:"owner,omitempty"`
+	// timestamp of the link in unix nanoseconds, zero for the current time
+	Timestamp int64 `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+}
+
+func (m *FileActionSymlink) Reset()                    { *m = FileActionSymlink{} }
+func (m *FileActionSymlink) String() string            { return proto.CompactTextString(m) }
+func (*FileActionSymlink) ProtoMessage()               {}
+func (*FileActionSymlink) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{27} }
+
+func (m *FileActionSymlink) GetOldpath() string {
+	if m != nil {
+		return m.Oldpath
+	}
+	return ""
+}
+
+func (m *FileActionSymlink) GetNewpath() string {
+	if m != nil {
+		return m.Newpath
+	}
+	return ""
+}
+
+func (m *FileActionSymlink) GetOwner() *ChownOpt {
+	if m != nil {
+		return m.Owner
+	}
+	return nil
+}
+
+func (m *FileActionSymlink) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+// ChownOpt sets the numeric owner of the created files
+type ChownOpt struct {
+	Uid uint32 `protobuf:"varint,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	Gid uint32 `protobuf:"varint,2,opt,name=gid,proto3" json:"gid,omitempty"`
+}
+
+func (m *ChownOpt) Reset()                    { *m = ChownOpt{} }
+func (m *ChownOpt) String() string            { return proto.CompactTextString(m) }
+func (*ChownOpt) ProtoMessage()               {}
+func (*ChownOpt) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{28} }
+
+func (m *ChownOpt) GetUid() uint32 {
+	if m != nil {
+		return m.Uid
+	}
+	return 0
+}
+
+func (m *ChownOpt) GetGid() uint32 {
+	if m != nil {
+		return m.Gid
+	}
+	return 0
+}
+
+// MergeOp layers its inputs on top of each other into a single state. The
+// files of the later inputs replace the files of the earlier ones.
+type MergeOp struct {
+	Inputs []*MergeInput `protobuf:"bytes,1,rep,name=inputs" json:"inputs,omitempty"`
+}
+
+func (m *MergeOp) Reset()                    { *m = MergeOp{} }
+func (m *MergeOp) String() string            { return proto.CompactTextString(m) }
+func (*MergeOp) ProtoMessage()               {}
+func (*MergeOp) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{29} }
+
+func (m *MergeOp) GetInputs() []*MergeInput {
+	if m != nil {
+		return m.Inputs
+	}
+	return nil
+}
+
+type MergeInput struct {
+	Input InputIndex `protobuf:"varint,1,opt,name=input,proto3,customtype=InputIndex" json:"input"`
+}
+
+func (m *MergeInput) Reset()                    { *m = MergeInput{} }
+func (m *MergeInput) String() string            { return proto.CompactTextString(m) }
+func (*MergeInput) ProtoMessage()               {}
+func (*MergeInput) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{30} }
+
+// DiffOp creates a state with the files that were added or changed in upper
+// compared to lower. An empty lower input compares upper to an empty state.
+type DiffOp struct {
+	Lower InputIndex `protobuf:"varint,1,opt,name=lower,proto3,customtype=InputIndex" json:"lower"`
+	Upper InputIndex `protobuf:"varint,2,opt,name=upper,proto3,customtype=InputIndex" json:"upper"`
+}
+
+func (m *DiffOp) Reset()                    { *m = DiffOp{} }
+func (m *DiffOp) String() string            { return proto.CompactTextString(m) }
+func (*DiffOp) ProtoMessage()               {}
+func (*DiffOp) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{31} }
+
+type SourceOp struct {
+	// source type?
+	Identifier string            `protobuf:"bytes,1,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	Attrs      map[string]string `protobuf:"bytes,2,rep,name=attrs" json:"attrs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (m *SourceOp) Reset()                    { *m = SourceOp{} }
+func (m *SourceOp) String() string            { return proto.CompactTextString(m) }
+func (*SourceOp) ProtoMessage()               {}
+func (*SourceOp) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{32} }
+
+func (m *SourceOp) GetIdentifier() string {
+	if m != nil {
+		return m.Identifier
+	}
+	return ""
+}
+
+func (m *SourceOp) GetAttrs() map[string]string {
+	if m != nil {
+		return m.Attrs
+	}
+	return nil
+}
+
+type BuildOp struct {
+	Builder InputIndex             `protobuf:"varint,1,opt,name=builder,proto3,customtype=InputIndex" json:"builder"`
+	Inputs  map[string]*BuildInput `protobuf:"bytes,2,rep,name=inputs" json:"inputs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value"`
+	Def     [][]byte               `protobuf:"bytes,3,rep,name=def" json:"def,omitempty"`
+	Attrs   map[string]string      `protobuf:"bytes,4,rep,name=attrs" json:"attrs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (m *BuildOp) Reset()                    { *m = BuildOp{} }
+func (m *BuildOp) String() string            { return proto.CompactTextString(m) }
+func (*BuildOp) ProtoMessage()               {}
+func (*BuildOp) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{33} }
+
+func (m *BuildOp) GetInputs() map[string]*BuildInput {
+	if m != nil {
+		return m.Inputs
+	}
+	return nil
+}
+
+func (m *BuildOp) GetDef() [][]byte {
+	if m != nil {
+		return m.Def
+	}
+	return nil
+}
+
+func (m *BuildOp) GetAttrs() map[string]string {
+	if m != nil {
+		return m.Attrs
+	}
+	return nil
+}
+
+type BuildInput struct {
+	Input InputIndex `protobuf:"varint,1,opt,name=input,proto3,customtype=InputIndex" json:"input"`
+}
+
+func (m *BuildInput) Reset()                    { *m = BuildInput{} }
+func (m *BuildInput) String() string            { return proto.CompactTextString(m) }
+func (*BuildInput) ProtoMessage()               {}
+func (*BuildInput) Descriptor() ([]byte, []int) { return fileDescriptorOps, []int{34} }
+
+func init() {
+	proto.RegisterType((*Op)(nil), "pb.Op")
+	proto.RegisterType((*WorkerConstraints)(nil), "pb.WorkerConstraints")
+	proto.RegisterType((*Platform)(nil), "pb.Platform")
+	proto.RegisterType((*Input)(nil), "pb.Input")
+	proto.RegisterType((*ExecOp)(nil), "pb.ExecOp")
+	proto.RegisterType((*SecurityProfile)(nil), "pb.SecurityProfile")
+	proto.RegisterType((*ContentAttrs)(nil), "pb.ContentAttrs")
+	proto.RegisterType((*RetryPolicy)(nil), "pb.RetryPolicy")
+	proto.RegisterType((*Resources)(nil), "pb.Resources")
+	proto.RegisterType((*Ulimit)(nil), "pb.Ulimit")
+	proto.RegisterType((*Meta)(nil), "pb.Meta")
+	proto.RegisterType((*HostIP)(nil), "pb.HostIP")
+	proto.RegisterType((*Mount)(nil), "pb.Mount")
+	proto.RegisterType((*Selector)(nil), "pb.Selector")
+	proto.RegisterType((*TmpfsOpt)(nil), "pb.TmpfsOpt")
+	proto.RegisterType((*SecretOpt)(nil), "pb.SecretOpt")
+	proto.RegisterType((*HostOpt)(nil), "pb.HostOpt")
+	proto.RegisterType((*SSHOpt)(nil), "pb.SSHOpt")
+	proto.RegisterType((*CacheOpt)(nil), "pb.CacheOpt")
+	proto.RegisterType((*CopyOp)(nil), "pb.CopyOp")
+	proto.RegisterType((*CopySource)(nil), "pb.CopySource")
+	proto.RegisterType((*FileOp)(nil), "pb.FileOp")
+	proto.RegisterType((*FileAction)(nil), "pb.FileAction")
+	proto.RegisterType((*FileActionCopy)(nil), "pb.FileActionCopy")
+	proto.RegisterType((*FileActionMkFile)(nil), "pb.FileActionMkFile")
+	proto.RegisterType((*FileActionMkDir)(nil), "pb.FileActionMkDir")
+	proto.RegisterType((*FileActionRm)(nil), "pb.FileActionRm")
+	proto.RegisterType((*FileActionSymlink)(nil), "pb.FileActionSymlink")
+	proto.RegisterType((*ChownOpt)(nil), "pb.ChownOpt")
+	proto.RegisterType((*MergeOp)(nil), "pb.MergeOp")
+	proto.RegisterType((*MergeInput)(nil), "pb.MergeInput")
+	proto.RegisterType((*DiffOp)(nil), "pb.DiffOp")
+	proto.RegisterType((*SourceOp)(nil), "pb.SourceOp")
+	proto.RegisterType((*BuildOp)(nil), "pb.BuildOp")
+	proto.RegisterType((*BuildInput)(nil), "pb.BuildInput")
+	proto.RegisterEnum("pb.NetMode", NetMode_name, NetMode_value)
+	proto.RegisterEnum("pb.SecurityMode", SecurityMode_name, SecurityMode_value)
+	proto.RegisterEnum("pb.MountType", MountType_name, MountType_value)
+	proto.RegisterEnum("pb.CacheSharingOpt", CacheSharingOpt_name, CacheSharingOpt_value)
+}
+func (m *Op) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Op) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Inputs) > 0 {
+		for _, msg := range m.Inputs {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintOps(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.Op != nil {
+		nn1, err := m.Op.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += nn1
+	}
+	if m.Platform != nil {
+		dAtA[i] = 0x4a
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Platform.Size()))
+		n2, err := m.Platform.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n2
+	}
+	if m.Constraints != nil {
+		dAtA[i] = 0x52
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Constraints.Size()))
+		n3, err := m.Constraints.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n3
+	}
+	return i, nil
+}
+
+func (m *Op_Exec) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.Exec != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Exec.Size()))
+		n4, err := m.Exec.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n4
+	}
+	return i, nil
+}
+func (m *Op_Source) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.Source != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Source.Size()))
+		n5, err := m.Source.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n5
+	}
+	return i, nil
+}
+func (m *Op_Copy) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.Copy != nil {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Copy.Size()))
+		n6, err := m.Copy.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n6
+	}
+	return i, nil
+}
+func (m *Op_Build) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.Build != nil {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Build.Size()))
+		n7, err := m.Build.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n7
+	}
+	return i, nil
+}
+func (m *Op_File) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.File != nil {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.File.Size()))
+		n8, err := m.File.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n8
+	}
+	return i, nil
+}
+func (m *Op_Merge) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.Merge != nil {
+		dAtA[i] = 0x3a
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Merge.Size()))
+		n9, err := m.Merge.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n9
+	}
+	return i, nil
+}
+func (m *Op_Diff) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.Diff != nil {
+		dAtA[i] = 0x42
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Diff.Size()))
+		n10, err := m.Diff.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n10
+	}
+	return i, nil
+}
+func (m *WorkerConstraints) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *WorkerConstraints) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Filter) > 0 {
+		for _, s := range m.Filter {
+			dAtA[i] = 0xa
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	return i, nil
+}
+
+func (m *Platform) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Platform) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Architecture) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Architecture)))
+		i += copy(dAtA[i:], m.Architecture)
+	}
+	if len(m.OS) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(len(m.OS)))
+		i += copy(dAtA[i:], m.OS)
+	}
+	if len(m.Variant) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Variant)))
+		i += copy(dAtA[i:], m.Variant)
+	}
+	if len(m.OSVersion) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(len(m.OSVersion)))
+		i += copy(dAtA[i:], m.OSVersion)
+	}
+	if len(m.OSFeatures) > 0 {
+		for _, s := range m.OSFeatures {
+			dAtA[i] = 0x2a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	return i, nil
+}
+
+func (m *Input) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Input) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Digest) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Digest)))
+		i += copy(dAtA[i:], m.Digest)
+	}
+	if m.Index != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Index))
+	}
+	return i, nil
+}
+
+func (m *ExecOp) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ExecOp) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Meta != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Meta.Size()))
+		n11, err := m.Meta.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n11
+	}
+	if len(m.Mounts) > 0 {
+		for _, msg := range m.Mounts {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintOps(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.ContentCacheRoot {
+		dAtA[i] = 0x18
+		i++
+		if m.ContentCacheRoot {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.Resources != nil {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Resources.Size()))
+		n12, err := m.Resources.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n12
+	}
+	if m.Timeout != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Timeout))
+	}
+	if m.Retry != nil {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Retry.Size()))
+		n13, err := m.Retry.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n13
+	}
+	if m.ReadonlyRootfs {
+		dAtA[i] = 0x38
+		i++
+		if m.ReadonlyRootfs {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.NoProxyEnv {
+		dAtA[i] = 0x40
+		i++
+		if m.NoProxyEnv {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if len(m.ProxyEnv) > 0 {
+		for _, s := range m.ProxyEnv {
+			dAtA[i] = 0x4a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if m.ContentAttrs != nil {
+		dAtA[i] = 0x52
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.ContentAttrs.Size()))
+		n14, err := m.ContentAttrs.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n14
+	}
+	if m.CheckpointInterval != 0 {
+		dAtA[i] = 0x58
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.CheckpointInterval))
+	}
+	if m.Network != 0 {
+		dAtA[i] = 0x60
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Network))
+	}
+	if m.Security != 0 {
+		dAtA[i] = 0x68
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Security))
+	}
+	if len(m.Devices) > 0 {
+		for _, s := range m.Devices {
+			dAtA[i] = 0x72
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if m.SecurityProfile != nil {
+		dAtA[i] = 0x7a
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.SecurityProfile.Size()))
+		n15, err := m.SecurityProfile.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n15
+	}
+	return i, nil
+}
+
+func (m *SecurityProfile) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SecurityProfile) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Seccomp) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Seccomp)))
+		i += copy(dAtA[i:], m.Seccomp)
+	}
+	if len(m.Apparmor) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Apparmor)))
+		i += copy(dAtA[i:], m.Apparmor)
+	}
+	if len(m.SelinuxLabel) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(len(m.SelinuxLabel)))
+		i += copy(dAtA[i:], m.SelinuxLabel)
+	}
+	return i, nil
+}
+
+func (m *ContentAttrs) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ContentAttrs) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Ownership {
+		dAtA[i] = 0x8
+		i++
+		if m.Ownership {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.Xattrs {
+		dAtA[i] = 0x10
+		i++
+		if m.Xattrs {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.Capabilities {
+		dAtA[i] = 0x18
+		i++
+		if m.Capabilities {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.ModTime {
+		dAtA[i] = 0x20
+		i++
+		if m.ModTime {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
+func (m *RetryPolicy) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RetryPolicy) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.MaxRetries != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.MaxRetries))
+	}
+	if len(m.ExitCodes) > 0 {
+		dAtA17 := make([]byte, len(m.ExitCodes)*10)
+		var j16 int
+		for _, num1 := range m.ExitCodes {
+			num := uint64(num1)
+			for num >= 1<<7 {
+				dAtA17[j16] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j16++
+			}
+			dAtA17[j16] = uint8(num)
+			j16++
+		}
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(j16))
+		i += copy(dAtA[i:], dAtA17[:j16])
+	}
+	return i, nil
+}
+
+func (m *Resources) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Resources) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.CpuShares != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.CpuShares))
+	}
+	if m.CpuQuota != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.CpuQuota))
+	}
+	if m.CpuPeriod != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.CpuPeriod))
+	}
+	if m.Memory != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Memory))
+	}
+	if m.PidsLimit != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.PidsLimit))
+	}
+	if len(m.Ulimits) > 0 {
+		for _, msg := range m.Ulimits {
+			dAtA[i] = 0x32
+			i++
+			i = encodeVarintOps(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *Ulimit) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Ulimit) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Name) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Name)))
+		i += copy(dAtA[i:], m.Name)
+	}
+	if m.Soft != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Soft))
+	}
+	if m.Hard != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Hard))
+	}
+	return i, nil
+}
+
+func (m *Meta) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Meta) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Args) > 0 {
+		for _, s := range m.Args {
+			dAtA[i] = 0xa
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if len(m.Env) > 0 {
+		for _, s := range m.Env {
+			dAtA[i] = 0x12
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if len(m.Cwd) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Cwd)))
+		i += copy(dAtA[i:], m.Cwd)
+	}
+	if len(m.User) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(len(m.User)))
+		i += copy(dAtA[i:], m.User)
+	}
+	if len(m.Hostname) > 0 {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Hostname)))
+		i += copy(dAtA[i:], m.Hostname)
+	}
+	if len(m.ExtraHosts) > 0 {
+		for _, msg := range m.ExtraHosts {
+			dAtA[i] = 0x32
+			i++
+			i = encodeVarintOps(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *HostIP) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *HostIP) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Host) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Host)))
+		i += copy(dAtA[i:], m.Host)
+	}
+	if len(m.IP) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(len(m.IP)))
+		i += copy(dAtA[i:], m.IP)
+	}
+	return i, nil
+}
+
+func (m *Mount) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Mount) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Input != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Input))
+	}
+	if len(m.Selector) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Selector)))
+		i += copy(dAtA[i:], m.Selector)
+	}
+	if len(m.Dest) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Dest)))
+		i += copy(dAtA[i:], m.Dest)
+	}
+	if m.Output != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Output))
+	}
+	if m.Readonly {
+		dAtA[i] = 0x28
+		i++
+		if m.Readonly {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.ContentCache {
+		dAtA[i] = 0x30
+		i++
+		if m.ContentCache {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.MountType != 0 {
+		dAtA[i] = 0x38
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.MountType))
+	}
+	if len(m.ContentSelectors) > 0 {
+		for _, msg := range m.ContentSelectors {
+			dAtA[i] = 0x42
+			i++
+			i = encodeVarintOps(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.CacheOpt != nil {
+		dAtA[i] = 0xa2
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.CacheOpt.Size()))
+		n18, err := m.CacheOpt.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n18
+	}
+	if m.TmpfsOpt != nil {
+		dAtA[i] = 0xaa
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.TmpfsOpt.Size()))
+		n19, err := m.TmpfsOpt.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n19
+	}
+	if m.SecretOpt != nil {
+		dAtA[i] = 0xb2
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.SecretOpt.Size()))
+		n20, err := m.SecretOpt.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n20
+	}
+	if m.SSHOpt != nil {
+		dAtA[i] = 0xba
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.SSHOpt.Size()))
+		n21, err := m.SSHOpt.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n21
+	}
+	if m.HostOpt != nil {
+		dAtA[i] = 0xc2
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.HostOpt.Size()))
+		n22, err := m.HostOpt.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n22
+	}
+	return i, nil
+}
+
+func (m *Selector) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Selector) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Path) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Path)))
+		i += copy(dAtA[i:], m.Path)
+	}
+	if m.Wildcard {
+		dAtA[i] = 0x10
+		i++
+		if m.Wildcard {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.FollowLinks {
+		dAtA[i] = 0x18
+		i++
+		if m.FollowLinks {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
+func (m *TmpfsOpt) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TmpfsOpt) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Size_ != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Size_))
+	}
+	return i, nil
+}
+
+func (m *SecretOpt) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SecretOpt) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.ID) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(len(m.ID)))
+		i += copy(dAtA[i:], m.ID)
+	}
+	if m.Uid != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Uid))
+	}
+	if m.Gid != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Gid))
+	}
+	if m.Mode != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Mode))
+	}
+	if m.Optional {
+		dAtA[i] = 0x28
+		i++
+		if m.Optional {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
+func (m *HostOpt) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *HostOpt) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Path) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Path)))
+		i += copy(dAtA[i:], m.Path)
+	}
+	if len(m.Version) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Version)))
+		i += copy(dAtA[i:], m.Version)
+	}
+	return i, nil
+}
+
+func (m *SSHOpt) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SSHOpt) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.ID) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(len(m.ID)))
+		i += copy(dAtA[i:], m.ID)
+	}
+	if m.Uid != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Uid))
+	}
+	if m.Gid != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Gid))
+	}
+	if m.Mode != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Mode))
+	}
+	if m.Optional {
+		dAtA[i] = 0x28
+		i++
+		if m.Optional {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
+func (m *CacheOpt) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CacheOpt) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.ID) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(len(m.ID)))
+		i += copy(dAtA[i:], m.ID)
+	}
+	if m.Sharing != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Sharing))
+	}
+	return i, nil
+}
+
+func (m *CopyOp) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CopyOp) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Src) > 0 {
+		for _, msg := range m.Src {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintOps(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.Dest) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Dest)))
+		i += copy(dAtA[i:], m.Dest)
+	}
+	return i, nil
+}
+
+func (m *CopySource) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CopySource) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Input != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Input))
+	}
+	if len(m.Selector) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Selector)))
+		i += copy(dAtA[i:], m.Selector)
+	}
+	return i, nil
+}
+
+func (m *FileOp) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FileOp) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Actions) > 0 {
+		for _, msg := range m.Actions {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintOps(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *FileAction) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FileAction) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Input != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Input))
+	}
+	if m.SecondaryInput != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.SecondaryInput))
+	}
+	if m.Output != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Output))
+	}
+	if m.Action != nil {
+		nn23, err := m.Action.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += nn23
+	}
+	return i, nil
+}
+
+func (m *FileAction_Copy) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.Copy != nil {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Copy.Size()))
+		n24, err := m.Copy.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n24
+	}
+	return i, nil
+}
+func (m *FileAction_Mkfile) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.Mkfile != nil {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Mkfile.Size()))
+		n25, err := m.Mkfile.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n25
+	}
+	return i, nil
+}
+func (m *FileAction_Mkdir) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.Mkdir != nil {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Mkdir.Size()))
+		n26, err := m.Mkdir.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n26
+	}
+	return i, nil
+}
+func (m *FileAction_Rm) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.Rm != nil {
+		dAtA[i] = 0x3a
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Rm.Size()))
+		n27, err := m.Rm.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n27
+	}
+	return i, nil
+}
+func (m *FileAction_Symlink) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.Symlink != nil {
+		dAtA[i] = 0x42
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Symlink.Size()))
+		n28, err := m.Symlink.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n28
+	}
+	return i, nil
+}
+func (m *FileActionCopy) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FileActionCopy) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Src) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Src)))
+		i += copy(dAtA[i:], m.Src)
+	}
+	if len(m.Dest) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Dest)))
+		i += copy(dAtA[i:], m.Dest)
+	}
+	if m.Owner != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Owner.Size()))
+		n29, err := m.Owner.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n29
+	}
+	if m.Mode != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Mode))
+	}
+	if m.FollowSymlink {
+		dAtA[i] = 0x28
+		i++
+		if m.FollowSymlink {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.DirCopyContents {
+		dAtA[i] = 0x30
+		i++
+		if m.DirCopyContents {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.CreateDestPath {
+		dAtA[i] = 0x38
+		i++
+		if m.CreateDestPath {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.AllowWildcard {
+		dAtA[i] = 0x40
+		i++
+		if m.AllowWildcard {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.Timestamp != 0 {
+		dAtA[i] = 0x48
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Timestamp))
+	}
+	if m.ContentAttrs != nil {
+		dAtA[i] = 0x52
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.ContentAttrs.Size()))
+		n30, err := m.ContentAttrs.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n30
+	}
+	return i, nil
+}
+
+func (m *FileActionMkFile) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FileActionMkFile) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Path) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Path)))
+		i += copy(dAtA[i:], m.Path)
+	}
+	if m.Mode != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Mode))
+	}
+	if len(m.Data) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Data)))
+		i += copy(dAtA[i:], m.Data)
+	}
+	if m.Owner != nil {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Owner.Size()))
+		n31, err := m.Owner.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n31
+	}
+	if m.Timestamp != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Timestamp))
+	}
+	if m.MakeParents {
+		dAtA[i] = 0x30
+		i++
+		if m.MakeParents {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
+func (m *FileActionMkDir) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FileActionMkDir) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Path) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Path)))
+		i += copy(dAtA[i:], m.Path)
+	}
+	if m.Mode != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Mode))
+	}
+	if m.MakeParents {
+		dAtA[i] = 0x18
+		i++
+		if m.MakeParents {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.Owner != nil {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Owner.Size()))
+		n32, err := m.Owner.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n32
+	}
+	if m.Timestamp != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Timestamp))
+	}
+	return i, nil
+}
+
+func (m *FileActionRm) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FileActionRm) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Path) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Path)))
+		i += copy(dAtA[i:], m.Path)
+	}
+	if m.AllowNotFound {
+		dAtA[i] = 0x10
+		i++
+		if m.AllowNotFound {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.AllowWildcard {
+		dAtA[i] = 0x18
+		i++
+		if m.AllowWildcard {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
+func (m *FileActionSymlink) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FileActionSymlink) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Oldpath) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Oldpath)))
+		i += copy(dAtA[i:], m.Oldpath)
+	}
+	if len(m.Newpath) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Newpath)))
+		i += copy(dAtA[i:], m.Newpath)
+	}
+	if m.Owner != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Owner.Size()))
+		n33, err := m.Owner.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n33
+	}
+	if m.Timestamp != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Timestamp))
+	}
+	return i, nil
+}
+
+func (m *ChownOpt) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ChownOpt) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Uid != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Uid))
+	}
+	if m.Gid != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Gid))
+	}
+	return i, nil
+}
+
+func (m *MergeOp) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MergeOp) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Inputs) > 0 {
+		for _, msg := range m.Inputs {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintOps(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *MergeInput) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MergeInput) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Input != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Input))
+	}
+	return i, nil
+}
+
+func (m *DiffOp) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DiffOp) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Lower != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Lower))
+	}
+	if m.Upper != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Upper))
+	}
+	return i, nil
+}
+
+func (m *SourceOp) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SourceOp) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Identifier) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(len(m.Identifier)))
+		i += copy(dAtA[i:], m.Identifier)
+	}
+	if len(m.Attrs) > 0 {
+		for k, _ := range m.Attrs {
+			dAtA[i] = 0x12
+			i++
+			v := m.Attrs[k]
+			mapSize := 1 + len(k) + sovOps(uint64(len(k))) + 1 + len(v) + sovOps(uint64(len(v)))
+			i = encodeVarintOps(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintOps(dAtA, i, uint64(len(k)))
+			i += copy(dAtA[i:], k)
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintOps(dAtA, i, uint64(len(v)))
+			i += copy(dAtA[i:], v)
+		}
+	}
+	return i, nil
+}
+
+func (m *BuildOp) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *BuildOp) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Builder != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Builder))
+	}
+	if len(m.Inputs) > 0 {
+		for k, _ := range m.Inputs {
+			dAtA[i] = 0x12
+			i++
+			v := m.Inputs[k]
+			msgSize := 0
+			if v != nil {
+				msgSize = v.Size()
+				msgSize += 1 + sovOps(uint64(msgSize))
+			}
+			mapSize := 1 + len(k) + sovOps(uint64(len(k))) + msgSize
+			i = encodeVarintOps(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintOps(dAtA, i, uint64(len(k)))
+			i += copy(dAtA[i:], k)
+			if v != nil {
+				dAtA[i] = 0x12
+				i++
+				i = encodeVarintOps(dAtA, i, uint64(v.Size()))
+				n34, err := v.MarshalTo(dAtA[i:])
+				if err != nil {
+					return 0, err
+				}
+				i += n34
+			}
+		}
+	}
+	if len(m.Def) > 0 {
+		for _, b := range m.Def {
+			dAtA[i] = 0x1a
+			i++
+			i = encodeVarintOps(dAtA, i, uint64(len(b)))
+			i += copy(dAtA[i:], b)
+		}
+	}
+	if len(m.Attrs) > 0 {
+		for k, _ := range m.Attrs {
+			dAtA[i] = 0x22
+			i++
+			v := m.Attrs[k]
+			mapSize := 1 + len(k) + sovOps(uint64(len(k))) + 1 + len(v) + sovOps(uint64(len(v)))
+			i = encodeVarintOps(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintOps(dAtA, i, uint64(len(k)))
+			i += copy(dAtA[i:], k)
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintOps(dAtA, i, uint64(len(v)))
+			i += copy(dAtA[i:], v)
+		}
+	}
+	return i, nil
+}
+
+func (m *BuildInput) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *BuildInput) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Input != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintOps(dAtA, i, uint64(m.Input))
+	}
+	return i, nil
+}
+
+func encodeFixed64Ops(dAtA []byte, offset int, v uint64) int {
+	dAtA[offset] = uint8(v)
+	dAtA[offset+1] = uint8(v >> 8)
+	dAtA[offset+2] = uint8(v >> 16)
+	dAtA[offset+3] = uint8(v >> 24)
+	dAtA[offset+4] = uint8(v >> 32)
+	dAtA[offset+5] = uint8(v >> 40)
+	dAtA[offset+6] = uint8(v >> 48)
+	dAtA[offset+7] = uint8(v >> 56)
+	return offset + 8
+}
+func encodeFixed32Ops(dAtA []byte, offset int, v uint32) int {
+	dAtA[offset] = uint8(v)
+	dAtA[offset+1] = uint8(v >> 8)
+	dAtA[offset+2] = uint8(v >> 16)
+	dAtA[offset+3] = uint8(v >> 24)
+	return offset + 4
+}
+func encodeVarintOps(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *Op) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Inputs) > 0 {
+		for _, e := range m.Inputs {
+			l = e.Size()
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	if m.Op != nil {
+		n += m.Op.Size()
+	}
+	if m.Platform != nil {
+		l = m.Platform.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Constraints != nil {
+		l = m.Constraints.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+
+func (m *Op_Exec) Size() (n int) {
+	var l int
+	_ = l
+	if m.Exec != nil {
+		l = m.Exec.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+func (m *Op_Source) Size() (n int) {
+	var l int
+	_ = l
+	if m.Source != nil {
+		l = m.Source.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+func (m *Op_Copy) Size() (n int) {
+	var l int
+	_ = l
+	if m.Copy != nil {
+		l = m.Copy.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+func (m *Op_Build) Size() (n int) {
+	var l int
+	_ = l
+	if m.Build != nil {
+		l = m.Build.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+func (m *Op_File) Size() (n int) {
+	var l int
+	_ = l
+	if m.File != nil {
+		l = m.File.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+func (m *Op_Merge) Size() (n int) {
+	var l int
+	_ = l
+	if m.Merge != nil {
+		l = m.Merge.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+func (m *Op_Diff) Size() (n int) {
+	var l int
+	_ = l
+	if m.Diff != nil {
+		l = m.Diff.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+func (m *WorkerConstraints) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Filter) > 0 {
+		for _, s := range m.Filter {
+			l = len(s)
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Platform) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Architecture)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	l = len(m.OS)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	l = len(m.Variant)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	l = len(m.OSVersion)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if len(m.OSFeatures) > 0 {
+		for _, s := range m.OSFeatures {
+			l = len(s)
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Input) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Digest)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Index != 0 {
+		n += 1 + sovOps(uint64(m.Index))
+	}
+	return n
+}
+
+func (m *ExecOp) Size() (n int) {
+	var l int
+	_ = l
+	if m.Meta != nil {
+		l = m.Meta.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if len(m.Mounts) > 0 {
+		for _, e := range m.Mounts {
+			l = e.Size()
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	if m.ContentCacheRoot {
+		n += 2
+	}
+	if m.Resources != nil {
+		l = m.Resources.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Timeout != 0 {
+		n += 1 + sovOps(uint64(m.Timeout))
+	}
+	if m.Retry != nil {
+		l = m.Retry.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.ReadonlyRootfs {
+		n += 2
+	}
+	if m.NoProxyEnv {
+		n += 2
+	}
+	if len(m.ProxyEnv) > 0 {
+		for _, s := range m.ProxyEnv {
+			l = len(s)
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	if m.ContentAttrs != nil {
+		l = m.ContentAttrs.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.CheckpointInterval != 0 {
+		n += 1 + sovOps(uint64(m.CheckpointInterval))
+	}
+	if m.Network != 0 {
+		n += 1 + sovOps(uint64(m.Network))
+	}
+	if m.Security != 0 {
+		n += 1 + sovOps(uint64(m.Security))
+	}
+	if len(m.Devices) > 0 {
+		for _, s := range m.Devices {
+			l = len(s)
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	if m.SecurityProfile != nil {
+		l = m.SecurityProfile.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+
+func (m *SecurityProfile) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Seccomp)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	l = len(m.Apparmor)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	l = len(m.SelinuxLabel)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+
+func (m *ContentAttrs) Size() (n int) {
+	var l int
+	_ = l
+	if m.Ownership {
+		n += 2
+	}
+	if m.Xattrs {
+		n += 2
+	}
+	if m.Capabilities {
+		n += 2
+	}
+	if m.ModTime {
+		n += 2
+	}
+	return n
+}
+
+func (m *RetryPolicy) Size() (n int) {
+	var l int
+	_ = l
+	if m.MaxRetries != 0 {
+		n += 1 + sovOps(uint64(m.MaxRetries))
+	}
+	if len(m.ExitCodes) > 0 {
+		l = 0
+		for _, e := range m.ExitCodes {
+			l += sovOps(uint64(e))
+		}
+		n += 1 + sovOps(uint64(l)) + l
+	}
+	return n
+}
+
+func (m *Resources) Size() (n int) {
+	var l int
+	_ = l
+	if m.CpuShares != 0 {
+		n += 1 + sovOps(uint64(m.CpuShares))
+	}
+	if m.CpuQuota != 0 {
+		n += 1 + sovOps(uint64(m.CpuQuota))
+	}
+	if m.CpuPeriod != 0 {
+		n += 1 + sovOps(uint64(m.CpuPeriod))
+	}
+	if m.Memory != 0 {
+		n += 1 + sovOps(uint64(m.Memory))
+	}
+	if m.PidsLimit != 0 {
+		n += 1 + sovOps(uint64(m.PidsLimit))
+	}
+	if len(m.Ulimits) > 0 {
+		for _, e := range m.Ulimits {
+			l = e.Size()
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Ulimit) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Soft != 0 {
+		n += 1 + sovOps(uint64(m.Soft))
+	}
+	if m.Hard != 0 {
+		n += 1 + sovOps(uint64(m.Hard))
+	}
+	return n
+}
+
+func (m *Meta) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Args) > 0 {
+		for _, s := range m.Args {
+			l = len(s)
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	if len(m.Env) > 0 {
+		for _, s := range m.Env {
+			l = len(s)
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	l = len(m.Cwd)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	l = len(m.User)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	l = len(m.Hostname)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if len(m.ExtraHosts) > 0 {
+		for _, e := range m.ExtraHosts {
+			l = e.Size()
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *HostIP) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Host)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	l = len(m.IP)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+
+func (m *Mount) Size() (n int) {
+	var l int
+	_ = l
+	if m.Input != 0 {
+		n += 1 + sovOps(uint64(m.Input))
+	}
+	l = len(m.Selector)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	l = len(m.Dest)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Output != 0 {
+		n += 1 + sovOps(uint64(m.Output))
+	}
+	if m.Readonly {
+		n += 2
+	}
+	if m.ContentCache {
+		n += 2
+	}
+	if m.MountType != 0 {
+		n += 1 + sovOps(uint64(m.MountType))
+	}
+	if len(m.ContentSelectors) > 0 {
+		for _, e := range m.ContentSelectors {
+			l = e.Size()
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	if m.CacheOpt != nil {
+		l = m.CacheOpt.Size()
+		n += 2 + l + sovOps(uint64(l))
+	}
+	if m.TmpfsOpt != nil {
+		l = m.TmpfsOpt.Size()
+		n += 2 + l + sovOps(uint64(l))
+	}
+	if m.SecretOpt != nil {
+		l = m.SecretOpt.Size()
+		n += 2 + l + sovOps(uint64(l))
+	}
+	if m.SSHOpt != nil {
+		l = m.SSHOpt.Size()
+		n += 2 + l + sovOps(uint64(l))
+	}
+	if m.HostOpt != nil {
+		l = m.HostOpt.Size()
+		n += 2 + l + sovOps(uint64(l))
+	}
+	return n
+}
+
+func (m *Selector) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Path)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Wildcard {
+		n += 2
+	}
+	if m.FollowLinks {
+		n += 2
+	}
+	return n
+}
+
+func (m *TmpfsOpt) Size() (n int) {
+	var l int
+	_ = l
+	if m.Size_ != 0 {
+		n += 1 + sovOps(uint64(m.Size_))
+	}
+	return n
+}
+
+func (m *SecretOpt) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.ID)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Uid != 0 {
+		n += 1 + sovOps(uint64(m.Uid))
+	}
+	if m.Gid != 0 {
+		n += 1 + sovOps(uint64(m.Gid))
+	}
+	if m.Mode != 0 {
+		n += 1 + sovOps(uint64(m.Mode))
+	}
+	if m.Optional {
+		n += 2
+	}
+	return n
+}
+
+func (m *HostOpt) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Path)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	l = len(m.Version)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+
+func (m *SSHOpt) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.ID)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Uid != 0 {
+		n += 1 + sovOps(uint64(m.Uid))
+	}
+	if m.Gid != 0 {
+		n += 1 + sovOps(uint64(m.Gid))
+	}
+	if m.Mode != 0 {
+		n += 1 + sovOps(uint64(m.Mode))
+	}
+	if m.Optional {
+		n += 2
+	}
+	return n
+}
+
+func (m *CacheOpt) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.ID)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Sharing != 0 {
+		n += 1 + sovOps(uint64(m.Sharing))
+	}
+	return n
+}
+
+func (m *CopyOp) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Src) > 0 {
+		for _, e := range m.Src {
+			l = e.Size()
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	l = len(m.Dest)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+
+func (m *CopySource) Size() (n int) {
+	var l int
+	_ = l
+	if m.Input != 0 {
+		n += 1 + sovOps(uint64(m.Input))
+	}
+	l = len(m.Selector)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+
+func (m *FileOp) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Actions) > 0 {
+		for _, e := range m.Actions {
+			l = e.Size()
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *FileAction) Size() (n int) {
+	var l int
+	_ = l
+	if m.Input != 0 {
+		n += 1 + sovOps(uint64(m.Input))
+	}
+	if m.SecondaryInput != 0 {
+		n += 1 + sovOps(uint64(m.SecondaryInput))
+	}
+	if m.Output != 0 {
+		n += 1 + sovOps(uint64(m.Output))
+	}
+	if m.Action != nil {
+		n += m.Action.Size()
+	}
+	return n
+}
+
+func (m *FileAction_Copy) Size() (n int) {
+	var l int
+	_ = l
+	if m.Copy != nil {
+		l = m.Copy.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+func (m *FileAction_Mkfile) Size() (n int) {
+	var l int
+	_ = l
+	if m.Mkfile != nil {
+		l = m.Mkfile.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+func (m *FileAction_Mkdir) Size() (n int) {
+	var l int
+	_ = l
+	if m.Mkdir != nil {
+		l = m.Mkdir.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+func (m *FileAction_Rm) Size() (n int) {
+	var l int
+	_ = l
+	if m.Rm != nil {
+		l = m.Rm.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+func (m *FileAction_Symlink) Size() (n int) {
+	var l int
+	_ = l
+	if m.Symlink != nil {
+		l = m.Symlink.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+func (m *FileActionCopy) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Src)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	l = len(m.Dest)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Owner != nil {
+		l = m.Owner.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Mode != 0 {
+		n += 1 + sovOps(uint64(m.Mode))
+	}
+	if m.FollowSymlink {
+		n += 2
+	}
+	if m.DirCopyContents {
+		n += 2
+	}
+	if m.CreateDestPath {
+		n += 2
+	}
+	if m.AllowWildcard {
+		n += 2
+	}
+	if m.Timestamp != 0 {
+		n += 1 + sovOps(uint64(m.Timestamp))
+	}
+	if m.ContentAttrs != nil {
+		l = m.ContentAttrs.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	return n
+}
+
+func (m *FileActionMkFile) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Path)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Mode != 0 {
+		n += 1 + sovOps(uint64(m.Mode))
+	}
+	l = len(m.Data)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Owner != nil {
+		l = m.Owner.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Timestamp != 0 {
+		n += 1 + sovOps(uint64(m.Timestamp))
+	}
+	if m.MakeParents {
+		n += 2
+	}
+	return n
+}
+
+func (m *FileActionMkDir) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Path)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Mode != 0 {
+		n += 1 + sovOps(uint64(m.Mode))
+	}
+	if m.MakeParents {
+		n += 2
+	}
+	if m.Owner != nil {
+		l = m.Owner.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Timestamp != 0 {
+		n += 1 + sovOps(uint64(m.Timestamp))
+	}
+	return n
+}
+
+func (m *FileActionRm) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Path)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.AllowNotFound {
+		n += 2
+	}
+	if m.AllowWildcard {
+		n += 2
+	}
+	return n
+}
+
+func (m *FileActionSymlink) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Oldpath)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	l = len(m.Newpath)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Owner != nil {
+		l = m.Owner.Size()
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if m.Timestamp != 0 {
+		n += 1 + sovOps(uint64(m.Timestamp))
+	}
+	return n
+}
+
+func (m *ChownOpt) Size() (n int) {
+	var l int
+	_ = l
+	if m.Uid != 0 {
+		n += 1 + sovOps(uint64(m.Uid))
+	}
+	if m.Gid != 0 {
+		n += 1 + sovOps(uint64(m.Gid))
+	}
+	return n
+}
+
+func (m *MergeOp) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Inputs) > 0 {
+		for _, e := range m.Inputs {
+			l = e.Size()
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *MergeInput) Size() (n int) {
+	var l int
+	_ = l
+	if m.Input != 0 {
+		n += 1 + sovOps(uint64(m.Input))
+	}
+	return n
+}
+
+func (m *DiffOp) Size() (n int) {
+	var l int
+	_ = l
+	if m.Lower != 0 {
+		n += 1 + sovOps(uint64(m.Lower))
+	}
+	if m.Upper != 0 {
+		n += 1 + sovOps(uint64(m.Upper))
+	}
+	return n
+}
+
+func (m *SourceOp) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Identifier)
+	if l > 0 {
+		n += 1 + l + sovOps(uint64(l))
+	}
+	if len(m.Attrs) > 0 {
+		for k, v := range m.Attrs {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovOps(uint64(len(k))) + 1 + len(v) + sovOps(uint64(len(v)))
+			n += mapEntrySize + 1 + sovOps(uint64(mapEntrySize))
+		}
+	}
+	return n
+}
+
+func (m *BuildOp) Size() (n int) {
+	var l int
+	_ = l
+	if m.Builder != 0 {
+		n += 1 + sovOps(uint64(m.Builder))
+	}
+	if len(m.Inputs) > 0 {
+		for k, v := range m.Inputs {
+			_ = k
+			_ = v
+			l = 0
+			if v != nil {
+				l = v.Size()
+				l += 1 + sovOps(uint64(l))
+			}
+			mapEntrySize := 1 + len(k) + sovOps(uint64(len(k))) + l
+			n += mapEntrySize + 1 + sovOps(uint64(mapEntrySize))
+		}
+	}
+	if len(m.Def) > 0 {
+		for _, b := range m.Def {
+			l = len(b)
+			n += 1 + l + sovOps(uint64(l))
+		}
+	}
+	if len(m.Attrs) > 0 {
+		for k, v := range m.Attrs {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovOps(uint64(len(k))) + 1 + len(v) + sovOps(uint64(len(v)))
+			n += mapEntrySize + 1 + sovOps(uint64(mapEntrySize))
+		}
+	}
+	return n
+}
+
+func (m *BuildInput) Size() (n int) {
+	var l int
+	_ = l
+	if m.Input != 0 {
+		n += 1 + sovOps(uint64(m.Input))
+	}
+	return n
+}
+
+func sovOps(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozOps(x uint64) (n int) {
+	return sovOps(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *Op) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Op: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Op: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Inputs", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Inputs = append(m.Inputs, &Input{})
+			if err := m.Inputs[len(m.Inputs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Exec", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &ExecOp{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Op = &Op_Exec{v}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Source", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &SourceOp{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Op = &Op_Source{v}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Copy", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &CopyOp{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Op = &Op_Copy{v}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Build", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &BuildOp{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Op = &Op_Build{v}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field File", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &FileOp{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Op = &Op_File{v}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Merge", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &MergeOp{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Op = &Op_Merge{v}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Diff", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &DiffOp{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Op = &Op_Diff{v}
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Platform", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Platform == nil {
+				m.Platform = &Platform{}
+			}
+			if err := m.Platform.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Constraints", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Constraints == nil {
+				m.Constraints = &WorkerConstraints{}
+			}
+			if err := m.Constraints.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *WorkerConstraints) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WorkerConstraints: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WorkerConstraints: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Filter", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Filter = append(m.Filter, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Platform) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Platform: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Platform: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Architecture", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Architecture = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OS", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OS = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Variant", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Variant = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OSVersion", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OSVersion = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OSFeatures", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OSFeatures = append(m.OSFeatures, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Input) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Input: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Input: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Digest", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Digest = github_com_opencontainers_go_digest.Digest(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			}
+			m.Index = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Index |= (OutputIndex(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ExecOp) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ExecOp: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ExecOp: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Meta", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Meta == nil {
+				m.Meta = &Meta{}
+			}
+			if err := m.Meta.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Mounts", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Mounts = append(m.Mounts, &Mount{})
+			if err := m.Mounts[len(m.Mounts)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ContentCacheRoot", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.ContentCacheRoot = bool(v != 0)
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Resources", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Resources == nil {
+				m.Resources = &Resources{}
+			}
+			if err := m.Resources.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timeout", wireType)
+			}
+			m.Timeout = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timeout |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Retry", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Retry == nil {
+				m.Retry = &RetryPolicy{}
+			}
+			if err := m.Retry.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReadonlyRootfs", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.ReadonlyRootfs = bool(v != 0)
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NoProxyEnv", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.NoProxyEnv = bool(v != 0)
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProxyEnv", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ProxyEnv = append(m.ProxyEnv, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ContentAttrs", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ContentAttrs == nil {
+				m.ContentAttrs = &ContentAttrs{}
+			}
+			if err := m.ContentAttrs.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CheckpointInterval", wireType)
+			}
+			m.CheckpointInterval = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CheckpointInterval |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Network", wireType)
+			}
+			m.Network = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Network |= (NetMode(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 13:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Security", wireType)
+			}
+			m.Security = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Security |= (SecurityMode(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Devices", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Devices = append(m.Devices, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SecurityProfile", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.SecurityProfile == nil {
+				m.SecurityProfile = &SecurityProfile{}
+			}
+			if err := m.SecurityProfile.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SecurityProfile) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SecurityProfile: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SecurityProfile: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Seccomp", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Seccomp = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Apparmor", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Apparmor = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SelinuxLabel", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SelinuxLabel = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ContentAttrs) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ContentAttrs: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ContentAttrs: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Ownership", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Ownership = bool(v != 0)
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Xattrs", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Xattrs = bool(v != 0)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Capabilities", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Capabilities = bool(v != 0)
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ModTime", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.ModTime = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RetryPolicy) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RetryPolicy: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RetryPolicy: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxRetries", wireType)
+			}
+			m.MaxRetries = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxRetries |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType == 0 {
+				var v int32
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowOps
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= (int32(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.ExitCodes = append(m.ExitCodes, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowOps
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= (int(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthOps
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				for iNdEx < postIndex {
+					var v int32
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowOps
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= (int32(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.ExitCodes = append(m.ExitCodes, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExitCodes", wireType)
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Resources) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Resources: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Resources: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CpuShares", wireType)
+			}
+			m.CpuShares = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CpuShares |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CpuQuota", wireType)
+			}
+			m.CpuQuota = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CpuQuota |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CpuPeriod", wireType)
+			}
+			m.CpuPeriod = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CpuPeriod |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Memory", wireType)
+			}
+			m.Memory = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Memory |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PidsLimit", wireType)
+			}
+			m.PidsLimit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.PidsLimit |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Ulimits", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Ulimits = append(m.Ulimits, &Ulimit{})
+			if err := m.Ulimits[len(m.Ulimits)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Ulimit) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Ulimit: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Ulimit: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Soft", wireType)
+			}
+			m.Soft = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Soft |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hard", wireType)
+			}
+			m.Hard = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Hard |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Meta) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Meta: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Meta: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Args", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Args = append(m.Args, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Env", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Env = append(m.Env, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cwd", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Cwd = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field User", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.User = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hostname", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Hostname = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExtraHosts", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ExtraHosts = append(m.ExtraHosts, &HostIP{})
+			if err := m.ExtraHosts[len(m.ExtraHosts)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *HostIP) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HostIP: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HostIP: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Host", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Host = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IP", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.IP = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Mount) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Mount: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Mount: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Input", wireType)
+			}
+			m.Input = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Input |= (InputIndex(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Selector", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Selector = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Dest", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Dest = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Output", wireType)
+			}
+			m.Output = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Output |= (OutputIndex(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Readonly", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Readonly = bool(v != 0)
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ContentCache", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.ContentCache = bool(v != 0)
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MountType", wireType)
+			}
+			m.MountType = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MountType |= (MountType(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ContentSelectors", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ContentSelectors = append(m.ContentSelectors, &Selector{})
+			if err := m.ContentSelectors[len(m.ContentSelectors)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 20:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CacheOpt", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.CacheOpt == nil {
+				m.CacheOpt = &CacheOpt{}
+			}
+			if err := m.CacheOpt.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 21:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TmpfsOpt", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.TmpfsOpt == nil {
+				m.TmpfsOpt = &TmpfsOpt{}
+			}
+			if err := m.TmpfsOpt.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 22:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SecretOpt", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.SecretOpt == nil {
+				m.SecretOpt = &SecretOpt{}
+			}
+			if err := m.SecretOpt.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 23:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SSHOpt", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.SSHOpt == nil {
+				m.SSHOpt = &SSHOpt{}
+			}
+			if err := m.SSHOpt.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 24:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HostOpt", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.HostOpt == nil {
+				m.HostOpt = &HostOpt{}
+			}
+			if err := m.HostOpt.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Selector) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Selector: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Selector: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Path", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Path = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Wildcard", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Wildcard = bool(v != 0)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FollowLinks", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.FollowLinks = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TmpfsOpt) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TmpfsOpt: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TmpfsOpt: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Size_", wireType)
+			}
+			m.Size_ = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Size_ |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SecretOpt) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SecretOpt: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SecretOpt: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Uid", wireType)
+			}
+			m.Uid = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Uid |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Gid", wireType)
+			}
+			m.Gid = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Gid |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Mode", wireType)
+			}
+			m.Mode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Mode |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Optional", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Optional = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *HostOpt) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HostOpt: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HostOpt: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Path", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Path = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Version = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SSHOpt) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SSHOpt: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SSHOpt: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Uid", wireType)
+			}
+			m.Uid = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Uid |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Gid", wireType)
+			}
+			m.Gid = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Gid |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Mode", wireType)
+			}
+			m.Mode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Mode |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Optional", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Optional = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CacheOpt) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CacheOpt: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CacheOpt: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sharing", wireType)
+			}
+			m.Sharing = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Sharing |= (CacheSharingOpt(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CopyOp) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CopyOp: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CopyOp: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Src", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Src = append(m.Src, &CopySource{})
+			if err := m.Src[len(m.Src)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Dest", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Dest = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CopySource) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CopySource: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CopySource: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Input", wireType)
+			}
+			m.Input = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Input |= (InputIndex(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Selector", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Selector = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *FileOp) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: FileOp: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: FileOp: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Actions", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Actions = append(m.Actions, &FileAction{})
+			if err := m.Actions[len(m.Actions)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *FileAction) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: FileAction: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: FileAction: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Input", wireType)
+			}
+			m.Input = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Input |= (InputIndex(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SecondaryInput", wireType)
+			}
+			m.SecondaryInput = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SecondaryInput |= (InputIndex(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Output", wireType)
+			}
+			m.Output = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Output |= (OutputIndex(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Copy", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &FileActionCopy{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Action = &FileAction_Copy{v}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Mkfile", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &FileActionMkFile{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Action = &FileAction_Mkfile{v}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Mkdir", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &FileActionMkDir{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Action = &FileAction_Mkdir{v}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Rm", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &FileActionRm{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Action = &FileAction_Rm{v}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Symlink", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &FileActionSymlink{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Action = &FileAction_Symlink{v}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *FileActionCopy) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: FileActionCopy: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: FileActionCopy: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Src", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Src = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Dest", wireType)
 			}
-			mapEntrySize := 1 + len(k) + sovOps(uint64(len(k))) + 1 + l + sovOps(uint64(l))
-			n += mapEntrySize + 1 + sovOps(uint64(mapEntrySize))
-		}
-	}
-	if len(m.Def) > 0 {
-		for _, b := range m.Def {
-			l = len(b)
-			n += 1 + l + sovOps(uint64(l))
-		}
-	}
-	if len(m.Attrs) > 0 {
-		for k, v := range m.Attrs {
-			_ = k
-			_ = v
-			mapEntrySize := 1 + len(k) + sovOps(uint64(len(k))) + 1 + len(v) + sovOps(uint64(len(v)))
-			n += mapEntrySize + 1 + sovOps(uint64(mapEntrySize))
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Dest = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Owner", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Owner == nil {
+				m.Owner = &ChownOpt{}
+			}
+			if err := m.Owner.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Mode", wireType)
+			}
+			m.Mode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Mode |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FollowSymlink", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.FollowSymlink = bool(v != 0)
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DirCopyContents", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.DirCopyContents = bool(v != 0)
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreateDestPath", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.CreateDestPath = bool(v != 0)
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AllowWildcard", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.AllowWildcard = bool(v != 0)
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			m.Timestamp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timestamp |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ContentAttrs", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ContentAttrs == nil {
+				m.ContentAttrs = &ContentAttrs{}
+			}
+			if err := m.ContentAttrs.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
 		}
 	}
-	return n
-}
-
-func (m *BuildInput) Size() (n int) {
-	var l int
-	_ = l
-	if m.Input != 0 {
-		n += 1 + sovOps(uint64(m.Input))
-	}
-	return n
-}
 
-func sovOps(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
-		}
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	return n
-}
-func sozOps(x uint64) (n int) {
-	return sovOps(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+	return nil
 }
-func (m *Op) Unmarshal(data []byte) error {
-	l := len(data)
+func (m *FileActionMkFile) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
 		preIndex := iNdEx
@@ -1077,7 +9013,7 @@ func (m *Op) Unmarshal(data []byte) error {
 			if iNdEx >= l {
 				return io.ErrUnexpectedEOF
 			}
-			b := data[iNdEx]
+			b := dAtA[iNdEx]
 			iNdEx++
 			wire |= (uint64(b) & 0x7F) << shift
 			if b < 0x80 {
@@ -1087,17 +9023,17 @@ func (m *Op) Unmarshal(data []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Op: wiretype end group for non-group")
+			return fmt.Errorf("proto: FileActionMkFile: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Op: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: FileActionMkFile: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Inputs", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Path", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowOps
@@ -1105,30 +9041,47 @@ func (m *Op) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthOps
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Inputs = append(m.Inputs, &Input{})
-			if err := m.Inputs[len(m.Inputs)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Path = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Mode", wireType)
+			}
+			m.Mode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Mode |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Exec", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowOps
@@ -1136,29 +9089,28 @@ func (m *Op) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				byteLen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthOps
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			v := &ExecOp{}
-			if err := v.Unmarshal(data[iNdEx:postIndex]); err != nil {
-				return err
+			m.Data = append(m.Data[:0], dAtA[iNdEx:postIndex]...)
+			if m.Data == nil {
+				m.Data = []byte{}
 			}
-			m.Op = &Op_Exec{v}
 			iNdEx = postIndex
-		case 3:
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Source", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Owner", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -1168,7 +9120,7 @@ func (m *Op) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
 				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
@@ -1182,17 +9134,18 @@ func (m *Op) Unmarshal(data []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			v := &SourceOp{}
-			if err := v.Unmarshal(data[iNdEx:postIndex]); err != nil {
+			if m.Owner == nil {
+				m.Owner = &ChownOpt{}
+			}
+			if err := m.Owner.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
-			m.Op = &Op_Source{v}
 			iNdEx = postIndex
-		case 4:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Copy", wireType)
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
 			}
-			var msglen int
+			m.Timestamp = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowOps
@@ -1200,31 +9153,18 @@ func (m *Op) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				m.Timestamp |= (int64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthOps
-			}
-			postIndex := iNdEx + msglen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			v := &CopyOp{}
-			if err := v.Unmarshal(data[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			m.Op = &Op_Copy{v}
-			iNdEx = postIndex
-		case 5:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Build", wireType)
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MakeParents", wireType)
 			}
-			var msglen int
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowOps
@@ -1232,29 +9172,17 @@ func (m *Op) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				v |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthOps
-			}
-			postIndex := iNdEx + msglen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			v := &BuildOp{}
-			if err := v.Unmarshal(data[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			m.Op = &Op_Build{v}
-			iNdEx = postIndex
+			m.MakeParents = bool(v != 0)
 		default:
 			iNdEx = preIndex
-			skippy, err := skipOps(data[iNdEx:])
+			skippy, err := skipOps(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
@@ -1273,8 +9201,8 @@ func (m *Op) Unmarshal(data []byte) error {
 	}
 	return nil
 }
-func (m *Input) Unmarshal(data []byte) error {
-	l := len(data)
+func (m *FileActionMkDir) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
 		preIndex := iNdEx
@@ -1286,7 +9214,7 @@ func (m *Input) Unmarshal(data []byte) error {
 			if iNdEx >= l {
 				return io.ErrUnexpectedEOF
 			}
-			b := data[iNdEx]
+			b := dAtA[iNdEx]
 			iNdEx++
 			wire |= (uint64(b) & 0x7F) << shift
 			if b < 0x80 {
@@ -1296,15 +9224,15 @@ func (m *Input) Unmarshal(data []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Input: wiretype end group for non-group")
+			return fmt.Errorf("proto: FileActionMkDir: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Input: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: FileActionMkDir: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Digest", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Path", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1314,7 +9242,7 @@ func (m *Input) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
 				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
@@ -1329,13 +9257,13 @@ func (m *Input) Unmarshal(data []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Digest = github_com_opencontainers_go_digest.Digest(data[iNdEx:postIndex])
+			m.Path = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Mode", wireType)
 			}
-			m.Index = 0
+			m.Mode = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowOps
@@ -1343,66 +9271,36 @@ func (m *Input) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
-				m.Index |= (OutputIndex(b) & 0x7F) << shift
+				m.Mode |= (int32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		default:
-			iNdEx = preIndex
-			skippy, err := skipOps(data[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if skippy < 0 {
-				return ErrInvalidLengthOps
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *ExecOp) Unmarshal(data []byte) error {
-	l := len(data)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowOps
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MakeParents", wireType)
 			}
-			b := data[iNdEx]
-			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
-			if b < 0x80 {
-				break
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: ExecOp: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ExecOp: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			m.MakeParents = bool(v != 0)
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Meta", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Owner", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -1412,7 +9310,7 @@ func (m *ExecOp) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
 				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
@@ -1426,18 +9324,18 @@ func (m *ExecOp) Unmarshal(data []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Meta == nil {
-				m.Meta = &Meta{}
+			if m.Owner == nil {
+				m.Owner = &ChownOpt{}
 			}
-			if err := m.Meta.Unmarshal(data[iNdEx:postIndex]); err != nil {
+			if err := m.Owner.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Mounts", wireType)
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
 			}
-			var msglen int
+			m.Timestamp = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowOps
@@ -1445,28 +9343,16 @@ func (m *ExecOp) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				m.Timestamp |= (int64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthOps
-			}
-			postIndex := iNdEx + msglen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Mounts = append(m.Mounts, &Mount{})
-			if err := m.Mounts[len(m.Mounts)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
-			skippy, err := skipOps(data[iNdEx:])
+			skippy, err := skipOps(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
@@ -1485,8 +9371,8 @@ func (m *ExecOp) Unmarshal(data []byte) error {
 	}
 	return nil
 }
-func (m *Meta) Unmarshal(data []byte) error {
-	l := len(data)
+func (m *FileActionRm) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
 		preIndex := iNdEx
@@ -1498,7 +9384,7 @@ func (m *Meta) Unmarshal(data []byte) error {
 			if iNdEx >= l {
 				return io.ErrUnexpectedEOF
 			}
-			b := data[iNdEx]
+			b := dAtA[iNdEx]
 			iNdEx++
 			wire |= (uint64(b) & 0x7F) << shift
 			if b < 0x80 {
@@ -1508,15 +9394,15 @@ func (m *Meta) Unmarshal(data []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Meta: wiretype end group for non-group")
+			return fmt.Errorf("proto: FileActionRm: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Meta: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: FileActionRm: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Args", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Path", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1526,7 +9412,7 @@ func (m *Meta) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
 				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
@@ -1541,13 +9427,13 @@ func (m *Meta) Unmarshal(data []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Args = append(m.Args, string(data[iNdEx:postIndex]))
+			m.Path = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Env", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AllowNotFound", wireType)
 			}
-			var stringLen uint64
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowOps
@@ -1555,28 +9441,19 @@ func (m *Meta) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				v |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthOps
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Env = append(m.Env, string(data[iNdEx:postIndex]))
-			iNdEx = postIndex
+			m.AllowNotFound = bool(v != 0)
 		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Cwd", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AllowWildcard", wireType)
 			}
-			var stringLen uint64
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowOps
@@ -1584,26 +9461,17 @@ func (m *Meta) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				v |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthOps
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Cwd = string(data[iNdEx:postIndex])
-			iNdEx = postIndex
+			m.AllowWildcard = bool(v != 0)
 		default:
 			iNdEx = preIndex
-			skippy, err := skipOps(data[iNdEx:])
+			skippy, err := skipOps(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
@@ -1622,8 +9490,8 @@ func (m *Meta) Unmarshal(data []byte) error {
 	}
 	return nil
 }
-func (m *Mount) Unmarshal(data []byte) error {
-	l := len(data)
+func (m *FileActionSymlink) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
 		preIndex := iNdEx
@@ -1635,7 +9503,7 @@ func (m *Mount) Unmarshal(data []byte) error {
 			if iNdEx >= l {
 				return io.ErrUnexpectedEOF
 			}
-			b := data[iNdEx]
+			b := dAtA[iNdEx]
 			iNdEx++
 			wire |= (uint64(b) & 0x7F) << shift
 			if b < 0x80 {
@@ -1645,17 +9513,17 @@ func (m *Mount) Unmarshal(data []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Mount: wiretype end group for non-group")
+			return fmt.Errorf("proto: FileActionSymlink: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Mount: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: FileActionSymlink: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Input", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Oldpath", wireType)
 			}
-			m.Input = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowOps
@@ -1663,16 +9531,26 @@ func (m *Mount) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
-				m.Input |= (InputIndex(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOps
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Oldpath = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Selector", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Newpath", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1682,7 +9560,7 @@ func (m *Mount) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
 				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
@@ -1697,13 +9575,13 @@ func (m *Mount) Unmarshal(data []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Selector = string(data[iNdEx:postIndex])
+			m.Newpath = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Dest", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Owner", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowOps
@@ -1711,28 +9589,101 @@ func (m *Mount) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthOps
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Dest = string(data[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 4:
+			if m.Owner == nil {
+				m.Owner = &ChownOpt{}
+			}
+			if err := m.Owner.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			m.Timestamp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOps
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timestamp |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ChownOpt) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ChownOpt: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ChownOpt: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Output", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Uid", wireType)
 			}
-			m.Output = 0
+			m.Uid = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowOps
@@ -1740,18 +9691,18 @@ func (m *Mount) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
-				m.Output |= (OutputIndex(b) & 0x7F) << shift
+				m.Uid |= (uint32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 5:
+		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Readonly", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Gid", wireType)
 			}
-			var v int
+			m.Gid = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowOps
@@ -1759,17 +9710,16 @@ func (m *Mount) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				m.Gid |= (uint32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.Readonly = bool(v != 0)
 		default:
 			iNdEx = preIndex
-			skippy, err := skipOps(data[iNdEx:])
+			skippy, err := skipOps(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
@@ -1788,8 +9738,8 @@ func (m *Mount) Unmarshal(data []byte) error {
 	}
 	return nil
 }
-func (m *CopyOp) Unmarshal(data []byte) error {
-	l := len(data)
+func (m *MergeOp) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
 		preIndex := iNdEx
@@ -1801,7 +9751,7 @@ func (m *CopyOp) Unmarshal(data []byte) error {
 			if iNdEx >= l {
 				return io.ErrUnexpectedEOF
 			}
-			b := data[iNdEx]
+			b := dAtA[iNdEx]
 			iNdEx++
 			wire |= (uint64(b) & 0x7F) << shift
 			if b < 0x80 {
@@ -1811,15 +9761,15 @@ func (m *CopyOp) Unmarshal(data []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: CopyOp: wiretype end group for non-group")
+			return fmt.Errorf("proto: MergeOp: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: CopyOp: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: MergeOp: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Src", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Inputs", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -1829,7 +9779,7 @@ func (m *CopyOp) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
 				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
@@ -1843,16 +9793,66 @@ func (m *CopyOp) Unmarshal(data []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Src = append(m.Src, &CopySource{})
-			if err := m.Src[len(m.Src)-1].Unmarshal(data[iNdEx:postIndex]); err != nil {
+			m.Inputs = append(m.Inputs, &MergeInput{})
+			if err := m.Inputs[len(m.Inputs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Dest", wireType)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOps(dAtA[iNdEx:])
+			if err != nil {
+				return err
 			}
-			var stringLen uint64
+			if skippy < 0 {
+				return ErrInvalidLengthOps
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *MergeInput) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOps
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: MergeInput: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: MergeInput: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Input", wireType)
+			}
+			m.Input = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowOps
@@ -1860,26 +9860,16 @@ func (m *CopyOp) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				m.Input |= (InputIndex(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthOps
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Dest = string(data[iNdEx:postIndex])
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
-			skippy, err := skipOps(data[iNdEx:])
+			skippy, err := skipOps(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
@@ -1898,8 +9888,8 @@ func (m *CopyOp) Unmarshal(data []byte) error {
 	}
 	return nil
 }
-func (m *CopySource) Unmarshal(data []byte) error {
-	l := len(data)
+func (m *DiffOp) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
 		preIndex := iNdEx
@@ -1911,7 +9901,7 @@ func (m *CopySource) Unmarshal(data []byte) error {
 			if iNdEx >= l {
 				return io.ErrUnexpectedEOF
 			}
-			b := data[iNdEx]
+			b := dAtA[iNdEx]
 			iNdEx++
 			wire |= (uint64(b) & 0x7F) << shift
 			if b < 0x80 {
@@ -1921,17 +9911,17 @@ func (m *CopySource) Unmarshal(data []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: CopySource: wiretype end group for non-group")
+			return fmt.Errorf("proto: DiffOp: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: CopySource: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: DiffOp: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Input", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Lower", wireType)
 			}
-			m.Input = 0
+			m.Lower = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowOps
@@ -1939,18 +9929,18 @@ func (m *CopySource) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
-				m.Input |= (InputIndex(b) & 0x7F) << shift
+				m.Lower |= (InputIndex(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Selector", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Upper", wireType)
 			}
-			var stringLen uint64
+			m.Upper = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowOps
@@ -1958,26 +9948,16 @@ func (m *CopySource) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				m.Upper |= (InputIndex(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthOps
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Selector = string(data[iNdEx:postIndex])
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
-			skippy, err := skipOps(data[iNdEx:])
+			skippy, err := skipOps(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
@@ -1996,8 +9976,8 @@ func (m *CopySource) Unmarshal(data []byte) error {
 	}
 	return nil
 }
-func (m *SourceOp) Unmarshal(data []byte) error {
-	l := len(data)
+func (m *SourceOp) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
 		preIndex := iNdEx
@@ -2009,7 +9989,7 @@ func (m *SourceOp) Unmarshal(data []byte) error {
 			if iNdEx >= l {
 				return io.ErrUnexpectedEOF
 			}
-			b := data[iNdEx]
+			b := dAtA[iNdEx]
 			iNdEx++
 			wire |= (uint64(b) & 0x7F) << shift
 			if b < 0x80 {
@@ -2037,7 +10017,7 @@ func (m *SourceOp) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
 				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
@@ -2052,7 +10032,7 @@ func (m *SourceOp) Unmarshal(data []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Identifier = string(data[iNdEx:postIndex])
+			m.Identifier = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
@@ -2066,7 +10046,7 @@ func (m *SourceOp) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
 				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
@@ -2088,7 +10068,7 @@ func (m *SourceOp) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
 				keykey |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
@@ -2103,7 +10083,7 @@ func (m *SourceOp) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
 				stringLenmapkey |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
@@ -2118,56 +10098,61 @@ func (m *SourceOp) Unmarshal(data []byte) error {
 			if postStringIndexmapkey > l {
 				return io.ErrUnexpectedEOF
 			}
-			mapkey := string(data[iNdEx:postStringIndexmapkey])
+			mapkey := string(dAtA[iNdEx:postStringIndexmapkey])
 			iNdEx = postStringIndexmapkey
-			var valuekey uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowOps
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
+			if m.Attrs == nil {
+				m.Attrs = make(map[string]string)
+			}
+			if iNdEx < postIndex {
+				var valuekey uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowOps
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					valuekey |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
 				}
-				b := data[iNdEx]
-				iNdEx++
-				valuekey |= (uint64(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
+				var stringLenmapvalue uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowOps
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					stringLenmapvalue |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
 				}
-			}
-			var stringLenmapvalue uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowOps
+				intStringLenmapvalue := int(stringLenmapvalue)
+				if intStringLenmapvalue < 0 {
+					return ErrInvalidLengthOps
 				}
-				if iNdEx >= l {
+				postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+				if postStringIndexmapvalue > l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
-				iNdEx++
-				stringLenmapvalue |= (uint64(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+				mapvalue := string(dAtA[iNdEx:postStringIndexmapvalue])
+				iNdEx = postStringIndexmapvalue
+				m.Attrs[mapkey] = mapvalue
+			} else {
+				var mapvalue string
+				m.Attrs[mapkey] = mapvalue
 			}
-			intStringLenmapvalue := int(stringLenmapvalue)
-			if intStringLenmapvalue < 0 {
-				return ErrInvalidLengthOps
-			}
-			postStringIndexmapvalue := iNdEx + intStringLenmapvalue
-			if postStringIndexmapvalue > l {
-				return io.ErrUnexpectedEOF
-			}
-			mapvalue := string(data[iNdEx:postStringIndexmapvalue])
-			iNdEx = postStringIndexmapvalue
-			if m.Attrs == nil {
-				m.Attrs = make(map[string]string)
-			}
-			m.Attrs[mapkey] = mapvalue
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
-			skippy, err := skipOps(data[iNdEx:])
+			skippy, err := skipOps(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
@@ -2186,8 +10171,8 @@ func (m *SourceOp) Unmarshal(data []byte) error {
 	}
 	return nil
 }
-func (m *BuildOp) Unmarshal(data []byte) error {
-	l := len(data)
+func (m *BuildOp) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
 		preIndex := iNdEx
@@ -2199,7 +10184,7 @@ func (m *BuildOp) Unmarshal(data []byte) error {
 			if iNdEx >= l {
 				return io.ErrUnexpectedEOF
 			}
-			b := data[iNdEx]
+			b := dAtA[iNdEx]
 			iNdEx++
 			wire |= (uint64(b) & 0x7F) << shift
 			if b < 0x80 {
@@ -2227,7 +10212,7 @@ func (m *BuildOp) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
 				m.Builder |= (InputIndex(b) & 0x7F) << shift
 				if b < 0x80 {
@@ -2246,7 +10231,7 @@ func (m *BuildOp) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
 				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
@@ -2268,7 +10253,7 @@ func (m *BuildOp) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
 				keykey |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
@@ -2283,7 +10268,7 @@ func (m *BuildOp) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
 				stringLenmapkey |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
@@ -2298,57 +10283,62 @@ func (m *BuildOp) Unmarshal(data []byte) error {
 			if postStringIndexmapkey > l {
 				return io.ErrUnexpectedEOF
 			}
-			mapkey := string(data[iNdEx:postStringIndexmapkey])
+			mapkey := string(dAtA[iNdEx:postStringIndexmapkey])
 			iNdEx = postStringIndexmapkey
-			var valuekey uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowOps
+			if m.Inputs == nil {
+				m.Inputs = make(map[string]*BuildInput)
+			}
+			if iNdEx < postIndex {
+				var valuekey uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowOps
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					valuekey |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
 				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
+				var mapmsglen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowOps
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					mapmsglen |= (int(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
 				}
-				b := data[iNdEx]
-				iNdEx++
-				valuekey |= (uint64(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
+				if mapmsglen < 0 {
+					return ErrInvalidLengthOps
 				}
-			}
-			var mapmsglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowOps
+				postmsgIndex := iNdEx + mapmsglen
+				if mapmsglen < 0 {
+					return ErrInvalidLengthOps
 				}
-				if iNdEx >= l {
+				if postmsgIndex > l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
-				iNdEx++
-				mapmsglen |= (int(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
+				mapvalue := &BuildInput{}
+				if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
+					return err
 				}
+				iNdEx = postmsgIndex
+				m.Inputs[mapkey] = mapvalue
+			} else {
+				var mapvalue *BuildInput
+				m.Inputs[mapkey] = mapvalue
 			}
-			if mapmsglen < 0 {
-				return ErrInvalidLengthOps
-			}
-			postmsgIndex := iNdEx + mapmsglen
-			if mapmsglen < 0 {
-				return ErrInvalidLengthOps
-			}
-			if postmsgIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			mapvalue := &BuildInput{}
-			if err := mapvalue.Unmarshal(data[iNdEx:postmsgIndex]); err != nil {
-				return err
-			}
-			iNdEx = postmsgIndex
-			if m.Inputs == nil {
-				m.Inputs = make(map[string]*BuildInput)
-			}
-			m.Inputs[mapkey] = mapvalue
 			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
@@ -2362,7 +10352,7 @@ func (m *BuildOp) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
 				byteLen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
@@ -2377,7 +10367,7 @@ func (m *BuildOp) Unmarshal(data []byte) error {
 				return io.ErrUnexpectedEOF
 			}
 			m.Def = append(m.Def, make([]byte, postIndex-iNdEx))
-			copy(m.Def[len(m.Def)-1], data[iNdEx:postIndex])
+			copy(m.Def[len(m.Def)-1], dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 4:
 			if wireType != 2 {
@@ -2391,7 +10381,7 @@ func (m *BuildOp) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
 				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
@@ -2413,7 +10403,7 @@ func (m *BuildOp) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
 				keykey |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
@@ -2428,7 +10418,7 @@ func (m *BuildOp) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
 				stringLenmapkey |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
@@ -2443,56 +10433,61 @@ func (m *BuildOp) Unmarshal(data []byte) error {
 			if postStringIndexmapkey > l {
 				return io.ErrUnexpectedEOF
 			}
-			mapkey := string(data[iNdEx:postStringIndexmapkey])
+			mapkey := string(dAtA[iNdEx:postStringIndexmapkey])
 			iNdEx = postStringIndexmapkey
-			var valuekey uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowOps
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
+			if m.Attrs == nil {
+				m.Attrs = make(map[string]string)
+			}
+			if iNdEx < postIndex {
+				var valuekey uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowOps
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					valuekey |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
 				}
-				b := data[iNdEx]
-				iNdEx++
-				valuekey |= (uint64(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
+				var stringLenmapvalue uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowOps
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					stringLenmapvalue |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
 				}
-			}
-			var stringLenmapvalue uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowOps
+				intStringLenmapvalue := int(stringLenmapvalue)
+				if intStringLenmapvalue < 0 {
+					return ErrInvalidLengthOps
 				}
-				if iNdEx >= l {
+				postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+				if postStringIndexmapvalue > l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
-				iNdEx++
-				stringLenmapvalue |= (uint64(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLenmapvalue := int(stringLenmapvalue)
-			if intStringLenmapvalue < 0 {
-				return ErrInvalidLengthOps
+				mapvalue := string(dAtA[iNdEx:postStringIndexmapvalue])
+				iNdEx = postStringIndexmapvalue
+				m.Attrs[mapkey] = mapvalue
+			} else {
+				var mapvalue string
+				m.Attrs[mapkey] = mapvalue
 			}
-			postStringIndexmapvalue := iNdEx + intStringLenmapvalue
-			if postStringIndexmapvalue > l {
-				return io.ErrUnexpectedEOF
-			}
-			mapvalue := string(data[iNdEx:postStringIndexmapvalue])
-			iNdEx = postStringIndexmapvalue
-			if m.Attrs == nil {
-				m.Attrs = make(map[string]string)
-			}
-			m.Attrs[mapkey] = mapvalue
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
-			skippy, err := skipOps(data[iNdEx:])
+			skippy, err := skipOps(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
@@ -2511,8 +10506,8 @@ func (m *BuildOp) Unmarshal(data []byte) error {
 	}
 	return nil
 }
-func (m *BuildInput) Unmarshal(data []byte) error {
-	l := len(data)
+func (m *BuildInput) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
 		preIndex := iNdEx
@@ -2524,7 +10519,7 @@ func (m *BuildInput) Unmarshal(data []byte) error {
 			if iNdEx >= l {
 				return io.ErrUnexpectedEOF
 			}
-			b := data[iNdEx]
+			b := dAtA[iNdEx]
 			iNdEx++
 			wire |= (uint64(b) & 0x7F) << shift
 			if b < 0x80 {
@@ -2552,7 +10547,7 @@ func (m *BuildInput) Unmarshal(data []byte) error {
 				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
 				m.Input |= (InputIndex(b) & 0x7F) << shift
 				if b < 0x80 {
@@ -2561,7 +10556,7 @@ func (m *BuildInput) Unmarshal(data []byte) error {
 			}
 		default:
 			iNdEx = preIndex
-			skippy, err := skipOps(data[iNdEx:])
+			skippy, err := skipOps(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
@@ -2580,8 +10575,8 @@ func (m *BuildInput) Unmarshal(data []byte) error {
 	}
 	return nil
 }
-func skipOps(data []byte) (n int, err error) {
-	l := len(data)
+func skipOps(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
 		var wire uint64
@@ -2592,7 +10587,7 @@ func skipOps(data []byte) (n int, err error) {
 			if iNdEx >= l {
 				return 0, io.ErrUnexpectedEOF
 			}
-			b := data[iNdEx]
+			b := dAtA[iNdEx]
 			iNdEx++
 			wire |= (uint64(b) & 0x7F) << shift
 			if b < 0x80 {
@@ -2610,7 +10605,7 @@ func skipOps(data []byte) (n int, err error) {
 					return 0, io.ErrUnexpectedEOF
 				}
 				iNdEx++
-				if data[iNdEx-1] < 0x80 {
+				if dAtA[iNdEx-1] < 0x80 {
 					break
 				}
 			}
@@ -2627,7 +10622,7 @@ func skipOps(data []byte) (n int, err error) {
 				if iNdEx >= l {
 					return 0, io.ErrUnexpectedEOF
 				}
-				b := data[iNdEx]
+				b := dAtA[iNdEx]
 				iNdEx++
 				length |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
@@ -2650,7 +10645,7 @@ func skipOps(data []byte) (n int, err error) {
 					if iNdEx >= l {
 						return 0, io.ErrUnexpectedEOF
 					}
-					b := data[iNdEx]
+					b := dAtA[iNdEx]
 					iNdEx++
 					innerWire |= (uint64(b) & 0x7F) << shift
 					if b < 0x80 {
@@ -2661,7 +10656,7 @@ func skipOps(data []byte) (n int, err error) {
 				if innerWireType == 4 {
 					break
 				}
-				next, err := skipOps(data[start:])
+				next, err := skipOps(dAtA[start:])
 				if err != nil {
 					return 0, err
 				}
@@ -2684,3 +10679,150 @@ var (
 	ErrInvalidLengthOps = fmt.Errorf("proto: negative length found during unmarshaling")
 	ErrIntOverflowOps   = fmt.Errorf("proto: integer overflow")
 )
+
+func init() { proto.RegisterFile("ops.proto", fileDescriptorOps) }
+
+var fileDescriptorOps = []byte{
+	// 2254 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x58, 0x4b, 0x8f, 0x1b, 0xc7,
+	0xf1, 0x5f, 0xbe, 0xc9, 0xe2, 0x3e, 0xe8, 0x96, 0x2c, 0x0f, 0x04, 0x63, 0xbd, 0xff, 0xf9, 0xcb,
+	0xc6, 0x7a, 0x25, 0xad, 0x60, 0xc5, 0x90, 0x05, 0x03, 0x09, 0xb0, 0x0f, 0x0a, 0x4b, 0x5b, 0xbb,
+	0x64, 0x9a, 0x2b, 0x39, 0x07, 0x03, 0xc1, 0xec, 0xb0, 0xb9, 0x6c, 0x90, 0x33, 0x3d, 0xe8, 0x69,
+	0xee, 0x2e, 0x73, 0x08, 0x90, 0x5c, 0x82, 0xe4, 0x14, 0x20, 0x40, 0x0e, 0x01, 0xf2, 0x11, 0x72,
+	0xc9, 0x17, 0xc8, 0xd5, 0xc7, 0x9c, 0x13, 0xc0, 0x30, 0x94, 0x2f, 0x12, 0x54, 0x3f, 0x38, 0x43,
+	0xee, 0x4a, 0x51, 0xe2, 0x20, 0xa7, 0xe9, 0xfa, 0x55, 0x4d, 0x75, 0x55, 0x75, 0x55, 0xf5, 0x03,
+	0x1a, 0x22, 0x49, 0x77, 0x13, 0x29, 0x94, 0x20, 0xc5, 0xe4, 0xec, 0xee, 0xc3, 0x73, 0xae, 0x46,
+	0xd3, 0xb3, 0xdd, 0x50, 0x44, 0x8f, 0xce, 0xc5, 0xb9, 0x78, 0xa4, 0x59, 0x67, 0xd3, 0xa1, 0xa6,
+	0x34, 0xa1, 0x47, 0xe6, 0x17, 0xff, 0x37, 0x25, 0x28, 0x76, 0x13, 0xf2, 0x7f, 0x50, 0xe5, 0x71,
+	0x32, 0x55, 0xa9, 0x57, 0xd8, 0x2a, 0x6d, 0x37, 0x1f, 0x37, 0x76, 0x93, 0xb3, 0xdd, 0x0e, 0x22,
+	0xd4, 0x32, 0xc8, 0x16, 0x94, 0xd9, 0x15, 0x0b, 0xbd, 0xe2, 0x56, 0x61, 0xbb, 0xf9, 0x18, 0x50,
+	0xa0, 0x7d, 0xc5, 0xc2, 0x6e, 0x72, 0xb4, 0x42, 0x35, 0x87, 0x7c, 0x04, 0xd5, 0x54, 0x4c, 0x65,
+	0xc8, 0xbc, 0x92, 0x96, 0x59, 0x45, 0x99, 0xbe, 0x46, 0xb4, 0x94, 0xe5, 0xa2, 0xa6, 0x50, 0x24,
+	0x33, 0xaf, 0x9c, 0x69, 0x3a, 0x10, 0xc9, 0xcc, 0x68, 0x42, 0x0e, 0xf9, 0x7f, 0xa8, 0x9c, 0x4d,
+	0xf9, 0x64, 0xe0, 0x55, 0xb4, 0x48, 0x13, 0x45, 0xf6, 0x11, 0xd0, 0x32, 0x86, 0x87, 0x6a, 0x86,
+	0x7c, 0xc2, 0xbc, 0x6a, 0xa6, 0xe6, 0x19, 0x9f, 0x98, 0xa9, 0x34, 0x07, 0xd5, 0x44, 0x4c, 0x9e,
+	0x33, 0xaf, 0x96, 0xa9, 0x39, 0x46, 0xc0, 0xa8, 0xd1, 0x3c, 0x54, 0x33, 0xe0, 0xc3, 0xa1, 0x57,
+	0xcf, 0xd4, 0x1c, 0xf2, 0xe1, 0xd0, 0xa8, 0x41, 0x0e, 0xd9, 0x86, 0x7a, 0x32, 0x09, 0xd4, 0x50,
+	0xc8, 0xc8, 0x6b, 0x64, 0x9e, 0xf5, 0x2c, 0x46, 0xe7, 0x5c, 0xf2, 0x19, 0x34, 0x43, 0x11, 0xa7,
+	0x4a, 0x06, 0x3c, 0x56, 0xa9, 0x07, 0x5a, 0xf8, 0x5d, 0x14, 0xfe, 0x4a, 0xc8, 0x31, 0x93, 0x07,
+	0x19, 0x93, 0xe6, 0x25, 0xf7, 0xcb, 0x50, 0x14, 0x89, 0x7f, 0x1f, 0xde, 0xb9, 0x26, 0x47, 0xee,
+	0x40, 0x75, 0xc8, 0x27, 0x8a, 0x49, 0xbd, 0x34, 0x0d, 0x6a, 0x29, 0xff, 0xf7, 0x05, 0xa8, 0x3b,
+	0x13, 0x88, 0x0f, 0xab, 0x7b, 0x32, 0x1c, 0x71, 0xc5, 0x42, 0x35, 0x95, 0xcc, 0x2b, 0x6c, 0x15,
+	0xb6, 0x1b, 0x74, 0x01, 0x23, 0xeb, 0x50, 0xec, 0xf6, 0xf5, 0xf2, 0x35, 0x68, 0xb1, 0xdb, 0x27,
+	0x1e, 0xd4, 0x5e, 0x06, 0x92, 0x07, 0xb1, 0xd2, 0xeb, 0xd5, 0xa0, 0x8e, 0x24, 0xef, 0x43, 0xa3,
+	0xdb, 0x7f, 0xc9, 0x64, 0xca, 0x45, 0xac, 0x57, 0xa9, 0x41, 0x33, 0x80, 0x6c, 0x02, 0x74, 0xfb,
+	0xcf, 0x58, 0x80, 0x4a, 0x53, 0xaf, 0xa2, 0x8d, 0xca, 0x21, 0xfe, 0xcf, 0xa1, 0xa2, 0x33, 0x87,
+	0x7c, 0x01, 0xd5, 0x01, 0x3f, 0x67, 0xa9, 0x32, 0xe6, 0xec, 0x3f, 0xfe, 0xe6, 0xdb, 0x0f, 0x56,
+	0xfe, 0xf6, 0xed, 0x07, 0x3b, 0xb9, 0x14, 0x15, 0x09, 0x8b, 0x43, 0x11, 0xab, 0x80, 0xc7, 0x4c,
+	0xa6, 0x8f, 0xce, 0xc5, 0x43, 0xf3, 0xcb, 0xee, 0xa1, 0xfe, 0x50, 0xab, 0x81, 0x7c, 0x0c, 0x15,
+	0x1e, 0x0f, 0xd8, 0x95, 0xb6, 0xbf, 0xb4, 0x7f, 0xcb, 0xaa, 0x6a, 0x76, 0xa7, 0x2a, 0x99, 0xaa,
+	0x0e, 0xb2, 0xa8, 0x91, 0xf0, 0xff, 0x5e, 0x86, 0xaa, 0xc9, 0x4c, 0xf2, 0x3e, 0x94, 0x23, 0xa6,
+	0x02, 0x3d, 0x7f, 0xf3, 0x71, 0xdd, 0xac, 0xbf, 0x0a, 0xa8, 0x46, 0x31, 0xe9, 0x23, 0x31, 0xc5,
+	0x85, 0x2a, 0x66, 0x49, 0x7f, 0x8c, 0x08, 0xb5, 0x0c, 0xb2, 0x03, 0x2d, 0xb4, 0x8e, 0xc5, 0xea,
+	0x20, 0x08, 0x47, 0x8c, 0x0a, 0x61, 0x82, 0x55, 0xa7, 0xd7, 0x70, 0x72, 0x1f, 0x1a, 0x92, 0x99,
+	0x14, 0x4f, 0x6d, 0x6e, 0xaf, 0xa1, 0x46, 0xea, 0x40, 0x9a, 0xf1, 0x31, 0xf8, 0x8a, 0x47, 0x4c,
+	0x4c, 0x95, 0xce, 0xf1, 0x12, 0x75, 0x24, 0xf9, 0x10, 0x2a, 0x92, 0x29, 0x39, 0xb3, 0x79, 0xbd,
+	0x61, 0x54, 0x28, 0x39, 0xeb, 0x89, 0x09, 0x0f, 0x67, 0xd4, 0x70, 0xc9, 0x47, 0xb0, 0x2e, 0x59,
+	0x30, 0x10, 0xf1, 0x64, 0x86, 0xb3, 0x0f, 0x53, 0x9d, 0xe4, 0x75, 0xba, 0x84, 0xe2, 0x6a, 0xc5,
+	0xa2, 0x27, 0xc5, 0xd5, 0xac, 0x1d, 0x5f, 0xe8, 0x24, 0xaf, 0xd3, 0x1c, 0x42, 0xee, 0x42, 0x3d,
+	0x71, 0xdc, 0x86, 0x5e, 0xcb, 0x39, 0x4d, 0x3e, 0x85, 0x55, 0xeb, 0xe5, 0x9e, 0x52, 0xd2, 0xe5,
+	0x73, 0xcb, 0x14, 0x6c, 0x86, 0xd3, 0x05, 0x29, 0xb2, 0x0b, 0x24, 0x1c, 0xb1, 0x70, 0x9c, 0x08,
+	0x1e, 0xab, 0x4e, 0xac, 0x98, 0xbc, 0x08, 0x26, 0x5e, 0x53, 0x7b, 0x79, 0x03, 0x87, 0x7c, 0x08,
+	0xb5, 0x98, 0xa9, 0x4b, 0x21, 0xc7, 0xde, 0xea, 0x56, 0x61, 0x7b, 0xdd, 0xd4, 0xe9, 0x09, 0x53,
+	0xc7, 0x62, 0xc0, 0xa8, 0xe3, 0x91, 0x07, 0x50, 0x4f, 0x59, 0x38, 0x95, 0x5c, 0xcd, 0xbc, 0x35,
+	0x2d, 0xa7, 0x0d, 0xe9, 0x5b, 0x4c, 0x0b, 0xcf, 0x25, 0x30, 0xbe, 0x03, 0x76, 0xc1, 0x71, 0x29,
+	0xd6, 0xb5, 0x57, 0x8e, 0x24, 0x3f, 0x84, 0x0d, 0x27, 0xd5, 0x93, 0x42, 0x77, 0x90, 0x0d, 0xed,
+	0xd7, 0xad, 0xbc, 0x3a, 0xcb, 0xa2, 0xcb, 0xb2, 0xfe, 0x18, 0x36, 0x96, 0x64, 0x70, 0xae, 0x94,
+	0x85, 0xa1, 0x88, 0x12, 0x5b, 0x77, 0x8e, 0xc4, 0xe0, 0x06, 0x49, 0x12, 0xc8, 0x48, 0x48, 0x5b,
+	0x78, 0x73, 0x1a, 0x4b, 0x36, 0x65, 0x13, 0x1e, 0x4f, 0xaf, 0x9e, 0x07, 0x67, 0x6c, 0x62, 0x6b,
+	0x70, 0x01, 0xf3, 0x7f, 0x59, 0x80, 0xd5, 0x7c, 0xa4, 0xb1, 0x32, 0xc5, 0x25, 0x56, 0xca, 0x88,
+	0x9b, 0xc9, 0xea, 0x34, 0x03, 0xb0, 0x55, 0x5c, 0x05, 0x7a, 0xa5, 0x8a, 0x9a, 0x65, 0x29, 0x9c,
+	0x2a, 0x0c, 0x92, 0xe0, 0x8c, 0x4f, 0xb8, 0xe2, 0x2c, 0xb5, 0x19, 0xbc, 0x80, 0xa1, 0x13, 0x91,
+	0x18, 0x9c, 0xf2, 0x88, 0xe9, 0xdc, 0xad, 0x53, 0x47, 0xfa, 0x5f, 0x42, 0x33, 0x97, 0x7f, 0x98,
+	0x50, 0x51, 0x70, 0x85, 0x08, 0xaa, 0x42, 0x1b, 0x2a, 0x34, 0x87, 0xa0, 0x89, 0xec, 0x8a, 0xab,
+	0x03, 0x31, 0x60, 0xa6, 0xb0, 0x2a, 0x34, 0x03, 0xfc, 0xbf, 0x14, 0xa0, 0x31, 0x2f, 0x08, 0x94,
+	0x0d, 0x93, 0x69, 0x7f, 0x14, 0x48, 0xab, 0xaa, 0x44, 0x33, 0x00, 0xa3, 0x17, 0x26, 0xd3, 0x1f,
+	0x4f, 0x85, 0x0a, 0x4c, 0xd9, 0xd3, 0x39, 0x6d, 0xff, 0xec, 0x31, 0xc9, 0xc5, 0xc0, 0x2b, 0xcd,
+	0xff, 0x34, 0x00, 0x06, 0x22, 0x62, 0x91, 0x90, 0x66, 0x8f, 0x29, 0x51, 0x4b, 0xe1, 0x5f, 0x09,
+	0x1f, 0xa4, 0xcf, 0x79, 0xc4, 0x5d, 0xdd, 0x65, 0x00, 0xb9, 0x07, 0xb5, 0xe9, 0x04, 0x47, 0xa9,
+	0x57, 0xdd, 0x2a, 0xb9, 0xcd, 0xe0, 0x85, 0x86, 0xa8, 0x63, 0xf9, 0x87, 0x50, 0x35, 0x10, 0x21,
+	0x50, 0x8e, 0x83, 0xc8, 0x35, 0x5b, 0x3d, 0x46, 0x2c, 0x15, 0x43, 0x65, 0xed, 0xd5, 0x63, 0xc4,
+	0x46, 0x81, 0x74, 0x66, 0xea, 0xb1, 0xff, 0x87, 0x02, 0x94, 0xb1, 0x15, 0x21, 0x33, 0x90, 0xe7,
+	0xa9, 0x6d, 0xee, 0x7a, 0x4c, 0x5a, 0x50, 0x62, 0xf1, 0x85, 0x0e, 0x5e, 0x83, 0xe2, 0x10, 0x91,
+	0xf0, 0x72, 0x60, 0x73, 0x04, 0x87, 0xf8, 0xdf, 0x34, 0x65, 0xd2, 0xb6, 0x67, 0x3d, 0xc6, 0x80,
+	0x8d, 0x44, 0xaa, 0xb4, 0x51, 0x15, 0x93, 0x6e, 0x8e, 0x26, 0x3b, 0x00, 0xec, 0x4a, 0xc9, 0xe0,
+	0x48, 0xa4, 0x8b, 0xfe, 0x21, 0xd0, 0xe9, 0xd1, 0x1c, 0xd7, 0x7f, 0x00, 0x55, 0x83, 0x6a, 0xd3,
+	0x85, 0x6b, 0xe0, 0x54, 0x8f, 0x71, 0x1f, 0xe9, 0xf4, 0xdc, 0x3e, 0xd2, 0xe9, 0xf9, 0xbf, 0x28,
+	0x43, 0x45, 0x77, 0x4d, 0xb2, 0x8d, 0x4d, 0x3a, 0x99, 0x1a, 0xf1, 0xd2, 0x3e, 0xb1, 0x4d, 0x1a,
+	0x3a, 0x71, 0xbe, 0x47, 0xe3, 0xd6, 0x70, 0x17, 0x8b, 0x79, 0xc2, 0x42, 0x95, 0x15, 0x86, 0xa3,
+	0x71, 0xce, 0x01, 0x6e, 0x1a, 0xc6, 0x59, 0x3d, 0x26, 0xf7, 0xa1, 0x2a, 0x74, 0xa7, 0xf7, 0xca,
+	0xaf, 0xef, 0xff, 0x56, 0x04, 0x95, 0xbb, 0x26, 0xa8, 0xc3, 0x50, 0xa7, 0x73, 0x5a, 0x97, 0x42,
+	0xae, 0x71, 0x7b, 0x55, 0x5b, 0x0a, 0x39, 0x0c, 0x1b, 0xb9, 0x6e, 0xff, 0xa7, 0xb3, 0xc4, 0x1c,
+	0x1d, 0xd6, 0x4d, 0x23, 0x3f, 0x76, 0x20, 0xcd, 0xf8, 0xe4, 0xe9, 0x7c, 0x87, 0xe8, 0x5b, 0x07,
+	0x52, 0xaf, 0xbe, 0x55, 0x72, 0x87, 0x04, 0x07, 0xd2, 0x6b, 0x52, 0x78, 0xac, 0x08, 0x71, 0xbe,
+	0x6e, 0xa2, 0xbc, 0xdb, 0xd9, 0xb1, 0xe2, 0xc0, 0x62, 0x74, 0xce, 0x45, 0x49, 0x15, 0x25, 0xc3,
+	0x14, 0x25, 0xdf, 0xcd, 0x24, 0x4f, 0x2d, 0x46, 0xe7, 0x5c, 0x34, 0x3d, 0x65, 0xa1, 0x64, 0x0a,
+	0x45, 0xef, 0x64, 0x7b, 0x50, 0xdf, 0x81, 0x34, 0xe3, 0x13, 0x1f, 0xaa, 0xfd, 0xfe, 0x11, 0x4a,
+	0xbe, 0x97, 0x9d, 0x7d, 0x0c, 0x42, 0x2d, 0x07, 0x9b, 0x33, 0x2e, 0x3a, 0x0a, 0x79, 0xd9, 0x21,
+	0xea, 0xc8, 0x40, 0xd4, 0xf1, 0xfc, 0xaf, 0xa1, 0xde, 0xcf, 0xad, 0x5f, 0x12, 0xa8, 0x91, 0xcb,
+	0x19, 0x1c, 0xe3, 0x92, 0x5c, 0xf2, 0xc9, 0x20, 0xc4, 0x32, 0x30, 0xbd, 0x69, 0x4e, 0x93, 0x2d,
+	0x68, 0x0e, 0xc5, 0x64, 0x22, 0x2e, 0x9f, 0xf3, 0x78, 0xec, 0x9a, 0x53, 0x1e, 0xf2, 0x37, 0xa1,
+	0xee, 0x7c, 0xd5, 0x05, 0xc6, 0x7f, 0xc6, 0x6c, 0xb7, 0xd0, 0x63, 0x5f, 0x40, 0x63, 0xee, 0xa0,
+	0x4e, 0xcf, 0x43, 0x3b, 0x79, 0xb1, 0x73, 0x88, 0xa5, 0x33, 0xe5, 0x66, 0xd6, 0x35, 0x8a, 0x43,
+	0x44, 0xce, 0xb9, 0x29, 0xa6, 0x35, 0x8a, 0x43, 0x54, 0x1a, 0x89, 0x81, 0xe9, 0x7c, 0x6b, 0x54,
+	0x8f, 0xd1, 0x64, 0x91, 0x28, 0x2e, 0xe2, 0x60, 0xe2, 0xb2, 0xc8, 0xd1, 0xfe, 0x67, 0x50, 0xb3,
+	0x21, 0xb8, 0xd1, 0x5b, 0x0f, 0x6a, 0x17, 0xf6, 0xf4, 0x64, 0x92, 0xdb, 0x91, 0xfe, 0xc4, 0x85,
+	0xfc, 0x7f, 0x62, 0x66, 0x07, 0xea, 0x2e, 0x9b, 0xae, 0xcd, 0xf7, 0x10, 0x6a, 0xe9, 0x28, 0x90,
+	0x3c, 0x3e, 0xd7, 0x73, 0xae, 0x9b, 0xed, 0x4f, 0x8b, 0xf7, 0x0d, 0xae, 0x17, 0xd8, 0xca, 0xf8,
+	0x3f, 0x82, 0xaa, 0x39, 0xa3, 0x93, 0x2d, 0x28, 0xa5, 0x32, 0xb4, 0xf7, 0x84, 0x75, 0x77, 0x78,
+	0x37, 0xc7, 0x7c, 0x8a, 0xac, 0x79, 0x01, 0x17, 0xb3, 0x02, 0xf6, 0x29, 0x40, 0x26, 0xf6, 0xdf,
+	0x69, 0x14, 0xfe, 0x63, 0xa8, 0x9a, 0x03, 0x3f, 0xd9, 0x86, 0x5a, 0x10, 0xa2, 0xd3, 0x69, 0xde,
+	0x2e, 0x64, 0xee, 0x69, 0x98, 0x3a, 0xb6, 0xff, 0xab, 0x12, 0x40, 0x86, 0xff, 0x1b, 0x86, 0x7c,
+	0x0e, 0xeb, 0x29, 0x0b, 0x45, 0x3c, 0x08, 0xe4, 0x4c, 0x73, 0xbd, 0xe2, 0x6b, 0x7f, 0x59, 0x92,
+	0xcc, 0x75, 0xaf, 0xd2, 0xbf, 0xee, 0x5e, 0xdb, 0x0b, 0xb7, 0x23, 0xb2, 0xe8, 0x08, 0xc6, 0x70,
+	0x7e, 0x4b, 0xda, 0x85, 0x6a, 0x34, 0xd6, 0x07, 0x18, 0x73, 0x4d, 0xba, 0xbd, 0x28, 0x7b, 0x3c,
+	0xc6, 0x31, 0xde, 0xbb, 0x8c, 0x14, 0xb9, 0x0f, 0x95, 0x68, 0x3c, 0xe0, 0xd2, 0x9e, 0x2c, 0x6f,
+	0x2d, 0x8b, 0x1f, 0x72, 0xa9, 0xaf, 0x45, 0x28, 0x43, 0x7c, 0x28, 0xca, 0xc8, 0x5e, 0x9c, 0x5a,
+	0x4b, 0xd1, 0x8c, 0x8e, 0x56, 0x68, 0x51, 0x46, 0xe4, 0x13, 0xa8, 0xa5, 0xb3, 0x68, 0xc2, 0xe3,
+	0xb1, 0x57, 0xcf, 0xae, 0x3a, 0x99, 0x60, 0xdf, 0x30, 0x8f, 0x56, 0xa8, 0x93, 0xdb, 0xaf, 0x43,
+	0xd5, 0x2c, 0x85, 0xff, 0x5d, 0x11, 0xd6, 0x17, 0x1d, 0x23, 0x2d, 0x97, 0x5a, 0x7a, 0x97, 0x7b,
+	0x4d, 0x2a, 0x11, 0x1f, 0x2a, 0xfa, 0xc8, 0x93, 0xbf, 0x65, 0x1e, 0x8c, 0xc4, 0x65, 0x8c, 0x09,
+	0x6b, 0x58, 0x0b, 0x95, 0x52, 0xb1, 0x95, 0x72, 0x0f, 0xd6, 0x4c, 0x53, 0xb1, 0x66, 0xd9, 0x72,
+	0x59, 0x04, 0xc9, 0x36, 0x6c, 0x0c, 0xb8, 0x44, 0x73, 0xec, 0xc1, 0x2b, 0xb5, 0x7b, 0xc4, 0x32,
+	0x8c, 0x27, 0xf0, 0x50, 0xb2, 0x40, 0xb1, 0x43, 0x96, 0xaa, 0x1e, 0xf6, 0x00, 0x7b, 0x02, 0x5f,
+	0x44, 0x71, 0xde, 0x00, 0x67, 0xf8, 0xca, 0x35, 0x40, 0x73, 0x08, 0x5f, 0x04, 0xf1, 0x68, 0x82,
+	0x37, 0x80, 0x54, 0x05, 0x51, 0xa2, 0x6f, 0x99, 0x25, 0x9a, 0x01, 0xff, 0xd9, 0x49, 0xdc, 0xff,
+	0x73, 0x01, 0x5a, 0xcb, 0xf9, 0x70, 0x63, 0xc3, 0x72, 0xe1, 0x2a, 0xe6, 0xc2, 0x85, 0xa1, 0x0f,
+	0x54, 0xa0, 0xa3, 0xbc, 0x4a, 0xf5, 0x38, 0x0b, 0x7d, 0xf9, 0xf5, 0xa1, 0x5f, 0x70, 0xa4, 0xb2,
+	0xec, 0xc8, 0x16, 0x34, 0xa3, 0x60, 0xcc, 0x7a, 0x81, 0xcc, 0x85, 0x36, 0x0f, 0xf9, 0x7f, 0x2c,
+	0xc0, 0xc6, 0x52, 0x56, 0xbe, 0xb5, 0xcd, 0x4b, 0xda, 0x4b, 0xd7, 0xb4, 0x7f, 0x7f, 0x0f, 0xfc,
+	0x18, 0x56, 0xf3, 0xa5, 0x70, 0xa3, 0x6d, 0x6e, 0xc9, 0x4f, 0x84, 0x7a, 0x26, 0xa6, 0xb1, 0xdb,
+	0xf3, 0x16, 0xc1, 0xeb, 0x89, 0x51, 0xba, 0x21, 0x31, 0xfc, 0x5f, 0x17, 0xe0, 0x9d, 0x6b, 0x25,
+	0x85, 0x5b, 0x8c, 0x98, 0x0c, 0x72, 0x13, 0x3b, 0x12, 0x39, 0x31, 0xbb, 0xd4, 0x1c, 0xbb, 0xf9,
+	0x58, 0xf2, 0xad, 0x0a, 0x67, 0xc1, 0xf7, 0xf2, 0xb2, 0xef, 0xbb, 0x50, 0x77, 0x3f, 0xb8, 0x0d,
+	0xab, 0x70, 0x6d, 0xc3, 0x2a, 0xce, 0x37, 0x2c, 0xff, 0x13, 0xa8, 0xd9, 0xf7, 0x16, 0x7c, 0x1c,
+	0x5a, 0x78, 0x61, 0x5a, 0x9f, 0x3f, 0xc6, 0x2c, 0x3c, 0x33, 0xf9, 0x4f, 0x00, 0x32, 0xf4, 0xed,
+	0xfb, 0xb3, 0xff, 0x35, 0x54, 0xcd, 0xb3, 0x0d, 0xfe, 0x33, 0x11, 0x97, 0x4c, 0xbe, 0xe9, 0x1f,
+	0x2d, 0x80, 0x92, 0xd3, 0x24, 0x61, 0xf2, 0x0d, 0xad, 0xdc, 0x08, 0xf8, 0xbf, 0x2b, 0x40, 0xdd,
+	0xbd, 0x64, 0xe1, 0x0d, 0x88, 0x0f, 0x58, 0xac, 0xf8, 0x90, 0xdb, 0x59, 0x1a, 0x34, 0x87, 0x90,
+	0x87, 0x50, 0x71, 0xb7, 0x30, 0xf4, 0xf4, 0xbd, 0xfc, 0x33, 0xd8, 0xae, 0xae, 0xcc, 0x76, 0xac,
+	0xe4, 0x8c, 0x1a, 0xa9, 0xbb, 0x4f, 0x01, 0x32, 0x10, 0x83, 0x38, 0x66, 0x33, 0xd7, 0x03, 0xc7,
+	0x6c, 0x46, 0x6e, 0x43, 0xe5, 0x22, 0x98, 0x4c, 0x99, 0x5d, 0x4e, 0x43, 0x7c, 0x5e, 0x7c, 0x5a,
+	0xf0, 0xff, 0x54, 0x84, 0x9a, 0x7d, 0x16, 0x23, 0x0f, 0xa0, 0xa6, 0x9f, 0xc5, 0xde, 0xe8, 0xb7,
+	0x13, 0x21, 0x8f, 0xe6, 0xab, 0x91, 0xb3, 0xd1, 0xaa, 0x32, 0xef, 0x7e, 0xd6, 0x46, 0x2b, 0x86,
+	0x66, 0x0d, 0xd8, 0xd0, 0x2b, 0x6d, 0x95, 0xb6, 0x57, 0x29, 0x0e, 0xc9, 0x03, 0xe7, 0x65, 0x59,
+	0x6b, 0xb8, 0x93, 0xd7, 0x70, 0xdd, 0xc9, 0x0e, 0x34, 0x73, 0x6a, 0x6f, 0xf0, 0xf2, 0x5e, 0xde,
+	0x4b, 0x9b, 0x1e, 0x5a, 0x9d, 0x49, 0x8f, 0xcc, 0xeb, 0xef, 0x11, 0xaf, 0x27, 0x00, 0x99, 0xca,
+	0xb7, 0xcf, 0xad, 0x9d, 0x4f, 0xa1, 0x66, 0x9f, 0x23, 0xc8, 0x1a, 0x34, 0x4e, 0xda, 0xa7, 0x3f,
+	0x7d, 0x71, 0xd2, 0x6f, 0x9f, 0xb6, 0x56, 0xc8, 0x2a, 0xd4, 0x91, 0x3c, 0xea, 0xf6, 0x4f, 0x5b,
+	0x05, 0x47, 0x9d, 0x74, 0x4f, 0xda, 0xad, 0xe2, 0xce, 0xc7, 0xb0, 0x9a, 0x7f, 0x9c, 0x20, 0x4d,
+	0xa8, 0xf5, 0xf7, 0x4e, 0x0e, 0xf7, 0xbb, 0x3f, 0x31, 0x3f, 0x76, 0x4e, 0xfa, 0xed, 0x83, 0x17,
+	0xb4, 0xdd, 0x2a, 0xec, 0x7c, 0x01, 0x8d, 0xf9, 0xe5, 0x82, 0xd4, 0xa1, 0xbc, 0xdf, 0x39, 0x39,
+	0x6c, 0xad, 0x90, 0x06, 0x54, 0x0e, 0xf6, 0x0e, 0x8e, 0xda, 0xad, 0x02, 0x0e, 0x4f, 0x8f, 0x7b,
+	0xcf, 0xfa, 0xad, 0x22, 0x01, 0xa8, 0xf6, 0xdb, 0x07, 0xb4, 0x7d, 0xda, 0x2a, 0x91, 0x1a, 0x94,
+	0xfa, 0xfd, 0xa3, 0x56, 0x19, 0x7f, 0xd2, 0x46, 0x54, 0x76, 0x9e, 0xc0, 0xc6, 0xd2, 0x29, 0x4e,
+	0xff, 0x71, 0xb4, 0x47, 0xdb, 0xa8, 0xb3, 0x09, 0xb5, 0x1e, 0xed, 0xbc, 0xdc, 0x3b, 0x45, 0xad,
+	0x00, 0xd5, 0xe7, 0xdd, 0x83, 0x2f, 0xdb, 0x87, 0xad, 0xe2, 0x7e, 0xeb, 0x9b, 0x57, 0x9b, 0x85,
+	0xbf, 0xbe, 0xda, 0x2c, 0x7c, 0xf7, 0x6a, 0xb3, 0xf0, 0xdb, 0x7f, 0x6c, 0xae, 0x9c, 0x55, 0xf5,
+	0x13, 0xf1, 0x0f, 0xfe, 0x39, 0x00, 0x5b, 0xf6, 0x98, 0xa7, 0x62, 0x16, 0x00, 0x00,
+}
@@ -0,0 +1,27 @@
+package pb
+
+import (
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// Spec returns the platform in the format of the OCI image specification
+func (p *Platform) Spec() ocispec.Platform {
+	return ocispec.Platform{
+		Architecture: p.Architecture,
+		OS:           p.OS,
+		Variant:      p.Variant,
+		OSVersion:    p.OSVersion,
+		OSFeatures:   p.OSFeatures,
+	}
+}
+
+// PlatformFromSpec returns the platform of the OCI image specification p
+func PlatformFromSpec(p ocispec.Platform) *Platform {
+	return &Platform{
+		Architecture: p.Architecture,
+		OS:           p.OS,
+		Variant:      p.Variant,
+		OSVersion:    p.OSVersion,
+		OSFeatures:   p.OSFeatures,
+	}
+}
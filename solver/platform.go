@@ -0,0 +1,40 @@
+package solver
+
+import (
+	"strings"
+
+	"github.com/containerd/containerd/platforms"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/worker"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// checkPlatform returns an error if the processes of platform p can't be run
+// by the worker described by info. Other architectures than the ones of the
+// worker can be run if the worker has a qemu emulator for them registered in
+// binfmt_misc, emulated is true for them.
+func checkPlatform(p *pb.Platform, info worker.Info) (emulated bool, err error) {
+	if p == nil {
+		return false, nil
+	}
+	spec := platforms.Normalize(p.Spec())
+	emulated, ok := info.Supports(spec)
+	if ok {
+		return emulated, nil
+	}
+	native := info.Platforms
+	if len(native) == 0 {
+		native = []ocispec.Platform{platforms.Default()}
+	}
+	formatted := make([]string, 0, len(native))
+	sameOS := false
+	for _, np := range native {
+		formatted = append(formatted, platforms.Format(np))
+		sameOS = sameOS || np.OS == spec.OS
+	}
+	if !sameOS {
+		return false, errors.Errorf("can't run processes for %s on %s", platforms.Format(spec), strings.Join(formatted, ","))
+	}
+	return false, errors.Errorf("no emulator available for running processes for %s on %s, register a qemu binfmt_misc handler for %s", platforms.Format(spec), strings.Join(formatted, ","), spec.Architecture)
+}
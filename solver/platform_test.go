@@ -0,0 +1,106 @@
+package solver
+
+import (
+	"io"
+	"testing"
+
+	"github.com/containerd/containerd/platforms"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/exporter"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/worker"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestCheckPlatform(t *testing.T) {
+	emulated, err := checkPlatform(nil, worker.Info{})
+	require.NoError(t, err)
+	require.False(t, emulated)
+	emulated, err = checkPlatform(pb.PlatformFromSpec(platforms.Default()), worker.Info{})
+	require.NoError(t, err)
+	require.False(t, emulated)
+
+	p := platforms.Default()
+	if p.OS == "windows" {
+		p.OS = "linux"
+	} else {
+		p.OS = "windows"
+	}
+	_, err = checkPlatform(pb.PlatformFromSpec(p), worker.Info{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't run processes")
+
+	// the platforms of the worker are used instead of the daemon's
+	info := worker.Info{
+		Platforms:         []ocispec.Platform{{OS: "linux", Architecture: "arm64"}},
+		EmulatedPlatforms: []ocispec.Platform{{OS: "linux", Architecture: "s390x"}},
+	}
+	emulated, err = checkPlatform(&pb.Platform{OS: "linux", Architecture: "arm64"}, info)
+	require.NoError(t, err)
+	require.False(t, emulated)
+	emulated, err = checkPlatform(&pb.Platform{OS: "linux", Architecture: "s390x"}, info)
+	require.NoError(t, err)
+	require.True(t, emulated)
+	_, err = checkPlatform(&pb.Platform{OS: "linux", Architecture: "ppc64le"}, info)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no emulator available")
+	_, err = checkPlatform(&pb.Platform{OS: "windows", Architecture: "arm64"}, info)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't run processes")
+}
+
+func TestResolveWorker(t *testing.T) {
+	w1, w2 := &testExecWorker{}, &testExecWorker{}
+	wc := &worker.Controller{}
+	require.NoError(t, wc.Add(w1, worker.Info{ID: "w1"}))
+	require.NoError(t, wc.Add(w2, worker.Info{ID: "w2", Labels: map[string]string{"gpu": "true"}, Platforms: []ocispec.Platform{{OS: "linux", Architecture: "arm64"}}}))
+
+	w, _, err := resolveWorker(wc, &vertex{})
+	require.NoError(t, err)
+	require.True(t, w == w1)
+
+	w, _, err = resolveWorker(wc, &vertex{platform: &pb.Platform{OS: "linux", Architecture: "arm64"}})
+	require.NoError(t, err)
+	require.True(t, w == w2)
+
+	w, _, err = resolveWorker(wc, &vertex{constraints: &pb.WorkerConstraints{Filter: []string{"labels.gpu==true"}}})
+	require.NoError(t, err)
+	require.True(t, w == w2)
+
+	_, _, err = resolveWorker(wc, &vertex{constraints: &pb.WorkerConstraints{Filter: []string{"id==w3"}}})
+	require.Error(t, err)
+	_, _, err = resolveWorker(nil, &vertex{})
+	require.Error(t, err)
+}
+
+type testExecWorker struct {
+	// avoids the pointers to the zero size workers being equal
+	_ int
+}
+
+func (w *testExecWorker) Exec(ctx context.Context, meta worker.Meta, rootfs cache.Mountable, mounts []worker.Mount, stdin io.ReadCloser, stdout, stderr io.WriteCloser) error {
+	return nil
+}
+
+func TestBuildPlatform(t *testing.T) {
+	require.Nil(t, buildPlatform(nil, nil))
+
+	arm := ocispec.Platform{OS: "linux", Architecture: "arm64"}
+	e := &testPlatformExporter{platform: &arm}
+	require.Equal(t, &arm, buildPlatform(nil, e))
+	require.Equal(t, &arm, buildPlatform(&vertex{}, e))
+
+	s390x := ocispec.Platform{OS: "linux", Architecture: "s390x"}
+	require.Equal(t, &s390x, buildPlatform(&vertex{platform: pb.PlatformFromSpec(s390x)}, e))
+}
+
+type testPlatformExporter struct {
+	exporter.ExporterInstance
+	platform *ocispec.Platform
+}
+
+func (e *testPlatformExporter) Platform() *ocispec.Platform {
+	return e.platform
+}
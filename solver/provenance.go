@@ -0,0 +1,134 @@
+package solver
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/moby/buildkit/exporter"
+	digest "github.com/opencontainers/go-digest"
+)
+
+// ExporterProvenanceKey is the key of the JSON encoded Provenance in the
+// exporter metadata
+const ExporterProvenanceKey = exporter.ProvenanceKey
+
+const provenanceType = "https://github.com/moby/buildkit/provenance/v0"
+
+// WithProvenance records how the vertexes of a build were run and passes the
+// document to the exporter
+func WithProvenance() SolverOpt {
+	return func(s *Solver) {
+		s.provenance = true
+	}
+}
+
+// Provenance describes the vertexes that were run to create a build result
+type Provenance struct {
+	Type     string              `json:"_type"`
+	Vertexes []*ProvenanceVertex `json:"vertexes"`
+}
+
+// ProvenanceVertex describes a single vertex that was run during the build.
+// Vertexes loaded from the cache are not included.
+type ProvenanceVertex struct {
+	Digest    digest.Digest   `json:"digest"`
+	Name      string          `json:"name,omitempty"`
+	Inputs    []digest.Digest `json:"inputs,omitempty"`
+	CacheKey  digest.Digest   `json:"cacheKey,omitempty"`
+	Exec      *ProvenanceExec `json:"exec,omitempty"`
+	Worker    string          `json:"worker,omitempty"`
+	Started   time.Time       `json:"started"`
+	Completed time.Time       `json:"completed"`
+}
+
+// ProvenanceExec is the process metadata of an exec vertex
+type ProvenanceExec struct {
+	Args []string `json:"args"`
+	Env  []string `json:"env,omitempty"`
+	Cwd  string   `json:"cwd,omitempty"`
+	User string   `json:"user,omitempty"`
+	// Network and Security are the modes of the exec that are not the
+	// defaults, eg. "host" and "insecure"
+	Network  string   `json:"network,omitempty"`
+	Security string   `json:"security,omitempty"`
+	Devices  []string `json:"devices,omitempty"`
+}
+
+// provenanceOp is implemented by the ops that can add details about how they
+// were run to the provenance
+type provenanceOp interface {
+	describe(*ProvenanceVertex)
+}
+
+// provenance returns the record of the vertex if it was run by vs. Hold
+// vs.mu before calling.
+func (vs *vertexSolver) provenance() *ProvenanceVertex {
+	if vs.executed == nil {
+		return nil
+	}
+	pv := *vs.executed
+	return &pv
+}
+
+// recordExecuted saves the provenance record of the vertex after running it.
+// Hold vs.mu before calling.
+func (vs *vertexSolver) recordExecuted(started, completed time.Time) {
+	pv := &ProvenanceVertex{
+		Digest:    vs.v.Digest(),
+		Name:      vs.v.Name(),
+		Started:   started,
+		Completed: completed,
+	}
+	for _, inp := range vs.v.inputs {
+		pv.Inputs = append(pv.Inputs, inp.vertex.Digest())
+	}
+	if k, err := vs.mainCacheKey(); err == nil {
+		pv.CacheKey = k
+	}
+	if po, ok := vs.op.(provenanceOp); ok {
+		po.describe(pv)
+	}
+	vs.executed = pv
+}
+
+// provenance returns the JSON encoded provenance of the vertexes requested by
+// the job
+func (j *job) provenance() ([]byte, error) {
+	j.l.mu.Lock()
+	defer j.l.mu.Unlock()
+
+	seen := map[digest.Digest]struct{}{}
+	p := &Provenance{Type: provenanceType}
+	var walk func(v *vertex)
+	walk = func(v *vertex) {
+		if _, ok := seen[v.Digest()]; ok {
+			return
+		}
+		seen[v.Digest()] = struct{}{}
+		for _, inp := range v.inputs {
+			walk(inp.vertex)
+		}
+		st, ok := j.l.actives[v.Digest()]
+		if !ok {
+			return
+		}
+		vs, ok := st.solver.(*vertexSolver)
+		if !ok {
+			return
+		}
+		vs.mu.Lock()
+		pv := vs.provenance()
+		vs.mu.Unlock()
+		if pv != nil {
+			p.Vertexes = append(p.Vertexes, pv)
+		}
+	}
+	for _, inp := range j.roots {
+		walk(inp.vertex)
+	}
+	sort.SliceStable(p.Vertexes, func(i, j int) bool {
+		return p.Vertexes[i].Started.Before(p.Vertexes[j].Started)
+	})
+	return json.Marshal(p)
+}
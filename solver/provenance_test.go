@@ -0,0 +1,55 @@
+package solver
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/moby/buildkit/solver/pb"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobProvenance(t *testing.T) {
+	src := &vertex{digest: "src", name: "source"}
+	cached := &vertex{digest: "cached", inputs: []*input{{vertex: src}}}
+	root := &vertex{digest: "root", name: "exec", inputs: []*input{{vertex: cached}}}
+
+	jl := newJobList(newScheduler(0))
+	j := &job{l: jl, roots: []*input{{vertex: root}}}
+
+	now := time.Now()
+	for _, v := range []*vertex{src, cached, root} {
+		vs := &vertexSolver{v: v, baseKey: digest.FromBytes([]byte(v.digest))}
+		for range v.inputs {
+			vs.inputs = append(vs.inputs, &vertexInput{cacheKeys: []digest.Digest{"inputkey"}})
+		}
+		if v == root {
+			vs.op = &execOp{op: &pb.ExecOp{Meta: &pb.Meta{Args: []string{"true"}, Env: []string{"B=2", "A=1"}}}}
+		}
+		if v != cached {
+			now = now.Add(time.Second)
+			vs.recordExecuted(now, now.Add(time.Second))
+		}
+		jl.actives[v.digest] = &state{solver: vs}
+	}
+
+	dt, err := j.provenance()
+	require.NoError(t, err)
+
+	var p Provenance
+	require.NoError(t, json.Unmarshal(dt, &p))
+	require.Equal(t, provenanceType, p.Type)
+	require.Equal(t, 2, len(p.Vertexes))
+
+	require.Equal(t, digest.Digest("src"), p.Vertexes[0].Digest)
+	require.Equal(t, "source", p.Vertexes[0].Name)
+	require.Nil(t, p.Vertexes[0].Exec)
+
+	require.Equal(t, digest.Digest("root"), p.Vertexes[1].Digest)
+	require.Equal(t, []digest.Digest{"cached"}, p.Vertexes[1].Inputs)
+	require.NotEqual(t, digest.Digest(""), p.Vertexes[1].CacheKey)
+	require.Equal(t, []string{"true"}, p.Vertexes[1].Exec.Args)
+	require.Equal(t, []string{"A=1", "B=2"}, p.Vertexes[1].Exec.Env)
+	require.True(t, p.Vertexes[1].Completed.After(p.Vertexes[1].Started))
+}
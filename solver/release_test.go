@@ -0,0 +1,235 @@
+package solver
+
+import (
+	gocontext "context"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/containerd/containerd/mount"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/worker"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+// TestExecReleaseOnError checks the invariant that every ref created by a
+// failed exec is released exactly once, even if the request is cancelled
+func TestExecReleaseOnError(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		execErr    error
+		failCommit int
+		cancel     bool
+	}{
+		{name: "exec", execErr: errors.Errorf("exec failed")},
+		{name: "cancelled", execErr: context.Canceled, cancel: true},
+		{name: "commit", failCommit: 3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := &refTracker{}
+			e := &execOp{
+				op: &pb.ExecOp{
+					Meta: &pb.Meta{Args: []string{"true"}},
+					Mounts: []*pb.Mount{
+						{Dest: pb.RootMount, Input: 0, Output: 0},
+						{Dest: "/foo", Input: pb.Empty, Output: 1},
+						{Dest: "/bar", Input: pb.Empty, Output: 2},
+					},
+				},
+				cm: &trackingCacheManager{tr: tr, failCommit: tc.failCommit},
+				w:  &failingWorker{err: tc.execErr},
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.cancel {
+				cancel()
+			}
+			root := tr.newImmutable()
+			refs, err := e.run(ctx, []Reference{root}, func(error) bool { return false })
+			require.Error(t, err)
+			require.Nil(t, refs)
+			tr.waitReleased(t, root.id)
+		})
+	}
+}
+
+func TestExecReleaseOnSuccess(t *testing.T) {
+	tr := &refTracker{}
+	e := &execOp{
+		op: &pb.ExecOp{
+			Meta: &pb.Meta{Args: []string{"true"}},
+			Mounts: []*pb.Mount{
+				{Dest: pb.RootMount, Input: pb.Empty, Output: 0},
+				{Dest: "/foo", Input: pb.Empty, Output: 1},
+			},
+		},
+		cm: &trackingCacheManager{tr: tr},
+		w:  &failingWorker{},
+	}
+	refs, err := e.run(context.Background(), nil, func(error) bool { return false })
+	require.NoError(t, err)
+	require.Equal(t, 2, len(refs))
+
+	// the committed outputs are owned by the caller
+	time.Sleep(10 * time.Millisecond)
+	tr.mu.Lock()
+	for id, n := range tr.released {
+		require.Equal(t, 1, n, "mutable ref %s", id)
+	}
+	require.Equal(t, 2, len(tr.released))
+	tr.mu.Unlock()
+
+	for _, r := range refs {
+		require.NoError(t, r.Release(context.Background()))
+	}
+	tr.waitReleased(t)
+}
+
+// refTracker records the created refs and how many times each was released
+type refTracker struct {
+	mu       sync.Mutex
+	next     int
+	created  []string
+	released map[string]int
+}
+
+func (tr *refTracker) add() string {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.next++
+	id := fmt.Sprintf("ref%d", tr.next)
+	tr.created = append(tr.created, id)
+	return id
+}
+
+func (tr *refTracker) release(ctx context.Context, id string) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	if tr.released == nil {
+		tr.released = map[string]int{}
+	}
+	tr.released[id]++
+	return nil
+}
+
+func (tr *refTracker) newImmutable() *trackedImmutableRef {
+	return &trackedImmutableRef{tr: tr, id: tr.add()}
+}
+
+// waitReleased waits for the background releases and checks that all the
+// refs except the ones owned by the test were released exactly once
+func (tr *refTracker) waitReleased(t *testing.T, owned ...string) {
+	skip := map[string]struct{}{}
+	for _, id := range owned {
+		skip[id] = struct{}{}
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		tr.mu.Lock()
+		pending := 0
+		for _, id := range tr.created {
+			if _, ok := skip[id]; ok {
+				continue
+			}
+			n := tr.released[id]
+			require.True(t, n <= 1, "ref %s released %d times", id, n)
+			if n == 0 {
+				pending++
+			}
+		}
+		for id := range skip {
+			require.Equal(t, 0, tr.released[id], "input ref %s released", id)
+		}
+		tr.mu.Unlock()
+		if pending == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d refs were not released", pending)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+type trackedImmutableRef struct {
+	cache.ImmutableRef
+	tr *refTracker
+	id string
+}
+
+func (r *trackedImmutableRef) ID() string {
+	return r.id
+}
+
+func (r *trackedImmutableRef) Parent() cache.ImmutableRef {
+	return nil
+}
+
+func (r *trackedImmutableRef) Mount(ctx context.Context, readonly bool) ([]mount.Mount, error) {
+	return nil, nil
+}
+
+func (r *trackedImmutableRef) Release(ctx context.Context) error {
+	return r.tr.release(ctx, r.id)
+}
+
+type trackedMutableRef struct {
+	cache.MutableRef
+	tr        *refTracker
+	id        string
+	commitErr bool
+}
+
+func (r *trackedMutableRef) ID() string {
+	return r.id
+}
+
+func (r *trackedMutableRef) Mount(ctx context.Context, readonly bool) ([]mount.Mount, error) {
+	return nil, nil
+}
+
+// Commit consumes the mutable ref so it is counted as released
+func (r *trackedMutableRef) Commit(ctx context.Context) (cache.ImmutableRef, error) {
+	if r.commitErr {
+		return nil, errors.Errorf("commit failed")
+	}
+	if err := r.tr.release(ctx, r.id); err != nil {
+		return nil, err
+	}
+	return r.tr.newImmutable(), nil
+}
+
+func (r *trackedMutableRef) Release(ctx context.Context) error {
+	return r.tr.release(ctx, r.id)
+}
+
+type trackingCacheManager struct {
+	cache.Manager
+	tr *refTracker
+	// failCommit is the number of the created mutable ref that fails to
+	// commit, starting from 1
+	failCommit int
+	mutables   int
+}
+
+func (cm *trackingCacheManager) New(ctx gocontext.Context, s cache.ImmutableRef, opts ...cache.RefOption) (cache.MutableRef, error) {
+	cm.mutables++
+	return &trackedMutableRef{tr: cm.tr, id: cm.tr.add(), commitErr: cm.mutables == cm.failCommit}, nil
+}
+
+type failingWorker struct {
+	err error
+}
+
+func (w *failingWorker) Exec(ctx context.Context, meta worker.Meta, rootfs cache.Mountable, mounts []worker.Mount, stdin io.ReadCloser, stdout, stderr io.WriteCloser) error {
+	return w.err
+}
@@ -0,0 +1,98 @@
+package solver
+
+import (
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/worker"
+)
+
+// ResourcePolicy defines the daemon limits for the resources of exec ops.
+// Default is used for the values not set by the exec. An exec can not go
+// over Max. Zero values in Max are not limited. The CPU limit of Max is the
+// ratio of CPUQuota to CPUPeriod, an exec can use any period with a quota
+// up to that ratio.
+type ResourcePolicy struct {
+	Default worker.Resources
+	Max     worker.Resources
+}
+
+// Apply returns the resources an exec runs with
+func (p ResourcePolicy) Apply(r worker.Resources) worker.Resources {
+	r.CPUShares = limitValue(r.CPUShares, p.Default.CPUShares, p.Max.CPUShares)
+	r.CPUQuota = limitValue(r.CPUQuota, p.Default.CPUQuota, 0)
+	r.CPUPeriod = limitValue(r.CPUPeriod, p.Default.CPUPeriod, 0)
+	if p.Max.CPUQuota > 0 {
+		maxPeriod := p.Max.CPUPeriod
+		if maxPeriod == 0 {
+			maxPeriod = defaultCPUPeriod
+		}
+		if r.CPUPeriod == 0 {
+			r.CPUPeriod = defaultCPUPeriod
+		}
+		if max := p.Max.CPUQuota * r.CPUPeriod / maxPeriod; r.CPUQuota <= 0 || r.CPUQuota > max {
+			r.CPUQuota = max
+		}
+	}
+	r.Memory = limitValue(r.Memory, p.Default.Memory, p.Max.Memory)
+	r.PidsLimit = limitValue(r.PidsLimit, p.Default.PidsLimit, p.Max.PidsLimit)
+
+	ulimits := make([]worker.Ulimit, 0, len(r.Ulimits)+len(p.Default.Ulimits))
+	set := map[string]struct{}{}
+	for _, u := range r.Ulimits {
+		ulimits = append(ulimits, u)
+		set[u.Name] = struct{}{}
+	}
+	for _, u := range p.Default.Ulimits {
+		if _, ok := set[u.Name]; !ok {
+			ulimits = append(ulimits, u)
+		}
+	}
+	for i, u := range ulimits {
+		for _, max := range p.Max.Ulimits {
+			if max.Name != u.Name {
+				continue
+			}
+			u.Soft = limitValue(u.Soft, 0, max.Soft)
+			u.Hard = limitValue(u.Hard, 0, max.Hard)
+			if u.Soft > u.Hard {
+				u.Soft = u.Hard
+			}
+			ulimits[i] = u
+		}
+	}
+	r.Ulimits = ulimits
+	return r
+}
+
+// defaultCPUPeriod is the CPU period of the kernel in microseconds
+const defaultCPUPeriod = 100000
+
+func limitValue(v, def, max int64) int64 {
+	if v == 0 {
+		v = def
+	}
+	if max > 0 && (v == 0 || v > max) {
+		v = max
+	}
+	return v
+}
+
+func resourcesFromPB(r *pb.Resources) worker.Resources {
+	if r == nil {
+		return worker.Resources{}
+	}
+	out := worker.Resources{
+		CPUShares: r.CpuShares,
+		CPUQuota:  r.CpuQuota,
+		CPUPeriod: r.CpuPeriod,
+		Memory:    r.Memory,
+		PidsLimit: r.PidsLimit,
+	}
+	for _, u := range r.Ulimits {
+		out.Ulimits = append(out.Ulimits, worker.Ulimit{
+			Name: u.Name,
+			Soft: u.Soft,
+			Hard: u.Hard,
+		})
+	}
+	return out
+}
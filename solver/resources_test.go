@@ -0,0 +1,62 @@
+package solver
+
+import (
+	"testing"
+
+	"github.com/moby/buildkit/worker"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourcePolicy(t *testing.T) {
+	p := ResourcePolicy{
+		Default: worker.Resources{
+			Memory:  100,
+			Ulimits: []worker.Ulimit{{Name: "nofile", Soft: 10, Hard: 20}},
+		},
+		Max: worker.Resources{
+			Memory:    200,
+			PidsLimit: 50,
+			Ulimits:   []worker.Ulimit{{Name: "nofile", Soft: 15, Hard: 30}},
+		},
+	}
+
+	r := p.Apply(worker.Resources{})
+	require.Equal(t, int64(100), r.Memory)
+	require.Equal(t, int64(50), r.PidsLimit)
+	require.Equal(t, int64(0), r.CPUShares)
+	require.Equal(t, []worker.Ulimit{{Name: "nofile", Soft: 10, Hard: 20}}, r.Ulimits)
+
+	r = p.Apply(worker.Resources{
+		Memory:    300,
+		PidsLimit: 10,
+		CPUShares: 512,
+		Ulimits:   []worker.Ulimit{{Name: "nofile", Soft: 40, Hard: 40}, {Name: "nproc", Soft: 1, Hard: 2}},
+	})
+	require.Equal(t, int64(200), r.Memory)
+	require.Equal(t, int64(10), r.PidsLimit)
+	require.Equal(t, int64(512), r.CPUShares)
+	require.Equal(t, []worker.Ulimit{{Name: "nofile", Soft: 15, Hard: 30}, {Name: "nproc", Soft: 1, Hard: 2}}, r.Ulimits)
+}
+
+func TestResourcePolicyCPU(t *testing.T) {
+	p := ResourcePolicy{
+		Max: worker.Resources{CPUQuota: 200000, CPUPeriod: 100000},
+	}
+
+	r := p.Apply(worker.Resources{})
+	require.Equal(t, int64(200000), r.CPUQuota)
+	require.Equal(t, int64(100000), r.CPUPeriod)
+
+	// a shorter period can't get more CPUs than the limit
+	r = p.Apply(worker.Resources{CPUQuota: 100000, CPUPeriod: 10000})
+	require.Equal(t, int64(20000), r.CPUQuota)
+	require.Equal(t, int64(10000), r.CPUPeriod)
+
+	r = p.Apply(worker.Resources{CPUQuota: 1000000, CPUPeriod: 1000000})
+	require.Equal(t, int64(1000000), r.CPUQuota)
+	require.Equal(t, int64(1000000), r.CPUPeriod)
+
+	r = p.Apply(worker.Resources{CPUQuota: 50000})
+	require.Equal(t, int64(50000), r.CPUQuota)
+	require.Equal(t, int64(100000), r.CPUPeriod)
+}
@@ -0,0 +1,131 @@
+package solver
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/containerd/containerd/namespaces"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/exporter"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+// TestSolveResultsExports checks that every export gets the ref of its result
+// and that the main exporter gets all the named results
+func TestSolveResultsExports(t *testing.T) {
+	ctx := namespaces.WithNamespace(context.Background(), "buildkit-test")
+	cm, ic, cleanup := newTestInstructionCache(t)
+	defer cleanup()
+
+	ops := map[digest.Digest]*namedOp{}
+	definition := func(name string) Vertex {
+		v := &vertex{digest: digest.FromString(name)}
+		ops[v.digest] = &namedOp{cm: cm, name: name}
+		return &vertex{digest: digest.FromString("definition " + name), inputs: []*input{{vertex: v}}}
+	}
+	s := &Solver{
+		jobs:  newJobList(newScheduler(0)),
+		cache: ic,
+		resolve: func(v Vertex) (Op, error) {
+			return ops[v.Digest()], nil
+		},
+	}
+
+	main := &testExporter{name: "main"}
+	mainExport := &testExporter{name: "main export"}
+	fooExport := &testExporter{name: "foo export"}
+	barExport := &testExporter{name: "bar export"}
+	_, err := s.Solve(ctx, "results", SolveRequest{
+		Definition: definition("main"),
+		Exporter:   main,
+		Results: map[string]Vertex{
+			"foo": definition("foo"),
+			"bar": definition("bar"),
+		},
+		Exports: []Export{
+			{Exporter: mainExport},
+			{Result: "foo", Exporter: fooExport},
+			{Result: "bar", Exporter: barExport},
+		},
+	})
+	require.NoError(t, err)
+
+	id := func(name string) string {
+		return ops[digest.FromString(name)].id()
+	}
+	require.Equal(t, []string{id("main")}, main.exported)
+	require.Equal(t, []string{id("main")}, mainExport.exported)
+	require.Equal(t, []string{id("foo")}, fooExport.exported)
+	require.Equal(t, []string{id("bar")}, barExport.exported)
+
+	results, ok := main.opt[exporter.ResultsKey].(map[string]cache.ImmutableRef)
+	require.True(t, ok)
+	require.Equal(t, 2, len(results))
+	require.Equal(t, id("foo"), results["foo"].ID())
+	require.Equal(t, id("bar"), results["bar"].ID())
+	// the exporters of the named results don't get the other results
+	_, ok = fooExport.opt[exporter.ResultsKey]
+	require.False(t, ok)
+
+	_, err = s.Solve(ctx, "missing", SolveRequest{
+		Definition: definition("main"),
+		Exports:    []Export{{Result: "baz", Exporter: &testExporter{}}},
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "result baz not found")
+}
+
+// namedOp creates an empty result with a cache key of its own
+type namedOp struct {
+	cm   cache.Manager
+	name string
+	mu   sync.Mutex
+	ref  string
+}
+
+func (o *namedOp) CacheKey(ctx context.Context) (digest.Digest, error) {
+	return digest.FromString("named " + o.name), nil
+}
+
+func (o *namedOp) ContentKeys(context.Context, [][]digest.Digest, []Reference) ([]digest.Digest, error) {
+	return nil, nil
+}
+
+func (o *namedOp) Run(ctx context.Context, inputs []Reference) ([]Reference, error) {
+	active, err := o.cm.New(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	ref, err := active.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	o.mu.Lock()
+	o.ref = ref.ID()
+	o.mu.Unlock()
+	return []Reference{ref}, nil
+}
+
+func (o *namedOp) id() string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.ref
+}
+
+type testExporter struct {
+	name     string
+	exported []string
+	opt      map[string]interface{}
+}
+
+func (e *testExporter) Name() string {
+	return e.name
+}
+
+func (e *testExporter) Export(ctx context.Context, ref cache.ImmutableRef, opt map[string]interface{}) (map[string]string, error) {
+	e.exported = append(e.exported, ref.ID())
+	e.opt = opt
+	return nil, nil
+}
@@ -0,0 +1,127 @@
+package solver
+
+import (
+	"sync"
+
+	"golang.org/x/net/context"
+)
+
+// scheduler limits the number of operations that can run in parallel. When
+// the limit has been reached, waiting operations are released in round-robin
+// order between the jobs that requested them so that a single large build
+// can't starve other builds running at the same time.
+type scheduler struct {
+	mu      sync.Mutex
+	limit   int
+	active  int
+	waiting map[string][]chan struct{}
+	queue   []string // jobs with waiting operations, in release order
+}
+
+// nestedOp is implemented by the ops that solve other vertexes while they
+// run, they are not limited by the scheduler
+type nestedOp interface {
+	solvesNested()
+}
+
+// newScheduler returns a new scheduler. A limit of zero or less disables the
+// parallelism limit.
+func newScheduler(limit int) *scheduler {
+	return &scheduler{
+		limit:   limit,
+		waiting: make(map[string][]chan struct{}),
+	}
+}
+
+// acquire blocks until an operation for job id is allowed to run. The
+// returned function needs to be called when the operation has completed.
+func (s *scheduler) acquire(ctx context.Context, id string) (func(), error) {
+	if s.limit <= 0 {
+		return func() {}, nil
+	}
+
+	s.mu.Lock()
+	if s.active < s.limit && len(s.queue) == 0 {
+		s.active++
+		s.mu.Unlock()
+		return s.releaseFunc(), nil
+	}
+
+	ch := make(chan struct{})
+	if _, ok := s.waiting[id]; !ok {
+		s.queue = append(s.queue, id)
+	}
+	s.waiting[id] = append(s.waiting[id], ch)
+	s.mu.Unlock()
+
+	metricQueueLength.Inc()
+	defer metricQueueLength.Dec()
+
+	select {
+	case <-ch:
+		return s.releaseFunc(), nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		select {
+		case <-ch: // slot was granted while cancelling
+			s.active--
+			s.next()
+		default:
+			s.remove(id, ch)
+		}
+		s.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+func (s *scheduler) releaseFunc() func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			s.active--
+			s.next()
+			s.mu.Unlock()
+		})
+	}
+}
+
+// next releases waiting operations while there are free slots. Hold s.mu
+// before calling.
+func (s *scheduler) next() {
+	for s.active < s.limit && len(s.queue) > 0 {
+		id := s.queue[0]
+		s.queue = s.queue[1:]
+		chs := s.waiting[id]
+		if len(chs) > 1 {
+			s.waiting[id] = chs[1:]
+			s.queue = append(s.queue, id)
+		} else {
+			delete(s.waiting, id)
+		}
+		s.active++
+		close(chs[0])
+	}
+}
+
+// remove deletes a waiting operation. Hold s.mu before calling.
+func (s *scheduler) remove(id string, ch chan struct{}) {
+	chs := s.waiting[id]
+	for i, c := range chs {
+		if c == ch {
+			chs = append(chs[:i], chs[i+1:]...)
+			break
+		}
+	}
+	if len(chs) > 0 {
+		s.waiting[id] = chs
+		return
+	}
+	delete(s.waiting, id)
+	for i, q := range s.queue {
+		if q == id {
+			s.queue = append(s.queue[:i], s.queue[i+1:]...)
+			break
+		}
+	}
+}
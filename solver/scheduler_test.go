@@ -0,0 +1,201 @@
+package solver
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/containerd/containerd/namespaces"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestSchedulerLimit(t *testing.T) {
+	s := newScheduler(2)
+	ctx := context.Background()
+
+	r1, err := s.acquire(ctx, "a")
+	require.NoError(t, err)
+	r2, err := s.acquire(ctx, "a")
+	require.NoError(t, err)
+
+	acquired := make(chan struct{})
+	go func() {
+		r, err := s.acquire(ctx, "a")
+		require.NoError(t, err)
+		close(acquired)
+		r()
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("acquired over the limit")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	r1()
+	r1() // releasing twice is a no-op
+
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for release")
+	}
+	r2()
+
+	s.mu.Lock()
+	require.Equal(t, 0, s.active)
+	s.mu.Unlock()
+}
+
+func TestSchedulerFairness(t *testing.T) {
+	s := newScheduler(1)
+	ctx := context.Background()
+
+	release, err := s.acquire(ctx, "a")
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+
+	queued := 0
+	enqueue := func(id string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r, err := s.acquire(ctx, id)
+			require.NoError(t, err)
+			mu.Lock()
+			order = append(order, id)
+			mu.Unlock()
+			r()
+		}()
+		queued++
+		// wait until the operation is queued
+		for waitingCount(s) != queued {
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	enqueue("a")
+	enqueue("a")
+	enqueue("b")
+
+	release()
+	wg.Wait()
+
+	require.Equal(t, []string{"a", "b", "a"}, order)
+}
+
+func TestSchedulerCancel(t *testing.T) {
+	s := newScheduler(1)
+
+	release, err := s.acquire(context.Background(), "a")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error)
+	go func() {
+		_, err := s.acquire(ctx, "b")
+		errCh <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	require.Equal(t, context.Canceled, <-errCh)
+
+	s.mu.Lock()
+	require.Equal(t, 0, len(s.queue))
+	require.Equal(t, 0, len(s.waiting))
+	s.mu.Unlock()
+
+	release()
+
+	r, err := s.acquire(context.Background(), "b")
+	require.NoError(t, err)
+	r()
+}
+
+func TestSchedulerUnlimited(t *testing.T) {
+	s := newScheduler(0)
+	for i := 0; i < 10; i++ {
+		_, err := s.acquire(context.Background(), "a")
+		require.NoError(t, err)
+	}
+}
+
+func waitingCount(s *scheduler) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, chs := range s.waiting {
+		n += len(chs)
+	}
+	return n
+}
+
+// TestSchedulerNestedOp checks that an op solving a nested vertex doesn't
+// hold the slot the nested vertex waits for
+func TestSchedulerNestedOp(t *testing.T) {
+	ctx := namespaces.WithNamespace(context.Background(), "buildkit-test")
+	cm, ic, cleanup := newTestInstructionCache(t)
+	defer cleanup()
+
+	jl := newJobList(newScheduler(1))
+	j, ctx := newTestJob(t, ctx, jl, ic)
+	child := &vertex{digest: "child"}
+	parent := &vertex{digest: "parent"}
+	var resolve ResolveOpFunc
+	resolve = func(v Vertex) (Op, error) {
+		if v.Digest() == parent.digest {
+			return &nestedTestOp{jl: jl, parent: parent.digest, child: child, resolve: resolve, ic: ic}, nil
+		}
+		return &commitOp{cm: cm}, nil
+	}
+	require.NoError(t, j.load(parent, resolve))
+
+	done := make(chan error, 1)
+	go func() {
+		ref, err := j.getRef(ctx, parent, 0)
+		if err == nil {
+			err = ref.Release(ctx)
+		}
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("nested vertex waited for the slot of its parent")
+	}
+	releaseTestJob(j)
+}
+
+// nestedTestOp solves the child vertex like a build op
+type nestedTestOp struct {
+	jl      *jobList
+	parent  digest.Digest
+	child   *vertex
+	resolve ResolveOpFunc
+	ic      InstructionCache
+}
+
+func (o *nestedTestOp) solvesNested() {}
+
+func (o *nestedTestOp) CacheKey(ctx context.Context) (digest.Digest, error) {
+	return digest.FromBytes([]byte("nested")), nil
+}
+
+func (o *nestedTestOp) ContentKeys(context.Context, [][]digest.Digest, []Reference) ([]digest.Digest, error) {
+	return nil, nil
+}
+
+func (o *nestedTestOp) Run(ctx context.Context, inputs []Reference) ([]Reference, error) {
+	ref, err := o.jl.loadAndSolveChildVertex(ctx, o.parent, o.child, 0, o.resolve, o.ic)
+	if err != nil {
+		return nil, err
+	}
+	return []Reference{ref}, nil
+}
@@ -0,0 +1,134 @@
+package solver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/containerd/containerd/mount"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/secrets"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+)
+
+// sessionCaller returns the client session of the build that is running in
+// ctx
+func sessionCaller(ctx context.Context, sm *session.Manager) (session.Caller, error) {
+	if sm == nil {
+		return nil, errors.Errorf("session manager not configured")
+	}
+	id := session.FromContext(ctx)
+	if id == "" {
+		return nil, errors.Errorf("no session")
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return sm.Get(timeoutCtx, id)
+}
+
+// getSecret loads the secret for a secret mount from the client session of
+// the build. A nil value is returned for missing optional secrets.
+func getSecret(ctx context.Context, sm *session.Manager, opt *pb.SecretOpt) ([]byte, error) {
+	if opt == nil || opt.ID == "" {
+		return nil, errors.Errorf("secret mount requires an id")
+	}
+	caller, err := sessionCaller(ctx, sm)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not access secret %s", opt.ID)
+	}
+
+	dt, err := secrets.GetSecret(ctx, caller, opt.ID)
+	if err != nil {
+		if errors.Cause(err) == secrets.ErrNotFound && opt.Optional {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return dt, nil
+}
+
+// secretMount is a mountable for a single secret file. The file is written to
+// a tmpfs on the host so the secret value is never stored to disk or to the
+// snapshots of the exec.
+type secretMount struct {
+	data []byte
+	opt  pb.SecretOpt
+	dir  string
+}
+
+func newSecretMount(data []byte, opt *pb.SecretOpt) *secretMount {
+	return &secretMount{
+		data: data,
+		opt:  *opt,
+	}
+}
+
+func (sm *secretMount) Mount(ctx context.Context, readonly bool) ([]mount.Mount, error) {
+	if sm.dir != "" {
+		return sm.mounts(), nil
+	}
+	dir, err := ioutil.TempDir("", "buildkit-secrets")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create temp dir for secret")
+	}
+	if err := os.Chmod(dir, 0711); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	tmpMount := mount.Mount{
+		Type:    "tmpfs",
+		Source:  "tmpfs",
+		Options: []string{"nodev", "nosuid", "noexec", "mode=0711", "size=1m"},
+	}
+	if err := tmpMount.Mount(dir); err != nil {
+		os.RemoveAll(dir)
+		return nil, errors.Wrap(err, "failed to mount tmpfs for secret")
+	}
+	sm.dir = dir
+
+	fp := filepath.Join(dir, "secret")
+	mode := os.FileMode(sm.opt.Mode & 0777)
+	if mode == 0 {
+		mode = 0400
+	}
+	if err := ioutil.WriteFile(fp, sm.data, mode); err != nil {
+		sm.release()
+		return nil, errors.Wrap(err, "failed to write secret")
+	}
+	if err := os.Chown(fp, int(sm.opt.Uid), int(sm.opt.Gid)); err != nil {
+		sm.release()
+		return nil, errors.Wrap(err, "failed to change owner of secret")
+	}
+	if err := os.Chmod(fp, mode); err != nil {
+		sm.release()
+		return nil, err
+	}
+	return sm.mounts(), nil
+}
+
+func (sm *secretMount) mounts() []mount.Mount {
+	return []mount.Mount{{
+		Type:    "bind",
+		Source:  filepath.Join(sm.dir, "secret"),
+		Options: []string{"nodev", "nosuid", "noexec", "rbind", "ro"},
+	}}
+}
+
+// release unmounts the tmpfs and removes the secret from the host
+func (sm *secretMount) release() {
+	if sm.dir == "" {
+		return
+	}
+	if err := mount.Unmount(sm.dir, 0); err != nil {
+		logrus.Errorf("failed to unmount secret dir %s: %v", sm.dir, err)
+		return
+	}
+	os.RemoveAll(sm.dir)
+	sm.dir = ""
+}
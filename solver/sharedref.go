@@ -4,6 +4,7 @@ import (
 	"sync"
 
 	"github.com/moby/buildkit/cache"
+	"github.com/pkg/errors"
 	"golang.org/x/net/context"
 )
 
@@ -76,7 +77,11 @@ func toImmutableRef(ref Reference) (cache.ImmutableRef, bool) {
 	if !ok {
 		return nil, false
 	}
-	return &immutableRef{immutable, ref.Release}, true
+	ir := &immutableRef{immutable, ref.Release}
+	if fr, ok := immutable.(cache.FileReader); ok {
+		return &fileReaderRef{ir, fr}, true
+	}
+	return ir, true
 }
 
 type immutableRef struct {
@@ -87,3 +92,27 @@ type immutableRef struct {
 func (ir *immutableRef) Release(ctx context.Context) error {
 	return ir.release(ctx)
 }
+
+// Resolve resolves the origin if it is a lazy ref so that the wrapper can be
+// used as the parent of new refs
+func (ir *immutableRef) Resolve(ctx context.Context) (cache.ImmutableRef, error) {
+	return cache.Resolve(ctx, ir.ImmutableRef)
+}
+
+// fileReaderRef is an immutableRef that can read files without mounting
+type fileReaderRef struct {
+	*immutableRef
+	cache.FileReader
+}
+
+func toImmutableRefs(refs map[string]Reference) (map[string]cache.ImmutableRef, error) {
+	out := make(map[string]cache.ImmutableRef, len(refs))
+	for name, ref := range refs {
+		ir, ok := toImmutableRef(ref)
+		if !ok {
+			return nil, errors.Errorf("invalid reference for result %s: %T", name, ref)
+		}
+		out[name] = ir
+	}
+	return out, nil
+}
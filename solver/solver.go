@@ -1,20 +1,30 @@
 package solver
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
 	"sync"
+	"time"
 
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/rootfs"
 	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/cache/cacheimport"
+	"github.com/moby/buildkit/cache/metadata"
 	"github.com/moby/buildkit/client"
 	"github.com/moby/buildkit/exporter"
 	"github.com/moby/buildkit/frontend"
+	"github.com/moby/buildkit/identity"
+	"github.com/moby/buildkit/session"
 	"github.com/moby/buildkit/solver/pb"
 	"github.com/moby/buildkit/source"
 	"github.com/moby/buildkit/util/bgfunc"
+	"github.com/moby/buildkit/util/entitlements"
 	"github.com/moby/buildkit/util/progress"
+	"github.com/moby/buildkit/util/tracing"
 	"github.com/moby/buildkit/worker"
 	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/net/context"
@@ -24,28 +34,133 @@ import (
 type LLBOpt struct {
 	SourceManager    *source.Manager
 	CacheManager     cache.Manager // TODO: this shouldn't be needed before instruction cache
-	Worker           worker.Worker
+	MetadataStore    *metadata.Store
+	Workers          *worker.Controller
 	InstructionCache InstructionCache
 	ImageSource      source.Source
+	MaxParallelism   int
+	CacheExporter    *cacheimport.CacheExporter
+	CacheImporter    *cacheimport.CacheImporter
+	SessionManager   *session.Manager
+	ResourcePolicy   ResourcePolicy
+	// ReadonlyRootFS runs all execs with a read-only root filesystem
+	ReadonlyRootFS bool
+	// DefaultNetMode is the network of the execs that don't set one. The
+	// execs with NetModeHost don't require the network.host entitlement.
+	DefaultNetMode worker.NetMode
+	// ProxyEnv is a list of KEY=VALUE proxy variables added to the execs. The
+	// variables are not part of the cache key.
+	ProxyEnv []string
+	// HostMounts are the host paths, and the paths under them, that the
+	// execs can bind read-only
+	HostMounts []string
+	// SecurityProfile confines the execs that don't set their own profiles
+	SecurityProfile worker.SecurityProfile
+	// CgroupParent is the cgroup the execs and the cgroups of the builds are
+	// created under
+	CgroupParent string
+	// DNS configures the resolv.conf of the execs instead of the one of the
+	// host
+	DNS *worker.DNSConfig
+	// Provenance passes a record of the run vertexes to the exporters
+	Provenance bool
+	// KeepCompleted keeps the results of the completed vertexes in the cache
+	// when the solve fails so that the next solve can continue from them
+	KeepCompleted bool
+	// Differ and Applier are used by the diff op to create the changes between
+	// two refs
+	Differ  rootfs.MountDiffer
+	Applier rootfs.Applier
+	// ContentStore is the content store of the Differ
+	ContentStore content.Store
+	// MergeOverlay stacks the inputs of the merge ops with overlayfs instead
+	// of copying their files. The snapshotter has to be overlayfs.
+	MergeOverlay bool
 }
 
 func NewLLBSolver(opt LLBOpt) *Solver {
 	var s *Solver
-	s = New(func(v Vertex) (Op, error) {
+	cms := newCacheMounts(opt.CacheManager, opt.MetadataStore)
+	cps := newCheckpoints(opt.CacheManager, opt.MetadataStore)
+	resolve := func(v Vertex) (Op, error) {
 		switch op := v.Sys().(type) {
 		case *pb.Op_Source:
 			return newSourceOp(v, op, opt.SourceManager)
 		case *pb.Op_Exec:
-			return newExecOp(v, op, opt.CacheManager, opt.Worker)
+			w, info, err := resolveWorker(opt.Workers, v)
+			if err != nil {
+				return nil, err
+			}
+			return newExecOp(v, op, opt.CacheManager, w, cms, opt.SessionManager, execOpt{
+				resources:      opt.ResourcePolicy,
+				readonlyRootFS: opt.ReadonlyRootFS,
+				netMode:        opt.DefaultNetMode,
+				proxyEnv:       opt.ProxyEnv,
+				checkpoints:    cps,
+				hostMounts:     opt.HostMounts,
+
+				securityProfile: opt.SecurityProfile,
+				cgroupParent:    opt.CgroupParent,
+				dns:             opt.DNS,
+				workerInfo:      info,
+			})
 		case *pb.Op_Build:
 			return newBuildOp(v, op, s)
+		case *pb.Op_File:
+			return newFileOp(v, op, opt.CacheManager)
+		case *pb.Op_Merge:
+			return newMergeOp(v, op, opt.CacheManager, opt.MetadataStore, opt.MergeOverlay)
+		case *pb.Op_Diff:
+			return newDiffOp(v, op, opt.CacheManager, opt.Differ, opt.Applier, opt.ContentStore)
 		default:
 			return nil, nil
 		}
-	}, opt.InstructionCache, opt.ImageSource)
+	}
+	opts := []SolverOpt{
+		WithMaxParallelism(opt.MaxParallelism),
+		WithCacheExporter(opt.CacheExporter),
+		WithCacheImporter(opt.CacheImporter),
+	}
+	if opt.Workers != nil {
+		// the processes of the frontends are run by the default worker
+		if w, err := opt.Workers.Default(); err == nil {
+			opts = append(opts, WithWorker(w))
+		}
+	}
+	if opt.Provenance {
+		opts = append(opts, WithProvenance())
+	}
+	if opt.KeepCompleted {
+		opts = append(opts, WithKeepCompleted())
+	}
+	if opt.CacheManager != nil {
+		opts = append(opts, WithLeaser(opt.CacheManager))
+	}
+	s = New(resolve, opt.InstructionCache, opt.ImageSource, opts...)
 	return s
 }
 
+// resolveWorker returns the worker that runs the processes of the vertex
+func resolveWorker(workers *worker.Controller, v Vertex) (worker.Worker, worker.Info, error) {
+	if workers == nil {
+		return nil, worker.Info{}, errors.Errorf("no workers for running processes")
+	}
+	var p *ocispec.Platform
+	if vp := vertexPlatform(v); vp != nil {
+		spec := vp.Spec()
+		p = &spec
+	}
+	var filters []string
+	if c := vertexConstraints(v); c != nil {
+		filters = c.Filter
+	}
+	w, info, err := workers.Resolve(p, filters...)
+	if err != nil {
+		return nil, worker.Info{}, errors.Wrapf(err, "failed to find a worker for %s", v.Name())
+	}
+	return w, info, nil
+}
+
 // ResolveOpFunc finds an Op implementation for a vertex
 type ResolveOpFunc func(Vertex) (Op, error)
 
@@ -70,40 +185,221 @@ type InstructionCache interface {
 }
 
 type Solver struct {
-	resolve     ResolveOpFunc
-	jobs        *jobList
-	cache       InstructionCache
-	imageSource source.Source
+	resolve        ResolveOpFunc
+	jobs           *jobList
+	cache          InstructionCache
+	imageSource    source.Source
+	maxParallelism int
+	ce             *cacheimport.CacheExporter
+	ci             *cacheimport.CacheImporter
+	provenance     bool
+	keepCompleted  bool
+	worker         worker.Worker
+	leaser         cache.Leaser
+}
+
+// SolverOpt is an option for configuring a new Solver
+type SolverOpt func(*Solver)
+
+// WithMaxParallelism limits the number of operations that are executed in
+// parallel over all jobs. Zero means no limit.
+func WithMaxParallelism(n int) SolverOpt {
+	return func(s *Solver) {
+		s.maxParallelism = n
+	}
+}
+
+// WithCacheExporter sets the exporter used for pushing the build cache of a
+// solve to a remote target.
+func WithCacheExporter(ce *cacheimport.CacheExporter) SolverOpt {
+	return func(s *Solver) {
+		s.ce = ce
+	}
+}
+
+// WithWorker sets the worker that runs the processes of the frontends
+func WithWorker(w worker.Worker) SolverOpt {
+	return func(s *Solver) {
+		s.worker = w
+	}
+}
+
+// WithKeepCompleted retains the result of a vertex in the cache as soon as it
+// has been run. By default the results are only retained when they are used by
+// another vertex, so the results of the vertexes that complete while a sibling
+// fails are removed when the solve is cleaned up.
+func WithKeepCompleted() SolverOpt {
+	return func(s *Solver) {
+		s.keepCompleted = true
+	}
+}
+
+// WithLeaser covers the snapshots created by every solve with a lease, so
+// that they are cleaned up if the daemon stops during the solve
+func WithLeaser(l cache.Leaser) SolverOpt {
+	return func(s *Solver) {
+		s.leaser = l
+	}
+}
+
+// WithCacheImporter sets the importer used for seeding the solver cache from
+// a remote source.
+func WithCacheImporter(ci *cacheimport.CacheImporter) SolverOpt {
+	return func(s *Solver) {
+		s.ci = ci
+	}
+}
+
+func New(resolve ResolveOpFunc, cache InstructionCache, imageSource source.Source, opts ...SolverOpt) *Solver {
+	s := &Solver{resolve: resolve, cache: cache, imageSource: imageSource}
+	for _, o := range opts {
+		o(s)
+	}
+	s.jobs = newJobList(newScheduler(s.maxParallelism))
+	s.jobs.keepCompleted = s.keepCompleted
+	return s
+}
+
+type SolveRequest struct {
+	Definition  Vertex
+	Frontend    frontend.Frontend
+	Exporter    exporter.ExporterInstance
+	FrontendOpt map[string]string
+	// FrontendInputs are named definitions that replace inputs of the
+	// frontend
+	FrontendInputs map[string][][]byte
+	// CacheImports are the caches that are looked up with the cache keys of
+	// the vertexes before they run
+	CacheImports []cacheimport.Entry
+	// CacheExports are the targets the cache of the build is exported to
+	CacheExports []cacheimport.Entry
+	// Results are named results of the build that are solved together with
+	// the definition
+	Results map[string]Vertex
+	// Exports run more exporters after the build
+	Exports []Export
+	// Entitlements are granted to the execs of the build
+	Entitlements entitlements.Set
+	// CgroupParent is the cgroup the execs of the build are created under,
+	// relative to the cgroup of the daemon
+	CgroupParent string
+	// Status receives the progress of the solve like Solver.Status. It is
+	// closed when the progress ends, right away if the solve fails before the
+	// job is created.
+	Status chan *client.SolveStatus
 }
 
-func New(resolve ResolveOpFunc, cache InstructionCache, imageSource source.Source) *Solver {
-	return &Solver{resolve: resolve, jobs: newJobList(), cache: cache, imageSource: imageSource}
+// SolveResponse is the metadata returned by the exporters of a solve
+type SolveResponse struct {
+	// ExporterResponse is the response of the exporter of the main result
+	ExporterResponse map[string]string
+	// Exports are the responses of the exporters of SolveRequest.Exports in
+	// the same order
+	Exports []map[string]string
+	// Summary describes the work done by the solve
+	Summary *client.BuildSummary
 }
 
-func (s *Solver) Solve(ctx context.Context, id string, f frontend.Frontend, v Vertex, exp exporter.ExporterInstance, frontendOpt map[string]string) error {
+// Export runs an exporter on a named result of the solve. The main result is
+// exported if Result is empty.
+type Export struct {
+	Result   string
+	Exporter exporter.ExporterInstance
+}
+
+func (s *Solver) Solve(ctx context.Context, id string, req SolveRequest) (resp *SolveResponse, retErr error) {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	span, ctx := tracing.StartSpan(ctx, "solve", id)
+	solveStarted := time.Now()
+	defer func() {
+		span.Finish(retErr)
+		metricSolves.Observe(time.Since(solveStarted).Seconds(), resultLabel(retErr))
+	}()
+
 	pr, ctx, closeProgressWriter := progress.NewContext(ctx)
 
 	defer closeProgressWriter()
 
+	statusPiped := false
+	if req.Status != nil {
+		defer func() {
+			if !statusPiped {
+				close(req.Status)
+			}
+		}()
+	}
+
+	if len(req.CacheExports) > 0 && s.ce == nil {
+		return nil, errors.Errorf("cache export is not supported")
+	}
+	if err := validateCgroupParent(req.CgroupParent); err != nil {
+		return nil, err
+	}
+	for _, e := range req.CacheExports {
+		if err := e.Validate(true); err != nil {
+			return nil, err
+		}
+	}
+
 	var vv *vertex
 	var index Index
-	if v != nil {
-		if len(v.Inputs()) == 0 {
-			return errors.New("required vertex needs to have inputs")
+	if v := req.Definition; v != nil {
+		var err error
+		vv, index, err = resultVertex(v)
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		index = v.Inputs()[0].Index
-		v = v.Inputs()[0].Vertex
+	results := make(map[string]*input, len(req.Results))
+	for name, v := range req.Results {
+		rv, index, err := resultVertex(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid result %s", name)
+		}
+		results[name] = &input{vertex: rv, index: index}
+	}
+	for _, e := range req.Exports {
+		if _, ok := results[e.Result]; !ok && e.Result != "" {
+			return nil, errors.Errorf("result %s not found for export", e.Result)
+		}
+	}
 
-		vv = toInternalVertex(v)
+	if s.leaser != nil {
+		var done func() error
+		var err error
+		ctx, done, err = s.leaser.WithLease(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err := done(); err != nil {
+				logrus.Errorf("failed to release lease %s: %+v", id, err)
+			}
+		}()
 	}
 
-	ctx, j, err := s.jobs.new(ctx, id, pr, s.cache)
+	ic, releaseCache, err := s.solveCache(ctx, req, buildPlatform(vv, req.Exporter))
 	if err != nil {
-		return err
+		return nil, err
+	}
+	defer releaseCache()
+
+	ctx, j, err := s.jobs.new(ctx, id, pr, ic, req.Entitlements, req.CgroupParent)
+	if err != nil {
+		return nil, err
+	}
+	if req.Status != nil {
+		statusPiped = true
+		go func() {
+			defer close(req.Status)
+			// the progress ends when the solve returns
+			if err := j.pipe(context.TODO(), req.Status); err != nil {
+				logrus.Debugf("incomplete progress of solve %s: %v", id, err)
+			}
+		}()
 	}
 
 	var ref Reference
@@ -112,44 +408,273 @@ func (s *Solver) Solve(ctx context.Context, id string, f frontend.Frontend, v Ve
 	if vv != nil {
 		if err := j.load(vv, s.resolve); err != nil {
 			j.discard()
-			return err
+			return nil, err
 		}
 		ref, err = j.getRef(ctx, vv, index)
 	} else {
-		ref, exporterOpt, err = f.Solve(ctx, &llbBridge{
+		ref, exporterOpt, err = req.Frontend.Solve(ctx, &llbBridge{
 			job:                j,
 			resolveOp:          s.resolve,
-			resolveImageConfig: s.imageSource.(resolveImageConfig),
-		}, frontendOpt)
+			resolveImageConfig: newImageConfigCache(s.imageSource.(resolveImageConfig)),
+			worker:             s.worker,
+		}, req.FrontendOpt, req.FrontendInputs)
+	}
+	var resultRefs map[string]Reference
+	if err == nil && len(results) > 0 {
+		resultRefs, err = s.solveResults(ctx, j, results)
+	}
+	defer func() {
+		for _, r := range resultRefs {
+			cache.ReleaseDetached(r)
+		}
+	}()
+	// the records of the results are exported with the min mode and the
+	// records of all the vertexes with the max mode and the inline cache
+	ie, inlineCache := req.Exporter.(exporter.InlineCacheExporter)
+	inlineCache = inlineCache && ie.InlineCache()
+	records := map[string]*cacheimport.Records{}
+	for _, e := range req.CacheExports {
+		mode, _ := e.Mode()
+		records[mode] = nil
+	}
+	if inlineCache {
+		records[cacheimport.ModeMax] = nil
+	}
+	for mode := range records {
+		if err != nil {
+			break
+		}
+		var r cacheimport.Records
+		r.CacheRecords, r.ContentMappings, err = j.cacheRecords(ctx, s.cache, mode == cacheimport.ModeMax)
+		records[mode] = &r
+		defer func() {
+			for _, cr := range r.CacheRecords {
+				cache.ReleaseDetached(cr.Reference)
+			}
+		}()
 	}
+	// the named results don't get the options of the frontend
+	resultOpt := map[string]interface{}{}
+	if err == nil && s.provenance {
+		var dt []byte
+		dt, err = j.provenance()
+		if err == nil {
+			if exporterOpt == nil {
+				exporterOpt = map[string]interface{}{}
+			}
+			exporterOpt[ExporterProvenanceKey] = dt
+			resultOpt[ExporterProvenanceKey] = dt
+		}
+	}
+	if err == nil && inlineCache {
+		if exporterOpt == nil {
+			exporterOpt = map[string]interface{}{}
+		}
+		exporterOpt[exporter.InlineCacheKey] = *records[cacheimport.ModeMax]
+	}
+	if err == nil && len(resultRefs) > 0 {
+		// the named results are passed to the exporter of the main result so
+		// it can combine them, e.g. in a manifest list
+		refs, rerr := toImmutableRefs(resultRefs)
+		if rerr != nil {
+			err = rerr
+		} else {
+			if exporterOpt == nil {
+				exporterOpt = map[string]interface{}{}
+			}
+			exporterOpt[exporter.ResultsKey] = refs
+		}
+	}
+	summary := j.summary()
 	j.discard()
+	go s.jobs.migrator.run(context.TODO())
 	if err != nil {
-		return err
+		if ref != nil {
+			cache.ReleaseDetached(ref)
+		}
+		return nil, err
 	}
 
 	defer func() {
-		go ref.Release(context.TODO())
+		cache.ReleaseDetached(ref)
 	}()
 
 	immutable, ok := toImmutableRef(ref)
 	if !ok {
-		return errors.Errorf("invalid reference for exporting: %T", ref)
+		return nil, errors.Errorf("invalid reference for exporting: %T", ref)
 	}
 	if err := immutable.Finalize(ctx); err != nil {
-		return err
+		return nil, err
 	}
 
-	if exp != nil {
+	res := &SolveResponse{}
+	exported := newByteCounter()
+	if exp := req.Exporter; exp != nil {
 		vv.notifyStarted(ctx)
 		pw, _, ctx := progress.FromContext(ctx, progress.WithMetadata("vertex", vv.Digest()))
 		defer pw.Close()
-		err := exp.Export(ctx, immutable, exporterOpt)
-		vv.notifyCompleted(ctx, false, err)
+		ctx = exported.withProgress(ctx, pw)
+		span, ctx := tracing.StartSpan(ctx, "export", exp.Name())
+		r, err := exp.Export(ctx, immutable, exporterOpt)
+		span.Finish(err)
+		vv.notifyCompleted(ctx, err)
 		if err != nil {
+			return nil, err
+		}
+		res.ExporterResponse = r
+	}
+
+	for _, e := range req.Exports {
+		ref, opt, name := Reference(immutable), exporterOpt, e.Exporter.Name()
+		if e.Result != "" {
+			ref, opt, name = resultRefs[e.Result], resultOpt, name+" for "+e.Result
+		}
+		ir, ok := toImmutableRef(ref)
+		if !ok {
+			return nil, errors.Errorf("invalid reference for exporting: %T", ref)
+		}
+		var r map[string]string
+		if err := inVertexContext(ctx, "exporting", name, exported, func(ctx context.Context) error {
+			var err error
+			r, err = e.Exporter.Export(ctx, ir, opt)
 			return err
+		}); err != nil {
+			return nil, err
 		}
+		res.Exports = append(res.Exports, r)
 	}
-	return err
+
+	for _, e := range req.CacheExports {
+		mode, _ := e.Mode()
+		r := records[mode]
+		if err := inVertexContext(ctx, "exporting cache", "exporting build cache to "+e.String(), nil, func(ctx context.Context) error {
+			if e.Type == cacheimport.TypeLocal {
+				return s.ce.ExportLocal(ctx, r.CacheRecords, r.ContentMappings, e.Attrs[cacheimport.AttrTarget])
+			}
+			return s.ce.Export(ctx, r.CacheRecords, r.ContentMappings, e.Attrs[cacheimport.AttrRef])
+		}); err != nil {
+			return nil, err
+		}
+	}
+	summary.ExportedBytes = exported.total()
+	res.Summary = summary
+	pw, _, _ := progress.FromContext(ctx)
+	pw.Write("summary", *summary)
+	pw.Close()
+	return res, nil
+}
+
+// solveResults solves the named results in parallel
+func (s *Solver) solveResults(ctx context.Context, j *job, results map[string]*input) (map[string]Reference, error) {
+	var mu sync.Mutex
+	refs := make(map[string]Reference, len(results))
+	// the context of the group is canceled when Wait returns
+	eg, egCtx := errgroup.WithContext(ctx)
+	for name, inp := range results {
+		func(name string, inp *input) {
+			eg.Go(func() error {
+				if err := j.load(inp.vertex, s.resolve); err != nil {
+					return err
+				}
+				ref, err := j.getRef(egCtx, inp.vertex, inp.index)
+				if err != nil {
+					return err
+				}
+				mu.Lock()
+				refs[name] = ref
+				mu.Unlock()
+				return nil
+			})
+		}(name, inp)
+	}
+	if err := eg.Wait(); err != nil {
+		for _, r := range refs {
+			cache.ReleaseDetached(r)
+		}
+		return nil, err
+	}
+	for _, r := range refs {
+		ir, ok := toImmutableRef(r)
+		if !ok {
+			continue
+		}
+		if err := ir.Finalize(ctx); err != nil {
+			for _, r := range refs {
+				cache.ReleaseDetached(r)
+			}
+			return nil, err
+		}
+	}
+	return refs, nil
+}
+
+// resultVertex returns the vertex and the output index of the result of the
+// terminal vertex of a definition
+func resultVertex(v Vertex) (*vertex, Index, error) {
+	if len(v.Inputs()) == 0 {
+		return nil, 0, errors.New("required vertex needs to have inputs")
+	}
+	return toInternalVertex(v.Inputs()[0].Vertex), v.Inputs()[0].Index, nil
+}
+
+// buildPlatform returns the platform of the definition v, or of the result of
+// the exporter if the build has no definition or the definition has no
+// platform. It returns nil for the default platform.
+func buildPlatform(v *vertex, e exporter.ExporterInstance) *ocispec.Platform {
+	if v != nil && v.platform != nil {
+		p := v.platform.Spec()
+		return &p
+	}
+	if pe, ok := e.(exporter.PlatformExporter); ok {
+		return pe.Platform()
+	}
+	return nil
+}
+
+// solveCache returns the instruction cache used for solving req and a
+// function that releases the imported caches. The inline caches of
+// multi-platform images are read from the images of platform p.
+func (s *Solver) solveCache(ctx context.Context, req SolveRequest, p *ocispec.Platform) (InstructionCache, func(), error) {
+	if len(req.CacheImports) == 0 {
+		return s.cache, func() {}, nil
+	}
+	if s.ci == nil {
+		return nil, nil, errors.Errorf("cache import is not supported")
+	}
+	var remotes []InstructionCache
+	var releases []func() error
+	release := func() {
+		for _, r := range releases {
+			if err := r(); err != nil {
+				logrus.Errorf("failed to release imported cache: %v", err)
+			}
+		}
+	}
+	for _, e := range req.CacheImports {
+		if err := e.Validate(false); err != nil {
+			release()
+			return nil, nil, err
+		}
+		var ic *cacheimport.ImportedCache
+		if err := inVertexContext(ctx, "importing cache", "importing cache from "+e.String(), nil, func(ctx context.Context) error {
+			var err error
+			if e.Type == cacheimport.TypeLocal {
+				var r func() error
+				ic, r, err = s.ci.ImportLocal(ctx, e.Attrs[cacheimport.AttrName], p)
+				if err == nil {
+					releases = append(releases, r)
+				}
+				return err
+			}
+			ic, err = s.ci.Import(ctx, e.Attrs[cacheimport.AttrRef], p)
+			return err
+		}); err != nil {
+			release()
+			return nil, nil, err
+		}
+		remotes = append(remotes, ic)
+	}
+	return mergeRemoteCache(s.cache, remotes...), release, nil
 }
 
 func (s *Solver) Status(ctx context.Context, id string, statusChan chan *client.SolveStatus) error {
@@ -176,6 +701,7 @@ type vertexInput struct {
 	ev        VertexEvaluator
 	cacheKeys []digest.Digest
 	ref       Reference
+	changed   bool // ref was not loaded from the cache
 }
 
 type vertexSolver struct {
@@ -186,17 +712,26 @@ type vertexSolver struct {
 	refs   []*sharedRef
 	f      *bgfunc.F
 	ctx    context.Context
+	sched  *scheduler
+	jobID  string
+
+	migrator *cacheKeyMigrator
 
-	baseKey digest.Digest
-	mu      sync.Mutex
-	results []digest.Digest
+	baseKey     digest.Digest
+	indexes     map[Index]struct{} // outputs that cache keys have been requested for
+	mu          sync.Mutex
+	results     []digest.Digest
+	contentKeys []digest.Digest
+	executed    *ProvenanceVertex // set if the vertex was run
+
+	keepCompleted bool // retain the results before they are used by a caller
 
 	signal *signal // used to notify that there are callers who need more data
 }
 
 type resolveF func(digest.Digest) (VertexSolver, error)
 
-func newVertexSolver(ctx context.Context, v *vertex, op Op, c InstructionCache, resolve resolveF) (VertexSolver, error) {
+func newVertexSolver(ctx context.Context, v *vertex, op Op, c InstructionCache, resolve resolveF, sched *scheduler, jobID string, migrator *cacheKeyMigrator, keepCompleted bool) (VertexSolver, error) {
 	inputs := make([]*vertexInput, len(v.inputs))
 	for i, in := range v.inputs {
 		s, err := resolve(in.vertex.digest)
@@ -219,14 +754,22 @@ func newVertexSolver(ctx context.Context, v *vertex, op Op, c InstructionCache,
 		op:     op,
 		cache:  c,
 		signal: newSignaller(),
+		sched:  sched,
+		jobID:  jobID,
+
+		migrator:      migrator,
+		indexes:       map[Index]struct{}{},
+		keepCompleted: keepCompleted,
 	}, nil
 }
 
 func (vs *vertexSolver) CacheKey(ctx context.Context, index Index) (digest.Digest, error) {
 	vs.mu.Lock()
 	defer vs.mu.Unlock()
+	vs.indexes[index] = struct{}{}
 	if vs.baseKey == "" {
-		eg, ctx := errgroup.WithContext(vs.ctx)
+		span, ctx := tracing.StartSpan(vs.ctx, "cachekey", vs.v.Name())
+		eg, ctx := errgroup.WithContext(ctx)
 		for i := range vs.inputs {
 			func(i int) {
 				eg.Go(func() error {
@@ -249,7 +792,9 @@ func (vs *vertexSolver) CacheKey(ctx context.Context, index Index) (digest.Diges
 			return nil
 		})
 
-		if err := eg.Wait(); err != nil {
+		err := eg.Wait()
+		span.Finish(err)
+		if err != nil {
 			return "", err
 		}
 
@@ -284,14 +829,52 @@ func (vs *vertexSolver) currentCacheKey(last bool) (digest.Digest, error) {
 			inputKeys[i] = inp.cacheKeys[0]
 		}
 	}
-	dt, err := json.Marshal(struct {
-		Inputs   []digest.Digest
-		CacheKey digest.Digest
-	}{Inputs: inputKeys, CacheKey: vs.baseKey})
-	if err != nil {
-		return "", err
+	return cacheKeyWithInputs(inputKeys, vs.baseKey)
+}
+
+// previousCacheKeys returns the cache keys computed with the previous cache
+// key versions of the op that have results in the cache. If migrate is set
+// the results are queued to be saved with the cache key that the results of
+// the vertex are saved with.
+func (vs *vertexSolver) previousCacheKeys(ctx context.Context, inputKeys []digest.Digest, migrate bool) ([]digest.Digest, error) {
+	vo, ok := vs.op.(versionedOp)
+	if !ok {
+		return nil, nil
+	}
+	migrate = migrate && vs.migrator != nil
+	var current digest.Digest
+	if migrate {
+		vs.mu.Lock()
+		k, err := vs.mainCacheKey()
+		vs.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		current = k
 	}
-	return digest.FromBytes(dt), nil
+	var keys []digest.Digest
+	for _, version := range previousCacheKeyVersions(vo.cacheKeyKind()) {
+		base, err := vo.versionedCacheKey(ctx, version)
+		if err != nil {
+			return nil, err
+		}
+		k, err := cacheKeyWithInputs(inputKeys, base)
+		if err != nil {
+			return nil, err
+		}
+		// every op with results has an output with index 0
+		exists, err := vs.cache.Probe(ctx, cacheKeyForIndex(k, 0))
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			keys = append(keys, k)
+			if migrate {
+				vs.migrator.add(vs.cache, k, current)
+			}
+		}
+	}
+	return keys, nil
 }
 
 func (vs *vertexSolver) OutputEvaluator(index Index) (VertexEvaluator, error) {
@@ -363,11 +946,14 @@ func (vs *vertexSolver) run(ctx context.Context, signal func()) (retErr error) {
 
 						// check if current cache key is in cache
 						if len(inp.cacheKeys) > 0 {
-							ref, err := vs.cache.Lookup(ctx2, inp.cacheKeys[len(inp.cacheKeys)-1])
+							k := inp.cacheKeys[len(inp.cacheKeys)-1]
+							iv := vs.v.inputs[i].vertex
+							ref, err := lookupCache(ctx2, vs.cache, iv, k)
 							if err != nil {
 								return err
 							}
 							if ref != nil {
+								iv.notifyCached(ctx2, k)
 								inp.ref = ref.(Reference)
 								return nil
 							}
@@ -383,13 +969,11 @@ func (vs *vertexSolver) run(ctx context.Context, signal func()) (retErr error) {
 						}
 						if ref := res.Reference; ref != nil {
 							if ref, ok := toImmutableRef(ref); ok {
-								if !cache.HasCachePolicyRetain(ref) {
-									if err := cache.CachePolicyRetain(ref); err != nil {
-										return err
-									}
-									ref.Metadata().Commit()
+								if err := retainRef(ref); err != nil {
+									return err
 								}
 								inp.ref = ref
+								inp.changed = true
 							}
 							return nil
 						}
@@ -431,11 +1015,19 @@ func (vs *vertexSolver) run(ctx context.Context, signal func()) (retErr error) {
 	}
 
 	// TODO: avoid doing this twice on cancellation+resume
-	contentKeys, err := vs.op.ContentKeys(ctx, [][]digest.Digest{lastInputKeys}, inputRefs)
+	span, spanCtx := tracing.StartSpan(ctx, "contentkeys", vs.v.Name())
+	hashStarted := time.Now()
+	contentKeys, err := vs.op.ContentKeys(spanCtx, [][]digest.Digest{lastInputKeys}, inputRefs)
+	vs.v.timings.record(phaseContentHash, hashStarted)
+	span.Finish(err)
 	if err != nil {
 		return err
 	}
+	vs.mu.Lock()
+	vs.contentKeys = contentKeys
+	vs.mu.Unlock()
 
+	lookupStarted := time.Now()
 	var extraKeys []digest.Digest
 	for _, k := range contentKeys {
 		cks, err := vs.cache.GetContentMapping(k)
@@ -444,6 +1036,13 @@ func (vs *vertexSolver) run(ctx context.Context, signal func()) (retErr error) {
 		}
 		extraKeys = append(extraKeys, cks...)
 	}
+	contentMatched := len(extraKeys) > 0
+	previousKeys, err := vs.previousCacheKeys(ctx, lastInputKeys, true)
+	if err != nil {
+		return err
+	}
+	vs.v.timings.record(phaseCacheLookup, lookupStarted)
+	extraKeys = append(extraKeys, previousKeys...)
 	if len(extraKeys) > 0 {
 		vs.mu.Lock()
 		vs.results = append(vs.results, extraKeys...)
@@ -458,16 +1057,55 @@ func (vs *vertexSolver) run(ctx context.Context, signal func()) (retErr error) {
 	case <-wait:
 	}
 
-	// no cache hit. start evaluating the node
+	// no cache hit. wait for a free slot and start evaluating the node. The
+	// ops solving nested vertexes don't take a slot, the nested vertexes
+	// would wait for the one they hold.
+	if _, ok := vs.op.(nestedOp); !ok {
+		release, err := vs.sched.acquire(ctx, vs.jobID)
+		if err != nil {
+			return err
+		}
+		defer release()
+	}
+
+	vs.mu.Lock()
+	reason := vs.cacheMissReason(contentKeys, contentMatched)
+	vs.mu.Unlock()
+	vs.v.notifyMu.Lock()
+	vs.v.clientVertex.CacheMissReason = reason
+	vs.v.notifyMu.Unlock()
 	vs.v.notifyStarted(ctx)
 	defer func() {
-		vs.v.notifyCompleted(ctx, false, retErr)
+		vs.v.notifyCompleted(ctx, retErr)
 	}()
 
-	refs, err := vs.op.Run(ctx, inputRefs)
+	started := time.Now()
+	span, spanCtx = tracing.StartSpan(ctx, "run", vs.v.Name())
+	if cacheKey, err := vs.mainCacheKey(); err == nil {
+		spanCtx = withCacheKey(spanCtx, cacheKey)
+	}
+	spanCtx = withTimings(spanCtx, &vs.v.timings)
+	metricVertexesExecuted.Inc()
+	refs, err := vs.op.Run(spanCtx, inputRefs)
+	span.Finish(err)
+	metricVertexDuration.Observe(time.Since(started).Seconds(), resultLabel(err))
 	if err != nil {
 		return err
 	}
+	vs.mu.Lock()
+	vs.recordExecuted(started, time.Now())
+	vs.mu.Unlock()
+	if vs.keepCompleted {
+		// retain before the results are published so they survive the cleanup
+		// of a solve that was cancelled by a failing sibling
+		for _, r := range refs {
+			if ref, ok := toImmutableRef(r); ok {
+				if err := retainRef(ref); err != nil {
+					logrus.Errorf("failed to retain result of %s: %v", vs.v.Digest(), err)
+				}
+			}
+		}
+	}
 	sr := make([]*sharedRef, len(refs))
 	for i, r := range refs {
 		sr[i] = newSharedRef(r)
@@ -500,6 +1138,17 @@ func (vs *vertexSolver) run(ctx context.Context, signal func()) (retErr error) {
 	return nil
 }
 
+// retainRef keeps ref in the cache after it has been released
+func retainRef(ref cache.ImmutableRef) error {
+	if cache.HasCachePolicyRetain(ref) {
+		return nil
+	}
+	if err := cache.CachePolicyRetain(ref); err != nil {
+		return err
+	}
+	return ref.Metadata().Commit()
+}
+
 type VertexEvaluator interface {
 	Next(context.Context) (*VertexResult, error)
 	Cancel() error
@@ -549,10 +1198,11 @@ type llbBridge struct {
 	resolveImageConfig
 	job       *job
 	resolveOp ResolveOpFunc
+	worker    worker.Worker
 }
 
 type resolveImageConfig interface {
-	ResolveImageConfig(ctx context.Context, ref string) ([]byte, error)
+	ResolveImageConfig(ctx context.Context, ref string, platform *ocispec.Platform) (digest.Digest, []byte, error)
 }
 
 func (s *llbBridge) Solve(ctx context.Context, dt [][]byte) (cache.ImmutableRef, error) {
@@ -584,6 +1234,35 @@ func (s *llbBridge) Solve(ctx context.Context, dt [][]byte) (cache.ImmutableRef,
 	return immutable, nil
 }
 
+func (s *llbBridge) Exec(ctx context.Context, meta worker.Meta, rootfs cache.ImmutableRef, stdin io.ReadCloser, stdout, stderr io.WriteCloser) error {
+	if s.worker == nil {
+		return errors.New("running processes is not supported by the frontend bridge")
+	}
+	return s.worker.Exec(ctx, meta, rootfs, nil, stdin, stdout, stderr)
+}
+
+// inVertexContext reports the progress of f as a separate internal vertex
+// of group that is not part of the build graph. The bytes of the progress
+// are counted by bc if it is not nil.
+func inVertexContext(ctx context.Context, group, name string, bc *byteCounter, f func(ctx context.Context) error) error {
+	v := &vertex{
+		digest: digest.FromBytes([]byte(identity.NewID())),
+		name:   name,
+	}
+	v.initClientVertex()
+	v.clientVertex.Internal = true
+	v.clientVertex.Group = group
+	pw, _, ctx := progress.FromContext(ctx, progress.WithMetadata("vertex", v.Digest()))
+	defer pw.Close()
+	if bc != nil {
+		ctx = bc.withProgress(ctx, pw)
+	}
+	v.notifyStarted(ctx)
+	err := f(ctx)
+	v.notifyCompleted(ctx, err)
+	return err
+}
+
 func cacheKeyForIndex(dgst digest.Digest, index Index) digest.Digest {
 	return digest.FromBytes([]byte(fmt.Sprintf("%s.%d", dgst, index)))
 }
@@ -2,6 +2,8 @@ package solver
 
 import (
 	"encoding/json"
+	"os"
+	"strconv"
 	"sync"
 
 	"github.com/moby/buildkit/solver/pb"
@@ -13,16 +15,18 @@ import (
 const sourceCacheType = "buildkit.source.v0"
 
 type sourceOp struct {
-	mu  sync.Mutex
-	op  *pb.Op_Source
-	sm  *source.Manager
-	src source.SourceInstance
+	mu       sync.Mutex
+	op       *pb.Op_Source
+	platform *pb.Platform
+	sm       *source.Manager
+	src      source.SourceInstance
 }
 
-func newSourceOp(_ Vertex, op *pb.Op_Source, sm *source.Manager) (Op, error) {
+func newSourceOp(v Vertex, op *pb.Op_Source, sm *source.Manager) (Op, error) {
 	return &sourceOp{
-		op: op,
-		sm: sm,
+		op:       op,
+		platform: vertexPlatform(v),
+		sm:       sm,
 	}, nil
 }
 
@@ -36,6 +40,10 @@ func (s *sourceOp) instance(ctx context.Context) (source.SourceInstance, error)
 	if err != nil {
 		return nil, err
 	}
+	if id, ok := id.(*source.ImageIdentifier); ok && s.platform != nil {
+		p := s.platform.Spec()
+		id.Platform = &p
+	}
 	if id, ok := id.(*source.GitIdentifier); ok {
 		for k, v := range s.op.Source.Attrs {
 			switch k {
@@ -57,6 +65,66 @@ func (s *sourceOp) instance(ctx context.Context) (source.SourceInstance, error)
 					return nil, err
 				}
 				id.IncludePatterns = patterns
+			case pb.AttrExcludePatterns:
+				var patterns []string
+				if err := json.Unmarshal([]byte(v), &patterns); err != nil {
+					return nil, err
+				}
+				id.ExcludePatterns = patterns
+			case pb.AttrFollowPaths:
+				var paths []string
+				if err := json.Unmarshal([]byte(v), &paths); err != nil {
+					return nil, err
+				}
+				id.FollowPaths = paths
+			case pb.AttrExcludeXattrs:
+				if v == "true" {
+					id.ExcludeXattrs = true
+				}
+			}
+		}
+	}
+	if id, ok := id.(*source.HttpIdentifier); ok {
+		for k, v := range s.op.Source.Attrs {
+			switch k {
+			case pb.AttrHTTPChecksum:
+				dgst, err := digest.Parse(v)
+				if err != nil {
+					return nil, err
+				}
+				id.Checksum = dgst
+			case pb.AttrHTTPFilename:
+				id.Filename = v
+			case pb.AttrHTTPPerm:
+				i, err := strconv.ParseInt(v, 0, 64)
+				if err != nil {
+					return nil, err
+				}
+				id.Perm = os.FileMode(i)
+			case pb.AttrHTTPUID:
+				i, err := strconv.Atoi(v)
+				if err != nil {
+					return nil, err
+				}
+				id.UID = i
+			case pb.AttrHTTPGID:
+				i, err := strconv.Atoi(v)
+				if err != nil {
+					return nil, err
+				}
+				id.GID = i
+			}
+		}
+	}
+	if id, ok := id.(*source.OCILayoutIdentifier); ok {
+		for k, v := range s.op.Source.Attrs {
+			switch k {
+			case pb.AttrOCILayoutLocal:
+				if v == "true" {
+					id.Local = true
+				}
+			case pb.AttrLocalSessionID:
+				id.SessionID = v
 			}
 		}
 	}
@@ -0,0 +1,64 @@
+package solver
+
+import (
+	"github.com/containerd/containerd/mount"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/sshforward"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+)
+
+// sshMount is a mountable for a unix socket that forwards the connections to
+// the ssh agent of the client
+type sshMount struct {
+	sock   string
+	closer func() error
+}
+
+// newSSHMount creates the socket for a ssh mount. A nil mount is returned
+// for missing optional agents.
+func newSSHMount(ctx context.Context, sm *session.Manager, opt *pb.SSHOpt) (*sshMount, error) {
+	if opt == nil {
+		return nil, errors.Errorf("missing ssh mount options")
+	}
+	id := opt.ID
+	if id == "" {
+		id = sshforward.DefaultID
+	}
+	caller, err := sessionCaller(ctx, sm)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not access ssh agent %s", id)
+	}
+	if err := sshforward.CheckSSHID(ctx, caller, id); err != nil {
+		if errors.Cause(err) == sshforward.ErrNotFound && opt.Optional {
+			return nil, nil
+		}
+		return nil, err
+	}
+	sock, closer, err := sshforward.MountSSHSocket(ctx, caller, sshforward.SocketOpt{
+		ID:   id,
+		UID:  int(opt.Uid),
+		GID:  int(opt.Gid),
+		Mode: int(opt.Mode & 0777),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &sshMount{sock: sock, closer: closer}, nil
+}
+
+func (sm *sshMount) Mount(ctx context.Context, readonly bool) ([]mount.Mount, error) {
+	return []mount.Mount{{
+		Type:    "bind",
+		Source:  sm.sock,
+		Options: []string{"nodev", "nosuid", "noexec", "rbind"},
+	}}, nil
+}
+
+func (sm *sshMount) release() {
+	if err := sm.closer(); err != nil {
+		logrus.Errorf("failed to close ssh agent socket %s: %v", sm.sock, err)
+	}
+}
@@ -0,0 +1,86 @@
+package solver
+
+import (
+	"sync"
+
+	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/util/progress"
+	"golang.org/x/net/context"
+)
+
+// byteCounter is a progress writer that counts the bytes of the statuses
+// written to it. The last value of every status is counted.
+type byteCounter struct {
+	mu      sync.Mutex
+	current map[string]int64
+}
+
+func newByteCounter() *byteCounter {
+	return &byteCounter{current: map[string]int64{}}
+}
+
+func (bc *byteCounter) Write(id string, v interface{}) error {
+	st, ok := v.(progress.Status)
+	if !ok {
+		return nil
+	}
+	bc.mu.Lock()
+	bc.current[id] = int64(st.Current)
+	bc.mu.Unlock()
+	return nil
+}
+
+func (bc *byteCounter) WriteRawProgress(p *progress.Progress) error {
+	return bc.Write(p.ID, p.Sys)
+}
+
+func (bc *byteCounter) Close() error {
+	return nil
+}
+
+func (bc *byteCounter) total() int64 {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	var n int64
+	for _, c := range bc.current {
+		n += c
+	}
+	return n
+}
+
+// withProgress returns a context whose progress is written to pw and counted
+// by bc
+func (bc *byteCounter) withProgress(ctx context.Context, pw progress.Writer) context.Context {
+	mw := progress.NewMultiWriter()
+	mw.Add(pw)
+	mw.Add(bc)
+	return progress.WithProgress(ctx, mw)
+}
+
+// summary returns the summary of the vertexes loaded by the job. The exported
+// bytes are not known by the job.
+func (j *job) summary() *client.BuildSummary {
+	j.l.mu.Lock()
+	defer j.l.mu.Unlock()
+	s := &client.BuildSummary{}
+	for _, st := range j.l.actives {
+		if _, ok := st.jobs[j]; !ok || st.vertex == nil {
+			continue
+		}
+		s.Vertexes++
+		st.vertex.notifyMu.Lock()
+		v := st.vertex.clientVertex
+		st.vertex.notifyMu.Unlock()
+		switch {
+		case v.Cached:
+			s.Cached++
+		case v.Started != nil && v.Completed != nil:
+			s.Executed++
+			if v.Timings != nil {
+				s.ExecTime += v.Timings.Exec
+			}
+		}
+		s.TransferredBytes += st.transferred.total()
+	}
+	return s
+}
@@ -0,0 +1,65 @@
+package solver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/util/progress"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestByteCounter(t *testing.T) {
+	pr, ctx, cancel := progress.NewContext(context.TODO())
+	defer cancel()
+
+	bc := newByteCounter()
+	pw, _, ctx := progress.FromContext(ctx, progress.WithMetadata("vertex", "v"))
+	ctx = bc.withProgress(ctx, pw)
+
+	tr := progress.NewTransfer(ctx, "layer1", "pulling", 100)
+	tr.Update(50)
+	tr.Update(100)
+	tr.Done(nil)
+	tr = progress.NewTransfer(ctx, "layer2", "pulling", 0)
+	tr.Update(20)
+	tr.Done(nil)
+	w, _, _ := progress.FromContext(ctx)
+	w.Write("log", "not a status")
+
+	require.Equal(t, int64(120), bc.total())
+
+	// the progress is still written to the vertex
+	p, err := pr.Read(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(p))
+	for _, p := range p {
+		vtx, ok := p.Meta("vertex")
+		require.True(t, ok)
+		require.Equal(t, "v", vtx)
+	}
+}
+
+func TestJobSummary(t *testing.T) {
+	jl := newJobList(newScheduler(0))
+	j := &job{l: jl}
+	now := time.Now()
+	later := now.Add(time.Minute)
+	add := func(dgst string, v client.Vertex) {
+		vtx := &vertex{clientVertex: v}
+		jl.actives[digest.Digest(dgst)] = &state{jobs: map[*job]struct{}{j: {}}, vertex: vtx, transferred: newByteCounter()}
+	}
+	add("a", client.Vertex{Cached: true})
+	// the exec time comes from the timings, not from the whole vertex
+	add("b", client.Vertex{Started: &now, Completed: &later, Timings: &client.VertexTimings{Exec: time.Second, Commit: time.Second}})
+	add("c", client.Vertex{Started: &now, Completed: &later})
+	add("d", client.Vertex{})
+
+	s := j.summary()
+	require.Equal(t, 4, s.Vertexes)
+	require.Equal(t, 1, s.Cached)
+	require.Equal(t, 2, s.Executed)
+	require.Equal(t, time.Second, s.ExecTime)
+}
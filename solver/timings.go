@@ -0,0 +1,70 @@
+package solver
+
+import (
+	"sync"
+	"time"
+
+	"github.com/moby/buildkit/client"
+	"golang.org/x/net/context"
+)
+
+type phase int
+
+const (
+	phaseCacheLookup phase = iota
+	phaseContentHash
+	phaseExec
+	phaseCommit
+)
+
+// vertexTimings accumulates the time spent in the phases of a vertex
+type vertexTimings struct {
+	mu sync.Mutex
+	t  client.VertexTimings
+}
+
+// record adds the time since started to the phase p
+func (vt *vertexTimings) record(p phase, started time.Time) {
+	d := time.Since(started)
+	vt.mu.Lock()
+	defer vt.mu.Unlock()
+	switch p {
+	case phaseCacheLookup:
+		vt.t.CacheLookup += d
+	case phaseContentHash:
+		vt.t.ContentHash += d
+	case phaseExec:
+		vt.t.Exec += d
+	case phaseCommit:
+		vt.t.Commit += d
+	}
+}
+
+// get returns the recorded timings or nil if nothing was recorded
+func (vt *vertexTimings) get() *client.VertexTimings {
+	vt.mu.Lock()
+	defer vt.mu.Unlock()
+	if vt.t == (client.VertexTimings{}) {
+		return nil
+	}
+	t := vt.t
+	return &t
+}
+
+type timingsKeyT string
+
+var timingsKey = timingsKeyT("buildkit/solver/timings")
+
+// withTimings sets the timings the phases of the op run with ctx are recorded
+// to
+func withTimings(ctx context.Context, vt *vertexTimings) context.Context {
+	return context.WithValue(ctx, timingsKey, vt)
+}
+
+// recordPhase adds the time since started to the phase p of the vertex run
+// with ctx
+func recordPhase(ctx context.Context, p phase, started time.Time) {
+	if vt, ok := ctx.Value(timingsKey).(*vertexTimings); ok {
+		vt.record(p, started)
+	}
+}
@@ -0,0 +1,27 @@
+package solver
+
+import (
+	"testing"
+
+	"github.com/moby/buildkit/util/tracing"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+// TestVertexSpan checks that the spans of a vertex are children of the span
+// of the solve that loaded it
+func TestVertexSpan(t *testing.T) {
+	cm, ic, cleanup := newTestInstructionCache(t)
+	defer cleanup()
+
+	span, ctx := tracing.StartSpan(context.Background(), "solve", t.Name())
+	defer span.Finish(nil)
+	j, _ := newTestJob(t, ctx, newJobList(newScheduler(0)), ic)
+	defer releaseTestJob(j)
+
+	v := &vertex{digest: "traced"}
+	require.NoError(t, j.load(v, func(Vertex) (Op, error) { return &commitOp{cm: cm}, nil }))
+	vs := j.l.actives[v.Digest()].solver.(*vertexSolver)
+	require.Equal(t, span, tracing.SpanFromContext(vs.ctx))
+	require.Equal(t, span.TraceID(), tracing.TraceID(vs.ctx))
+}
@@ -0,0 +1,290 @@
+package solver
+
+import (
+	"fmt"
+	"net"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/moby/buildkit/solver/pb"
+	digest "github.com/opencontainers/go-digest"
+)
+
+// DefinitionError is returned when an op of a definition is invalid
+type DefinitionError struct {
+	// Vertex is the digest of the invalid op
+	Vertex digest.Digest
+	Reason string
+}
+
+func (e *DefinitionError) Error() string {
+	return fmt.Sprintf("invalid op %s: %s", e.Vertex, e.Reason)
+}
+
+// GraphError is returned when an op refers to one of its inputs, or to an
+// output of an input, with an index that is out of range
+type GraphError struct {
+	// Vertex is the digest of the op with the invalid reference
+	Vertex digest.Digest
+	// Ref is the part of the op with the index, eg. "mount /out"
+	Ref   string
+	Index int
+	// Arity is the number of inputs, or outputs if Output is set, that Index
+	// can refer to
+	Arity  int
+	Output bool
+}
+
+func (e *GraphError) Error() string {
+	kind := "input"
+	if e.Output {
+		kind = "output"
+	}
+	return fmt.Sprintf("invalid op %s: %s refers to %s %d of %d", e.Vertex, e.Ref, kind, e.Index, e.Arity)
+}
+
+// checkInputIndex returns a GraphError if index is not one of the arity
+// inputs of the op dgst
+func checkInputIndex(dgst digest.Digest, ref string, index pb.InputIndex, arity int) error {
+	if index < 0 || int(index) >= arity {
+		return &GraphError{Vertex: dgst, Ref: ref, Index: int(index), Arity: arity}
+	}
+	return nil
+}
+
+func vertexDigest(v Vertex) digest.Digest {
+	if v == nil {
+		return ""
+	}
+	return v.Digest()
+}
+
+func definitionErrorf(dgst digest.Digest, format string, args ...interface{}) error {
+	return &DefinitionError{Vertex: dgst, Reason: fmt.Sprintf(format, args...)}
+}
+
+// validateDefinition checks the ops reachable from the last op root before
+// any of them is resolved: the inputs have to exist without cycles and refer
+// to the outputs the input ops have, and the exec mounts need to be
+// consistent. Only root, which selects the result, can be without an
+// operation.
+func validateDefinition(root digest.Digest, all map[digest.Digest]*pb.Op) error {
+	const (
+		visiting = iota + 1
+		validated
+	)
+	state := map[digest.Digest]int{}
+	var validate func(dgst digest.Digest) error
+	validate = func(dgst digest.Digest) error {
+		switch state[dgst] {
+		case visiting:
+			return definitionErrorf(dgst, "cycle in definition")
+		case validated:
+			return nil
+		}
+		state[dgst] = visiting
+		op := all[dgst]
+		if op.Op == nil && dgst != root {
+			return definitionErrorf(dgst, "empty op")
+		}
+		if err := validateOp(dgst, op); err != nil {
+			return err
+		}
+		for i, in := range op.Inputs {
+			inp, ok := all[in.Digest]
+			if !ok {
+				return definitionErrorf(dgst, "input %d refers to missing op %s", i, in.Digest)
+			}
+			if err := validate(in.Digest); err != nil {
+				return err
+			}
+			if n, ok := numOutputs(inp); ok && (in.Index < 0 || int(in.Index) >= n) {
+				return &GraphError{Vertex: dgst, Ref: fmt.Sprintf("input %d", i), Index: int(in.Index), Arity: n, Output: true}
+			}
+		}
+		state[dgst] = validated
+		return nil
+	}
+	return validate(root)
+}
+
+func validateOp(dgst digest.Digest, op *pb.Op) error {
+	switch o := op.Op.(type) {
+	case *pb.Op_Source:
+		if o.Source == nil || o.Source.Identifier == "" {
+			return definitionErrorf(dgst, "source without identifier")
+		}
+	case *pb.Op_Exec:
+		return validateExec(dgst, o.Exec, len(op.Inputs), platformOS(op.Platform))
+	case *pb.Op_File:
+		if o.File == nil || len(o.File.Actions) == 0 {
+			return definitionErrorf(dgst, "file op without actions")
+		}
+	case *pb.Op_Build:
+		if o.Build == nil {
+			return definitionErrorf(dgst, "empty build op")
+		}
+		names := make([]string, 0, len(o.Build.Inputs))
+		for name := range o.Build.Inputs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if inp := o.Build.Inputs[name]; inp != nil {
+				if err := checkInputIndex(dgst, "builder input "+name, inp.Input, len(op.Inputs)); err != nil {
+					return err
+				}
+			}
+		}
+	case *pb.Op_Merge:
+		if o.Merge == nil || len(o.Merge.Inputs) == 0 {
+			return definitionErrorf(dgst, "merge op without inputs")
+		}
+		for i, inp := range o.Merge.Inputs {
+			if err := checkInputIndex(dgst, fmt.Sprintf("merge input %d", i), inp.Input, len(op.Inputs)); err != nil {
+				return err
+			}
+		}
+	case *pb.Op_Diff:
+		if o.Diff == nil {
+			return definitionErrorf(dgst, "empty diff op")
+		}
+		if o.Diff.Lower != pb.Empty {
+			if err := checkInputIndex(dgst, "lower", o.Diff.Lower, len(op.Inputs)); err != nil {
+				return err
+			}
+		}
+		if err := checkInputIndex(dgst, "upper", o.Diff.Upper, len(op.Inputs)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateExec checks that the mounts have unique absolute destinations
+// including the root, that their inputs exist and that every output is set by
+// exactly one mount in the order of the mounts. The paths are the ones of
+// the execs for goos.
+func validateExec(dgst digest.Digest, e *pb.ExecOp, numInputs int, goos string) error {
+	if e == nil || e.Meta == nil || len(e.Meta.Args) == 0 {
+		return definitionErrorf(dgst, "exec without arguments")
+	}
+	if err := validateMeta(dgst, e.Meta, goos); err != nil {
+		return err
+	}
+	dests := map[string]struct{}{}
+	var outputs int
+	for _, m := range e.Mounts {
+		if !isAbsExecPath(goos, m.Dest) {
+			return definitionErrorf(dgst, "mount destination %q is not absolute", m.Dest)
+		}
+		dest := cleanExecPath(goos, m.Dest)
+		if _, ok := dests[dest]; ok {
+			return definitionErrorf(dgst, "duplicate mount destination %s", dest)
+		}
+		dests[dest] = struct{}{}
+		if m.Input != pb.Empty {
+			if err := checkInputIndex(dgst, "mount "+m.Dest, m.Input, numInputs); err != nil {
+				return err
+			}
+		}
+		if m.Output != pb.SkipOutput {
+			if int(m.Output) != outputs {
+				return definitionErrorf(dgst, "mount %s has output %d, expected %d", m.Dest, m.Output, outputs)
+			}
+			outputs++
+		}
+	}
+	if _, ok := dests[pb.RootMount]; !ok {
+		return definitionErrorf(dgst, "exec without root mount")
+	}
+	if _, ok := pb.NetMode_name[int32(e.Network)]; !ok {
+		return definitionErrorf(dgst, "unknown network mode %d", e.Network)
+	}
+	if _, ok := pb.SecurityMode_name[int32(e.Security)]; !ok {
+		return definitionErrorf(dgst, "unknown security mode %d", e.Security)
+	}
+	if h := e.Meta.Hostname; h != "" && !validHostname(h) {
+		return definitionErrorf(dgst, "invalid hostname %q", h)
+	}
+	for _, h := range e.Meta.ExtraHosts {
+		if h == nil || !validHostname(h.Host) || net.ParseIP(h.IP) == nil {
+			return definitionErrorf(dgst, "invalid extra host %v", h)
+		}
+	}
+	for _, d := range e.Devices {
+		if !path.IsAbs(d) {
+			return definitionErrorf(dgst, "device path %q is not absolute", d)
+		}
+	}
+	return nil
+}
+
+// validateMeta checks the process of an exec before it is run, the worker
+// would only fail with a generic error
+func validateMeta(dgst digest.Digest, m *pb.Meta, goos string) error {
+	if m.Args[0] == "" {
+		return definitionErrorf(dgst, "exec with an empty executable, use a shell to run a command line")
+	}
+	for i, a := range m.Args {
+		if strings.ContainsRune(a, 0) {
+			return definitionErrorf(dgst, "exec argument %d contains a NUL byte", i)
+		}
+	}
+	for _, env := range m.Env {
+		if strings.HasPrefix(env, "=") || strings.ContainsRune(env, 0) {
+			return definitionErrorf(dgst, "invalid environment variable %q", env)
+		}
+	}
+	if m.Cwd != "" && !isAbsExecPath(goos, m.Cwd) {
+		return definitionErrorf(dgst, "working directory %q is not absolute", m.Cwd)
+	}
+	return nil
+}
+
+// validHostname checks that h is a host name of dot separated labels of
+// letters, digits and hyphens
+func validHostname(h string) bool {
+	if len(h) > 253 {
+		return false
+	}
+	for _, l := range strings.Split(h, ".") {
+		if l == "" || len(l) > 63 || l[0] == '-' || l[len(l)-1] == '-' {
+			return false
+		}
+		for _, c := range l {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// numOutputs returns the number of outputs of the validated op if it is
+// known before the op is run
+func numOutputs(op *pb.Op) (int, bool) {
+	switch o := op.Op.(type) {
+	case *pb.Op_Source, *pb.Op_Merge, *pb.Op_Diff:
+		return 1, true
+	case *pb.Op_Exec:
+		var n int
+		for _, m := range o.Exec.Mounts {
+			if m.Output != pb.SkipOutput {
+				n++
+			}
+		}
+		return n, true
+	case *pb.Op_File:
+		var n int
+		for _, a := range o.File.Actions {
+			if a.Output != pb.SkipOutput {
+				n++
+			}
+		}
+		return n, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,131 @@
+package solver
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/moby/buildkit/solver/pb"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadLLBValidation(t *testing.T) {
+	src := &pb.Op{Op: &pb.Op_Source{Source: &pb.SourceOp{Identifier: "docker-image://docker.io/library/busybox:latest"}}}
+	srcDgst, srcDt := marshalTestOp(t, src)
+
+	exec := func(mounts ...*pb.Mount) *pb.Op {
+		return &pb.Op{
+			Inputs: []*pb.Input{{Digest: srcDgst}},
+			Op: &pb.Op_Exec{Exec: &pb.ExecOp{
+				Meta:   &pb.Meta{Args: []string{"true"}},
+				Mounts: mounts,
+			}},
+		}
+	}
+	load := func(op *pb.Op, index pb.OutputIndex) (digest.Digest, error) {
+		dgst, dt := marshalTestOp(t, op)
+		_, last := marshalTestOp(t, &pb.Op{Inputs: []*pb.Input{{Digest: dgst, Index: index}}})
+		_, err := LoadLLB([][]byte{srcDt, dt, last})
+		return dgst, err
+	}
+
+	dgst, err := load(exec(
+		&pb.Mount{Dest: "/", Input: 0, Output: 0},
+		&pb.Mount{Dest: "/out", Input: pb.Empty, Output: 1},
+	), 1)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		mounts []*pb.Mount
+		index  pb.OutputIndex
+		reason string
+	}{
+		{[]*pb.Mount{{Dest: "/", Input: 0, Output: 0}, {Dest: "out", Input: pb.Empty, Output: 1}}, 0, `mount destination "out" is not absolute`},
+		{[]*pb.Mount{{Dest: "/", Input: 0, Output: 0}, {Dest: "/out/", Input: pb.Empty, Output: 1}, {Dest: "/out", Input: pb.Empty, Output: 2}}, 0, "duplicate mount destination /out"},
+		{[]*pb.Mount{{Dest: "/", Input: 0, Output: 0}, {Dest: "/out", Input: pb.Empty, Output: 0}}, 0, "mount /out has output 0, expected 1"},
+		{[]*pb.Mount{{Dest: "/out", Input: 0, Output: 0}}, 0, "exec without root mount"},
+	} {
+		dgst, err := load(exec(tc.mounts...), tc.index)
+		require.Error(t, err)
+		de, ok := errors.Cause(err).(*DefinitionError)
+		require.True(t, ok, "%v", err)
+		require.Equal(t, dgst, de.Vertex)
+		require.Equal(t, tc.reason, de.Reason)
+	}
+
+	for _, meta := range []*pb.Meta{
+		{Args: []string{""}},
+		{Args: []string{"echo", "a\x00b"}},
+		{Args: []string{"true"}, Env: []string{"=foo"}},
+		{Args: []string{"true"}, Cwd: "src"},
+		{Args: []string{"true"}, Hostname: "-builder"},
+		{Args: []string{"true"}, ExtraHosts: []*pb.HostIP{{Host: "mirror", IP: "10.0.0"}}},
+		{Args: []string{"true"}, ExtraHosts: []*pb.HostIP{{Host: "mirror host", IP: "10.0.0.1"}}},
+	} {
+		op := exec(&pb.Mount{Dest: "/", Input: 0, Output: 0})
+		op.GetExec().Meta = meta
+		_, err := load(op, 0)
+		_, ok := errors.Cause(err).(*DefinitionError)
+		require.True(t, ok, "%v", err)
+	}
+	op := exec(&pb.Mount{Dest: "/", Input: 0, Output: 0})
+	op.GetExec().Meta = &pb.Meta{Args: []string{"true"}, Hostname: "builder", ExtraHosts: []*pb.HostIP{{Host: "mirror.local", IP: "::1"}}}
+	_, err = load(op, 0)
+	require.NoError(t, err)
+
+	// windows destinations can start with the system drive
+	op = exec(&pb.Mount{Dest: `C:\`, Input: 0, Output: 0}, &pb.Mount{Dest: `D:\out`, Input: pb.Empty, Output: 1})
+	op.Platform = &pb.Platform{OS: "windows", Architecture: "amd64"}
+	op.GetExec().Meta = &pb.Meta{Args: []string{"cmd"}, Cwd: `C:\src`}
+	_, err = load(op, 1)
+	require.NoError(t, err)
+	op.Platform = nil
+	_, err = load(op, 1)
+	_, ok := errors.Cause(err).(*DefinitionError)
+	require.True(t, ok, "%v", err)
+
+	dgst, err = load(exec(&pb.Mount{Dest: "/", Input: 1, Output: 0}), 0)
+	ge, ok := errors.Cause(err).(*GraphError)
+	require.True(t, ok, "%v", err)
+	require.Equal(t, &GraphError{Vertex: dgst, Ref: "mount /", Index: 1, Arity: 1}, ge)
+
+	// the output index of an input is checked by the op using it
+	dgst, err = load(exec(&pb.Mount{Dest: "/", Input: 0, Output: 0}), 1)
+	ge, ok = errors.Cause(err).(*GraphError)
+	require.True(t, ok, "%v", err)
+	require.NotEqual(t, dgst, ge.Vertex)
+	require.Equal(t, GraphError{Vertex: ge.Vertex, Ref: "input 0", Index: 1, Arity: 1, Output: true}, *ge)
+	require.Equal(t, fmt.Sprintf("invalid op %s: input 0 refers to output 1 of 1", ge.Vertex), ge.Error())
+
+	merge := &pb.Op{Inputs: []*pb.Input{{Digest: srcDgst}}, Op: &pb.Op_Merge{Merge: &pb.MergeOp{Inputs: []*pb.MergeInput{{Input: 0}, {Input: 2}}}}}
+	dgst, err = load(merge, 0)
+	ge, ok = errors.Cause(err).(*GraphError)
+	require.True(t, ok, "%v", err)
+	require.Equal(t, &GraphError{Vertex: dgst, Ref: "merge input 1", Index: 2, Arity: 1}, ge)
+
+	missing := &pb.Op{Inputs: []*pb.Input{{Digest: digest.FromBytes([]byte("missing"))}}, Op: &pb.Op_Merge{Merge: &pb.MergeOp{Inputs: []*pb.MergeInput{{Input: 0}}}}}
+	dgst, err = load(missing, 0)
+	de, ok := errors.Cause(err).(*DefinitionError)
+	require.True(t, ok, "%v", err)
+	require.Equal(t, dgst, de.Vertex)
+	require.Contains(t, de.Reason, "missing op")
+}
+
+func TestValidateDefinitionCycle(t *testing.T) {
+	a, b := digest.FromBytes([]byte("a")), digest.FromBytes([]byte("b"))
+	all := map[digest.Digest]*pb.Op{
+		a: {Inputs: []*pb.Input{{Digest: b}}, Op: &pb.Op_Merge{Merge: &pb.MergeOp{Inputs: []*pb.MergeInput{{Input: 0}}}}},
+		b: {Inputs: []*pb.Input{{Digest: a}}, Op: &pb.Op_Merge{Merge: &pb.MergeOp{Inputs: []*pb.MergeInput{{Input: 0}}}}},
+	}
+	err := validateDefinition(a, all)
+	require.Error(t, err)
+	require.Equal(t, a, err.(*DefinitionError).Vertex)
+	require.Equal(t, "cycle in definition", err.(*DefinitionError).Reason)
+}
+
+func marshalTestOp(t *testing.T, op *pb.Op) (digest.Digest, []byte) {
+	dt, err := op.Marshal()
+	require.NoError(t, err)
+	return digest.FromBytes(dt), dt
+}
@@ -5,8 +5,10 @@ import (
 	"time"
 
 	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/solver/pb"
 	"github.com/moby/buildkit/util/progress"
 	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
 	"golang.org/x/net/context"
 )
 
@@ -43,7 +45,10 @@ type vertex struct {
 	digest       digest.Digest
 	clientVertex client.Vertex
 	name         string
+	platform     *pb.Platform
+	constraints  *pb.WorkerConstraints
 	notifyMu     sync.Mutex
+	timings      vertexTimings
 }
 
 func (v *vertex) initClientVertex() {
@@ -66,6 +71,23 @@ func (v *vertex) Sys() interface{} {
 	return v.sys
 }
 
+// vertexPlatform returns the platform the vertex is run for or nil for the
+// platform of the daemon
+func vertexPlatform(v Vertex) *pb.Platform {
+	if v, ok := v.(*vertex); ok {
+		return v.platform
+	}
+	return nil
+}
+
+// vertexConstraints returns the constraints for the worker running the vertex
+func vertexConstraints(v Vertex) *pb.WorkerConstraints {
+	if v, ok := v.(*vertex); ok {
+		return v.constraints
+	}
+	return nil
+}
+
 func (v *vertex) Inputs() (inputs []Input) {
 	inputs = make([]Input, 0, len(v.inputs))
 	for _, i := range v.inputs {
@@ -83,7 +105,8 @@ func (v *vertex) inputRequiresExport(i int) bool {
 }
 
 func (v *vertex) notifyStarted(ctx context.Context) {
-	v.recursiveMarkCached(ctx)
+	v.notifyMu.Lock()
+	defer v.notifyMu.Unlock()
 	pw, _, _ := progress.FromContext(ctx)
 	defer pw.Close()
 	now := time.Now()
@@ -92,30 +115,46 @@ func (v *vertex) notifyStarted(ctx context.Context) {
 	pw.Write(v.Digest().String(), v.clientVertex)
 }
 
-func (v *vertex) notifyCompleted(ctx context.Context, cached bool, err error) {
+func (v *vertex) notifyCompleted(ctx context.Context, err error) {
+	v.notifyMu.Lock()
+	defer v.notifyMu.Unlock()
 	pw, _, _ := progress.FromContext(ctx)
 	defer pw.Close()
 	now := time.Now()
-	v.recursiveMarkCached(ctx)
 	if v.clientVertex.Started == nil {
 		v.clientVertex.Started = &now
 	}
 	v.clientVertex.Completed = &now
-	v.clientVertex.Cached = cached
+	v.clientVertex.Timings = v.timings.get()
 	if err != nil {
 		v.clientVertex.Error = err.Error()
+		if execErr, ok := errors.Cause(err).(*ExecError); ok && execErr.Vertex == v.Digest() {
+			v.clientVertex.ExecError = execErr.toClient()
+		}
 	}
 	pw.Write(v.Digest().String(), v.clientVertex)
 }
 
-func (v *vertex) recursiveMarkCached(ctx context.Context) {
-	for _, inp := range v.inputs {
-		inp.vertex.notifyMu.Lock()
-		if inp.vertex.clientVertex.Started == nil {
-			inp.vertex.recursiveMarkCached(ctx)
-			inp.vertex.notifyCompleted(ctx, true, nil)
-		}
-		inp.vertex.notifyMu.Unlock()
+// notifyCached reports that the result of the vertex was loaded from the
+// cache with the key k. The vertexes that are not needed because their
+// dependents were loaded from the cache are not reported at all.
+func (v *vertex) notifyCached(ctx context.Context, k digest.Digest) {
+	v.notifyMu.Lock()
+	defer v.notifyMu.Unlock()
+	if v.clientVertex.Cached {
+		return
 	}
-
+	pw, _, _ := progress.FromContext(ctx)
+	defer pw.Close()
+	now := time.Now()
+	if v.clientVertex.Started == nil {
+		v.clientVertex.Started = &now
+	}
+	v.clientVertex.Completed = &now
+	v.clientVertex.Cached = true
+	v.clientVertex.CacheKey = k
+	v.clientVertex.CacheMissReason = nil
+	v.clientVertex.Timings = v.timings.get()
+	metricVertexesCached.Inc()
+	pw.Write(v.Digest().String(), v.clientVertex)
 }
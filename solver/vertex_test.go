@@ -0,0 +1,86 @@
+package solver
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/util/progress"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestNotifyCached(t *testing.T) {
+	pr, ctx, cancel := progress.NewContext(context.TODO())
+	defer cancel()
+
+	v := &vertex{digest: digest.FromString("v"), name: "step"}
+	v.initClientVertex()
+	v.clientVertex.CacheMissReason = &client.CacheMissReason{Reason: client.CacheMissNoMatch}
+	k := digest.FromString("key")
+	v.notifyCached(ctx, k)
+	v.notifyCached(ctx, digest.FromString("other"))
+
+	p, err := pr.Read(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(p))
+	cv := p[0].Sys.(client.Vertex)
+	require.True(t, cv.Cached)
+	require.Equal(t, k, cv.CacheKey)
+	require.Nil(t, cv.CacheMissReason)
+	require.NotNil(t, cv.Started)
+	require.NotNil(t, cv.Completed)
+}
+
+func TestNotifyConcurrent(t *testing.T) {
+	pr, ctx, cancel := progress.NewContext(context.TODO())
+	defer cancel()
+	go func() {
+		for {
+			if _, err := pr.Read(ctx); err != nil {
+				return
+			}
+		}
+	}()
+
+	v := &vertex{digest: digest.FromString("v"), name: "step"}
+	v.initClientVertex()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			v.notifyStarted(ctx)
+		}()
+		go func() {
+			defer wg.Done()
+			v.notifyCompleted(ctx, nil)
+		}()
+		go func() {
+			defer wg.Done()
+			v.notifyCached(ctx, digest.FromString("key"))
+		}()
+	}
+	wg.Wait()
+	require.True(t, v.clientVertex.Cached)
+}
+
+func TestVertexTimings(t *testing.T) {
+	var vt vertexTimings
+	require.Nil(t, vt.get())
+
+	ctx := withTimings(context.TODO(), &vt)
+	recordPhase(ctx, phaseExec, time.Now().Add(-time.Second))
+	recordPhase(ctx, phaseExec, time.Now().Add(-time.Second))
+	recordPhase(context.TODO(), phaseCommit, time.Now().Add(-time.Second))
+	vt.record(phaseContentHash, time.Now().Add(-time.Second))
+
+	timings := vt.get()
+	require.NotNil(t, timings)
+	require.True(t, timings.Exec >= 2*time.Second)
+	require.True(t, timings.ContentHash >= time.Second)
+	require.Equal(t, time.Duration(0), timings.Commit)
+	require.Equal(t, time.Duration(0), timings.CacheLookup)
+}
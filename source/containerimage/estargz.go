@@ -0,0 +1,223 @@
+package containerimage
+
+import (
+	"io"
+	"io/ioutil"
+	"path"
+	"sync"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/remotes"
+	"github.com/containerd/containerd/rootfs"
+	"github.com/moby/buildkit/util/estargz"
+	"github.com/moby/buildkit/util/resolver"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+)
+
+const (
+	whiteoutPrefix = ".wh."
+	whiteoutOpaque = ".wh..wh..opq"
+)
+
+// estargzLayers reads the files of an image with eStargz layers from the
+// TOCs of the layers, without unpacking them. The TOCs and the files are read
+// from the registry with range requests. If the range requests fail, a layer
+// blob is fetched to the content store when a file is read from it for the
+// first time.
+type estargzLayers struct {
+	layers   []rootfs.Layer
+	provider content.Provider
+	fetcher  remotes.Fetcher
+	fetch    images.Handler
+
+	mu      sync.Mutex
+	readers map[int]*estargz.Reader
+	closers []io.Closer
+}
+
+// newEStargzLayers returns nil if not all the layers are eStargz layers. The
+// range requests are made with fetcher, the layers are fetched with fetch.
+func newEStargzLayers(layers []rootfs.Layer, provider content.Provider, fetcher remotes.Fetcher, fetch images.Handler) *estargzLayers {
+	for _, l := range layers {
+		if !estargz.IsEStargz(l.Blob.Annotations) {
+			return nil
+		}
+	}
+	return &estargzLayers{
+		layers:   layers,
+		provider: provider,
+		fetcher:  fetcher,
+		fetch:    fetch,
+		readers:  map[int]*estargz.Reader{},
+	}
+}
+
+func (l *estargzLayers) reader(ctx context.Context, i int) (*estargz.Reader, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if r, ok := l.readers[i]; ok {
+		return r, nil
+	}
+	desc := l.layers[i].Blob
+	ra, err := l.provider.ReaderAt(ctx, desc.Digest)
+	if errdefs.IsNotFound(err) && l.fetcher != nil {
+		r, err := estargz.Open(io.NewSectionReader(newRemoteReaderAt(l.fetcher, desc), 0, desc.Size))
+		if err == nil {
+			l.readers[i] = r
+			return r, nil
+		}
+		logrus.Debugf("failed to read the TOC of %s with range requests, fetching the layer: %v", desc.Digest, err)
+	}
+	if errdefs.IsNotFound(err) {
+		if _, err := l.fetch.Handle(ctx, desc); err != nil {
+			return nil, err
+		}
+		ra, err = l.provider.ReaderAt(ctx, desc.Digest)
+	}
+	if err != nil {
+		return nil, err
+	}
+	r, err := estargz.Open(io.NewSectionReader(ra, 0, ra.Size()))
+	if err != nil {
+		ra.Close()
+		return nil, errors.Wrapf(err, "failed to open layer %s", desc.Digest)
+	}
+	l.readers[i] = r
+	l.closers = append(l.closers, ra)
+	return r, nil
+}
+
+// remoteBlockSize is the size of the ranges that are fetched from the
+// registry, the gzip streams are read in much smaller parts
+const remoteBlockSize = 1 << 20
+
+// remoteReaderAt reads a layer blob from the registry with range requests.
+// The last fetched blocks are kept for the following reads.
+type remoteReaderAt struct {
+	fetcher remotes.Fetcher
+	desc    ocispec.Descriptor
+
+	mu     sync.Mutex
+	blocks map[int64][]byte
+	order  []int64
+}
+
+func newRemoteReaderAt(fetcher remotes.Fetcher, desc ocispec.Descriptor) *remoteReaderAt {
+	return &remoteReaderAt{
+		fetcher: fetcher,
+		desc:    desc,
+		blocks:  map[int64][]byte{},
+	}
+}
+
+func (r *remoteReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	var n int
+	for n < len(p) {
+		pos := off + int64(n)
+		if pos >= r.desc.Size {
+			return n, io.EOF
+		}
+		b, err := r.block(pos / remoteBlockSize)
+		if err != nil {
+			return n, err
+		}
+		n += copy(p[n:], b[pos%remoteBlockSize:])
+	}
+	return n, nil
+}
+
+func (r *remoteReaderAt) block(i int64) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if b, ok := r.blocks[i]; ok {
+		return b, nil
+	}
+	offset := i * remoteBlockSize
+	size := r.desc.Size - offset
+	if size > remoteBlockSize {
+		size = remoteBlockSize
+	}
+	// the readers of the layers outlive the requests that open them
+	ctx := resolver.WithRange(context.Background(), r.desc.Digest, offset, size)
+	rc, err := r.fetcher.Fetch(ctx, r.desc)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	b := make([]byte, size)
+	if _, err := io.ReadFull(rc, b); err != nil {
+		return nil, errors.Wrapf(err, "failed to read range %d-%d of %s", offset, offset+size, r.desc.Digest)
+	}
+	if len(r.order) == 4 {
+		delete(r.blocks, r.order[0])
+		r.order = r.order[1:]
+	}
+	r.blocks[i] = b
+	r.order = append(r.order, i)
+	return b, nil
+}
+
+// readFile reads the regular file p from the top layer that contains it. The
+// files that are removed by a whiteout, or that are behind a link, return an
+// error so that they are read from the unpacked snapshot instead.
+func (l *estargzLayers) readFile(ctx context.Context, p string) ([]byte, error) {
+	name := path.Clean("/" + p)[1:]
+	if name == "" {
+		return nil, errors.Errorf("%s is not a regular file", p)
+	}
+	for i := len(l.layers) - 1; i >= 0; i-- {
+		r, err := l.reader(ctx, i)
+		if err != nil {
+			return nil, err
+		}
+		if e, ok := r.Lookup(name); ok {
+			if e.Type != "reg" {
+				return nil, errors.Errorf("%s is not a regular file", p)
+			}
+			f, err := r.OpenFile(name)
+			if err != nil {
+				return nil, err
+			}
+			return ioutil.ReadAll(f)
+		}
+		if hidden(r, name) {
+			return nil, errors.Errorf("%s is hidden by layer %s", p, l.layers[i].Blob.Digest)
+		}
+	}
+	return nil, errors.Errorf("%s not found in the layers", p)
+}
+
+// hidden checks if the layer of r removes name or any of its parent
+// directories, or replaces the parent directories with other files
+func hidden(r *estargz.Reader, name string) bool {
+	for n := name; n != "."; n = path.Dir(n) {
+		if _, ok := r.Lookup(path.Join(path.Dir(n), whiteoutPrefix+path.Base(n))); ok {
+			return true
+		}
+		if n == name {
+			continue
+		}
+		if e, ok := r.Lookup(n); ok && e.Type != "dir" {
+			return true
+		}
+		if _, ok := r.Lookup(path.Join(n, whiteoutOpaque)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *estargzLayers) close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, c := range l.closers {
+		c.Close()
+	}
+	l.closers = nil
+	l.readers = map[int]*estargz.Reader{}
+}
@@ -0,0 +1,188 @@
+package containerimage
+
+import (
+	"archive/tar"
+	"bytes"
+	gocontext "context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/rootfs"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/util/estargz"
+	"github.com/moby/buildkit/util/resolver"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestEStargzLayers(t *testing.T) {
+	provider := &testProvider{blobs: map[digest.Digest][]byte{}}
+	remote := map[digest.Digest][]byte{}
+	var fetched []digest.Digest
+	fetch := images.HandlerFunc(func(ctx gocontext.Context, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+		fetched = append(fetched, desc.Digest)
+		provider.blobs[desc.Digest] = remote[desc.Digest]
+		return nil, nil
+	})
+
+	var layers []rootfs.Layer
+	for _, files := range []map[string]string{
+		{"etc/": "", "etc/foo": "foo0", "etc/bar": "bar0", "etc/baz": "baz0"},
+		{"etc/foo": "foo1", "etc/.wh.bar": ""},
+	} {
+		dt := testEStargzLayer(t, files)
+		dgst := digest.FromBytes(dt)
+		remote[dgst] = dt
+		layers = append(layers, rootfs.Layer{Blob: ocispec.Descriptor{
+			Digest:      dgst,
+			Size:        int64(len(dt)),
+			Annotations: map[string]string{estargz.TOCDigestAnnotation: "sha256:toc"},
+		}})
+	}
+
+	l := newEStargzLayers(layers, provider, nil, fetch)
+	require.NotNil(t, l)
+	defer l.close()
+
+	dt, err := l.readFile(context.TODO(), "/etc/foo")
+	require.NoError(t, err)
+	require.Equal(t, "foo1", string(dt))
+	require.Equal(t, []digest.Digest{layers[1].Blob.Digest}, fetched)
+
+	dt, err = l.readFile(context.TODO(), "etc/baz")
+	require.NoError(t, err)
+	require.Equal(t, "baz0", string(dt))
+	require.Equal(t, 2, len(fetched))
+
+	_, err = l.readFile(context.TODO(), "/etc/bar")
+	require.Error(t, err)
+	_, err = l.readFile(context.TODO(), "/etc")
+	require.Error(t, err)
+	_, err = l.readFile(context.TODO(), "/missing")
+	require.Error(t, err)
+
+	layers[0].Blob.Annotations = nil
+	require.Nil(t, newEStargzLayers(layers, provider, nil, fetch))
+}
+
+func TestEStargzLayersRange(t *testing.T) {
+	// the file that isn't read fills the blocks between etc/foo and the TOC
+	big := make([]byte, 3*remoteBlockSize)
+	_, err := rand.Read(big)
+	require.NoError(t, err)
+	dt := testEStargzLayer(t, map[string]string{"etc/": "", "etc/bar": string(big), "etc/foo": "foo0"})
+	dgst := digest.FromBytes(dt)
+	var ranges []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/test/blobs/"+dgst.String() {
+			http.NotFound(w, r)
+			return
+		}
+		ranges = append(ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(dt))
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	registries, err := resolver.NewRegistries(resolver.Config{u.Host: {PlainHTTP: true}})
+	require.NoError(t, err)
+	fetcher, err := registries.Resolver(nil).Fetcher(context.TODO(), u.Host+"/test:latest")
+	require.NoError(t, err)
+
+	fetch := images.HandlerFunc(func(ctx gocontext.Context, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+		return nil, errors.Errorf("unexpected fetch of %s", desc.Digest)
+	})
+	layers := []rootfs.Layer{{Blob: ocispec.Descriptor{
+		Digest:      dgst,
+		Size:        int64(len(dt)),
+		Annotations: map[string]string{estargz.TOCDigestAnnotation: "sha256:toc"},
+	}}}
+	l := newEStargzLayers(layers, &testProvider{blobs: map[digest.Digest][]byte{}}, fetcher, fetch)
+	defer l.close()
+
+	out, err := l.readFile(context.TODO(), "etc/foo")
+	require.NoError(t, err)
+	require.Equal(t, "foo0", string(out))
+	last := int64(len(dt)-1) / remoteBlockSize * remoteBlockSize
+	require.Equal(t, []string{fmt.Sprintf("bytes=%d-%d", last, len(dt)-1), fmt.Sprintf("bytes=0-%d", remoteBlockSize-1)}, ranges)
+}
+
+func TestLazyRefReadFile(t *testing.T) {
+	dt := testEStargzLayer(t, map[string]string{"foo": "foo0"})
+	dgst := digest.FromBytes(dt)
+	provider := &testProvider{blobs: map[digest.Digest][]byte{dgst: dt}}
+	layers := []rootfs.Layer{{Blob: ocispec.Descriptor{
+		Digest:      dgst,
+		Size:        int64(len(dt)),
+		Annotations: map[string]string{estargz.TOCDigestAnnotation: "sha256:toc"},
+	}}}
+
+	r := &lazyRef{id: "sha256:foo", files: newEStargzLayers(layers, provider, nil, nil), pull: func(context.Context) (cache.ImmutableRef, error) {
+		return &testRef{}, nil
+	}}
+	out, err := r.ReadFile(context.TODO(), "foo")
+	require.NoError(t, err)
+	require.Equal(t, "foo0", string(out))
+
+	// the pulled snapshot is mounted instead
+	_, err = r.Mount(context.TODO(), true)
+	require.NoError(t, err)
+	_, err = r.ReadFile(context.TODO(), "foo")
+	require.Error(t, err)
+}
+
+func testEStargzLayer(t *testing.T, files map[string]string) []byte {
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	for _, name := range []string{"etc/", "etc/foo", "etc/bar", "etc/baz", "etc/.wh.bar", "foo"} {
+		dt, ok := files[name]
+		if !ok {
+			continue
+		}
+		h := &tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(dt))}
+		if name == "etc/" {
+			h = &tar.Header{Name: name, Typeflag: tar.TypeDir, Mode: 0755}
+		}
+		require.NoError(t, tw.WriteHeader(h))
+		_, err := tw.Write([]byte(dt))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+
+	layer := &bytes.Buffer{}
+	_, err := estargz.Build(layer, bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	return layer.Bytes()
+}
+
+type testProvider struct {
+	blobs map[digest.Digest][]byte
+}
+
+func (p *testProvider) ReaderAt(ctx gocontext.Context, dgst digest.Digest) (content.ReaderAt, error) {
+	dt, ok := p.blobs[dgst]
+	if !ok {
+		return nil, errors.Wrapf(errdefs.ErrNotFound, "blob %s", dgst)
+	}
+	return &testReaderAt{bytes.NewReader(dt)}, nil
+}
+
+type testReaderAt struct {
+	*bytes.Reader
+}
+
+func (r *testReaderAt) Close() error {
+	return nil
+}
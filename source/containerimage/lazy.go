@@ -0,0 +1,116 @@
+package containerimage
+
+import (
+	"sync"
+
+	"github.com/containerd/containerd/mount"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/cache/metadata"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// lazyRef is a reference to an image snapshot that has not been unpacked.
+// The layers are pulled and unpacked when the data of the snapshot is needed
+// for the first time. The ID is the chain ID of the layers, so it matches the
+// snapshot that is created by the pull. The files of eStargz images can be
+// read before the pull. The parent is nil until the ref is resolved.
+type lazyRef struct {
+	id    string
+	md    *metadata.StorageItem
+	pull  func(context.Context) (cache.ImmutableRef, error)
+	files *estargzLayers
+
+	mu  sync.Mutex
+	ref cache.ImmutableRef
+}
+
+// Resolve pulls the image if it hasn't been pulled yet. The pulled ref is
+// released with the lazy ref.
+func (r *lazyRef) Resolve(ctx context.Context) (cache.ImmutableRef, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.ref == nil {
+		ref, err := r.pull(ctx)
+		if err != nil {
+			return nil, err
+		}
+		r.ref = ref
+	}
+	return r.ref, nil
+}
+
+func (r *lazyRef) ID() string {
+	return r.id
+}
+
+func (r *lazyRef) Mount(ctx context.Context, readonly bool) ([]mount.Mount, error) {
+	ref, err := r.Resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ref.Mount(ctx, readonly)
+}
+
+// ReadFile reads the file p from the eStargz layers of the image if the
+// snapshot hasn't been pulled yet
+func (r *lazyRef) ReadFile(ctx context.Context, p string) ([]byte, error) {
+	r.mu.Lock()
+	pulled := r.ref != nil
+	r.mu.Unlock()
+	if r.files == nil || pulled {
+		return nil, errors.Errorf("%s can't be read without mounting", p)
+	}
+	return r.files.readFile(ctx, p)
+}
+
+func (r *lazyRef) Release(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.files != nil {
+		r.files.close()
+	}
+	if r.ref == nil {
+		return nil
+	}
+	ref := r.ref
+	r.ref = nil
+	return ref.Release(ctx)
+}
+
+func (r *lazyRef) Size(ctx context.Context) (int64, error) {
+	ref, err := r.Resolve(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return ref.Size(ctx)
+}
+
+func (r *lazyRef) Parent() cache.ImmutableRef {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.ref == nil {
+		return nil
+	}
+	return r.ref.Parent()
+}
+
+func (r *lazyRef) Finalize(ctx context.Context) error {
+	ref, err := r.Resolve(ctx)
+	if err != nil {
+		return err
+	}
+	return ref.Finalize(ctx)
+}
+
+// Metadata returns the metadata of the snapshot. Values that are committed
+// before the pull are loaded by the cache manager when the snapshot is
+// created.
+func (r *lazyRef) Metadata() *metadata.StorageItem {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.ref != nil {
+		return r.ref.Metadata()
+	}
+	return r.md
+}
@@ -0,0 +1,48 @@
+package containerimage
+
+import (
+	"testing"
+
+	"github.com/containerd/containerd/mount"
+	"github.com/moby/buildkit/cache"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+type testRef struct {
+	cache.ImmutableRef
+	released bool
+}
+
+func (r *testRef) Mount(ctx context.Context, readonly bool) ([]mount.Mount, error) {
+	return []mount.Mount{{Type: "bind"}}, nil
+}
+
+func (r *testRef) Release(ctx context.Context) error {
+	r.released = true
+	return nil
+}
+
+func TestLazyRef(t *testing.T) {
+	var pulls int
+	tr := &testRef{}
+	r := &lazyRef{id: "sha256:foo", pull: func(context.Context) (cache.ImmutableRef, error) {
+		pulls++
+		return tr, nil
+	}}
+
+	require.Equal(t, "sha256:foo", r.ID())
+	require.Nil(t, r.Metadata())
+	require.NoError(t, r.Release(context.TODO()))
+	require.Equal(t, 0, pulls)
+
+	for i := 0; i < 2; i++ {
+		m, err := r.Mount(context.TODO(), true)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(m))
+	}
+	require.Equal(t, 1, pulls)
+
+	require.NoError(t, r.Release(context.TODO()))
+	require.True(t, tr.released)
+}
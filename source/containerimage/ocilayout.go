@@ -0,0 +1,259 @@
+package containerimage
+
+import (
+	gocontext "context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/containerd/containerd/fs"
+	"github.com/containerd/containerd/reference"
+	"github.com/containerd/containerd/remotes"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/snapshot"
+	"github.com/moby/buildkit/source"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// NewOCILayoutSource creates a source that reads images from OCI image
+// layout directories. The layouts of the client are transferred with
+// localSource. The paths of the layouts of the daemon are relative to root,
+// and the builds can only use the layouts of the client if root is empty.
+func NewOCILayoutSource(opt SourceOpt, localSource source.Source, root string) (source.Source, error) {
+	is, err := NewSource(opt)
+	if err != nil {
+		return nil, err
+	}
+	return &ociLayoutSource{is: is.(*imageSource), local: localSource, root: root}, nil
+}
+
+type ociLayoutSource struct {
+	is    *imageSource
+	local source.Source
+	root  string
+}
+
+func (ls *ociLayoutSource) ID() string {
+	return source.OCILayoutScheme
+}
+
+func (ls *ociLayoutSource) Resolve(ctx context.Context, id source.Identifier) (source.SourceInstance, error) {
+	layoutIdentifier, ok := id.(*source.OCILayoutIdentifier)
+	if !ok {
+		return nil, errors.Errorf("invalid oci layout identifier %v", id)
+	}
+	if layoutIdentifier.Local && ls.local == nil {
+		return nil, errors.Errorf("oci layouts of the client are not supported")
+	}
+	var dir string
+	if !layoutIdentifier.Local {
+		if ls.root == "" {
+			return nil, errors.Errorf("oci layouts of the daemon are not enabled, use the layout of a local directory of the client")
+		}
+		var err error
+		// the symlinks of the layout can't point outside of the root
+		if dir, err = fs.RootPath(ls.root, layoutIdentifier.Path); err != nil {
+			return nil, errors.Wrapf(err, "invalid oci layout path %s", layoutIdentifier.Path)
+		}
+	}
+
+	spec := reference.Spec{Locator: source.OCILayoutScheme + "/" + layoutIdentifier.Path, Object: layoutIdentifier.Tag}
+	if layoutIdentifier.Digest != "" {
+		spec.Object = "@" + layoutIdentifier.Digest.String()
+	}
+	inst := &ociLayoutInstance{
+		src:   layoutIdentifier,
+		local: ls.local,
+		dir:   dir,
+	}
+	inst.puller = &puller{
+		src:      &source.ImageIdentifier{Reference: spec},
+		is:       ls.is,
+		resolver: &layoutResolver{src: layoutIdentifier},
+	}
+	return inst, nil
+}
+
+// ociLayoutInstance keeps the layout open while the image is resolved or
+// pulled. The layers are always pulled right away as the layout of the
+// client is not available after the build.
+type ociLayoutInstance struct {
+	*puller
+	src   *source.OCILayoutIdentifier
+	local source.Source
+	// dir is the layout of the daemon, empty for a layout of the client
+	dir string
+}
+
+func (li *ociLayoutInstance) CacheKey(ctx context.Context) (string, error) {
+	ctx, release, err := li.open(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+	return li.puller.CacheKey(ctx)
+}
+
+func (li *ociLayoutInstance) Snapshot(ctx context.Context) (cache.ImmutableRef, error) {
+	ctx, release, err := li.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	if err := li.puller.resolve(ctx); err != nil {
+		return nil, err
+	}
+	return li.puller.pull(ctx)
+}
+
+// open returns the context of the resolver with the directory of the layout.
+// The layout of the client is transferred for every call and released with
+// the returned function.
+func (li *ociLayoutInstance) open(ctx context.Context) (context.Context, func(), error) {
+	if !li.src.Local {
+		return withLayoutDir(ctx, li.dir), func() {}, nil
+	}
+
+	si, err := li.local.Resolve(ctx, &source.LocalIdentifier{Name: li.src.Path, SessionID: li.src.SessionID})
+	if err != nil {
+		return nil, nil, err
+	}
+	ref, err := si.Snapshot(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	mount, err := ref.Mount(ctx, true)
+	if err != nil {
+		ref.Release(context.TODO())
+		return nil, nil, err
+	}
+	lm := snapshot.LocalMounter(mount)
+	dir, err := lm.Mount()
+	if err != nil {
+		ref.Release(context.TODO())
+		return nil, nil, err
+	}
+	return withLayoutDir(ctx, dir), func() {
+		lm.Unmount()
+		ref.Release(context.TODO())
+	}, nil
+}
+
+type layoutDirKeyT string
+
+var layoutDirKey = layoutDirKeyT("buildkit/ocilayout/dir")
+
+func withLayoutDir(ctx context.Context, dir string) context.Context {
+	return context.WithValue(ctx, layoutDirKey, dir)
+}
+
+func layoutDir(ctx gocontext.Context) (string, error) {
+	dir, ok := ctx.Value(layoutDirKey).(string)
+	if !ok || dir == "" {
+		return "", errors.Errorf("oci layout is not open")
+	}
+	return dir, nil
+}
+
+// layoutResolver resolves the references from the index of the layout open
+// in the context
+type layoutResolver struct {
+	src *source.OCILayoutIdentifier
+}
+
+func (r *layoutResolver) Resolve(ctx gocontext.Context, ref string) (string, ocispec.Descriptor, error) {
+	dir, err := layoutDir(ctx)
+	if err != nil {
+		return "", ocispec.Descriptor{}, err
+	}
+	desc, err := resolveLayout(dir, r.src.Tag, r.src.Digest)
+	if err != nil {
+		return "", ocispec.Descriptor{}, errors.Wrapf(err, "failed to resolve %s", ref)
+	}
+	return ref, desc, nil
+}
+
+func (r *layoutResolver) Fetcher(ctx gocontext.Context, ref string) (remotes.Fetcher, error) {
+	dir, err := layoutDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return layoutFetcher(dir), nil
+}
+
+func (r *layoutResolver) Pusher(ctx gocontext.Context, ref string) (remotes.Pusher, error) {
+	return nil, errors.Errorf("pushing to an oci layout is not supported")
+}
+
+type layoutFetcher string
+
+func (dir layoutFetcher) Fetch(ctx gocontext.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+	if err := desc.Digest.Validate(); err != nil {
+		return nil, err
+	}
+	p, err := fs.RootPath(string(dir), blobPath("", desc.Digest))
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(p)
+}
+
+func blobPath(dir string, dgst digest.Digest) string {
+	return filepath.Join(dir, "blobs", dgst.Algorithm().String(), dgst.Hex())
+}
+
+// resolveLayout finds the descriptor of the manifest with the tag or the
+// digest from the index of the layout in dir
+func resolveLayout(dir, tag string, dgst digest.Digest) (ocispec.Descriptor, error) {
+	p, err := fs.RootPath(dir, "index.json")
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	dt, err := ioutil.ReadFile(p)
+	if err != nil {
+		return ocispec.Descriptor{}, errors.Wrap(err, "failed to read oci layout index")
+	}
+	var idx ocispec.Index
+	if err := json.Unmarshal(dt, &idx); err != nil {
+		return ocispec.Descriptor{}, errors.Wrap(err, "failed to parse oci layout index")
+	}
+
+	for _, m := range idx.Manifests {
+		switch {
+		case dgst != "":
+			if m.Digest == dgst {
+				return m, nil
+			}
+		case tag != "":
+			if m.Annotations[ocispec.AnnotationRefName] == tag {
+				return m, nil
+			}
+		}
+	}
+
+	if dgst != "" {
+		// the manifest doesn't need to be in the index if it is referenced
+		// by digest
+		p, err := fs.RootPath(dir, blobPath("", dgst))
+		if err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		fi, err := os.Stat(p)
+		if err != nil {
+			return ocispec.Descriptor{}, errors.Wrapf(err, "%s not found", dgst)
+		}
+		return ocispec.Descriptor{MediaType: ocispec.MediaTypeImageManifest, Digest: dgst, Size: fi.Size()}, nil
+	}
+	if tag == "" && len(idx.Manifests) == 1 {
+		return idx.Manifests[0], nil
+	}
+	if tag == "" {
+		return ocispec.Descriptor{}, errors.Errorf("tag required for oci layout with %d manifests", len(idx.Manifests))
+	}
+	return ocispec.Descriptor{}, errors.Errorf("tag %s not found", tag)
+}
@@ -0,0 +1,89 @@
+package containerimage
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/moby/buildkit/source"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveLayout(t *testing.T) {
+	dir, err := ioutil.TempDir("", "buildkit-ocilayout")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeBlob := func(dt []byte) ocispec.Descriptor {
+		dgst := digest.FromBytes(dt)
+		p := blobPath(dir, dgst)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0700))
+		require.NoError(t, ioutil.WriteFile(p, dt, 0600))
+		return ocispec.Descriptor{MediaType: ocispec.MediaTypeImageManifest, Digest: dgst, Size: int64(len(dt))}
+	}
+
+	m1 := writeBlob([]byte("manifest1"))
+	m1.Annotations = map[string]string{ocispec.AnnotationRefName: "v1"}
+	m2 := writeBlob([]byte("manifest2"))
+	m3 := writeBlob([]byte("manifest3"))
+
+	dt, err := json.Marshal(ocispec.Index{Manifests: []ocispec.Descriptor{m1, m2}})
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "index.json"), dt, 0600))
+
+	desc, err := resolveLayout(dir, "v1", "")
+	require.NoError(t, err)
+	require.Equal(t, m1.Digest, desc.Digest)
+
+	desc, err = resolveLayout(dir, "", m2.Digest)
+	require.NoError(t, err)
+	require.Equal(t, m2.Digest, desc.Digest)
+
+	desc, err = resolveLayout(dir, "", m3.Digest)
+	require.NoError(t, err)
+	require.Equal(t, m3.Digest, desc.Digest)
+	require.Equal(t, m3.Size, desc.Size)
+
+	_, err = resolveLayout(dir, "v2", "")
+	require.Error(t, err)
+
+	_, err = resolveLayout(dir, "", "")
+	require.Error(t, err)
+
+	rc, err := layoutFetcher(dir).Fetch(context.TODO(), m2)
+	require.NoError(t, err)
+	defer rc.Close()
+	dt, err = ioutil.ReadAll(rc)
+	require.NoError(t, err)
+	require.Equal(t, "manifest2", string(dt))
+}
+
+func TestOCILayoutRoot(t *testing.T) {
+	root, err := ioutil.TempDir("", "buildkit-ocilayout")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	require.NoError(t, os.Symlink("/images/foo", filepath.Join(root, "abs")))
+	require.NoError(t, os.Symlink("../../../etc", filepath.Join(root, "rel")))
+
+	ls := &ociLayoutSource{}
+	_, err = ls.Resolve(context.TODO(), &source.OCILayoutIdentifier{Path: "images/foo"})
+	require.Error(t, err)
+
+	ls.root = root
+	for p, expected := range map[string]string{
+		"images/foo":       "images/foo",
+		"/images/foo":      "images/foo",
+		"../../images/foo": "images/foo",
+		"abs":              "images/foo",
+		"rel":              "etc",
+	} {
+		inst, err := ls.Resolve(context.TODO(), &source.OCILayoutIdentifier{Path: p})
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(root, expected), inst.(*ociLayoutInstance).dir, p)
+	}
+}
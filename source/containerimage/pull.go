@@ -3,7 +3,6 @@ package containerimage
 import (
 	gocontext "context"
 	"fmt"
-	"net/http"
 	"sync"
 	"time"
 
@@ -13,18 +12,23 @@ import (
 	"github.com/containerd/containerd/images"
 	"github.com/containerd/containerd/platforms"
 	"github.com/containerd/containerd/remotes"
-	"github.com/containerd/containerd/remotes/docker"
 	"github.com/containerd/containerd/rootfs"
 	"github.com/containerd/containerd/snapshot"
 	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/cache/metadata"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/auth"
 	"github.com/moby/buildkit/source"
 	"github.com/moby/buildkit/util/imageutil"
 	"github.com/moby/buildkit/util/progress"
+	"github.com/moby/buildkit/util/resolver"
 	digest "github.com/opencontainers/go-digest"
 	"github.com/opencontainers/image-spec/identity"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	"golang.org/x/net/context"
+	"golang.org/x/sync/errgroup"
 )
 
 // TODO: break apart containerd specifics like contentstore so the resolver
@@ -35,8 +39,24 @@ type SourceOpt struct {
 	ContentStore  content.Store
 	Applier       rootfs.Applier
 	CacheAccessor cache.Accessor
+	// MetadataStore enables lazy snapshots that are pulled on first use
+	MetadataStore *metadata.Store
+	// MaxConcurrentDownloads limits the number of layers that are downloaded
+	// at the same time for a pull
+	MaxConcurrentDownloads int
+	// Registries configures the access to the registries. The defaults are
+	// used if it is nil.
+	Registries *resolver.Registries
+	// SessionManager is used for requesting the registry credentials from
+	// the client of the build
+	SessionManager *session.Manager
+	// EStargz reads the files of the lazy snapshots of eStargz images from
+	// the layer blobs without unpacking them
+	EStargz bool
 }
 
+const defaultMaxConcurrentDownloads = 3
+
 type blobmapper interface {
 	GetBlob(ctx gocontext.Context, key string) (digest.Digest, error)
 	SetBlob(ctx gocontext.Context, key string, blob digest.Digest) error
@@ -49,17 +69,15 @@ type resolveRecord struct {
 
 type imageSource struct {
 	SourceOpt
-	resolver remotes.Resolver
-	lru      map[string]resolveRecord
+	resolveCache *resolveCache
+	lru          map[string]resolveRecord
 }
 
 func NewSource(opt SourceOpt) (source.Source, error) {
 	is := &imageSource{
-		SourceOpt: opt,
-		lru:       map[string]resolveRecord{},
-		resolver: newCachedResolver(docker.NewResolver(docker.ResolverOptions{
-			Client: http.DefaultClient,
-		}), 5*time.Second),
+		SourceOpt:    opt,
+		lru:          map[string]resolveRecord{},
+		resolveCache: newResolveCache(5 * time.Second),
 	}
 
 	if _, ok := opt.Snapshotter.(blobmapper); !ok {
@@ -73,8 +91,20 @@ func (is *imageSource) ID() string {
 	return source.DockerImageScheme
 }
 
-func (is *imageSource) ResolveImageConfig(ctx context.Context, ref string) ([]byte, error) {
-	return imageutil.Config(ctx, ref, is.resolver, is.ContentStore)
+// resolver returns a resolver that uses the registry credentials of the
+// client session in ctx
+func (is *imageSource) resolver(ctx context.Context) remotes.Resolver {
+	return &cachedResolver{
+		Resolver:     is.Registries.Resolver(auth.SessionCredentials(ctx, is.SessionManager)),
+		resolveCache: is.resolveCache,
+		session:      session.FromContext(ctx),
+	}
+}
+
+// ResolveImageConfig returns the manifest digest and the config of the image
+// ref for platform with the registry credentials of the client session
+func (is *imageSource) ResolveImageConfig(ctx context.Context, ref string, platform *ocispec.Platform) (digest.Digest, []byte, error) {
+	return imageutil.Config(ctx, ref, is.resolver(ctx), is.ContentStore, platform)
 }
 
 func (is *imageSource) Resolve(ctx context.Context, id source.Identifier) (source.SourceInstance, error) {
@@ -84,14 +114,16 @@ func (is *imageSource) Resolve(ctx context.Context, id source.Identifier) (sourc
 	}
 
 	p := &puller{
-		src: imageIdentifier,
-		is:  is,
+		src:      imageIdentifier,
+		is:       is,
+		resolver: is.resolver(ctx),
 	}
 	return p, nil
 }
 
 type puller struct {
 	is          *imageSource
+	resolver    remotes.Resolver
 	resolveOnce sync.Once
 	src         *source.ImageIdentifier
 	desc        ocispec.Descriptor
@@ -118,7 +150,7 @@ func (p *puller) resolve(ctx context.Context) error {
 			}
 		}
 
-		ref, desc, err := p.is.resolver.Resolve(ctx, p.src.Reference.String())
+		ref, desc, err := p.resolver.Resolve(ctx, p.src.Reference.String())
 		if err != nil {
 			p.resolveErr = err
 			resolveProgressDone(err)
@@ -135,21 +167,98 @@ func (p *puller) CacheKey(ctx context.Context) (string, error) {
 	if err := p.resolve(ctx); err != nil {
 		return "", err
 	}
+	// the digest of a manifest list is the same for all the platforms
+	if p.src.Platform != nil {
+		if pl := p.platform(); pl != platforms.Format(platforms.Default()) {
+			return p.desc.Digest.String() + "@" + pl, nil
+		}
+	}
 	return p.desc.Digest.String(), nil
 }
 
+// platform returns the platform of the image that is pulled from a manifest
+// list
+func (p *puller) platform() string {
+	if p.src.Platform == nil {
+		return platforms.Format(platforms.Default())
+	}
+	return platforms.Format(platforms.Normalize(*p.src.Platform))
+}
+
 func (p *puller) Snapshot(ctx context.Context) (cache.ImmutableRef, error) {
 	if err := p.resolve(ctx); err != nil {
 		return nil, err
 	}
 
+	if p.is.MetadataStore != nil {
+		ref, err := p.lazySnapshot(ctx)
+		if err == nil {
+			return ref, nil
+		}
+		logrus.Debugf("failed to create lazy snapshot for %s, pulling: %v", p.ref, err)
+	}
+	return p.pull(ctx)
+}
+
+// lazySnapshot fetches only the manifest and the config of the image. If the
+// layers haven't been unpacked before, they are pulled when the returned ref
+// is used for the first time.
+func (p *puller) lazySnapshot(ctx context.Context) (cache.ImmutableRef, error) {
+	fetcher, err := p.resolver.Fetcher(ctx, p.ref)
+	if err != nil {
+		return nil, err
+	}
+
+	layers, err := p.fetchLayers(ctx, remotes.FetchHandler(p.is.ContentStore, fetcher))
+	if err != nil {
+		return nil, err
+	}
+	if len(layers) == 0 {
+		return nil, errors.Errorf("no layers in %s", p.ref)
+	}
+	diffIDs := make([]digest.Digest, len(layers))
+	for i, l := range layers {
+		diffIDs[i] = l.Diff.Digest
+	}
+	chainID := identity.ChainID(diffIDs).String()
+
+	if ref, err := p.is.CacheAccessor.Get(ctx, chainID, p.description()); err == nil {
+		return ref, nil
+	}
+
+	md, _ := p.is.MetadataStore.Get(chainID)
+	r := &lazyRef{id: chainID, md: md, pull: p.pull}
+	if p.is.EStargz {
+		r.files = newEStargzLayers(layers, p.is.ContentStore, fetcher, remotes.FetchHandler(p.is.ContentStore, fetcher))
+	}
+	return r, nil
+}
+
+// fetchLayers fetches the manifest and the config of the image with fetch
+// and returns the layers without downloading them
+func (p *puller) fetchLayers(ctx context.Context, fetch images.Handler) ([]rootfs.Layer, error) {
+	handlers := []images.Handler{
+		fetch,
+		withoutLayers(images.ChildrenHandler(p.is.ContentStore)),
+	}
+	if err := images.Dispatch(ctx, images.Handlers(handlers...), p.desc); err != nil {
+		return nil, err
+	}
+	return getLayers(ctx, p.is.ContentStore, p.desc, p.platform())
+}
+
+func (p *puller) description() cache.RefOption {
+	return cache.WithDescription(fmt.Sprintf("pulled from %s", p.ref))
+}
+
+func (p *puller) pull(ctx context.Context) (cache.ImmutableRef, error) {
 	ongoing := newJobs(p.ref)
 
 	pctx, stopProgress := context.WithCancel(ctx)
 
 	go showProgress(pctx, ongoing, p.is.ContentStore)
 
-	fetcher, err := p.is.resolver.Fetcher(ctx, p.ref)
+	fetcher, err := p.resolver.Fetcher(ctx, p.ref)
 	if err != nil {
 		stopProgress()
 		return nil, err
@@ -158,38 +267,102 @@ func (p *puller) Snapshot(ctx context.Context) (cache.ImmutableRef, error) {
 	// TODO: need a wrapper snapshot interface that combines content
 	// and snapshots as 1) buildkit shouldn't have a dependency on contentstore
 	// or 2) cachemanager should manage the contentstore
-	handlers := []images.Handler{
+	fetch := images.Handlers(
 		images.HandlerFunc(func(ctx gocontext.Context, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
 			ongoing.add(desc)
 			return nil, nil
 		}),
 		remotes.FetchHandler(p.is.ContentStore, fetcher),
-		images.ChildrenHandler(p.is.ContentStore),
-	}
-	if err := images.Dispatch(ctx, images.Handlers(handlers...), p.desc); err != nil {
+	)
+	layers, err := p.fetchLayers(ctx, fetch)
+	if err != nil {
 		stopProgress()
 		return nil, err
 	}
-	stopProgress()
 
 	unpackProgressDone := oneOffProgress(ctx, "unpacking "+p.src.Reference.String())
-	chainid, err := p.is.unpack(ctx, p.desc)
+	chainid, err := p.is.unpack(ctx, layers, fetch)
+	stopProgress()
 	if err != nil {
 		return nil, unpackProgressDone(err)
 	}
 	unpackProgressDone(nil)
 
-	return p.is.CacheAccessor.Get(ctx, chainid, cache.WithDescription(fmt.Sprintf("pulled from %s", p.ref)))
+	return p.is.CacheAccessor.Get(ctx, chainid, p.description())
 }
 
-func (is *imageSource) unpack(ctx context.Context, desc ocispec.Descriptor) (string, error) {
-	layers, err := getLayers(ctx, is.ContentStore, desc)
-	if err != nil {
-		return "", err
+// withoutLayers filters the layers from the children returned by f
+func withoutLayers(f images.HandlerFunc) images.HandlerFunc {
+	return func(ctx gocontext.Context, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+		children, err := f(ctx, desc)
+		if err != nil {
+			return nil, err
+		}
+		var out []ocispec.Descriptor
+		for _, c := range children {
+			switch c.MediaType {
+			case images.MediaTypeDockerSchema2Manifest, images.MediaTypeDockerSchema2ManifestList, images.MediaTypeDockerSchema2Config,
+				ocispec.MediaTypeImageManifest, ocispec.MediaTypeImageIndex, ocispec.MediaTypeImageConfig:
+				out = append(out, c)
+			}
+		}
+		return out, nil
 	}
+}
 
-	chainID, err := rootfs.ApplyLayers(ctx, layers, is.Snapshotter, is.Applier)
-	if err != nil {
+// unpack downloads the layers with fetch, at most MaxConcurrentDownloads at a
+// time, and applies every layer as soon as it and the layers below it have
+// been downloaded
+func (is *imageSource) unpack(ctx context.Context, layers []rootfs.Layer, fetch images.Handler) (string, error) {
+	eg, ctx := errgroup.WithContext(ctx)
+
+	downloaded := make([]chan struct{}, len(layers))
+	for i := range downloaded {
+		downloaded[i] = make(chan struct{})
+	}
+
+	var chainID digest.Digest
+	eg.Go(func() error {
+		var chain []digest.Digest
+		for i, l := range layers {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-downloaded[i]:
+			}
+			if _, err := rootfs.ApplyLayer(ctx, l, chain, is.Snapshotter, is.Applier); err != nil {
+				return err
+			}
+			chain = append(chain, l.Diff.Digest)
+		}
+		chainID = identity.ChainID(chain)
+		return nil
+	})
+
+	limit := make(chan struct{}, is.maxConcurrentDownloads())
+	eg.Go(func() error {
+		for i, l := range layers {
+			// acquire in order so that the lower layers are downloaded first
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case limit <- struct{}{}:
+			}
+			func(i int, l rootfs.Layer) {
+				eg.Go(func() error {
+					defer func() { <-limit }()
+					if _, err := fetch.Handle(ctx, l.Blob); err != nil {
+						return err
+					}
+					close(downloaded[i])
+					return nil
+				})
+			}(i, l)
+		}
+		return nil
+	})
+
+	if err := eg.Wait(); err != nil {
 		return "", err
 	}
 
@@ -200,6 +373,13 @@ func (is *imageSource) unpack(ctx context.Context, desc ocispec.Descriptor) (str
 	return string(chainID), nil
 }
 
+func (is *imageSource) maxConcurrentDownloads() int {
+	if is.MaxConcurrentDownloads > 0 {
+		return is.MaxConcurrentDownloads
+	}
+	return defaultMaxConcurrentDownloads
+}
+
 func (is *imageSource) fillBlobMapping(ctx context.Context, layers []rootfs.Layer) error {
 	var chain []digest.Digest
 	for _, l := range layers {
@@ -212,13 +392,13 @@ func (is *imageSource) fillBlobMapping(ctx context.Context, layers []rootfs.Laye
 	return nil
 }
 
-func getLayers(ctx context.Context, provider content.Provider, desc ocispec.Descriptor) ([]rootfs.Layer, error) {
-	manifest, err := images.Manifest(ctx, provider, desc, platforms.Format(platforms.Default()))
+func getLayers(ctx context.Context, provider content.Provider, desc ocispec.Descriptor, platform string) ([]rootfs.Layer, error) {
+	manifest, err := images.Manifest(ctx, provider, desc, platform)
 	if err != nil {
-		return nil, errors.WithStack(err)
+		return nil, errors.Wrapf(err, "failed to find the image for %s", platform)
 	}
 	image := images.Image{Target: desc}
-	diffIDs, err := image.RootFS(ctx, provider, platforms.Format(platforms.Default()))
+	diffIDs, err := image.RootFS(ctx, provider, platform)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to resolve rootfs")
 	}
@@ -409,39 +589,54 @@ func oneOffProgress(ctx context.Context, id string) func(err error) error {
 	}
 }
 
-func newCachedResolver(r remotes.Resolver, timeout time.Duration) remotes.Resolver {
-	return &cachedResolver{
-		Resolver: r,
-		cache:    map[string]cachedResult{},
-		timeout:  timeout,
-		locker:   locker.NewLocker(),
+func newResolveCache(timeout time.Duration) *resolveCache {
+	return &resolveCache{
+		cache:   map[string]cachedResult{},
+		timeout: timeout,
+		locker:  locker.NewLocker(),
 	}
 }
 
-type cachedResolver struct {
-	remotes.Resolver
+// resolveCache is shared by the resolvers of all sessions. The results are
+// kept per session, the credentials of one session may not be allowed to
+// resolve the refs resolved by another one.
+type resolveCache struct {
+	mu      sync.Mutex
 	cache   map[string]cachedResult
 	timeout time.Duration
 	locker  *locker.Locker
 }
 
+type cachedResolver struct {
+	remotes.Resolver
+	*resolveCache
+	// session is the ID of the session of the credentials of the Resolver
+	session string
+}
+
 func (cr *cachedResolver) Resolve(ctx gocontext.Context, ref string) (name string, desc ocispec.Descriptor, err error) {
-	cr.locker.Lock(ref)
-	defer cr.locker.Unlock(ref)
-	r, ok := cr.cache[ref]
+	key := cr.session + "/" + ref
+	cr.locker.Lock(key)
+	defer cr.locker.Unlock(key)
+	cr.mu.Lock()
+	r, ok := cr.cache[key]
 	if ok && time.Since(r.ts) < cr.timeout {
+		cr.mu.Unlock()
 		return r.name, r.desc, nil
 	}
-	delete(cr.cache, ref)
+	delete(cr.cache, key)
+	cr.mu.Unlock()
 	n, d, err := cr.Resolver.Resolve(ctx, ref)
 	if err != nil {
 		return "", d, err
 	}
-	cr.cache[ref] = cachedResult{
+	cr.mu.Lock()
+	cr.cache[key] = cachedResult{
 		name: n,
 		desc: d,
 		ts:   time.Now(),
 	}
+	cr.mu.Unlock()
 	return n, d, nil
 }
 
@@ -0,0 +1,37 @@
+package containerimage
+
+import (
+	gocontext "context"
+	"testing"
+	"time"
+
+	"github.com/containerd/containerd/remotes"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+type testResolver struct {
+	remotes.Resolver
+	resolves int
+}
+
+func (r *testResolver) Resolve(ctx gocontext.Context, ref string) (string, ocispec.Descriptor, error) {
+	r.resolves++
+	return ref, ocispec.Descriptor{}, nil
+}
+
+func TestCachedResolverSession(t *testing.T) {
+	rc := newResolveCache(time.Minute)
+	tr := &testResolver{}
+
+	_, _, err := (&cachedResolver{Resolver: tr, resolveCache: rc, session: "foo"}).Resolve(gocontext.TODO(), "busybox")
+	require.NoError(t, err)
+	_, _, err = (&cachedResolver{Resolver: tr, resolveCache: rc, session: "foo"}).Resolve(gocontext.TODO(), "busybox")
+	require.NoError(t, err)
+	require.Equal(t, 1, tr.resolves)
+
+	// another session resolves the ref with its own credentials
+	_, _, err = (&cachedResolver{Resolver: tr, resolveCache: rc, session: "bar"}).Resolve(gocontext.TODO(), "busybox")
+	require.NoError(t, err)
+	require.Equal(t, 2, tr.resolves)
+}
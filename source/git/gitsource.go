@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/BurntSushi/locker"
 	"github.com/boltdb/bolt"
+	"github.com/docker/docker/pkg/symlink"
 	"github.com/moby/buildkit/cache"
 	"github.com/moby/buildkit/cache/metadata"
 	"github.com/moby/buildkit/identity"
@@ -153,6 +155,10 @@ func (gs *gitSource) Resolve(ctx context.Context, id source.Identifier) (source.
 	if !ok {
 		return nil, errors.Errorf("invalid git identifier %v", id)
 	}
+	// the subdir is moved out of the checkout, without the git directory
+	if gitIdentifier.KeepGitDir && gitIdentifier.Subdir != "" {
+		return nil, errors.Errorf("keeping the git directory of %s is not supported with a subdirectory", gitIdentifier.Remote)
+	}
 
 	return &gitSourceHandler{
 		src:       *gitIdentifier,
@@ -161,6 +167,15 @@ func (gs *gitSource) Resolve(ctx context.Context, id source.Identifier) (source.
 }
 
 func (gs *gitSourceHandler) CacheKey(ctx context.Context) (string, error) {
+	sha, err := gs.resolveCommit(ctx)
+	if err != nil {
+		return "", err
+	}
+	return gs.subdirKey(sha), nil
+}
+
+// resolveCommit returns the commit SHA of the ref of the source
+func (gs *gitSourceHandler) resolveCommit(ctx context.Context) (string, error) {
 	remote := gs.src.Remote
 	ref := gs.src.Ref
 	if ref == "" {
@@ -186,17 +201,46 @@ func (gs *gitSourceHandler) CacheKey(ctx context.Context) (string, error) {
 	if err != nil {
 		return "", errors.Wrapf(err, "failed to fetch remote %s", remote)
 	}
-	out := buf.String()
-	idx := strings.Index(out, "\t")
-	if idx == -1 {
-		return "", errors.Errorf("failed to find commit SHA from output: %s", string(out))
+	sha, err := parseLsRemote(buf.String(), ref)
+	if err != nil {
+		return "", err
+	}
+	gs.cacheKey = sha
+	return sha, nil
+}
+
+// subdirKey returns the cache key for the checkout of a commit. Different
+// subdirectories of the same commit have different contents.
+func (gs *gitSourceHandler) subdirKey(sha string) string {
+	if gs.src.Subdir == "" {
+		return sha
 	}
+	return sha + ":" + gs.src.Subdir
+}
 
-	sha := string(out[:idx])
+// parseLsRemote returns the commit SHA of ref from the output of git
+// ls-remote. Annotated tags are resolved to the commit they point to.
+func parseLsRemote(out, ref string) (string, error) {
+	var sha string
+	for _, line := range strings.Split(out, "\n") {
+		parts := strings.SplitN(line, "\t", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		if strings.HasSuffix(parts[1], "^{}") {
+			sha = parts[0]
+			break
+		}
+		if sha == "" {
+			sha = parts[0]
+		}
+	}
+	if sha == "" {
+		return "", errors.Errorf("failed to find commit SHA for %s from output: %s", ref, out)
+	}
 	if !isCommitSHA(sha) {
 		return "", errors.Errorf("invalid commit sha %q", sha)
 	}
-	gs.cacheKey = sha
 	return sha, nil
 }
 
@@ -206,16 +250,16 @@ func (gs *gitSourceHandler) Snapshot(ctx context.Context) (out cache.ImmutableRe
 		ref = "master"
 	}
 
-	cacheKey := gs.cacheKey
-	if cacheKey == "" {
+	sha := gs.cacheKey
+	if sha == "" {
 		var err error
-		cacheKey, err = gs.CacheKey(ctx)
+		sha, err = gs.resolveCommit(ctx)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	snapshotKey := "git-snapshot::" + cacheKey + ":" + gs.src.Subdir
+	snapshotKey := "git-snapshot::" + sha + ":" + gs.src.Subdir
 	gs.locker.Lock(snapshotKey)
 	defer gs.locker.Unlock(snapshotKey)
 
@@ -289,12 +333,22 @@ func (gs *gitSourceHandler) Snapshot(ctx context.Context) (out cache.ImmutableRe
 		}
 	}()
 
+	// with a subdir the repository is checked out to a temporary directory
+	// in the same snapshot and the subdir is moved in place afterwards
+	workDir := checkoutDir
+	if gs.src.Subdir != "" {
+		workDir = filepath.Join(checkoutDir, ".buildkit-checkout-"+identity.NewID())
+		if err := os.Mkdir(workDir, 0700); err != nil {
+			return nil, errors.Wrapf(err, "failed to create checkout dir for %s", gs.src.Remote)
+		}
+	}
+
 	if gs.src.KeepGitDir {
-		_, err = gitWithinDir(ctx, checkoutDir, "", "init")
+		_, err = gitWithinDir(ctx, workDir, "", "init")
 		if err != nil {
 			return nil, err
 		}
-		_, err = gitWithinDir(ctx, checkoutDir, "", "remote", "add", "origin", gitDir)
+		_, err = gitWithinDir(ctx, workDir, "", "remote", "add", "origin", gitDir)
 		if err != nil {
 			return nil, err
 		}
@@ -306,20 +360,25 @@ func (gs *gitSourceHandler) Snapshot(ctx context.Context) (out cache.ImmutableRe
 				return nil, err
 			}
 		}
-		_, err = gitWithinDir(ctx, checkoutDir, "", "fetch", "--recurse-submodules=yes", "--depth=1", "origin", pullref)
+		_, err = gitWithinDir(ctx, workDir, "", "fetch", "--recurse-submodules=yes", "--depth=1", "origin", pullref)
 		if err != nil {
 			return nil, err
 		}
-		_, err = gitWithinDir(ctx, checkoutDir, checkoutDir, "checkout", "FETCH_HEAD")
+		_, err = gitWithinDir(ctx, workDir, workDir, "checkout", "FETCH_HEAD")
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to checkout remote %s", gs.src.Remote)
 		}
 	} else {
-		_, err = gitWithinDir(ctx, gitDir, checkoutDir, "checkout", ref, "--", ".")
+		_, err = gitWithinDir(ctx, gitDir, workDir, "checkout", ref, "--", ".")
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to checkout remote %s", gs.src.Remote)
 		}
 	}
+	if workDir != checkoutDir {
+		if err := moveSubdir(workDir, gs.src.Subdir, checkoutDir); err != nil {
+			return nil, errors.Wrapf(err, "failed to checkout subdir %s of %s", gs.src.Subdir, gs.src.Remote)
+		}
+	}
 	lm.Unmount()
 	lm = nil
 
@@ -350,6 +409,32 @@ func (gs *gitSourceHandler) Snapshot(ctx context.Context) (out cache.ImmutableRe
 	return snap, nil
 }
 
+// moveSubdir moves the contents of subdir in workDir to dest and removes
+// workDir
+func moveSubdir(workDir, subdir, dest string) error {
+	src, err := symlink.FollowSymlinkInScope(filepath.Join(workDir, subdir), workDir)
+	if err != nil {
+		return err
+	}
+	fi, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if !fi.IsDir() {
+		return errors.Errorf("%s is not a directory", subdir)
+	}
+	files, err := ioutil.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := os.Rename(filepath.Join(src, f.Name()), filepath.Join(dest, f.Name())); err != nil {
+			return err
+		}
+	}
+	return os.RemoveAll(workDir)
+}
+
 func isCommitSHA(str string) bool {
 	return validHex.MatchString(str)
 }
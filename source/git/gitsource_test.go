@@ -317,3 +317,89 @@ func runShell(t *testing.T, dir string, cmds ...string) {
 		require.NoError(t, err, "command %v returned %s", args, dt)
 	}
 }
+
+func TestFetchSubdir(t *testing.T) {
+	ctx := namespaces.WithNamespace(context.Background(), "buildkit-test")
+
+	tmpdir, err := ioutil.TempDir("", "buildkit-state")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	gs := setupGitSource(t, tmpdir)
+
+	repodir, err := ioutil.TempDir("", "buildkit-gitsource")
+	require.NoError(t, err)
+	defer os.RemoveAll(repodir)
+
+	runShell(t, repodir,
+		"git init",
+		"git config --local user.email test",
+		"git config --local user.name test",
+		"echo foo > abc",
+		"mkdir sub",
+		"echo bar > sub/def",
+		"git add abc sub",
+		"git commit -m initial",
+	)
+
+	g, err := gs.Resolve(ctx, &source.GitIdentifier{Remote: repodir})
+	require.NoError(t, err)
+
+	key1, err := g.CacheKey(ctx)
+	require.NoError(t, err)
+
+	g, err = gs.Resolve(ctx, &source.GitIdentifier{Remote: repodir, Subdir: "sub"})
+	require.NoError(t, err)
+
+	key2, err := g.CacheKey(ctx)
+	require.NoError(t, err)
+	require.Equal(t, key1+":sub", key2)
+
+	ref, err := g.Snapshot(ctx)
+	require.NoError(t, err)
+	defer ref.Release(context.TODO())
+
+	mount, err := ref.Mount(ctx, false)
+	require.NoError(t, err)
+
+	lm := snapshot.LocalMounter(mount)
+	dir, err := lm.Mount()
+	require.NoError(t, err)
+	defer lm.Unmount()
+
+	dt, err := ioutil.ReadFile(filepath.Join(dir, "def"))
+	require.NoError(t, err)
+	require.Equal(t, "bar\n", string(dt))
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(files))
+
+	// the snapshot without a cache key resolves to the same checkout
+	g, err = gs.Resolve(ctx, &source.GitIdentifier{Remote: repodir, Subdir: "sub"})
+	require.NoError(t, err)
+
+	ref2, err := g.Snapshot(ctx)
+	require.NoError(t, err)
+	defer ref2.Release(context.TODO())
+	require.Equal(t, ref.ID(), ref2.ID())
+
+	_, err = gs.Resolve(ctx, &source.GitIdentifier{Remote: repodir, Subdir: "sub", KeepGitDir: true})
+	require.Error(t, err)
+}
+
+func TestParseLsRemote(t *testing.T) {
+	commit := strings.Repeat("a", 40)
+	tag := strings.Repeat("b", 40)
+
+	sha, err := parseLsRemote(commit+"\trefs/heads/master\n", "master")
+	require.NoError(t, err)
+	require.Equal(t, commit, sha)
+
+	sha, err = parseLsRemote(tag+"\trefs/tags/v1\n"+commit+"\trefs/tags/v1^{}\n", "v1")
+	require.NoError(t, err)
+	require.Equal(t, commit, sha)
+
+	_, err = parseLsRemote("", "master")
+	require.Error(t, err)
+}
@@ -5,7 +5,6 @@ import (
 	"strings"
 
 	"github.com/docker/docker/pkg/urlutil"
-	"github.com/pkg/errors"
 )
 
 type GitIdentifier struct {
@@ -42,9 +41,6 @@ func NewGitIdentifier(remoteURL string) (*GitIdentifier, error) {
 		u.Fragment = ""
 		repo.Remote = u.String()
 	}
-	if repo.Subdir != "" {
-		return nil, errors.Errorf("subdir not supported yet")
-	}
 	return &repo, nil
 }
 
@@ -0,0 +1,427 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/locker"
+	"github.com/boltdb/bolt"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/cache/metadata"
+	"github.com/moby/buildkit/snapshot"
+	"github.com/moby/buildkit/source"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+	"golang.org/x/net/context/ctxhttp"
+)
+
+const (
+	keyHTTPIndex        = "http.index"
+	keyHTTPChecksum     = "http.checksum"
+	keyHTTPETag         = "http.etag"
+	keyHTTPLastModified = "http.lastmodified"
+	keyHTTPCheckedAt    = "http.checkedat"
+)
+
+type Opt struct {
+	CacheAccessor cache.Accessor
+	MetadataStore *metadata.Store
+	// Transport is used for the requests. Defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+	// CacheTTL is the time a download is reused without revalidating the URL
+	CacheTTL time.Duration
+	// Scheme is the scheme of the URLs of the source, source.HttpsScheme or
+	// source.HttpScheme. Defaults to source.HttpsScheme.
+	Scheme string
+}
+
+type httpSource struct {
+	scheme string
+	md     *metadata.Store
+	cache  cache.Accessor
+	client *http.Client
+	ttl    time.Duration
+	locker *locker.Locker
+}
+
+func NewSource(opt Opt) (source.Source, error) {
+	transport := opt.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	scheme := opt.Scheme
+	if scheme == "" {
+		scheme = source.HttpsScheme
+	}
+	if scheme != source.HttpsScheme && scheme != source.HttpScheme {
+		return nil, errors.Errorf("invalid http source scheme %s", scheme)
+	}
+	hs := &httpSource{
+		scheme: scheme,
+		md:     opt.MetadataStore,
+		cache:  opt.CacheAccessor,
+		client: &http.Client{Transport: transport},
+		ttl:    opt.CacheTTL,
+		locker: locker.NewLocker(),
+	}
+	return hs, nil
+}
+
+func (hs *httpSource) ID() string {
+	return hs.scheme
+}
+
+func (hs *httpSource) Resolve(ctx context.Context, id source.Identifier) (source.SourceInstance, error) {
+	httpIdentifier, ok := id.(*source.HttpIdentifier)
+	if !ok {
+		return nil, errors.Errorf("invalid http identifier %v", id)
+	}
+
+	return &httpSourceHandler{
+		src:        *httpIdentifier,
+		httpSource: hs,
+	}, nil
+}
+
+type httpSourceHandler struct {
+	*httpSource
+	src      source.HttpIdentifier
+	refID    string // download found or made by CacheKey
+	checksum digest.Digest
+}
+
+// indexKey identifies the downloads of the URL with the same file options
+func (hs *httpSourceHandler) indexKey() string {
+	dt, _ := json.Marshal(struct {
+		URL      string
+		Filename string
+		Perm     os.FileMode
+		UID, GID int
+	}{hs.src.URL, hs.filename(), hs.src.Perm, hs.src.UID, hs.src.GID})
+	return "http:" + digest.FromBytes(dt).String()
+}
+
+// cacheKey returns the key of the file with content dgst
+func (hs *httpSourceHandler) cacheKey(dgst digest.Digest) (string, error) {
+	dt, err := json.Marshal(struct {
+		Checksum digest.Digest
+		Filename string
+		Perm     os.FileMode
+		UID, GID int
+	}{dgst, hs.filename(), hs.src.Perm, hs.src.UID, hs.src.GID})
+	if err != nil {
+		return "", err
+	}
+	return digest.FromBytes(dt).String(), nil
+}
+
+func (hs *httpSourceHandler) filename() string {
+	if hs.src.Filename != "" {
+		return hs.src.Filename
+	}
+	if u, err := url.Parse(hs.src.URL); err == nil {
+		if base := path.Base(u.Path); base != "." && base != "/" {
+			return base
+		}
+	}
+	return "index"
+}
+
+func (hs *httpSourceHandler) CacheKey(ctx context.Context) (string, error) {
+	if hs.src.Checksum != "" {
+		hs.checksum = hs.src.Checksum
+		return hs.cacheKey(hs.src.Checksum)
+	}
+
+	index := hs.indexKey()
+	hs.locker.Lock(index)
+	defer hs.locker.Unlock(index)
+
+	req, err := http.NewRequest("GET", hs.src.URL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	prev, err := hs.previousDownload(ctx, index)
+	if err != nil {
+		return "", err
+	}
+	if prev != nil {
+		var checkedAt time.Time
+		if v := prev.Get(keyHTTPCheckedAt); v != nil {
+			if err := v.Unmarshal(&checkedAt); err != nil {
+				return "", err
+			}
+		}
+		dgst, err := getChecksum(prev)
+		if err != nil {
+			return "", err
+		}
+		if hs.ttl > 0 && time.Since(checkedAt) < hs.ttl {
+			hs.refID = prev.ID()
+			hs.checksum = dgst
+			return hs.cacheKey(dgst)
+		}
+		if v := prev.Get(keyHTTPETag); v != nil {
+			var etag string
+			if err := v.Unmarshal(&etag); err == nil && etag != "" {
+				req.Header.Set("If-None-Match", etag)
+			}
+		}
+		if v := prev.Get(keyHTTPLastModified); v != nil {
+			var lastModified string
+			if err := v.Unmarshal(&lastModified); err == nil && lastModified != "" {
+				req.Header.Set("If-Modified-Since", lastModified)
+			}
+		}
+
+		resp, err := ctxhttp.Do(ctx, hs.client, req)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to request %s", hs.src.URL)
+		}
+		if resp.StatusCode == http.StatusNotModified || (resp.StatusCode == http.StatusOK && sameETag(prev, resp)) {
+			resp.Body.Close()
+			if err := setValue(prev, keyHTTPCheckedAt, "", time.Now()); err != nil {
+				return "", err
+			}
+			hs.refID = prev.ID()
+			hs.checksum = dgst
+			return hs.cacheKey(dgst)
+		}
+		return hs.downloadResponse(ctx, index, resp)
+	}
+
+	resp, err := ctxhttp.Do(ctx, hs.client, req)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to request %s", hs.src.URL)
+	}
+	return hs.downloadResponse(ctx, index, resp)
+}
+
+func (hs *httpSourceHandler) downloadResponse(ctx context.Context, index string, resp *http.Response) (string, error) {
+	ref, dgst, err := hs.save(ctx, index, resp)
+	if err != nil {
+		return "", err
+	}
+	// the download is kept in the cache and loaded again by Snapshot
+	hs.refID = ref.ID()
+	hs.checksum = dgst
+	if err := ref.Release(context.TODO()); err != nil {
+		return "", err
+	}
+	return hs.cacheKey(dgst)
+}
+
+// previousDownload returns the metadata of the latest download of the URL
+// that is still in the cache
+func (hs *httpSourceHandler) previousDownload(ctx context.Context, index string) (*metadata.StorageItem, error) {
+	sis, err := hs.md.Search(index)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to search metadata for %s", index)
+	}
+	var latest *metadata.StorageItem
+	var latestAt time.Time
+	for _, si := range sis {
+		var checkedAt time.Time
+		if v := si.Get(keyHTTPCheckedAt); v != nil {
+			if err := v.Unmarshal(&checkedAt); err != nil {
+				continue
+			}
+		}
+		if latest == nil || checkedAt.After(latestAt) {
+			latest = si
+			latestAt = checkedAt
+		}
+	}
+	return latest, nil
+}
+
+func (hs *httpSourceHandler) Snapshot(ctx context.Context) (cache.ImmutableRef, error) {
+	if hs.checksum == "" {
+		if _, err := hs.CacheKey(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if hs.refID != "" {
+		if ref, err := hs.cache.Get(ctx, hs.refID); err == nil {
+			return ref, nil
+		}
+	}
+
+	index := hs.indexKey()
+	hs.locker.Lock(index)
+	defer hs.locker.Unlock(index)
+
+	// the expected checksum may match an earlier download
+	if hs.src.Checksum != "" {
+		sis, err := hs.md.Search(index)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to search metadata for %s", index)
+		}
+		for _, si := range sis {
+			if dgst, err := getChecksum(si); err == nil && dgst == hs.src.Checksum {
+				if ref, err := hs.cache.Get(ctx, si.ID()); err == nil {
+					return ref, nil
+				}
+			}
+		}
+	}
+
+	resp, err := ctxhttp.Get(ctx, hs.client, hs.src.URL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to request %s", hs.src.URL)
+	}
+	ref, _, err := hs.save(ctx, index, resp)
+	if err != nil {
+		return nil, err
+	}
+	return ref, nil
+}
+
+// save writes the body of resp to a new snapshot and closes it
+func (hs *httpSourceHandler) save(ctx context.Context, index string, resp *http.Response) (ref cache.ImmutableRef, dgst digest.Digest, retErr error) {
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, "", errors.Errorf("invalid response status %d for %s", resp.StatusCode, hs.src.URL)
+	}
+
+	newRef, err := hs.cache.New(ctx, nil, cache.CachePolicyRetain, cache.WithDescription(fmt.Sprintf("http url %s", hs.src.URL)))
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() {
+		if retErr != nil && newRef != nil {
+			newRef.Release(context.TODO())
+		}
+	}()
+
+	mount, err := newRef.Mount(ctx, false)
+	if err != nil {
+		return nil, "", err
+	}
+	lm := snapshot.LocalMounter(mount)
+	dir, err := lm.Mount()
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() {
+		if lm != nil {
+			lm.Unmount()
+		}
+	}()
+
+	perm := os.FileMode(0600)
+	if hs.src.Perm != 0 {
+		perm = hs.src.Perm
+	}
+	fp := filepath.Join(dir, filepath.Clean("/"+hs.filename()))
+	f, err := os.OpenFile(fp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return nil, "", err
+	}
+	digester := digest.Canonical.Digester()
+	if _, err := io.Copy(io.MultiWriter(f, digester.Hash()), resp.Body); err != nil {
+		f.Close()
+		return nil, "", errors.Wrapf(err, "failed to download %s", hs.src.URL)
+	}
+	if err := f.Close(); err != nil {
+		return nil, "", err
+	}
+	dgst = digester.Digest()
+	if hs.src.Checksum != "" && dgst != hs.src.Checksum {
+		return nil, "", errors.Errorf("digest mismatch for %s: %s (expected: %s)", hs.src.URL, dgst, hs.src.Checksum)
+	}
+	// the umask may have limited the permissions on create
+	if err := os.Chmod(fp, perm); err != nil {
+		return nil, "", err
+	}
+	if hs.src.UID != 0 || hs.src.GID != 0 {
+		if err := os.Lchown(fp, hs.src.UID, hs.src.GID); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := lm.Unmount(); err != nil {
+		return nil, "", err
+	}
+	lm = nil
+
+	ref, err = newRef.Commit(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	newRef = nil
+	defer func() {
+		if retErr != nil {
+			ref.Release(context.TODO())
+		}
+	}()
+
+	si, _ := hs.md.Get(ref.ID())
+	if err := setValue(si, keyHTTPIndex, index, index); err != nil {
+		return nil, "", err
+	}
+	if err := setValue(si, keyHTTPChecksum, "", dgst); err != nil {
+		return nil, "", err
+	}
+	if err := setValue(si, keyHTTPETag, "", resp.Header.Get("ETag")); err != nil {
+		return nil, "", err
+	}
+	if err := setValue(si, keyHTTPLastModified, "", resp.Header.Get("Last-Modified")); err != nil {
+		return nil, "", err
+	}
+	if err := setValue(si, keyHTTPCheckedAt, "", time.Now()); err != nil {
+		return nil, "", err
+	}
+	return ref, dgst, nil
+}
+
+func getChecksum(si *metadata.StorageItem) (digest.Digest, error) {
+	v := si.Get(keyHTTPChecksum)
+	if v == nil {
+		return "", errors.Errorf("no checksum for %s", si.ID())
+	}
+	var dgst digest.Digest
+	if err := v.Unmarshal(&dgst); err != nil {
+		return "", err
+	}
+	return dgst, nil
+}
+
+// sameETag returns true if the server ignored the conditional request but
+// returned the ETag of the previous download
+func sameETag(si *metadata.StorageItem, resp *http.Response) bool {
+	etag := resp.Header.Get("ETag")
+	if etag == "" || strings.HasPrefix(etag, "W/") {
+		return false
+	}
+	v := si.Get(keyHTTPETag)
+	if v == nil {
+		return false
+	}
+	var prev string
+	if err := v.Unmarshal(&prev); err != nil {
+		return false
+	}
+	return prev == etag
+}
+
+func setValue(si *metadata.StorageItem, key, index string, value interface{}) error {
+	v, err := metadata.NewValue(value)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create value for %s", key)
+	}
+	v.Index = index
+	return si.Update(func(b *bolt.Bucket) error {
+		return si.SetValue(b, key, v)
+	})
+}
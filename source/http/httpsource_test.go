@@ -0,0 +1,230 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/containerd/containerd/namespaces"
+	"github.com/containerd/containerd/snapshot/naive"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/cache/metadata"
+	"github.com/moby/buildkit/snapshot"
+	"github.com/moby/buildkit/source"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPSource(t *testing.T) {
+	ctx := namespaces.WithNamespace(context.Background(), "buildkit-test")
+
+	tmpdir, err := ioutil.TempDir("", "buildkit-state")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	hs := setupHTTPSource(t, tmpdir)
+
+	srv := &testServer{content: "content1", etag: `"v1"`}
+	server := httptest.NewServer(srv)
+	defer server.Close()
+
+	id := &source.HttpIdentifier{URL: server.URL + "/foo", Perm: 0644}
+
+	h, err := hs.Resolve(ctx, id)
+	require.NoError(t, err)
+
+	key1, err := h.CacheKey(ctx)
+	require.NoError(t, err)
+
+	ref, err := h.Snapshot(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "content1", readFile(t, ctx, ref, "foo", 0644))
+	require.NoError(t, ref.Release(context.TODO()))
+	require.Equal(t, 1, srv.downloads())
+
+	// unchanged ETag reuses the download
+	h, err = hs.Resolve(ctx, id)
+	require.NoError(t, err)
+
+	key2, err := h.CacheKey(ctx)
+	require.NoError(t, err)
+	require.Equal(t, key1, key2)
+
+	ref2, err := h.Snapshot(ctx)
+	require.NoError(t, err)
+	require.Equal(t, ref.ID(), ref2.ID())
+	require.NoError(t, ref2.Release(context.TODO()))
+	require.Equal(t, 1, srv.downloads())
+
+	srv.set("content2", `"v2"`)
+
+	h, err = hs.Resolve(ctx, id)
+	require.NoError(t, err)
+
+	key3, err := h.CacheKey(ctx)
+	require.NoError(t, err)
+	require.NotEqual(t, key1, key3)
+
+	ref3, err := h.Snapshot(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "content2", readFile(t, ctx, ref3, "foo", 0644))
+	require.NoError(t, ref3.Release(context.TODO()))
+	require.Equal(t, 2, srv.downloads())
+}
+
+func TestHTTPChecksum(t *testing.T) {
+	ctx := namespaces.WithNamespace(context.Background(), "buildkit-test")
+
+	tmpdir, err := ioutil.TempDir("", "buildkit-state")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	hs := setupHTTPSource(t, tmpdir)
+
+	srv := &testServer{content: "content1"}
+	server := httptest.NewServer(srv)
+	defer server.Close()
+
+	id := &source.HttpIdentifier{URL: server.URL + "/foo", Filename: "bar", Checksum: digest.FromBytes([]byte("content1"))}
+
+	h, err := hs.Resolve(ctx, id)
+	require.NoError(t, err)
+
+	_, err = h.CacheKey(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 0, srv.downloads())
+
+	ref, err := h.Snapshot(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "content1", readFile(t, ctx, ref, "bar", 0600))
+	require.NoError(t, ref.Release(context.TODO()))
+
+	id.Checksum = digest.FromBytes([]byte("other"))
+
+	h, err = hs.Resolve(ctx, id)
+	require.NoError(t, err)
+
+	_, err = h.Snapshot(ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "digest mismatch")
+}
+
+func TestHTTPStatus(t *testing.T) {
+	ctx := namespaces.WithNamespace(context.Background(), "buildkit-test")
+
+	tmpdir, err := ioutil.TempDir("", "buildkit-state")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	hs := setupHTTPSource(t, tmpdir)
+
+	srv := &testServer{content: "content1"}
+	server := httptest.NewServer(srv)
+	defer server.Close()
+
+	for _, status := range []int{http.StatusMultipleChoices, http.StatusNotFound} {
+		srv.status = status
+		h, err := hs.Resolve(ctx, &source.HttpIdentifier{URL: server.URL + "/foo"})
+		require.NoError(t, err)
+		_, err = h.CacheKey(ctx)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid response status")
+	}
+}
+
+func TestHTTPIdentifier(t *testing.T) {
+	id, err := source.FromString("https://example.com/foo")
+	require.NoError(t, err)
+	require.Equal(t, source.HttpsScheme, id.ID())
+
+	id, err = source.FromString("http://example.com/foo")
+	require.NoError(t, err)
+	require.Equal(t, source.HttpScheme, id.ID())
+	require.Equal(t, "http://example.com/foo", id.(*source.HttpIdentifier).URL)
+
+	_, err = NewSource(Opt{Scheme: "ftp"})
+	require.Error(t, err)
+}
+
+type testServer struct {
+	mu      sync.Mutex
+	content string
+	etag    string
+	status  int
+	count   int
+}
+
+func (s *testServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.etag != "" {
+		if r.Header.Get("If-None-Match") == s.etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", s.etag)
+	}
+	s.count++
+	if s.status != 0 {
+		w.WriteHeader(s.status)
+	}
+	w.Write([]byte(s.content))
+}
+
+func (s *testServer) set(content, etag string) {
+	s.mu.Lock()
+	s.content = content
+	s.etag = etag
+	s.mu.Unlock()
+}
+
+func (s *testServer) downloads() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.count
+}
+
+func readFile(t *testing.T, ctx context.Context, ref cache.ImmutableRef, name string, perm os.FileMode) string {
+	mount, err := ref.Mount(ctx, true)
+	require.NoError(t, err)
+
+	lm := snapshot.LocalMounter(mount)
+	dir, err := lm.Mount()
+	require.NoError(t, err)
+	defer lm.Unmount()
+
+	fi, err := os.Stat(filepath.Join(dir, name))
+	require.NoError(t, err)
+	require.Equal(t, perm, fi.Mode().Perm())
+
+	dt, err := ioutil.ReadFile(filepath.Join(dir, name))
+	require.NoError(t, err)
+	return string(dt)
+}
+
+func setupHTTPSource(t *testing.T, tmpdir string) source.Source {
+	snapshotter, err := naive.NewSnapshotter(filepath.Join(tmpdir, "snapshots"))
+	require.NoError(t, err)
+
+	md, err := metadata.NewStore(filepath.Join(tmpdir, "metadata.db"))
+	require.NoError(t, err)
+
+	cm, err := cache.NewManager(cache.ManagerOpt{
+		Snapshotter:   snapshotter,
+		MetadataStore: md,
+	})
+	require.NoError(t, err)
+
+	hs, err := NewSource(Opt{
+		CacheAccessor: cm,
+		MetadataStore: md,
+	})
+	require.NoError(t, err)
+
+	return hs
+}
@@ -1,9 +1,12 @@
 package source
 
 import (
+	"os"
 	"strings"
 
 	"github.com/containerd/containerd/reference"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 )
 
@@ -16,6 +19,9 @@ const (
 	DockerImageScheme = "docker-image"
 	GitScheme         = "git"
 	LocalScheme       = "local"
+	HttpsScheme       = "https"
+	HttpScheme        = "http"
+	OCILayoutScheme   = "oci-layout"
 )
 
 type Identifier interface {
@@ -36,6 +42,12 @@ func FromString(s string) (Identifier, error) {
 		return NewGitIdentifier(parts[1])
 	case LocalScheme:
 		return NewLocalIdentifier(parts[1])
+	case HttpsScheme:
+		return NewHttpIdentifier(parts[1], true)
+	case HttpScheme:
+		return NewHttpIdentifier(parts[1], false)
+	case OCILayoutScheme:
+		return NewOCILayoutIdentifier(parts[1])
 	default:
 		return nil, errors.Wrapf(errNotFound, "unknown schema %s", parts[0])
 	}
@@ -43,6 +55,9 @@ func FromString(s string) (Identifier, error) {
 
 type ImageIdentifier struct {
 	Reference reference.Spec
+	// Platform selects the image from a manifest list. The platform of the
+	// daemon is used if it is nil.
+	Platform *ocispec.Platform
 }
 
 func NewImageIdentifier(str string) (*ImageIdentifier, error) {
@@ -65,6 +80,9 @@ type LocalIdentifier struct {
 	Name            string
 	SessionID       string
 	IncludePatterns []string
+	ExcludePatterns []string
+	FollowPaths     []string
+	ExcludeXattrs   bool
 }
 
 func NewLocalIdentifier(str string) (*LocalIdentifier, error) {
@@ -74,3 +92,66 @@ func NewLocalIdentifier(str string) (*LocalIdentifier, error) {
 func (_ *LocalIdentifier) ID() string {
 	return LocalScheme
 }
+
+type HttpIdentifier struct {
+	TLS bool
+	URL string
+	// Checksum is the expected digest of the downloaded file. The source is
+	// cached by the checksum instead of revalidating the URL if it is set.
+	Checksum digest.Digest
+	Filename string
+	Perm     os.FileMode
+	UID      int
+	GID      int
+}
+
+func NewHttpIdentifier(str string, tls bool) (*HttpIdentifier, error) {
+	proto := "https://"
+	if !tls {
+		proto = "http://"
+	}
+	return &HttpIdentifier{TLS: tls, URL: proto + str}, nil
+}
+
+func (id *HttpIdentifier) ID() string {
+	if !id.TLS {
+		return HttpScheme
+	}
+	return HttpsScheme
+}
+
+// OCILayoutIdentifier is an image in an OCI image layout directory
+type OCILayoutIdentifier struct {
+	// Path is the directory of the layout relative to the OCI layout root of
+	// the daemon, or the name of the local directory of the client if Local
+	// is set
+	Path string
+	// Tag selects the manifest by its org.opencontainers.image.ref.name
+	// annotation. The only manifest of the layout is used if both Tag and
+	// Digest are empty.
+	Tag       string
+	Digest    digest.Digest
+	Local     bool
+	SessionID string
+}
+
+func NewOCILayoutIdentifier(str string) (*OCILayoutIdentifier, error) {
+	id := &OCILayoutIdentifier{Path: str}
+	if i := strings.LastIndex(str, "@"); i != -1 {
+		dgst, err := digest.Parse(str[i+1:])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid oci layout reference %s", str)
+		}
+		id.Path, id.Digest = str[:i], dgst
+	} else if i := strings.LastIndex(str, ":"); i > strings.LastIndex(str, "/") {
+		id.Path, id.Tag = str[:i], str[i+1:]
+	}
+	if id.Path == "" {
+		return nil, errors.Wrapf(errInvalid, "no path in oci layout reference %s", str)
+	}
+	return id, nil
+}
+
+func (_ *OCILayoutIdentifier) ID() string {
+	return OCILayoutScheme
+}
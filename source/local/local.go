@@ -19,7 +19,6 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/tonistiigi/fsutil"
 	"golang.org/x/net/context"
-	"golang.org/x/time/rate"
 )
 
 const keySharedKey = "local.sharedKey"
@@ -79,7 +78,10 @@ func (ls *localSourceHandler) CacheKey(ctx context.Context) (string, error) {
 	dt, err := json.Marshal(struct {
 		SessionID       string
 		IncludePatterns []string
-	}{SessionID: sessionID, IncludePatterns: ls.src.IncludePatterns})
+		ExcludePatterns []string
+		FollowPaths     []string
+		ExcludeXattrs   bool `json:",omitempty"`
+	}{SessionID: sessionID, IncludePatterns: ls.src.IncludePatterns, ExcludePatterns: ls.src.ExcludePatterns, FollowPaths: ls.src.FollowPaths, ExcludeXattrs: ls.src.ExcludeXattrs})
 	if err != nil {
 		return "", err
 	}
@@ -127,7 +129,7 @@ func (ls *localSourceHandler) Snapshot(ctx context.Context) (out cache.Immutable
 
 	defer func() {
 		if retErr != nil && mutable != nil {
-			go mutable.Release(context.TODO())
+			cache.ReleaseDetached(mutable)
 		}
 	}()
 
@@ -154,12 +156,27 @@ func (ls *localSourceHandler) Snapshot(ctx context.Context) (out cache.Immutable
 		return nil, err
 	}
 
+	// files that were left out by an older client must not change the
+	// content based cache keys
+	checksumOpts := contenthash.ChecksumOpts{
+		IncludePatterns: ls.src.IncludePatterns,
+		ExcludePatterns: ls.src.ExcludePatterns,
+		FollowPaths:     ls.src.FollowPaths,
+		ExcludeXattrs:   ls.src.ExcludeXattrs,
+	}
+
+	includes := ls.src.IncludePatterns
+	if includes != nil {
+		includes = append(append([]string{}, includes...), ls.src.FollowPaths...)
+	}
+
 	opt := filesync.FSSendRequestOpt{
 		Name:             ls.src.Name,
-		IncludePatterns:  ls.src.IncludePatterns,
+		IncludePatterns:  includes,
+		ExcludePatterns:  ls.src.ExcludePatterns,
 		OverrideExcludes: false,
 		DestDir:          dest,
-		CacheUpdater:     &cacheUpdater{cc},
+		CacheUpdater:     &cacheUpdater{CacheContext: cc, opts: checksumOpts},
 		ProgressCb:       newProgressHandler(ctx, "transferring "+ls.src.Name+":"),
 	}
 
@@ -176,6 +193,10 @@ func (ls *localSourceHandler) Snapshot(ctx context.Context) (out cache.Immutable
 		return nil, err
 	}
 
+	if err := contenthash.SetChecksumOpts(mutable.Metadata(), checksumOpts); err != nil {
+		return nil, err
+	}
+
 	// skip storing snapshot by the shared key if it already exists
 	skipStoreSharedKey := false
 	si, _ := ls.md.Get(mutable.ID())
@@ -211,36 +232,23 @@ func (ls *localSourceHandler) Snapshot(ctx context.Context) (out cache.Immutable
 }
 
 func newProgressHandler(ctx context.Context, id string) func(int, bool) {
-	limiter := rate.NewLimiter(rate.Every(100*time.Millisecond), 1)
-	pw, _, _ := progress.FromContext(ctx)
-	now := time.Now()
-	st := progress.Status{
-		Started: &now,
-		Action:  "transferring",
-	}
-	pw.Write(id, st)
+	t := progress.NewTransfer(ctx, id, "transferring", 0)
 	return func(s int, last bool) {
-		if last || limiter.Allow() {
-			st.Current = s
-			if last {
-				now := time.Now()
-				st.Completed = &now
-			}
-			pw.Write(id, st)
-			if last {
-				pw.Close()
-			}
+		t.Update(int64(s))
+		if last {
+			t.Done(nil)
 		}
 	}
 }
 
 type cacheUpdater struct {
 	contenthash.CacheContext
+	opts contenthash.ChecksumOpts
 }
 
 func (cu *cacheUpdater) MarkSupported(bool) {
 }
 
 func (cu *cacheUpdater) ContentHasher() fsutil.ContentHasher {
-	return contenthash.NewFromStat
+	return contenthash.ContentHasher(cu.opts)
 }
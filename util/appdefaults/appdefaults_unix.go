@@ -5,4 +5,6 @@ package appdefaults
 const (
 	Socket = "/run/buildkit/buildd.sock"
 	Root   = ".buildstate"
+
+	ContainerdAddress = "/run/containerd/containerd.sock"
 )
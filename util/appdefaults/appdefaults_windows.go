@@ -3,4 +3,6 @@ package appdefaults
 const (
 	Socket = "//./pipe/buildd"
 	Root   = ".buildstate"
+
+	ContainerdAddress = "//./pipe/containerd-containerd"
 )
@@ -0,0 +1,90 @@
+// Package entitlements defines the privileges a build has to request to run
+// execs with access to the host. The daemon allows a subset of them.
+package entitlements
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type Entitlement string
+
+const (
+	// EntitlementNetworkHost runs the execs that request it in the network
+	// namespace of the host
+	EntitlementNetworkHost Entitlement = "network.host"
+	// EntitlementSecurityInsecure runs the execs that request it with all the
+	// capabilities and access to all the devices of the host
+	EntitlementSecurityInsecure Entitlement = "security.insecure"
+	// EntitlementDevice gives the execs that request them access to the
+	// devices of the host
+	EntitlementDevice Entitlement = "device"
+	// EntitlementSecurityProfile lets the execs replace the seccomp, AppArmor
+	// and SELinux profiles of the daemon
+	EntitlementSecurityProfile Entitlement = "security.profile"
+)
+
+var all = map[Entitlement]struct{}{
+	EntitlementNetworkHost:      {},
+	EntitlementSecurityInsecure: {},
+	EntitlementDevice:           {},
+	EntitlementSecurityProfile:  {},
+}
+
+// Parse returns the entitlement named s. "security.privileged" is an alias of
+// "security.insecure".
+func Parse(s string) (Entitlement, error) {
+	e := Entitlement(strings.TrimSpace(s))
+	if e == "security.privileged" {
+		e = EntitlementSecurityInsecure
+	}
+	if _, ok := all[e]; !ok {
+		return "", errors.Errorf("unknown entitlement %s", s)
+	}
+	return e, nil
+}
+
+// Set is a set of entitlements granted to a build
+type Set map[Entitlement]struct{}
+
+// WhiteList returns the set of the requested entitlements. All of them have
+// to be allowed.
+func WhiteList(allowed, requested []Entitlement) (Set, error) {
+	m := Set{}
+	for _, e := range requested {
+		if !contains(allowed, e) {
+			return nil, errors.Errorf("granting entitlement %s is not allowed by the daemon", e)
+		}
+		m[e] = struct{}{}
+	}
+	return m, nil
+}
+
+// Allowed returns nil if e is in the set
+func (s Set) Allowed(e Entitlement) error {
+	if _, ok := s[e]; !ok {
+		return errors.Errorf("%s is not allowed, the build has to request the entitlement and the daemon has to allow it", e)
+	}
+	return nil
+}
+
+// List returns the sorted entitlements of the set
+func (s Set) List() []Entitlement {
+	l := make([]Entitlement, 0, len(s))
+	for e := range s {
+		l = append(l, e)
+	}
+	sort.Slice(l, func(i, j int) bool { return l[i] < l[j] })
+	return l
+}
+
+func contains(l []Entitlement, e Entitlement) bool {
+	for _, v := range l {
+		if v == e {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package entitlements
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	e, err := Parse("network.host")
+	require.NoError(t, err)
+	require.Equal(t, EntitlementNetworkHost, e)
+
+	e, err = Parse("security.privileged")
+	require.NoError(t, err)
+	require.Equal(t, EntitlementSecurityInsecure, e)
+
+	_, err = Parse("network.none")
+	require.Error(t, err)
+}
+
+func TestWhiteList(t *testing.T) {
+	allowed := []Entitlement{EntitlementNetworkHost, EntitlementDevice}
+
+	s, err := WhiteList(allowed, nil)
+	require.NoError(t, err)
+	require.Error(t, s.Allowed(EntitlementNetworkHost))
+
+	s, err = WhiteList(allowed, []Entitlement{EntitlementDevice, EntitlementNetworkHost})
+	require.NoError(t, err)
+	require.NoError(t, s.Allowed(EntitlementNetworkHost))
+	require.NoError(t, s.Allowed(EntitlementDevice))
+	require.Error(t, s.Allowed(EntitlementSecurityInsecure))
+	require.Equal(t, []Entitlement{EntitlementDevice, EntitlementNetworkHost}, s.List())
+
+	_, err = WhiteList(allowed, []Entitlement{EntitlementSecurityInsecure})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not allowed by the daemon")
+}
@@ -0,0 +1,343 @@
+// Package estargz reads and writes eStargz layers. An eStargz layer is a
+// gzipped tar that can still be unpacked as a regular layer, but where every
+// file starts a new gzip stream and an index of the files, the TOC, is stored
+// at the end. The files can be read without the rest of the layer by reading
+// the ranges of their gzip streams, eg. with HTTP range requests to the
+// registry.
+package estargz
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+const (
+	// TOCTarName is the name of the tar entry of the TOC
+	TOCTarName = "stargz.index.json"
+	// TOCDigestAnnotation is the annotation of the layer descriptors with
+	// the digest of the uncompressed TOC
+	TOCDigestAnnotation = "containerd.io/snapshot/stargz/toc.digest"
+	// FooterSize is the size of the gzip stream at the end of the layer that
+	// stores the offset of the TOC
+	FooterSize = 51
+)
+
+// TOC is the index of the files of a layer
+type TOC struct {
+	Version int         `json:"version"`
+	Entries []*TOCEntry `json:"entries"`
+}
+
+// TOCEntry is a file of a layer. The content of the regular files is split
+// in chunks, the first chunk is stored in the entry of the file and the
+// following ones in entries of the "chunk" type that follow it.
+type TOCEntry struct {
+	Name     string    `json:"name"`
+	Type     string    `json:"type"`
+	Size     int64     `json:"size,omitempty"`
+	ModTime  time.Time `json:"modtime,omitempty"`
+	LinkName string    `json:"linkName,omitempty"`
+	Mode     int64     `json:"mode,omitempty"`
+	UID      int       `json:"uid,omitempty"`
+	GID      int       `json:"gid,omitempty"`
+	// Offset is the offset in the layer of the gzip stream of the chunk
+	Offset      int64  `json:"offset,omitempty"`
+	ChunkOffset int64  `json:"chunkOffset,omitempty"`
+	ChunkSize   int64  `json:"chunkSize,omitempty"`
+	Digest      string `json:"digest,omitempty"`
+}
+
+// IsEStargz returns true if the annotations of a layer descriptor mark it as
+// an eStargz layer
+func IsEStargz(annotations map[string]string) bool {
+	_, ok := annotations[TOCDigestAnnotation]
+	return ok
+}
+
+// Reader reads the files of a layer
+type Reader struct {
+	sr      *io.SectionReader
+	toc     *TOC
+	entries map[string]*TOCEntry
+	chunks  map[string][]*TOCEntry
+}
+
+// Open reads the TOC of the layer sr. Only the end of the layer is read.
+func Open(sr *io.SectionReader) (*Reader, error) {
+	if sr.Size() < FooterSize {
+		return nil, errors.Errorf("layer of size %d is too small", sr.Size())
+	}
+	footer := make([]byte, FooterSize)
+	if _, err := sr.ReadAt(footer, sr.Size()-FooterSize); err != nil {
+		return nil, errors.Wrap(err, "failed to read footer")
+	}
+	tocOffset, err := parseFooter(footer)
+	if err != nil {
+		return nil, err
+	}
+	if tocOffset < 0 || tocOffset > sr.Size()-FooterSize {
+		return nil, errors.Errorf("invalid TOC offset %d", tocOffset)
+	}
+
+	zr, err := gzip.NewReader(io.NewSectionReader(sr, tocOffset, sr.Size()-FooterSize-tocOffset))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read TOC")
+	}
+	tr := tar.NewReader(zr)
+	h, err := tr.Next()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read TOC")
+	}
+	if h.Name != TOCTarName {
+		return nil, errors.Errorf("invalid TOC entry %s", h.Name)
+	}
+	var toc TOC
+	if err := json.NewDecoder(tr).Decode(&toc); err != nil {
+		return nil, errors.Wrap(err, "failed to decode TOC")
+	}
+
+	r := &Reader{
+		sr:      sr,
+		toc:     &toc,
+		entries: map[string]*TOCEntry{},
+		chunks:  map[string][]*TOCEntry{},
+	}
+	var last *TOCEntry
+	for _, e := range toc.Entries {
+		name := cleanName(e.Name)
+		if e.Type == "chunk" {
+			if last == nil || cleanName(last.Name) != name {
+				return nil, errors.Errorf("chunk of %s does not follow its file", e.Name)
+			}
+			r.chunks[name] = append(r.chunks[name], e)
+			continue
+		}
+		r.entries[name] = e
+		if e.Type == "reg" {
+			r.chunks[name] = []*TOCEntry{e}
+		}
+		last = e
+	}
+	return r, nil
+}
+
+// TOC returns the index of the files of the layer
+func (r *Reader) TOC() *TOC {
+	return r.toc
+}
+
+// Lookup returns the entry of the file name
+func (r *Reader) Lookup(name string) (*TOCEntry, bool) {
+	e, ok := r.entries[cleanName(name)]
+	return e, ok
+}
+
+// OpenFile returns the content of the regular file name. Only the gzip
+// streams of the file are read from the layer.
+func (r *Reader) OpenFile(name string) (io.Reader, error) {
+	e, ok := r.Lookup(name)
+	if !ok {
+		return nil, errors.Errorf("%s not found", name)
+	}
+	if e.Type != "reg" {
+		return nil, errors.Errorf("%s is not a regular file", name)
+	}
+	chunks := r.chunks[cleanName(name)]
+	readers := make([]io.Reader, 0, len(chunks))
+	for _, c := range chunks {
+		readers = append(readers, &chunkReader{sr: r.sr, entry: c, fileSize: e.Size})
+	}
+	return io.MultiReader(readers...), nil
+}
+
+// chunkReader decompresses a chunk when it is read for the first time
+type chunkReader struct {
+	sr       *io.SectionReader
+	entry    *TOCEntry
+	fileSize int64
+	r        io.Reader
+}
+
+func (c *chunkReader) Read(p []byte) (int, error) {
+	if c.r == nil {
+		size := c.entry.ChunkSize
+		if size == 0 {
+			size = c.fileSize - c.entry.ChunkOffset
+		}
+		zr, err := gzip.NewReader(io.NewSectionReader(c.sr, c.entry.Offset, c.sr.Size()-c.entry.Offset))
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to read chunk of %s", c.entry.Name)
+		}
+		zr.Multistream(false)
+		c.r = io.LimitReader(zr, size)
+	}
+	return c.r.Read(p)
+}
+
+// Build converts the uncompressed tar layer r to an eStargz layer written
+// to w. It returns the digest of the TOC that is set as the
+// TOCDigestAnnotation of the layer.
+func Build(w io.Writer, r io.Reader) (digest.Digest, error) {
+	cw := &countWriter{w: w}
+	zw := gzip.NewWriter(cw)
+	tw := tar.NewWriter(zw)
+	// the content of every file starts a new gzip stream, the tar writer
+	// writes the headers directly to the stream
+	restart := func() error {
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		zw.Reset(cw)
+		return nil
+	}
+
+	toc := &TOC{Version: 1}
+	tr := tar.NewReader(r)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", errors.Wrap(err, "failed to read tar")
+		}
+		e := &TOCEntry{
+			Name:     h.Name,
+			Size:     h.Size,
+			ModTime:  h.ModTime,
+			LinkName: h.Linkname,
+			Mode:     h.Mode,
+			UID:      h.Uid,
+			GID:      h.Gid,
+		}
+		switch h.Typeflag {
+		case tar.TypeReg, tar.TypeRegA:
+			e.Type = "reg"
+		case tar.TypeDir:
+			e.Type = "dir"
+		case tar.TypeSymlink:
+			e.Type = "symlink"
+		case tar.TypeLink:
+			e.Type = "hardlink"
+		case tar.TypeChar:
+			e.Type = "char"
+		case tar.TypeBlock:
+			e.Type = "block"
+		case tar.TypeFifo:
+			e.Type = "fifo"
+		default:
+			return "", errors.Errorf("unsupported tar entry %s of type %c", h.Name, h.Typeflag)
+		}
+		if err := tw.WriteHeader(h); err != nil {
+			return "", err
+		}
+		if e.Type == "reg" && h.Size > 0 {
+			if err := restart(); err != nil {
+				return "", err
+			}
+			e.Offset = cw.n
+			dgstr := digest.Canonical.Digester()
+			if _, err := io.CopyN(tw, io.TeeReader(tr, dgstr.Hash()), h.Size); err != nil {
+				return "", errors.Wrapf(err, "failed to copy %s", h.Name)
+			}
+			e.Digest = dgstr.Digest().String()
+		}
+		toc.Entries = append(toc.Entries, e)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return "", err
+	}
+	if err := restart(); err != nil {
+		return "", err
+	}
+	tocOffset := cw.n
+	dt, err := json.Marshal(toc)
+	if err != nil {
+		return "", err
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Name:     TOCTarName,
+		Typeflag: tar.TypeReg,
+		Mode:     0444,
+		Size:     int64(len(dt)),
+	}); err != nil {
+		return "", err
+	}
+	if _, err := tw.Write(dt); err != nil {
+		return "", err
+	}
+	if err := tw.Close(); err != nil {
+		return "", err
+	}
+	if err := zw.Close(); err != nil {
+		return "", err
+	}
+	if _, err := w.Write(footer(tocOffset)); err != nil {
+		return "", err
+	}
+	return digest.FromBytes(dt), nil
+}
+
+// footer is an empty gzip stream with the offset of the TOC in the extra
+// field of its header. It is written by hand, the empty stored block keeps
+// its size compatible with the other implementations.
+func footer(tocOffset int64) []byte {
+	extra := append([]byte{'S', 'G', 22, 0}, []byte(fmt.Sprintf("%016xSTARGZ", tocOffset))...)
+	p := make([]byte, 0, FooterSize)
+	// magic, deflate, FEXTRA, no modification time, no extra flags, unknown OS
+	p = append(p, 0x1f, 0x8b, 8, 4, 0, 0, 0, 0, 0, 0xff)
+	p = append(p, byte(len(extra)), 0)
+	p = append(p, extra...)
+	// final empty stored block
+	p = append(p, 1, 0, 0, 0xff, 0xff)
+	// CRC-32 and size of the empty content
+	p = append(p, 0, 0, 0, 0, 0, 0, 0, 0)
+	return p
+}
+
+func parseFooter(p []byte) (int64, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(p))
+	if err != nil {
+		return 0, errors.Wrap(err, "invalid footer")
+	}
+	if _, err := io.Copy(ioutil.Discard, zr); err != nil {
+		return 0, errors.Wrap(err, "invalid footer")
+	}
+	extra := zr.Header.Extra
+	if len(extra) != 26 || extra[0] != 'S' || extra[1] != 'G' || !strings.HasSuffix(string(extra), "STARGZ") {
+		return 0, errors.New("layer is not an eStargz layer")
+	}
+	offset, err := strconv.ParseInt(string(extra[4:20]), 16, 64)
+	if err != nil {
+		return 0, errors.Wrap(err, "invalid TOC offset")
+	}
+	return offset, nil
+}
+
+func cleanName(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,84 @@
+package estargz
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuild(t *testing.T) {
+	files := map[string]string{
+		"foo/bar": "bar contents",
+		"baz":     string(bytes.Repeat([]byte("baz"), 10000)),
+		"empty":   "",
+	}
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "foo/", Typeflag: tar.TypeDir, Mode: 0755}))
+	for _, name := range []string{"foo/bar", "baz", "empty"} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(files[name]))}))
+		_, err := tw.Write([]byte(files[name]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "foo/bar"}))
+	require.NoError(t, tw.Close())
+
+	layer := &bytes.Buffer{}
+	tocDigest, err := Build(layer, bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+
+	// the layer is still a regular gzipped tar
+	zr, err := gzip.NewReader(bytes.NewReader(layer.Bytes()))
+	require.NoError(t, err)
+	tr := tar.NewReader(zr)
+	var names []string
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, h.Name)
+		if want, ok := files[h.Name]; ok {
+			dt, err := ioutil.ReadAll(tr)
+			require.NoError(t, err)
+			require.Equal(t, want, string(dt))
+		}
+	}
+	require.Equal(t, []string{"foo/", "foo/bar", "baz", "empty", "link", TOCTarName}, names)
+
+	r, err := Open(io.NewSectionReader(bytes.NewReader(layer.Bytes()), 0, int64(layer.Len())))
+	require.NoError(t, err)
+	require.Equal(t, 5, len(r.TOC().Entries))
+
+	for name, want := range files {
+		f, err := r.OpenFile("/" + name)
+		require.NoError(t, err)
+		dt, err := ioutil.ReadAll(f)
+		require.NoError(t, err)
+		require.Equal(t, want, string(dt))
+	}
+	e, ok := r.Lookup("baz")
+	require.True(t, ok)
+	require.Equal(t, digest.FromString(files["baz"]).String(), e.Digest)
+
+	e, ok = r.Lookup("link")
+	require.True(t, ok)
+	require.Equal(t, "symlink", e.Type)
+	require.Equal(t, "foo/bar", e.LinkName)
+	_, err = r.OpenFile("link")
+	require.Error(t, err)
+
+	require.NoError(t, tocDigest.Validate())
+	require.Equal(t, FooterSize, len(footer(0)))
+
+	// a regular layer has no footer
+	_, err = Open(io.NewSectionReader(bytes.NewReader(buf.Bytes()), 0, int64(buf.Len())))
+	require.Error(t, err)
+}
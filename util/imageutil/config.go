@@ -9,6 +9,7 @@ import (
 	"github.com/containerd/containerd/platforms"
 	"github.com/containerd/containerd/reference"
 	"github.com/containerd/containerd/remotes"
+	digest "github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 )
@@ -18,10 +19,13 @@ type IngesterProvider interface {
 	content.Provider
 }
 
-func Config(ctx context.Context, str string, resolver remotes.Resolver, ingester IngesterProvider) ([]byte, error) {
+// Config returns the digest of the manifest or the manifest list of the image
+// str and the JSON config of the image for platform. The config of the
+// default platform is returned if platform is nil.
+func Config(ctx context.Context, str string, resolver remotes.Resolver, ingester IngesterProvider, platform *ocispec.Platform) (digest.Digest, []byte, error) {
 	ref, err := reference.Parse(str)
 	if err != nil {
-		return nil, errors.WithStack(err)
+		return "", nil, errors.WithStack(err)
 	}
 
 	dgst := ref.Digest()
@@ -43,14 +47,14 @@ func Config(ctx context.Context, str string, resolver remotes.Resolver, ingester
 	if desc == nil {
 		_, desc2, err := resolver.Resolve(ctx, ref.String())
 		if err != nil {
-			return nil, err
+			return "", nil, err
 		}
 		desc = &desc2
 	}
 
 	fetcher, err := resolver.Fetcher(ctx, ref.String())
 	if err != nil {
-		return nil, err
+		return "", nil, err
 	}
 
 	handlers := []images.Handler{
@@ -58,14 +62,22 @@ func Config(ctx context.Context, str string, resolver remotes.Resolver, ingester
 		childrenConfigHandler(ingester),
 	}
 	if err := images.Dispatch(ctx, images.Handlers(handlers...), *desc); err != nil {
-		return nil, err
+		return "", nil, err
 	}
-	config, err := images.Config(ctx, ingester, *desc, platforms.Format(platforms.Default()))
+	p := platforms.Default()
+	if platform != nil {
+		p = platforms.Normalize(*platform)
+	}
+	config, err := images.Config(ctx, ingester, *desc, platforms.Format(p))
 	if err != nil {
-		return nil, err
+		return "", nil, err
 	}
 
-	return content.ReadBlob(ctx, ingester, config.Digest)
+	dt, err := content.ReadBlob(ctx, ingester, config.Digest)
+	if err != nil {
+		return "", nil, err
+	}
+	return desc.Digest, dt, nil
 }
 
 func childrenConfigHandler(provider content.Provider) images.HandlerFunc {
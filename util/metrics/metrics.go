@@ -0,0 +1,398 @@
+// Package metrics implements the counters, gauges and histograms of the
+// daemon and writes them in the Prometheus text exposition format. The
+// metrics can have labels. The label values are passed to the methods
+// updating a metric in the order of the label names of the metric.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultRegistry is the registry of the metrics of the daemon
+var DefaultRegistry = NewRegistry()
+
+// MustRegister adds the metrics to DefaultRegistry and panics if a metric with
+// the same name was registered before
+func MustRegister(cs ...Collector) {
+	DefaultRegistry.MustRegister(cs...)
+}
+
+// Handler serves the metrics of DefaultRegistry
+func Handler() http.Handler {
+	return DefaultRegistry
+}
+
+// Collector is a metric that can be added to a registry
+type Collector interface {
+	// Name returns the name of the metric
+	Name() string
+	write(w io.Writer)
+}
+
+// Registry is a set of metrics that are written together
+type Registry struct {
+	mu      sync.Mutex
+	metrics map[string]Collector
+}
+
+// NewRegistry returns an empty registry
+func NewRegistry() *Registry {
+	return &Registry{metrics: map[string]Collector{}}
+}
+
+// Register adds a metric to the registry
+func (r *Registry) Register(c Collector) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.metrics[c.Name()]; ok {
+		return errors.Errorf("metric %s is already registered", c.Name())
+	}
+	r.metrics[c.Name()] = c
+	return nil
+}
+
+// MustRegister adds the metrics to the registry and panics on an error
+func (r *Registry) MustRegister(cs ...Collector) {
+	for _, c := range cs {
+		if err := r.Register(c); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Unregister removes the metric name from the registry
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.metrics, name)
+}
+
+// WriteTo writes the metrics sorted by name
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	cs := make([]Collector, 0, len(r.metrics))
+	for _, c := range r.metrics {
+		cs = append(cs, c)
+	}
+	r.mu.Unlock()
+	sort.Slice(cs, func(i, j int) bool { return cs[i].Name() < cs[j].Name() })
+
+	cw := &countWriter{w: bufio.NewWriter(w)}
+	for _, c := range cs {
+		c.write(cw)
+	}
+	if err := cw.w.Flush(); err != nil {
+		return cw.n, err
+	}
+	return cw.n, cw.err
+}
+
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	r.WriteTo(w)
+}
+
+type countWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (cw *countWriter) Write(dt []byte) (int, error) {
+	n, err := cw.w.Write(dt)
+	cw.n += int64(n)
+	if err != nil && cw.err == nil {
+		cw.err = err
+	}
+	return n, err
+}
+
+// desc describes a metric and its labels
+type desc struct {
+	name   string
+	help   string
+	typ    string
+	labels []string
+}
+
+func (d *desc) Name() string {
+	return d.name
+}
+
+func (d *desc) writeHeader(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n", d.name, escapeHelp(d.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", d.name, d.typ)
+}
+
+// key joins the label values of a series
+func (d *desc) key(values []string) string {
+	if len(values) != len(d.labels) {
+		panic(fmt.Sprintf("metric %s has %d labels, got %d values", d.name, len(d.labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+// labelPairs formats the labels of the series key with the extra label pairs
+func (d *desc) labelPairs(key string, extra ...string) string {
+	var pairs []string
+	if len(d.labels) > 0 {
+		for i, v := range strings.Split(key, "\xff") {
+			pairs = append(pairs, d.labels[i]+`="`+escapeLabelValue(v)+`"`)
+		}
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pairs = append(pairs, extra[i]+`="`+escapeLabelValue(extra[i+1])+`"`)
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// series are the values of a counter or a gauge by label values
+type series struct {
+	desc
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+func (s *series) add(v float64, values []string) {
+	k := s.key(values)
+	s.mu.Lock()
+	s.values[k] += v
+	s.mu.Unlock()
+}
+
+func (s *series) write(w io.Writer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.writeHeader(w)
+	for _, k := range sortedKeys(s.values) {
+		fmt.Fprintf(w, "%s%s %s\n", s.name, s.labelPairs(k), formatFloat(s.values[k]))
+	}
+}
+
+func (s *series) init(name, help, typ string, labels []string) {
+	s.desc = desc{name: name, help: help, typ: typ, labels: labels}
+	s.values = map[string]float64{}
+	if len(labels) == 0 {
+		// a metric without labels is reported before it is updated
+		s.values[""] = 0
+	}
+}
+
+// Counter is a metric that only increases
+type Counter struct {
+	series
+}
+
+// NewCounter returns a counter with the label names
+func NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{}
+	c.init(name, help, "counter", labels)
+	return c
+}
+
+// Inc adds one to the counter
+func (c *Counter) Inc(labelValues ...string) {
+	c.add(1, labelValues)
+}
+
+// Add adds v to the counter. v must not be negative.
+func (c *Counter) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		panic(fmt.Sprintf("counter %s can not decrease", c.name))
+	}
+	c.add(v, labelValues)
+}
+
+// Gauge is a metric that can increase and decrease
+type Gauge struct {
+	series
+}
+
+// NewGauge returns a gauge with the label names
+func NewGauge(name, help string, labels ...string) *Gauge {
+	g := &Gauge{}
+	g.init(name, help, "gauge", labels)
+	return g
+}
+
+// Set sets the value of the gauge
+func (g *Gauge) Set(v float64, labelValues ...string) {
+	k := g.key(labelValues)
+	g.mu.Lock()
+	g.values[k] = v
+	g.mu.Unlock()
+}
+
+// Add adds v to the gauge
+func (g *Gauge) Add(v float64, labelValues ...string) {
+	g.add(v, labelValues)
+}
+
+// Inc adds one to the gauge
+func (g *Gauge) Inc(labelValues ...string) {
+	g.add(1, labelValues)
+}
+
+// Dec subtracts one from the gauge
+func (g *Gauge) Dec(labelValues ...string) {
+	g.add(-1, labelValues)
+}
+
+// GaugeFunc is a gauge with a value that is read when the metrics are written
+type GaugeFunc struct {
+	desc
+	fn  func() float64
+	ttl time.Duration
+
+	mu    sync.Mutex
+	value float64
+	ts    time.Time
+}
+
+// NewGaugeFunc returns a gauge returning the value of fn
+func NewGaugeFunc(name, help string, fn func() float64) *GaugeFunc {
+	return &GaugeFunc{desc: desc{name: name, help: help, typ: "gauge"}, fn: fn}
+}
+
+// NewCachedGaugeFunc returns a gauge returning the value of fn that is
+// reused for ttl, for the values that are expensive to read
+func NewCachedGaugeFunc(name, help string, ttl time.Duration, fn func() float64) *GaugeFunc {
+	g := NewGaugeFunc(name, help, fn)
+	g.ttl = ttl
+	return g
+}
+
+func (g *GaugeFunc) get() float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.ts.IsZero() || time.Since(g.ts) >= g.ttl {
+		g.value = g.fn()
+		g.ts = time.Now()
+	}
+	return g.value
+}
+
+func (g *GaugeFunc) write(w io.Writer) {
+	g.writeHeader(w)
+	fmt.Fprintf(w, "%s %s\n", g.name, formatFloat(g.get()))
+}
+
+// DefBuckets are the default buckets of the histograms of durations in
+// seconds
+var DefBuckets = []float64{.01, .05, .1, .5, 1, 5, 10, 30, 60, 300, 600}
+
+// Histogram counts observed values in buckets
+type Histogram struct {
+	desc
+	buckets []float64
+	mu      sync.Mutex
+	values  map[string]*histogramValue
+}
+
+type histogramValue struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewHistogram returns a histogram with the upper bounds of the buckets in
+// increasing order and the label names. DefBuckets are used if buckets is
+// empty.
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if len(buckets) == 0 {
+		buckets = DefBuckets
+	}
+	if !sort.Float64sAreSorted(buckets) {
+		panic(fmt.Sprintf("buckets of histogram %s are not sorted", name))
+	}
+	return &Histogram{
+		desc:    desc{name: name, help: help, typ: "histogram", labels: labels},
+		buckets: buckets,
+		values:  map[string]*histogramValue{},
+	}
+}
+
+// Observe adds a value to the histogram
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	k := h.key(labelValues)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	hv, ok := h.values[k]
+	if !ok {
+		hv = &histogramValue{counts: make([]uint64, len(h.buckets))}
+		h.values[k] = hv
+	}
+	for i, b := range h.buckets {
+		if v <= b {
+			hv.counts[i]++
+		}
+	}
+	hv.count++
+	hv.sum += v
+}
+
+func (h *Histogram) write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.writeHeader(w)
+	keys := make([]string, 0, len(h.values))
+	for k := range h.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		hv := h.values[k]
+		for i, b := range h.buckets {
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelPairs(k, "le", formatFloat(b)), hv.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelPairs(k, "le", "+Inf"), hv.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, h.labelPairs(k), formatFloat(hv.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, h.labelPairs(k), hv.count)
+	}
+}
+
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// escapeLabelValue escapes the backslashes, the double quotes and the line
+// feeds of a label value, the other characters are written as they are
+func escapeLabelValue(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, `"`, `\"`, -1)
+	return strings.Replace(s, "\n", `\n`, -1)
+}
+
+func escapeHelp(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	return strings.Replace(s, "\n", `\n`, -1)
+}
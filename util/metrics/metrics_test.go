@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+
+	c := NewCounter("test_total", "Total of the test", "kind")
+	g := NewGauge("test_running", "Running\ntests")
+	h := NewHistogram("test_seconds", "Durations", []float64{1, 5})
+	f := NewGaugeFunc("test_size_bytes", "Size", func() float64 { return 1024 })
+	r.MustRegister(c, g, h, f)
+	require.Error(t, r.Register(NewCounter("test_total", "")))
+
+	c.Inc("a")
+	c.Add(2, "b")
+	c.Inc("a")
+	g.Inc()
+	g.Inc()
+	g.Dec()
+	h.Observe(0.5)
+	h.Observe(3)
+	h.Observe(10)
+
+	buf := &bytes.Buffer{}
+	_, err := r.WriteTo(buf)
+	require.NoError(t, err)
+	require.Equal(t, `# HELP test_running Running\ntests
+# TYPE test_running gauge
+test_running 1
+# HELP test_seconds Durations
+# TYPE test_seconds histogram
+test_seconds_bucket{le="1"} 1
+test_seconds_bucket{le="5"} 2
+test_seconds_bucket{le="+Inf"} 3
+test_seconds_sum 13.5
+test_seconds_count 3
+# HELP test_size_bytes Size
+# TYPE test_size_bytes gauge
+test_size_bytes 1024
+# HELP test_total Total of the test
+# TYPE test_total counter
+test_total{kind="a"} 2
+test_total{kind="b"} 2
+`, buf.String())
+
+	r.Unregister("test_total")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	require.Equal(t, "text/plain; version=0.0.4", rec.Header().Get("Content-Type"))
+	require.NotContains(t, rec.Body.String(), "test_total")
+	require.Contains(t, rec.Body.String(), "test_running 1\n")
+
+	require.Panics(t, func() { c.Inc() })
+	require.Panics(t, func() { c.Add(-1, "a") })
+}
+
+func TestLabelValues(t *testing.T) {
+	r := NewRegistry()
+	c := NewCounter("test_total", "Total", "name")
+	r.MustRegister(c)
+	c.Inc("a\\b\"c\nd\té")
+
+	buf := &bytes.Buffer{}
+	_, err := r.WriteTo(buf)
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "test_total{name=\"a\\\\b\\\"c\\nd\té\"} 1\n")
+}
+
+func TestCachedGaugeFunc(t *testing.T) {
+	var calls int
+	g := NewCachedGaugeFunc("test_size_bytes", "Size", time.Hour, func() float64 {
+		calls++
+		return float64(calls)
+	})
+	for i := 0; i < 2; i++ {
+		buf := &bytes.Buffer{}
+		g.write(buf)
+		require.Contains(t, buf.String(), "test_size_bytes 1\n")
+	}
+	require.Equal(t, 1, calls)
+
+	g.ttl = 0
+	buf := &bytes.Buffer{}
+	g.write(buf)
+	require.Contains(t, buf.String(), "test_size_bytes 2\n")
+}
@@ -0,0 +1,90 @@
+// Package peercred identifies the clients of the daemon for the limits that
+// apply per client. The clients of the local socket are identified by the uid
+// of their process and the TLS clients by the subject of their certificate.
+package peercred
+
+import (
+	"fmt"
+	"net"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// AuthInfo is the identity of the process connected to the local socket
+type AuthInfo struct {
+	UID int
+}
+
+func (AuthInfo) AuthType() string {
+	return "peercred"
+}
+
+// NewCredentials returns the transport credentials of a server on the local
+// socket. The connections are not encrypted, the handshake only reads the
+// uid of the peer process when the platform supports it.
+func NewCredentials() credentials.TransportCredentials {
+	return &peerCredentials{}
+}
+
+type peerCredentials struct{}
+
+func (c *peerCredentials) ClientHandshake(ctx context.Context, addr string, conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return conn, nil, nil
+}
+
+func (c *peerCredentials) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	uid, ok := peerUID(conn)
+	if !ok {
+		return conn, nil, nil
+	}
+	return conn, AuthInfo{UID: uid}, nil
+}
+
+func (c *peerCredentials) Info() credentials.ProtocolInfo {
+	return credentials.ProtocolInfo{SecurityProtocol: "peercred"}
+}
+
+func (c *peerCredentials) Clone() credentials.TransportCredentials {
+	return &peerCredentials{}
+}
+
+func (c *peerCredentials) OverrideServerName(string) error {
+	return nil
+}
+
+// Identity returns the identity of the client of the request in ctx: the uid
+// of a local process or the subject of a verified TLS client certificate. It
+// is empty if the peer can't be identified, so that all the unidentified
+// clients share the same limits.
+func Identity(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	if info, ok := p.AuthInfo.(AuthInfo); ok {
+		return fmt.Sprintf("uid:%d", info.UID)
+	}
+	if subject, ok := Certificate(ctx); ok {
+		return "cert:" + subject
+	}
+	return ""
+}
+
+// Certificate returns the subject of the verified TLS client certificate of
+// the request in ctx, false if the client didn't authenticate with one
+func Certificate(ctx context.Context) (string, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "", false
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return "", false
+	}
+	if chains := info.State.VerifiedChains; len(chains) > 0 && len(chains[0]) > 0 {
+		return chains[0][0].Subject.String(), true
+	}
+	return "", false
+}
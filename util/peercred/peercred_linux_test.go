@@ -0,0 +1,36 @@
+package peercred
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerHandshake(t *testing.T) {
+	dir, err := ioutil.TempDir("", "peercred")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	l, err := net.Listen("unix", filepath.Join(dir, "sock"))
+	require.NoError(t, err)
+	defer l.Close()
+
+	go func() {
+		c, err := net.Dial("unix", filepath.Join(dir, "sock"))
+		if err == nil {
+			defer c.Close()
+			c.Read(make([]byte, 1))
+		}
+	}()
+	conn, err := l.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, info, err := NewCredentials().ServerHandshake(conn)
+	require.NoError(t, err)
+	require.Equal(t, AuthInfo{UID: os.Getuid()}, info)
+}
@@ -0,0 +1,10 @@
+// +build !linux
+
+package peercred
+
+import "net"
+
+// peerUID returns false as the uid of the peer is only read on linux
+func peerUID(conn net.Conn) (int, bool) {
+	return 0, false
+}
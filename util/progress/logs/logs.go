@@ -3,16 +3,16 @@ package logs
 import (
 	"context"
 	"io"
-	"os"
 
 	"github.com/moby/buildkit/client"
 	"github.com/moby/buildkit/identity"
 	"github.com/moby/buildkit/util/progress"
-	"github.com/pkg/errors"
 )
 
+// NewLogStreams returns the writers for the stdout and stderr of a process.
+// Every write is sent as a client.VertexLog of the vertex of the context.
 func NewLogStreams(ctx context.Context) (io.WriteCloser, io.WriteCloser) {
-	return newStreamWriter(ctx, 1), newStreamWriter(ctx, 2)
+	return newStreamWriter(ctx, client.StreamStdout), newStreamWriter(ctx, client.StreamStderr)
 }
 
 func newStreamWriter(ctx context.Context, stream int) io.WriteCloser {
@@ -29,19 +29,12 @@ type streamWriter struct {
 }
 
 func (sw *streamWriter) Write(dt []byte) (int, error) {
+	// the timestamp is set from the progress item when it's sent to the client
 	sw.pw.Write(identity.NewID(), client.VertexLog{
 		Stream: sw.stream,
 		Data:   append([]byte{}, dt...),
 	})
-	// TODO: remove debug
-	switch sw.stream {
-	case 1:
-		return os.Stdout.Write(dt)
-	case 2:
-		return os.Stderr.Write(dt)
-	default:
-		return 0, errors.Errorf("invalid stream %d", sw.stream)
-	}
+	return len(dt), nil
 }
 
 func (sw *streamWriter) Close() error {
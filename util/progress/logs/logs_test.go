@@ -0,0 +1,39 @@
+package logs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/util/progress"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogStreams(t *testing.T) {
+	pr, ctx, cancelProgress := progress.NewContext(context.Background())
+	_, _, ctx = progress.FromContext(ctx, progress.WithMetadata("vertex", "foo"))
+
+	stdout, stderr := NewLogStreams(ctx)
+	n, err := stdout.Write([]byte("out"))
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+	_, err = stderr.Write([]byte("err"))
+	require.NoError(t, err)
+	require.NoError(t, stdout.Close())
+	require.NoError(t, stderr.Close())
+	cancelProgress()
+
+	p, err := pr.Read(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, 2, len(p))
+	for i, stream := range []int{client.StreamStdout, client.StreamStderr} {
+		l, ok := p[i].Sys.(client.VertexLog)
+		require.True(t, ok)
+		require.Equal(t, stream, l.Stream)
+		require.False(t, p[i].Timestamp.IsZero())
+		v, ok := p[i].Meta("vertex")
+		require.True(t, ok)
+		require.Equal(t, "foo", v)
+	}
+	require.Equal(t, "out", string(p[0].Sys.(client.VertexLog).Data))
+}
@@ -130,3 +130,30 @@ func saveProgress(ctx context.Context, pr Reader, t *trace) error {
 		t.items = append(t.items, p...)
 	}
 }
+
+func TestTransfer(t *testing.T) {
+	pr, ctx, cancelProgress := NewContext(context.Background())
+	var trace trace
+	done := make(chan error)
+	go func() {
+		done <- saveProgress(ctx, pr, &trace)
+	}()
+
+	tr := NewTransfer(ctx, "layer", "pushing", 10)
+	tr.Update(5)
+	tr.Update(10)
+	tr.Done(nil)
+	tr.Update(3)
+	cancelProgress()
+	assert.NoError(t, <-done)
+
+	assert.True(t, len(trace.items) > 0)
+	last := trace.items[len(trace.items)-1]
+	assert.Equal(t, "layer", last.ID)
+	st := last.Sys.(Status)
+	assert.Equal(t, "done", st.Action)
+	assert.Equal(t, 10, st.Current)
+	assert.Equal(t, 10, st.Total)
+	assert.NotNil(t, st.Started)
+	assert.NotNil(t, st.Completed)
+}
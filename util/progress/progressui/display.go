@@ -3,7 +3,7 @@ package progressui
 import (
 	"context"
 	"fmt"
-	"os"
+	"io"
 	"strings"
 	"time"
 
@@ -15,10 +15,11 @@ import (
 	"golang.org/x/time/rate"
 )
 
-func DisplaySolveStatus(ctx context.Context, ch chan *client.SolveStatus) error {
-	c, err := console.ConsoleFromFile(os.Stdout)
-	if err != nil {
-		return err // TODO: switch to log mode
+// DisplaySolveStatus shows the progress of a solve as an updating view on the
+// console c. If c is nil the progress is printed as plain text on w.
+func DisplaySolveStatus(ctx context.Context, c console.Console, w io.Writer, ch chan *client.SolveStatus) error {
+	if c == nil {
+		return PrintSolveStatus(ctx, w, ch)
 	}
 	disp := &display{c: c}
 
@@ -45,7 +46,10 @@ func DisplaySolveStatus(ctx context.Context, ch chan *client.SolveStatus) error
 
 		if done {
 			disp.print(t.displayInfo(), true)
-			t.printErrorLogs()
+			t.printErrorLogs(c)
+			if t.summary != nil {
+				fmt.Fprintln(c, formatSummary(t.summary))
+			}
 			return nil
 		} else if displayLimiter.Allow() {
 			disp.print(t.displayInfo(), false)
@@ -70,6 +74,7 @@ type job struct {
 }
 
 type trace struct {
+	summary       *client.BuildSummary
 	localTimeDiff time.Duration
 	vertexes      []*vertex
 	byDigest      map[digest.Digest]*vertex
@@ -94,6 +99,9 @@ func newTrace() *trace {
 }
 
 func (t *trace) update(s *client.SolveStatus) {
+	if s.Summary != nil {
+		t.summary = s.Summary
+	}
 	for _, v := range s.Vertexes {
 		prev, ok := t.byDigest[v.Digest]
 		if !ok {
@@ -139,20 +147,15 @@ func (t *trace) update(s *client.SolveStatus) {
 	}
 }
 
-func (t *trace) printErrorLogs() {
+func (t *trace) printErrorLogs(w io.Writer) {
 	for _, v := range t.vertexes {
 		if v.Error != "" && !strings.HasSuffix(v.Error, context.Canceled.Error()) {
-			fmt.Println("------")
-			fmt.Printf(" > %s:\n", v.Name)
+			fmt.Fprintln(w, "------")
+			fmt.Fprintf(w, " > %s:\n", v.Name)
 			for _, l := range v.logs {
-				switch l.Stream {
-				case 1:
-					os.Stdout.Write(l.Data)
-				case 2:
-					os.Stderr.Write(l.Data)
-				}
+				w.Write(l.Data)
 			}
-			fmt.Println("------")
+			fmt.Fprintln(w, "------")
 		}
 	}
 }
@@ -162,14 +165,36 @@ func (t *trace) displayInfo() (d displayInfo) {
 	if t.localTimeDiff != 0 {
 		d.startTime = (*t.vertexes[0].Started).Add(t.localTimeDiff)
 	}
-	d.countTotal = len(t.byDigest)
-	for _, v := range t.byDigest {
+	// the vertexes that are not needed by the build are never started
+	d.countTotal = len(t.vertexes)
+	for _, v := range t.vertexes {
 		if v.Completed != nil {
 			d.countCompleted++
 		}
 	}
 
+	// the completed internal vertexes of a group are shown as a single job
+	groups := map[string]int{}
 	for _, v := range t.vertexes {
+		if v.Group != "" && v.Completed != nil && v.Error == "" {
+			if i, ok := groups[v.Group]; ok {
+				j := &d.jobs[i]
+				if j.startTime.After(*addTime(v.Started, t.localTimeDiff)) {
+					j.startTime = addTime(v.Started, t.localTimeDiff)
+				}
+				if j.completedTime.Before(*addTime(v.Completed, t.localTimeDiff)) {
+					j.completedTime = addTime(v.Completed, t.localTimeDiff)
+				}
+				continue
+			}
+			groups[v.Group] = len(d.jobs)
+			d.jobs = append(d.jobs, job{
+				startTime:     addTime(v.Started, t.localTimeDiff),
+				completedTime: addTime(v.Completed, t.localTimeDiff),
+				name:          v.indent + v.Group,
+			})
+			continue
+		}
 		j := job{
 			startTime:     addTime(v.Started, t.localTimeDiff),
 			completedTime: addTime(v.Completed, t.localTimeDiff),
@@ -243,19 +268,19 @@ func (disp *display) print(d displayInfo, all bool) {
 		b = b.Down(1)
 	}
 	disp.repeated = true
-	fmt.Print(b.Column(0).ANSI)
+	fmt.Fprint(disp.c, b.Column(0).ANSI)
 
 	statusStr := ""
 	if d.countCompleted > 0 && d.countCompleted == d.countTotal {
 		statusStr = "FINISHED"
 	}
 
-	fmt.Print(aec.Hide)
-	defer fmt.Print(aec.Show)
+	fmt.Fprint(disp.c, aec.Hide)
+	defer fmt.Fprint(disp.c, aec.Show)
 
 	out := fmt.Sprintf("[+] Building %.1fs (%d/%d) %s", time.Since(d.startTime).Seconds(), d.countCompleted, d.countTotal, statusStr)
 	out = align(out, "", width)
-	fmt.Println(out)
+	fmt.Fprintln(disp.c, out)
 	lineCount := 0
 	for _, j := range d.jobs {
 		endTime := time.Now()
@@ -303,7 +328,7 @@ func (disp *display) print(d displayInfo, all bool) {
 			}
 			out = aec.Apply(out, color)
 		}
-		fmt.Print(out)
+		fmt.Fprint(disp.c, out)
 		lineCount++
 	}
 	disp.lineCount = lineCount
@@ -0,0 +1,61 @@
+package progressui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moby/buildkit/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisplayInfo(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Second)
+	tr := newTrace()
+	tr.update(&client.SolveStatus{
+		Vertexes: []*client.Vertex{
+			{Digest: "a", Name: "step\ta", Started: &now, Completed: &later, Cached: true},
+			{Digest: "b", Name: "step b", Started: &now, Completed: &later, Error: "failed"},
+			{Digest: "c", Name: "step c", Started: &now},
+		},
+		Statuses: []*client.VertexStatus{
+			{Vertex: "c", ID: "sha256:layer", Current: 1000, Total: 2000, Started: &now},
+		},
+	})
+
+	d := tr.displayInfo()
+	require.Equal(t, 3, d.countTotal)
+	require.Equal(t, 2, d.countCompleted)
+	require.Equal(t, 4, len(d.jobs))
+	require.Equal(t, "CACHED step a", d.jobs[0].name)
+	require.Equal(t, "ERROR step b", d.jobs[1].name)
+	require.True(t, d.jobs[1].hasError)
+	require.Equal(t, "step c", d.jobs[2].name)
+	require.Nil(t, d.jobs[2].completedTime)
+	require.Equal(t, "=> sha256:layer", d.jobs[3].name)
+	require.Equal(t, "1kB / 2kB", d.jobs[3].status)
+}
+
+func TestDisplayInfoGroups(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Second)
+	tr := newTrace()
+	tr.update(&client.SolveStatus{
+		Vertexes: []*client.Vertex{
+			{Digest: "a", Name: "step a"},
+			{Digest: "b", Name: "step b", Started: &now, Completed: &later},
+			{Digest: "c", Name: "exporting build cache to foo", Started: &now, Completed: &now, Internal: true, Group: "exporting cache"},
+			{Digest: "d", Name: "exporting build cache to bar", Started: &now, Completed: &later, Internal: true, Group: "exporting cache"},
+			{Digest: "e", Name: "exporting build cache to baz", Started: &now, Internal: true, Group: "exporting cache"},
+		},
+	})
+
+	d := tr.displayInfo()
+	require.Equal(t, 4, d.countTotal)
+	require.Equal(t, 3, d.countCompleted)
+	require.Equal(t, 3, len(d.jobs))
+	require.Equal(t, "step b", d.jobs[0].name)
+	require.Equal(t, "exporting cache", d.jobs[1].name)
+	require.Equal(t, later.Unix(), d.jobs[1].completedTime.Unix())
+	require.Equal(t, "exporting build cache to baz", d.jobs[2].name)
+}
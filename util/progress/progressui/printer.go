@@ -0,0 +1,190 @@
+package progressui
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/moby/buildkit/client"
+	digest "github.com/opencontainers/go-digest"
+)
+
+// PrintSolveStatus writes the progress of a solve to w as plain text for
+// output that isn't a terminal. The vertexes are numbered when they start and
+// every line of their logs is prefixed with the number so the output of the
+// steps running in parallel can be told apart.
+func PrintSolveStatus(ctx context.Context, w io.Writer, ch chan *client.SolveStatus) error {
+	p := newPrinter(w)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ss, ok := <-ch:
+			if !ok {
+				p.flush()
+				return nil
+			}
+			p.update(ss)
+		}
+	}
+}
+
+type printer struct {
+	w        io.Writer
+	next     int
+	vertexes map[digest.Digest]*printVertex
+}
+
+type printVertex struct {
+	index   int
+	started bool
+	done    bool
+	// partial lines of the log streams
+	lines map[int]*bytes.Buffer
+}
+
+func newPrinter(w io.Writer) *printer {
+	return &printer{w: w, next: 1, vertexes: map[digest.Digest]*printVertex{}}
+}
+
+func (p *printer) vertex(dgst digest.Digest) *printVertex {
+	v, ok := p.vertexes[dgst]
+	if !ok {
+		v = &printVertex{index: p.next, lines: map[int]*bytes.Buffer{}}
+		p.vertexes[dgst] = v
+		p.next++
+	}
+	return v
+}
+
+// update prints the started vertexes before the logs and the completed
+// vertexes after them so the output of a vertex is printed between its start
+// and its result
+func (p *printer) update(ss *client.SolveStatus) {
+	for _, v := range ss.Vertexes {
+		if v.Started == nil {
+			continue
+		}
+		pv := p.vertex(v.Digest)
+		if !pv.started {
+			pv.started = true
+			fmt.Fprintf(p.w, "#%d %s\n", pv.index, strings.Replace(v.Name, "\t", " ", -1))
+		}
+	}
+
+	logs := append([]*client.VertexLog{}, ss.Logs...)
+	sort.SliceStable(logs, func(i, j int) bool {
+		return logs[i].Timestamp.Before(logs[j].Timestamp)
+	})
+	for _, l := range logs {
+		pv := p.vertex(l.Vertex)
+		b, ok := pv.lines[l.Stream]
+		if !ok {
+			b = &bytes.Buffer{}
+			pv.lines[l.Stream] = b
+		}
+		b.Write(l.Data)
+		for {
+			i := bytes.IndexByte(b.Bytes(), '\n')
+			if i < 0 {
+				break
+			}
+			fmt.Fprintf(p.w, "#%d %s\n", pv.index, b.Next(i + 1)[:i])
+		}
+	}
+
+	for _, s := range ss.Statuses {
+		if s.Completed == nil {
+			continue
+		}
+		pv := p.vertex(s.Vertex)
+		size := ""
+		if s.Total != 0 {
+			size = " " + units.HumanSize(float64(s.Current)) + " / " + units.HumanSize(float64(s.Total))
+		}
+		fmt.Fprintf(p.w, "#%d %s%s done\n", pv.index, s.ID, size)
+	}
+
+	for _, v := range ss.Vertexes {
+		if v.Started == nil || v.Completed == nil {
+			continue
+		}
+		pv := p.vertex(v.Digest)
+		if pv.done {
+			continue
+		}
+		pv.done = true
+		p.flushVertex(pv)
+		switch {
+		case v.Error != "":
+			fmt.Fprintf(p.w, "#%d ERROR: %s\n", pv.index, v.Error)
+		case v.Cached:
+			fmt.Fprintf(p.w, "#%d CACHED\n", pv.index)
+		default:
+			fmt.Fprintf(p.w, "#%d DONE %.1fs%s\n", pv.index, v.Completed.Sub(*v.Started).Seconds(), formatTimings(v.Timings))
+		}
+	}
+
+	if ss.Summary != nil {
+		fmt.Fprintln(p.w, formatSummary(ss.Summary))
+	}
+}
+
+// flushVertex prints the log lines of the vertex that didn't end with a
+// newline
+func (p *printer) flushVertex(pv *printVertex) {
+	for _, stream := range []int{client.StreamStdout, client.StreamStderr} {
+		if b, ok := pv.lines[stream]; ok && b.Len() > 0 {
+			fmt.Fprintf(p.w, "#%d %s\n", pv.index, b.String())
+			b.Reset()
+		}
+	}
+}
+
+func (p *printer) flush() {
+	order := make([]*printVertex, 0, len(p.vertexes))
+	for _, pv := range p.vertexes {
+		order = append(order, pv)
+	}
+	sort.Slice(order, func(i, j int) bool { return order[i].index < order[j].index })
+	for _, pv := range order {
+		p.flushVertex(pv)
+	}
+}
+
+// formatTimings returns the phases of the vertex that took some time
+func formatTimings(t *client.VertexTimings) string {
+	if t == nil {
+		return ""
+	}
+	var phases []string
+	for _, ph := range []struct {
+		name string
+		d    time.Duration
+	}{
+		{"cache lookup", t.CacheLookup},
+		{"content hash", t.ContentHash},
+		{"exec", t.Exec},
+		{"commit", t.Commit},
+	} {
+		if ph.d >= 50*time.Millisecond {
+			phases = append(phases, fmt.Sprintf("%s %.1fs", ph.name, ph.d.Seconds()))
+		}
+	}
+	if len(phases) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(phases, ", ") + ")"
+}
+
+// formatSummary returns the line describing the work done by a build
+func formatSummary(s *client.BuildSummary) string {
+	return fmt.Sprintf("%d steps: %d cached, %d executed in %.1fs, %s transferred, %s exported",
+		s.Vertexes, s.Cached, s.Executed, s.ExecTime.Seconds(),
+		units.HumanSize(float64(s.TransferredBytes)), units.HumanSize(float64(s.ExportedBytes)))
+}
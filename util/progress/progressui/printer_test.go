@@ -0,0 +1,58 @@
+package progressui
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/moby/buildkit/client"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintSolveStatus(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Second)
+	ch := make(chan *client.SolveStatus, 3)
+	ch <- &client.SolveStatus{
+		Vertexes: []*client.Vertex{
+			{Digest: "a", Name: "step a", Started: &now},
+			{Digest: "b", Name: "step b", Started: &now},
+		},
+		Logs: []*client.VertexLog{
+			{Vertex: "b", Stream: client.StreamStdout, Data: []byte("b1\nb"), Timestamp: now.Add(2)},
+			{Vertex: "a", Stream: client.StreamStderr, Data: []byte("a1\n"), Timestamp: now.Add(1)},
+		},
+	}
+	ch <- &client.SolveStatus{
+		Vertexes: []*client.Vertex{
+			{Digest: "b", Name: "step b", Started: &now, Completed: &later, Timings: &client.VertexTimings{Exec: 800 * time.Millisecond, Commit: 10 * time.Millisecond}},
+		},
+		Statuses: []*client.VertexStatus{
+			{Vertex: "a", ID: "sha256:layer", Current: 2048, Total: 2048, Started: &now, Completed: &later},
+		},
+		Logs: []*client.VertexLog{
+			{Vertex: "b", Stream: client.StreamStdout, Data: []byte("2\n"), Timestamp: now.Add(3)},
+		},
+	}
+	ch <- &client.SolveStatus{
+		Vertexes: []*client.Vertex{
+			{Digest: "a", Name: "step a", Started: &now, Completed: &later, Error: "failed"},
+		},
+		Summary: &client.BuildSummary{Vertexes: 3, Cached: 1, Executed: 1, ExecTime: 1500 * time.Millisecond, TransferredBytes: 2048},
+	}
+	close(ch)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, PrintSolveStatus(context.TODO(), buf, ch))
+	require.Equal(t, `#1 step a
+#2 step b
+#1 a1
+#2 b1
+#2 b2
+#1 sha256:layer 2.048kB / 2.048kB done
+#2 DONE 1.0s (exec 0.8s)
+#1 ERROR: failed
+3 steps: 1 cached, 1 executed in 1.5s, 2.048kB transferred, 0B exported
+`, buf.String())
+}
@@ -0,0 +1,72 @@
+package progress
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Transfer writes the progress of copying the bytes of an item, e.g. a layer
+// of a pull or a push, as a status of the vertex of the context. The id names
+// the item and is shown as a sub-status of the vertex.
+type Transfer struct {
+	mu      sync.Mutex
+	pw      Writer
+	id      string
+	st      Status
+	limiter *rate.Limiter
+	done    bool
+}
+
+// NewTransfer starts the status id with the action. Total is the number of
+// bytes to copy or zero if it is unknown.
+func NewTransfer(ctx context.Context, id, action string, total int64) *Transfer {
+	pw, _, _ := FromContext(ctx)
+	now := time.Now()
+	t := &Transfer{
+		pw: pw,
+		id: id,
+		st: Status{
+			Action:  action,
+			Total:   int(total),
+			Started: &now,
+		},
+		limiter: rate.NewLimiter(rate.Every(100*time.Millisecond), 1),
+	}
+	pw.Write(id, t.st)
+	return t
+}
+
+// Update sets the number of bytes copied. The writes of the updates are rate
+// limited.
+func (t *Transfer) Update(current int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.done {
+		return
+	}
+	t.st.Current = int(current)
+	if t.limiter.Allow() {
+		t.pw.Write(t.id, t.st)
+	}
+}
+
+// Done completes the status. Only the first call has an effect.
+func (t *Transfer) Done(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.done {
+		return
+	}
+	t.done = true
+	now := time.Now()
+	t.st.Completed = &now
+	t.st.Action = "done"
+	if err != nil {
+		t.st.Action = "error"
+	}
+	t.pw.Write(t.id, t.st)
+	t.pw.Close()
+}
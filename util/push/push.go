@@ -0,0 +1,87 @@
+package push
+
+import (
+	"context"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/remotes"
+	"github.com/moby/buildkit/util/progress"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+var errNotCommitted = errors.New("push was not committed")
+
+// Handler returns a handler pushing the blobs of the descriptors from
+// provider. The bytes pushed for every blob are written to the progress
+// writer of the context with the digest of the blob as the id.
+func Handler(provider content.Provider, pusher remotes.Pusher) images.HandlerFunc {
+	return remotes.PushHandler(provider, &progressPusher{Pusher: pusher})
+}
+
+type progressPusher struct {
+	remotes.Pusher
+}
+
+func (p *progressPusher) Push(ctx context.Context, desc ocispec.Descriptor) (content.Writer, error) {
+	t := progress.NewTransfer(ctx, desc.Digest.String(), "pushing", desc.Size)
+	cw, err := p.Pusher.Push(ctx, desc)
+	if err != nil {
+		if errdefs.IsAlreadyExists(err) {
+			// the registry has the blob already
+			t.Update(desc.Size)
+			t.Done(nil)
+			return nil, err
+		}
+		t.Done(err)
+		return nil, err
+	}
+	pw := &progressWriter{Writer: cw, t: t}
+	// an interrupted push may be resumed from the offset
+	if st, err := cw.Status(); err == nil {
+		pw.offset = st.Offset
+	}
+	return pw, nil
+}
+
+// progressWriter reports the bytes written to the registry
+type progressWriter struct {
+	content.Writer
+	t      *progress.Transfer
+	offset int64
+}
+
+func (w *progressWriter) Write(dt []byte) (int, error) {
+	n, err := w.Writer.Write(dt)
+	w.offset += int64(n)
+	w.t.Update(w.offset)
+	return n, err
+}
+
+func (w *progressWriter) Truncate(size int64) error {
+	if err := w.Writer.Truncate(size); err != nil {
+		return err
+	}
+	w.offset = size
+	w.t.Update(w.offset)
+	return nil
+}
+
+func (w *progressWriter) Commit(ctx context.Context, size int64, expected digest.Digest, opts ...content.Opt) error {
+	err := w.Writer.Commit(ctx, size, expected, opts...)
+	if errdefs.IsAlreadyExists(err) {
+		w.t.Done(nil)
+	} else {
+		w.t.Done(err)
+	}
+	return err
+}
+
+// Close completes the status of a push that was not committed as failed
+func (w *progressWriter) Close() error {
+	w.t.Done(errNotCommitted)
+	return w.Writer.Close()
+}
@@ -0,0 +1,86 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/content/local"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/moby/buildkit/util/progress"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerProgress(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "pushprogress")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	src, err := local.NewStore(filepath.Join(tmpdir, "src"))
+	require.NoError(t, err)
+	dest, err := local.NewStore(filepath.Join(tmpdir, "dest"))
+	require.NoError(t, err)
+
+	blob := func(dt []byte) ocispec.Descriptor {
+		desc := ocispec.Descriptor{Digest: digest.FromBytes(dt), Size: int64(len(dt))}
+		require.NoError(t, content.WriteBlob(context.TODO(), src, desc.Digest.String(), bytes.NewReader(dt), desc.Size, desc.Digest))
+		return desc
+	}
+	pushed := blob([]byte("layer"))
+	existing := blob([]byte("existing layer"))
+
+	pr, ctx, cancelProgress := progress.NewContext(context.Background())
+	statuses := map[string]progress.Status{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			p, err := pr.Read(context.TODO())
+			if err != nil {
+				return
+			}
+			for _, p := range p {
+				statuses[p.ID] = p.Sys.(progress.Status)
+			}
+		}
+	}()
+
+	pusher := &testPusher{store: dest, existing: existing.Digest}
+	handler := Handler(src, pusher)
+	for _, desc := range []ocispec.Descriptor{pushed, existing} {
+		_, err := handler(ctx, desc)
+		require.NoError(t, err)
+	}
+	cancelProgress()
+	<-done
+
+	_, err = dest.Info(context.TODO(), pushed.Digest)
+	require.NoError(t, err)
+
+	for _, desc := range []ocispec.Descriptor{pushed, existing} {
+		st, ok := statuses[desc.Digest.String()]
+		require.True(t, ok)
+		require.Equal(t, "done", st.Action)
+		require.Equal(t, int(desc.Size), st.Current)
+		require.Equal(t, int(desc.Size), st.Total)
+		require.NotNil(t, st.Completed)
+	}
+}
+
+type testPusher struct {
+	store    content.Store
+	existing digest.Digest
+}
+
+func (p *testPusher) Push(ctx context.Context, desc ocispec.Descriptor) (content.Writer, error) {
+	if desc.Digest == p.existing {
+		return nil, errdefs.ErrAlreadyExists
+	}
+	return p.store.Writer(ctx, desc.Digest.String(), desc.Size, desc.Digest)
+}
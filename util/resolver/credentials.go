@@ -0,0 +1,39 @@
+package resolver
+
+import (
+	"bytes"
+	"encoding/json"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// credentialsNotFound is returned by the credential helpers if there are no
+// credentials stored for the host
+const credentialsNotFound = "credentials not found in native keychain"
+
+// CredentialHelper returns Credentials that are read from the
+// docker-credential-<name> helper binary
+func CredentialHelper(name string) Credentials {
+	return func(host string) (string, string, error) {
+		cmd := exec.Command("docker-credential-"+name, "get")
+		cmd.Stdin = strings.NewReader(host)
+		var stdout bytes.Buffer
+		cmd.Stdout = &stdout
+		if err := cmd.Run(); err != nil {
+			if strings.Contains(stdout.String(), credentialsNotFound) {
+				return "", "", nil
+			}
+			return "", "", errors.Wrapf(err, "failed to get credentials for %s from %s", host, name)
+		}
+		var creds struct {
+			Username string
+			Secret   string
+		}
+		if err := json.Unmarshal(stdout.Bytes(), &creds); err != nil {
+			return "", "", errors.Wrapf(err, "invalid credentials for %s from %s", host, name)
+		}
+		return creds.Username, creds.Secret, nil
+	}
+}
@@ -0,0 +1,76 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
+
+	digest "github.com/opencontainers/go-digest"
+)
+
+type rangeKey struct{}
+
+type byteRange struct {
+	dgst   digest.Digest
+	offset int64
+	size   int64
+}
+
+// WithRange makes the fetches of the blob dgst with ctx return only the size
+// bytes of the blob at offset
+func WithRange(ctx context.Context, dgst digest.Digest, offset, size int64) context.Context {
+	return context.WithValue(ctx, rangeKey{}, byteRange{dgst: dgst, offset: offset, size: size})
+}
+
+// rangeTransport sets the Range header of the blob requests that have a
+// range in their context. The responses of the registries that don't
+// support ranges are cut to the range.
+type rangeTransport struct {
+	rt http.RoundTripper
+}
+
+func withRangeTransport(c *http.Client) *http.Client {
+	rt := c.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	cc := *c
+	cc.Transport = &rangeTransport{rt: rt}
+	return &cc
+}
+
+func (t *rangeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	br, ok := req.Context().Value(rangeKey{}).(byteRange)
+	if !ok || req.Method != http.MethodGet {
+		return t.rt.RoundTrip(req)
+	}
+	value := fmt.Sprintf("bytes=%d-%d", br.offset, br.offset+br.size-1)
+	// the redirects of the blob requests keep the header of the request
+	if path.Base(req.URL.Path) != br.dgst.String() && req.Header.Get("Range") != value {
+		return t.rt.RoundTrip(req)
+	}
+	req2 := *req
+	req2.Header = make(http.Header, len(req.Header)+1)
+	for k, v := range req.Header {
+		req2.Header[k] = v
+	}
+	req2.Header.Set("Range", value)
+	resp, err := t.rt.RoundTrip(&req2)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	if _, err := io.CopyN(ioutil.Discard, resp.Body, br.offset); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	resp.Body = &limitedReadCloser{Reader: io.LimitReader(resp.Body, br.size), Closer: resp.Body}
+	return resp, nil
+}
+
+type limitedReadCloser struct {
+	io.Reader
+	io.Closer
+}
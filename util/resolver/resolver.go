@@ -0,0 +1,237 @@
+package resolver
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containerd/containerd/reference"
+	"github.com/containerd/containerd/remotes"
+	"github.com/containerd/containerd/remotes/docker"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// RegistryConfig defines how a registry host is accessed
+type RegistryConfig struct {
+	// Mirrors are tried in order before the host when resolving images
+	Mirrors []string `json:"mirrors,omitempty"`
+	// PlainHTTP uses http instead of https
+	PlainHTTP bool `json:"http,omitempty"`
+	// Insecure skips the verification of the TLS certificate
+	Insecure bool `json:"insecure,omitempty"`
+	// RootCAs are the paths of PEM encoded CA certificates that are trusted
+	// in addition to the system pool
+	RootCAs []string `json:"ca,omitempty"`
+	// CredentialHelper is the suffix of the docker-credential-<helper>
+	// binary that provides the credentials for the host
+	CredentialHelper string `json:"credentialHelper,omitempty"`
+}
+
+// Config is the registry configuration keyed by the registry host
+type Config map[string]RegistryConfig
+
+// LoadConfig reads a JSON encoded Config from path
+func LoadConfig(path string) (Config, error) {
+	dt, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read registry config %s", path)
+	}
+	var cfg Config
+	if err := json.Unmarshal(dt, &cfg); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse registry config %s", path)
+	}
+	return cfg, nil
+}
+
+// Credentials returns the username and the secret for a registry host
+type Credentials func(host string) (string, string, error)
+
+// Registries holds the settings of the configured registries that are shared
+// by all resolvers
+type Registries struct {
+	cfg     Config
+	options map[string]docker.ResolverOptions
+}
+
+// NewRegistries validates cfg and loads the TLS settings of the registries
+func NewRegistries(cfg Config) (*Registries, error) {
+	r := &Registries{
+		cfg:     cfg,
+		options: map[string]docker.ResolverOptions{},
+	}
+	for host, c := range cfg {
+		opt, err := resolverOptions(host, c)
+		if err != nil {
+			return nil, err
+		}
+		r.options[host] = opt
+	}
+	return r, nil
+}
+
+// Resolver returns a resolver that accesses the registries with the
+// configured settings. Credentials from creds are used before the
+// credential helper of the host. Registries that are not configured, or a
+// nil Registries, use the defaults.
+func (r *Registries) Resolver(creds Credentials) remotes.Resolver {
+	return &resolver{
+		registries: r,
+		creds:      creds,
+		hosts:      map[string]remotes.Resolver{},
+	}
+}
+
+func (r *Registries) config(host string) RegistryConfig {
+	if r == nil {
+		return RegistryConfig{}
+	}
+	return r.cfg[host]
+}
+
+func (r *Registries) resolverOptions(host string) docker.ResolverOptions {
+	if r != nil {
+		if opt, ok := r.options[host]; ok {
+			return opt
+		}
+	}
+	return docker.ResolverOptions{
+		Client: withRangeTransport(http.DefaultClient),
+	}
+}
+
+type resolver struct {
+	registries *Registries
+	creds      Credentials
+	mu         sync.Mutex
+	hosts      map[string]remotes.Resolver
+}
+
+func (r *resolver) Resolve(ctx context.Context, ref string) (string, ocispec.Descriptor, error) {
+	spec, err := reference.Parse(ref)
+	if err != nil {
+		return "", ocispec.Descriptor{}, errors.WithStack(err)
+	}
+	host := spec.Hostname()
+	for _, m := range r.registries.config(host).Mirrors {
+		mirrorRef := mirrorReference(spec, m)
+		name, desc, err := r.forHost(m).Resolve(ctx, mirrorRef)
+		if err == nil {
+			return name, desc, nil
+		}
+		logrus.Debugf("failed to resolve %s from mirror %s: %v", ref, m, err)
+	}
+	return r.forHost(host).Resolve(ctx, ref)
+}
+
+func (r *resolver) Fetcher(ctx context.Context, ref string) (remotes.Fetcher, error) {
+	hr, err := r.forRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	return hr.Fetcher(ctx, ref)
+}
+
+func (r *resolver) Pusher(ctx context.Context, ref string) (remotes.Pusher, error) {
+	hr, err := r.forRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	return hr.Pusher(ctx, ref)
+}
+
+func (r *resolver) forRef(ref string) (remotes.Resolver, error) {
+	spec, err := reference.Parse(ref)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return r.forHost(spec.Hostname()), nil
+}
+
+func (r *resolver) forHost(host string) remotes.Resolver {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if hr, ok := r.hosts[host]; ok {
+		return hr
+	}
+	opt := r.registries.resolverOptions(host)
+	if r.creds != nil {
+		opt.Credentials = chainCredentials(r.creds, opt.Credentials)
+	}
+	hr := docker.NewResolver(opt)
+	r.hosts[host] = hr
+	return hr
+}
+
+// chainCredentials returns the credentials from first, or from second if
+// first has none
+func chainCredentials(first, second Credentials) Credentials {
+	if second == nil {
+		return first
+	}
+	return func(host string) (string, string, error) {
+		user, secret, err := first(host)
+		if err == nil && (user != "" || secret != "") {
+			return user, secret, nil
+		}
+		return second(host)
+	}
+}
+
+func resolverOptions(host string, c RegistryConfig) (docker.ResolverOptions, error) {
+	opt := docker.ResolverOptions{
+		Client:    http.DefaultClient,
+		PlainHTTP: c.PlainHTTP,
+	}
+	if c.Insecure || len(c.RootCAs) > 0 {
+		tc := &tls.Config{InsecureSkipVerify: c.Insecure}
+		if len(c.RootCAs) > 0 {
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				pool = x509.NewCertPool()
+			}
+			for _, p := range c.RootCAs {
+				dt, err := ioutil.ReadFile(p)
+				if err != nil {
+					return opt, errors.Wrapf(err, "failed to read CA for %s", host)
+				}
+				if !pool.AppendCertsFromPEM(dt) {
+					return opt, errors.Errorf("invalid CA %s for %s", p, host)
+				}
+			}
+			tc.RootCAs = pool
+		}
+		opt.Client = &http.Client{
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+				DialContext: (&net.Dialer{
+					Timeout:   30 * time.Second,
+					KeepAlive: 30 * time.Second,
+				}).DialContext,
+				TLSClientConfig:     tc,
+				TLSHandshakeTimeout: 10 * time.Second,
+				IdleConnTimeout:     90 * time.Second,
+			},
+		}
+	}
+	if c.CredentialHelper != "" {
+		opt.Credentials = CredentialHelper(c.CredentialHelper)
+	}
+	opt.Client = withRangeTransport(opt.Client)
+	return opt, nil
+}
+
+// mirrorReference returns the reference of the image in spec on the mirror
+// host
+func mirrorReference(spec reference.Spec, mirror string) string {
+	spec.Locator = strings.TrimSuffix(mirror, "/") + strings.TrimPrefix(spec.Locator, spec.Hostname())
+	return spec.String()
+}
@@ -0,0 +1,96 @@
+package resolver
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/containerd/containerd/reference"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMirrorReference(t *testing.T) {
+	spec, err := reference.Parse("docker.io/library/alpine:latest")
+	require.NoError(t, err)
+	require.Equal(t, "mirror.example.com/library/alpine:latest", mirrorReference(spec, "mirror.example.com/"))
+
+	spec, err = reference.Parse("docker.io/library/alpine@sha256:0000000000000000000000000000000000000000000000000000000000000000")
+	require.NoError(t, err)
+	require.Equal(t, "mirror.example.com:5000/library/alpine@sha256:0000000000000000000000000000000000000000000000000000000000000000", mirrorReference(spec, "mirror.example.com:5000"))
+}
+
+func TestLoadConfig(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "resolver")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	p := filepath.Join(tmpdir, "registries.json")
+	err = ioutil.WriteFile(p, []byte(`{"docker.io": {"mirrors": ["mirror.example.com"]}, "localhost:5000": {"http": true}}`), 0600)
+	require.NoError(t, err)
+
+	cfg, err := LoadConfig(p)
+	require.NoError(t, err)
+	require.Equal(t, []string{"mirror.example.com"}, cfg["docker.io"].Mirrors)
+	require.True(t, cfg["localhost:5000"].PlainHTTP)
+
+	_, err = NewRegistries(cfg)
+	require.NoError(t, err)
+
+	ca := filepath.Join(tmpdir, "ca.pem")
+	err = ioutil.WriteFile(ca, []byte("invalid"), 0600)
+	require.NoError(t, err)
+
+	_, err = NewRegistries(Config{"registry.example.com": {RootCAs: []string{ca}}})
+	require.Error(t, err)
+
+	_, err = NewRegistries(Config{"registry.example.com": {RootCAs: []string{filepath.Join(tmpdir, "missing.pem")}}})
+	require.Error(t, err)
+}
+
+func TestRangeTransport(t *testing.T) {
+	dt := []byte("0123456789")
+	dgst := digest.FromBytes(dt)
+	var ranges []string
+	ignoreRange := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		if ignoreRange {
+			w.Write(dt)
+			return
+		}
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(dt))
+	}))
+	defer srv.Close()
+
+	c := withRangeTransport(http.DefaultClient)
+	get := func(ctx context.Context, p string) string {
+		req, err := http.NewRequest(http.MethodGet, srv.URL+p, nil)
+		require.NoError(t, err)
+		resp, err := c.Do(req.WithContext(ctx))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		out, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(out)
+	}
+
+	ctx := WithRange(context.TODO(), dgst, 2, 3)
+	require.Equal(t, "234", get(ctx, "/v2/test/blobs/"+dgst.String()))
+	require.Equal(t, []string{"bytes=2-4"}, ranges)
+
+	// the registries that don't support ranges return the whole blob
+	ignoreRange = true
+	require.Equal(t, "234", get(ctx, "/v2/test/blobs/"+dgst.String()))
+
+	// other requests and blobs are not changed
+	require.Equal(t, "0123456789", get(ctx, "/token"))
+	require.Equal(t, "0123456789", get(context.TODO(), "/v2/test/blobs/"+dgst.String()))
+	require.Equal(t, []string{"bytes=2-4", "bytes=2-4", "", ""}, ranges)
+}
@@ -0,0 +1,20 @@
+package system
+
+const (
+	// DefaultPathEnvUnix is the default PATH of the processes of linux and
+	// other unix containers
+	DefaultPathEnvUnix = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+	// DefaultPathEnvWindows is the default PATH of the processes of windows
+	// containers
+	DefaultPathEnvWindows = `c:\Windows\System32;c:\Windows`
+)
+
+// DefaultPathEnvForOS returns the default PATH of the processes of the
+// containers of os, eg. for the platform of an image instead of the one of
+// the daemon
+func DefaultPathEnvForOS(os string) string {
+	if os == "windows" {
+		return DefaultPathEnvWindows
+	}
+	return DefaultPathEnvUnix
+}
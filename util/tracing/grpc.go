@@ -0,0 +1,54 @@
+package tracing
+
+import (
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// traceIDHeader is the grpc metadata key of the trace ID of a request
+const traceIDHeader = "buildkit-trace-id"
+
+// UnaryClientInterceptor sends the trace ID of the context of the call to the
+// daemon
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoingContext(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor sends the trace ID of the context of the stream to
+// the daemon
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoingContext(ctx), desc, cc, method, opts...)
+	}
+}
+
+// FromIncomingContext returns a context with the trace ID sent by the client
+// of a request. The context is returned unchanged if there is no trace ID.
+func FromIncomingContext(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	if v := md[traceIDHeader]; len(v) > 0 && v[0] != "" {
+		return ContextWithTraceID(ctx, v[0])
+	}
+	return ctx
+}
+
+func outgoingContext(ctx context.Context) context.Context {
+	id := TraceID(ctx)
+	if id == "" {
+		return ctx
+	}
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	md[traceIDHeader] = []string{id}
+	return metadata.NewOutgoingContext(ctx, md)
+}
@@ -0,0 +1,142 @@
+// Package tracing records the spans of the steps of a build so that slow
+// builds can be profiled. All the spans of a build share the trace ID that
+// is set by the client or generated for the first span. The spans are shown
+// on the /debug/requests page of the daemon and their durations are logged
+// at the debug level.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/moby/buildkit/identity"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/trace"
+)
+
+type contextKeyT string
+
+var (
+	spanKey    = contextKeyT("buildkit/util/tracing.span")
+	traceIDKey = contextKeyT("buildkit/util/tracing.traceid")
+)
+
+// Span is a timed step of a trace
+type Span struct {
+	mu       sync.Mutex
+	tr       trace.Trace
+	traceID  string
+	id       string
+	parentID string
+	name     string
+	started  time.Time
+	finished bool
+}
+
+// StartSpan starts a span of the trace of the context. The family groups the
+// spans of the same kind and the title describes the step. The returned
+// context carries the span as the parent of the spans started from it.
+func StartSpan(ctx context.Context, family, title string) (*Span, context.Context) {
+	s := &Span{
+		tr:      trace.New(family, title),
+		traceID: TraceID(ctx),
+		id:      identity.NewID(),
+		name:    family + " " + title,
+		started: time.Now(),
+	}
+	if parent := SpanFromContext(ctx); parent != nil {
+		s.parentID = parent.id
+	}
+	if s.traceID == "" {
+		s.traceID = identity.NewID()
+	}
+	s.tr.LazyPrintf("trace %s span %s parent %s", s.traceID, s.id, s.parentID)
+	ctx = context.WithValue(ctx, traceIDKey, s.traceID)
+	ctx = context.WithValue(ctx, spanKey, s)
+	return s, ctx
+}
+
+// SpanFromContext returns the current span of the context or nil
+func SpanFromContext(ctx context.Context) *Span {
+	switch v := ctx.Value(spanKey).(type) {
+	case *Span:
+		return v
+	case func() *Span:
+		return v()
+	default:
+		return nil
+	}
+}
+
+// ContextWithSpanFunc sets fn to return the parent of the spans started from
+// the context, for a context that outlives the span it was created for
+func ContextWithSpanFunc(ctx context.Context, fn func() *Span) context.Context {
+	return context.WithValue(ctx, spanKey, fn)
+}
+
+// ContextWithTraceID sets the trace ID of the spans started from the context
+func ContextWithTraceID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, traceIDKey, id)
+}
+
+// TraceID returns the trace ID of the context or an empty string. The trace
+// ID of the current span is used if the context has none set.
+func TraceID(ctx context.Context) string {
+	if id, ok := ctx.Value(traceIDKey).(string); ok {
+		return id
+	}
+	if s := SpanFromContext(ctx); s != nil {
+		return s.traceID
+	}
+	return ""
+}
+
+// TraceID returns the ID of the trace of the span
+func (s *Span) TraceID() string {
+	return s.traceID
+}
+
+// Printf adds an event to the span
+func (s *Span) Printf(format string, a ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.finished {
+		return
+	}
+	s.tr.LazyPrintf(format, a...)
+}
+
+// Finish completes the span. The span is marked as failed if err is set.
+// Only the first call has an effect.
+func (s *Span) Finish(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.finished {
+		return
+	}
+	s.finished = true
+	if err != nil {
+		s.tr.LazyPrintf("error: %v", err)
+		s.tr.SetError()
+	}
+	s.tr.Finish()
+
+	fields := logrus.Fields{
+		"trace":    s.traceID,
+		"span":     s.id,
+		"duration": time.Since(s.started),
+	}
+	if s.parentID != "" {
+		fields["parent"] = s.parentID
+	}
+	if err != nil {
+		fields["error"] = err
+	}
+	logrus.WithFields(fields).Debugf("%s", s)
+}
+
+func (s *Span) String() string {
+	return fmt.Sprintf("span %s", s.name)
+}
@@ -0,0 +1,79 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	netcontext "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestSpans(t *testing.T) {
+	root, ctx := StartSpan(context.Background(), "test", "root")
+	require.NotEqual(t, "", root.TraceID())
+	require.Equal(t, root.TraceID(), TraceID(ctx))
+	require.Equal(t, root, SpanFromContext(ctx))
+	require.Equal(t, "", root.parentID)
+
+	child, childCtx := StartSpan(ctx, "test", "child")
+	require.Equal(t, root.TraceID(), child.TraceID())
+	require.Equal(t, root.id, child.parentID)
+	require.Equal(t, child, SpanFromContext(childCtx))
+
+	child.Printf("event %d", 1)
+	child.Finish(errors.New("failed"))
+	// finished spans ignore the events and the errors
+	child.Printf("event %d", 2)
+	child.Finish(nil)
+	root.Finish(nil)
+
+	ctx = ContextWithTraceID(context.Background(), "foo")
+	span, _ := StartSpan(ctx, "test", "span")
+	require.Equal(t, "foo", span.TraceID())
+	span.Finish(nil)
+
+	require.Nil(t, SpanFromContext(context.Background()))
+	require.Equal(t, "", TraceID(context.Background()))
+}
+
+func TestGRPCTraceID(t *testing.T) {
+	var md metadata.MD
+	invoker := func(ctx netcontext.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+
+	err := UnaryClientInterceptor()(context.Background(), "/test", nil, nil, nil, invoker)
+	require.NoError(t, err)
+	require.Nil(t, md[traceIDHeader])
+
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("foo", "bar"))
+	ctx = ContextWithTraceID(ctx, "trace1")
+	err = UnaryClientInterceptor()(ctx, "/test", nil, nil, nil, invoker)
+	require.NoError(t, err)
+	require.Equal(t, []string{"trace1"}, md[traceIDHeader])
+	require.Equal(t, []string{"bar"}, md["foo"])
+
+	// the daemon receives the metadata sent by the client
+	ctx = FromIncomingContext(metadata.NewIncomingContext(context.Background(), md))
+	require.Equal(t, "trace1", TraceID(ctx))
+
+	ctx = FromIncomingContext(context.Background())
+	require.Equal(t, "", TraceID(ctx))
+}
+
+func TestSpanFunc(t *testing.T) {
+	ctx := ContextWithSpanFunc(context.Background(), func() *Span { return nil })
+	require.Nil(t, SpanFromContext(ctx))
+	require.Equal(t, "", TraceID(ctx))
+
+	root, _ := StartSpan(context.Background(), "test", "root")
+	ctx = ContextWithSpanFunc(context.Background(), func() *Span { return root })
+	require.Equal(t, root.TraceID(), TraceID(ctx))
+	child, _ := StartSpan(ctx, "test", "child")
+	require.Equal(t, root.TraceID(), child.TraceID())
+	require.Equal(t, root.id, child.parentID)
+}
@@ -0,0 +1,72 @@
+package worker
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/containerd/containerd/platforms"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// BinfmtMiscDir is the directory of the binfmt_misc handlers of the host
+const BinfmtMiscDir = "/proc/sys/fs/binfmt_misc"
+
+// qemuArchs are the architectures of the qemu user emulators that are
+// registered as binfmt_misc handlers named qemu-<name>
+var qemuArchs = map[string]ocispec.Platform{
+	"x86_64":   {OS: "linux", Architecture: "amd64"},
+	"i386":     {OS: "linux", Architecture: "386"},
+	"aarch64":  {OS: "linux", Architecture: "arm64"},
+	"arm":      {OS: "linux", Architecture: "arm", Variant: "v7"},
+	"ppc64le":  {OS: "linux", Architecture: "ppc64le"},
+	"s390x":    {OS: "linux", Architecture: "s390x"},
+	"riscv64":  {OS: "linux", Architecture: "riscv64"},
+	"mips64el": {OS: "linux", Architecture: "mips64le"},
+	"mips64":   {OS: "linux", Architecture: "mips64"},
+}
+
+// EmulatorAvailable returns true if an enabled qemu emulator for arch is
+// registered in the binfmt_misc directory dir
+func EmulatorAvailable(dir, arch string) bool {
+	for name, p := range qemuArchs {
+		if p.Architecture == arch {
+			return handlerEnabled(filepath.Join(dir, "qemu-"+name))
+		}
+	}
+	return false
+}
+
+// EmulatedPlatforms returns the platforms of the enabled qemu emulators
+// registered in the binfmt_misc directory dir, other than the platform of
+// the daemon. Handlers of unknown architectures are ignored.
+func EmulatedPlatforms(dir string) []ocispec.Platform {
+	def := platforms.Default()
+	var out []ocispec.Platform
+	for name, p := range qemuArchs {
+		if def.OS != p.OS || def.Architecture == p.Architecture {
+			continue
+		}
+		if handlerEnabled(filepath.Join(dir, "qemu-"+name)) {
+			out = append(out, p)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return platforms.Format(out[i]) < platforms.Format(out[j])
+	})
+	return out
+}
+
+// handlerEnabled returns true if the binfmt_misc handler file p exists and
+// its status line is enabled
+func handlerEnabled(p string) bool {
+	f, err := os.Open(p)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	return s.Scan() && strings.TrimSpace(s.Text()) == "enabled"
+}
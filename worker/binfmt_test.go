@@ -0,0 +1,38 @@
+package worker
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containerd/containerd/platforms"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmulatorAvailable(t *testing.T) {
+	dir, err := ioutil.TempDir("", "buildkit-binfmt")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "qemu-aarch64"), []byte("enabled\ninterpreter /usr/bin/qemu-aarch64\n"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "qemu-ppc64le"), []byte("enabled"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "qemu-riscv64"), []byte("disabled"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "qemu-sparc"), []byte("enabled"), 0644))
+
+	require.True(t, EmulatorAvailable(dir, "arm64"))
+	require.True(t, EmulatorAvailable(dir, "ppc64le"))
+	require.False(t, EmulatorAvailable(dir, "riscv64"))
+	require.False(t, EmulatorAvailable(dir, "s390x"))
+	require.False(t, EmulatorAvailable(dir, "amd64"))
+
+	var expected []ocispec.Platform
+	for _, p := range []ocispec.Platform{{OS: "linux", Architecture: "arm64"}, {OS: "linux", Architecture: "ppc64le"}} {
+		if def := platforms.Default(); def.OS == p.OS && def.Architecture != p.Architecture {
+			expected = append(expected, p)
+		}
+	}
+	require.Equal(t, expected, EmulatedPlatforms(dir))
+	require.Nil(t, EmulatedPlatforms(filepath.Join(dir, "missing")))
+}
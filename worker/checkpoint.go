@@ -0,0 +1,53 @@
+package worker
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+)
+
+// Checkpoint saves the state of a long running process periodically
+type Checkpoint struct {
+	// Interval is the time between the checkpoints
+	Interval time.Duration
+	// Save is called while the process is paused
+	Save func(ctx context.Context) error
+}
+
+// StartCheckpoints calls the Save function of cp every interval until the
+// returned function is called. The process is paused with pause before and
+// resumed with resume after each save. Failed checkpoints are logged and
+// don't stop the process.
+func StartCheckpoints(ctx context.Context, cp *Checkpoint, pause, resume func(context.Context) error) func() {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		t := time.NewTicker(cp.Interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+			}
+			if err := pause(ctx); err != nil {
+				logrus.Debugf("failed to pause process for checkpoint: %v", err)
+				continue
+			}
+			err := cp.Save(ctx)
+			// the process is resumed even if the exec has been cancelled
+			if err := resume(context.TODO()); err != nil {
+				logrus.Errorf("failed to resume process after checkpoint: %v", err)
+			}
+			if err != nil {
+				logrus.Errorf("failed to save checkpoint: %v", err)
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
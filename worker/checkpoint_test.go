@@ -0,0 +1,61 @@
+package worker
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestStartCheckpoints(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	record := func(name string, err error) func(context.Context) error {
+		return func(context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			calls = append(calls, name)
+			return err
+		}
+	}
+
+	stop := StartCheckpoints(context.TODO(), &Checkpoint{
+		Interval: 10 * time.Millisecond,
+		Save:     record("save", errors.New("failed")),
+	}, record("pause", nil), record("resume", nil))
+	time.Sleep(35 * time.Millisecond)
+	stop()
+
+	mu.Lock()
+	n := len(calls)
+	require.True(t, n >= 3)
+	require.Equal(t, 0, n%3)
+	for i := 0; i < n; i += 3 {
+		// a failed save is resumed and doesn't stop the checkpoints
+		require.Equal(t, []string{"pause", "save", "resume"}, calls[i:i+3])
+	}
+	mu.Unlock()
+
+	// no checkpoints after stop
+	time.Sleep(20 * time.Millisecond)
+	mu.Lock()
+	require.Equal(t, n, len(calls))
+	mu.Unlock()
+
+	// nothing is saved if the process can't be paused
+	calls = nil
+	stop = StartCheckpoints(context.TODO(), &Checkpoint{
+		Interval: 10 * time.Millisecond,
+		Save:     record("save", nil),
+	}, record("pause", errors.New("not running")), record("resume", nil))
+	time.Sleep(25 * time.Millisecond)
+	stop()
+	mu.Lock()
+	for _, c := range calls {
+		require.Equal(t, "pause", c)
+	}
+	mu.Unlock()
+}
@@ -1,11 +1,14 @@
 package containerdworker
 
 import (
+	"bytes"
 	"io"
+	"os"
 
 	"github.com/containerd/containerd"
 	"github.com/moby/buildkit/cache"
 	"github.com/moby/buildkit/identity"
+	"github.com/moby/buildkit/util/tracing"
 	"github.com/moby/buildkit/worker"
 	"github.com/moby/buildkit/worker/oci"
 	"github.com/pkg/errors"
@@ -22,20 +25,39 @@ func New(client *containerd.Client) worker.Worker {
 	}
 }
 
-func (w containerdWorker) Exec(ctx context.Context, meta worker.Meta, root cache.Mountable, mounts []worker.Mount, stdout, stderr io.WriteCloser) error {
+func (w containerdWorker) Identity() string {
+	host, _ := os.Hostname()
+	return "containerd@" + host
+}
+
+func (w containerdWorker) Exec(ctx context.Context, meta worker.Meta, root cache.Mountable, mounts []worker.Mount, stdin io.ReadCloser, stdout, stderr io.WriteCloser) (retErr error) {
 	id := identity.NewID()
 
-	spec, cleanup, err := oci.GenerateSpec(ctx, meta, mounts)
+	span, ctx := tracing.StartSpan(ctx, "exec", "containerd "+id)
+	defer func() {
+		span.Finish(retErr)
+	}()
+	span.Printf("args %v", meta.Args)
+
+	spec, cleanup, err := oci.GenerateSpec(ctx, meta, mounts, id)
 	if err != nil {
 		return err
 	}
 	defer cleanup()
 
-	rootMounts, err := root.Mount(ctx, false)
+	rootMounts, err := root.Mount(ctx, meta.ReadonlyRootFS)
 	if err != nil {
 		return err
 	}
 
+	if err := oci.SetUser(spec, rootMounts, meta.User); err != nil {
+		return err
+	}
+
+	if meta.ReadonlyRootFS && spec.Root != nil {
+		spec.Root.Readonly = true
+	}
+
 	container, err := w.client.NewContainer(ctx, id,
 		containerd.WithSpec(spec),
 	)
@@ -44,7 +66,12 @@ func (w containerdWorker) Exec(ctx context.Context, meta worker.Meta, root cache
 	}
 	defer container.Delete(ctx)
 
-	task, err := container.NewTask(ctx, containerd.Stdio, containerd.WithRootFS(rootMounts))
+	var in io.Reader = stdin
+	if stdin == nil {
+		in = bytes.NewReader(nil)
+	}
+
+	task, err := container.NewTask(ctx, containerd.NewIO(in, stdout, stderr), containerd.WithRootFS(rootMounts))
 	if err != nil {
 		return err
 	}
@@ -57,14 +84,25 @@ func (w containerdWorker) Exec(ctx context.Context, meta worker.Meta, root cache
 	if err := task.Start(ctx); err != nil {
 		return err
 	}
+	span.Printf("running")
+
+	if meta.Checkpoint != nil {
+		stop := worker.StartCheckpoints(ctx, meta.Checkpoint, func(ctx context.Context) error {
+			return task.Pause(ctx)
+		}, func(ctx context.Context) error {
+			return task.Resume(ctx)
+		})
+		defer stop()
+	}
 
 	statusCh, err := task.Wait(ctx)
 	if err != nil {
 		return err
 	}
 	status := <-statusCh
+	span.Printf("exited with status %d", status.ExitCode())
 	if status.ExitCode() != 0 {
-		return errors.Errorf("process returned non-zero exit code: %d", status.ExitCode())
+		return errors.WithStack(&worker.ExitError{ExitCode: status.ExitCode()})
 	}
 
 	return nil
@@ -0,0 +1,262 @@
+package worker
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/containerd/containerd/filters"
+	"github.com/containerd/containerd/platforms"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+const (
+	// LabelExecutor is the label with the name of the executor of a worker
+	LabelExecutor = "org.mobyproject.buildkit.worker.executor"
+	// LabelSnapshotter is the label with the name of the snapshotter used
+	// for the root filesystems of a worker
+	LabelSnapshotter = "org.mobyproject.buildkit.worker.snapshotter"
+	// LabelHostname is the label with the hostname of a worker
+	LabelHostname = "org.mobyproject.buildkit.worker.hostname"
+	// LabelRemote is the label with the address of the daemon of a remote
+	// worker
+	LabelRemote = "org.mobyproject.buildkit.worker.remote"
+)
+
+// Info describes a worker registered in a Controller
+type Info struct {
+	ID     string
+	Labels map[string]string
+	// Platforms are the platforms the worker runs processes for natively.
+	// The platform of the daemon is used if empty.
+	Platforms []ocispec.Platform
+	// EmulatedPlatforms are the other platforms the worker runs processes
+	// for with the registered binfmt_misc emulators
+	EmulatedPlatforms []ocispec.Platform
+}
+
+func (i Info) platforms() []ocispec.Platform {
+	if len(i.Platforms) == 0 {
+		return []ocispec.Platform{platforms.Default()}
+	}
+	return i.Platforms
+}
+
+// Supports returns true if the worker runs processes for platform p, and if
+// they are emulated
+func (i Info) Supports(p ocispec.Platform) (emulated bool, ok bool) {
+	p = platforms.Normalize(p)
+	for _, wp := range i.platforms() {
+		if matchPlatform(p, platforms.Normalize(wp)) {
+			return false, true
+		}
+	}
+	for _, wp := range i.EmulatedPlatforms {
+		if matchPlatform(p, platforms.Normalize(wp)) {
+			return true, true
+		}
+	}
+	return false, false
+}
+
+// Field implements filters.Adaptor. The filters can match the id, the labels
+// and the platforms of the worker. The platforms are formatted and separated
+// by commas, e.g. platforms~=linux/arm64.
+func (i Info) Field(fieldpath []string) (string, bool) {
+	if len(fieldpath) == 0 {
+		return "", false
+	}
+	switch fieldpath[0] {
+	case "id":
+		return i.ID, i.ID != ""
+	case "labels":
+		if len(fieldpath) < 2 {
+			return "", false
+		}
+		v, ok := i.Labels[strings.Join(fieldpath[1:], ".")]
+		return v, ok
+	case "platforms":
+		ps := i.platforms()
+		formatted := make([]string, 0, len(ps))
+		for _, p := range ps {
+			formatted = append(formatted, platforms.Format(platforms.Normalize(p)))
+		}
+		return strings.Join(formatted, ","), true
+	}
+	return "", false
+}
+
+type workerInfo struct {
+	Worker
+	info Info
+}
+
+// Controller holds the workers of the daemon. The first added worker is the
+// default worker.
+type Controller struct {
+	mu      sync.RWMutex
+	workers []workerInfo
+	// next is the turn of the worker that Resolve picks from several
+	// preferred workers
+	next    int
+	closers []func() error
+}
+
+// OnClose registers fn to be called by Close, eg. to stop the watch of a
+// remote worker
+func (c *Controller) OnClose(fn func() error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closers = append(c.closers, fn)
+}
+
+// Close calls the functions registered with OnClose in the reverse order
+// and returns the first error
+func (c *Controller) Close() error {
+	c.mu.Lock()
+	closers := c.closers
+	c.closers = nil
+	c.mu.Unlock()
+	var err error
+	for i := len(closers) - 1; i >= 0; i-- {
+		if cerr := closers[i](); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// Add registers a worker. The IDs of the workers must be unique.
+func (c *Controller) Add(w Worker, info Info) error {
+	if info.ID == "" {
+		return errors.Errorf("worker without an id")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, wi := range c.workers {
+		if wi.info.ID == info.ID {
+			return errors.Errorf("worker %s already exists", info.ID)
+		}
+	}
+	c.workers = append(c.workers, workerInfo{Worker: w, info: info})
+	return nil
+}
+
+// Remove unregisters the worker with the id
+func (c *Controller) Remove(id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, wi := range c.workers {
+		if wi.info.ID == id {
+			c.workers = append(c.workers[:i], c.workers[i+1:]...)
+			return nil
+		}
+	}
+	return errors.Errorf("worker %s not found", id)
+}
+
+// List returns the workers matching all the filters in the order they were
+// added
+func (c *Controller) List(filterStrings ...string) ([]Info, error) {
+	filter, err := parseFilters(filterStrings)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var out []Info
+	for _, wi := range c.workers {
+		if filter.Match(wi.info) {
+			out = append(out, wi.info)
+		}
+	}
+	return out, nil
+}
+
+// Get returns the worker with the id
+func (c *Controller) Get(id string) (Worker, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, wi := range c.workers {
+		if wi.info.ID == id {
+			return wi.Worker, nil
+		}
+	}
+	return nil, errors.Errorf("worker %s not found", id)
+}
+
+// Default returns the first added worker
+func (c *Controller) Default() (Worker, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if len(c.workers) == 0 {
+		return nil, errors.Errorf("no workers registered")
+	}
+	return c.workers[0].Worker, nil
+}
+
+// Resolve returns the worker for running a process for platform p that
+// matches all the filters. The workers running p natively are preferred, then
+// the workers emulating p, otherwise the matching workers are used and the
+// platform needs to be emulated. The processes are spread over the preferred
+// workers in turn. A nil p is the platform of the daemon.
+func (c *Controller) Resolve(p *ocispec.Platform, filterStrings ...string) (Worker, Info, error) {
+	filter, err := parseFilters(filterStrings)
+	if err != nil {
+		return nil, Info{}, err
+	}
+	spec := platforms.Default()
+	if p != nil {
+		spec = *p
+	}
+	spec = platforms.Normalize(spec)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var native, emulated, matched []workerInfo
+	for _, wi := range c.workers {
+		if !filter.Match(wi.info) {
+			continue
+		}
+		matched = append(matched, wi)
+		if emu, ok := wi.info.Supports(spec); ok && !emu {
+			native = append(native, wi)
+		} else if ok {
+			emulated = append(emulated, wi)
+		}
+	}
+	candidates := native
+	if len(candidates) == 0 {
+		candidates = emulated
+	}
+	if len(candidates) == 0 {
+		candidates = matched
+	}
+	if len(candidates) == 0 {
+		return nil, Info{}, errors.Errorf("no worker matches %v", filterStrings)
+	}
+	wi := candidates[0]
+	if len(candidates) > 1 {
+		wi = candidates[c.next%len(candidates)]
+		c.next++
+	}
+	return wi.Worker, wi.info, nil
+}
+
+// parseFilters returns a filter matching all the filter strings. Unlike
+// filters.ParseAll the filters are not alternatives.
+func parseFilters(filterStrings []string) (filters.Filter, error) {
+	all := make(filters.All, 0, len(filterStrings))
+	for _, s := range filterStrings {
+		f, err := filters.Parse(s)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid worker filter %q", s)
+		}
+		all = append(all, f)
+	}
+	return all, nil
+}
+
+func matchPlatform(p, wp ocispec.Platform) bool {
+	return p.OS == wp.OS && p.Architecture == wp.Architecture && p.Variant == wp.Variant
+}
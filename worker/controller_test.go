@@ -0,0 +1,157 @@
+package worker
+
+import (
+	"io"
+	"testing"
+
+	"github.com/containerd/containerd/platforms"
+	"github.com/moby/buildkit/cache"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+type testWorker struct {
+	name string
+}
+
+func (w *testWorker) Exec(ctx context.Context, meta Meta, rootfs cache.Mountable, mounts []Mount, stdin io.ReadCloser, stdout, stderr io.WriteCloser) error {
+	return nil
+}
+
+func TestControllerList(t *testing.T) {
+	c := &Controller{}
+	_, err := c.Default()
+	require.Error(t, err)
+
+	runc, ctd := &testWorker{"runc"}, &testWorker{"containerd"}
+	require.NoError(t, c.Add(runc, Info{ID: "w1", Labels: map[string]string{LabelExecutor: "runc"}}))
+	require.NoError(t, c.Add(ctd, Info{ID: "w2", Labels: map[string]string{LabelExecutor: "containerd", "gpu": "true"}}))
+	require.Error(t, c.Add(ctd, Info{ID: "w2"}))
+	require.Error(t, c.Add(ctd, Info{}))
+
+	w, err := c.Default()
+	require.NoError(t, err)
+	require.Equal(t, runc, w)
+
+	w, err = c.Get("w2")
+	require.NoError(t, err)
+	require.Equal(t, ctd, w)
+	_, err = c.Get("w3")
+	require.Error(t, err)
+
+	infos, err := c.List()
+	require.NoError(t, err)
+	require.Equal(t, 2, len(infos))
+	require.Equal(t, "w1", infos[0].ID)
+
+	infos, err = c.List(`labels."org.mobyproject.buildkit.worker.executor"==containerd`)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(infos))
+	require.Equal(t, "w2", infos[0].ID)
+
+	// the filters must all match
+	infos, err = c.List("labels.gpu==true", "id==w1")
+	require.NoError(t, err)
+	require.Equal(t, 0, len(infos))
+
+	_, err = c.List("labels.gpu==")
+	require.Error(t, err)
+
+	require.NoError(t, c.Remove("w2"))
+	require.Error(t, c.Remove("w2"))
+	_, err = c.Get("w2")
+	require.Error(t, err)
+	require.NoError(t, c.Add(ctd, Info{ID: "w2"}))
+}
+
+func TestControllerResolve(t *testing.T) {
+	amd64 := ocispec.Platform{OS: "linux", Architecture: "amd64"}
+	arm64 := ocispec.Platform{OS: "linux", Architecture: "arm64"}
+	armv7 := ocispec.Platform{OS: "linux", Architecture: "arm", Variant: "v7"}
+
+	c := &Controller{}
+	_, _, err := c.Resolve(nil)
+	require.Error(t, err)
+
+	w1, w2 := &testWorker{"w1"}, &testWorker{"w2"}
+	require.NoError(t, c.Add(w1, Info{ID: "w1", Platforms: []ocispec.Platform{amd64}}))
+	require.NoError(t, c.Add(w2, Info{ID: "w2", Labels: map[string]string{"gpu": "true"}, Platforms: []ocispec.Platform{arm64, armv7}}))
+
+	w, info, err := c.Resolve(&arm64)
+	require.NoError(t, err)
+	require.Equal(t, w2, w)
+	require.Equal(t, "w2", info.ID)
+
+	w, _, err = c.Resolve(&ocispec.Platform{OS: "linux", Architecture: "arm", Variant: "7"})
+	require.NoError(t, err)
+	require.Equal(t, w2, w)
+
+	// platforms without a native worker are emulated by the first worker
+	w, _, err = c.Resolve(&ocispec.Platform{OS: "linux", Architecture: "s390x"})
+	require.NoError(t, err)
+	require.Equal(t, w1, w)
+
+	w, _, err = c.Resolve(&amd64, "labels.gpu==true")
+	require.NoError(t, err)
+	require.Equal(t, w2, w)
+
+	w, _, err = c.Resolve(nil, "platforms~=linux/arm64")
+	require.NoError(t, err)
+	require.Equal(t, w2, w)
+
+	_, _, err = c.Resolve(&arm64, "labels.gpu==false")
+	require.Error(t, err)
+
+	// workers emulating the platform are preferred
+	w3 := &testWorker{"w3"}
+	require.NoError(t, c.Add(w3, Info{ID: "w3", Platforms: []ocispec.Platform{amd64}, EmulatedPlatforms: []ocispec.Platform{{OS: "linux", Architecture: "s390x"}}}))
+	w, _, err = c.Resolve(&ocispec.Platform{OS: "linux", Architecture: "s390x"})
+	require.NoError(t, err)
+	require.Equal(t, w3, w)
+
+	// workers without platforms run the platform of the daemon
+	c = &Controller{}
+	require.NoError(t, c.Add(w1, Info{ID: "w1"}))
+	_, info, err = c.Resolve(nil, "platforms=="+platforms.Format(platforms.Normalize(platforms.Default())))
+	require.NoError(t, err)
+	require.Equal(t, "w1", info.ID)
+}
+
+func TestControllerResolveInTurn(t *testing.T) {
+	amd64 := ocispec.Platform{OS: "linux", Architecture: "amd64"}
+	c := &Controller{}
+	w1, w2, w3 := &testWorker{"w1"}, &testWorker{"w2"}, &testWorker{"w3"}
+	require.NoError(t, c.Add(w1, Info{ID: "w1", Platforms: []ocispec.Platform{amd64}}))
+	require.NoError(t, c.Add(w2, Info{ID: "w2", Platforms: []ocispec.Platform{amd64}}))
+	require.NoError(t, c.Add(w3, Info{ID: "w3", Platforms: []ocispec.Platform{{OS: "linux", Architecture: "arm64"}}}))
+
+	// the native workers take turns, the other worker isn't used
+	var ids []string
+	for i := 0; i < 4; i++ {
+		_, info, err := c.Resolve(&amd64)
+		require.NoError(t, err)
+		ids = append(ids, info.ID)
+	}
+	require.Equal(t, []string{"w1", "w2", "w1", "w2"}, ids)
+}
+
+func TestControllerClose(t *testing.T) {
+	c := &Controller{}
+	var closed []int
+	c.OnClose(func() error {
+		closed = append(closed, 1)
+		return nil
+	})
+	c.OnClose(func() error {
+		closed = append(closed, 2)
+		return errors.New("failed")
+	})
+	require.Error(t, c.Close())
+	require.Equal(t, []int{2, 1}, closed)
+
+	// the functions are only called once
+	require.NoError(t, c.Close())
+	require.Equal(t, []int{2, 1}, closed)
+}
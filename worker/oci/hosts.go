@@ -0,0 +1,68 @@
+package oci
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/moby/buildkit/worker"
+)
+
+// generateHosts returns the /etc/hosts file of a process. The hostname is
+// mapped to the loopback address.
+func generateHosts(hostname string, extraHosts []worker.HostIP) []byte {
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "127.0.0.1\tlocalhost %s\n", hostname)
+	fmt.Fprintf(buf, "::1\tlocalhost ip6-localhost ip6-loopback\n")
+	for _, h := range extraHosts {
+		fmt.Fprintf(buf, "%s\t%s\n", h.IP, h.Host)
+	}
+	return buf.Bytes()
+}
+
+// generateResolvConf returns the /etc/resolv.conf file of a process. The
+// nameservers, search domains and options of the resolv.conf of the host are
+// kept unless they are set in dns, the other lines are dropped.
+func generateResolvConf(host []byte, dns *worker.DNSConfig) []byte {
+	var nameservers, search, options []string
+	s := bufio.NewScanner(bytes.NewReader(host))
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "nameserver":
+			nameservers = append(nameservers, fields[1])
+		case "search", "domain":
+			// the last search or domain line is used by the resolver
+			search = fields[1:]
+		case "options":
+			options = append(options, fields[1:]...)
+		}
+	}
+	if dns != nil {
+		if len(dns.Nameservers) > 0 {
+			nameservers = dns.Nameservers
+		}
+		if len(dns.SearchDomains) > 0 {
+			search = dns.SearchDomains
+		}
+		if len(dns.Options) > 0 {
+			options = dns.Options
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	for _, ns := range nameservers {
+		fmt.Fprintf(buf, "nameserver %s\n", ns)
+	}
+	if len(search) > 0 {
+		fmt.Fprintf(buf, "search %s\n", strings.Join(search, " "))
+	}
+	if len(options) > 0 {
+		fmt.Fprintf(buf, "options %s\n", strings.Join(options, " "))
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,26 @@
+package oci
+
+import (
+	"testing"
+
+	"github.com/moby/buildkit/worker"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateHosts(t *testing.T) {
+	dt := generateHosts("builder", []worker.HostIP{{Host: "mirror", IP: "10.0.0.2"}})
+	require.Equal(t, "127.0.0.1\tlocalhost builder\n::1\tlocalhost ip6-localhost ip6-loopback\n10.0.0.2\tmirror\n", string(dt))
+}
+
+func TestGenerateResolvConf(t *testing.T) {
+	host := []byte("# generated\nnameserver 10.0.0.1\nnameserver 10.0.0.2\ndomain corp\nsearch example.com corp\noptions ndots:2\nsortlist 10.0.0.0\n")
+
+	dt := generateResolvConf(host, nil)
+	require.Equal(t, "nameserver 10.0.0.1\nnameserver 10.0.0.2\nsearch example.com corp\noptions ndots:2\n", string(dt))
+
+	dt = generateResolvConf(host, &worker.DNSConfig{Nameservers: []string{"8.8.8.8"}, Options: []string{"timeout:1"}})
+	require.Equal(t, "nameserver 8.8.8.8\nsearch example.com corp\noptions timeout:1\n", string(dt))
+
+	dt = generateResolvConf(nil, &worker.DNSConfig{SearchDomains: []string{"build"}})
+	require.Equal(t, "search build\n", string(dt))
+}
@@ -0,0 +1,50 @@
+// +build !windows
+
+package oci
+
+import (
+	"strings"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// ToRootless changes the spec so it can be run by the daemon in a user
+// namespace without privileges on the host. The cgroups can't be written so
+// the resource limits other than the ulimits are dropped, and sysfs is bound
+// from the daemon as it can't be mounted in the network namespace of the
+// daemon. The devices can't be created so they are bound from the host.
+func ToRootless(s *specs.Spec) {
+	if s.Linux != nil {
+		s.Linux.Resources = nil
+		s.Linux.CgroupsPath = ""
+		for _, d := range s.Linux.Devices {
+			s.Mounts = append(s.Mounts, specs.Mount{
+				Destination: d.Path,
+				Type:        "bind",
+				Source:      d.Path,
+				Options:     []string{"rbind", "nosuid", "noexec"},
+			})
+		}
+		s.Linux.Devices = nil
+	}
+
+	for i, m := range s.Mounts {
+		if m.Type == "sysfs" {
+			m = specs.Mount{
+				Destination: m.Destination,
+				Type:        "bind",
+				Source:      "/sys",
+				Options:     []string{"rbind", "nosuid", "noexec", "nodev", "ro"},
+			}
+		}
+		// the groups of the host may not be mapped in the user namespace
+		var options []string
+		for _, o := range m.Options {
+			if !strings.HasPrefix(o, "gid=") {
+				options = append(options, o)
+			}
+		}
+		m.Options = options
+		s.Mounts[i] = m
+	}
+}
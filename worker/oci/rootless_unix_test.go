@@ -0,0 +1,39 @@
+// +build !windows
+
+package oci
+
+import (
+	"testing"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToRootless(t *testing.T) {
+	limit := int64(100)
+	s := &specs.Spec{
+		Mounts: []specs.Mount{
+			{Destination: "/proc", Type: "proc", Source: "proc"},
+			{Destination: "/dev/pts", Type: "devpts", Source: "devpts", Options: []string{"nosuid", "newinstance", "gid=5"}},
+			{Destination: "/sys", Type: "sysfs", Source: "sysfs", Options: []string{"nosuid", "ro"}},
+		},
+		Linux: &specs.Linux{
+			Resources:   &specs.LinuxResources{Pids: &specs.LinuxPids{Limit: limit}},
+			CgroupsPath: "/buildkit",
+			Devices:     []specs.LinuxDevice{{Path: "/dev/fuse", Type: "c", Major: 10, Minor: 229}},
+		},
+	}
+	ToRootless(s)
+
+	require.Nil(t, s.Linux.Resources)
+	require.Equal(t, "", s.Linux.CgroupsPath)
+	require.Nil(t, s.Linux.Devices)
+	require.Equal(t, 4, len(s.Mounts))
+	require.Equal(t, specs.Mount{Destination: "/dev/fuse", Type: "bind", Source: "/dev/fuse", Options: []string{"rbind", "nosuid", "noexec"}}, s.Mounts[3])
+	require.Equal(t, specs.Mount{Destination: "/proc", Type: "proc", Source: "proc"}, s.Mounts[0])
+	require.Equal(t, []string{"nosuid", "newinstance"}, s.Mounts[1].Options)
+	require.Equal(t, "bind", s.Mounts[2].Type)
+	require.Equal(t, "/sys", s.Mounts[2].Source)
+	require.Contains(t, s.Mounts[2].Options, "rbind")
+	require.Contains(t, s.Mounts[2].Options, "ro")
+}
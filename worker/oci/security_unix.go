@@ -0,0 +1,151 @@
+// +build !windows
+
+package oci
+
+import (
+	"encoding/json"
+	"os"
+	"syscall"
+
+	"github.com/moby/buildkit/worker"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+)
+
+// allCaps are the capabilities of an insecure process
+var allCaps = []string{
+	"CAP_AUDIT_CONTROL",
+	"CAP_AUDIT_READ",
+	"CAP_AUDIT_WRITE",
+	"CAP_BLOCK_SUSPEND",
+	"CAP_CHOWN",
+	"CAP_DAC_OVERRIDE",
+	"CAP_DAC_READ_SEARCH",
+	"CAP_FOWNER",
+	"CAP_FSETID",
+	"CAP_IPC_LOCK",
+	"CAP_IPC_OWNER",
+	"CAP_KILL",
+	"CAP_LEASE",
+	"CAP_LINUX_IMMUTABLE",
+	"CAP_MAC_ADMIN",
+	"CAP_MAC_OVERRIDE",
+	"CAP_MKNOD",
+	"CAP_NET_ADMIN",
+	"CAP_NET_BIND_SERVICE",
+	"CAP_NET_BROADCAST",
+	"CAP_NET_RAW",
+	"CAP_SETFCAP",
+	"CAP_SETGID",
+	"CAP_SETPCAP",
+	"CAP_SETUID",
+	"CAP_SYSLOG",
+	"CAP_SYS_ADMIN",
+	"CAP_SYS_BOOT",
+	"CAP_SYS_CHROOT",
+	"CAP_SYS_MODULE",
+	"CAP_SYS_NICE",
+	"CAP_SYS_PACCT",
+	"CAP_SYS_PTRACE",
+	"CAP_SYS_RAWIO",
+	"CAP_SYS_RESOURCE",
+	"CAP_SYS_TIME",
+	"CAP_SYS_TTY_CONFIG",
+	"CAP_WAKE_ALARM",
+}
+
+// setInsecure gives the process all the capabilities, access to all the
+// devices through the devices cgroup and a writable sysfs
+func setInsecure(s *specs.Spec) {
+	s.Process.Capabilities = &specs.LinuxCapabilities{
+		Bounding:    allCaps,
+		Permitted:   allCaps,
+		Inheritable: allCaps,
+		Effective:   allCaps,
+	}
+	s.Process.NoNewPrivileges = false
+	s.Process.ApparmorProfile = ""
+	s.Linux.Seccomp = nil
+	s.Linux.MaskedPaths = nil
+	s.Linux.ReadonlyPaths = nil
+	s.Linux.Resources.Devices = []specs.LinuxDeviceCgroup{{Allow: true, Access: "rwm"}}
+	for i, m := range s.Mounts {
+		if m.Type != "sysfs" {
+			continue
+		}
+		var options []string
+		for _, o := range m.Options {
+			if o != "ro" {
+				options = append(options, o)
+			}
+		}
+		s.Mounts[i].Options = append(options, "rw")
+	}
+}
+
+// setSecurityProfile confines the process with the profiles that are set
+func setSecurityProfile(s *specs.Spec, p worker.SecurityProfile) error {
+	switch p.Seccomp {
+	case "", worker.Unconfined:
+		s.Linux.Seccomp = nil
+	default:
+		var sc specs.LinuxSeccomp
+		if err := json.Unmarshal([]byte(p.Seccomp), &sc); err != nil {
+			return errors.Wrap(err, "failed to parse seccomp profile")
+		}
+		s.Linux.Seccomp = &sc
+	}
+	s.Process.ApparmorProfile = p.AppArmor
+	if p.AppArmor == worker.Unconfined {
+		s.Process.ApparmorProfile = ""
+	}
+	s.Process.SelinuxLabel = p.SELinuxLabel
+	return nil
+}
+
+// setDevices creates the host devices in the container and allows them in
+// the devices cgroup
+func setDevices(s *specs.Spec, devices []string) error {
+	for _, p := range devices {
+		var st syscall.Stat_t
+		if err := syscall.Stat(p, &st); err != nil {
+			return errors.Wrapf(err, "failed to access device %s", p)
+		}
+		var typ string
+		switch st.Mode & syscall.S_IFMT {
+		case syscall.S_IFCHR:
+			typ = "c"
+		case syscall.S_IFBLK:
+			typ = "b"
+		default:
+			return errors.Errorf("%s is not a device", p)
+		}
+		major, minor := devNumbers(uint64(st.Rdev))
+		mode := os.FileMode(st.Mode &^ syscall.S_IFMT)
+		uid, gid := st.Uid, st.Gid
+		s.Linux.Devices = append(s.Linux.Devices, specs.LinuxDevice{
+			Path:     p,
+			Type:     typ,
+			Major:    major,
+			Minor:    minor,
+			FileMode: &mode,
+			UID:      &uid,
+			GID:      &gid,
+		})
+		s.Linux.Resources.Devices = append(s.Linux.Resources.Devices, specs.LinuxDeviceCgroup{
+			Allow:  true,
+			Type:   typ,
+			Major:  &major,
+			Minor:  &minor,
+			Access: "rwm",
+		})
+	}
+	return nil
+}
+
+// devNumbers splits a device number with the encoding of Linux
+func devNumbers(dev uint64) (int64, int64) {
+	major := (dev>>8)&0xfff | (dev>>32)&0xfffff000
+	minor := dev&0xff | (dev>>12)&0xffffff00
+	return int64(major), int64(minor)
+}
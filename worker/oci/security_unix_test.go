@@ -0,0 +1,77 @@
+// +build !windows
+
+package oci
+
+import (
+	"testing"
+
+	"github.com/moby/buildkit/worker"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetInsecure(t *testing.T) {
+	s := &specs.Spec{
+		Process: &specs.Process{NoNewPrivileges: true},
+		Mounts: []specs.Mount{
+			{Destination: "/sys", Type: "sysfs", Source: "sysfs", Options: []string{"nosuid", "ro"}},
+		},
+		Linux: &specs.Linux{
+			Resources: &specs.LinuxResources{Devices: []specs.LinuxDeviceCgroup{{Allow: false, Access: "rwm"}}},
+		},
+	}
+	setInsecure(s)
+
+	require.False(t, s.Process.NoNewPrivileges)
+	require.Contains(t, s.Process.Capabilities.Effective, "CAP_SYS_ADMIN")
+	require.Equal(t, []specs.LinuxDeviceCgroup{{Allow: true, Access: "rwm"}}, s.Linux.Resources.Devices)
+	require.Equal(t, []string{"nosuid", "rw"}, s.Mounts[0].Options)
+}
+
+func TestSetSecurityProfile(t *testing.T) {
+	s := &specs.Spec{Process: &specs.Process{}, Linux: &specs.Linux{}}
+	require.NoError(t, setSecurityProfile(s, worker.SecurityProfile{
+		Seccomp:      `{"defaultAction":"SCMP_ACT_ERRNO","syscalls":[{"names":["read"],"action":"SCMP_ACT_ALLOW"}]}`,
+		AppArmor:     "buildkit-default",
+		SELinuxLabel: "system_u:system_r:container_t:s0",
+	}))
+	require.NotNil(t, s.Linux.Seccomp)
+	require.Equal(t, specs.ActErrno, s.Linux.Seccomp.DefaultAction)
+	require.Equal(t, []string{"read"}, s.Linux.Seccomp.Syscalls[0].Names)
+	require.Equal(t, "buildkit-default", s.Process.ApparmorProfile)
+	require.Equal(t, "system_u:system_r:container_t:s0", s.Process.SelinuxLabel)
+
+	require.NoError(t, setSecurityProfile(s, worker.SecurityProfile{Seccomp: worker.Unconfined, AppArmor: worker.Unconfined}))
+	require.Nil(t, s.Linux.Seccomp)
+	require.Equal(t, "", s.Process.ApparmorProfile)
+
+	require.Error(t, setSecurityProfile(s, worker.SecurityProfile{Seccomp: "{"}))
+}
+
+func TestSetDevices(t *testing.T) {
+	s := &specs.Spec{Linux: &specs.Linux{Resources: &specs.LinuxResources{}}}
+	require.NoError(t, setDevices(s, []string{"/dev/null"}))
+
+	require.Equal(t, 1, len(s.Linux.Devices))
+	d := s.Linux.Devices[0]
+	require.Equal(t, "/dev/null", d.Path)
+	require.Equal(t, "c", d.Type)
+	require.Equal(t, int64(1), d.Major)
+	require.Equal(t, int64(3), d.Minor)
+	require.Equal(t, 1, len(s.Linux.Resources.Devices))
+	require.True(t, s.Linux.Resources.Devices[0].Allow)
+
+	require.Error(t, setDevices(s, []string{"/"}))
+	require.Error(t, setDevices(s, []string{"/dev/missing"}))
+}
+
+func TestDevNumbers(t *testing.T) {
+	major, minor := devNumbers(0x103)
+	require.Equal(t, int64(1), major)
+	require.Equal(t, int64(3), minor)
+
+	// numbers that don't fit the old encoding
+	major, minor = devNumbers(0x00001000000fff01)
+	require.Equal(t, int64(0x1fff), major)
+	require.Equal(t, int64(0x1), minor)
+}
@@ -4,7 +4,11 @@ package oci
 
 import (
 	"context"
+	"io/ioutil"
+	"os"
 	"path"
+	"path/filepath"
+	"strings"
 	"sync"
 
 	"github.com/containerd/containerd"
@@ -18,32 +22,60 @@ import (
 
 // Ideally we don't have to import whole containerd just for the default spec
 
-func GenerateSpec(ctx context.Context, meta worker.Meta, mounts []worker.Mount) (*specs.Spec, func(), error) {
-	s, err := containerd.GenerateSpec(ctx, nil, nil,
-		containerd.WithHostNamespace(specs.NetworkNamespace),
-		containerd.WithHostResolvconf,
-		containerd.WithHostHostsFile,
-	)
+func GenerateSpec(ctx context.Context, meta worker.Meta, mounts []worker.Mount, id string) (*specs.Spec, func(), error) {
+	var opts []containerd.SpecOpts
+	// there is no network provider, the execs that aren't isolated from the
+	// network use the network of the host
+	if meta.NetMode != worker.NetModeNone {
+		opts = append(opts, containerd.WithHostNamespace(specs.NetworkNamespace))
+	}
+	s, err := containerd.GenerateSpec(ctx, nil, nil, opts...)
 	if err != nil {
 		return nil, nil, err
 	}
 	s.Process.Args = meta.Args
 	s.Process.Env = meta.Env
 	s.Process.Cwd = meta.Cwd
-	// TODO: User
+	s.Hostname = meta.Hostname
+	if s.Hostname == "" {
+		s.Hostname = worker.DefaultHostname
+	}
+	if err := setResources(s, meta.Resources); err != nil {
+		return nil, nil, err
+	}
+	if meta.CgroupParent != "" {
+		s.Linux.CgroupsPath = path.Join(meta.CgroupParent, id)
+	}
+	if err := setSecurityProfile(s, meta.SecurityProfile); err != nil {
+		return nil, nil, err
+	}
+	if meta.Insecure {
+		setInsecure(s)
+	}
+	if err := setDevices(s, meta.Devices); err != nil {
+		return nil, nil, err
+	}
 
+	etcDir, err := writeEtcFiles(s, meta)
+	if err != nil {
+		return nil, nil, err
+	}
 	sm := &submounts{}
+	cleanup := func() {
+		sm.cleanup()
+		os.RemoveAll(etcDir)
+	}
 
 	for _, m := range mounts {
 		mounts, err := m.Src.Mount(ctx, m.Readonly)
 		if err != nil {
-			sm.cleanup()
+			cleanup()
 			return nil, nil, errors.Wrapf(err, "failed to mount %s", m.Dest)
 		}
 		for _, mount := range mounts {
 			mount, err = sm.subMount(mount, m.Selector)
 			if err != nil {
-				sm.cleanup()
+				cleanup()
 				return nil, nil, err
 			}
 			s.Mounts = append(s.Mounts, specs.Mount{
@@ -55,7 +87,40 @@ func GenerateSpec(ctx context.Context, meta worker.Meta, mounts []worker.Mount)
 		}
 	}
 
-	return s, sm.cleanup, nil
+	return s, cleanup, nil
+}
+
+// writeEtcFiles creates the /etc/hosts and /etc/resolv.conf files of the
+// process in a temporary directory that is returned, and binds them
+func writeEtcFiles(s *specs.Spec, meta worker.Meta) (string, error) {
+	hostResolv, err := ioutil.ReadFile("/etc/resolv.conf")
+	if err != nil && !os.IsNotExist(err) {
+		return "", errors.Wrap(err, "failed to read resolv.conf of the host")
+	}
+	dir, err := ioutil.TempDir("", "buildkit-etc")
+	if err != nil {
+		return "", err
+	}
+	for _, f := range []struct {
+		name string
+		dt   []byte
+	}{
+		{"hosts", generateHosts(s.Hostname, meta.ExtraHosts)},
+		{"resolv.conf", generateResolvConf(hostResolv, meta.DNS)},
+	} {
+		p := filepath.Join(dir, f.name)
+		if err := ioutil.WriteFile(p, f.dt, 0644); err != nil {
+			os.RemoveAll(dir)
+			return "", err
+		}
+		s.Mounts = append(s.Mounts, specs.Mount{
+			Destination: "/etc/" + f.name,
+			Type:        "bind",
+			Source:      p,
+			Options:     []string{"rbind", "ro"},
+		})
+	}
+	return dir, nil
 }
 
 type mountRef struct {
@@ -122,6 +187,61 @@ func (s *submounts) cleanup() {
 	wg.Wait()
 }
 
+func setResources(s *specs.Spec, r worker.Resources) error {
+	if s.Linux == nil {
+		s.Linux = &specs.Linux{}
+	}
+	if s.Linux.Resources == nil {
+		s.Linux.Resources = &specs.LinuxResources{}
+	}
+	res := s.Linux.Resources
+	if r.CPUShares > 0 || r.CPUQuota > 0 || r.CPUPeriod > 0 {
+		if res.CPU == nil {
+			res.CPU = &specs.LinuxCPU{}
+		}
+		if r.CPUShares > 0 {
+			shares := uint64(r.CPUShares)
+			res.CPU.Shares = &shares
+		}
+		if r.CPUQuota > 0 {
+			quota := r.CPUQuota
+			res.CPU.Quota = &quota
+		}
+		if r.CPUPeriod > 0 {
+			period := uint64(r.CPUPeriod)
+			res.CPU.Period = &period
+		}
+	}
+	if r.Memory > 0 {
+		if res.Memory == nil {
+			res.Memory = &specs.LinuxMemory{}
+		}
+		limit := r.Memory
+		res.Memory.Limit = &limit
+	}
+	if r.PidsLimit > 0 {
+		res.Pids = &specs.LinuxPids{Limit: r.PidsLimit}
+	}
+	for _, u := range r.Ulimits {
+		if u.Soft < 0 || u.Hard < 0 || u.Soft > u.Hard {
+			return errors.Errorf("invalid ulimit %s=%d:%d", u.Name, u.Soft, u.Hard)
+		}
+		typ := "RLIMIT_" + strings.ToUpper(u.Name)
+		rl := specs.POSIXRlimit{Type: typ, Soft: uint64(u.Soft), Hard: uint64(u.Hard)}
+		replaced := false
+		for i, existing := range s.Process.Rlimits {
+			if existing.Type == typ {
+				s.Process.Rlimits[i] = rl
+				replaced = true
+			}
+		}
+		if !replaced {
+			s.Process.Rlimits = append(s.Process.Rlimits, rl)
+		}
+	}
+	return nil
+}
+
 func sub(m mount.Mount, subPath string) mount.Mount {
 	m.Source = path.Join(m.Source, subPath)
 	return m
@@ -0,0 +1,40 @@
+// +build !windows
+
+package oci
+
+import (
+	"context"
+	"testing"
+
+	"github.com/moby/buildkit/worker"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateSpecNetwork(t *testing.T) {
+	for _, tc := range []struct {
+		mode worker.NetMode
+		host bool
+	}{
+		{worker.NetModeDefault, true},
+		{worker.NetModeNone, false},
+		{worker.NetModeHost, true},
+	} {
+		s, cleanup, err := GenerateSpec(context.TODO(), worker.Meta{Args: []string{"true"}, NetMode: tc.mode}, nil, "test")
+		require.NoError(t, err)
+		cleanup()
+
+		var netns *specs.LinuxNamespace
+		for i, ns := range s.Linux.Namespaces {
+			if ns.Type == specs.NetworkNamespace {
+				netns = &s.Linux.Namespaces[i]
+			}
+		}
+		if tc.host {
+			require.Nil(t, netns, "mode %d", tc.mode)
+		} else {
+			require.NotNil(t, netns, "mode %d", tc.mode)
+			require.Equal(t, "", netns.Path, "mode %d", tc.mode)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+// +build windows
+
+package oci
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+
+	"github.com/containerd/containerd"
+	"github.com/containerd/containerd/mount"
+	"github.com/moby/buildkit/worker"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+)
+
+// GenerateSpec returns the spec of a windows container. The hosts and
+// resolv.conf files, the cgroups and the security profiles of the linux
+// containers don't apply, and the mounts have to be directories of the host.
+func GenerateSpec(ctx context.Context, meta worker.Meta, mounts []worker.Mount, id string) (*specs.Spec, func(), error) {
+	if err := checkMeta(meta); err != nil {
+		return nil, nil, err
+	}
+	s, err := containerd.GenerateSpec(ctx, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.Process.Args = meta.Args
+	s.Process.Env = meta.Env
+	if meta.Cwd != "" {
+		s.Process.Cwd = windowsPath(meta.Cwd)
+	}
+	s.Hostname = meta.Hostname
+	if s.Hostname == "" {
+		s.Hostname = worker.DefaultHostname
+	}
+	setResources(s, meta.Resources)
+
+	for _, m := range mounts {
+		mounts, err := m.Src.Mount(ctx, m.Readonly)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to mount %s", m.Dest)
+		}
+		for _, mount := range mounts {
+			if mount.Type != "bind" {
+				return nil, nil, unsupportedMount(m.Dest, mount)
+			}
+			var opts []string
+			if m.Readonly {
+				opts = append(opts, "ro")
+			}
+			s.Mounts = append(s.Mounts, specs.Mount{
+				Destination: windowsPath(m.Dest),
+				Source:      filepath.Join(mount.Source, filepath.FromSlash(m.Selector)),
+				Options:     opts,
+			})
+		}
+	}
+
+	return s, func() {}, nil
+}
+
+// unsupportedMount returns the error for the mount m at dest that the windows
+// containers can't mount. Only the root of the container can be a layer of
+// the snapshotter.
+func unsupportedMount(dest string, m mount.Mount) error {
+	switch m.Type {
+	case "tmpfs":
+		return errors.Errorf("tmpfs mount of %s is not supported on windows, the windows containers have no tmpfs", dest)
+	case "windows-layer":
+		return errors.Errorf("mount of the snapshot %s at %s is not supported on windows, only the root of an exec can be a snapshot and the other mounts have to be directories of the host", m.Source, dest)
+	default:
+		return errors.Errorf("%s mount of %s is not supported on windows, only directories of the host can be mounted", m.Type, dest)
+	}
+}
+
+// checkMeta returns an error for the settings of linux containers that the
+// process uses
+func checkMeta(meta worker.Meta) error {
+	switch {
+	case meta.Insecure:
+		return errors.New("insecure execs are not supported on windows")
+	case len(meta.Devices) > 0:
+		return errors.New("devices are not supported on windows")
+	case meta.NetMode == worker.NetModeNone:
+		return errors.New("execs without network are not supported on windows")
+	case meta.SecurityProfile != (worker.SecurityProfile{}):
+		return errors.New("security profiles are not supported on windows")
+	case len(meta.ExtraHosts) > 0:
+		return errors.New("extra hosts are not supported on windows")
+	}
+	return nil
+}
+
+// windowsPath returns the path p of an exec in the windows form. Paths
+// without a drive letter are on the system drive.
+func windowsPath(p string) string {
+	p = filepath.FromSlash(p)
+	if filepath.VolumeName(p) == "" {
+		p = `C:` + p
+	}
+	return filepath.Clean(p)
+}
+
+// setResources sets the memory limit and the CPU shares and quota of the
+// container. The pids limit and the ulimits have no windows equivalent and
+// are ignored.
+func setResources(s *specs.Spec, r worker.Resources) {
+	res := &specs.WindowsResources{}
+	if r.Memory > 0 {
+		limit := uint64(r.Memory)
+		res.Memory = &specs.WindowsMemoryResources{Limit: &limit}
+	}
+	if r.CPUShares > 0 || (r.CPUQuota > 0 && r.CPUPeriod > 0) {
+		res.CPU = &specs.WindowsCPUResources{}
+		if r.CPUShares > 0 {
+			shares := uint16(r.CPUShares)
+			if r.CPUShares > 10000 {
+				shares = 10000
+			}
+			res.CPU.Shares = &shares
+		}
+		if r.CPUQuota > 0 && r.CPUPeriod > 0 {
+			res.CPU.Maximum = cpuMaximum(r.CPUQuota, r.CPUPeriod, runtime.NumCPU())
+		}
+	}
+	if res.Memory != nil || res.CPU != nil {
+		s.Windows.Resources = res
+	}
+}
+
+// cpuMaximum converts a CPU quota of a period to the portion of the cycles
+// of all the processors in hundredths of a percent
+func cpuMaximum(quota, period int64, ncpu int) *uint16 {
+	m := quota * 10000 / (period * int64(ncpu))
+	if m < 1 {
+		m = 1
+	} else if m > 10000 {
+		m = 10000
+	}
+	max := uint16(m)
+	return &max
+}
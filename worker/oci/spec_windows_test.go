@@ -0,0 +1,31 @@
+// +build windows
+
+package oci
+
+import (
+	"testing"
+
+	"github.com/containerd/containerd/mount"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWindowsPath(t *testing.T) {
+	require.Equal(t, `C:\`, windowsPath("/"))
+	require.Equal(t, `C:\src\app`, windowsPath("/src/app/"))
+	require.Equal(t, `D:\cache`, windowsPath("D:/cache"))
+}
+
+func TestCPUMaximum(t *testing.T) {
+	require.Equal(t, uint16(2500), *cpuMaximum(100000, 100000, 4))
+	require.Equal(t, uint16(10000), *cpuMaximum(800000, 100000, 4))
+	require.Equal(t, uint16(1), *cpuMaximum(1, 100000, 4))
+}
+
+func TestUnsupportedMount(t *testing.T) {
+	err := unsupportedMount(`/tmp`, mount.Mount{Type: "tmpfs", Source: "tmpfs"})
+	require.Contains(t, err.Error(), "tmpfs mount of /tmp is not supported on windows")
+	err = unsupportedMount(`/cache`, mount.Mount{Type: "windows-layer", Source: `C:\layers\1`})
+	require.Contains(t, err.Error(), "only the root of an exec can be a snapshot")
+	err = unsupportedMount(`/data`, mount.Mount{Type: "overlay"})
+	require.Contains(t, err.Error(), "overlay mount of /data is not supported on windows")
+}
@@ -0,0 +1,63 @@
+// +build !windows
+
+package oci
+
+import (
+	"path/filepath"
+
+	"github.com/containerd/containerd/mount"
+	"github.com/docker/docker/pkg/symlink"
+	"github.com/moby/buildkit/snapshot"
+	"github.com/opencontainers/runc/libcontainer/user"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+)
+
+// GetUser resolves a user specification, like "user", "uid" or
+// "user:group", with the passwd and group files of the root filesystem
+// mounted at root. Supplementary groups of the user are also returned.
+func GetUser(root, username string) (specs.User, error) {
+	if username == "" {
+		return specs.User{}, nil
+	}
+	passwdPath, err := symlink.FollowSymlinkInScope(filepath.Join(root, "/etc/passwd"), root)
+	if err != nil {
+		return specs.User{}, errors.Wrap(err, "invalid passwd path")
+	}
+	groupPath, err := symlink.FollowSymlinkInScope(filepath.Join(root, "/etc/group"), root)
+	if err != nil {
+		return specs.User{}, errors.Wrap(err, "invalid group path")
+	}
+	u, err := user.GetExecUserPath(username, nil, passwdPath, groupPath)
+	if err != nil {
+		return specs.User{}, errors.Wrapf(err, "failed to find user %s", username)
+	}
+	s := specs.User{
+		UID: uint32(u.Uid),
+		GID: uint32(u.Gid),
+	}
+	for _, g := range u.Sgids {
+		s.AdditionalGids = append(s.AdditionalGids, uint32(g))
+	}
+	return s, nil
+}
+
+// SetUser sets the user of the process of s to the user specification
+// username of the root filesystem with the mounts rootMounts
+func SetUser(s *specs.Spec, rootMounts []mount.Mount, username string) error {
+	if username == "" {
+		return nil
+	}
+	lm := snapshot.LocalMounter(rootMounts)
+	root, err := lm.Mount()
+	if err != nil {
+		return err
+	}
+	u, err := GetUser(root, username)
+	lm.Unmount()
+	if err != nil {
+		return err
+	}
+	s.Process.User = u
+	return nil
+}
@@ -0,0 +1,15 @@
+// +build windows
+
+package oci
+
+import (
+	"github.com/containerd/containerd/mount"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// SetUser sets the user name of the process of s. Windows resolves the
+// user in the container.
+func SetUser(s *specs.Spec, rootMounts []mount.Mount, username string) error {
+	s.Process.User.Username = username
+	return nil
+}
@@ -0,0 +1,159 @@
+package remoteworker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/boltdb/bolt"
+	controlapi "github.com/moby/buildkit/api/services/control"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/cache/contenthash"
+	"github.com/moby/buildkit/cache/metadata"
+	"github.com/moby/buildkit/snapshot"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/tonistiigi/fsutil"
+	"golang.org/x/net/context"
+)
+
+const keyRemoteMount = "buildkit.remoteworker.mount.v0"
+
+// mountDirs are the directory mounts of a remote exec. They are kept in the
+// cache manager of the ServeOpt, or in dir if it has none.
+type mountDirs struct {
+	opt ServeOpt
+	dir string
+	// refs are the received or cached directories by the index of the mount
+	refs map[int]cache.ImmutableRef
+	// active are the writable mounts created from refs
+	active map[int]cache.MutableRef
+}
+
+// lookup checks if the directory of em is cached
+func (d *mountDirs) lookup(ctx context.Context, i int, em *controlapi.ExecMount) bool {
+	if d.opt.CacheManager == nil || em.Digest == "" {
+		return false
+	}
+	sis, err := d.opt.MetadataStore.Search(d.indexKey(em))
+	if err != nil || len(sis) == 0 {
+		return false
+	}
+	ref, err := d.opt.CacheManager.Get(ctx, sis[0].ID())
+	if err != nil {
+		logrus.Debugf("failed to get cached mount %s: %v", em.Dest, err)
+		return false
+	}
+	d.refs[i] = ref
+	return true
+}
+
+// receive receives the directory of em. A received directory is cached if
+// its content matches the digest sent by the client.
+func (d *mountDirs) receive(ctx context.Context, ps *packetStream, i int, em *controlapi.ExecMount) error {
+	if d.opt.CacheManager == nil {
+		dir := filepath.Join(d.dir, strconv.Itoa(i))
+		if err := os.Mkdir(dir, 0755); err != nil {
+			return err
+		}
+		return errors.Wrapf(fsutil.Receive(ctx, ps, dir, fsutil.ReceiveOpt{}), "failed to receive mount %s", em.Dest)
+	}
+
+	active, err := d.opt.CacheManager.New(ctx, nil, cache.WithDescription(fmt.Sprintf("remote exec mount %s", em.Dest)))
+	if err != nil {
+		return err
+	}
+	if err := withDir(ctx, active, false, func(dir string) error {
+		return fsutil.Receive(ctx, ps, dir, fsutil.ReceiveOpt{})
+	}); err != nil {
+		active.Release(context.TODO())
+		return errors.Wrapf(err, "failed to receive mount %s", em.Dest)
+	}
+	ref, err := active.Commit(ctx)
+	if err != nil {
+		return err
+	}
+	d.refs[i] = ref
+	if em.Digest == "" {
+		return nil
+	}
+	dgst, err := contenthash.ChecksumWithOpts(ctx, ref, "/", contenthash.ChecksumOpts{})
+	if err != nil {
+		return err
+	}
+	if dgst != em.Digest {
+		logrus.Debugf("digest %s of mount %s doesn't match %s of the client", dgst, em.Dest, em.Digest)
+		return nil
+	}
+	si, _ := d.opt.MetadataStore.Get(ref.ID())
+	v, err := metadata.NewValue(d.indexKey(em))
+	if err != nil {
+		return err
+	}
+	v.Index = d.indexKey(em)
+	return si.Update(func(b *bolt.Bucket) error {
+		return si.SetValue(b, keyRemoteMount, v)
+	})
+}
+
+// mountable returns the mountable of the directory of em. A writable mount
+// is a new mutable ref on top of the received directory.
+func (d *mountDirs) mountable(ctx context.Context, i int, em *controlapi.ExecMount) (cache.Mountable, error) {
+	if d.opt.CacheManager == nil {
+		return &bindMount{path: filepath.Join(d.dir, strconv.Itoa(i))}, nil
+	}
+	ref := d.refs[i]
+	if em.Readonly {
+		return ref, nil
+	}
+	active, err := d.opt.CacheManager.New(ctx, ref, cache.WithDescription(fmt.Sprintf("remote exec mount %s", em.Dest)))
+	if err != nil {
+		return nil, err
+	}
+	d.active[i] = active
+	return active, nil
+}
+
+// send sends the directory of the writable mount em back to the client
+func (d *mountDirs) send(ctx context.Context, ps *packetStream, i int, em *controlapi.ExecMount) error {
+	var err error
+	if d.opt.CacheManager == nil {
+		err = fsutil.Send(ctx, ps, filepath.Join(d.dir, strconv.Itoa(i)), nil, nil)
+	} else {
+		err = withDir(ctx, d.active[i], true, func(dir string) error {
+			return fsutil.Send(ctx, ps, dir, nil, nil)
+		})
+	}
+	return errors.Wrapf(err, "failed to send mount %s", em.Dest)
+}
+
+// release releases the refs of the mounts. The received directories stay in
+// the cache manager until they are pruned.
+func (d *mountDirs) release() {
+	for _, active := range d.active {
+		active.Release(context.TODO())
+	}
+	for _, ref := range d.refs {
+		ref.Release(context.TODO())
+	}
+}
+
+func (d *mountDirs) indexKey(em *controlapi.ExecMount) string {
+	return "remote-mount::" + d.opt.Scope + "::" + em.Digest.String()
+}
+
+// withDir calls fn with the local directory of m
+func withDir(ctx context.Context, m cache.Mountable, readonly bool, fn func(string) error) error {
+	mounts, err := m.Mount(ctx, readonly)
+	if err != nil {
+		return err
+	}
+	lm := snapshot.LocalMounter(mounts)
+	dir, err := lm.Mount()
+	if err != nil {
+		return err
+	}
+	defer lm.Unmount()
+	return fn(dir)
+}
@@ -0,0 +1,42 @@
+// +build !windows
+
+package remoteworker
+
+import (
+	"os"
+	"syscall"
+
+	"github.com/containerd/containerd/mount"
+	"github.com/pkg/errors"
+)
+
+// fileOwner returns the owner of the file described by fi
+func fileOwner(fi os.FileInfo) (uint32, uint32) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0
+	}
+	return st.Uid, st.Gid
+}
+
+// mountFilesDir mounts a tmpfs on the directory of the file mounts so their
+// content, eg. secrets, is never stored to the disk of the daemon
+func mountFilesDir(dir string) error {
+	m := mount.Mount{
+		Type:    "tmpfs",
+		Source:  "tmpfs",
+		Options: []string{"nodev", "nosuid", "noexec", "mode=0711", "size=1m"},
+	}
+	if err := m.Mount(dir); err != nil {
+		return errors.Wrap(err, "failed to mount tmpfs for file mounts")
+	}
+	return nil
+}
+
+func unmountFilesDir(dir string) error {
+	return mount.Unmount(dir, 0)
+}
+
+func chownFile(p string, uid, gid uint32) error {
+	return os.Chown(p, int(uid), int(gid))
+}
@@ -0,0 +1,23 @@
+// +build windows
+
+package remoteworker
+
+import "os"
+
+// fileOwner returns the owner of the file described by fi. Windows files
+// have no uid and gid.
+func fileOwner(fi os.FileInfo) (uint32, uint32) {
+	return 0, 0
+}
+
+func mountFilesDir(dir string) error {
+	return nil
+}
+
+func unmountFilesDir(dir string) error {
+	return nil
+}
+
+func chownFile(p string, uid, gid uint32) error {
+	return nil
+}
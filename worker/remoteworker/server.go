@@ -0,0 +1,230 @@
+package remoteworker
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/containerd/containerd/mount"
+	controlapi "github.com/moby/buildkit/api/services/control"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/cache/metadata"
+	"github.com/moby/buildkit/util/entitlements"
+	"github.com/moby/buildkit/worker"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+)
+
+// ServeOpt are the limits of the daemon for the processes of the remote
+// workers of other daemons
+type ServeOpt struct {
+	// Entitlements are the entitlements the processes can require
+	Entitlements []entitlements.Entitlement
+	// CgroupParent replaces the cgroup requested by the client
+	CgroupParent string
+	// Resources returns the resources a process runs with from the ones
+	// requested by the client
+	Resources func(worker.Resources) worker.Resources
+	// CacheManager keeps the directories received from the clients by their
+	// digests so they are only transferred once. The directories are
+	// received in temporary directories if it is nil.
+	CacheManager cache.Manager
+	// MetadataStore is the metadata store of CacheManager
+	MetadataStore *metadata.Store
+	// Scope separates the cached directories of the clients, e.g. it is the
+	// identity of the client
+	Scope string
+}
+
+// Serve runs the process of a remote worker with the worker w. The
+// entitlements required by the process have to be allowed.
+func Serve(ctx context.Context, stream controlapi.Control_ExecServer, w worker.Worker, opt ServeOpt) error {
+	msg, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	m, ok := msg.Message.(*controlapi.ExecMessage_Request)
+	if !ok || len(m.Request.Mounts) == 0 {
+		return errors.Errorf("invalid remote exec request")
+	}
+	req := m.Request
+	var meta worker.Meta
+	if err := json.Unmarshal(req.Meta, &meta); err != nil {
+		return errors.Wrap(err, "failed to parse remote exec")
+	}
+	if err := validateRequest(meta, req.Mounts); err != nil {
+		return err
+	}
+	if _, err := entitlements.WhiteList(opt.Entitlements, metaEntitlements(meta)); err != nil {
+		return err
+	}
+	meta.CgroupParent = opt.CgroupParent
+	if opt.Resources != nil {
+		meta.Resources = opt.Resources(meta.Resources)
+	}
+	meta.Checkpoint = nil
+
+	dir, err := ioutil.TempDir("", "buildkit-remote")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temp dir for remote exec")
+	}
+	defer os.RemoveAll(dir)
+	filesDir := filepath.Join(dir, "files")
+	if hasFileMounts(req.Mounts) {
+		if err := os.Mkdir(filesDir, 0711); err != nil {
+			return err
+		}
+		if err := mountFilesDir(filesDir); err != nil {
+			return err
+		}
+		defer func() {
+			if err := unmountFilesDir(filesDir); err != nil {
+				logrus.Errorf("failed to unmount %s: %v", filesDir, err)
+			}
+		}()
+	}
+
+	dirs := &mountDirs{opt: opt, dir: dir, refs: map[int]cache.ImmutableRef{}, active: map[int]cache.MutableRef{}}
+	defer dirs.release()
+	transfer := &controlapi.ExecTransfer{}
+	for i, em := range req.Mounts {
+		if em.Type == mountTypeDir && !dirs.lookup(ctx, i, em) {
+			transfer.Mounts = append(transfer.Mounts, int32(i))
+		}
+	}
+	if err := stream.Send(&controlapi.ExecMessage{Message: &controlapi.ExecMessage_Transfer{Transfer: transfer}}); err != nil {
+		return err
+	}
+	ps := &packetStream{ctx: ctx, stream: stream}
+	for _, i := range transfer.Mounts {
+		if err := dirs.receive(ctx, ps, int(i), req.Mounts[i]); err != nil {
+			return err
+		}
+	}
+
+	mounts := make([]worker.Mount, len(req.Mounts))
+	for i, em := range req.Mounts {
+		var src cache.Mountable
+		var err error
+		if em.Type == mountTypeDir {
+			src, err = dirs.mountable(ctx, i, em)
+		} else {
+			src, err = serverMount(em, filepath.Join(filesDir, strconv.Itoa(i)))
+		}
+		if err != nil {
+			return err
+		}
+		mounts[i] = worker.Mount{Src: src, Dest: em.Dest, Readonly: em.Readonly}
+	}
+
+	ls := &lockedStream{execStream: stream}
+	stdout := &outputWriter{stream: ls}
+	stderr := &outputWriter{stream: ls, stderr: true}
+	res := &controlapi.ExecResult{}
+	if err := w.Exec(ctx, meta, mounts[0].Src, mounts[1:], nil, stdout, stderr); err != nil {
+		if ee, ok := errors.Cause(err).(*worker.ExitError); ok {
+			res.ExitCode = ee.ExitCode
+		} else {
+			res.Error = err.Error()
+		}
+	}
+	if err := ls.Send(&controlapi.ExecMessage{Message: &controlapi.ExecMessage_Result{Result: res}}); err != nil {
+		return err
+	}
+	if res.ExitCode != 0 || res.Error != "" {
+		return nil
+	}
+
+	for i, em := range req.Mounts {
+		if em.Type != mountTypeDir || em.Readonly {
+			continue
+		}
+		if err := dirs.send(ctx, ps, i, em); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// serverMount returns the mountable of the file or tmpfs mount em. The
+// content of a file mount is written to file.
+func serverMount(em *controlapi.ExecMount, file string) (cache.Mountable, error) {
+	switch em.Type {
+	case mountTypeFile:
+		mode := os.FileMode(em.Mode & 0777)
+		if err := ioutil.WriteFile(file, em.Data, mode); err != nil {
+			return nil, errors.Wrapf(err, "failed to write mount %s", em.Dest)
+		}
+		if err := chownFile(file, em.Uid, em.Gid); err != nil {
+			return nil, errors.Wrapf(err, "failed to change owner of mount %s", em.Dest)
+		}
+		if err := os.Chmod(file, mode); err != nil {
+			return nil, err
+		}
+		return &bindMount{path: file}, nil
+	case mountTypeTmpfs:
+		return &tmpfsMount{options: em.Options}, nil
+	}
+	return nil, errors.Errorf("invalid type %s of mount %s", em.Type, em.Dest)
+}
+
+func hasFileMounts(mounts []*controlapi.ExecMount) bool {
+	for _, m := range mounts {
+		if m.Type == mountTypeFile {
+			return true
+		}
+	}
+	return false
+}
+
+// metaEntitlements returns the entitlements required by the process
+func metaEntitlements(meta worker.Meta) []entitlements.Entitlement {
+	var l []entitlements.Entitlement
+	if meta.NetMode == worker.NetModeHost {
+		l = append(l, entitlements.EntitlementNetworkHost)
+	}
+	if meta.Insecure {
+		l = append(l, entitlements.EntitlementSecurityInsecure)
+	}
+	if len(meta.Devices) > 0 {
+		l = append(l, entitlements.EntitlementDevice)
+	}
+	if meta.SecurityProfile != (worker.SecurityProfile{}) {
+		l = append(l, entitlements.EntitlementSecurityProfile)
+	}
+	return l
+}
+
+// bindMount is a bind mount of a path received from the client
+type bindMount struct {
+	path string
+}
+
+func (bm *bindMount) Mount(ctx context.Context, readonly bool) ([]mount.Mount, error) {
+	opts := []string{"rbind"}
+	if readonly {
+		opts = append(opts, "ro")
+	} else {
+		opts = append(opts, "rw")
+	}
+	return []mount.Mount{{
+		Type:    "bind",
+		Source:  bm.path,
+		Options: opts,
+	}}, nil
+}
+
+type tmpfsMount struct {
+	options []string
+}
+
+func (t *tmpfsMount) Mount(ctx context.Context, readonly bool) ([]mount.Mount, error) {
+	return []mount.Mount{{
+		Type:    "tmpfs",
+		Source:  "tmpfs",
+		Options: t.options,
+	}}, nil
+}
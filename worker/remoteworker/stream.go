@@ -0,0 +1,98 @@
+package remoteworker
+
+import (
+	"sync"
+
+	controlapi "github.com/moby/buildkit/api/services/control"
+	"github.com/pkg/errors"
+	"github.com/tonistiigi/fsutil"
+	"golang.org/x/net/context"
+)
+
+const (
+	mountTypeDir   = "dir"
+	mountTypeFile  = "file"
+	mountTypeTmpfs = "tmpfs"
+)
+
+// execStream is the part of the client and server sides of the Exec stream
+// the transfers use
+type execStream interface {
+	Send(*controlapi.ExecMessage) error
+	Recv() (*controlapi.ExecMessage, error)
+}
+
+// lockedStream serializes the messages sent by the output writers and the
+// transfers
+type lockedStream struct {
+	execStream
+	mu sync.Mutex
+}
+
+func (s *lockedStream) Send(m *controlapi.ExecMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.execStream.Send(m)
+}
+
+// packetStream is the fsutil stream of the transfer of a directory in the
+// packet messages of an Exec stream
+type packetStream struct {
+	ctx    context.Context
+	stream execStream
+}
+
+func (s *packetStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *packetStream) SendMsg(m interface{}) error {
+	p, ok := m.(*fsutil.Packet)
+	if !ok {
+		return errors.Errorf("invalid transfer message %T", m)
+	}
+	dt, err := p.Marshal()
+	if err != nil {
+		return err
+	}
+	return s.stream.Send(&controlapi.ExecMessage{Message: &controlapi.ExecMessage_Packet{Packet: dt}})
+}
+
+func (s *packetStream) RecvMsg(m interface{}) error {
+	p, ok := m.(*fsutil.Packet)
+	if !ok {
+		return errors.Errorf("invalid transfer message %T", m)
+	}
+	msg, err := s.stream.Recv()
+	if err != nil {
+		return err
+	}
+	dt, ok := msg.Message.(*controlapi.ExecMessage_Packet)
+	if !ok {
+		return errors.Errorf("unexpected message %T during transfer", msg.Message)
+	}
+	return p.Unmarshal(dt.Packet)
+}
+
+// outputWriter sends the stdout or stderr of the process
+type outputWriter struct {
+	stream execStream
+	stderr bool
+}
+
+func (w *outputWriter) Write(dt []byte) (int, error) {
+	// the writer may reuse the buffer after the call
+	dt = append([]byte{}, dt...)
+	msg := &controlapi.ExecMessage{Message: &controlapi.ExecMessage_Stdout{Stdout: dt}}
+	if w.stderr {
+		msg.Message = &controlapi.ExecMessage_Stderr{Stderr: dt}
+	}
+	if err := w.stream.Send(msg); err != nil {
+		return 0, err
+	}
+	return len(dt), nil
+}
+
+func (w *outputWriter) Close() error {
+	return nil
+}
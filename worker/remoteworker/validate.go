@@ -0,0 +1,83 @@
+package remoteworker
+
+import (
+	"net"
+	"path"
+	"strings"
+
+	controlapi "github.com/moby/buildkit/api/services/control"
+	"github.com/moby/buildkit/worker"
+	"github.com/pkg/errors"
+)
+
+// validateRequest checks the process and the mounts of a remote exec. The
+// client is another daemon that has validated the definitions of its builds,
+// but the server doesn't trust it to.
+func validateRequest(meta worker.Meta, mounts []*controlapi.ExecMount) error {
+	if len(meta.Args) == 0 || meta.Args[0] == "" {
+		return errors.Errorf("remote exec without an executable")
+	}
+	for i, a := range meta.Args {
+		if strings.ContainsRune(a, 0) {
+			return errors.Errorf("remote exec argument %d contains a NUL byte", i)
+		}
+	}
+	for _, env := range meta.Env {
+		if strings.HasPrefix(env, "=") || strings.ContainsRune(env, 0) {
+			return errors.Errorf("invalid environment variable %q", env)
+		}
+	}
+	if meta.Cwd != "" && !isAbsPath(meta.Cwd) {
+		return errors.Errorf("working directory %q is not absolute", meta.Cwd)
+	}
+	for _, d := range meta.Devices {
+		if path.Clean(d) != d || !strings.HasPrefix(d, "/dev/") {
+			return errors.Errorf("invalid device path %q", d)
+		}
+	}
+	if meta.Hostname != "" && !validHostname(meta.Hostname) {
+		return errors.Errorf("invalid hostname %q", meta.Hostname)
+	}
+	for _, h := range meta.ExtraHosts {
+		if !validHostname(h.Host) || net.ParseIP(h.IP) == nil {
+			return errors.Errorf("invalid extra host %s:%s", h.Host, h.IP)
+		}
+	}
+	if mounts[0].Dest != "/" {
+		return errors.Errorf("remote exec without root mount")
+	}
+	for _, m := range mounts[1:] {
+		if !isAbsPath(m.Dest) {
+			return errors.Errorf("mount destination %q is not absolute", m.Dest)
+		}
+	}
+	return nil
+}
+
+// isAbsPath checks that p is an absolute path of a linux or a windows
+// process
+func isAbsPath(p string) bool {
+	if len(p) >= 2 && p[1] == ':' {
+		p = strings.Replace(p[2:], "\\", "/", -1)
+	}
+	return path.IsAbs(p)
+}
+
+// validHostname checks that h is a host name of dot separated labels of
+// letters, digits and hyphens
+func validHostname(h string) bool {
+	if len(h) > 253 {
+		return false
+	}
+	for _, l := range strings.Split(h, ".") {
+		if l == "" || len(l) > 63 || l[0] == '-' || l[len(l)-1] == '-' {
+			return false
+		}
+		for _, c := range l {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,227 @@
+package remoteworker
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	controlapi "github.com/moby/buildkit/api/services/control"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/cache/contenthash"
+	"github.com/moby/buildkit/snapshot"
+	"github.com/moby/buildkit/util/tracing"
+	"github.com/moby/buildkit/worker"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/tonistiigi/fsutil"
+	"golang.org/x/net/context"
+)
+
+type remoteWorker struct {
+	client controlapi.ControlClient
+}
+
+// New returns a worker running the processes with the default worker of the
+// daemon of the client. The root and the mounts the daemon doesn't have
+// cached are transferred to it before the process is started and the changes
+// of the writable ones are transferred back after it succeeded, so the cache
+// of the builds stays with the daemon of the worker. The stdin, the
+// checkpoints and the writable file mounts of the process are not supported.
+func New(client controlapi.ControlClient) worker.Worker {
+	return &remoteWorker{client: client}
+}
+
+func (w *remoteWorker) Exec(ctx context.Context, meta worker.Meta, root cache.Mountable, mounts []worker.Mount, stdin io.ReadCloser, stdout, stderr io.WriteCloser) (retErr error) {
+	span, ctx := tracing.StartSpan(ctx, "exec", "remote")
+	defer func() {
+		span.Finish(retErr)
+	}()
+	span.Printf("args %v", meta.Args)
+
+	all := append([]worker.Mount{{Src: root, Dest: "/", Readonly: meta.ReadonlyRootFS, Base: meta.RootBase}}, mounts...)
+	req := &controlapi.ExecRequest{}
+	// dirs are the local directories of the directory mounts
+	dirs := make([]string, len(all))
+	var unmounts []func() error
+	defer func() {
+		for _, u := range unmounts {
+			u()
+		}
+	}()
+	for i, m := range all {
+		em, dir, unmount, err := localMount(ctx, m)
+		if err != nil {
+			return err
+		}
+		if unmount != nil {
+			unmounts = append(unmounts, unmount)
+		}
+		req.Mounts = append(req.Mounts, em)
+		dirs[i] = dir
+	}
+
+	meta.Checkpoint = nil
+	meta.RootBase = nil
+	dt, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	req.Meta = dt
+
+	// canceling the context closes the stream if the exec fails
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := w.client.Exec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to start remote exec")
+	}
+	if err := stream.Send(&controlapi.ExecMessage{Message: &controlapi.ExecMessage_Request{Request: req}}); err != nil {
+		return errors.Wrap(err, "failed to send remote exec")
+	}
+	transfer, err := receiveTransfer(stream)
+	if err != nil {
+		return err
+	}
+	ps := &packetStream{ctx: ctx, stream: stream}
+	for _, i := range transfer.Mounts {
+		if i < 0 || int(i) >= len(req.Mounts) || req.Mounts[i].Type != mountTypeDir {
+			return errors.Errorf("invalid transfer of mount %d", i)
+		}
+		if err := fsutil.Send(ctx, ps, dirs[i], nil, nil); err != nil {
+			return errors.Wrapf(err, "failed to send mount %s", req.Mounts[i].Dest)
+		}
+	}
+	span.Printf("transferred %d of %d mounts", len(transfer.Mounts), len(req.Mounts))
+	span.Printf("running")
+
+	res, err := receiveOutput(stream, stdout, stderr)
+	if err != nil {
+		return err
+	}
+	if res.Error != "" {
+		return errors.Errorf("remote worker: %s", res.Error)
+	}
+	if res.ExitCode != 0 {
+		return errors.WithStack(&worker.ExitError{ExitCode: res.ExitCode})
+	}
+
+	for i, em := range req.Mounts {
+		if em.Type != mountTypeDir || em.Readonly {
+			continue
+		}
+		if err := fsutil.Receive(ctx, ps, dirs[i], fsutil.ReceiveOpt{}); err != nil {
+			return errors.Wrapf(err, "failed to receive mount %s", em.Dest)
+		}
+	}
+	return stream.CloseSend()
+}
+
+// receiveTransfer receives the mounts the daemon requests
+func receiveTransfer(stream controlapi.Control_ExecClient) (*controlapi.ExecTransfer, error) {
+	msg, err := stream.Recv()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to start remote exec")
+	}
+	m, ok := msg.Message.(*controlapi.ExecMessage_Transfer)
+	if !ok {
+		return nil, errors.Errorf("unexpected remote exec message %T", msg.Message)
+	}
+	return m.Transfer, nil
+}
+
+// receiveOutput writes the output of the process until its result is
+// received
+func receiveOutput(stream controlapi.Control_ExecClient, stdout, stderr io.Writer) (*controlapi.ExecResult, error) {
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to receive remote exec output")
+		}
+		switch m := msg.Message.(type) {
+		case *controlapi.ExecMessage_Stdout:
+			if stdout != nil {
+				stdout.Write(m.Stdout)
+			}
+		case *controlapi.ExecMessage_Stderr:
+			if stderr != nil {
+				stderr.Write(m.Stderr)
+			}
+		case *controlapi.ExecMessage_Result:
+			return m.Result, nil
+		default:
+			return nil, errors.Errorf("unexpected remote exec message %T", msg.Message)
+		}
+	}
+}
+
+// localMount returns the description of the mount m that is sent to the
+// daemon, the local directory of a directory mount and the function
+// releasing it
+func localMount(ctx context.Context, m worker.Mount) (*controlapi.ExecMount, string, func() error, error) {
+	mounts, err := m.Src.Mount(ctx, m.Readonly)
+	if err != nil {
+		return nil, "", nil, errors.Wrapf(err, "failed to mount %s", m.Dest)
+	}
+	em := &controlapi.ExecMount{Dest: m.Dest, Readonly: m.Readonly}
+	if len(mounts) == 1 {
+		switch mounts[0].Type {
+		case "tmpfs":
+			em.Type = mountTypeTmpfs
+			em.Options = mounts[0].Options
+			return em, "", nil, nil
+		case "bind":
+			fi, err := os.Stat(mounts[0].Source)
+			if err != nil {
+				return nil, "", nil, errors.Wrapf(err, "failed to stat mount %s", m.Dest)
+			}
+			if fi.IsDir() {
+				break
+			}
+			if !fi.Mode().IsRegular() {
+				return nil, "", nil, errors.Errorf("mount %s of %s is not supported by remote workers", m.Dest, fi.Mode())
+			}
+			if !m.Readonly {
+				return nil, "", nil, errors.Errorf("writable file mount %s is not supported by remote workers", m.Dest)
+			}
+			dt, err := ioutil.ReadFile(mounts[0].Source)
+			if err != nil {
+				return nil, "", nil, err
+			}
+			em.Type = mountTypeFile
+			em.Data = dt
+			em.Mode = uint32(fi.Mode().Perm())
+			em.Uid, em.Gid = fileOwner(fi)
+			return em, "", nil, nil
+		}
+	}
+	lm := snapshot.LocalMounter(mounts)
+	mp, err := lm.Mount()
+	if err != nil {
+		return nil, "", nil, errors.Wrapf(err, "failed to mount %s", m.Dest)
+	}
+	em.Type = mountTypeDir
+	if m.Selector == "" {
+		em.Digest = mountDigest(ctx, m)
+	}
+	return em, filepath.Join(mp, m.Selector), lm.Unmount, nil
+}
+
+// mountDigest returns the content digest of the directory of the mount m
+// before the process runs, or an empty digest if it isn't known
+func mountDigest(ctx context.Context, m worker.Mount) digest.Digest {
+	ref, ok := m.Src.(cache.ImmutableRef)
+	if !ok {
+		if ref = m.Base; ref == nil {
+			return ""
+		}
+	}
+	dgst, err := contenthash.ChecksumWithOpts(ctx, ref, "/", contenthash.ChecksumOpts{})
+	if err != nil {
+		logrus.Debugf("failed to compute the digest of mount %s: %v", m.Dest, err)
+		return ""
+	}
+	return dgst
+}
@@ -0,0 +1,338 @@
+package remoteworker
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containerd/containerd/mount"
+	"github.com/containerd/containerd/snapshot/naive"
+	controlapi "github.com/moby/buildkit/api/services/control"
+	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/cache/metadata"
+	"github.com/moby/buildkit/snapshot"
+	"github.com/moby/buildkit/worker"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+func TestRemoteExec(t *testing.T) {
+	c, stop := newTestClient(t, &testWorker{})
+	defer stop()
+
+	root, err := ioutil.TempDir("", "remoteworker")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	err = ioutil.WriteFile(filepath.Join(root, "in"), []byte("input"), 0644)
+	require.NoError(t, err)
+	src, err := ioutil.TempDir("", "remoteworker")
+	require.NoError(t, err)
+	defer os.RemoveAll(src)
+	err = ioutil.WriteFile(filepath.Join(src, "in"), []byte("source"), 0644)
+	require.NoError(t, err)
+
+	stdout := &bufferCloser{}
+	mounts := []worker.Mount{{Src: &testMount{path: src}, Dest: "/src", Readonly: true}}
+	err = New(c).Exec(context.TODO(), worker.Meta{Args: []string{"copy"}}, &testMount{path: root}, mounts, nil, stdout, nil)
+	require.NoError(t, err)
+
+	dt, err := ioutil.ReadFile(filepath.Join(root, "out"))
+	require.NoError(t, err)
+	require.Equal(t, "input source", string(dt))
+	require.Equal(t, "done", stdout.String())
+	_, err = os.Stat(filepath.Join(src, "out"))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestRemoteExecExitCode(t *testing.T) {
+	c, stop := newTestClient(t, &testWorker{})
+	defer stop()
+
+	root, err := ioutil.TempDir("", "remoteworker")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	err = New(c).Exec(context.TODO(), worker.Meta{Args: []string{"fail"}}, &testMount{path: root}, nil, nil, nil, nil)
+	require.Error(t, err)
+	ee, ok := errors.Cause(err).(*worker.ExitError)
+	require.True(t, ok)
+	require.Equal(t, uint32(3), ee.ExitCode)
+	_, err = os.Stat(filepath.Join(root, "out"))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestRemoteExecLimits(t *testing.T) {
+	w := &testWorker{}
+	c, stop := newTestClient(t, w)
+	defer stop()
+
+	root, err := ioutil.TempDir("", "remoteworker")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	meta := worker.Meta{
+		Args:         []string{"record"},
+		CgroupParent: "/client/../..",
+		Resources:    worker.Resources{Memory: 1 << 30},
+	}
+	err = New(c).Exec(context.TODO(), meta, &testMount{path: root}, nil, nil, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, "/server", w.meta.CgroupParent)
+	require.Equal(t, int64(1024), w.meta.Resources.Memory)
+
+	for _, args := range [][]string{{""}, {"record", "a\x00b"}} {
+		err = New(c).Exec(context.TODO(), worker.Meta{Args: args}, &testMount{path: root}, nil, nil, nil, nil)
+		require.Error(t, err)
+	}
+	err = New(c).Exec(context.TODO(), worker.Meta{Args: []string{"record"}, Devices: []string{"/etc/shadow"}}, &testMount{path: root}, nil, nil, nil, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid device path")
+}
+
+func TestRemoteExecEntitlements(t *testing.T) {
+	c, stop := newTestClient(t, &testWorker{})
+	defer stop()
+
+	root, err := ioutil.TempDir("", "remoteworker")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	err = New(c).Exec(context.TODO(), worker.Meta{Args: []string{"copy"}, Insecure: true}, &testMount{path: root}, nil, nil, nil, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "security.insecure")
+}
+
+func TestRemoteExecCache(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "remoteworker")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+	cm := newTestCache(t, filepath.Join(tmpdir, "client"))
+	srv := &testServer{w: &testWorker{}, cache: newTestCache(t, filepath.Join(tmpdir, "server"))}
+	c, stop := newTestServerClient(t, srv)
+	defer stop()
+	tc := &transferClient{ControlClient: c}
+
+	ctx := context.TODO()
+	base := newTestRef(t, cm, "input")
+	defer base.Release(ctx)
+	src := newTestRef(t, cm, "source")
+	defer src.Release(ctx)
+
+	for i := 0; i < 2; i++ {
+		root, err := cm.New(ctx, base)
+		require.NoError(t, err)
+		mounts := []worker.Mount{{Src: src, Dest: "/src", Readonly: true}}
+		err = New(tc).Exec(ctx, worker.Meta{Args: []string{"copy"}, RootBase: base}, root, mounts, nil, &bufferCloser{}, nil)
+		require.NoError(t, err)
+
+		dir, release, err := mountDir(ctx, root)
+		require.NoError(t, err)
+		dt, err := ioutil.ReadFile(filepath.Join(dir, "out"))
+		require.NoError(t, err)
+		require.Equal(t, "input source", string(dt))
+		require.NoError(t, release())
+		require.NoError(t, root.Release(ctx))
+	}
+	require.Equal(t, [][]int32{{0, 1}, nil}, tc.transfers)
+}
+
+func TestRemoteExecWritableFile(t *testing.T) {
+	c, stop := newTestClient(t, &testWorker{})
+	defer stop()
+
+	root, err := ioutil.TempDir("", "remoteworker")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	f := filepath.Join(root, "file")
+	err = ioutil.WriteFile(f, []byte("data"), 0644)
+	require.NoError(t, err)
+
+	mounts := []worker.Mount{{Src: &testMount{path: f}, Dest: "/file"}}
+	err = New(c).Exec(context.TODO(), worker.Meta{Args: []string{"record"}}, &testMount{path: root}, mounts, nil, nil, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "writable file mount")
+}
+
+type testCache struct {
+	cache.Manager
+	md *metadata.Store
+}
+
+func newTestCache(t *testing.T, dir string) testCache {
+	snapshotter, err := naive.NewSnapshotter(filepath.Join(dir, "snapshots"))
+	require.NoError(t, err)
+	md, err := metadata.NewStore(filepath.Join(dir, "metadata.db"))
+	require.NoError(t, err)
+	cm, err := cache.NewManager(cache.ManagerOpt{
+		Snapshotter:   snapshotter,
+		MetadataStore: md,
+	})
+	require.NoError(t, err)
+	return testCache{Manager: cm, md: md}
+}
+
+// newTestRef returns a ref with the in file with the content dt
+func newTestRef(t *testing.T, cm cache.Manager, dt string) cache.ImmutableRef {
+	ctx := context.TODO()
+	active, err := cm.New(ctx, nil)
+	require.NoError(t, err)
+	dir, release, err := mountDir(ctx, active)
+	require.NoError(t, err)
+	err = ioutil.WriteFile(filepath.Join(dir, "in"), []byte(dt), 0644)
+	require.NoError(t, err)
+	require.NoError(t, release())
+	ref, err := active.Commit(ctx)
+	require.NoError(t, err)
+	return ref
+}
+
+// transferClient records the mounts the server requests for each exec
+type transferClient struct {
+	controlapi.ControlClient
+	transfers [][]int32
+}
+
+func (c *transferClient) Exec(ctx context.Context, opts ...grpc.CallOption) (controlapi.Control_ExecClient, error) {
+	stream, err := c.ControlClient.Exec(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &transferStream{Control_ExecClient: stream, c: c}, nil
+}
+
+type transferStream struct {
+	controlapi.Control_ExecClient
+	c *transferClient
+}
+
+func (s *transferStream) Recv() (*controlapi.ExecMessage, error) {
+	msg, err := s.Control_ExecClient.Recv()
+	if err == nil {
+		if m, ok := msg.Message.(*controlapi.ExecMessage_Transfer); ok {
+			s.c.transfers = append(s.c.transfers, m.Transfer.Mounts)
+		}
+	}
+	return msg, err
+}
+
+// newTestClient returns a client of a control server running the remote
+// execs with w and the function stopping it
+func newTestClient(t *testing.T, w worker.Worker) (controlapi.ControlClient, func()) {
+	return newTestServerClient(t, &testServer{w: w})
+}
+
+func newTestServerClient(t *testing.T, srv *testServer) (controlapi.ControlClient, func()) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := grpc.NewServer()
+	controlapi.RegisterControlServer(s, srv)
+	go s.Serve(l)
+
+	conn, err := grpc.Dial(l.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	return controlapi.NewControlClient(conn), func() {
+		conn.Close()
+		s.Stop()
+	}
+}
+
+type testServer struct {
+	controlapi.ControlServer
+	w     worker.Worker
+	cache testCache
+}
+
+func (s *testServer) Exec(stream controlapi.Control_ExecServer) error {
+	return Serve(stream.Context(), stream, s.w, ServeOpt{
+		CgroupParent: "/server",
+		Resources: func(r worker.Resources) worker.Resources {
+			if r.Memory == 0 || r.Memory > 1024 {
+				r.Memory = 1024
+			}
+			return r
+		},
+		CacheManager:  s.cache.Manager,
+		MetadataStore: s.cache.md,
+	})
+}
+
+// testWorker writes the content of the in files of the root and of the
+// /src mount to the out file of the root for the copy command, fails with
+// exit code 3 for the fail command and records the meta of the record
+// command
+type testWorker struct {
+	meta worker.Meta
+}
+
+func (w *testWorker) Exec(ctx context.Context, meta worker.Meta, root cache.Mountable, mounts []worker.Mount, stdin io.ReadCloser, stdout, stderr io.WriteCloser) error {
+	switch meta.Args[0] {
+	case "fail":
+		return &worker.ExitError{ExitCode: 3}
+	case "record":
+		w.meta = meta
+		return nil
+	}
+	rootDir, release, err := mountDir(ctx, root)
+	if err != nil {
+		return err
+	}
+	defer release()
+	srcDir, releaseSrc, err := mountDir(ctx, mounts[0].Src)
+	if err != nil {
+		return err
+	}
+	defer releaseSrc()
+
+	in, err := ioutil.ReadFile(filepath.Join(rootDir, "in"))
+	if err != nil {
+		return err
+	}
+	src, err := ioutil.ReadFile(filepath.Join(srcDir, "in"))
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(rootDir, "out"), []byte(string(in)+" "+string(src)), 0644); err != nil {
+		return err
+	}
+	_, err = stdout.Write([]byte("done"))
+	return err
+}
+
+func mountDir(ctx context.Context, m cache.Mountable) (string, func() error, error) {
+	mounts, err := m.Mount(ctx, false)
+	if err != nil {
+		return "", nil, err
+	}
+	lm := snapshot.LocalMounter(mounts)
+	dir, err := lm.Mount()
+	if err != nil {
+		return "", nil, err
+	}
+	return dir, lm.Unmount, nil
+}
+
+type testMount struct {
+	path string
+}
+
+func (m *testMount) Mount(ctx context.Context, readonly bool) ([]mount.Mount, error) {
+	return []mount.Mount{{
+		Type:    "bind",
+		Source:  m.path,
+		Options: []string{"rbind", "rw"},
+	}}, nil
+}
+
+type bufferCloser struct {
+	bytes.Buffer
+}
+
+func (b *bufferCloser) Close() error {
+	return nil
+}
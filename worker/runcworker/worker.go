@@ -14,6 +14,7 @@ import (
 	runc "github.com/containerd/go-runc"
 	"github.com/docker/docker/pkg/symlink"
 	"github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/util/tracing"
 	"github.com/moby/buildkit/worker"
 	"github.com/moby/buildkit/worker/oci"
 	"github.com/pkg/errors"
@@ -22,15 +23,36 @@ import (
 )
 
 type runcworker struct {
-	runc *runc.Runc
-	root string
+	runc     *runc.Runc
+	root     string
+	rootless bool
 }
 
-func New(root string) (worker.Worker, error) {
+// Opt changes the options of the worker
+type Opt func(*runcworker)
+
+// WithRootless runs the processes without privileges on the host. The daemon
+// needs to run as root in a user namespace with its own mount namespace, e.g.
+// with rootlesskit. The processes use the network of the daemon.
+func WithRootless() Opt {
+	return func(w *runcworker) {
+		w.rootless = true
+	}
+}
+
+func New(root string, opts ...Opt) (worker.Worker, error) {
 	if err := exec.Command("runc", "--version").Run(); err != nil {
 		return nil, errors.Wrap(err, "failed to find runc binary")
 	}
 
+	w := &runcworker{}
+	for _, o := range opts {
+		o(w)
+	}
+	if w.rootless && os.Geteuid() != 0 {
+		return nil, errors.Errorf("rootless mode requires running as root in a user namespace, e.g. with rootlesskit")
+	}
+
 	if err := os.MkdirAll(root, 0700); err != nil {
 		return nil, errors.Wrapf(err, "failed to create %s", root)
 	}
@@ -47,22 +69,36 @@ func New(root string) (worker.Worker, error) {
 		PdeathSignal: syscall.SIGKILL,
 		Setpgid:      true,
 	}
-
-	w := &runcworker{
-		runc: runtime,
-		root: root,
+	if w.rootless {
+		// the default state directory of runc is not writable in the user
+		// namespace
+		runtime.Root = filepath.Join(root, "state")
 	}
+
+	w.runc = runtime
+	w.root = root
 	return w, nil
 }
 
-func (w *runcworker) Exec(ctx context.Context, meta worker.Meta, root cache.Mountable, mounts []worker.Mount, stdout, stderr io.WriteCloser) error {
+func (w *runcworker) Identity() string {
+	host, _ := os.Hostname()
+	return "runc@" + host
+}
+
+func (w *runcworker) Exec(ctx context.Context, meta worker.Meta, root cache.Mountable, mounts []worker.Mount, stdin io.ReadCloser, stdout, stderr io.WriteCloser) (retErr error) {
+	id := generateID()
+
+	span, ctx := tracing.StartSpan(ctx, "exec", "runc "+id)
+	defer func() {
+		span.Finish(retErr)
+	}()
+	span.Printf("args %v", meta.Args)
 
-	rootMount, err := root.Mount(ctx, false)
+	rootMount, err := root.Mount(ctx, meta.ReadonlyRootFS)
 	if err != nil {
 		return err
 	}
 
-	id := generateID()
 	bundle := filepath.Join(w.root, id)
 
 	if err := os.Mkdir(bundle, 0700); err != nil {
@@ -78,18 +114,28 @@ func (w *runcworker) Exec(ctx context.Context, meta worker.Meta, root cache.Moun
 		return err
 	}
 	defer f.Close()
-	spec, cleanup, err := oci.GenerateSpec(ctx, meta, mounts)
+	spec, cleanup, err := oci.GenerateSpec(ctx, meta, mounts, id)
 	if err != nil {
 		return err
 	}
 	defer cleanup()
+	if w.rootless {
+		oci.ToRootless(spec)
+	}
 
 	if err := mount.MountAll(rootMount, rootFSPath); err != nil {
 		return err
 	}
 	defer mount.Unmount(rootFSPath, 0)
 	spec.Root.Path = rootFSPath
-	if _, ok := root.(cache.ImmutableRef); ok { // TODO: pass in with mount, not ref type
+	if meta.User != "" {
+		u, err := oci.GetUser(rootFSPath, meta.User)
+		if err != nil {
+			return err
+		}
+		spec.Process.User = u
+	}
+	if _, ok := root.(cache.ImmutableRef); ok || meta.ReadonlyRootFS { // TODO: pass in with mount, not ref type
 		spec.Root.Readonly = true
 	}
 
@@ -106,11 +152,22 @@ func (w *runcworker) Exec(ctx context.Context, meta worker.Meta, root cache.Moun
 	}
 
 	logrus.Debugf("> running %s %v", id, meta.Args)
+	span.Printf("running")
+
+	if meta.Checkpoint != nil {
+		stop := worker.StartCheckpoints(ctx, meta.Checkpoint, func(ctx context.Context) error {
+			return w.runc.Pause(ctx, id)
+		}, func(ctx context.Context) error {
+			return w.runc.Resume(ctx, id)
+		})
+		defer stop()
+	}
 
 	status, err := w.runc.Run(ctx, id, bundle, &runc.CreateOpts{
-		IO: &forwardIO{stdout: stdout, stderr: stderr},
+		IO: &forwardIO{stdin: stdin, stdout: stdout, stderr: stderr},
 	})
 	logrus.Debugf("< completed %s %v %v", id, status, err)
+	span.Printf("exited with status %d", status)
 	if status != 0 {
 		select {
 		case <-ctx.Done():
@@ -118,13 +175,14 @@ func (w *runcworker) Exec(ctx context.Context, meta worker.Meta, root cache.Moun
 			return errors.Wrapf(ctx.Err(), "exit code %d", status)
 		default:
 		}
-		return errors.Errorf("exit code %d", status)
+		return errors.WithStack(&worker.ExitError{ExitCode: uint32(status)})
 	}
 
 	return err
 }
 
 type forwardIO struct {
+	stdin          io.ReadCloser
 	stdout, stderr io.WriteCloser
 }
 
@@ -133,6 +191,9 @@ func (s *forwardIO) Close() error {
 }
 
 func (s *forwardIO) Set(cmd *exec.Cmd) {
+	if s.stdin != nil {
+		cmd.Stdin = s.stdin
+	}
 	cmd.Stdout = s.stdout
 	cmd.Stderr = s.stderr
 }
@@ -1,6 +1,7 @@
 package worker
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/moby/buildkit/cache"
@@ -13,7 +14,101 @@ type Meta struct {
 	User string
 	Cwd  string
 	Tty  bool
-	// DisableNetworking bool
+	// NetMode is the network namespace the process runs in
+	NetMode   NetMode
+	Resources Resources
+	// ReadonlyRootFS mounts the rootfs read-only
+	ReadonlyRootFS bool
+	// Checkpoint is called periodically while the process is paused. Workers
+	// that can't pause processes don't make checkpoints.
+	Checkpoint *Checkpoint
+	// RootBase is the ref a writable rootfs was created from and has the
+	// content of, if it is known. The remote workers transfer it by its
+	// digest.
+	RootBase cache.ImmutableRef
+	// Insecure runs the process with all the capabilities and access to all
+	// the devices of the host
+	Insecure bool
+	// Devices are the paths of the host devices the process can access
+	Devices []string
+	// SecurityProfile confines the process unless it is insecure
+	SecurityProfile SecurityProfile
+	// CgroupParent is the cgroup the cgroup of the process is created under.
+	// The worker uses its default cgroup if it is empty.
+	CgroupParent string
+	// Hostname is the hostname of the process, DefaultHostname if it is
+	// empty
+	Hostname string
+	// ExtraHosts are added to the /etc/hosts file of the process
+	ExtraHosts []HostIP
+	// DNS replaces the settings of the resolv.conf of the host in the
+	// /etc/resolv.conf file of the process
+	DNS *DNSConfig
+}
+
+// DefaultHostname is the hostname of the processes that haven't set one
+const DefaultHostname = "buildkitsandbox"
+
+// HostIP maps a host name to an IP address
+type HostIP struct {
+	Host string
+	IP   string
+}
+
+// DNSConfig is the configuration of the resolver of the processes. Empty
+// fields keep the settings of the host.
+type DNSConfig struct {
+	Nameservers   []string
+	SearchDomains []string
+	Options       []string
+}
+
+// Unconfined disables a profile of SecurityProfile
+const Unconfined = "unconfined"
+
+// SecurityProfile are the profiles that confine a process. The workers have
+// no default profiles, an empty field doesn't confine the process like
+// Unconfined. The solver fills the fields that an exec doesn't set with the
+// profiles of the daemon.
+type SecurityProfile struct {
+	// Seccomp is the JSON of a seccomp configuration of the OCI runtime spec
+	Seccomp string
+	// AppArmor is the name of an AppArmor profile loaded on the host
+	AppArmor string
+	// SELinuxLabel is the SELinux label of the process
+	SELinuxLabel string
+}
+
+// NetMode selects the network namespace of a process
+type NetMode int
+
+const (
+	// NetModeDefault uses the network of the worker. The OCI workers have no
+	// network provider and use the network namespace of the host.
+	NetModeDefault NetMode = iota
+	// NetModeHost uses the network namespace of the host
+	NetModeHost
+	// NetModeNone uses a new network namespace with only a loopback
+	// interface
+	NetModeNone
+)
+
+// Resources defines the resource limits of the process. Zero values are not
+// limited.
+type Resources struct {
+	CPUShares int64
+	CPUQuota  int64
+	CPUPeriod int64
+	Memory    int64
+	PidsLimit int64
+	Ulimits   []Ulimit
+}
+
+// Ulimit is a POSIX rlimit, eg. with name "nofile"
+type Ulimit struct {
+	Name string
+	Soft int64
+	Hard int64
 }
 
 type Mount struct {
@@ -21,9 +116,29 @@ type Mount struct {
 	Selector string
 	Dest     string
 	Readonly bool
+	// Base is the ref a writable Src was created from and has the content
+	// of, if it is known
+	Base cache.ImmutableRef
+}
+
+// ExitError is returned from Exec when the process exits with a non-zero
+// exit code
+type ExitError struct {
+	ExitCode uint32
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit code %d", e.ExitCode)
 }
 
 type Worker interface {
-	// TODO: add stdout/err
-	Exec(ctx context.Context, meta Meta, rootfs cache.Mountable, mounts []Mount, stdout, stderr io.WriteCloser) error
+	// Exec runs a process with the rootfs and mounts. Stdin may be nil.
+	Exec(ctx context.Context, meta Meta, rootfs cache.Mountable, mounts []Mount, stdin io.ReadCloser, stdout, stderr io.WriteCloser) error
+}
+
+// Identifier is implemented by workers that can be identified in the
+// provenance of build results
+type Identifier interface {
+	// Identity returns the type of the worker and the host it is running on
+	Identity() string
 }